@@ -6,11 +6,13 @@ import (
 	"net/http/httptest"
 	"net/http/httputil"
 	"os/exec"
+	"strings"
 
 	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -20,9 +22,17 @@ var _ = Describe("apply-changes command", func() {
 		installationsStatusCallCount int
 		installationsLogsCallCount   int
 		logLines                     string
+		unterminatedLogLine          string
+		finalStatus                  string
 	)
 
 	BeforeEach(func() {
+		installationsStatusCallCount = 0
+		installationsLogsCallCount = 0
+		logLines = ""
+		unterminatedLogLine = ""
+		finalStatus = "succeeded"
+
 		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 
@@ -61,7 +71,7 @@ var _ = Describe("apply-changes command", func() {
 				}
 			case "/api/v0/installations/42":
 				if installationsStatusCallCount == 3 {
-					_, err := w.Write([]byte(`{ "status": "succeeded" }`))
+					_, err := w.Write([]byte(fmt.Sprintf(`{ "status": %q }`, finalStatus)))
 					Expect(err).ToNot(HaveOccurred())
 					return
 				}
@@ -74,7 +84,12 @@ var _ = Describe("apply-changes command", func() {
 					logLines += fmt.Sprintf("something logged for call #%d\n", installationsLogsCallCount)
 				}
 
-				_, err := w.Write([]byte(fmt.Sprintf(`{ "logs": %q }`, logLines)))
+				logs := logLines
+				if installationsLogsCallCount == 3 {
+					logs += unterminatedLogLine
+				}
+
+				_, err := w.Write([]byte(fmt.Sprintf(`{ "logs": %q }`, logs)))
 				Expect(err).ToNot(HaveOccurred())
 				installationsLogsCallCount++
 			case "/api/v0/staged/products":
@@ -119,6 +134,50 @@ var _ = Describe("apply-changes command", func() {
 		Expect(session.Out).To(gbytes.Say("something logged for call #2"))
 	})
 
+	It("exits with code 2 when the timeout is reached", func() {
+		command := exec.Command(pathToMain,
+			"--target", server.URL,
+			"--username", "some-username",
+			"--password", "some-password",
+			"--skip-ssl-validation",
+			"apply-changes",
+			"--timeout", "1ns")
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session, "5s").Should(gexec.Exit(2))
+
+		Expect(installationsStatusCallCount).To(Equal(1))
+
+		Expect(session.Out).To(gbytes.Say(`waited 1ns for the installation to finish \(Installation ID: 42\)`))
+		Expect(session.Err).To(gbytes.Say("timed out waiting for the installation to finish, it is still running on the Ops Manager"))
+	})
+
+	DescribeTable("writes the last line of the logs as a json record when it exits", func(status string, exitCode int) {
+		unterminatedLogLine = "something logged without a newline"
+		finalStatus = status
+
+		command := exec.Command(pathToMain,
+			"--target", server.URL,
+			"--username", "some-username",
+			"--password", "some-password",
+			"--skip-ssl-validation",
+			"--log-format", "json",
+			"apply-changes")
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session, "5s").Should(gexec.Exit(exitCode))
+
+		lines := strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")
+		Expect(lines[len(lines)-1]).To(ContainSubstring(`"message":"something logged without a newline"`))
+	},
+		Entry("when the installation succeeds", "succeeded", 0),
+		Entry("when the installation fails", "failed", 1),
+	)
+
 	It("successfully re-attaches to an existing deployment", func() {
 		command := exec.Command(pathToMain,
 			"--target", server.URL,
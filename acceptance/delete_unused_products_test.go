@@ -15,10 +15,13 @@ import (
 
 var _ = Describe("delete-unused-products command", func() {
 	var (
-		server *httptest.Server
+		server  *httptest.Server
+		deleted bool
 	)
 
 	BeforeEach(func() {
+		deleted = false
+
 		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			var responseString string
 			w.Header().Set("Content-Type", "application/json")
@@ -31,8 +34,17 @@ var _ = Describe("delete-unused-products command", func() {
 				"expires_in": 3600
 			}`
 			case "/api/v0/available_products":
-				if req.Method == "DELETE" {
+				switch {
+				case req.Method == "DELETE":
+					deleted = true
 					responseString = "{}"
+				case deleted:
+					responseString = `[{"name": "cf", "product_version": "1.10.0-build.177"}]`
+				default:
+					responseString = `[
+						{"name": "cf", "product_version": "1.9.0-build.1"},
+						{"name": "cf", "product_version": "1.10.0-build.177"}
+					]`
 				}
 			default:
 				out, err := httputil.DumpRequest(req, true)
@@ -63,6 +75,7 @@ var _ = Describe("delete-unused-products command", func() {
 
 		Eventually(session, 5).Should(gexec.Exit(0))
 		Eventually(session.Out, 5).Should(gbytes.Say("trashing unused products"))
+		Eventually(session.Out, 5).Should(gbytes.Say("deleted cf 1.9.0-build.1"))
 		Eventually(session.Out, 5).Should(gbytes.Say("done"))
 	})
 })
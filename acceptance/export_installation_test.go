@@ -1,6 +1,8 @@
 package acceptance
 
 import (
+	"archive/zip"
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -20,12 +22,22 @@ import (
 var _ = Describe("export-installation command", func() {
 	var (
 		outputFileName string
+		installation   []byte
 		server         *httptest.Server
 	)
 
 	BeforeEach(func() {
 		var err error
 
+		buffer := &bytes.Buffer{}
+		archive := zip.NewWriter(buffer)
+		file, err := archive.Create("installation.yml")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = file.Write([]byte("some-installation"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(archive.Close()).To(Succeed())
+		installation = buffer.Bytes()
+
 		tempFile, err := ioutil.TempFile("", "")
 		Expect(err).NotTo(HaveOccurred())
 		outputFileName = tempFile.Name()
@@ -43,7 +55,7 @@ var _ = Describe("export-installation command", func() {
 				Expect(err).ToNot(HaveOccurred())
 			case "/api/v0/installation_asset_collection":
 				time.Sleep(1010 * time.Millisecond)
-				_, err := w.Write([]byte("some-installation"))
+				_, err := w.Write(installation)
 				Expect(err).ToNot(HaveOccurred())
 			default:
 				out, err := httputil.DumpRequest(req, true)
@@ -77,11 +89,12 @@ var _ = Describe("export-installation command", func() {
 		Expect(session.Err).To(gbytes.Say("exporting installation"))
 		Expect(session.Err).To(gbytes.Say("waiting for response"))
 		Expect(session.Err).To(gbytes.Say(`100(\.\d+)?`))
+		Expect(session.Err).To(gbytes.Say("verifying the exported installation"))
 		Expect(session.Err).To(gbytes.Say("finished exporting installation"))
 
 		content, err := ioutil.ReadFile(outputFileName)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(content).To(Equal([]byte("some-installation")))
+		Expect(content).To(Equal(installation))
 	})
 
 	Context("when an error occurs", func() {
@@ -82,6 +82,58 @@ connect-timeout: 10
 			})
 		})
 
+		Context("when given an env file with unrecognized keys", func() {
+			It("warns about them and ignores them", func() {
+				server := testServer(true)
+
+				var err error
+
+				configFile, err = ioutil.TempFile("", "config.yml")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = configFile.WriteString(fmt.Sprintf(configContent+`skip_ssl_validation: true
+user: admin
+`, server.URL))
+				Expect(err).NotTo(HaveOccurred())
+
+				err = configFile.Close()
+				Expect(err).NotTo(HaveOccurred())
+
+				command := exec.Command(pathToMain,
+					"--env", configFile.Name(),
+					"curl",
+					"-p", "/api/v0/available_products",
+				)
+
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(string(session.Err.Contents())).To(ContainSubstring(`warning: env file contains unrecognized keys, which are ignored: "skip_ssl_validation", "user"`))
+				Expect(string(session.Out.Contents())).To(MatchJSON(`[ { "name": "p-bosh", "product_version": "999.99" } ]`))
+			})
+		})
+
+		Context("when the env file is given by OM_ENV", func() {
+			It("authenticates with creds in config file", func() {
+				server := testServer(true)
+
+				createConfigFile(server.URL)
+
+				command := exec.Command(pathToMain,
+					"curl",
+					"-p", "/api/v0/available_products",
+				)
+				command.Env = append(os.Environ(), "OM_ENV="+configFile.Name())
+
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(string(session.Out.Contents())).To(MatchJSON(`[ { "name": "p-bosh", "product_version": "999.99" } ]`))
+			})
+		})
+
 		Context("when given an env file that does not exist", func() {
 			It("returns an error", func() {
 				command := exec.Command(pathToMain,
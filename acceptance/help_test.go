@@ -17,30 +17,47 @@ Usage: om [options] <command> [<args>]
   --client-secret, -s, OM_CLIENT_SECRET                  string  Client Secret for the Ops Manager VM (not required for unauthenticated commands)
   --connect-timeout, -o, OM_CONNECT_TIMEOUT              int     timeout in seconds to make TCP connections (default: 10)
   --decryption-passphrase, -d, OM_DECRYPTION_PASSPHRASE  string  Passphrase to decrypt the installation if the Ops Manager VM has been rebooted (optional for most commands)
-  --env, -e                                              string  env file with login credentials
+  --env, -e, OM_ENV                                      string  env file with login credentials
   --help, -h                                             bool    prints this usage information (default: false)
+  --log-format, OM_LOG_FORMAT                            string  format of the output (options: text,json), json writes every line as a record with its time and level (default: text)
+  --no-cache, OM_NO_CACHE                                bool    do not reuse the UAA token of the previous commands, nor store it for the next ones (default: false)
+  --otlp-endpoint, OTEL_EXPORTER_OTLP_ENDPOINT           string  OpenTelemetry collector to export the traces and the metrics of the downloads, the uploads and apply-changes to over OTLP/HTTP, e.g. http://localhost:4318
   --password, -p, OM_PASSWORD                            string  admin password for the Ops Manager VM (not required for unauthenticated commands)
+  --plugins-credentials, OM_PLUGINS_CREDENTIALS          bool    also give the credentials of om (username, password, client id and secret, decryption passphrase) to the plugins, which only get the target and a token otherwise (default: false)
+  --plugins-dir, OM_PLUGINS_DIR                          string  directory of the om-<name> executables that are run as the om <name> commands, before the ones on the PATH
   --request-timeout, -r, OM_REQUEST_TIMEOUT              int     timeout in seconds for HTTP requests to Ops Manager (default: 1800)
+  --retries, OM_RETRIES                                  int     number of times the requests that do not modify Ops Manager are retried when the connection is dropped or Ops Manager is unavailable (502, 503 or 504) (default: 3)
+  --retry-delay, OM_RETRY_DELAY                          int     delay in seconds before the first retry, doubled after every retry (default: 1)
   --skip-ssl-validation, -k, OM_SKIP_SSL_VALIDATION      bool    skip ssl certificate validation during http requests (default: false)
   --target, -t, OM_TARGET                                string  location of the Ops Manager VM
-  --trace, -tr, OM_TRACE                                 bool    prints HTTP requests and response payloads
+  --trace, -tr, OM_TRACE                                 bool    prints HTTP requests and responses, with the headers that carry credentials redacted
+  --trace-bodies, OM_TRACE_BODIES                        bool    also prints the payloads of the traced requests and responses, which can contain secrets (default: false)
+  --trace-file, OM_TRACE_FILE                            string  file to append the trace to instead of stderr (implies --trace)
   --username, -u, OM_USERNAME                            string  admin username for the Ops Manager VM (not required for unauthenticated commands)
   --version, -v                                          bool    prints the om release version (default: false)
 
 Commands:
   activate-certificate-authority  activates a certificate authority on the Ops Manager
   apply-changes                   triggers an install on the Ops Manager targeted
+  apply-environment               configures the director, the products and their stemcells from a config directory
+  assign-multi-stemcell           assigns multiple uploaded stemcells to a product in the targeted Ops Manager 2.6+
   assign-stemcell                 assigns an uploaded stemcell to a product in the targeted Ops Manager
   available-products              list available products
+  blobstore                       prunes the old versions of the products persisted in an s3 blobstore
   bosh-env                        prints bosh environment variables
   certificate-authorities         lists certificates managed by Ops Manager
   certificate-authority           prints requested certificate authority
+  certificate-rotations           lists the rotations of the NATS and BOSH DNS certificates of the director
+  compare-config                  compares a product or director config against its staged configuration
   config-template                 **EXPERIMENTAL** generates a config template for the product
   configure-authentication        configures Ops Manager with an internal userstore and admin user account
   configure-director              configures the director
   configure-ldap-authentication   configures Ops Manager with LDAP authentication
+  configure-opsman                configures the settings of the Ops Manager
   configure-product               configures a staged product
+  configure-resource-config       configures the resource config of the jobs of a staged product
   configure-saml-authentication   configures Ops Manager with SAML authentication
+  configure-syslog                configures syslog forwarding of the director and products
   create-certificate-authority    creates a certificate authority on the Ops Manager
   create-vm-extension             creates/updates a VM extension
   credential-references           list credential references for a deployed product
@@ -51,10 +68,17 @@ Commands:
   delete-product                  deletes a product from the Ops Manager
   delete-ssl-certificate          deletes certificate applied to Ops Manager
   delete-unused-products          deletes unused products on the Ops Manager targeted
-  deployed-manifest               prints the deployed manifest for a product
+  delete-vm-extension             deletes a VM extension
+  deployed-manifest               prints the deployed manifest for a product or the director
   deployed-products               lists deployed products
+  diff                            displays the changes between the deployed and staged manifests
+  director-verifiers              lists the director verifiers
+  disable-director-verifiers      disables director verifiers
+  disable-product-verifiers       disables product verifiers
   download-product                downloads a specified product file from Pivotal Network
   errands                         list errands for a product
+  expiring-certificates           lists the certificates expiring soon
+  expiring-licenses               lists the licenses of the products expiring soon
   export-installation             exports the installation of the target Ops Manager
   generate-certificate            generates a new certificate signed by Ops Manager's root CA
   generate-certificate-authority  generates a certificate authority on the Opsman
@@ -64,20 +88,32 @@ Commands:
   installations                   list recent installation events
   interpolate                     Interpolates variables into a manifest
   pending-changes                 lists pending changes
+  pre-deploy-check                checks that the director and staged products are ready to be deployed
+  product-metadata                prints the metadata of a .pivotal file
+  products                        lists the available, staged and deployed versions of products
   regenerate-certificates         deletes all non-configurable certificates in Ops Manager so they will automatically be regenerated on the next apply-changes
+  resource-config                 lists the resource config of the jobs of a staged product
   revert-staged-changes           reverts staged changes on the Ops Manager targeted
+  rotate-certificate-authority    rotates the certificate authority of the Ops Manager
+  rotate-director-certificates    rotates the NATS or BOSH DNS certificates of the director
+  ssh                             opens an ssh session on the Ops Manager VM or a VM of a product
   ssl-certificate                 gets certificate applied to Ops Manager
   stage-product                   stages a given product in the Ops Manager targeted
   staged-config                   **EXPERIMENTAL** generates a config from a staged product
   staged-director-config          **EXPERIMENTAL** generates a config from a staged director
   staged-manifest                 prints the staged manifest for a product
   staged-products                 lists staged products
+  stemcell-assignments            lists the stemcells required by and assigned to staged products
   tile-metadata                   prints tile metadata
   unstage-product                 unstages a given product from the Ops Manager targeted
   update-ssl-certificate          updates the SSL Certificate on the Ops Manager
+  upgrade-opsman                  upgrades the Ops Manager by exporting and importing its installation
   upload-product                  uploads a given product to the Ops Manager targeted
   upload-stemcell                 uploads a given stemcell to the Ops Manager targeted
+  validate-config                 validates a product config against the properties of the staged product
   version                         prints the om release version
+  vm-extensions                   lists VM extensions
+  vm-lifecycle                    creates, deletes or replaces the Ops Manager VM
 `
 
 const CONFIGURE_AUTHENTICATION_USAGE = `ॐ  configure-authentication
@@ -88,13 +124,22 @@ Usage: om [options] configure-authentication [<args>]
   --client-secret, -s, OM_CLIENT_SECRET                  string  Client Secret for the Ops Manager VM (not required for unauthenticated commands)
   --connect-timeout, -o, OM_CONNECT_TIMEOUT              int     timeout in seconds to make TCP connections (default: 10)
   --decryption-passphrase, -d, OM_DECRYPTION_PASSPHRASE  string  Passphrase to decrypt the installation if the Ops Manager VM has been rebooted (optional for most commands)
-  --env, -e                                              string  env file with login credentials
+  --env, -e, OM_ENV                                      string  env file with login credentials
   --help, -h                                             bool    prints this usage information (default: false)
+  --log-format, OM_LOG_FORMAT                            string  format of the output (options: text,json), json writes every line as a record with its time and level (default: text)
+  --no-cache, OM_NO_CACHE                                bool    do not reuse the UAA token of the previous commands, nor store it for the next ones (default: false)
+  --otlp-endpoint, OTEL_EXPORTER_OTLP_ENDPOINT           string  OpenTelemetry collector to export the traces and the metrics of the downloads, the uploads and apply-changes to over OTLP/HTTP, e.g. http://localhost:4318
   --password, -p, OM_PASSWORD                            string  admin password for the Ops Manager VM (not required for unauthenticated commands)
+  --plugins-credentials, OM_PLUGINS_CREDENTIALS          bool    also give the credentials of om (username, password, client id and secret, decryption passphrase) to the plugins, which only get the target and a token otherwise (default: false)
+  --plugins-dir, OM_PLUGINS_DIR                          string  directory of the om-<name> executables that are run as the om <name> commands, before the ones on the PATH
   --request-timeout, -r, OM_REQUEST_TIMEOUT              int     timeout in seconds for HTTP requests to Ops Manager (default: 1800)
+  --retries, OM_RETRIES                                  int     number of times the requests that do not modify Ops Manager are retried when the connection is dropped or Ops Manager is unavailable (502, 503 or 504) (default: 3)
+  --retry-delay, OM_RETRY_DELAY                          int     delay in seconds before the first retry, doubled after every retry (default: 1)
   --skip-ssl-validation, -k, OM_SKIP_SSL_VALIDATION      bool    skip ssl certificate validation during http requests (default: false)
   --target, -t, OM_TARGET                                string  location of the Ops Manager VM
-  --trace, -tr, OM_TRACE                                 bool    prints HTTP requests and response payloads
+  --trace, -tr, OM_TRACE                                 bool    prints HTTP requests and responses, with the headers that carry credentials redacted
+  --trace-bodies, OM_TRACE_BODIES                        bool    also prints the payloads of the traced requests and responses, which can contain secrets (default: false)
+  --trace-file, OM_TRACE_FILE                            string  file to append the trace to instead of stderr (implies --trace)
   --username, -u, OM_USERNAME                            string  admin username for the Ops Manager VM (not required for unauthenticated commands)
   --version, -v                                          bool    prints the om release version (default: false)
 
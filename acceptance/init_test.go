@@ -5,6 +5,7 @@ import (
 	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 	"io/ioutil"
+	"os"
 	"os/exec"
 
 	. "github.com/onsi/ginkgo"
@@ -43,6 +44,9 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	return []byte(pathToMain)
 }, func(data []byte) {
 	pathToMain = string(data)
+
+	// the commands must not reuse the tokens of the servers of other tests
+	os.Setenv("OM_NO_CACHE", "true")
 })
 
 var _ = SynchronizedAfterSuite(func() {
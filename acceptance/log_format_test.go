@@ -0,0 +1,145 @@
+package acceptance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/onsi/gomega/gexec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("global log-format flag", func() {
+	type record struct {
+		Time    string `json:"time"`
+		Level   string `json:"level"`
+		Stream  string `json:"stream"`
+		Message string `json:"message"`
+	}
+
+	parseRecords := func(output []byte) []record {
+		var records []record
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			var r record
+			Expect(json.Unmarshal([]byte(line), &r)).To(Succeed(), line)
+
+			_, err := time.Parse(time.RFC3339Nano, r.Time)
+			Expect(err).NotTo(HaveOccurred())
+
+			records = append(records, r)
+		}
+		return records
+	}
+
+	var version string
+
+	BeforeEach(func() {
+		output, err := exec.Command(pathToMain, "version").Output()
+		Expect(err).NotTo(HaveOccurred())
+
+		version = strings.TrimSpace(string(output))
+	})
+
+	It("writes the output as json records", func() {
+		cmd := exec.Command(pathToMain, "--log-format", "json", "version")
+
+		session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(0))
+
+		records := parseRecords(session.Out.Contents())
+		Expect(records).To(HaveLen(1))
+		Expect(records[0].Level).To(Equal("info"))
+		Expect(records[0].Stream).To(Equal("stdout"))
+		Expect(records[0].Message).To(Equal(version))
+	})
+
+	It("writes the errors as json records with the error level", func() {
+		cmd := exec.Command(pathToMain, "--log-format", "json", "-t", "pcf.foo.cf-app.com", "banana")
+		cmd.Env = append(cmd.Env, "OM_LOG_FORMAT=text")
+
+		session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(1))
+
+		records := parseRecords(session.Err.Contents())
+		Expect(records[len(records)-1].Level).To(Equal("error"))
+		Expect(records[len(records)-1].Stream).To(Equal("stderr"))
+		Expect(records[len(records)-1].Message).To(ContainSubstring("unknown command: banana"))
+	})
+
+	It("writes what the commands write to stderr as json records", func() {
+		var attempts int
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/uaa/oauth/token":
+				w.Header().Set("Content-Type", "application/json")
+				_, err := w.Write([]byte(`{"access_token": "some-opsman-token", "token_type": "bearer", "expires_in": 3600}`))
+				Expect(err).NotTo(HaveOccurred())
+			case "/api/v0/available_products":
+				attempts++
+				if attempts == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				_, err := w.Write([]byte(`[]`))
+				Expect(err).NotTo(HaveOccurred())
+			default:
+				Fail("unexpected request: " + req.URL.Path)
+			}
+		}))
+		defer server.Close()
+
+		cmd := exec.Command(pathToMain,
+			"--log-format", "json",
+			"--target", server.URL,
+			"--username", "some-username",
+			"--password", "some-password",
+			"--skip-ssl-validation",
+			"--retry-delay", "0",
+			"curl", "--path", "/api/v0/available_products",
+		)
+
+		session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(0))
+
+		var messages []string
+		for _, r := range parseRecords(session.Err.Contents()) {
+			Expect(r.Stream).To(Equal("stderr"))
+			messages = append(messages, r.Message)
+		}
+		Expect(strings.Join(messages, "\n")).To(ContainSubstring("GET /api/v0/available_products failed"))
+	})
+
+	It("can be set with OM_LOG_FORMAT", func() {
+		cmd := exec.Command(pathToMain, "version")
+		cmd.Env = append(cmd.Env, "OM_LOG_FORMAT=json")
+
+		session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(0))
+
+		records := parseRecords(session.Out.Contents())
+		Expect(records[0].Message).To(Equal(version))
+	})
+
+	It("fails on an unknown format", func() {
+		cmd := exec.Command(pathToMain, "--log-format", "xml", "version")
+
+		session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring(`unknown log format "xml", expected text or json`))
+	})
+})
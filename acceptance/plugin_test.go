@@ -0,0 +1,145 @@
+package acceptance
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("plugins", func() {
+	const plugin = `#!/bin/sh
+echo "target: $OM_TARGET"
+echo "token: $OM_ACCESS_TOKEN"
+echo "bin: $OM_BIN"
+echo "password: $OM_PASSWORD"
+echo "env: $OM_ENV"
+echo "args: $@"
+exit 3
+`
+
+	var (
+		server     *httptest.Server
+		pluginsDir string
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/uaa/oauth/token":
+				w.Header().Set("Content-Type", "application/json")
+				_, err := w.Write([]byte(`{
+					"access_token": "some-opsman-token",
+					"token_type": "bearer",
+					"expires_in": 3600
+				}`))
+				Expect(err).ToNot(HaveOccurred())
+			default:
+				Fail(fmt.Sprintf("unexpected request: %s", req.URL.Path))
+			}
+		}))
+
+		var err error
+		pluginsDir, err = ioutil.TempDir("", "om-plugins")
+		Expect(err).ToNot(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(pluginsDir, "om-compliance"), []byte(plugin), 0755)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(pluginsDir)
+	})
+
+	It("runs the plugin with the target and a token in its environment", func() {
+		command := exec.Command(pathToMain,
+			"--target", server.URL,
+			"--username", "some-username",
+			"--password", "some-password",
+			"--skip-ssl-validation",
+			"--plugins-dir", pluginsDir,
+			"compliance", "--strict", "cf",
+		)
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(3))
+		Expect(session.Out).To(gbytes.Say("target: " + server.URL))
+		Expect(session.Out).To(gbytes.Say("token: some-opsman-token"))
+		Expect(session.Out).To(gbytes.Say("bin: " + pathToMain))
+		Expect(session.Out).To(gbytes.Say("password: \n"))
+		Expect(session.Out).To(gbytes.Say("args: --strict cf"))
+	})
+
+	It("runs the plugin with the credentials of om with --plugins-credentials", func() {
+		command := exec.Command(pathToMain,
+			"--target", server.URL,
+			"--username", "some-username",
+			"--skip-ssl-validation",
+			"--plugins-dir", pluginsDir,
+			"--plugins-credentials",
+			"compliance",
+		)
+		command.Env = append(os.Environ(), "OM_PASSWORD=some-password")
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(3))
+		Expect(session.Out).To(gbytes.Say("token: some-opsman-token"))
+		Expect(session.Out).To(gbytes.Say("password: some-password\n"))
+	})
+
+	It("clears the credentials of the environment of om without --plugins-credentials", func() {
+		command := exec.Command(pathToMain, "compliance")
+		command.Env = append(os.Environ(), "OM_PLUGINS_DIR="+pluginsDir, "OM_PASSWORD=some-password")
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(3))
+		Expect(session.Out).To(gbytes.Say("password: \n"))
+	})
+
+	It("clears the env file of om without --plugins-credentials", func() {
+		envFile, err := ioutil.TempFile("", "env.yml")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(envFile.Name())
+
+		_, err = fmt.Fprintf(envFile, "target: %s\nusername: some-username\npassword: some-password\nskip-ssl-validation: true\n", server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(envFile.Close()).To(Succeed())
+
+		command := exec.Command(pathToMain, "compliance")
+		command.Env = append(os.Environ(), "OM_PLUGINS_DIR="+pluginsDir, "OM_ENV="+envFile.Name())
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(3))
+		Expect(session.Out).To(gbytes.Say("token: some-opsman-token"))
+		Expect(session.Out).To(gbytes.Say("password: \n"))
+		Expect(session.Out).To(gbytes.Say("env: \n"))
+	})
+
+	It("runs the plugin without a token when om has no credentials", func() {
+		command := exec.Command(pathToMain, "compliance")
+		command.Env = append(os.Environ(), "OM_PLUGINS_DIR="+pluginsDir)
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(3))
+		Expect(session.Out).To(gbytes.Say("token: \n"))
+	})
+})
@@ -5,7 +5,6 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
-	"net/url"
 	"os/exec"
 
 	. "github.com/onsi/ginkgo"
@@ -16,12 +15,15 @@ import (
 
 var _ = Describe("revert-staged-changes command", func() {
 	var (
-		server          *httptest.Server
-		receivedCookies []*http.Cookie
-		Forms           []url.Values
+		server         *httptest.Server
+		revertStatus   int
+		revertRequests int
 	)
 
 	BeforeEach(func() {
+		revertStatus = http.StatusOK
+		revertRequests = 0
+
 		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			switch req.URL.Path {
 			case "/uaa/oauth/token":
@@ -32,34 +34,12 @@ var _ = Describe("revert-staged-changes command", func() {
 				"expires_in": 3600
 			}`))
 				Expect(err).ToNot(HaveOccurred())
-			case "/":
-				http.SetCookie(w, &http.Cookie{
-					Name:  "somecookie",
-					Value: "somevalue",
-					Path:  "/",
-				})
-
-				_, err := w.Write([]byte(`<html>
-				<body>
-					<form action="/some-other-form" method="post">
-						<input name="_method" value="fakemethod2" />
-						<input name="authenticity_token" value="fake_authenticity2" />
-					</form>
-					<form action="/installation" method="post">
-						<input name="_method" value="fakemethod" />
-						<input name="authenticity_token" value="fake_authenticity" />
-					</form>
-					<form action="/install" method="post">
-						<input name="_method" value="fakemethod" />
-						<input name="authenticity_token" value="fake_authenticity" />
-					</form>
-					</body>
-				</html>`))
-				Expect(err).ToNot(HaveOccurred())
-			case "/installation":
-				receivedCookies = req.Cookies()
-				_ = req.ParseForm()
-				Forms = append(Forms, req.Form)
+			case "/api/v0/staged":
+				Expect(req.Method).To(Equal("DELETE"))
+				Expect(req.Header.Get("Authorization")).To(Equal("Bearer some-opsman-token"))
+
+				revertRequests++
+				w.WriteHeader(revertStatus)
 			default:
 				out, err := httputil.DumpRequest(req, true)
 				Expect(err).NotTo(HaveOccurred())
@@ -69,7 +49,6 @@ var _ = Describe("revert-staged-changes command", func() {
 	})
 
 	AfterEach(func() {
-		Forms = []url.Values{}
 		server.Close()
 	})
 
@@ -87,18 +66,25 @@ var _ = Describe("revert-staged-changes command", func() {
 		)
 	})
 
-	It("reverts staged changes on the installation dashboard", func() {
+	It("reverts staged changes on the targeted Ops Manager", func() {
 		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
 		Expect(err).NotTo(HaveOccurred())
 
 		Eventually(session).Should(gexec.Exit(0))
 
 		Expect(session.Out).To(gbytes.Say("reverting staged changes on the targeted Ops Manager"))
-		Expect(receivedCookies).To(HaveLen(1))
-		Expect(receivedCookies[0].Name).To(Equal("somecookie"))
+		Expect(session.Out).To(gbytes.Say("done"))
+		Expect(revertRequests).To(Equal(1))
+	})
+
+	It("succeeds when there are no staged changes", func() {
+		revertStatus = http.StatusNotModified
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(0))
 
-		Expect(Forms[0].Get("authenticity_token")).To(Equal("fake_authenticity"))
-		Expect(Forms[0].Get("_method")).To(Equal("delete"))
-		Expect(Forms[0].Get("commit")).To(Equal("Confirm"))
+		Expect(session.Out).To(gbytes.Say("no staged changes to revert"))
 	})
 })
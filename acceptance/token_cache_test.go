@@ -0,0 +1,111 @@
+package acceptance
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("token cache", func() {
+	var (
+		server        *httptest.Server
+		cacheDir      string
+		mutex         sync.Mutex
+		tokenRequests int
+		validToken    string
+	)
+
+	BeforeEach(func() {
+		tokenRequests = 0
+		validToken = "some-opsman-token-1"
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+
+			switch req.URL.Path {
+			case "/uaa/oauth/token":
+				tokenRequests++
+				validToken = fmt.Sprintf("some-opsman-token-%d", tokenRequests)
+				_, err := w.Write([]byte(fmt.Sprintf(`{
+					"access_token": "%s",
+					"token_type": "bearer",
+					"expires_in": 3600
+				}`, validToken)))
+				Expect(err).ToNot(HaveOccurred())
+			case "/api/v0/diagnostic_report":
+				if req.Header.Get("Authorization") != "Bearer "+validToken {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				_, err := w.Write([]byte(`{"added_products": {"deployed": []}}`))
+				Expect(err).ToNot(HaveOccurred())
+			default:
+				Fail(fmt.Sprintf("unexpected request: %s", req.URL.Path))
+			}
+		}))
+
+		var err error
+		cacheDir, err = ioutil.TempDir("", "om-token-cache")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(cacheDir)
+	})
+
+	runDeployedProducts := func(env ...string) {
+		command := exec.Command(pathToMain,
+			"--target", server.URL,
+			"--username", "some-username",
+			"--password", "some-password",
+			"--skip-ssl-validation",
+			"deployed-products",
+		)
+		command.Env = append(os.Environ(), "XDG_CACHE_HOME="+cacheDir, "HOME="+cacheDir)
+		command.Env = append(command.Env, env...)
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(session, "10s").Should(gexec.Exit(0))
+	}
+
+	It("reuses the token of the previous command", func() {
+		runDeployedProducts("OM_NO_CACHE=false")
+		runDeployedProducts("OM_NO_CACHE=false")
+
+		Expect(tokenRequests).To(Equal(1))
+	})
+
+	It("retrieves a new token when the cached token is rejected", func() {
+		runDeployedProducts("OM_NO_CACHE=false")
+
+		mutex.Lock()
+		validToken = "some-revoked-token"
+		mutex.Unlock()
+
+		runDeployedProducts("OM_NO_CACHE=false")
+		runDeployedProducts("OM_NO_CACHE=false")
+
+		Expect(tokenRequests).To(Equal(2))
+	})
+
+	It("does not cache the token with --no-cache", func() {
+		runDeployedProducts()
+		runDeployedProducts()
+
+		Expect(tokenRequests).To(Equal(2))
+	})
+})
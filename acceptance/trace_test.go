@@ -115,6 +115,51 @@ name: some-product`)
 		Expect(string(session.Err.Contents())).To(ContainSubstring("200 OK"))
 	})
 
+	It("writes the trace to the --trace-file", func() {
+		traceFile, err := ioutil.TempFile("", "trace")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(traceFile.Name())
+		Expect(traceFile.Close()).To(Succeed())
+
+		command := exec.Command(pathToMain,
+			"--target", server.URL,
+			"--username", "some-username",
+			"--password", "some-password",
+			"--skip-ssl-validation",
+			"--trace-file", traceFile.Name(),
+			"available-products")
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session, "40s").Should(gexec.Exit(0))
+
+		Expect(string(session.Err.Contents())).NotTo(ContainSubstring("GET /api/v0"))
+
+		trace, err := ioutil.ReadFile(traceFile.Name())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(trace)).To(MatchRegexp(`GET https://127.0.0.1:\d+/api/v0/available_products: 200 OK in \d+m?s`))
+		Expect(string(trace)).NotTo(ContainSubstring("some-product"))
+	})
+
+	It("prints the payloads with --trace-bodies", func() {
+		command := exec.Command(pathToMain,
+			"--target", server.URL,
+			"--username", "some-username",
+			"--password", "some-password",
+			"--skip-ssl-validation",
+			"--trace",
+			"--trace-bodies",
+			"available-products")
+
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session, "40s").Should(gexec.Exit(0))
+
+		Expect(string(session.Err.Contents())).To(ContainSubstring(`"product_version":"1.7.2"`))
+	})
+
 	It("prints helpful debug output for upload requests", func() {
 		command := exec.Command(pathToMain,
 			"--target", server.URL,
@@ -34,6 +34,13 @@ var _ = Describe("unstage-product command", func() {
 						"token_type": "bearer",
 						"expires_in": 3600
 					}`
+				case "/api/v0/deployed/products":
+					auth := req.Header.Get("Authorization")
+					if auth != "Bearer some-opsman-token" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					responseString = `[]`
 				case "/api/v0/staged/products":
 					auth := req.Header.Get("Authorization")
 					if auth != "Bearer some-opsman-token" {
@@ -109,6 +116,13 @@ var _ = Describe("unstage-product command", func() {
 						"token_type": "bearer",
 						"expires_in": 3600
 					}`
+				case "/api/v0/deployed/products":
+					auth := req.Header.Get("Authorization")
+					if auth != "Bearer some-opsman-token" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					responseString = `[]`
 				case "/api/v0/staged/products":
 					auth := req.Header.Get("Authorization")
 					if auth != "Bearer some-opsman-token" {
@@ -60,9 +60,8 @@ func parseKeyValues(credentials string) map[string]string {
 	kvs := strings.Split(credentials, " ")
 	for _, kv := range kvs {
 		if strings.Contains(kv, "=") {
-			k := strings.Split(kv, "=")[0]
-			v := strings.Split(kv, "=")[1]
-			values[k] = v
+			parts := strings.SplitN(kv, "=", 2)
+			values[parts[0]] = parts[1]
 		}
 	}
 	return values
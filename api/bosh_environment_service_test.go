@@ -51,6 +51,19 @@ var _ = Describe("Credentials", func() {
 			Expect(output.Environment).To(Equal("10.0.0.10"))
 		})
 
+		It("keeps the equal signs of the client secret", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(
+					strings.NewReader(`{"credential":"BOSH_CLIENT=ops_manager BOSH_CLIENT_SECRET=foo=bar== BOSH_ENVIRONMENT=10.0.0.10 bosh "}`),
+				),
+			}, nil)
+
+			output, err := service.GetBoshEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.ClientSecret).To(Equal("foo=bar=="))
+		})
+
 		Describe("errors", func() {
 			Context("the client can't connect to the server", func() {
 				It("returns an error", func() {
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CertificateRotation is the progress of the rotation of the certificate
+// authority of the NATS or the BOSH DNS certificates of the director, which
+// takes an apply-changes after each of its phases.
+type CertificateRotation struct {
+	Type                 string `json:"type"`
+	Phase                string `json:"phase"`
+	ApplyChangesRequired bool   `json:"apply_changes_required"`
+	UpdatedAt            string `json:"updated_at"`
+}
+
+type CertificateRotationsOutput struct {
+	Rotations []CertificateRotation `json:"certificate_rotations"`
+}
+
+func (a Api) ListCertificateRotations() (CertificateRotationsOutput, error) {
+	var output CertificateRotationsOutput
+
+	resp, err := a.sendAPIRequest("GET", "/api/v0/certificate_rotations", nil)
+	if err != nil {
+		return output, err
+	}
+
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return CertificateRotationsOutput{}, err
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&output)
+	if err != nil {
+		return CertificateRotationsOutput{}, err
+	}
+
+	return output, nil
+}
+
+// StartCertificateRotationPhase runs the phase of the rotation of the
+// certificates of the type, e.g. the create phase of the nats rotation.
+func (a Api) StartCertificateRotationPhase(rotationType, phase string) (CertificateRotation, error) {
+	var output CertificateRotation
+
+	resp, err := a.sendAPIRequest("POST", fmt.Sprintf("/api/v0/certificate_rotations/%s/%s", rotationType, phase), []byte("{}"))
+	if err != nil {
+		return output, err
+	}
+
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return CertificateRotation{}, err
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&output)
+	if err != nil {
+		return CertificateRotation{}, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,139 @@
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/api/fakes"
+)
+
+var _ = Describe("CertificateRotations", func() {
+	var (
+		client  *fakes.HttpClient
+		service api.Api
+	)
+
+	BeforeEach(func() {
+		client = &fakes.HttpClient{}
+		service = api.New(api.ApiInput{
+			Client: client,
+		})
+	})
+
+	Describe("ListCertificateRotations", func() {
+		It("returns the rotations of the director certificates", func() {
+			var path string
+			client.DoStub = func(req *http.Request) (*http.Response, error) {
+				path = req.URL.Path
+
+				return &http.Response{StatusCode: http.StatusOK,
+					Body: ioutil.NopCloser(strings.NewReader(`{
+"certificate_rotations": [
+	{"type": "nats", "phase": "created", "apply_changes_required": true, "updated_at": "2019-01-09T10:00:00Z"},
+	{"type": "bosh-dns", "phase": "none", "apply_changes_required": false, "updated_at": ""}
+]
+}`)),
+				}, nil
+			}
+
+			output, err := service.ListCertificateRotations()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(output.Rotations).To(Equal([]api.CertificateRotation{
+				{Type: "nats", Phase: "created", ApplyChangesRequired: true, UpdatedAt: "2019-01-09T10:00:00Z"},
+				{Type: "bosh-dns", Phase: "none"},
+			}))
+
+			Expect(path).To(Equal("/api/v0/certificate_rotations"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the client cannot make a request", func() {
+				client.DoReturns(nil, errors.New("client do errored"))
+
+				_, err := service.ListCertificateRotations()
+				Expect(err).To(MatchError("could not send api request to GET /api/v0/certificate_rotations: client do errored"))
+			})
+
+			It("returns an error when Ops Manager returns a non-200 status code", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+				}, nil)
+
+				_, err := service.ListCertificateRotations()
+				Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+			})
+
+			It("returns an error when the response body cannot be parsed", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`%%%%`)),
+				}, nil)
+
+				_, err := service.ListCertificateRotations()
+				Expect(err).To(MatchError(ContainSubstring("invalid character")))
+			})
+		})
+	})
+
+	Describe("StartCertificateRotationPhase", func() {
+		It("runs the phase of the rotation", func() {
+			var (
+				path   string
+				method string
+			)
+			client.DoStub = func(req *http.Request) (*http.Response, error) {
+				path = req.URL.Path
+				method = req.Method
+
+				return &http.Response{StatusCode: http.StatusOK,
+					Body: ioutil.NopCloser(strings.NewReader(`{"type": "nats", "phase": "created", "apply_changes_required": true}`)),
+				}, nil
+			}
+
+			rotation, err := service.StartCertificateRotationPhase("nats", "create")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rotation).To(Equal(api.CertificateRotation{Type: "nats", Phase: "created", ApplyChangesRequired: true}))
+
+			Expect(method).To(Equal("POST"))
+			Expect(path).To(Equal("/api/v0/certificate_rotations/nats/create"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the client cannot make a request", func() {
+				client.DoReturns(nil, errors.New("client do errored"))
+
+				_, err := service.StartCertificateRotationPhase("bosh-dns", "activate")
+				Expect(err).To(MatchError("could not send api request to POST /api/v0/certificate_rotations/bosh-dns/activate: client do errored"))
+			})
+
+			It("returns an error when Ops Manager returns a non-200 status code", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusUnprocessableEntity,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"errors": ["apply changes first"]}`)),
+				}, nil)
+
+				_, err := service.StartCertificateRotationPhase("nats", "activate")
+				Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+			})
+
+			It("returns an error when the response body cannot be parsed", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`%%%%`)),
+				}, nil)
+
+				_, err := service.StartCertificateRotationPhase("nats", "cleanup")
+				Expect(err).To(MatchError(ContainSubstring("invalid character")))
+			})
+		})
+	})
+})
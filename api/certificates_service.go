@@ -2,13 +2,32 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net/url"
 )
 
 type DomainsInput struct {
 	Domains []string `json:"domains"`
 }
 
+type ExpiringCertificatesOutput struct {
+	Certificates []ExpiringCertificate `json:"certificates"`
+}
+
+type ExpiringCertificate struct {
+	Configurable      bool   `json:"configurable"`
+	IsCA              bool   `json:"is_ca"`
+	PropertyReference string `json:"property_reference"`
+	PropertyType      string `json:"property_type"`
+	ProductGUID       string `json:"product_guid"`
+	Location          string `json:"location"`
+	VariablePath      string `json:"variable_path"`
+	Issuer            string `json:"issuer"`
+	ValidFrom         string `json:"valid_from"`
+	ValidUntil        string `json:"valid_until"`
+}
+
 func (a Api) GenerateCertificate(domains DomainsInput) (string, error) {
 	payload, err := json.Marshal(domains)
 	if err != nil {
@@ -32,3 +51,28 @@ func (a Api) GenerateCertificate(domains DomainsInput) (string, error) {
 
 	return string(respBody), nil
 }
+
+// ListExpiringCertificates lists the certificates of the director and of the
+// deployed products, CAs included, that expire within the duration, such as
+// "3m" for three months (d, w, m and y are understood by Ops Manager).
+func (a Api) ListExpiringCertificates(expiresWithin string) ([]ExpiringCertificate, error) {
+	path := "/api/v0/deployed/certificates?expires_within=" + url.QueryEscape(expiresWithin)
+
+	resp, err := a.sendAPIRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return nil, err
+	}
+
+	var output ExpiringCertificatesOutput
+	err = json.NewDecoder(resp.Body).Decode(&output)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal the expiring certificates: %s", err)
+	}
+
+	return output.Certificates, nil
+}
@@ -104,4 +104,102 @@ var _ = Describe("Certificates", func() {
 			})
 		})
 	})
+
+	Describe("ListExpiringCertificates", func() {
+		It("lists the certificates expiring within the duration", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"certificates": [
+						{
+							"configurable": false,
+							"is_ca": true,
+							"property_reference": ".properties.root_ca",
+							"property_type": "rsa_cert_credentials",
+							"product_guid": "p-bosh-guid",
+							"location": "ops_manager",
+							"variable_path": null,
+							"issuer": "/C=US/O=Pivotal",
+							"valid_from": "2017-01-09T19:27:16Z",
+							"valid_until": "2021-01-09T19:27:16Z"
+						},
+						{
+							"configurable": true,
+							"is_ca": false,
+							"property_reference": null,
+							"property_type": "rsa_cert_credentials",
+							"product_guid": "cf-guid",
+							"location": "credhub",
+							"variable_path": "/opsmgr/cf-guid/uaa/service_provider_credentials",
+							"issuer": "/C=US/O=Pivotal",
+							"valid_from": "2018-01-09T19:27:16Z",
+							"valid_until": "2020-01-09T19:27:16Z"
+						}
+					]
+				}`)),
+			}, nil)
+
+			certificates, err := service.ListExpiringCertificates("3m")
+			Expect(err).NotTo(HaveOccurred())
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.Path).To(Equal("/api/v0/deployed/certificates"))
+			Expect(request.URL.Query().Get("expires_within")).To(Equal("3m"))
+
+			Expect(certificates).To(Equal([]api.ExpiringCertificate{
+				{
+					Configurable:      false,
+					IsCA:              true,
+					PropertyReference: ".properties.root_ca",
+					PropertyType:      "rsa_cert_credentials",
+					ProductGUID:       "p-bosh-guid",
+					Location:          "ops_manager",
+					Issuer:            "/C=US/O=Pivotal",
+					ValidFrom:         "2017-01-09T19:27:16Z",
+					ValidUntil:        "2021-01-09T19:27:16Z",
+				},
+				{
+					Configurable: true,
+					IsCA:         false,
+					PropertyType: "rsa_cert_credentials",
+					ProductGUID:  "cf-guid",
+					Location:     "credhub",
+					VariablePath: "/opsmgr/cf-guid/uaa/service_provider_credentials",
+					Issuer:       "/C=US/O=Pivotal",
+					ValidFrom:    "2018-01-09T19:27:16Z",
+					ValidUntil:   "2020-01-09T19:27:16Z",
+				},
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the client cannot make the request", func() {
+				client.DoReturns(nil, errors.New("client do errored"))
+
+				_, err := service.ListExpiringCertificates("3m")
+				Expect(err).To(MatchError("could not send api request to GET /api/v0/deployed/certificates?expires_within=3m: client do errored"))
+			})
+
+			It("returns an error when Ops Manager returns a non-200 status code", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+				}, nil)
+
+				_, err := service.ListExpiringCertificates("3m")
+				Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+			})
+
+			It("returns an error when the response is not json", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`%%%`)),
+				}, nil)
+
+				_, err := service.ListExpiringCertificates("3m")
+				Expect(err).To(MatchError(ContainSubstring("could not unmarshal the expiring certificates")))
+			})
+		})
+	})
 })
@@ -9,8 +9,18 @@ import (
 )
 
 type DeployedProductOutput struct {
-	Type string
-	GUID string
+	Type            string
+	GUID            string
+	LicenseMetadata []LicenseMetadata `json:"license_metadata,omitempty"`
+}
+
+// LicenseMetadata describes a license of a product, as set by one of its
+// properties; the expiry is a date, such as 2020-01-31.
+type LicenseMetadata struct {
+	PropertyReference string `json:"property_reference"`
+	ExpiresAt         string `json:"expiry"`
+	ProductName       string `json:"product_name"`
+	ProductVersion    string `json:"product_version"`
 }
 
 func (a Api) GetDeployedProductManifest(guid string) (string, error) {
@@ -119,7 +119,13 @@ key-4: 2147483648
 						},
 						{
 							"guid":"some-other-product-guid",
-							"type":"some-other-type"
+							"type":"some-other-type",
+							"license_metadata": [{
+								"property_reference": ".properties.license_key",
+								"expiry": "2020-01-31",
+								"product_name": "some-other-type",
+								"product_version": "1.2.3"
+							}]
 						}]`)),
 					}
 				}
@@ -139,6 +145,12 @@ key-4: 2147483648
 				{
 					GUID: "some-other-product-guid",
 					Type: "some-other-type",
+					LicenseMetadata: []api.LicenseMetadata{{
+						PropertyReference: ".properties.license_key",
+						ExpiresAt:         "2020-01-31",
+						ProductName:       "some-other-type",
+						ProductVersion:    "1.2.3",
+					}},
 				},
 			},
 			))
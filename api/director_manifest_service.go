@@ -0,0 +1,56 @@
+package api
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+func (a Api) GetStagedDirectorManifest() (string, error) {
+	resp, err := a.sendAPIRequest("GET", "/api/v0/staged/director/manifest", nil)
+	if err != nil {
+		return "", errors.Wrap(err, "could not make api request to staged director manifest endpoint")
+	}
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return "", err
+	}
+
+	var contents struct {
+		Manifest interface{}
+	}
+	if err := yaml.NewDecoder(resp.Body).Decode(&contents); err != nil {
+		return "", errors.Wrap(err, "could not parse json")
+	}
+
+	manifest, err := yaml.Marshal(contents.Manifest)
+	if err != nil {
+		return "", err // not tested
+	}
+
+	return string(manifest), nil
+}
+
+func (a Api) GetDeployedDirectorManifest() (string, error) {
+	resp, err := a.sendAPIRequest("GET", "/api/v0/deployed/director/manifest", nil)
+	if err != nil {
+		return "", errors.Wrap(err, "could not make api request to deployed director manifest endpoint")
+	}
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return "", err
+	}
+
+	var contents interface{}
+	if err := yaml.NewDecoder(resp.Body).Decode(&contents); err != nil {
+		return "", errors.Wrap(err, "could not parse json")
+	}
+
+	manifest, err := yaml.Marshal(contents)
+	if err != nil {
+		return "", err // not tested
+	}
+
+	return string(manifest), nil
+}
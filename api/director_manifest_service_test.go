@@ -0,0 +1,128 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/api/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DirectorManifest", func() {
+	var (
+		client  *fakes.HttpClient
+		service api.Api
+	)
+
+	BeforeEach(func() {
+		client = &fakes.HttpClient{}
+		service = api.New(api.ApiInput{
+			Client: client,
+		})
+	})
+
+	Describe("GetStagedDirectorManifest", func() {
+		It("returns the staged manifest of the director", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"manifest": {
+						"name": "p-bosh",
+						"instance_groups": [{"name": "bosh", "instances": 1}]
+					}
+				}`)),
+			}, nil)
+
+			manifest, err := service.GetStagedDirectorManifest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest).To(MatchYAML(`---
+name: p-bosh
+instance_groups:
+- name: bosh
+  instances: 1
+`))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.Path).To(Equal("/api/v0/staged/director/manifest"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the client request fails", func() {
+				client.DoReturns(&http.Response{}, errors.New("nope"))
+
+				_, err := service.GetStagedDirectorManifest()
+				Expect(err).To(MatchError(ContainSubstring("could not make api request to staged director manifest endpoint")))
+			})
+
+			It("returns an error when the server returns a non-200 status code", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+				}, nil)
+
+				_, err := service.GetStagedDirectorManifest()
+				Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+			})
+
+			It("returns an error when the response cannot be parsed", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`%%%`)),
+				}, nil)
+
+				_, err := service.GetStagedDirectorManifest()
+				Expect(err).To(MatchError(ContainSubstring("could not parse json")))
+			})
+		})
+	})
+
+	Describe("GetDeployedDirectorManifest", func() {
+		It("returns the deployed manifest of the director", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"name": "p-bosh",
+					"instance_groups": [{"name": "bosh", "instances": 1}]
+				}`)),
+			}, nil)
+
+			manifest, err := service.GetDeployedDirectorManifest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest).To(MatchYAML(`---
+name: p-bosh
+instance_groups:
+- name: bosh
+  instances: 1
+`))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.Path).To(Equal("/api/v0/deployed/director/manifest"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the client request fails", func() {
+				client.DoReturns(&http.Response{}, errors.New("nope"))
+
+				_, err := service.GetDeployedDirectorManifest()
+				Expect(err).To(MatchError(ContainSubstring("could not make api request to deployed director manifest endpoint")))
+			})
+
+			It("returns an error when the server returns a non-200 status code", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+				}, nil)
+
+				_, err := service.GetDeployedDirectorManifest()
+				Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+			})
+		})
+	})
+})
@@ -41,15 +41,19 @@ func (a Api) GetStagedProductByName(productName string) (StagedProductsFindOutpu
 		return StagedProductsFindOutput{}, err
 	}
 
-	var foundProduct StagedProduct
+	var (
+		foundProduct StagedProduct
+		found        bool
+	)
 	for _, product := range productsOutput.Products {
 		if product.Type == productName {
 			foundProduct = product
+			found = true
 			break
 		}
 	}
 
-	if (foundProduct == StagedProduct{}) {
+	if !found {
 		return StagedProductsFindOutput{}, fmt.Errorf("could not find product %q", productName)
 	}
 
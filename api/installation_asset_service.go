@@ -17,32 +17,77 @@ type ImportInstallationInput struct {
 	PollingInterval int
 }
 
-func (a Api) DownloadInstallationAssetCollection(outputFile string) error {
-	resp, err := a.sendProgressAPIRequest("GET", "/api/v0/installation_asset_collection", nil)
+// DownloadInstallationAssetCollectionOutput tells from which byte the export was resumed,
+// 0 when it was downloaded from the start.
+type DownloadInstallationAssetCollectionOutput struct {
+	ResumedFrom int64
+}
+
+// DownloadInstallationAssetCollection writes the export of the installation to the output file.
+// With resume, the bytes missing from the output file are requested, and appended to it when
+// Ops Manager returns them; otherwise the whole export is downloaded again.
+func (a Api) DownloadInstallationAssetCollection(outputFile string, resume bool) (DownloadInstallationAssetCollectionOutput, error) {
+	var offset int64
+	if resume {
+		if info, err := os.Stat(outputFile); err == nil {
+			offset = info.Size()
+		}
+	}
+
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := sendRequestWithHeader(a.progressClient, "GET", "/api/v0/installation_asset_collection", nil, header)
 	if err != nil {
-		return errors.Wrap(err, "could not make api request to installation_asset_collection endpoint")
+		return DownloadInstallationAssetCollectionOutput{}, errors.Wrap(err, "could not make api request to installation_asset_collection endpoint")
 	}
 	defer resp.Body.Close()
 
-	if err = validateStatusOK(resp); err != nil {
-		return err
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 && resp.StatusCode == http.StatusPartialContent {
+		// the bytes can only be appended when they start where the
+		// output file ends, the whole export is downloaded again otherwise
+		if !rangeStartsAt(resp.Header.Get("Content-Range"), offset) {
+			resp.Body.Close()
+			return a.DownloadInstallationAssetCollection(outputFile, false)
+		}
+		flags = os.O_WRONLY | os.O_APPEND
+	} else {
+		offset = 0
+		if err = validateStatusOK(resp); err != nil {
+			return DownloadInstallationAssetCollectionOutput{}, err
+		}
 	}
 
-	outputFileHandle, err := os.Create(outputFile)
+	outputFileHandle, err := os.OpenFile(outputFile, flags, 0644)
 	if err != nil {
-		return errors.Wrap(err, "cannot create output file")
+		return DownloadInstallationAssetCollectionOutput{}, errors.Wrap(err, "cannot create output file")
 	}
+	defer outputFileHandle.Close()
 
 	bytesWritten, err := io.Copy(outputFileHandle, resp.Body)
 	if err != nil {
-		return errors.Wrap(err, "cannot write output file")
+		return DownloadInstallationAssetCollectionOutput{}, errors.Wrap(err, "cannot write output file")
 	}
 
 	if bytesWritten != resp.ContentLength {
-		return fmt.Errorf("invalid response length (expected %d, got %d)", resp.ContentLength, bytesWritten)
+		return DownloadInstallationAssetCollectionOutput{}, fmt.Errorf("invalid response length (expected %d, got %d)", resp.ContentLength, bytesWritten)
 	}
 
-	return nil
+	return DownloadInstallationAssetCollectionOutput{ResumedFrom: offset}, nil
+}
+
+// rangeStartsAt is whether the Content-Range of a partial response,
+// e.g. "bytes 5-16/17", starts at the offset.
+func rangeStartsAt(contentRange string, offset int64) bool {
+	var start int64
+	if _, err := fmt.Sscanf(contentRange, "bytes %d-", &start); err != nil {
+		return false
+	}
+
+	return start == offset
 }
 
 func (a Api) UploadInstallationAssetCollection(input ImportInstallationInput) error {
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -55,8 +56,9 @@ var _ = Describe("InstallationAssetService", func() {
 				Body:          ioutil.NopCloser(strings.NewReader("some-installation")),
 			}, nil)
 
-			err := service.DownloadInstallationAssetCollection(outputFile.Name())
+			output, err := service.DownloadInstallationAssetCollection(outputFile.Name(), false)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(output.ResumedFrom).To(BeZero())
 
 			By("posting to the installation_asset_collection endpoint")
 			request := progressClient.DoArgsForCall(0)
@@ -69,12 +71,96 @@ var _ = Describe("InstallationAssetService", func() {
 			Expect(string(ins)).To(Equal("some-installation"))
 		})
 
+		Context("when resuming", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(outputFile.Name(), []byte("some-"), 0644)).To(Succeed())
+			})
+
+			It("appends the missing bytes when Ops Manager returns them", func() {
+				progressClient.DoReturns(&http.Response{
+					StatusCode:    http.StatusPartialContent,
+					Header:        http.Header{"Content-Range": []string{"bytes 5-16/17"}},
+					ContentLength: int64(len("installation")),
+					Body:          ioutil.NopCloser(strings.NewReader("installation")),
+				}, nil)
+
+				output, err := service.DownloadInstallationAssetCollection(outputFile.Name(), true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output.ResumedFrom).To(Equal(int64(5)))
+
+				request := progressClient.DoArgsForCall(0)
+				Expect(request.Header.Get("Range")).To(Equal("bytes=5-"))
+
+				ins, err := ioutil.ReadFile(outputFile.Name())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(ins)).To(Equal("some-installation"))
+			})
+
+			DescribeTable("downloads the whole export again when the returned bytes do not start at the end of the file", func(contentRange string) {
+				progressClient.DoReturnsOnCall(0, &http.Response{
+					StatusCode:    http.StatusPartialContent,
+					Header:        http.Header{"Content-Range": []string{contentRange}},
+					ContentLength: int64(len("stallation")),
+					Body:          ioutil.NopCloser(strings.NewReader("stallation")),
+				}, nil)
+				progressClient.DoReturnsOnCall(1, &http.Response{
+					StatusCode:    http.StatusOK,
+					ContentLength: int64(len("some-installation")),
+					Body:          ioutil.NopCloser(strings.NewReader("some-installation")),
+				}, nil)
+
+				output, err := service.DownloadInstallationAssetCollection(outputFile.Name(), true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output.ResumedFrom).To(BeZero())
+
+				Expect(progressClient.DoCallCount()).To(Equal(2))
+				Expect(progressClient.DoArgsForCall(1).Header.Get("Range")).To(BeEmpty())
+
+				ins, err := ioutil.ReadFile(outputFile.Name())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(ins)).To(Equal("some-installation"))
+			},
+				Entry("from another offset", "bytes 7-16/17"),
+				Entry("without a range", ""),
+			)
+
+			It("downloads the whole export again when Ops Manager cannot resume it", func() {
+				progressClient.DoReturns(&http.Response{
+					StatusCode:    http.StatusOK,
+					ContentLength: int64(len("some-installation")),
+					Body:          ioutil.NopCloser(strings.NewReader("some-installation")),
+				}, nil)
+
+				output, err := service.DownloadInstallationAssetCollection(outputFile.Name(), true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output.ResumedFrom).To(BeZero())
+
+				ins, err := ioutil.ReadFile(outputFile.Name())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(ins)).To(Equal("some-installation"))
+			})
+
+			It("does not request a range without a resume", func() {
+				progressClient.DoReturns(&http.Response{
+					StatusCode:    http.StatusOK,
+					ContentLength: int64(len("some-installation")),
+					Body:          ioutil.NopCloser(strings.NewReader("some-installation")),
+				}, nil)
+
+				_, err := service.DownloadInstallationAssetCollection(outputFile.Name(), false)
+				Expect(err).NotTo(HaveOccurred())
+
+				request := progressClient.DoArgsForCall(0)
+				Expect(request.Header.Get("Range")).To(BeEmpty())
+			})
+		})
+
 		Context("when an error occurs", func() {
 			Context("when the client errors before the request", func() {
 				It("returns an error", func() {
 					progressClient.DoReturns(&http.Response{}, errors.New("some client error"))
 
-					err := service.DownloadInstallationAssetCollection("fake-file")
+					_, err := service.DownloadInstallationAssetCollection("fake-file", false)
 					Expect(err).To(MatchError("could not make api request to installation_asset_collection endpoint: could not send api request to GET /api/v0/installation_asset_collection: some client error"))
 				})
 			})
@@ -86,7 +172,7 @@ var _ = Describe("InstallationAssetService", func() {
 						Body:       ioutil.NopCloser(strings.NewReader("")),
 					}, nil)
 
-					err := service.DownloadInstallationAssetCollection("fake-file")
+					_, err := service.DownloadInstallationAssetCollection("fake-file", false)
 					Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
 				})
 			})
@@ -98,7 +184,7 @@ var _ = Describe("InstallationAssetService", func() {
 						Body:       ioutil.NopCloser(strings.NewReader("{}")),
 					}, nil)
 
-					err := service.DownloadInstallationAssetCollection("fake-dir/fake-file")
+					_, err := service.DownloadInstallationAssetCollection("fake-dir/fake-file", false)
 					Expect(err).To(MatchError(ContainSubstring("no such file")))
 				})
 			})
@@ -111,7 +197,7 @@ var _ = Describe("InstallationAssetService", func() {
 						ContentLength: 50,
 					}, nil)
 
-					err := service.DownloadInstallationAssetCollection(outputFile.Name())
+					_, err := service.DownloadInstallationAssetCollection(outputFile.Name(), false)
 					Expect(err).To(MatchError(ContainSubstring("invalid response length")))
 				})
 			})
@@ -45,9 +45,11 @@ func (a Api) fetchProductGUID() (map[string]string, error) {
 
 	for _, stagedProduct := range sp.Products {
 		productGuidMapping[stagedProduct.Type] = stagedProduct.GUID
+		productGuidMapping[stagedProduct.GUID] = stagedProduct.GUID
 	}
 	for _, deployedProduct := range dp {
 		productGuidMapping[deployedProduct.Type] = deployedProduct.GUID
+		productGuidMapping[deployedProduct.GUID] = deployedProduct.GUID
 	}
 
 	return productGuidMapping, nil
@@ -87,9 +89,11 @@ func (a Api) CreateInstallation(ignoreWarnings bool, deployProducts bool, produc
 	} else if len(productNames) > 0 {
 		var productGUIDs []string
 		for _, productName := range productNames {
-			if productGUID, ok := productGuidMapping[productName]; ok {
-				productGUIDs = append(productGUIDs, productGUID)
+			productGUID, ok := productGuidMapping[productName]
+			if !ok {
+				return InstallationsServiceOutput{}, fmt.Errorf("failed to fetch product GUID for product: %s", productName)
 			}
+			productGUIDs = append(productGUIDs, productGUID)
 		}
 		deployProductsVal = productGUIDs
 	}
@@ -195,6 +195,56 @@ var _ = Describe("InstallationsService", func() {
 			})
 		})
 
+		Context("When deploying products by their GUID", func() {
+			It("triggers an installation on an Ops Manager, deploying those products", func() {
+				client.DoReturnsOnCall(0, &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`[ { "guid": "guid1", "type": "product1"}, { "guid": "guid2", "type": "product2"} ]`)),
+				}, nil)
+				client.DoReturnsOnCall(1, &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`[ { "guid": "guid1", "type": "product1"}, { "guid": "guid2", "type": "product2"} ]`)),
+				}, nil)
+				client.DoReturnsOnCall(2, &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`{"install":{"id":1}}`)),
+				}, nil)
+
+				_, err := service.CreateInstallation(false, true, []string{"guid2"}, api.ApplyErrandChanges{
+					Errands: map[string]api.ProductErrand{
+						"guid1": {
+							RunPostDeploy: map[string]interface{}{
+								"errand1": false,
+							},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				body, err := ioutil.ReadAll(client.DoArgsForCall(2).Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(body)).To(MatchJSON(`{"ignore_warnings":"false","deploy_products":["guid2"],"errands":{"guid1":{"run_post_deploy":{"errand1":false}}}}`))
+			})
+		})
+
+		Context("When deploying a product that is not staged", func() {
+			It("returns an error instead of triggering an installation", func() {
+				client.DoReturnsOnCall(0, &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`[ { "guid": "guid1", "type": "product1"}, { "guid": "guid2", "type": "product2"} ]`)),
+				}, nil)
+				client.DoReturnsOnCall(1, &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(`[ { "guid": "guid1", "type": "product1"}, { "guid": "guid2", "type": "product2"} ]`)),
+				}, nil)
+
+				_, err := service.CreateInstallation(false, true, []string{"product2", "product3"}, api.ApplyErrandChanges{})
+				Expect(err).To(MatchError("failed to fetch product GUID for product: product3"))
+
+				Expect(client.DoCallCount()).To(Equal(2))
+			})
+		})
+
 		Context("when given the errands", func() {
 			It("sends the errands as a json parameter", func() {
 				client.DoReturnsOnCall(0, &http.Response{
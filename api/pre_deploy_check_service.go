@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+type PreDeployCheck struct {
+	Identifier       string                    `json:"identifier"`
+	Complete         bool                      `json:"complete"`
+	Network          PreDeployNetwork          `json:"network"`
+	AvailabilityZone PreDeployAvailabilityZone `json:"availability_zone"`
+	Stemcells        []PreDeployStemcell       `json:"stemcells"`
+	Properties       []PreDeployProperty       `json:"properties"`
+	Resources        PreDeployResources        `json:"resources"`
+	Verifiers        []PreDeployVerifier       `json:"verifiers"`
+}
+
+type PreDeployNetwork struct {
+	Assigned bool `json:"assigned"`
+}
+
+type PreDeployAvailabilityZone struct {
+	Assigned bool `json:"assigned"`
+}
+
+type PreDeployStemcell struct {
+	Assigned                bool   `json:"assigned"`
+	RequiredStemcellVersion string `json:"required_stemcell_version"`
+	RequiredStemcellOS      string `json:"required_stemcell_os"`
+}
+
+type PreDeployProperty struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Errors []string `json:"errors"`
+}
+
+type PreDeployResources struct {
+	Jobs []PreDeployJob `json:"jobs"`
+}
+
+type PreDeployJob struct {
+	Identifier string   `json:"identifier"`
+	GUID       string   `json:"guid"`
+	Errors     []string `json:"error"`
+}
+
+type PreDeployVerifier struct {
+	Type      string   `json:"type"`
+	Errors    []string `json:"errors"`
+	Ignorable bool     `json:"ignorable"`
+}
+
+func (a Api) GetDirectorPreDeployCheck() (PreDeployCheck, error) {
+	return a.getPreDeployCheck("/api/v0/staged/director/pre_deploy_check")
+}
+
+func (a Api) GetProductPreDeployCheck(productGUID string) (PreDeployCheck, error) {
+	return a.getPreDeployCheck(fmt.Sprintf("/api/v0/staged/products/%s/pre_deploy_check", productGUID))
+}
+
+func (a Api) getPreDeployCheck(endpoint string) (PreDeployCheck, error) {
+	resp, err := a.sendAPIRequest("GET", endpoint, nil)
+	if err != nil {
+		return PreDeployCheck{}, errors.Wrap(err, "could not make api request to pre_deploy_check endpoint")
+	}
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return PreDeployCheck{}, err
+	}
+
+	var output struct {
+		PreDeployCheck PreDeployCheck `json:"pre_deploy_check"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&output); err != nil {
+		return PreDeployCheck{}, errors.Wrap(err, "could not unmarshal pre_deploy_check response")
+	}
+
+	return output.PreDeployCheck, nil
+}
@@ -0,0 +1,160 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/api/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreDeployCheckService", func() {
+	var (
+		client  *fakes.HttpClient
+		service api.Api
+	)
+
+	BeforeEach(func() {
+		client = &fakes.HttpClient{}
+
+		service = api.New(api.ApiInput{
+			Client: client,
+		})
+	})
+
+	Describe("GetDirectorPreDeployCheck", func() {
+		It("returns the pre-deploy check of the director", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"pre_deploy_check": {
+						"identifier": "p-bosh-guid",
+						"complete": false,
+						"network": {"assigned": false},
+						"availability_zone": {"assigned": true},
+						"stemcells": [{
+							"assigned": false,
+							"required_stemcell_version": "250.17",
+							"required_stemcell_os": "ubuntu-xenial"
+						}],
+						"properties": [{
+							"name": ".properties.iaas_configuration",
+							"type": null,
+							"errors": ["can't be blank"]
+						}],
+						"resources": {
+							"jobs": [{
+								"identifier": "director",
+								"guid": "director-guid",
+								"error": ["Instance type is invalid"]
+							}]
+						},
+						"verifiers": [{
+							"type": "NetworksExistenceVerifier",
+							"errors": ["network does not exist"],
+							"ignorable": true
+						}]
+					}
+				}`)),
+			}, nil)
+
+			check, err := service.GetDirectorPreDeployCheck()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(check).To(Equal(api.PreDeployCheck{
+				Identifier:       "p-bosh-guid",
+				Complete:         false,
+				Network:          api.PreDeployNetwork{Assigned: false},
+				AvailabilityZone: api.PreDeployAvailabilityZone{Assigned: true},
+				Stemcells: []api.PreDeployStemcell{
+					{Assigned: false, RequiredStemcellVersion: "250.17", RequiredStemcellOS: "ubuntu-xenial"},
+				},
+				Properties: []api.PreDeployProperty{
+					{Name: ".properties.iaas_configuration", Errors: []string{"can't be blank"}},
+				},
+				Resources: api.PreDeployResources{
+					Jobs: []api.PreDeployJob{
+						{Identifier: "director", GUID: "director-guid", Errors: []string{"Instance type is invalid"}},
+					},
+				},
+				Verifiers: []api.PreDeployVerifier{
+					{Type: "NetworksExistenceVerifier", Errors: []string{"network does not exist"}, Ignorable: true},
+				},
+			}))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.Path).To(Equal("/api/v0/staged/director/pre_deploy_check"))
+		})
+	})
+
+	Describe("GetProductPreDeployCheck", func() {
+		It("returns the pre-deploy check of the product", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{
+					"pre_deploy_check": {
+						"identifier": "cf-guid",
+						"complete": true,
+						"network": {"assigned": true},
+						"availability_zone": {"assigned": true}
+					}
+				}`)),
+			}, nil)
+
+			check, err := service.GetProductPreDeployCheck("cf-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(check).To(Equal(api.PreDeployCheck{
+				Identifier:       "cf-guid",
+				Complete:         true,
+				Network:          api.PreDeployNetwork{Assigned: true},
+				AvailabilityZone: api.PreDeployAvailabilityZone{Assigned: true},
+			}))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.Path).To(Equal("/api/v0/staged/products/cf-guid/pre_deploy_check"))
+		})
+
+		Context("failure cases", func() {
+			Context("when the client can't connect to the server", func() {
+				It("returns an error", func() {
+					client.DoReturns(&http.Response{}, errors.New("some error"))
+
+					_, err := service.GetProductPreDeployCheck("cf-guid")
+					Expect(err).To(MatchError(ContainSubstring("could not make api request to pre_deploy_check endpoint")))
+				})
+			})
+
+			Context("when the server returns a non-200 status code", func() {
+				It("returns an error", func() {
+					client.DoReturns(&http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+					}, nil)
+
+					_, err := service.GetProductPreDeployCheck("cf-guid")
+					Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+				})
+			})
+
+			Context("when the response is not JSON", func() {
+				It("returns an error", func() {
+					client.DoReturns(&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader(`%%%`)),
+					}, nil)
+
+					_, err := service.GetProductPreDeployCheck("cf-guid")
+					Expect(err).To(MatchError(ContainSubstring("could not unmarshal pre_deploy_check response")))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// RevertStagedChanges discards every staged change that has not been applied yet.
+// It returns false when there was nothing to revert.
+func (a Api) RevertStagedChanges() (bool, error) {
+	resp, err := a.sendAPIRequest("DELETE", "/api/v0/staged", nil)
+	if err != nil {
+		return false, errors.Wrap(err, "could not make api request to staged endpoint")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return false, nil
+	}
+
+	if err = validateStatusOK(resp); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
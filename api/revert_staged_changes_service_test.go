@@ -0,0 +1,78 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/api/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RevertStagedChanges", func() {
+	var (
+		client  *fakes.HttpClient
+		service api.Api
+	)
+
+	BeforeEach(func() {
+		client = &fakes.HttpClient{}
+
+		service = api.New(api.ApiInput{
+			Client: client,
+		})
+	})
+
+	It("reverts the staged changes", func() {
+		client.DoReturns(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+		}, nil)
+
+		reverted, err := service.RevertStagedChanges()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reverted).To(BeTrue())
+
+		request := client.DoArgsForCall(0)
+		Expect(request.Method).To(Equal("DELETE"))
+		Expect(request.URL.Path).To(Equal("/api/v0/staged"))
+	})
+
+	It("does not revert anything when there are no staged changes", func() {
+		client.DoReturns(&http.Response{
+			StatusCode: http.StatusNotModified,
+			Body:       ioutil.NopCloser(strings.NewReader(``)),
+		}, nil)
+
+		reverted, err := service.RevertStagedChanges()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reverted).To(BeFalse())
+	})
+
+	Context("failure cases", func() {
+		Context("when the client can't connect to the server", func() {
+			It("returns an error", func() {
+				client.DoReturns(&http.Response{}, errors.New("some error"))
+
+				_, err := service.RevertStagedChanges()
+				Expect(err).To(MatchError(ContainSubstring("could not make api request to staged endpoint")))
+			})
+		})
+
+		Context("when the server returns a non-200 status code", func() {
+			It("returns an error", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+				}, nil)
+
+				_, err := service.RevertStagedChanges()
+				Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+			})
+		})
+	})
+})
@@ -21,11 +21,18 @@ func (a Api) sendUnauthedAPIRequest(method, endpoint string, jsonData []byte) (*
 }
 
 func sendRequest(client httpClient, method, endpoint string, jsonData []byte) (*http.Response, error) {
+	return sendRequestWithHeader(client, method, endpoint, jsonData, http.Header{})
+}
+
+func sendRequestWithHeader(client httpClient, method, endpoint string, jsonData []byte, header http.Header) (*http.Response, error) {
 	req, err := http.NewRequest(method, endpoint, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("could not create api request %s %s", method, endpoint))
 	}
 
+	for key, values := range header {
+		req.Header[key] = values
+	}
 	req.Header.Add("Content-Type", "application/json")
 
 	resp, err := client.Do(req)
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+type BannerSettings struct {
+	UIBannerContents  string `yaml:"ui_banner_contents"  json:"ui_banner_contents"`
+	SSHBannerContents string `yaml:"ssh_banner_contents" json:"ssh_banner_contents"`
+}
+
+type RBACSettings struct {
+	LDAPAdminGroupName  string `yaml:"ldap_rbac_admin_group_name" json:"ldap_rbac_admin_group_name,omitempty"`
+	SAMLAdminGroup      string `yaml:"rbac_saml_admin_group"      json:"rbac_saml_admin_group,omitempty"`
+	SAMLGroupsAttribute string `yaml:"rbac_saml_groups_attribute" json:"rbac_saml_groups_attribute,omitempty"`
+}
+
+type TokensExpiration struct {
+	AccessTokenExpiration  int `yaml:"access_token_expiration"  json:"access_token_expiration,omitempty"`
+	RefreshTokenExpiration int `yaml:"refresh_token_expiration" json:"refresh_token_expiration,omitempty"`
+	SessionIdleTimeout     int `yaml:"session_idle_timeout"     json:"session_idle_timeout,omitempty"`
+}
+
+type SyslogSettings struct {
+	Enabled                    bool   `yaml:"enabled"                      json:"enabled"`
+	Address                    string `yaml:"address"                      json:"address,omitempty"`
+	Port                       int    `yaml:"port"                         json:"port,omitempty"`
+	TransportProtocol          string `yaml:"transport_protocol"           json:"transport_protocol,omitempty"`
+	TLSEnabled                 bool   `yaml:"tls_enabled"                  json:"tls_enabled"`
+	PermittedPeer              string `yaml:"permitted_peer"               json:"permitted_peer,omitempty"`
+	SSLCACertificate           string `yaml:"ssl_ca_certificate"           json:"ssl_ca_certificate,omitempty"`
+	QueueSize                  int    `yaml:"queue_size"                   json:"queue_size,omitempty"`
+	ForwardDebugLogs           bool   `yaml:"forward_debug_logs"           json:"forward_debug_logs"`
+	CustomRsyslogConfiguration string `yaml:"custom_rsyslog_configuration" json:"custom_rsyslog_configuration,omitempty"`
+}
+
+// UpdateBanner sets the banners of the Ops Manager UI and of SSH sessions to the Ops Manager VM.
+func (a Api) UpdateBanner(settings BannerSettings) error {
+	return a.updateSettings("/api/v0/settings/banner", settings)
+}
+
+// EnableRBAC enables role-based access control for the users of the LDAP
+// admin group, or of the SAML admin group, depending on the authentication of the Ops Manager.
+func (a Api) EnableRBAC(settings RBACSettings) error {
+	return a.updateSettings("/api/v0/settings/rbac", settings)
+}
+
+// UpdateTokensExpiration sets the lifetimes, in seconds, of the UAA tokens and sessions of the Ops Manager.
+func (a Api) UpdateTokensExpiration(settings TokensExpiration) error {
+	return a.updateSettings("/api/v0/uaa/tokens_expiration", map[string]TokensExpiration{
+		"tokens_expiration": settings,
+	})
+}
+
+// UpdateSyslogSettings sets where the Ops Manager VM forwards its own logs to.
+func (a Api) UpdateSyslogSettings(settings SyslogSettings) error {
+	return a.updateSettings("/api/v0/settings/syslog", map[string]SyslogSettings{
+		"syslog": settings,
+	})
+}
+
+func (a Api) updateSettings(endpoint string, settings interface{}) error {
+	jsonData, err := json.Marshal(settings)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal json") // un-tested
+	}
+
+	resp, err := a.sendAPIRequest("PUT", endpoint, jsonData)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return fmt.Errorf("could not update the settings at %s: %s", endpoint, err)
+	}
+
+	return nil
+}
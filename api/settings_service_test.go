@@ -0,0 +1,137 @@
+package api_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/api/fakes"
+)
+
+var _ = Describe("Settings", func() {
+	var (
+		client  *fakes.HttpClient
+		service api.Api
+	)
+
+	BeforeEach(func() {
+		client = &fakes.HttpClient{}
+		service = api.New(api.ApiInput{
+			Client: client,
+		})
+		client.DoStub = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{}`))}, nil
+		}
+	})
+
+	requestBody := func(req *http.Request) string {
+		body, err := ioutil.ReadAll(req.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	It("updates the banners", func() {
+		err := service.UpdateBanner(api.BannerSettings{
+			UIBannerContents:  "some-ui-banner",
+			SSHBannerContents: "some-ssh-banner",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(client.DoCallCount()).To(Equal(1))
+		req := client.DoArgsForCall(0)
+
+		Expect(req.Method).To(Equal("PUT"))
+		Expect(req.URL.Path).To(Equal("/api/v0/settings/banner"))
+		Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(requestBody(req)).To(MatchJSON(`{
+			"ui_banner_contents": "some-ui-banner",
+			"ssh_banner_contents": "some-ssh-banner"
+		}`))
+	})
+
+	It("enables role-based access control", func() {
+		err := service.EnableRBAC(api.RBACSettings{
+			SAMLAdminGroup:      "opsman-admins",
+			SAMLGroupsAttribute: "groups",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		req := client.DoArgsForCall(0)
+		Expect(req.Method).To(Equal("PUT"))
+		Expect(req.URL.Path).To(Equal("/api/v0/settings/rbac"))
+		Expect(requestBody(req)).To(MatchJSON(`{
+			"rbac_saml_admin_group": "opsman-admins",
+			"rbac_saml_groups_attribute": "groups"
+		}`))
+	})
+
+	It("updates the expiration of the tokens", func() {
+		err := service.UpdateTokensExpiration(api.TokensExpiration{
+			AccessTokenExpiration:  3600,
+			RefreshTokenExpiration: 7200,
+			SessionIdleTimeout:     1800,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		req := client.DoArgsForCall(0)
+		Expect(req.Method).To(Equal("PUT"))
+		Expect(req.URL.Path).To(Equal("/api/v0/uaa/tokens_expiration"))
+		Expect(requestBody(req)).To(MatchJSON(`{
+			"tokens_expiration": {
+				"access_token_expiration": 3600,
+				"refresh_token_expiration": 7200,
+				"session_idle_timeout": 1800
+			}
+		}`))
+	})
+
+	It("updates the syslog settings", func() {
+		err := service.UpdateSyslogSettings(api.SyslogSettings{
+			Enabled:           true,
+			Address:           "logs.example.com",
+			Port:              514,
+			TransportProtocol: "tcp",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		req := client.DoArgsForCall(0)
+		Expect(req.Method).To(Equal("PUT"))
+		Expect(req.URL.Path).To(Equal("/api/v0/settings/syslog"))
+		Expect(requestBody(req)).To(MatchJSON(`{
+			"syslog": {
+				"enabled": true,
+				"address": "logs.example.com",
+				"port": 514,
+				"transport_protocol": "tcp",
+				"tls_enabled": false,
+				"forward_debug_logs": false
+			}
+		}`))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the request fails", func() {
+			client.DoReturns(nil, errors.New("some-error"))
+
+			err := service.UpdateBanner(api.BannerSettings{})
+			Expect(err).To(MatchError(ContainSubstring("some-error")))
+		})
+
+		It("returns an error when the settings are not updated", func() {
+			client.DoStub = func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusUnprocessableEntity,
+					Body:       ioutil.NopCloser(strings.NewReader(`{"errors": ["invalid"]}`))}, nil
+			}
+
+			err := service.UpdateSyslogSettings(api.SyslogSettings{})
+			Expect(err).To(MatchError(ContainSubstring("could not update the settings at /api/v0/settings/syslog: request failed: unexpected response")))
+		})
+	})
+})
@@ -20,8 +20,9 @@ type StagedProductsOutput struct {
 }
 
 type StagedProduct struct {
-	GUID string
-	Type string
+	GUID            string
+	Type            string
+	LicenseMetadata []LicenseMetadata `json:"license_metadata,omitempty"`
 }
 
 type UnstageProductInput struct {
@@ -16,6 +16,7 @@ type ProductStemcell struct {
 	StagedForDeletion       bool     `json:"is_staged_for_deletion,omitempty"`
 	StagedStemcellVersion   string   `json:"staged_stemcell_version,omitempty"`
 	RequiredStemcellVersion string   `json:"required_stemcell_version,omitempty"`
+	RequiredStemcellOS      string   `json:"required_stemcell_os,omitempty"`
 	AvailableVersions       []string `json:"available_stemcell_versions,omitempty"`
 }
 
@@ -56,3 +57,58 @@ func (a Api) AssignStemcell(input ProductStemcells) error {
 
 	return nil
 }
+
+type ProductMultiStemcells struct {
+	Products []ProductMultiStemcell `json:"products"`
+}
+
+type ProductMultiStemcell struct {
+	GUID               string           `json:"guid,omitempty"`
+	ProductName        string           `json:"identifier,omitempty"`
+	StagedForDeletion  bool             `json:"is_staged_for_deletion,omitempty"`
+	StagedStemcells    []StemcellObject `json:"staged_stemcells"`
+	AvailableStemcells []StemcellObject `json:"available_stemcells,omitempty"`
+}
+
+type StemcellObject struct {
+	OS      string `json:"os"`
+	Version string `json:"version"`
+}
+
+func (a Api) ListMultiStemcells() (ProductMultiStemcells, error) {
+	resp, err := a.sendAPIRequest("GET", "/api/v0/stemcell_associations", nil)
+	if err != nil {
+		return ProductMultiStemcells{}, errors.Wrap(err, "could not make api request to list stemcell associations")
+	}
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return ProductMultiStemcells{}, err
+	}
+
+	var productStemcells ProductMultiStemcells
+	err = json.NewDecoder(resp.Body).Decode(&productStemcells)
+	if err != nil {
+		return ProductMultiStemcells{}, errors.Wrap(err, "could not unmarshal stemcell associations response")
+	}
+
+	return productStemcells, nil
+}
+
+func (a Api) AssignMultiStemcell(input ProductMultiStemcells) error {
+	jsonData, err := json.Marshal(&input)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal json")
+	}
+
+	resp, err := a.sendAPIRequest("PATCH", "/api/v0/stemcell_associations", jsonData)
+	if err != nil {
+		return err
+	}
+
+	if err = validateStatusOK(resp); err != nil {
+		return err
+	}
+
+	return nil
+}
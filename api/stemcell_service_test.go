@@ -36,6 +36,7 @@ var _ = Describe("StemcellService", func() {
                       "guid": "some-guid",
                       "staged_stemcell_version": "1234.5",
                       "identifier": "some-product",
+                      "required_stemcell_os": "ubuntu-xenial",
                       "available_stemcell_versions": [
                         "1234.5", "1234.6"
                       ]
@@ -53,6 +54,7 @@ var _ = Describe("StemcellService", func() {
 						StagedForDeletion:     false,
 						StagedStemcellVersion: "1234.5",
 						ProductName:           "some-product",
+						RequiredStemcellOS:    "ubuntu-xenial",
 						AvailableVersions: []string{
 							"1234.5",
 							"1234.6",
@@ -159,4 +161,172 @@ var _ = Describe("StemcellService", func() {
 			})
 		})
 	})
+
+	Describe("ListMultiStemcells", func() {
+		var (
+			fakeClient *fakes.HttpClient
+			service    api.Api
+		)
+
+		BeforeEach(func() {
+			fakeClient = &fakes.HttpClient{}
+			service = api.New(api.ApiInput{
+				Client: fakeClient,
+			})
+		})
+
+		It("makes a request to list the stemcell associations", func() {
+			fakeClient.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(strings.NewReader(`{
+                  "products": [
+                    {
+                      "guid": "some-guid",
+                      "identifier": "some-product",
+                      "staged_stemcells": [
+                        {"os": "ubuntu-xenial", "version": "250.17"}
+                      ],
+                      "available_stemcells": [
+                        {"os": "ubuntu-xenial", "version": "250.17"},
+                        {"os": "windows2019", "version": "2019.7"}
+                      ]
+                    }
+                  ]
+                }`)),
+			}, nil)
+
+			output, err := service.ListMultiStemcells()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal(api.ProductMultiStemcells{
+				Products: []api.ProductMultiStemcell{
+					{
+						GUID:        "some-guid",
+						ProductName: "some-product",
+						StagedStemcells: []api.StemcellObject{
+							{OS: "ubuntu-xenial", Version: "250.17"},
+						},
+						AvailableStemcells: []api.StemcellObject{
+							{OS: "ubuntu-xenial", Version: "250.17"},
+							{OS: "windows2019", Version: "2019.7"},
+						},
+					},
+				},
+			}))
+
+			request := fakeClient.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.Path).To(Equal("/api/v0/stemcell_associations"))
+		})
+
+		Context("when an error occurs", func() {
+			Context("when the client errors before the request", func() {
+				It("returns an error", func() {
+					fakeClient.DoReturns(&http.Response{}, errors.New("some client error"))
+
+					_, err := service.ListMultiStemcells()
+					Expect(err).To(MatchError("could not make api request to list stemcell associations: could not send api request to GET /api/v0/stemcell_associations: some client error"))
+				})
+			})
+
+			Context("when the api returns a non-200 status code", func() {
+				It("returns an error", func() {
+					fakeClient.DoReturns(&http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       ioutil.NopCloser(strings.NewReader("{}")),
+					}, nil)
+
+					_, err := service.ListMultiStemcells()
+					Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+				})
+			})
+
+			Context("when the response is not valid json", func() {
+				It("returns an error", func() {
+					fakeClient.DoReturns(&http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader("%%%")),
+					}, nil)
+
+					_, err := service.ListMultiStemcells()
+					Expect(err).To(MatchError(ContainSubstring("could not unmarshal stemcell associations response")))
+				})
+			})
+		})
+	})
+
+	Describe("AssignMultiStemcell", func() {
+		var (
+			fakeClient *fakes.HttpClient
+			service    api.Api
+			input      api.ProductMultiStemcells
+		)
+
+		BeforeEach(func() {
+			fakeClient = &fakes.HttpClient{}
+			service = api.New(api.ApiInput{
+				Client: fakeClient,
+			})
+
+			input = api.ProductMultiStemcells{
+				Products: []api.ProductMultiStemcell{
+					{
+						GUID: "some-guid",
+						StagedStemcells: []api.StemcellObject{
+							{OS: "ubuntu-xenial", Version: "250.17"},
+							{OS: "windows2019", Version: "2019.7"},
+						},
+					},
+				},
+			}
+		})
+
+		It("makes a request to assign the stemcells", func() {
+			fakeClient.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{}`))}, nil)
+
+			err := service.AssignMultiStemcell(input)
+			Expect(err).NotTo(HaveOccurred())
+
+			request := fakeClient.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("PATCH"))
+			Expect(request.URL.Path).To(Equal("/api/v0/stemcell_associations"))
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(MatchJSON(`{
+              "products": [
+                {
+                  "guid": "some-guid",
+                  "staged_stemcells": [
+                    {"os": "ubuntu-xenial", "version": "250.17"},
+                    {"os": "windows2019", "version": "2019.7"}
+                  ]
+                }
+              ]
+            }`))
+		})
+
+		Context("when an error occurs", func() {
+			Context("when the client errors before the request", func() {
+				It("returns an error", func() {
+					fakeClient.DoReturns(&http.Response{}, errors.New("some client error"))
+
+					err := service.AssignMultiStemcell(input)
+					Expect(err).To(MatchError("could not send api request to PATCH /api/v0/stemcell_associations: some client error"))
+				})
+			})
+
+			Context("when the api returns a non-200 status code", func() {
+				It("returns an error", func() {
+					fakeClient.DoReturns(&http.Response{
+						StatusCode: http.StatusInternalServerError,
+						Body:       ioutil.NopCloser(strings.NewReader("{}")),
+					}, nil)
+
+					err := service.AssignMultiStemcell(input)
+					Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+				})
+			})
+		})
+	})
 })
@@ -0,0 +1,18 @@
+package api
+
+import "fmt"
+
+// UpdateStagedDirectorSyslogConfiguration sets where the director, and the VMs it deploys, forward their logs to.
+func (a Api) UpdateStagedDirectorSyslogConfiguration(settings SyslogSettings) error {
+	return a.updateSettings("/api/v0/staged/director/properties", map[string]SyslogSettings{
+		"syslog_configuration": settings,
+	})
+}
+
+// UpdateStagedProductSyslogConfiguration sets where the VMs of the staged product forward their logs to.
+// Only the products whose tile supports syslog forwarding accept it.
+func (a Api) UpdateStagedProductSyslogConfiguration(productGUID string, settings SyslogSettings) error {
+	return a.updateSettings(fmt.Sprintf("/api/v0/staged/products/%s/syslog_configuration", productGUID), map[string]SyslogSettings{
+		"syslog_configuration": settings,
+	})
+}
@@ -0,0 +1,92 @@
+package api_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/api/fakes"
+)
+
+var _ = Describe("SyslogConfiguration", func() {
+	var (
+		client   *fakes.HttpClient
+		service  api.Api
+		settings api.SyslogSettings
+	)
+
+	BeforeEach(func() {
+		client = &fakes.HttpClient{}
+		service = api.New(api.ApiInput{
+			Client: client,
+		})
+		client.DoStub = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{}`))}, nil
+		}
+
+		settings = api.SyslogSettings{
+			Enabled:           true,
+			Address:           "logs.example.com",
+			Port:              6514,
+			TransportProtocol: "tcp",
+			TLSEnabled:        true,
+			PermittedPeer:     "*.example.com",
+			SSLCACertificate:  "some-ca",
+		}
+	})
+
+	expectedBody := `{
+		"syslog_configuration": {
+			"enabled": true,
+			"address": "logs.example.com",
+			"port": 6514,
+			"transport_protocol": "tcp",
+			"tls_enabled": true,
+			"permitted_peer": "*.example.com",
+			"ssl_ca_certificate": "some-ca",
+			"forward_debug_logs": false
+		}
+	}`
+
+	It("updates the syslog configuration of the director", func() {
+		err := service.UpdateStagedDirectorSyslogConfiguration(settings)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := client.DoArgsForCall(0)
+		Expect(req.Method).To(Equal("PUT"))
+		Expect(req.URL.Path).To(Equal("/api/v0/staged/director/properties"))
+
+		body, err := ioutil.ReadAll(req.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(MatchJSON(expectedBody))
+	})
+
+	It("updates the syslog configuration of a staged product", func() {
+		err := service.UpdateStagedProductSyslogConfiguration("some-product-guid", settings)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := client.DoArgsForCall(0)
+		Expect(req.Method).To(Equal("PUT"))
+		Expect(req.URL.Path).To(Equal("/api/v0/staged/products/some-product-guid/syslog_configuration"))
+
+		body, err := ioutil.ReadAll(req.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(MatchJSON(expectedBody))
+	})
+
+	It("returns an error when the syslog configuration is not updated", func() {
+		client.DoStub = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ioutil.NopCloser(strings.NewReader(`{}`))}, nil
+		}
+
+		err := service.UpdateStagedProductSyslogConfiguration("some-product-guid", settings)
+		Expect(err).To(MatchError(ContainSubstring("could not update the settings at /api/v0/staged/products/some-product-guid/syslog_configuration: request failed: unexpected response")))
+	})
+})
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+type VerifiersOutput struct {
+	Verifiers []Verifier `json:"verifiers"`
+}
+
+type Verifier struct {
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+const directorVerifiersEndpoint = "/api/v0/staged/director/verifiers/install_time"
+
+// ListDirectorVerifiers lists the verifiers run against the director before an installation.
+func (a Api) ListDirectorVerifiers() ([]Verifier, error) {
+	return a.listVerifiers(directorVerifiersEndpoint, "director verifiers")
+}
+
+// DisableDirectorVerifiers disables the director verifiers of the given types, one at a time.
+func (a Api) DisableDirectorVerifiers(verifierTypes []string) error {
+	return a.disableVerifiers(directorVerifiersEndpoint, "director verifier", verifierTypes)
+}
+
+// ListProductVerifiers lists the verifiers run against the staged product before an installation.
+func (a Api) ListProductVerifiers(productGUID string) ([]Verifier, error) {
+	return a.listVerifiers(productVerifiersEndpoint(productGUID), "product verifiers")
+}
+
+// DisableProductVerifiers disables the verifiers of the staged product of the given types, one at a time.
+func (a Api) DisableProductVerifiers(productGUID string, verifierTypes []string) error {
+	return a.disableVerifiers(productVerifiersEndpoint(productGUID), "product verifier", verifierTypes)
+}
+
+func productVerifiersEndpoint(productGUID string) string {
+	return fmt.Sprintf("/api/v0/staged/products/%s/verifiers/install_time", productGUID)
+}
+
+func (a Api) listVerifiers(endpoint, kind string) ([]Verifier, error) {
+	resp, err := a.sendAPIRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err = validateStatusOK(resp); err != nil {
+		return nil, err
+	}
+
+	var output VerifiersOutput
+	err = json.NewDecoder(resp.Body).Decode(&output)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal the "+kind)
+	}
+
+	return output.Verifiers, nil
+}
+
+func (a Api) disableVerifiers(endpoint, kind string, verifierTypes []string) error {
+	for _, verifierType := range verifierTypes {
+		resp, err := a.sendAPIRequest("PUT", endpoint+"/"+url.PathEscape(verifierType), []byte(`{"enabled": false}`))
+		if err != nil {
+			return err
+		}
+
+		err = validateStatusOK(resp)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("could not disable the %s %s: %s", kind, verifierType, err)
+		}
+	}
+
+	return nil
+}
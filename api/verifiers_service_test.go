@@ -0,0 +1,204 @@
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/api/fakes"
+)
+
+var _ = Describe("Verifiers", func() {
+	var (
+		client  *fakes.HttpClient
+		service api.Api
+	)
+
+	BeforeEach(func() {
+		client = &fakes.HttpClient{}
+		service = api.New(api.ApiInput{
+			Client: client,
+		})
+	})
+
+	Describe("ListDirectorVerifiers", func() {
+		It("lists the director verifiers", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(bytes.NewBufferString(`{
+					"verifiers": [
+						{"type": "NetworksPingableVerifier", "enabled": true},
+						{"type": "IaasConfigurationVerifier", "enabled": false}
+					]
+				}`)),
+			}, nil)
+
+			verifiers, err := service.ListDirectorVerifiers()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(verifiers).To(Equal([]api.Verifier{
+				{Type: "NetworksPingableVerifier", Enabled: true},
+				{Type: "IaasConfigurationVerifier", Enabled: false},
+			}))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.Path).To(Equal("/api/v0/staged/director/verifiers/install_time"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the client cannot make the request", func() {
+				client.DoReturns(nil, errors.New("client do errored"))
+
+				_, err := service.ListDirectorVerifiers()
+				Expect(err).To(MatchError("could not send api request to GET /api/v0/staged/director/verifiers/install_time: client do errored"))
+			})
+
+			It("returns an error when Ops Manager returns a non-200 status code", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusInternalServerError,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+				}, nil)
+
+				_, err := service.ListDirectorVerifiers()
+				Expect(err).To(MatchError(ContainSubstring("request failed: unexpected response")))
+			})
+
+			It("returns an error when the response is not json", func() {
+				client.DoReturns(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`%%%`)),
+				}, nil)
+
+				_, err := service.ListDirectorVerifiers()
+				Expect(err).To(MatchError(ContainSubstring("could not unmarshal the director verifiers")))
+			})
+		})
+	})
+
+	Describe("DisableDirectorVerifiers", func() {
+		BeforeEach(func() {
+			client.DoStub = func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+				}, nil
+			}
+		})
+
+		It("disables every verifier", func() {
+			err := service.DisableDirectorVerifiers([]string{"NetworksPingableVerifier", "IaasConfigurationVerifier"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.DoCallCount()).To(Equal(2))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("PUT"))
+			Expect(request.URL.Path).To(Equal("/api/v0/staged/director/verifiers/install_time/NetworksPingableVerifier"))
+			Expect(request.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(MatchJSON(`{"enabled": false}`))
+
+			Expect(client.DoArgsForCall(1).URL.Path).To(Equal("/api/v0/staged/director/verifiers/install_time/IaasConfigurationVerifier"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the client cannot make the request", func() {
+				client.DoStub = nil
+				client.DoReturns(nil, errors.New("client do errored"))
+
+				err := service.DisableDirectorVerifiers([]string{"NetworksPingableVerifier"})
+				Expect(err).To(MatchError("could not send api request to PUT /api/v0/staged/director/verifiers/install_time/NetworksPingableVerifier: client do errored"))
+			})
+
+			It("stops at the first verifier that cannot be disabled", func() {
+				client.DoStub = func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+					}, nil
+				}
+
+				err := service.DisableDirectorVerifiers([]string{"UnknownVerifier", "NetworksPingableVerifier"})
+				Expect(err).To(MatchError(ContainSubstring("could not disable the director verifier UnknownVerifier: request failed: unexpected response")))
+				Expect(client.DoCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("ListProductVerifiers", func() {
+		It("lists the verifiers of the staged product", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body: ioutil.NopCloser(bytes.NewBufferString(`{
+					"verifiers": [
+						{"type": "WildcardDomainVerifier", "enabled": true}
+					]
+				}`)),
+			}, nil)
+
+			verifiers, err := service.ListProductVerifiers("some-product-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(verifiers).To(Equal([]api.Verifier{
+				{Type: "WildcardDomainVerifier", Enabled: true},
+			}))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.Path).To(Equal("/api/v0/staged/products/some-product-guid/verifiers/install_time"))
+		})
+
+		It("returns an error when the response is not json", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`%%%`)),
+			}, nil)
+
+			_, err := service.ListProductVerifiers("some-product-guid")
+			Expect(err).To(MatchError(ContainSubstring("could not unmarshal the product verifiers")))
+		})
+	})
+
+	Describe("DisableProductVerifiers", func() {
+		It("disables every verifier of the staged product", func() {
+			client.DoStub = func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+				}, nil
+			}
+
+			err := service.DisableProductVerifiers("some-product-guid", []string{"WildcardDomainVerifier", "AppSSHVerifier"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.DoCallCount()).To(Equal(2))
+
+			request := client.DoArgsForCall(0)
+			Expect(request.Method).To(Equal("PUT"))
+			Expect(request.URL.Path).To(Equal("/api/v0/staged/products/some-product-guid/verifiers/install_time/WildcardDomainVerifier"))
+
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(MatchJSON(`{"enabled": false}`))
+
+			Expect(client.DoArgsForCall(1).URL.Path).To(Equal("/api/v0/staged/products/some-product-guid/verifiers/install_time/AppSSHVerifier"))
+		})
+
+		It("returns an error when a verifier cannot be disabled", func() {
+			client.DoReturns(&http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+			}, nil)
+
+			err := service.DisableProductVerifiers("some-product-guid", []string{"UnknownVerifier"})
+			Expect(err).To(MatchError(ContainSubstring("could not disable the product verifier UnknownVerifier: request failed: unexpected response")))
+		})
+	})
+})
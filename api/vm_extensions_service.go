@@ -66,9 +66,10 @@ func (a Api) ListStagedVMExtensions() ([]VMExtension, error) {
 
 func (a Api) DeleteVMExtension(name string) error {
 	resp, err := a.sendAPIRequest("DELETE", fmt.Sprintf("/api/v0/staged/vm_extensions/%s", name), nil)
-	if err = validateStatusOK(resp); err != nil {
+	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
-	return err
+	return validateStatusOK(resp)
 }
@@ -152,5 +152,12 @@ var _ = Describe("VMExtensions", func() {
 
 			Expect(err).To(MatchError("could not send api request to PUT /api/v0/staged/vm_extensions/some-vm-extension: api endpoint failed"))
 		})
+
+		It("returns an error when the api endpoint fails for deleting vm extensions", func() {
+			client.DoReturns(nil, errors.New("api endpoint failed"))
+
+			err := service.DeleteVMExtension("some-vm-extension")
+			Expect(err).To(MatchError("could not send api request to DELETE /api/v0/staged/vm_extensions/some-vm-extension: api endpoint failed"))
+		})
 	})
 })
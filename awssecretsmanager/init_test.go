@@ -0,0 +1,13 @@
+package awssecretsmanager_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAWSSecretsManager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "awssecretsmanager")
+}
@@ -0,0 +1,126 @@
+package awssecretsmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/envvars"
+)
+
+type secretsManager interface {
+	GetSecretValue(*secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// Variables resolves variables from the secrets stored in AWS Secrets Manager,
+// the name of a secret being the prefix followed by the name of the variable.
+type Variables struct {
+	client  secretsManager
+	prefix  string
+	secrets map[string]interface{}
+}
+
+var _ boshtpl.Variables = &Variables{}
+
+// NewVariables reads the secrets with a Secrets Manager client that is
+// already configured for the region of the secrets.
+func NewVariables(client secretsManager, prefix string) *Variables {
+	return &Variables{
+		client:  client,
+		prefix:  prefix,
+		secrets: map[string]interface{}{},
+	}
+}
+
+// NewVariablesFromEnv configures Secrets Manager from the AWS_REGION (or AWS_DEFAULT_REGION)
+// and OM_VARS_STORE_PREFIX environment variables. The client authenticates with
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY when they are set, and otherwise
+// with the shared credentials file or the IAM role of the instance, like the AWS CLI.
+func NewVariablesFromEnv(environ []string) (*Variables, error) {
+	env := envvars.Map(environ)
+
+	region := env["AWS_REGION"]
+	if region == "" {
+		region = env["AWS_DEFAULT_REGION"]
+	}
+	if region == "" {
+		return nil, errors.New("AWS_REGION must be set to read variables from aws secrets manager")
+	}
+
+	config := aws.NewConfig().WithRegion(region)
+	if env["AWS_ACCESS_KEY_ID"] != "" && env["AWS_SECRET_ACCESS_KEY"] != "" {
+		config = config.WithCredentials(credentials.NewStaticCredentials(
+			env["AWS_ACCESS_KEY_ID"],
+			env["AWS_SECRET_ACCESS_KEY"],
+			env["AWS_SESSION_TOKEN"],
+		))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not configure aws secrets manager: %s", err)
+	}
+
+	return NewVariables(secretsmanager.New(sess), env["OM_VARS_STORE_PREFIX"]), nil
+}
+
+func (v *Variables) Get(definition boshtpl.VariableDefinition) (interface{}, bool, error) {
+	name := v.prefix + definition.Name
+
+	if secret, ok := v.secrets[name]; ok {
+		return secret, true, nil
+	}
+
+	output, err := v.client.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("could not read %s from aws secrets manager: %s", name, err)
+	}
+
+	var value string
+	if output.SecretString != nil {
+		value = *output.SecretString
+	} else {
+		value = string(output.SecretBinary)
+	}
+
+	secret := secretValue(value)
+	v.secrets[name] = secret
+
+	return secret, true, nil
+}
+
+// List is empty as the secrets of Secrets Manager are only read when they are referenced.
+func (v *Variables) List() ([]boshtpl.VariableDefinition, error) {
+	return nil, nil
+}
+
+// secretValue returns the keys of the secrets that are JSON objects, such as
+// the ones created from key-value pairs in the console, and the other secrets as is.
+func secretValue(value string) interface{} {
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &object); err != nil || object == nil {
+		return value
+	}
+
+	secret := map[interface{}]interface{}{}
+	for key, child := range object {
+		secret[key] = child
+	}
+
+	return secret
+}
@@ -0,0 +1,125 @@
+package awssecretsmanager_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/awssecretsmanager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Variables", func() {
+	var (
+		server    *httptest.Server
+		secretIDs []string
+		handler   func(w http.ResponseWriter, req *http.Request)
+		vars      *awssecretsmanager.Variables
+	)
+
+	BeforeEach(func() {
+		secretIDs = nil
+		handler = func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(`{"Name": "om/password", "SecretString": "some-password"}`))
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.Header.Get("X-Amz-Target")).To(Equal("secretsmanager.GetSecretValue"))
+
+			var input struct {
+				SecretId string
+			}
+			Expect(json.NewDecoder(req.Body).Decode(&input)).To(Succeed())
+			secretIDs = append(secretIDs, input.SecretId)
+
+			handler(w, req)
+		}))
+
+		sess := session.Must(session.NewSession(aws.NewConfig().
+			WithRegion("us-east-1").
+			WithEndpoint(server.URL).
+			WithMaxRetries(0).
+			WithCredentials(credentials.NewStaticCredentials("some-key-id", "some-secret-key", ""))))
+
+		vars = awssecretsmanager.NewVariables(secretsmanager.New(sess), "om/")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewVariablesFromEnv", func() {
+		It("returns an error when the region is not set", func() {
+			_, err := awssecretsmanager.NewVariablesFromEnv([]string{"OM_VARS_STORE_PREFIX=om/"})
+			Expect(err).To(MatchError("AWS_REGION must be set to read variables from aws secrets manager"))
+		})
+
+		It("accepts the default region", func() {
+			vars, err := awssecretsmanager.NewVariablesFromEnv([]string{"AWS_DEFAULT_REGION=us-west-2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).NotTo(BeNil())
+		})
+	})
+
+	Describe("Get", func() {
+		It("reads the secret named after the prefix and the variable", func() {
+			password, found, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(password).To(Equal("some-password"))
+
+			Expect(secretIDs).To(Equal([]string{"om/password"}))
+		})
+
+		It("reads every secret only once", func() {
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(secretIDs).To(HaveLen(1))
+		})
+
+		It("returns the keys of the secrets that are JSON objects", func() {
+			handler = func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(`{"Name": "om/user", "SecretString": "{\"username\": \"admin\", \"password\": \"some-password\"}"}`))
+			}
+
+			user, found, err := vars.Get(boshtpl.VariableDefinition{Name: "user"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(user).To(Equal(map[interface{}]interface{}{
+				"username": "admin",
+				"password": "some-password",
+			}))
+		})
+
+		It("does not find secrets that do not exist", func() {
+			handler = func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"__type": "ResourceNotFoundException", "message": "Secrets Manager can't find the specified secret."}`))
+			}
+
+			_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "missing"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns an error when the secret cannot be read", func() {
+			handler = func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"__type": "AccessDeniedException", "message": "not allowed"}`))
+			}
+
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).To(MatchError(ContainSubstring("could not read om/password from aws secrets manager: AccessDeniedException: not allowed")))
+		})
+	})
+})
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v2"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/telemetry"
 )
 
 type ApplyChanges struct {
@@ -18,20 +20,37 @@ type ApplyChanges struct {
 	logWriter      logWriter
 	waitDuration   time.Duration
 	Options        struct {
-		Config                string   `short:"c"   long:"config"               description:"path to yml file containing errand configuration (see docs/apply-changes/README.md for format)"`
-		IgnoreWarnings        bool     `short:"i"   long:"ignore-warnings"      description:"ignore issues reported by Ops Manager when applying changes"`
-		SkipDeployProducts    bool     `short:"sdp" long:"skip-deploy-products" description:"skip deploying products when applying changes - just update the director"`
-		SkipUnchangedProducts bool     `short:"sup" long:"skip-unchanged-products"         description:"skip deploying unchanged products - just run changed or new products --skip-unchanged-products (OM 2.2+)"`
-		ProductNames          []string `short:"n"   long:"product-name"         description:"name of the product(s) to deploy, cannot be used in conjunction with --skip-deploy-products (OM 2.2+)"`
+		Config                string        `short:"c"   long:"config"               description:"path to yml file containing errand configuration (see docs/apply-changes/README.md for format)"`
+		IgnoreWarnings        bool          `short:"i"   long:"ignore-warnings"      description:"ignore issues reported by Ops Manager when applying changes"`
+		SkipDeployProducts    bool          `short:"sdp" long:"skip-deploy-products" description:"skip deploying products when applying changes - just update the director"`
+		SkipUnchangedProducts bool          `short:"sup" long:"skip-unchanged-products"         description:"skip deploying unchanged products - just run changed or new products --skip-unchanged-products (OM 2.2+)"`
+		ProductNames          []string      `short:"n"   long:"product-name"         description:"name of the product(s) to deploy, cannot be used in conjunction with --skip-deploy-products (OM 2.2+)"`
+		Errands               []string      `            long:"errand"               description:"override a post-deploy errand for this installation only, in the form <product-name>:<errand-name>:<default|true|false|when-changed>"`
+		SkipErrands           bool          `            long:"skip-errands"         description:"disable all post-deploy errands for this installation only"`
+		Reattach              bool          `            long:"reattach"             description:"never trigger a new installation: re-attach to the running installation, or report the result of the most recent one"`
+		RecreateVMs           string        `            long:"recreate-vms"         description:"recreate VMs during this installation (options: all, director, products). products only recreates the VMs of the products being deployed, see --product-name"`
+		LogsProducts          []string      `            long:"logs-product"         description:"only print the installation logs of the given product(s), by product name or GUID"`
+		LogsSkipDirector      bool          `            long:"logs-skip-director"   description:"do not print the installation logs that are not part of a product deployment, such as deploying the director or uploading stemcells"`
+		LogsPrefix            bool          `            long:"logs-prefix"          description:"prefix every installation log line with the name of the product it belongs to"`
+		PollInterval          time.Duration `            long:"poll-interval"        description:"how long to wait between installation status checks, e.g. 30s (default: 10s)"`
+		Timeout               time.Duration `            long:"timeout"              description:"stop waiting for the installation after this long, e.g. 2h, leaving it running. om exits with code 2 when the timeout is reached"`
+		NotifyURL             string        `            long:"notify-url"           description:"url to POST a JSON summary of the installation to when apply-changes finishes, e.g. a webhook of the on-call tooling"`
+		NotifyFormat          string        `            long:"notify-format"        description:"payload posted to --notify-url (options: json,slack)" default:"json"`
 	}
 }
 
+// ErrInstallationTimedOut is returned by apply-changes when --timeout is reached.
+// The installation keeps running on the Ops Manager.
+var ErrInstallationTimedOut = errors.New("timed out waiting for the installation to finish, it is still running on the Ops Manager")
+
 //go:generate counterfeiter -o ./fakes/apply_changes_service.go --fake-name ApplyChangesService . applyChangesService
 type applyChangesService interface {
 	CreateInstallation(bool, bool, []string, api.ApplyErrandChanges) (api.InstallationsServiceOutput, error)
 	GetInstallation(id int) (api.InstallationsServiceOutput, error)
 	GetInstallationLogs(id int) (api.InstallationsServiceOutput, error)
 	Info() (api.Info, error)
+	UpdateStagedDirectorProperties(api.DirectorProperties) error
+	ListStagedProducts() (api.StagedProductsOutput, error)
 	RunningInstallation() (api.InstallationsServiceOutput, error)
 	ListInstallations() ([]api.InstallationsServiceOutput, error)
 }
@@ -52,8 +71,30 @@ func NewApplyChanges(service applyChangesService, pendingService pendingChangesS
 }
 
 func (ac ApplyChanges) Execute(args []string) error {
+	started := time.Now()
+	span := telemetry.Start("apply-changes", nil)
+	products, err := ac.execute(args)
+	span.SetAttribute("products", strings.Join(products, ","))
+	span.End(err)
+
+	notifyErr := notify(ac.Options.NotifyURL, ac.Options.NotifyFormat, newNotification("apply-changes", started, products, err))
+	if notifyErr != nil {
+		ac.logger.Printf("warning: %s", notifyErr)
+	}
+
+	return err
+}
+
+// execute applies the changes, and returns the products that are deployed.
+// They are listed from the staged products for the notification when all
+// of them are deployed, as Ops Manager is not given any to select.
+func (ac *ApplyChanges) execute(args []string) ([]string, error) {
 	if _, err := jhanda.Parse(&ac.Options, args); err != nil {
-		return fmt.Errorf("could not parse apply-changes flags: %s", err)
+		return nil, fmt.Errorf("could not parse apply-changes flags: %s", err)
+	}
+
+	if err := validateNotifyFormat(ac.Options.NotifyFormat); err != nil {
+		return nil, fmt.Errorf("could not parse apply-changes flags: %s", err)
 	}
 
 	errands := api.ApplyErrandChanges{}
@@ -61,12 +102,41 @@ func (ac ApplyChanges) Execute(args []string) error {
 	if ac.Options.Config != "" {
 		fh, err := os.Open(ac.Options.Config)
 		if err != nil {
-			return fmt.Errorf("could not load config: %s", err)
+			return nil, fmt.Errorf("could not load config: %s", err)
 		}
 		defer fh.Close()
 		err = yaml.NewDecoder(fh).Decode(&errands)
 		if err != nil {
-			return fmt.Errorf("could not parse %s: %s", ac.Options.Config, err)
+			return nil, fmt.Errorf("could not parse %s: %s", ac.Options.Config, err)
+		}
+	}
+
+	recreateVMsProperties, err := ac.recreateVMsProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	logWriter := ac.logWriter
+	var logFilter *installationLogFilter
+	if len(ac.Options.LogsProducts) > 0 || ac.Options.LogsSkipDirector || ac.Options.LogsPrefix {
+		logFilter, err = ac.newInstallationLogFilter()
+		if err != nil {
+			return nil, err
+		}
+		logWriter = logFilter
+	}
+
+	if ac.Options.SkipErrands {
+		err := ac.skipPostDeployErrands(&errands)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, errand := range ac.Options.Errands {
+		err := overridePostDeployErrand(&errands, errand)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -75,17 +145,17 @@ func (ac ApplyChanges) Execute(args []string) error {
 
 	if len(ac.Options.ProductNames) > 0 {
 		if ac.Options.SkipDeployProducts {
-			return fmt.Errorf("product-name flag can not be passed with the skip-deploy-products flag")
+			return nil, fmt.Errorf("product-name flag can not be passed with the skip-deploy-products flag")
 		}
 		if ac.Options.SkipUnchangedProducts {
-			return fmt.Errorf("product-name flag can not be passed with the skip-unchanged-products flag")
+			return nil, fmt.Errorf("product-name flag can not be passed with the skip-unchanged-products flag")
 		}
 		info, err := ac.service.Info()
 		if err != nil {
-			return fmt.Errorf("could not retrieve info from targetted ops manager: %v", err)
+			return nil, fmt.Errorf("could not retrieve info from targetted ops manager: %v", err)
 		}
 		if !info.VersionAtLeast(2, 2) {
-			return fmt.Errorf("--product-name is only available with Ops Manager 2.2 or later: you are running %s", info.Version)
+			return nil, fmt.Errorf("--product-name is only available with Ops Manager 2.2 or later: you are running %s", info.Version)
 		}
 		for _, product := range ac.Options.ProductNames {
 			changedProducts = append(changedProducts, product)
@@ -95,67 +165,237 @@ func (ac ApplyChanges) Execute(args []string) error {
 	if ac.Options.SkipUnchangedProducts {
 		s, err := ac.pendingService.ListStagedPendingChanges()
 		if err != nil {
-			return fmt.Errorf("could not check for any pending changes installation: %s", err)
+			return nil, fmt.Errorf("could not check for any pending changes installation: %s", err)
 		}
 		info, err := ac.service.Info()
 		if err != nil {
-			return fmt.Errorf("could not retrieve info from targetted ops manager: %v", err)
+			return nil, fmt.Errorf("could not retrieve info from targetted ops manager: %v", err)
 		}
 		if !info.VersionAtLeast(2, 2) {
-			return fmt.Errorf("skip-unchanged-products is only available with Ops Manager 2.2 or later: you are running %s", info.Version)
+			return nil, fmt.Errorf("skip-unchanged-products is only available with Ops Manager 2.2 or later: you are running %s", info.Version)
 		}
 		for _, p := range s.ChangeList {
 			ac.logger.Printf("Found product: %s with action of: %s", p.GUID, p.Action)
+			// the director is always deployed, it can not be selected as a product
+			if strings.HasPrefix(p.GUID, "p-bosh-") {
+				continue
+			}
 			if p.Action != "unchanged" {
 				changedProducts = append(changedProducts, p.GUID)
 				ac.logger.Printf("Adding %s to ProductNames", p.GUID)
 			}
 		}
 		if len(changedProducts) <= 0 {
+			ac.logger.Printf("no products have pending changes, only the director will be deployed")
 			deployProducts = false
 		}
 	}
 
 	installation, err := ac.service.RunningInstallation()
 	if err != nil {
-		return fmt.Errorf("could not check for any already running installation: %s", err)
+		return changedProducts, fmt.Errorf("could not check for any already running installation: %s", err)
 	}
 
-	if installation == (api.InstallationsServiceOutput{}) {
+	if installation == (api.InstallationsServiceOutput{}) && ac.Options.Reattach {
+		installations, err := ac.service.ListInstallations()
+		if err != nil {
+			return changedProducts, fmt.Errorf("could not list installations to re-attach to: %s", err)
+		}
+
+		if len(installations) == 0 {
+			return changedProducts, errors.New("could not re-attach: there are no installations on the targeted Ops Manager")
+		}
+
+		installation = installations[0]
+		ac.logger.Printf("no installation is running...re-attaching to the most recent installation (Installation ID: %d, Status: %s)", installation.ID, installation.Status)
+	} else if installation == (api.InstallationsServiceOutput{}) {
+		if recreateVMsProperties != nil {
+			ac.logger.Printf("setting VMs to be recreated (%s) on this installation", ac.Options.RecreateVMs)
+			err = ac.service.UpdateStagedDirectorProperties(recreateVMsProperties)
+			if err != nil {
+				return changedProducts, fmt.Errorf("could not set VMs to be recreated: %s", err)
+			}
+		}
+
 		ac.logger.Printf("attempting to apply changes to the targeted Ops Manager")
 		installation, err = ac.service.CreateInstallation(ac.Options.IgnoreWarnings, deployProducts, changedProducts, errands)
 		if err != nil {
-			return fmt.Errorf("installation failed to trigger: %s", err)
+			return changedProducts, fmt.Errorf("installation failed to trigger: %s", err)
 		}
 	} else {
 		startedAtFormatted := installation.StartedAt.Format(time.UnixDate)
 		ac.logger.Printf("found already running installation...re-attaching (Installation ID: %d, Started: %s)", installation.ID, startedAtFormatted)
 	}
 
+	if len(changedProducts) == 0 && deployProducts && ac.Options.NotifyURL != "" {
+		changedProducts = ac.stagedProductNames()
+	}
+
+	waitDuration := ac.waitDuration
+	if ac.Options.PollInterval > 0 {
+		waitDuration = ac.Options.PollInterval
+	}
+
+	startedWaiting := time.Now()
+
 	for {
 		current, err := ac.service.GetInstallation(installation.ID)
 		if err != nil {
-			return fmt.Errorf("installation failed to get status: %s", err)
+			return changedProducts, fmt.Errorf("installation failed to get status: %s", err)
 		}
 
 		install, err := ac.service.GetInstallationLogs(installation.ID)
 		if err != nil {
-			return fmt.Errorf("installation failed to get logs: %s", err)
+			return changedProducts, fmt.Errorf("installation failed to get logs: %s", err)
 		}
 
-		err = ac.logWriter.Flush(install.Logs)
+		finished := current.Status == api.StatusSucceeded || current.Status == api.StatusFailed
+		if finished && logFilter != nil {
+			err = logFilter.FlushAll(install.Logs)
+		} else {
+			err = logWriter.Flush(install.Logs)
+		}
 		if err != nil {
-			return fmt.Errorf("installation failed to flush logs: %s", err)
+			return changedProducts, fmt.Errorf("installation failed to flush logs: %s", err)
 		}
 
 		if current.Status == api.StatusSucceeded {
-			return nil
+			return changedProducts, nil
 		} else if current.Status == api.StatusFailed {
-			return errors.New("installation was unsuccessful")
+			return changedProducts, errors.New("installation was unsuccessful")
+		}
+
+		if ac.Options.Timeout > 0 && time.Since(startedWaiting) >= ac.Options.Timeout {
+			ac.logger.Printf("waited %s for the installation to finish (Installation ID: %d)", ac.Options.Timeout, installation.ID)
+			return changedProducts, ErrInstallationTimedOut
+		}
+
+		time.Sleep(waitDuration)
+	}
+}
+
+// recreateVMsProperties returns the director properties that make Ops Manager
+// recreate VMs on the next installation. Ops Manager resets them once it is done.
+func (ac ApplyChanges) recreateVMsProperties() (api.DirectorProperties, error) {
+	var recreate string
+	switch ac.Options.RecreateVMs {
+	case "":
+		return nil, nil
+	case "all":
+		recreate = `"bosh_recreate_on_next_deploy": true, "bosh_director_recreate_on_next_deploy": true`
+	case "director":
+		recreate = `"bosh_director_recreate_on_next_deploy": true`
+	case "products":
+		recreate = `"bosh_recreate_on_next_deploy": true`
+	default:
+		return nil, fmt.Errorf("invalid value for --recreate-vms: %s (options: all, director, products)", ac.Options.RecreateVMs)
+	}
+
+	if ac.Options.SkipDeployProducts && ac.Options.RecreateVMs != "director" {
+		return nil, fmt.Errorf("--recreate-vms %s can not be passed with the skip-deploy-products flag, use --recreate-vms director instead", ac.Options.RecreateVMs)
+	}
+
+	return api.DirectorProperties(fmt.Sprintf(`{"director_configuration": {%s}}`, recreate)), nil
+}
+
+// stagedProductNames are the names of the staged products, without the
+// director, which is not a product to deploy.
+func (ac ApplyChanges) stagedProductNames() []string {
+	stagedProducts, err := ac.service.ListStagedProducts()
+	if err != nil {
+		ac.logger.Printf("warning: could not list the staged products to notify: %s", err)
+		return []string{}
+	}
+
+	names := []string{}
+	for _, product := range stagedProducts.Products {
+		if product.Type != "p-bosh" {
+			names = append(names, product.Type)
 		}
+	}
+
+	return names
+}
+
+func (ac ApplyChanges) newInstallationLogFilter() (*installationLogFilter, error) {
+	stagedProducts, err := ac.service.ListStagedProducts()
+	if err != nil {
+		return nil, fmt.Errorf("could not list the staged products to filter the installation logs: %s", err)
+	}
 
-		time.Sleep(ac.waitDuration)
+	productNames := map[string]string{}
+	for _, product := range stagedProducts.Products {
+		productNames[product.GUID] = product.Type
 	}
+
+	products := map[string]bool{}
+	for _, product := range ac.Options.LogsProducts {
+		products[product] = true
+	}
+
+	return &installationLogFilter{
+		writer:       ac.logWriter,
+		products:     products,
+		skipDirector: ac.Options.LogsSkipDirector,
+		prefix:       ac.Options.LogsPrefix,
+		productNames: productNames,
+	}, nil
+}
+
+func (ac ApplyChanges) skipPostDeployErrands(errands *api.ApplyErrandChanges) error {
+	pendingChanges, err := ac.pendingService.ListStagedPendingChanges()
+	if err != nil {
+		return fmt.Errorf("could not list the errands of the staged products: %s", err)
+	}
+
+	for _, change := range pendingChanges.ChangeList {
+		for _, errand := range change.Errands {
+			if errand.PostDeploy == nil {
+				continue
+			}
+
+			setPostDeployErrand(errands, change.GUID, errand.Name, false)
+		}
+	}
+
+	return nil
+}
+
+func overridePostDeployErrand(errands *api.ApplyErrandChanges, errand string) error {
+	parts := strings.Split(errand, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("expected \"--errand\" to be in the form <product-name>:<errand-name>:<state>, got \"%s\"", errand)
+	}
+
+	var state interface{}
+	switch parts[2] {
+	case "true":
+		state = true
+	case "false":
+		state = false
+	case "default", "when-changed":
+		state = parts[2]
+	default:
+		return fmt.Errorf("invalid state \"%s\" for errand %s: expected one of default, true, false, when-changed", parts[2], parts[1])
+	}
+
+	setPostDeployErrand(errands, parts[0], parts[1], state)
+
+	return nil
+}
+
+func setPostDeployErrand(errands *api.ApplyErrandChanges, product, errand string, state interface{}) {
+	if errands.Errands == nil {
+		errands.Errands = map[string]api.ProductErrand{}
+	}
+
+	productErrand := errands.Errands[product]
+	if productErrand.RunPostDeploy == nil {
+		productErrand.RunPostDeploy = map[string]interface{}{}
+	}
+
+	productErrand.RunPostDeploy[errand] = state
+	errands.Errands[product] = productErrand
 }
 
 func (ac ApplyChanges) Usage() jhanda.Usage {
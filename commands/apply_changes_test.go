@@ -1,11 +1,16 @@
 package commands_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/pivotal-cf/jhanda"
@@ -14,6 +19,7 @@ import (
 	"github.com/pivotal-cf/om/commands/fakes"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -182,6 +188,22 @@ var _ = Describe("ApplyChanges", func() {
 					Expect(productList).To(ConsistOf("some-product", "some-product-2"))
 				})
 
+				It("does not select the director as a product to deploy", func() {
+					pendingService.ListStagedPendingChangesReturns(api.PendingChangesOutput{
+						ChangeList: []api.ProductChange{
+							{GUID: "p-bosh-some-guid", Action: "update"},
+							{GUID: "some-product", Action: "update"},
+						},
+					}, nil)
+
+					err := command.Execute([]string{"--skip-unchanged-products"})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, deployProducts, productList, _ := service.CreateInstallationArgsForCall(0)
+					Expect(deployProducts).To(BeTrue())
+					Expect(productList).To(ConsistOf("some-product"))
+				})
+
 				It("fails if product names were specified", func() {
 					err := command.Execute([]string{"--skip-unchanged-products", "--product-name", "product1"})
 					Expect(err).To(HaveOccurred())
@@ -218,6 +240,23 @@ var _ = Describe("ApplyChanges", func() {
 					Expect(productList).To(HaveLen(0))
 					Expect(deployProducts).To(Equal(false))
 				})
+
+				It("deploys only the director when it is the only thing changed", func() {
+					pendingService.ListStagedPendingChangesReturns(api.PendingChangesOutput{
+						ChangeList: []api.ProductChange{
+							{GUID: "p-bosh-some-guid", Action: "update"},
+							{GUID: "some-product", Action: "unchanged"},
+						},
+					}, nil)
+					command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+					err := command.Execute([]string{"--skip-unchanged-products"})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, deployProducts, productList, _ := service.CreateInstallationArgsForCall(0)
+					Expect(productList).To(BeEmpty())
+					Expect(deployProducts).To(BeFalse())
+				})
 			})
 		})
 
@@ -236,6 +275,169 @@ var _ = Describe("ApplyChanges", func() {
 			})
 		})
 
+		Context("when passed the recreate-vms flag", func() {
+			DescribeTable("sets the director to recreate VMs before applying changes",
+				func(recreateVMs string, expectedProperties string) {
+					command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+					err := command.Execute([]string{"--recreate-vms", recreateVMs})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(service.UpdateStagedDirectorPropertiesCallCount()).To(Equal(1))
+					Expect(string(service.UpdateStagedDirectorPropertiesArgsForCall(0))).To(MatchJSON(expectedProperties))
+					Expect(service.CreateInstallationCallCount()).To(Equal(1))
+				},
+				Entry("all", "all", `{"director_configuration": {"bosh_recreate_on_next_deploy": true, "bosh_director_recreate_on_next_deploy": true}}`),
+				Entry("director", "director", `{"director_configuration": {"bosh_director_recreate_on_next_deploy": true}}`),
+				Entry("products", "products", `{"director_configuration": {"bosh_recreate_on_next_deploy": true}}`),
+			)
+
+			It("does not change the director when re-attaching to an ongoing installation", func() {
+				startedAt := time.Date(2017, time.February, 25, 02, 31, 1, 0, time.UTC)
+				service.RunningInstallationReturns(api.InstallationsServiceOutput{ID: 200, Status: "running", StartedAt: &startedAt}, nil)
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--recreate-vms", "all"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.UpdateStagedDirectorPropertiesCallCount()).To(Equal(0))
+			})
+
+			It("fails if the value is not valid", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--recreate-vms", "some"})
+				Expect(err).To(MatchError("invalid value for --recreate-vms: some (options: all, director, products)"))
+
+				Expect(service.UpdateStagedDirectorPropertiesCallCount()).To(Equal(0))
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+			})
+
+			It("fails if product VMs are recreated without deploying products", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--recreate-vms", "products", "--skip-deploy-products"})
+				Expect(err).To(MatchError("--recreate-vms products can not be passed with the skip-deploy-products flag, use --recreate-vms director instead"))
+			})
+
+			It("fails if the director cannot be updated", func() {
+				service.UpdateStagedDirectorPropertiesReturns(errors.New("some api error"))
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--recreate-vms", "director"})
+				Expect(err).To(MatchError("could not set VMs to be recreated: some api error"))
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when passed the errand flag", func() {
+			It("overrides the post-deploy errands for this installation", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{
+					"--errand", "product1:smoke_tests:when-changed",
+					"--errand", "product1:push-apps:false",
+					"--errand", "product2:smoke_tests:default",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, errands := service.CreateInstallationArgsForCall(0)
+				Expect(errands).To(Equal(api.ApplyErrandChanges{
+					Errands: map[string]api.ProductErrand{
+						"product1": {
+							RunPostDeploy: map[string]interface{}{
+								"smoke_tests": "when-changed",
+								"push-apps":   false,
+							},
+						},
+						"product2": {
+							RunPostDeploy: map[string]interface{}{
+								"smoke_tests": "default",
+							},
+						},
+					},
+				}))
+			})
+
+			It("fails if the errand is not in the form <product-name>:<errand-name>:<state>", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--errand", "smoke_tests:true"})
+				Expect(err).To(MatchError(`expected "--errand" to be in the form <product-name>:<errand-name>:<state>, got "smoke_tests:true"`))
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+			})
+
+			It("fails if the errand state is not valid", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--errand", "product1:smoke_tests:sometimes"})
+				Expect(err).To(MatchError(`invalid state "sometimes" for errand smoke_tests: expected one of default, true, false, when-changed`))
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when passed the skip-errands flag", func() {
+			BeforeEach(func() {
+				pendingService.ListStagedPendingChangesReturns(api.PendingChangesOutput{
+					ChangeList: []api.ProductChange{
+						{
+							GUID:   "product1-guid",
+							Action: "update",
+							Errands: []api.Errand{
+								{Name: "smoke_tests", PostDeploy: "when-changed"},
+								{Name: "delete-apps", PreDelete: true},
+							},
+						},
+						{
+							GUID:   "product2-guid",
+							Action: "unchanged",
+							Errands: []api.Errand{
+								{Name: "push-apps", PostDeploy: true},
+							},
+						},
+					},
+				}, nil)
+			})
+
+			It("disables every post-deploy errand for this installation", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--skip-errands"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, errands := service.CreateInstallationArgsForCall(0)
+				Expect(errands).To(Equal(api.ApplyErrandChanges{
+					Errands: map[string]api.ProductErrand{
+						"product1-guid": {
+							RunPostDeploy: map[string]interface{}{
+								"smoke_tests": false,
+							},
+						},
+						"product2-guid": {
+							RunPostDeploy: map[string]interface{}{
+								"push-apps": false,
+							},
+						},
+					},
+				}))
+			})
+
+			It("still runs the errands that are explicitly enabled", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--skip-errands", "--errand", "product1-guid:smoke_tests:true"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, errands := service.CreateInstallationArgsForCall(0)
+				Expect(errands.Errands["product1-guid"].RunPostDeploy).To(Equal(map[string]interface{}{
+					"smoke_tests": true,
+				}))
+			})
+
+			It("returns an error when the errands cannot be listed", func() {
+				pendingService.ListStagedPendingChangesReturns(api.PendingChangesOutput{}, errors.New("some api error"))
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--skip-errands"})
+				Expect(err).To(MatchError("could not list the errands of the staged products: some api error"))
+			})
+		})
+
 		Context("Load config file", func() {
 			var fileName string
 
@@ -398,6 +600,348 @@ errands: lolololol
 			Expect(service.GetInstallationLogsArgsForCall(0)).To(Equal(200))
 		})
 
+		Context("when passed the reattach flag", func() {
+			It("re-attaches to an ongoing installation", func() {
+				startedAt := time.Date(2017, time.February, 25, 02, 31, 1, 0, time.UTC)
+				service.RunningInstallationReturns(api.InstallationsServiceOutput{ID: 200, Status: "running", StartedAt: &startedAt}, nil)
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--reattach"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+				Expect(service.GetInstallationArgsForCall(0)).To(Equal(200))
+			})
+
+			It("reports the result of the most recent installation when none is running", func() {
+				service.ListInstallationsReturns([]api.InstallationsServiceOutput{
+					{ID: 201, Status: "failed"},
+					{ID: 200, Status: "succeeded"},
+				}, nil)
+				statusOutputs = []api.InstallationsServiceOutput{{Status: "failed"}}
+				statusErrors = []error{nil}
+				logsOutputs = []api.InstallationsServiceOutput{{Logs: "the logs"}}
+				logsErrors = []error{nil}
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--reattach"})
+				Expect(err).To(MatchError("installation was unsuccessful"))
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+				Expect(service.GetInstallationArgsForCall(0)).To(Equal(201))
+				Expect(writer.FlushArgsForCall(0)).To(Equal("the logs"))
+
+				format, content := logger.PrintfArgsForCall(0)
+				Expect(fmt.Sprintf(format, content...)).To(Equal("no installation is running...re-attaching to the most recent installation (Installation ID: 201, Status: failed)"))
+			})
+
+			It("returns an error when there are no installations", func() {
+				service.ListInstallationsReturns([]api.InstallationsServiceOutput{}, nil)
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--reattach"})
+				Expect(err).To(MatchError("could not re-attach: there are no installations on the targeted Ops Manager"))
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the installations cannot be listed", func() {
+				service.ListInstallationsReturns(nil, errors.New("some api error"))
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+				err := command.Execute([]string{"--reattach"})
+				Expect(err).To(MatchError("could not list installations to re-attach to: some api error"))
+			})
+		})
+
+		Context("when passed the logs flags", func() {
+			var buffer *bytes.Buffer
+
+			BeforeEach(func() {
+				buffer = &bytes.Buffer{}
+
+				service.ListStagedProductsReturns(api.StagedProductsOutput{
+					Products: []api.StagedProduct{
+						{GUID: "p-bosh-guid", Type: "p-bosh"},
+						{GUID: "cf-guid", Type: "cf"},
+						{GUID: "p-redis-guid", Type: "p-redis"},
+					},
+				}, nil)
+
+				logsOutputs = []api.InstallationsServiceOutput{
+					{Logs: "===== 2018-08-29 17:53:45 UTC Running \"bosh create-env /var/tempest/workspaces/default/deployments/bosh.yml\"\n" +
+						"Deploying director\n"},
+					{Logs: "===== 2018-08-29 17:53:45 UTC Running \"bosh create-env /var/tempest/workspaces/default/deployments/bosh.yml\"\n" +
+						"Deploying director\n" +
+						"===== 2018-08-29 18:01:02 UTC Running \"bosh --deployment=cf-guid deploy /var/tempest/workspaces/default/deployments/cf-guid.yml\"\n" +
+						"Updating cf\n" +
+						"===== 2018-08-29 18:11:02 UTC Running \"bosh -d p-redis-guid deploy /var/tempest/workspaces/default/deployments/p-redis-guid.yml\"\n" +
+						"Updating re"},
+					{Logs: "===== 2018-08-29 17:53:45 UTC Running \"bosh create-env /var/tempest/workspaces/default/deployments/bosh.yml\"\n" +
+						"Deploying director\n" +
+						"===== 2018-08-29 18:01:02 UTC Running \"bosh --deployment=cf-guid deploy /var/tempest/workspaces/default/deployments/cf-guid.yml\"\n" +
+						"Updating cf\n" +
+						"===== 2018-08-29 18:11:02 UTC Running \"bosh -d p-redis-guid deploy /var/tempest/workspaces/default/deployments/p-redis-guid.yml\"\n" +
+						"Updating redis\n" +
+						"===== 2018-08-29 18:21:02 UTC Running \"bosh upload-stemcell /var/tempest/stemcells/stemcell.tgz\"\n" +
+						"Uploading stemcell\n"},
+				}
+			})
+
+			It("only prints the logs of the given products", func() {
+				command := commands.NewApplyChanges(service, pendingService, commands.NewLogWriter(buffer), logger, 1)
+
+				err := command.Execute([]string{"--logs-product", "cf", "--logs-product", "p-redis-guid"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(buffer.String()).To(Equal(
+					"===== 2018-08-29 18:01:02 UTC Running \"bosh --deployment=cf-guid deploy /var/tempest/workspaces/default/deployments/cf-guid.yml\"\n" +
+						"Updating cf\n" +
+						"===== 2018-08-29 18:11:02 UTC Running \"bosh -d p-redis-guid deploy /var/tempest/workspaces/default/deployments/p-redis-guid.yml\"\n" +
+						"Updating redis\n"))
+			})
+
+			It("does not print the logs of the director", func() {
+				command := commands.NewApplyChanges(service, pendingService, commands.NewLogWriter(buffer), logger, 1)
+
+				err := command.Execute([]string{"--logs-skip-director"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(buffer.String()).NotTo(ContainSubstring("Deploying director"))
+				Expect(buffer.String()).NotTo(ContainSubstring("Uploading stemcell"))
+				Expect(buffer.String()).To(ContainSubstring("Updating cf\n"))
+				Expect(buffer.String()).To(ContainSubstring("Updating redis\n"))
+			})
+
+			It("prefixes every line with the name of its product", func() {
+				command := commands.NewApplyChanges(service, pendingService, commands.NewLogWriter(buffer), logger, 1)
+
+				err := command.Execute([]string{"--logs-prefix"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(buffer.String()).To(Equal(
+					"[director] ===== 2018-08-29 17:53:45 UTC Running \"bosh create-env /var/tempest/workspaces/default/deployments/bosh.yml\"\n" +
+						"[director] Deploying director\n" +
+						"[cf] ===== 2018-08-29 18:01:02 UTC Running \"bosh --deployment=cf-guid deploy /var/tempest/workspaces/default/deployments/cf-guid.yml\"\n" +
+						"[cf] Updating cf\n" +
+						"[p-redis] ===== 2018-08-29 18:11:02 UTC Running \"bosh -d p-redis-guid deploy /var/tempest/workspaces/default/deployments/p-redis-guid.yml\"\n" +
+						"[p-redis] Updating redis\n" +
+						"[director] ===== 2018-08-29 18:21:02 UTC Running \"bosh upload-stemcell /var/tempest/stemcells/stemcell.tgz\"\n" +
+						"[director] Uploading stemcell\n"))
+			})
+
+			It("prints the last line of the logs once the installation is over, even without a newline", func() {
+				logsOutputs[2].Logs = strings.TrimSuffix(logsOutputs[2].Logs, "\n")
+
+				command := commands.NewApplyChanges(service, pendingService, commands.NewLogWriter(buffer), logger, 1)
+
+				err := command.Execute([]string{"--logs-prefix"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(buffer.String()).To(HaveSuffix("[director] Uploading stemcell"))
+			})
+
+			It("does not list the staged products when no logs flag is passed", func() {
+				command := commands.NewApplyChanges(service, pendingService, commands.NewLogWriter(buffer), logger, 1)
+
+				err := command.Execute([]string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.ListStagedProductsCallCount()).To(Equal(0))
+				Expect(buffer.String()).To(HaveSuffix("Updating redis\n" +
+					"===== 2018-08-29 18:21:02 UTC Running \"bosh upload-stemcell /var/tempest/stemcells/stemcell.tgz\"\n" +
+					"Uploading stemcell\n"))
+			})
+
+			It("returns an error when the staged products cannot be listed", func() {
+				service.ListStagedProductsReturns(api.StagedProductsOutput{}, errors.New("some error"))
+
+				command := commands.NewApplyChanges(service, pendingService, commands.NewLogWriter(buffer), logger, 1)
+
+				err := command.Execute([]string{"--logs-prefix"})
+				Expect(err).To(MatchError("could not list the staged products to filter the installation logs: some error"))
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when passed the poll-interval flag", func() {
+			It("waits for the given interval between status checks", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, time.Hour)
+
+				err := command.Execute([]string{"--poll-interval", "1ms"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.GetInstallationCallCount()).To(Equal(3))
+			})
+		})
+
+		Context("when passed the timeout flag", func() {
+			It("stops waiting and leaves the installation running", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--timeout", "1ns"})
+				Expect(err).To(Equal(commands.ErrInstallationTimedOut))
+
+				Expect(service.GetInstallationCallCount()).To(Equal(1))
+				Expect(writer.FlushArgsForCall(0)).To(Equal("start of logs"))
+
+				format, content := logger.PrintfArgsForCall(1)
+				Expect(fmt.Sprintf(format, content...)).To(Equal("waited 1ns for the installation to finish (Installation ID: 311)"))
+			})
+
+			It("returns the result of an installation that finishes in time", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--timeout", "1h"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.GetInstallationCallCount()).To(Equal(3))
+			})
+		})
+
+		Context("when passed the notify-url flag", func() {
+			var (
+				server   *httptest.Server
+				payloads []map[string]interface{}
+				status   int
+			)
+
+			BeforeEach(func() {
+				payloads = nil
+				status = http.StatusOK
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					defer GinkgoRecover()
+
+					Expect(req.Method).To(Equal("POST"))
+					Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+
+					var payload map[string]interface{}
+					Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+					payloads = append(payloads, payload)
+
+					w.WriteHeader(status)
+				}))
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("posts a summary of the installation when it finishes", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--notify-url", server.URL, "--product-name", "cf"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(payloads).To(HaveLen(1))
+				Expect(payloads[0]).To(HaveKeyWithValue("command", "apply-changes"))
+				Expect(payloads[0]).To(HaveKeyWithValue("status", "succeeded"))
+				Expect(payloads[0]).To(HaveKeyWithValue("duration", "0s"))
+				Expect(payloads[0]).To(HaveKeyWithValue("duration_seconds", BeNumerically("==", 0)))
+				Expect(payloads[0]).To(HaveKeyWithValue("products", []interface{}{"cf"}))
+				Expect(payloads[0]).To(HaveKeyWithValue("errors", BeEmpty()))
+			})
+
+			It("posts the errors of a failed installation", func() {
+				statusOutputs = []api.InstallationsServiceOutput{{Status: "failed"}}
+				statusErrors = []error{nil}
+				logsOutputs = []api.InstallationsServiceOutput{{Logs: "start of logs"}}
+				logsErrors = []error{nil}
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--notify-url", server.URL})
+				Expect(err).To(MatchError("installation was unsuccessful"))
+
+				Expect(payloads).To(HaveLen(1))
+				Expect(payloads[0]).To(HaveKeyWithValue("status", "failed"))
+				Expect(payloads[0]).To(HaveKeyWithValue("products", BeEmpty()))
+				Expect(payloads[0]).To(HaveKeyWithValue("errors", []interface{}{"installation was unsuccessful"}))
+			})
+
+			It("posts the staged products when all of them are deployed", func() {
+				service.ListStagedProductsReturns(api.StagedProductsOutput{
+					Products: []api.StagedProduct{
+						{GUID: "p-bosh-guid", Type: "p-bosh"},
+						{GUID: "cf-guid", Type: "cf"},
+						{GUID: "p-redis-guid", Type: "p-redis"},
+					},
+				}, nil)
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--notify-url", server.URL})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(1))
+				_, _, productNames, _ := service.CreateInstallationArgsForCall(0)
+				Expect(productNames).To(BeEmpty())
+
+				Expect(payloads).To(HaveLen(1))
+				Expect(payloads[0]).To(HaveKeyWithValue("products", []interface{}{"cf", "p-redis"}))
+			})
+
+			It("posts a timed out status when the installation does not finish in time", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--notify-url", server.URL, "--timeout", "1ns"})
+				Expect(err).To(Equal(commands.ErrInstallationTimedOut))
+
+				Expect(payloads).To(HaveLen(1))
+				Expect(payloads[0]).To(HaveKeyWithValue("status", "timed_out"))
+				Expect(payloads[0]).To(HaveKeyWithValue("errors", []interface{}{commands.ErrInstallationTimedOut.Error()}))
+			})
+
+			It("posts a Slack message with the slack format", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--notify-url", server.URL, "--notify-format", "slack", "--product-name", "cf", "--product-name", "p-mysql"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(payloads).To(Equal([]map[string]interface{}{
+					{"text": "om apply-changes succeeded after 0s (products: cf, p-mysql)"},
+				}))
+			})
+
+			It("logs a warning and keeps the result of the installation when the notification fails", func() {
+				status = http.StatusInternalServerError
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--notify-url", server.URL + "/services/some-secret"})
+				Expect(err).NotTo(HaveOccurred())
+
+				format, content := logger.PrintfArgsForCall(logger.PrintfCallCount() - 1)
+				Expect(fmt.Sprintf(format, content...)).To(Equal(fmt.Sprintf("warning: could not notify %s: unexpected response 500", server.URL)))
+			})
+
+			It("does not log the path of the url, which can be the secret of a webhook, when the url cannot be reached", func() {
+				unreachable := httptest.NewServer(http.NotFoundHandler())
+				unreachable.Close()
+
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--notify-url", unreachable.URL + "/services/some-secret"})
+				Expect(err).NotTo(HaveOccurred())
+
+				format, content := logger.PrintfArgsForCall(logger.PrintfCallCount() - 1)
+				warning := fmt.Sprintf(format, content...)
+				Expect(warning).To(HavePrefix(fmt.Sprintf("warning: could not notify %s: ", unreachable.URL)))
+				Expect(warning).NotTo(ContainSubstring("some-secret"))
+			})
+
+			It("returns an error when the format is not supported", func() {
+				command := commands.NewApplyChanges(service, pendingService, writer, logger, 1)
+
+				err := command.Execute([]string{"--notify-url", server.URL, "--notify-format", "email"})
+				Expect(err).To(MatchError(`could not parse apply-changes flags: --notify-format must be json or slack, got "email"`))
+
+				Expect(service.CreateInstallationCallCount()).To(Equal(0))
+				Expect(payloads).To(BeEmpty())
+			})
+		})
+
 		It("handles a failed installation", func() {
 			service.CreateInstallationReturns(api.InstallationsServiceOutput{ID: 311}, nil)
 			statusOutputs = []api.InstallationsServiceOutput{
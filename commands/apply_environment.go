@@ -0,0 +1,265 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyEnvironment configures a foundation from a config directory, with the
+// commands that configure each part of it, in the order they depend on each
+// other: the director, the products, the stemcells of the products, then
+// apply-changes.
+type ApplyEnvironment struct {
+	environFunc       func() []string
+	configureDirector subcommand
+	configureProduct  subcommand
+	compareConfig     subcommand
+	assignStemcell    subcommand
+	applyChanges      subcommand
+	logger            logger
+	Options           struct {
+		ConfigDir    string   `long:"config-dir"    short:"c" required:"true" description:"directory of the configs of the foundation (see docs/apply-environment/README.md for its layout)"`
+		VarsEnv      []string `long:"vars-env"                                description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		VarsStore    string   `long:"vars-store"                              description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		Plan         bool     `long:"plan"                                    description:"print the commands that would be run, and how the staged director and products differ from their configs, without changing anything"`
+		ApplyChanges bool     `long:"apply-changes"                           description:"run apply-changes once the foundation is configured"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/subcommand.go --fake-name Subcommand . subcommand
+type subcommand interface {
+	Execute(args []string) error
+}
+
+// environmentStep is a command that apply-environment runs
+type environmentStep struct {
+	name    string
+	command subcommand
+	args    []string
+	// the product whose staged config is compared against its config in
+	// the plan, when the step configures one
+	product string
+}
+
+func NewApplyEnvironment(environFunc func() []string, configureDirector, configureProduct, compareConfig, assignStemcell, applyChanges subcommand, logger logger) ApplyEnvironment {
+	return ApplyEnvironment{
+		environFunc:       environFunc,
+		configureDirector: configureDirector,
+		configureProduct:  configureProduct,
+		compareConfig:     compareConfig,
+		assignStemcell:    assignStemcell,
+		applyChanges:      applyChanges,
+		logger:            logger,
+	}
+}
+
+func (ae ApplyEnvironment) Execute(args []string) error {
+	if _, err := jhanda.Parse(&ae.Options, args); err != nil {
+		return fmt.Errorf("could not parse apply-environment flags: %s", err)
+	}
+
+	info, err := os.Stat(ae.Options.ConfigDir)
+	if err != nil {
+		return fmt.Errorf("could not read the config directory: %s", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("could not read the config directory: %s is not a directory", ae.Options.ConfigDir)
+	}
+
+	steps, err := ae.steps()
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		return fmt.Errorf("there is nothing to configure in %s: expected director.yml, products/*.yml or stemcells/*.yml", ae.Options.ConfigDir)
+	}
+
+	if ae.Options.Plan {
+		return ae.plan(steps)
+	}
+
+	for _, step := range steps {
+		ae.logger.Printf("## %s %s", step.name, strings.Join(step.args, " "))
+
+		err := step.command.Execute(step.args)
+		if err != nil {
+			return fmt.Errorf("could not %s: %s", step.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (ae ApplyEnvironment) plan(steps []environmentStep) error {
+	ae.logger.Println("the following commands would be run:")
+	for _, step := range steps {
+		ae.logger.Printf("  om %s %s", step.name, strings.Join(step.args, " "))
+	}
+
+	for _, step := range steps {
+		var args []string
+		switch {
+		case step.name == "configure-director":
+			ae.logger.Println("## director")
+			args = append([]string{"--director"}, step.args...)
+		case step.product != "":
+			ae.logger.Printf("## product: %s", step.product)
+			args = append([]string{"--product-name", step.product}, step.args...)
+		default:
+			continue
+		}
+
+		// the drift and the products that are not staged yet are what the
+		// plan reports, they do not fail it
+		err := ae.compareConfig.Execute(args)
+		if err != nil {
+			ae.logger.Println(err)
+		}
+	}
+
+	return nil
+}
+
+func (ae ApplyEnvironment) steps() ([]environmentStep, error) {
+	varsFiles, err := ae.glob("vars")
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []environmentStep
+
+	directorConfig := filepath.Join(ae.Options.ConfigDir, "director.yml")
+	if _, err := os.Stat(directorConfig); err == nil {
+		opsFiles, err := ae.glob(filepath.Join("ops-files", "director"))
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, environmentStep{name: "configure-director", command: ae.configureDirector, args: ae.configArgs(directorConfig, varsFiles, opsFiles)})
+	}
+
+	productConfigs, err := ae.glob("products")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, productConfig := range productConfigs {
+		name := strings.TrimSuffix(filepath.Base(productConfig), filepath.Ext(productConfig))
+		opsFiles, err := ae.glob(filepath.Join("ops-files", name))
+		if err != nil {
+			return nil, err
+		}
+
+		product, err := ae.productName(productConfig, varsFiles, opsFiles)
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, environmentStep{name: "configure-product", command: ae.configureProduct, args: ae.configArgs(productConfig, varsFiles, opsFiles), product: product})
+	}
+
+	stemcellConfigs, err := ae.glob("stemcells")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stemcellConfig := range stemcellConfigs {
+		steps = append(steps, environmentStep{name: "assign-stemcell", command: ae.assignStemcell, args: []string{"--config", stemcellConfig}})
+	}
+
+	if ae.Options.ApplyChanges && len(steps) > 0 {
+		steps = append(steps, environmentStep{name: "apply-changes", command: ae.applyChanges})
+	}
+
+	return steps, nil
+}
+
+// configArgs are the args of the command that configures with the config,
+// with the vars of the foundation and the ops files of the config
+func (ae ApplyEnvironment) configArgs(config string, varsFiles, opsFiles []string) []string {
+	args := []string{"--config", config}
+	for _, varsFile := range varsFiles {
+		args = append(args, "--vars-file", varsFile)
+	}
+	for _, varsEnv := range ae.Options.VarsEnv {
+		args = append(args, "--vars-env", varsEnv)
+	}
+	if ae.Options.VarsStore != "" {
+		args = append(args, "--vars-store", ae.Options.VarsStore)
+	}
+	for _, opsFile := range opsFiles {
+		args = append(args, "--ops-file", opsFile)
+	}
+
+	return args
+}
+
+// productName is the product-name of the product config, which is only
+// interpolated when it is a variable, as the plan must not require the
+// secrets of the config.
+func (ae ApplyEnvironment) productName(config string, varsFiles, opsFiles []string) (string, error) {
+	var product struct {
+		Name string `yaml:"product-name"`
+	}
+	err := readYAMLFile(config, &product)
+	if err != nil {
+		return "", fmt.Errorf("could not parse %s: %s", config, err)
+	}
+
+	if strings.Contains(product.Name, "((") {
+		contents, err := interpolate(interpolateOptions{
+			templateFile: config,
+			varsFiles:    varsFiles,
+			environFunc:  ae.environFunc,
+			varsEnvs:     ae.Options.VarsEnv,
+			varsStore:    ae.Options.VarsStore,
+			opsFiles:     opsFiles,
+		}, "/product-name")
+		if err != nil {
+			return "", fmt.Errorf("could not interpolate the product-name of %s: %s", config, err)
+		}
+
+		err = yaml.Unmarshal(contents, &product.Name)
+		if err != nil {
+			return "", fmt.Errorf("could not interpolate the product-name of %s: %s", config, err) // un-tested
+		}
+	}
+
+	if product.Name == "" {
+		return "", fmt.Errorf("%s does not have a product-name", config)
+	}
+
+	return product.Name, nil
+}
+
+// glob lists the yml files of the directory of the config directory, in
+// the order of their names; a directory that does not exist has none.
+func (ae ApplyEnvironment) glob(dir string) ([]string, error) {
+	var files []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(ae.Options.ConfigDir, dir, pattern))
+		if err != nil {
+			return nil, err // un-tested
+		}
+		files = append(files, matches...)
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func (ae ApplyEnvironment) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command configures a foundation from a config directory: the director with director.yml, every product with its config in products, the stemcells of the products with the assign-stemcell configs in stemcells, then runs apply-changes when asked to. The vars files in vars are given to every config, and the ops files in ops-files/<name of the config> to that config.",
+		ShortDescription: "configures the director, the products and their stemcells from a config directory",
+		Flags:            ae.Options,
+	}
+}
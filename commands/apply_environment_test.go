@@ -0,0 +1,247 @@
+package commands_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("ApplyEnvironment", func() {
+	var (
+		configDir         string
+		configureDirector *fakes.Subcommand
+		configureProduct  *fakes.Subcommand
+		compareConfig     *fakes.Subcommand
+		assignStemcell    *fakes.Subcommand
+		applyChanges      *fakes.Subcommand
+		order             []string
+		stdout            *gbytes.Buffer
+		command           commands.ApplyEnvironment
+	)
+
+	writeFile := func(path, contents string) {
+		path = filepath.Join(configDir, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+	}
+
+	inDir := func(path string) string {
+		return filepath.Join(configDir, path)
+	}
+
+	BeforeEach(func() {
+		var err error
+		configDir, err = ioutil.TempDir("", "foundation")
+		Expect(err).NotTo(HaveOccurred())
+
+		order = nil
+		record := func(name string) *fakes.Subcommand {
+			fake := &fakes.Subcommand{}
+			fake.ExecuteStub = func([]string) error {
+				order = append(order, name)
+				return nil
+			}
+			return fake
+		}
+
+		configureDirector = record("configure-director")
+		configureProduct = record("configure-product")
+		compareConfig = &fakes.Subcommand{}
+		assignStemcell = record("assign-stemcell")
+		applyChanges = record("apply-changes")
+
+		stdout = gbytes.NewBuffer()
+		command = commands.NewApplyEnvironment(func() []string { return nil },
+			configureDirector, configureProduct, compareConfig, assignStemcell, applyChanges, log.New(stdout, "", 0))
+
+		writeFile("director.yml", "az-configuration: []\n")
+		writeFile("products/01-cf.yml", "product-name: cf\n")
+		writeFile("products/02-windows.yml", "product-name: ((product_name))\n")
+		writeFile("stemcells/cf.yml", "product: cf\nstemcell: latest\n")
+		writeFile("vars/b.yml", "product_name: pas-windows\n")
+		writeFile("vars/a.yml", "a: 1\n")
+		writeFile("ops-files/director/az.yml", "[]\n")
+		writeFile("ops-files/01-cf/small.yml", "[]\n")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(configDir)
+	})
+
+	Describe("Execute", func() {
+		It("configures the director, the products and their stemcells, in that order", func() {
+			err := command.Execute([]string{"--config-dir", configDir})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(order).To(Equal([]string{"configure-director", "configure-product", "configure-product", "assign-stemcell"}))
+
+			Expect(configureDirector.ExecuteArgsForCall(0)).To(Equal([]string{
+				"--config", inDir("director.yml"),
+				"--vars-file", inDir("vars/a.yml"),
+				"--vars-file", inDir("vars/b.yml"),
+				"--ops-file", inDir("ops-files/director/az.yml"),
+			}))
+
+			Expect(configureProduct.ExecuteArgsForCall(0)).To(Equal([]string{
+				"--config", inDir("products/01-cf.yml"),
+				"--vars-file", inDir("vars/a.yml"),
+				"--vars-file", inDir("vars/b.yml"),
+				"--ops-file", inDir("ops-files/01-cf/small.yml"),
+			}))
+			Expect(configureProduct.ExecuteArgsForCall(1)).To(Equal([]string{
+				"--config", inDir("products/02-windows.yml"),
+				"--vars-file", inDir("vars/a.yml"),
+				"--vars-file", inDir("vars/b.yml"),
+			}))
+
+			Expect(assignStemcell.ExecuteArgsForCall(0)).To(Equal([]string{"--config", inDir("stemcells/cf.yml")}))
+
+			Expect(compareConfig.ExecuteCallCount()).To(Equal(0))
+			Expect(applyChanges.ExecuteCallCount()).To(Equal(0))
+		})
+
+		It("passes the vars of the environment and of the vars store to every config", func() {
+			// the product-name of the products is only interpolated with the
+			// vars store when it is a variable
+			Expect(os.RemoveAll(inDir("products"))).To(Succeed())
+
+			err := command.Execute([]string{"--config-dir", configDir, "--vars-env", "OM_VAR", "--vars-store", "credhub"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(configureDirector.ExecuteArgsForCall(0)).To(Equal([]string{
+				"--config", inDir("director.yml"),
+				"--vars-file", inDir("vars/a.yml"),
+				"--vars-file", inDir("vars/b.yml"),
+				"--vars-env", "OM_VAR",
+				"--vars-store", "credhub",
+				"--ops-file", inDir("ops-files/director/az.yml"),
+			}))
+		})
+
+		It("runs apply-changes last when asked to", func() {
+			err := command.Execute([]string{"--config-dir", configDir, "--apply-changes"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(order).To(Equal([]string{"configure-director", "configure-product", "configure-product", "assign-stemcell", "apply-changes"}))
+			Expect(applyChanges.ExecuteArgsForCall(0)).To(BeEmpty())
+		})
+
+		It("only runs the commands of the parts of the foundation that have a config", func() {
+			Expect(os.Remove(inDir("director.yml"))).To(Succeed())
+			Expect(os.RemoveAll(inDir("stemcells"))).To(Succeed())
+
+			err := command.Execute([]string{"--config-dir", configDir})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(order).To(Equal([]string{"configure-product", "configure-product"}))
+		})
+
+		Context("with --plan", func() {
+			It("prints the commands and compares the staged director and products with their configs without changing anything", func() {
+				compareConfig.ExecuteReturnsOnCall(2, errors.New("the staged configuration of pas-windows has drifted from 02-windows.yml"))
+
+				err := command.Execute([]string{"--config-dir", configDir, "--plan", "--apply-changes", "--vars-env", "OM_VAR"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(order).To(BeEmpty())
+
+				Expect(stdout).To(gbytes.Say("the following commands would be run:"))
+				Expect(stdout).To(gbytes.Say(`om configure-director --config \S+director.yml`))
+				Expect(stdout).To(gbytes.Say(`om configure-product --config \S+01-cf.yml`))
+				Expect(stdout).To(gbytes.Say(`om configure-product --config \S+02-windows.yml`))
+				Expect(stdout).To(gbytes.Say(`om assign-stemcell --config \S+cf.yml`))
+				Expect(stdout).To(gbytes.Say(`om apply-changes`))
+				Expect(stdout).To(gbytes.Say("## director"))
+				Expect(stdout).To(gbytes.Say("## product: cf"))
+				Expect(stdout).To(gbytes.Say("## product: pas-windows"))
+				Expect(stdout).To(gbytes.Say("the staged configuration of pas-windows has drifted from 02-windows.yml"))
+
+				Expect(compareConfig.ExecuteCallCount()).To(Equal(3))
+				Expect(compareConfig.ExecuteArgsForCall(0)).To(Equal([]string{
+					"--director",
+					"--config", inDir("director.yml"),
+					"--vars-file", inDir("vars/a.yml"),
+					"--vars-file", inDir("vars/b.yml"),
+					"--vars-env", "OM_VAR",
+					"--ops-file", inDir("ops-files/director/az.yml"),
+				}))
+				Expect(compareConfig.ExecuteArgsForCall(1)).To(Equal([]string{
+					"--product-name", "cf",
+					"--config", inDir("products/01-cf.yml"),
+					"--vars-file", inDir("vars/a.yml"),
+					"--vars-file", inDir("vars/b.yml"),
+					"--vars-env", "OM_VAR",
+					"--ops-file", inDir("ops-files/01-cf/small.yml"),
+				}))
+				Expect(compareConfig.ExecuteArgsForCall(2)).To(ContainElement("pas-windows"))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the flags cannot be parsed", func() {
+				err := command.Execute([]string{"--unknown-flag"})
+				Expect(err).To(MatchError(ContainSubstring("could not parse apply-environment flags")))
+			})
+
+			It("returns an error when the config directory does not exist", func() {
+				err := command.Execute([]string{"--config-dir", inDir("missing")})
+				Expect(err).To(MatchError(ContainSubstring("could not read the config directory")))
+			})
+
+			It("returns an error when the config directory has nothing to configure", func() {
+				emptyDir, err := ioutil.TempDir("", "empty")
+				Expect(err).NotTo(HaveOccurred())
+				defer os.RemoveAll(emptyDir)
+
+				err = command.Execute([]string{"--config-dir", emptyDir})
+				Expect(err).To(MatchError(ContainSubstring("there is nothing to configure in")))
+			})
+
+			It("returns an error when a product config does not have a product-name", func() {
+				writeFile("products/03-empty.yml", "product-properties: {}\n")
+
+				err := command.Execute([]string{"--config-dir", configDir})
+				Expect(err).To(MatchError(ContainSubstring("03-empty.yml does not have a product-name")))
+				Expect(order).To(BeEmpty())
+			})
+
+			It("returns an error when the product-name is a variable that is not provided", func() {
+				writeFile("products/03-missing.yml", "product-name: ((missing))\n")
+
+				err := command.Execute([]string{"--config-dir", configDir})
+				Expect(err).To(MatchError(ContainSubstring("could not interpolate the product-name of")))
+			})
+
+			It("stops at the first command that fails", func() {
+				configureProduct.ExecuteReturns(errors.New("product is not staged"))
+
+				err := command.Execute([]string{"--config-dir", configDir, "--apply-changes"})
+				Expect(err).To(MatchError("could not configure-product: product is not staged"))
+
+				Expect(order).To(Equal([]string{"configure-director"}))
+				Expect(assignStemcell.ExecuteCallCount()).To(Equal(0))
+				Expect(applyChanges.ExecuteCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command configures a foundation from a config directory: the director with director.yml, every product with its config in products, the stemcells of the products with the assign-stemcell configs in stemcells, then runs apply-changes when asked to. The vars files in vars are given to every config, and the ops files in ops-files/<name of the config> to that config.",
+				ShortDescription: "configures the director, the products and their stemcells from a config directory",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+)
+
+type AssignMultiStemcell struct {
+	logger  logger
+	service assignMultiStemcellService
+	Options struct {
+		ProductName string   `long:"product"  short:"p" description:"name of Ops Manager tile to associate a stemcell to" required:"true"`
+		Stemcells   []string `long:"stemcell" short:"s" description:"associate a particular stemcell version to a tile, in the form <os>:<version> or <os>:latest (can be specified multiple times)" required:"true"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/assign_multi_stemcell_service.go --fake-name AssignMultiStemcellService . assignMultiStemcellService
+type assignMultiStemcellService interface {
+	ListMultiStemcells() (api.ProductMultiStemcells, error)
+	AssignMultiStemcell(input api.ProductMultiStemcells) error
+}
+
+func NewAssignMultiStemcell(service assignMultiStemcellService, logger logger) AssignMultiStemcell {
+	return AssignMultiStemcell{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (as AssignMultiStemcell) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This command will assign already uploaded stemcells to a specific product in Ops Manager.\n" +
+			"It is recommended to use \"upload-stemcell --floating=false\" before using this command.\n" +
+			"Requires Ops Manager 2.6 or newer.",
+		ShortDescription: "assigns multiple uploaded stemcells to a product in the targeted Ops Manager 2.6+",
+		Flags:            as.Options,
+	}
+}
+
+func (as AssignMultiStemcell) Execute(args []string) error {
+	_, err := jhanda.Parse(&as.Options, args)
+	if err != nil {
+		return fmt.Errorf("could not parse assign-multi-stemcell flags: %s", err)
+	}
+
+	var requested []api.StemcellObject
+	for _, stemcell := range as.Options.Stemcells {
+		parts := strings.SplitN(stemcell, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("could not parse assign-multi-stemcell flags: expected \"--stemcell\" to be in the form <os>:<version>, got \"%s\"", stemcell)
+		}
+
+		requested = append(requested, api.StemcellObject{OS: parts[0], Version: parts[1]})
+	}
+
+	as.logger.Printf("finding available stemcells for product: \"%s\"...", as.Options.ProductName)
+	productStemcell, err := as.getProductStemcell()
+	if err != nil {
+		return err
+	}
+
+	if productStemcell.StagedForDeletion {
+		return fmt.Errorf("could not assign stemcell: product \"%s\" is staged for deletion", as.Options.ProductName)
+	}
+
+	as.logger.Println("validating that stemcells exist in Ops Manager...")
+	var stagedStemcells []api.StemcellObject
+	for _, stemcell := range requested {
+		version, err := as.validateStemcellVersion(productStemcell, stemcell)
+		if err != nil {
+			return err
+		}
+
+		stagedStemcells = append(stagedStemcells, api.StemcellObject{OS: stemcell.OS, Version: version})
+	}
+
+	for _, stemcell := range stagedStemcells {
+		as.logger.Printf("assigning stemcell: \"%s %s\" to product \"%s\"...\n", stemcell.OS, stemcell.Version, as.Options.ProductName)
+	}
+
+	err = as.service.AssignMultiStemcell(api.ProductMultiStemcells{
+		Products: []api.ProductMultiStemcell{
+			{
+				GUID:            productStemcell.GUID,
+				StagedStemcells: stagedStemcells,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	as.logger.Println("assigned stemcells successfully")
+	return nil
+}
+
+func (as AssignMultiStemcell) getProductStemcell() (api.ProductMultiStemcell, error) {
+	var result api.ProductMultiStemcell
+
+	productStemcells, err := as.service.ListMultiStemcells()
+	if err != nil {
+		return result, err
+	}
+
+	for _, productStemcell := range productStemcells.Products {
+		if productStemcell.ProductName == as.Options.ProductName {
+			return productStemcell, nil
+		}
+	}
+
+	return result, fmt.Errorf("could not list product stemcell: product \"%s\" not found", as.Options.ProductName)
+}
+
+func (as AssignMultiStemcell) validateStemcellVersion(productStemcell api.ProductMultiStemcell, stemcell api.StemcellObject) (string, error) {
+	var availableVersions []string
+	for _, availableStemcell := range productStemcell.AvailableStemcells {
+		if availableStemcell.OS == stemcell.OS {
+			availableVersions = append(availableVersions, availableStemcell.Version)
+		}
+	}
+
+	if len(availableVersions) == 0 {
+		return "", fmt.Errorf("no \"%s\" stemcells are available for \"%s\". "+
+			"upload-stemcell, and try again",
+			stemcell.OS,
+			as.Options.ProductName)
+	}
+
+	if stemcell.Version == "latest" {
+		return latestStemcellVersion(availableVersions), nil
+	}
+
+	for _, version := range availableVersions {
+		if stemcell.Version == version {
+			return stemcell.Version, nil
+		}
+	}
+
+	return "", fmt.Errorf("stemcell version %s for %s not found in Ops Manager. "+
+		"Available \"%s\" stemcells for \"%s\": %s",
+		stemcell.Version, stemcell.OS, stemcell.OS, as.Options.ProductName, strings.Join(availableVersions, ", "))
+}
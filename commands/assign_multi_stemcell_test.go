@@ -0,0 +1,149 @@
+package commands_test
+
+import (
+	"errors"
+
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AssignMultiStemcell", func() {
+	var (
+		fakeService *fakes.AssignMultiStemcellService
+		logger      *fakes.Logger
+		command     commands.AssignMultiStemcell
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.AssignMultiStemcellService{}
+		logger = &fakes.Logger{}
+		command = commands.NewAssignMultiStemcell(fakeService, logger)
+
+		fakeService.ListMultiStemcellsReturns(api.ProductMultiStemcells{
+			Products: []api.ProductMultiStemcell{
+				{
+					GUID:        "pas-windows-guid",
+					ProductName: "pas-windows",
+					AvailableStemcells: []api.StemcellObject{
+						{OS: "ubuntu-xenial", Version: "250.9"},
+						{OS: "ubuntu-xenial", Version: "250.17"},
+						{OS: "windows2019", Version: "2019.7"},
+						{OS: "windows2019", Version: "2019.12"},
+					},
+				},
+			},
+		}, nil)
+	})
+
+	It("assigns the stemcells for each operating system", func() {
+		err := command.Execute([]string{
+			"--product", "pas-windows",
+			"--stemcell", "ubuntu-xenial:latest",
+			"--stemcell", "windows2019:2019.7",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeService.ListMultiStemcellsCallCount()).To(Equal(1))
+		Expect(fakeService.AssignMultiStemcellCallCount()).To(Equal(1))
+
+		Expect(fakeService.AssignMultiStemcellArgsForCall(0)).To(Equal(api.ProductMultiStemcells{
+			Products: []api.ProductMultiStemcell{
+				{
+					GUID: "pas-windows-guid",
+					StagedStemcells: []api.StemcellObject{
+						{OS: "ubuntu-xenial", Version: "250.17"},
+						{OS: "windows2019", Version: "2019.7"},
+					},
+				},
+			},
+		}))
+	})
+
+	Context("when the stemcell version is not available", func() {
+		It("returns an error with the available stemcells for that operating system", func() {
+			err := command.Execute([]string{"--product", "pas-windows", "--stemcell", "windows2019:2019.99"})
+			Expect(err).To(MatchError(ContainSubstring("stemcell version 2019.99 for windows2019 not found in Ops Manager.")))
+			Expect(err).To(MatchError(ContainSubstring(`Available "windows2019" stemcells for "pas-windows": 2019.7, 2019.12`)))
+
+			Expect(fakeService.AssignMultiStemcellCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when no stemcells are available for the operating system", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{"--product", "pas-windows", "--stemcell", "ubuntu-trusty:latest"})
+			Expect(err).To(MatchError(`no "ubuntu-trusty" stemcells are available for "pas-windows". upload-stemcell, and try again`))
+
+			Expect(fakeService.AssignMultiStemcellCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the product is not found", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{"--product", "cf", "--stemcell", "ubuntu-xenial:latest"})
+			Expect(err).To(MatchError(`could not list product stemcell: product "cf" not found`))
+		})
+	})
+
+	Context("when the product is staged for deletion", func() {
+		It("returns an error", func() {
+			fakeService.ListMultiStemcellsReturns(api.ProductMultiStemcells{
+				Products: []api.ProductMultiStemcell{
+					{
+						GUID:              "pas-windows-guid",
+						ProductName:       "pas-windows",
+						StagedForDeletion: true,
+					},
+				},
+			}, nil)
+
+			err := command.Execute([]string{"--product", "pas-windows", "--stemcell", "ubuntu-xenial:latest"})
+			Expect(err).To(MatchError(`could not assign stemcell: product "pas-windows" is staged for deletion`))
+		})
+	})
+
+	Context("when the stemcell associations cannot be listed", func() {
+		It("returns an error", func() {
+			fakeService.ListMultiStemcellsReturns(api.ProductMultiStemcells{}, errors.New("some api error"))
+
+			err := command.Execute([]string{"--product", "pas-windows", "--stemcell", "ubuntu-xenial:latest"})
+			Expect(err).To(MatchError("some api error"))
+		})
+	})
+
+	Context("when the stemcells cannot be assigned", func() {
+		It("returns an error", func() {
+			fakeService.AssignMultiStemcellReturns(errors.New("some api error"))
+
+			err := command.Execute([]string{"--product", "pas-windows", "--stemcell", "ubuntu-xenial:latest"})
+			Expect(err).To(MatchError("some api error"))
+		})
+	})
+
+	Context("when the stemcell flag is not in the form <os>:<version>", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{"--product", "pas-windows", "--stemcell", "250.17"})
+			Expect(err).To(MatchError(`could not parse assign-multi-stemcell flags: expected "--stemcell" to be in the form <os>:<version>, got "250.17"`))
+
+			Expect(fakeService.ListMultiStemcellsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when an unknown flag is provided", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse assign-multi-stemcell flags: flag provided but not defined: -badflag"))
+		})
+	})
+
+	Context("when the stemcell flag is not provided", func() {
+		It("returns an error", func() {
+			err := command.Execute([]string{"--product", "pas-windows"})
+			Expect(err).To(MatchError("could not parse assign-multi-stemcell flags: missing required flag \"--stemcell\""))
+		})
+	})
+})
@@ -2,8 +2,10 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-version"
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
 )
@@ -108,7 +110,7 @@ func (as *AssignStemcell) validateStemcellVersion(productStemcell api.ProductSte
 	}
 
 	if as.Options.StemcellVersion == "latest" {
-		return availableVersions[len(availableVersions)-1], nil
+		return latestStemcellVersion(availableVersions), nil
 	}
 
 	for _, version := range availableVersions {
@@ -120,3 +122,25 @@ func (as *AssignStemcell) validateStemcellVersion(productStemcell api.ProductSte
 	return "", fmt.Errorf(`stemcell version %s not found in Ops Manager. 
 	Available Stemcells for "%s": %s`, as.Options.StemcellVersion, as.Options.ProductName, strings.Join(availableVersions, ", "))
 }
+
+// latestStemcellVersion compares the available versions numerically, rather than
+// relying on the order they are returned in by Ops Manager.
+// If none of the versions can be parsed, the last version listed is used.
+func latestStemcellVersion(availableVersions []string) string {
+	var versions version.Collection
+	for _, availableVersion := range availableVersions {
+		v, err := version.NewVersion(availableVersion)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+
+	if len(versions) == 0 {
+		return availableVersions[len(availableVersions)-1]
+	}
+
+	sort.Sort(versions)
+
+	return versions[len(versions)-1].Original()
+}
@@ -74,6 +74,32 @@ var _ = Describe("AssignStemcell", func() {
 					},
 				}))
 			})
+
+			Context("when the available stemcells are not listed in order", func() {
+				It("assigns the numerically latest stemcell", func() {
+					fakeService.ListStemcellsReturns(api.ProductStemcells{
+						Products: []api.ProductStemcell{
+							{
+								GUID:              "cf-guid",
+								ProductName:       "cf",
+								AvailableVersions: []string{"97.10", "97.101", "97.9"},
+							},
+						},
+					}, nil)
+
+					err := command.Execute([]string{"--product", "cf", "--stemcell", "latest"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeService.AssignStemcellArgsForCall(0)).To(Equal(api.ProductStemcells{
+						Products: []api.ProductStemcell{
+							{
+								GUID:                  "cf-guid",
+								StagedStemcellVersion: "97.101",
+							},
+						},
+					}))
+				})
+			})
 		})
 	})
 
@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pivotal-cf/jhanda"
+)
+
+// Blobstore manages the products that download-product persisted in an s3
+// blobstore, as [slug,version]file, and the stemcells that it downloaded
+// for them with --stemcell-iaas, which keep the name of their file.
+type Blobstore struct {
+	environFunc    func() []string
+	stower         Stower
+	progressWriter io.Writer
+	logger         logger
+	Options        struct {
+		ConfigFile        string   `long:"config"               short:"c" description:"path to yml file for configuration (keys must match the following command line flags)"`
+		KeepPerProduct    int      `long:"keep-per-product"               description:"number of the newest versions of each product to keep, required by prune"`
+		Delete            bool     `long:"delete"                         description:"delete the files of the versions that are pruned, which are only reported by default"`
+		S3Bucket          string   `long:"s3-bucket"                      description:"bucket name where the products reside in the s3 compatible blobstore"`
+		S3AccessKeyID     string   `long:"s3-access-key-id"               description:"access key for the s3 compatible blobstore"`
+		S3SecretAccessKey string   `long:"s3-secret-access-key"           description:"secret key for the s3 compatible blobstore"`
+		S3RegionName      string   `long:"s3-region-name"                 description:"bucket region in the s3 compatible blobstore. If not using AWS, this value is 'region'. Looked up when omitted for AWS"`
+		S3Endpoint        string   `long:"s3-endpoint"                    description:"the endpoint to access the s3 compatible blobstore. If not using AWS, this is required"`
+		S3Partition       string   `long:"s3-partition"                   description:"the aws partition of the bucket region (options: aws,aws-us-gov,aws-cn,aws-iso,aws-iso-b)"`
+		S3DisableSSL      bool     `long:"s3-disable-ssl"                 description:"whether to disable ssl validation when contacting the s3 compatible blobstore"`
+		S3EnableV2Signing bool     `long:"s3-enable-v2-signing"           description:"whether to use v2 signing with your s3 compatible blobstore. (if you don't know what this is, leave blank, or set to 'false')"`
+		S3Path            string   `long:"s3-path"                        description:"specify the lookup path where the s3 artifacts are stored. for example, \"/location-name/\" will look for files under s3://bucket-name/location-name/"`
+		VarsEnv           []string `long:"vars-env"                       description:"load variables from environment variables matching the provided prefix (e.g.: 'MY' to load MY_var=value)"`
+		VarsFile          []string `long:"vars-file"            short:"l" description:"load variables from a YAML file"`
+		Vars              []string `long:"var"                            description:"load variable from the command line. Format: VAR=VAL"`
+	}
+}
+
+var blobstoreSubcommands = []string{"prune"}
+
+// persistedVersion is a version of a product in the blobstore, with the
+// files that were persisted for it
+type persistedVersion struct {
+	version string
+	files   []string
+}
+
+func NewBlobstore(environFunc func() []string, stower Stower, progressWriter io.Writer, logger logger) Blobstore {
+	return Blobstore{
+		environFunc:    environFunc,
+		stower:         stower,
+		progressWriter: progressWriter,
+		logger:         logger,
+	}
+}
+
+func (b Blobstore) Execute(args []string) error {
+	if len(args) == 0 || !containsString(blobstoreSubcommands, args[0]) {
+		return fmt.Errorf("expected a subcommand of blobstore: %s", strings.Join(blobstoreSubcommands, ", "))
+	}
+
+	err := loadConfigFile(args[1:], &b.Options, b.environFunc)
+	if err != nil {
+		return fmt.Errorf("could not parse blobstore flags: %s", err)
+	}
+
+	if b.Options.KeepPerProduct < 1 {
+		return fmt.Errorf("could not parse blobstore flags: prune requires --keep-per-product of at least 1")
+	}
+
+	client, err := NewS3Client(b.stower, S3Configuration{
+		Bucket:          b.Options.S3Bucket,
+		AccessKeyID:     b.Options.S3AccessKeyID,
+		SecretAccessKey: b.Options.S3SecretAccessKey,
+		RegionName:      b.Options.S3RegionName,
+		Endpoint:        b.Options.S3Endpoint,
+		Partition:       b.Options.S3Partition,
+		DisableSSL:      b.Options.S3DisableSSL,
+		EnableV2Signing: b.Options.S3EnableV2Signing,
+		Path:            b.Options.S3Path,
+	}, b.progressWriter)
+	if err != nil {
+		return fmt.Errorf("could not create an s3 client: %s", err)
+	}
+
+	return b.prune(client)
+}
+
+// prune deletes the files of every version of each product but the newest
+// ones, or only reports them without --delete.
+func (b Blobstore) prune(client *S3Client) error {
+	products, err := b.persistedProducts(client)
+	if err != nil {
+		return err
+	}
+
+	var slugs []string
+	for slug := range products {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	var prunedVersions, prunedFiles int
+	for _, slug := range slugs {
+		versions := products[slug]
+		if len(versions) <= b.Options.KeepPerProduct {
+			b.logger.Printf("%s: keeping %s", slug, joinVersions(versions))
+			continue
+		}
+
+		kept, pruned := versions[:b.Options.KeepPerProduct], versions[b.Options.KeepPerProduct:]
+		b.logger.Printf("%s: keeping %s, pruning %s", slug, joinVersions(kept), joinVersions(pruned))
+
+		for _, v := range pruned {
+			prunedVersions++
+			for _, file := range v.files {
+				prunedFiles++
+				if !b.Options.Delete {
+					b.logger.Printf("  would delete %s", file)
+					continue
+				}
+
+				err := client.deleteFile(file)
+				if err != nil {
+					return fmt.Errorf("could not delete %s: %s", file, err)
+				}
+				b.logger.Printf("  deleted %s", file)
+			}
+		}
+	}
+
+	if !b.Options.Delete {
+		b.logger.Printf("dry run: %d file(s) of %d version(s) would be deleted, run with --delete to delete them", prunedFiles, prunedVersions)
+		return nil
+	}
+
+	b.logger.Printf("deleted %d file(s) of %d version(s)", prunedFiles, prunedVersions)
+
+	return nil
+}
+
+// persistedProducts are the versions of the products of the blobstore by
+// their slug, from the newest version. The versions that are not semver
+// cannot be ordered, they are left out so that they are kept.
+func (b Blobstore) persistedProducts(client *S3Client) (map[string][]persistedVersion, error) {
+	files, err := client.listFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	persistedFile := regexp.MustCompile(
+		fmt.Sprintf(`^/?%s/?\[([^,\]]+),(.*?)\]`,
+			regexp.QuoteMeta(strings.Trim(client.path, "/")),
+		),
+	)
+
+	// the stemcells are grouped by the name of their file without the
+	// version, as they have no slug, e.g. light-bosh-stemcell-*-google-kvm-ubuntu-xenial-go_agent.tgz
+	stemcellFile := regexp.MustCompile(
+		fmt.Sprintf(`^/?%s/?((?:light-)?bosh-stemcell)-([^-]+)-(.+?\.tgz)(?:%s)?$`,
+			regexp.QuoteMeta(strings.Trim(client.path, "/")),
+			regexp.QuoteMeta(sha256FileExtension),
+		),
+	)
+
+	filesByVersion := map[string]map[string][]string{}
+	for _, file := range files {
+		var slug, v string
+		if match := persistedFile.FindStringSubmatch(file); match != nil {
+			slug, v = match[1], match[2]
+		} else if match := stemcellFile.FindStringSubmatch(file); match != nil {
+			slug, v = fmt.Sprintf("%s-*-%s", match[1], match[3]), match[2]
+		} else {
+			continue
+		}
+
+		if filesByVersion[slug] == nil {
+			filesByVersion[slug] = map[string][]string{}
+		}
+		filesByVersion[slug][v] = append(filesByVersion[slug][v], file)
+	}
+
+	products := map[string][]persistedVersion{}
+	for slug, versionFiles := range filesByVersion {
+		var semvers version.Collection
+		for v := range versionFiles {
+			semver, err := version.NewVersion(v)
+			if err != nil {
+				b.logger.Printf("warning: could not parse semver version from: %s of %s, it is kept", v, slug)
+				continue
+			}
+			semvers = append(semvers, semver)
+		}
+		sort.Sort(sort.Reverse(semvers))
+
+		for _, semver := range semvers {
+			products[slug] = append(products[slug], persistedVersion{version: semver.Original(), files: versionFiles[semver.Original()]})
+		}
+	}
+
+	return products, nil
+}
+
+func joinVersions(versions []persistedVersion) string {
+	var names []string
+	for _, v := range versions {
+		names = append(names, v.version)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (b Blobstore) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command manages the products persisted in an s3 blobstore by download-product. Its subcommand prune keeps the newest --keep-per-product versions of each product, and reports the files of the other versions, which are only deleted with --delete. Usage: om blobstore prune [<args>]",
+		ShortDescription: "prunes the old versions of the products persisted in an s3 blobstore",
+		Flags:            b.Options,
+	}
+}
@@ -0,0 +1,191 @@
+package commands_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Blobstore", func() {
+	var (
+		stower  *mockStower
+		removed []string
+		logger  *fakes.Logger
+		command commands.Blobstore
+	)
+
+	logMessages := func() []string {
+		var messages []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, v := logger.PrintfArgsForCall(i)
+			messages = append(messages, fmt.Sprintf(format, v...))
+		}
+		return messages
+	}
+
+	prune := func(args ...string) error {
+		return command.Execute(append([]string{
+			"prune",
+			"--s3-bucket", "bucket",
+			"--s3-access-key-id", "access-key-id",
+			"--s3-secret-access-key", "secret-access-key",
+			"--s3-region-name", "region",
+			"--s3-endpoint", "endpoint",
+		}, args...))
+	}
+
+	BeforeEach(func() {
+		removed = nil
+		stower = &mockStower{
+			location: mockLocation{container: &mockContainer{removedItems: &removed}},
+			itemsList: []mockItem{
+				newMockItem("[elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal"),
+				newMockItem("[elastic-runtime,2.5.0]cf-2.5.0-build.1.pivotal"),
+				newMockItem("[elastic-runtime,2.10.0]cf-2.10.0-build.1.pivotal"),
+				newMockItem("[elastic-runtime,2.6.0-build.2]cf-2.6.0-build.2.pivotal"),
+				newMockItem("[stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz"),
+				newMockItem("[stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz.sha256"),
+				newMockItem("[stemcells-ubuntu-xenial,170.1]light-bosh-stemcell-170.1-google-kvm-ubuntu-xenial-go_agent.tgz"),
+				newMockItem("[p-redis,snapshot]redis-snapshot.pivotal"),
+				newMockItem("[p-redis,2.0.0]redis-2.0.0.pivotal"),
+				newMockItem("notes.txt"),
+			},
+		}
+		logger = &fakes.Logger{}
+		command = commands.NewBlobstore(func() []string { return nil }, stower, GinkgoWriter, logger)
+	})
+
+	Describe("prune", func() {
+		It("reports the versions of each product that would be pruned, without deleting them", func() {
+			err := prune("--keep-per-product", "1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(removed).To(BeEmpty())
+			Expect(logMessages()).To(ContainElement("warning: could not parse semver version from: snapshot of p-redis, it is kept"))
+			Expect(logMessages()).To(ContainElement("elastic-runtime: keeping 2.10.0, pruning 2.6.0-build.2, 2.5.0, 2.4.1"))
+			Expect(logMessages()).To(ContainElement("  would delete [elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal"))
+			Expect(logMessages()).To(ContainElement("p-redis: keeping 2.0.0"))
+			Expect(logMessages()).To(ContainElement("stemcells-ubuntu-xenial: keeping 170.1, pruning 97.19"))
+			Expect(logMessages()).To(ContainElement("dry run: 5 file(s) of 4 version(s) would be deleted, run with --delete to delete them"))
+		})
+
+		It("deletes the files of the versions that are pruned with --delete", func() {
+			err := prune("--keep-per-product", "2", "--delete")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(removed).To(ConsistOf(
+				"[elastic-runtime,2.5.0]cf-2.5.0-build.1.pivotal",
+				"[elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal",
+			))
+			Expect(logMessages()).To(ContainElement("  deleted [elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal"))
+			Expect(logMessages()).To(ContainElement("stemcells-ubuntu-xenial: keeping 170.1, 97.19"))
+			Expect(logMessages()).To(ContainElement("deleted 2 file(s) of 2 version(s)"))
+		})
+
+		It("only prunes the products in the path of the bucket", func() {
+			stower.itemsList = []mockItem{
+				newMockItem("mirror/[elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal"),
+				newMockItem("mirror/[elastic-runtime,2.5.0]cf-2.5.0-build.1.pivotal"),
+				newMockItem("[elastic-runtime,2.3.0]cf-2.3.0-build.1.pivotal"),
+			}
+
+			err := prune("--keep-per-product", "1", "--s3-path", "/mirror/", "--delete")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(removed).To(Equal([]string{"mirror/[elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal"}))
+		})
+
+		It("prunes the stemcells downloaded with --stemcell-iaas, by the name of their file", func() {
+			stower.itemsList = []mockItem{
+				newMockItem("light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz"),
+				newMockItem("light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz.sha256"),
+				newMockItem("light-bosh-stemcell-170.1-google-kvm-ubuntu-xenial-go_agent.tgz"),
+				newMockItem("light-bosh-stemcell-97.19-aws-xen-hvm-ubuntu-xenial-go_agent.tgz"),
+			}
+
+			err := prune("--keep-per-product", "1", "--delete")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(removed).To(ConsistOf(
+				"light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz",
+				"light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz.sha256",
+			))
+			Expect(logMessages()).To(ContainElement("light-bosh-stemcell-*-google-kvm-ubuntu-xenial-go_agent.tgz: keeping 170.1, pruning 97.19"))
+			Expect(logMessages()).To(ContainElement("light-bosh-stemcell-*-aws-xen-hvm-ubuntu-xenial-go_agent.tgz: keeping 97.19"))
+		})
+
+		It("reads the flags from a config file", func() {
+			configFile, err := ioutil.TempFile("", "blobstore.yml")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(configFile.Name())
+
+			_, err = configFile.WriteString(`---
+keep-per-product: 3
+s3-bucket: bucket
+s3-access-key-id: access-key-id
+s3-secret-access-key: ((secret_access_key))
+s3-region-name: region
+s3-endpoint: endpoint
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configFile.Close()).To(Succeed())
+
+			err = command.Execute([]string{"prune", "--config", configFile.Name(), "--var", "secret_access_key=secret"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logMessages()).To(ContainElement("elastic-runtime: keeping 2.10.0, 2.6.0-build.2, 2.5.0, pruning 2.4.1"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error without a subcommand", func() {
+				err := command.Execute([]string{"--keep-per-product", "1"})
+				Expect(err).To(MatchError("expected a subcommand of blobstore: prune"))
+			})
+
+			It("returns an error when the flags cannot be parsed", func() {
+				err := prune("--unknown-flag")
+				Expect(err).To(MatchError(ContainSubstring("could not parse blobstore flags")))
+			})
+
+			It("returns an error without the number of versions to keep", func() {
+				err := prune()
+				Expect(err).To(MatchError("could not parse blobstore flags: prune requires --keep-per-product of at least 1"))
+			})
+
+			It("returns an error when the s3 client cannot be created", func() {
+				err := command.Execute([]string{"prune", "--keep-per-product", "1"})
+				Expect(err).To(MatchError(ContainSubstring("could not create an s3 client")))
+			})
+
+			It("returns an error when the bucket cannot be listed", func() {
+				stower.dialError = fmt.Errorf("could not dial")
+
+				err := prune("--keep-per-product", "1")
+				Expect(err).To(MatchError("could not dial"))
+			})
+
+			It("does not find the config file", func() {
+				err := command.Execute([]string{"prune", "--config", filepath.Join(os.TempDir(), "missing.yml")})
+				Expect(err).To(MatchError(ContainSubstring("could not parse blobstore flags")))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This command manages the products persisted in an s3 blobstore by download-product. Its subcommand prune keeps the newest --keep-per-product versions of each product, and reports the files of the other versions, which are only deleted with --delete. Usage: om blobstore prune [<args>]",
+				ShortDescription: "prunes the old versions of the products persisted in an s3 blobstore",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
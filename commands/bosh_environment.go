@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
+	"sort"
 	"strings"
 
 	"github.com/pivotal-cf/jhanda"
@@ -19,6 +21,7 @@ type BoshEnvironment struct {
 	Options         struct {
 		ShellType     string `long:"shell-type" description:"Prints for the given shell (posix|powershell)"`
 		SSHPrivateKey string `long:"ssh-private-key" short:"i" description:"Location of ssh private key to use to tunnel through the Ops Manager VM. Only necessary if bosh director is not reachable without a tunnel."`
+		OutputFile    string `long:"output-file" short:"o" description:"Writes the environment variables to the file, readable only by the current user, instead of printing them. The file can then be sourced."`
 	}
 }
 
@@ -42,11 +45,16 @@ func NewBoshEnvironment(service boshEnvironmentService, logger logger, opsmanHos
 	}
 }
 func (be BoshEnvironment) Target() string {
-	if strings.Contains(be.opsmanHost, protocolPrefix) {
-		parts := strings.SplitAfter(be.opsmanHost, protocolPrefix)
+	return targetHost(be.opsmanHost)
+}
+
+// targetHost returns the target of om without its protocol.
+func targetHost(opsmanHost string) string {
+	if strings.Contains(opsmanHost, protocolPrefix) {
+		parts := strings.SplitAfter(opsmanHost, protocolPrefix)
 		return parts[1]
 	}
-	return be.opsmanHost
+	return opsmanHost
 }
 
 func (be BoshEnvironment) Execute(args []string) error {
@@ -58,14 +66,67 @@ func (be BoshEnvironment) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
-	boshEnvironment, err := be.service.GetBoshEnvironment()
+	variables, err := boshEnvironmentVariables(be.service)
 	if err != nil {
 		return err
 	}
 
-	certificateAuthorities, err := be.service.ListCertificateAuthorities()
+	if be.Options.SSHPrivateKey != "" {
+		variables["BOSH_ALL_PROXY"] = fmt.Sprintf("ssh+socks5://ubuntu@%s:22?private-key=%s", be.Target(), be.Options.SSHPrivateKey)
+		variables["CREDHUB_PROXY"] = variables["BOSH_ALL_PROXY"]
+	}
+
+	if be.Options.OutputFile != "" {
+		err = ioutil.WriteFile(be.Options.OutputFile, []byte(strings.Join(be.renderVariables(renderer, variables), "\n")+"\n"), 0600)
+		if err != nil {
+			return fmt.Errorf("could not write the bosh environment variables to %s: %s", be.Options.OutputFile, err)
+		}
+
+		be.logger.Printf("Wrote the bosh environment variables to %s\n", be.Options.OutputFile)
+		return nil
+	}
+
+	for _, line := range be.renderVariables(renderer, variables) {
+		be.logger.Println(line)
+	}
+
+	return nil
+}
+
+func (be BoshEnvironment) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This prints bosh environment variables to target bosh director. You can invoke it directly to see its output, or use it directly with an evaluate-type command:\nOn posix system: eval \"$(om bosh-env)\"\nOn powershell: iex $(om bosh-env | Out-String)",
+		ShortDescription: "prints bosh environment variables",
+		Flags:            be.Options,
+	}
+}
+
+func (be BoshEnvironment) renderVariables(renderer renderers.Renderer, variables map[string]string) []string {
+	var names []string
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, renderer.RenderEnvironmentVariable(name, variables[name]))
+	}
+
+	return lines
+}
+
+// boshEnvironmentVariables returns the variables of the bosh and credhub clis
+// to target the director with its client credentials.
+func boshEnvironmentVariables(service boshEnvironmentService) (map[string]string, error) {
+	boshEnvironment, err := service.GetBoshEnvironment()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	certificateAuthorities, err := service.ListCertificateAuthorities()
+	if err != nil {
+		return nil, err
 	}
 
 	var boshCACerts string
@@ -90,25 +151,5 @@ func (be BoshEnvironment) Execute(args []string) error {
 	variables["CREDHUB_SERVER"] = fmt.Sprintf("https://%s:8844", boshEnvironment.Environment)
 	variables["CREDHUB_CA_CERT"] = boshCACerts
 
-	if be.Options.SSHPrivateKey != "" {
-		variables["BOSH_ALL_PROXY"] = fmt.Sprintf("ssh+socks5://ubuntu@%s:22?private-key=%s", be.Target(), be.Options.SSHPrivateKey)
-		variables["CREDHUB_PROXY"] = variables["BOSH_ALL_PROXY"]
-	}
-	be.renderVariables(renderer, variables)
-
-	return nil
-}
-
-func (be BoshEnvironment) Usage() jhanda.Usage {
-	return jhanda.Usage{
-		Description:      "This prints bosh environment variables to target bosh director. You can invoke it directly to see its output, or use it directly with an evaluate-type command:\nOn posix system: eval \"$(om bosh-env)\"\nOn powershell: iex $(om bosh-env | Out-String)",
-		ShortDescription: "prints bosh environment variables",
-		Flags:            be.Options,
-	}
-}
-
-func (be BoshEnvironment) renderVariables(renderer renderers.Renderer, variables map[string]string) {
-	for k, v := range variables {
-		be.logger.Println(renderer.RenderEnvironmentVariable(k, v))
-	}
+	return variables, nil
 }
@@ -2,6 +2,9 @@ package commands_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/pivotal-cf/jhanda"
@@ -113,6 +116,56 @@ var _ = Describe("bosh-env", func() {
 				Expect(err).ShouldNot(HaveOccurred())
 				Expect(stdout.PrintlnCallCount()).To(Equal(8))
 			})
+
+			It("prints the variables sorted by name", func() {
+				err := command.Execute([]string{})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(stdout.PrintlnArgsForCall(0)).To(Equal([]interface{}{"export BOSH_CA_CERT='-----BEGIN CERTIFICATE-----\nMIIC+zCCAeOgAwIBAgI....\n'"}))
+				Expect(stdout.PrintlnArgsForCall(1)).To(Equal([]interface{}{"export BOSH_CLIENT=opsmanager_client"}))
+				Expect(stdout.PrintlnArgsForCall(7)).To(Equal([]interface{}{"export CREDHUB_SERVER=https://10.0.0.10:8844"}))
+			})
+		})
+
+		Describe("Execute with an output file", func() {
+			var outputDir string
+
+			BeforeEach(func() {
+				var err error
+				outputDir, err = ioutil.TempDir("", "bosh-env")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(outputDir)
+			})
+
+			It("writes the variables to the file instead of printing them", func() {
+				outputFile := filepath.Join(outputDir, "bosh.env")
+
+				err := command.Execute([]string{"--output-file", outputFile})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(stdout.PrintlnCallCount()).To(Equal(0))
+				format, args := stdout.PrintfArgsForCall(0)
+				Expect(fmt.Sprintf(format, args...)).To(Equal(fmt.Sprintf("Wrote the bosh environment variables to %s\n", outputFile)))
+
+				contents, err := ioutil.ReadFile(outputFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(HavePrefix("export BOSH_CA_CERT='-----BEGIN CERTIFICATE-----\nMIIC+zCCAeOgAwIBAgI....\n'\nexport BOSH_CLIENT=opsmanager_client\n"))
+				Expect(string(contents)).To(HaveSuffix("export CREDHUB_SERVER=https://10.0.0.10:8844\n"))
+
+				info, err := os.Stat(outputFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			})
+
+			It("returns an error when the file cannot be written", func() {
+				outputFile := filepath.Join(outputDir, "missing", "bosh.env")
+
+				err := command.Execute([]string{"--output-file", outputFile})
+				Expect(err).To(MatchError(ContainSubstring("could not write the bosh environment variables to " + outputFile)))
+			})
 		})
 	})
 
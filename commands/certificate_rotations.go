@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type CertificateRotations struct {
+	service   certificateRotationsService
+	presenter presenters.FormattedPresenter
+	Options   struct {
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/certificate_rotations_service.go --fake-name CertificateRotationsService . certificateRotationsService
+
+type certificateRotationsService interface {
+	ListCertificateRotations() (api.CertificateRotationsOutput, error)
+}
+
+func NewCertificateRotations(service certificateRotationsService, presenter presenters.FormattedPresenter) CertificateRotations {
+	return CertificateRotations{
+		service:   service,
+		presenter: presenter,
+	}
+}
+
+func (c CertificateRotations) Execute(args []string) error {
+	if _, err := jhanda.Parse(&c.Options, args); err != nil {
+		return fmt.Errorf("could not parse certificate-rotations flags: %s", err)
+	}
+
+	output, err := c.service.ListCertificateRotations()
+	if err != nil {
+		return fmt.Errorf("could not list the certificate rotations: %s", err)
+	}
+
+	c.presenter.SetFormat(c.Options.Format)
+	c.presenter.PresentCertificateRotations(output.Rotations)
+
+	return nil
+}
+
+func (c CertificateRotations) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command lists the rotations of the NATS and the BOSH DNS certificates of the director, with their current phase and whether apply-changes is required before the next phase.",
+		ShortDescription: "lists the rotations of the NATS and BOSH DNS certificates of the director",
+		Flags:            c.Options,
+	}
+}
@@ -0,0 +1,74 @@
+package commands_test
+
+import (
+	"errors"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CertificateRotations", func() {
+	var (
+		fakeService   *fakes.CertificateRotationsService
+		fakePresenter *presenterfakes.FormattedPresenter
+		command       commands.CertificateRotations
+		rotations     []api.CertificateRotation
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.CertificateRotationsService{}
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		command = commands.NewCertificateRotations(fakeService, fakePresenter)
+
+		rotations = []api.CertificateRotation{
+			{Type: "nats", Phase: "created", ApplyChangesRequired: true},
+			{Type: "bosh-dns", Phase: "none"},
+		}
+		fakeService.ListCertificateRotationsReturns(api.CertificateRotationsOutput{Rotations: rotations}, nil)
+	})
+
+	It("presents the certificate rotations", func() {
+		err := command.Execute([]string{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("table"))
+		Expect(fakePresenter.PresentCertificateRotationsCallCount()).To(Equal(1))
+		Expect(fakePresenter.PresentCertificateRotationsArgsForCall(0)).To(Equal(rotations))
+	})
+
+	It("presents the certificate rotations in the format", func() {
+		err := command.Execute([]string{"--format", "json"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the flags cannot be parsed", func() {
+			err := command.Execute([]string{"--unknown-flag"})
+			Expect(err).To(MatchError(ContainSubstring("could not parse certificate-rotations flags")))
+		})
+
+		It("returns an error when the rotations cannot be listed", func() {
+			fakeService.ListCertificateRotationsReturns(api.CertificateRotationsOutput{}, errors.New("network error"))
+
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("could not list the certificate rotations: network error"))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			usage := command.Usage()
+			Expect(usage.ShortDescription).To(Equal("lists the rotations of the NATS and BOSH DNS certificates of the director"))
+			Expect(usage.Flags).To(BeAssignableToTypeOf(command.Options))
+			Expect(usage).To(BeAssignableToTypeOf(jhanda.Usage{}))
+		})
+	})
+})
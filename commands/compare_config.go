@@ -0,0 +1,247 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/configparser"
+	"gopkg.in/yaml.v2"
+)
+
+// obscuredCredential stands in for the credentials of the staged product,
+// which cannot be read back from the Ops Manager and are not compared.
+const obscuredCredential = "***"
+
+// compareConfigSections lists the sections of a configure-product config
+// that are compared against the staged product.
+var compareConfigSections = []string{"product-properties", "network-properties", "resource-config", "errand-config"}
+
+// compareDirectorSections lists the sections of a configure-director config
+// that are compared against the staged director.
+var compareDirectorSections = []string{"az-configuration", "network-assignment", "networks-configuration", "properties-configuration", "resource-configuration", "vmextensions-configuration"}
+
+// directorPlaceholderPattern matches the placeholders that
+// staged-director-config puts in place of the credentials of the director.
+var directorPlaceholderPattern = regexp.MustCompile(`^\(\(.+\)\)$`)
+
+type CompareConfig struct {
+	environFunc func() []string
+	service     compareConfigService
+	logger      logger
+	Options     struct {
+		Product    string   `long:"product-name" short:"p"                 description:"name of product"`
+		Director   bool     `long:"director"                               description:"compare a configure-director config against the staged director instead of a product"`
+		ConfigFile string   `long:"config"       short:"c" required:"true" description:"path to yml file containing the config of the product (see docs/configure-product/README.md for format), or of the director with --director"`
+		VarsFile   []string `long:"vars-file"    short:"l"                 description:"Load variables from a YAML file"`
+		VarsEnv    []string `long:"vars-env"                               description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars       []string `long:"var"                                    description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore  string   `long:"vars-store"                             description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		OpsFile    []string `long:"ops-file"     short:"o"                 description:"YAML operations file"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/compare_config_service.go --fake-name CompareConfigService . compareConfigService
+type compareConfigService interface {
+	stagedDirectorConfigService
+
+	GetStagedProductByName(product string) (api.StagedProductsFindOutput, error)
+	GetStagedProductJobResourceConfig(productGUID, jobGUID string) (api.JobProperties, error)
+	GetStagedProductNetworksAndAZs(product string) (map[string]interface{}, error)
+	GetStagedProductProperties(product string) (map[string]api.ResponseProperty, error)
+	ListStagedProductJobs(productGUID string) (map[string]string, error)
+	ListStagedProductErrands(productID string) (api.ErrandsListOutput, error)
+}
+
+func NewCompareConfig(environFunc func() []string, service compareConfigService, logger logger) CompareConfig {
+	return CompareConfig{
+		environFunc: environFunc,
+		service:     service,
+		logger:      logger,
+	}
+}
+
+func (cc CompareConfig) Execute(args []string) error {
+	if _, err := jhanda.Parse(&cc.Options, args); err != nil {
+		return fmt.Errorf("could not parse compare-config flags: %s", err)
+	}
+
+	if cc.Options.Product == "" && !cc.Options.Director {
+		return fmt.Errorf("could not parse compare-config flags: --product-name or --director is required")
+	}
+
+	if cc.Options.Product != "" && cc.Options.Director {
+		return fmt.Errorf("could not parse compare-config flags: --product-name cannot be used with --director")
+	}
+
+	configContents, err := interpolate(interpolateOptions{
+		templateFile: cc.Options.ConfigFile,
+		varsFiles:    cc.Options.VarsFile,
+		environFunc:  cc.environFunc,
+		varsEnvs:     cc.Options.VarsEnv,
+		vars:         cc.Options.Vars,
+		varsStore:    cc.Options.VarsStore,
+		opsFiles:     cc.Options.OpsFile,
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	var local map[interface{}]interface{}
+	err = yaml.Unmarshal(configContents, &local)
+	if err != nil {
+		return fmt.Errorf("%s could not be parsed as valid configuration: %s", cc.Options.ConfigFile, err)
+	}
+
+	name, sections := cc.Options.Product, compareConfigSections
+	if cc.Options.Director {
+		name, sections = "the director", compareDirectorSections
+	}
+
+	staged, err := cc.stagedConfig()
+	if err != nil {
+		return fmt.Errorf("could not retrieve the staged config of %s: %s", name, err)
+	}
+
+	var lines []string
+	for _, section := range sections {
+		var changes []string
+		compareValue("", local[section], staged[section], &changes)
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		lines = append(lines, section+":")
+		lines = append(lines, changes...)
+	}
+
+	if len(lines) == 0 {
+		cc.logger.Printf("the staged configuration of %s matches %s", name, cc.Options.ConfigFile)
+		return nil
+	}
+
+	for _, line := range lines {
+		cc.logger.Println(line)
+	}
+
+	return fmt.Errorf("the staged configuration of %s has drifted from %s", name, cc.Options.ConfigFile)
+}
+
+// stagedConfig is the staged config of the product, or the one that
+// staged-director-config prints with --director. It goes through yaml so
+// that its values have the same types as the ones of the local config.
+func (cc CompareConfig) stagedConfig() (map[interface{}]interface{}, error) {
+	var stagedContents []byte
+	if cc.Options.Director {
+		var output bytes.Buffer
+		err := NewStagedDirectorConfig(cc.service, log.New(&output, "", 0)).Execute([]string{"--include-placeholders"})
+		if err != nil {
+			return nil, err
+		}
+		stagedContents = output.Bytes()
+	} else {
+		stagedConfig, err := stagedProductConfig(cc.service, cc.Options.Product, func(string) configparser.CredentialHandler {
+			return obscuredCredentialHandler
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		stagedContents, err = yaml.Marshal(stagedConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the staged config: %s", err) // un-tested
+		}
+	}
+
+	var staged map[interface{}]interface{}
+	err := yaml.Unmarshal(stagedContents, &staged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the staged config: %s", err) // un-tested
+	}
+
+	for key, value := range staged {
+		staged[key] = obscurePlaceholders(value)
+	}
+
+	return staged, nil
+}
+
+// obscurePlaceholders replaces the placeholders of the credentials of the
+// director, which are not compared like the credentials of the products.
+func obscurePlaceholders(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if directorPlaceholderPattern.MatchString(v) {
+			return obscuredCredential
+		}
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			v[key] = obscurePlaceholders(child)
+		}
+	case []interface{}:
+		for index, child := range v {
+			v[index] = obscurePlaceholders(child)
+		}
+	}
+
+	return value
+}
+
+func obscuredCredentialHandler(configparser.PropertyName, api.ResponseProperty) (map[string]interface{}, error) {
+	return map[string]interface{}{"value": obscuredCredential}, nil
+}
+
+// compareValue only compares what is set in the local config: anything else
+// the staged product has, such as defaults, is not drift.
+func compareValue(path string, local, staged interface{}, changes *[]string) {
+	if local == nil || staged == obscuredCredential || reflect.DeepEqual(local, staged) {
+		return
+	}
+
+	if staged == nil {
+		*changes = append(*changes, fmt.Sprintf("  - %s: not staged", path))
+		return
+	}
+
+	localMap, localIsMap := asMap(local)
+	stagedMap, stagedIsMap := asMap(staged)
+	if localIsMap && stagedIsMap {
+		for _, key := range mapKeys(localMap) {
+			compareValue(joinPath(path, key), localMap[key], stagedMap[key], changes)
+		}
+		return
+	}
+
+	localList, localIsList := local.([]interface{})
+	stagedList, stagedIsList := staged.([]interface{})
+	if localIsList && stagedIsList && len(localList) == len(stagedList) {
+		for index := range localList {
+			compareValue(joinPath(path, strconv.Itoa(index)), localList[index], stagedList[index], changes)
+		}
+		return
+	}
+
+	if isSecretPath("", path) {
+		*changes = append(*changes, fmt.Sprintf("  ~ %s: (redacted)", path))
+		return
+	}
+
+	*changes = append(*changes, fmt.Sprintf("  ~ %s: %s -> %s", path, formatValue(local), formatValue(staged)))
+}
+
+func (cc CompareConfig) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This authenticated command interpolates a configure-product config and compares it against the staged configuration of the product. " +
+			"Only the properties, networks, resources and errands set in the config are compared and credentials are ignored. " +
+			"With --director, a configure-director config is compared against the staged director, as staged-director-config prints it. " +
+			"It exits non-zero if the staged configuration has drifted from the config.",
+		ShortDescription: "compares a product or director config against its staged configuration",
+		Flags:            cc.Options,
+	}
+}
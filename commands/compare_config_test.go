@@ -0,0 +1,317 @@
+package commands_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CompareConfig", func() {
+	var (
+		logger     *fakes.Logger
+		service    *fakes.CompareConfigService
+		command    commands.CompareConfig
+		configFile *os.File
+	)
+
+	writeConfig := func(contents string) {
+		_, err := configFile.WriteString(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configFile.Close()).To(Succeed())
+	}
+
+	loggedLines := func() []string {
+		var lines []string
+		for i := 0; i < logger.PrintlnCallCount(); i++ {
+			lines = append(lines, logger.PrintlnArgsForCall(i)[0].(string))
+		}
+		return lines
+	}
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		service = &fakes.CompareConfigService{}
+
+		service.GetStagedProductByNameReturns(api.StagedProductsFindOutput{
+			Product: api.StagedProduct{GUID: "some-product-guid"},
+		}, nil)
+		service.GetStagedProductPropertiesReturns(map[string]api.ResponseProperty{
+			".properties.some-string-property": {
+				Value:        "some-value",
+				Configurable: true,
+			},
+			".properties.some-default-property": {
+				Value:        "some-default",
+				Configurable: true,
+			},
+			".properties.some-secret-property": {
+				Value:        map[string]interface{}{"secret": "***"},
+				Type:         "secret",
+				Configurable: true,
+				IsCredential: true,
+			},
+		}, nil)
+		service.GetStagedProductNetworksAndAZsReturns(map[string]interface{}{
+			"singleton_availability_zone": map[string]string{"name": "az-one"},
+		}, nil)
+		service.ListStagedProductJobsReturns(map[string]string{
+			"some-job": "some-job-guid",
+		}, nil)
+		service.GetStagedProductJobResourceConfigReturns(api.JobProperties{
+			Instances:    1.0,
+			InstanceType: api.InstanceType{ID: "automatic"},
+		}, nil)
+		service.ListStagedProductErrandsReturns(api.ErrandsListOutput{
+			Errands: []api.Errand{
+				{Name: "smoke-tests", PostDeploy: true},
+			},
+		}, nil)
+
+		var err error
+		configFile, err = ioutil.TempFile("", "product.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		command = commands.NewCompareConfig(func() []string { return nil }, service, logger)
+	})
+
+	AfterEach(func() {
+		os.Remove(configFile.Name())
+	})
+
+	Describe("Execute", func() {
+		Context("when the staged configuration matches the config", func() {
+			It("succeeds", func() {
+				writeConfig(`---
+product-name: some-product
+product-properties:
+  .properties.some-string-property:
+    value: ((string-value))
+  .properties.some-secret-property:
+    value:
+      secret: some-password
+network-properties:
+  singleton_availability_zone:
+    name: az-one
+resource-config:
+  some-job:
+    instances: 1
+errand-config:
+  smoke-tests:
+    post-deploy-state: true
+`)
+
+				command = commands.NewCompareConfig(func() []string { return []string{"OM_VAR_string-value=some-value"} }, service, logger)
+				err := command.Execute([]string{
+					"--product-name", "some-product",
+					"--config", configFile.Name(),
+					"--vars-env", "OM_VAR",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.GetStagedProductByNameArgsForCall(0)).To(Equal("some-product"))
+				Expect(logger.PrintlnCallCount()).To(Equal(0))
+
+				format, content := logger.PrintfArgsForCall(logger.PrintfCallCount() - 1)
+				Expect(format).To(Equal("the staged configuration of %s matches %s"))
+				Expect(content).To(Equal([]interface{}{"some-product", configFile.Name()}))
+			})
+		})
+
+		Context("when the staged configuration has drifted from the config", func() {
+			It("prints the differences and returns an error", func() {
+				writeConfig(`---
+product-name: some-product
+product-properties:
+  .properties.some-string-property:
+    value: some-other-value
+  .properties.some-missing-property:
+    value: some-value
+network-properties:
+  singleton_availability_zone:
+    name: az-two
+resource-config:
+  some-job:
+    instances: 1
+errand-config:
+  smoke-tests:
+    post-deploy-state: false
+`)
+
+				err := command.Execute([]string{
+					"--product-name", "some-product",
+					"--config", configFile.Name(),
+				})
+				Expect(err).To(MatchError("the staged configuration of some-product has drifted from " + configFile.Name()))
+
+				Expect(loggedLines()).To(Equal([]string{
+					"product-properties:",
+					"  - .properties.some-missing-property: not staged",
+					"  ~ .properties.some-string-property.value: some-other-value -> some-value",
+					"network-properties:",
+					"  ~ singleton_availability_zone.name: az-two -> az-one",
+					"errand-config:",
+					"  ~ smoke-tests.post-deploy-state: false -> true",
+				}))
+			})
+		})
+
+		Context("with --director", func() {
+			BeforeEach(func() {
+				service.GetStagedDirectorAvailabilityZonesReturns(api.AvailabilityZonesOutput{
+					AvailabilityZones: []api.AvailabilityZoneOutput{{Name: "az-one"}},
+				}, nil)
+				service.GetStagedDirectorPropertiesReturns(map[string]map[string]interface{}{
+					"director_configuration": {
+						"ntp_servers_string": "ntp.example.com",
+						"max_threads":        5,
+					},
+					"security_configuration": {
+						"trusted_certificates":             "some-certificate",
+						"opsmanager_root_ca_trusted_certs": false,
+					},
+					"syslog_configuration": {
+						"enabled":  true,
+						"password": "***",
+					},
+				}, nil)
+				service.GetStagedDirectorNetworksReturns(api.NetworksConfigurationOutput{
+					ICMP:     false,
+					Networks: []api.NetworkConfigurationOutput{{Name: "some-network"}},
+				}, nil)
+				service.ListStagedVMExtensionsReturns([]api.VMExtension{
+					{Name: "some-extension", CloudProperties: map[string]interface{}{"some-key": "some-value"}},
+				}, nil)
+			})
+
+			It("compares the config against the staged director", func() {
+				writeConfig(`---
+az-configuration:
+- name: az-one
+properties-configuration:
+  director_configuration:
+    ntp_servers_string: ntp.example.com
+  syslog_configuration:
+    enabled: true
+    password: some-password
+resource-configuration:
+  some-job:
+    instances: 1
+vmextensions-configuration:
+- name: some-extension
+  cloud_properties:
+    some-key: some-value
+`)
+
+				err := command.Execute([]string{
+					"--director",
+					"--config", configFile.Name(),
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.GetStagedProductByNameArgsForCall(0)).To(Equal("p-bosh"))
+				Expect(service.GetStagedDirectorPropertiesArgsForCall(0)).To(BeTrue())
+				Expect(logger.PrintlnCallCount()).To(Equal(0))
+
+				format, content := logger.PrintfArgsForCall(logger.PrintfCallCount() - 1)
+				Expect(format).To(Equal("the staged configuration of %s matches %s"))
+				Expect(content).To(Equal([]interface{}{"the director", configFile.Name()}))
+			})
+
+			It("prints the differences and returns an error when the staged director has drifted", func() {
+				writeConfig(`---
+az-configuration:
+- name: az-one
+- name: az-two
+properties-configuration:
+  director_configuration:
+    max_threads: 10
+networks-configuration:
+  icmp_checks_enabled: true
+`)
+
+				err := command.Execute([]string{
+					"--director",
+					"--config", configFile.Name(),
+				})
+				Expect(err).To(MatchError("the staged configuration of the director has drifted from " + configFile.Name()))
+
+				Expect(loggedLines()).To(Equal([]string{
+					"az-configuration:",
+					"  - az-two: not staged",
+					"networks-configuration:",
+					"  ~ icmp_checks_enabled: true -> false",
+					"properties-configuration:",
+					"  ~ director_configuration.max_threads: 10 -> 5",
+				}))
+			})
+
+			It("returns an error when the staged director cannot be retrieved", func() {
+				writeConfig("az-configuration: []")
+				service.GetStagedDirectorPropertiesReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{
+					"--director",
+					"--config", configFile.Name(),
+				})
+				Expect(err).To(MatchError("could not retrieve the staged config of the director: some error"))
+			})
+		})
+
+		Context("failure cases", func() {
+			Context("when neither --product-name nor --director is provided", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{"--config", configFile.Name()})
+					Expect(err).To(MatchError("could not parse compare-config flags: --product-name or --director is required"))
+				})
+			})
+
+			Context("when both --product-name and --director are provided", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{
+						"--product-name", "some-product",
+						"--director",
+						"--config", configFile.Name(),
+					})
+					Expect(err).To(MatchError("could not parse compare-config flags: --product-name cannot be used with --director"))
+				})
+			})
+
+			Context("when an unknown flag is provided", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{"--badflag"})
+					Expect(err).To(MatchError("could not parse compare-config flags: flag provided but not defined: -badflag"))
+				})
+			})
+
+			Context("when the config file cannot be interpolated", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{
+						"--product-name", "some-product",
+						"--config", "some/missing/file.yml",
+					})
+					Expect(err).To(MatchError(ContainSubstring("open some/missing/file.yml")))
+				})
+			})
+
+			Context("when the staged config cannot be retrieved", func() {
+				It("returns an error", func() {
+					writeConfig("product-name: some-product")
+					service.GetStagedProductByNameReturns(api.StagedProductsFindOutput{}, errors.New("product not found"))
+
+					err := command.Execute([]string{
+						"--product-name", "some-product",
+						"--config", configFile.Name(),
+					})
+					Expect(err).To(MatchError("could not retrieve the staged config of some-product: product not found"))
+				})
+			})
+		})
+	})
+})
@@ -2,22 +2,45 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 
+	pivnetlog "github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/kiln/proofing"
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/config"
 	"github.com/pivotal-cf/om/configparser"
+	"github.com/pivotal-cf/pivnet-cli/filter"
 	"gopkg.in/yaml.v2"
 )
 
+const (
+	ConfigTemplateProductFilename     = "product.yml"
+	ConfigTemplateSecretsVarsFilename = "secrets-vars.yml"
+)
+
+var placeholderPattern = regexp.MustCompile(`\(\(([-\w]+)\.([-\w]+)\)\)`)
+
 type ConfigTemplate struct {
 	metadataExtractor metadataExtractor
+	pivnetLogger      pivnetlog.Logger
+	progressWriter    io.Writer
+	pivnetFactory     PivnetFactory
 	logger            logger
 	Options           struct {
-		Product             string `long:"product"  short:"p"  required:"true" description:"path to product to generate config template for"`
-		IncludePlaceholders bool   `long:"include-placeholders" short:"r" description:"replace obscured credentials with interpolatable placeholders"`
+		Product             string `long:"product"              short:"p"                         description:"path to product to generate config template for. deprecated: use --pivotal-file"`
+		PivotalFile         string `long:"pivotal-file"                                           description:"path to the .pivotal file of the product to generate config template for"`
+		PivnetProductSlug   string `long:"pivnet-product-slug"                                    description:"slug of the product on Pivotal Network to generate config template for, instead of a local .pivotal file"`
+		ProductVersion      string `long:"product-version"                                        description:"version of the product on Pivotal Network"`
+		PivnetToken         string `long:"pivnet-api-token"                                       description:"API token to use when downloading the product from Pivotal Network"`
+		PivnetFileGlob      string `long:"pivnet-file-glob"                default:"*.pivotal"    description:"glob to match the .pivotal file within the Pivotal Network product"`
+		OutputDirectory     string `long:"output-directory"     short:"o"                         description:"write the template to product.yml and the credentials it references to secrets-vars.yml in this directory"`
+		IncludePlaceholders bool   `long:"include-placeholders" short:"r"                         description:"replace obscured credentials with interpolatable placeholders"`
 	}
 }
 
@@ -26,9 +49,12 @@ type propertyBluePrintPair struct {
 	proofing.PropertyBlueprint
 }
 
-func NewConfigTemplate(metadataExtractor metadataExtractor, logger logger) ConfigTemplate {
+func NewConfigTemplate(metadataExtractor metadataExtractor, pivnetLogger pivnetlog.Logger, progressWriter io.Writer, pivnetFactory PivnetFactory, logger logger) ConfigTemplate {
 	return ConfigTemplate{
 		metadataExtractor: metadataExtractor,
+		pivnetLogger:      pivnetLogger,
+		progressWriter:    progressWriter,
+		pivnetFactory:     pivnetFactory,
 		logger:            logger,
 	}
 }
@@ -38,7 +64,25 @@ func (ct ConfigTemplate) Execute(args []string) error {
 		return fmt.Errorf("could not parse config-template flags: %s", err)
 	}
 
-	extractedMetadata, err := ct.metadataExtractor.ExtractMetadata(ct.Options.Product)
+	if ct.Options.PivotalFile == "" {
+		ct.Options.PivotalFile = ct.Options.Product
+	}
+
+	err := ct.validate()
+	if err != nil {
+		return err
+	}
+
+	if ct.Options.PivnetProductSlug != "" {
+		var cleanup func()
+		ct.Options.PivotalFile, cleanup, err = ct.downloadPivotalFile()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
+
+	extractedMetadata, err := ct.metadataExtractor.ExtractMetadata(ct.Options.PivotalFile)
 	if err != nil {
 		return fmt.Errorf("could not extract metadata: %s", err)
 	}
@@ -78,7 +122,93 @@ func (ct ConfigTemplate) Execute(args []string) error {
 	}
 
 	// post-processing
-	ct.logger.Println(concatenateRequiredProperties(output, propertyPairs))
+	productTemplate := concatenateRequiredProperties(output, propertyPairs)
+
+	if ct.Options.OutputDirectory == "" {
+		ct.logger.Println(productTemplate)
+		return nil
+	}
+
+	return ct.writeOutputDirectory(productTemplate)
+}
+
+func (ct ConfigTemplate) validate() error {
+	if ct.Options.PivotalFile == "" && ct.Options.PivnetProductSlug == "" {
+		return fmt.Errorf("please provide either --pivotal-file or --pivnet-product-slug")
+	}
+
+	if ct.Options.PivotalFile != "" && ct.Options.PivnetProductSlug != "" {
+		return fmt.Errorf("cannot use both --pivotal-file and --pivnet-product-slug; please choose one or the other")
+	}
+
+	if ct.Options.PivnetProductSlug != "" && (ct.Options.ProductVersion == "" || ct.Options.PivnetToken == "") {
+		return fmt.Errorf("--product-version and --pivnet-api-token are required with --pivnet-product-slug")
+	}
+
+	return nil
+}
+
+// downloadPivotalFile downloads the .pivotal file of the product to a temporary
+// file, which is removed by the returned cleanup function.
+func (ct ConfigTemplate) downloadPivotalFile() (string, func(), error) {
+	client := NewPivnetClient(ct.pivnetLogger, ct.progressWriter, ct.pivnetFactory, ct.Options.PivnetToken, filter.NewFilter(ct.pivnetLogger))
+
+	fileArtifact, err := client.GetLatestProductFile(ct.Options.PivnetProductSlug, ct.Options.ProductVersion, ct.Options.PivnetFileGlob)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not find the product on Pivotal Network: %s", err)
+	}
+
+	file, err := ioutil.TempFile("", "config-template")
+	if err != nil {
+		return "", nil, err // un-tested
+	}
+	defer file.Close()
+
+	cleanup := func() { os.Remove(file.Name()) }
+
+	err = client.DownloadProductToFile(fileArtifact, file)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("could not download product: %s", err)
+	}
+
+	return file.Name(), cleanup, nil
+}
+
+// writeOutputDirectory writes the template along with a vars file for the
+// placeholders of its credentials, so secrets can be kept out of product.yml.
+func (ct ConfigTemplate) writeOutputDirectory(productTemplate string) error {
+	err := os.MkdirAll(ct.Options.OutputDirectory, 0755)
+	if err != nil {
+		return fmt.Errorf("could not create output directory: %s", err)
+	}
+
+	productFile := filepath.Join(ct.Options.OutputDirectory, ConfigTemplateProductFilename)
+	err = ioutil.WriteFile(productFile, []byte(productTemplate), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write %s: %s", productFile, err)
+	}
+
+	secretsVars := map[string]map[string]string{}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(productTemplate, -1) {
+		if _, ok := secretsVars[match[1]]; !ok {
+			secretsVars[match[1]] = map[string]string{}
+		}
+		secretsVars[match[1]][match[2]] = ""
+	}
+
+	contents, err := yaml.Marshal(secretsVars)
+	if err != nil {
+		return fmt.Errorf("could not marshal secrets vars: %s", err) // un-tested
+	}
+
+	secretsVarsFile := filepath.Join(ct.Options.OutputDirectory, ConfigTemplateSecretsVarsFilename)
+	err = ioutil.WriteFile(secretsVarsFile, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write %s: %s", secretsVarsFile, err)
+	}
+
+	ct.logger.Printf("wrote %s and %s\n", productFile, secretsVarsFile)
 
 	return nil
 }
@@ -93,8 +223,12 @@ func concatenateRequiredProperties(output []byte, propertyPairs []propertyBluePr
 	lines := strings.Split(string(output), "\n")
 	for i, line := range lines {
 		propertyName := strings.TrimSpace(strings.Split(line, ":")[0])
-		if v, ok := namePropertyMaps[propertyName]; ok && v.Required {
-			lines[i+1] = lines[i+1] + " # required"
+		if v, ok := namePropertyMaps[propertyName]; ok && i+1 < len(lines) {
+			status := "optional"
+			if v.Required {
+				status = "required"
+			}
+			lines[i+1] = fmt.Sprintf("%s # %s, type: %s", lines[i+1], status, v.Type)
 		}
 	}
 
@@ -103,14 +237,14 @@ func concatenateRequiredProperties(output []byte, propertyPairs []propertyBluePr
 
 func (ct ConfigTemplate) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "**EXPERIMENTAL** This command generates a configuration template that can be passed in to om configure-product",
+		Description:      "**EXPERIMENTAL** This command generates a configuration template that can be passed in to om configure-product, from a local .pivotal file or one downloaded from Pivotal Network. Every property is annotated with its type and whether it is required, and has its default value. With --output-directory, the credentials are written as placeholders to a separate vars file",
 		ShortDescription: "**EXPERIMENTAL** generates a config template for the product",
 		Flags:            ct.Options,
 	}
 }
 
 func (ct ConfigTemplate) chooseCredentialHandler() configparser.CredentialHandler {
-	if ct.Options.IncludePlaceholders {
+	if ct.Options.IncludePlaceholders || ct.Options.OutputDirectory != "" {
 		return configparser.PlaceholderHandler()
 	}
 
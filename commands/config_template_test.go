@@ -2,8 +2,15 @@ package commands_test
 
 import (
 	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/pivotal-cf/go-pivnet"
+	log "github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logger/loggerfakes"
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
@@ -15,9 +22,11 @@ import (
 
 var _ = Describe("ConfigTemplate", func() {
 	var (
-		logger            *fakes.Logger
-		metadataExtractor *fakes.MetadataExtractor
-		command           commands.ConfigTemplate
+		logger               *fakes.Logger
+		metadataExtractor    *fakes.MetadataExtractor
+		fakePivnetDownloader *fakes.PivnetDownloader
+		pivnetConfig         pivnet.ClientConfig
+		command              commands.ConfigTemplate
 	)
 
 	runCommand := func() []interface{} {
@@ -38,7 +47,12 @@ var _ = Describe("ConfigTemplate", func() {
 	BeforeEach(func() {
 		logger = &fakes.Logger{}
 		metadataExtractor = &fakes.MetadataExtractor{}
-		command = commands.NewConfigTemplate(metadataExtractor, logger)
+		fakePivnetDownloader = &fakes.PivnetDownloader{}
+		fakePivnetFactory := func(config pivnet.ClientConfig, _ log.Logger) commands.PivnetDownloader {
+			pivnetConfig = config
+			return fakePivnetDownloader
+		}
+		command = commands.NewConfigTemplate(metadataExtractor, &loggerfakes.FakeLogger{}, GinkgoWriter, fakePivnetFactory, logger)
 	})
 
 	Describe("Execute", func() {
@@ -92,6 +106,23 @@ product-properties:
 		})
 
 		Context("optional property", func() {
+			It("writes '# optional' next to the property", func() {
+				metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+					Raw: []byte(`---
+property_blueprints:
+- name: some-name
+  type: boolean
+  default: true
+  optional: true
+  configurable: true
+`),
+				}, nil)
+
+				output := runCommand()
+				lines := strings.Split(output[0].(string), "\n")
+				Expect(strings.TrimSpace(lines[2])).To(Equal("value: true # optional, type: boolean"))
+			})
+
 			It("does not write '# required' next to the property", func() {
 				metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
 					Raw: []byte(`---
@@ -125,7 +156,7 @@ property_blueprints:
 				output := runCommand()
 				lines := strings.Split(output[0].(string), "\n")
 				valueOutput := strings.TrimSpace(lines[2])
-				Expect(valueOutput).To(Equal("value: true # required"))
+				Expect(valueOutput).To(Equal("value: true # required, type: boolean"))
 			})
 		})
 
@@ -408,10 +439,135 @@ product-properties:
 		})
 	})
 
+	Describe("with --pivotal-file flag", func() {
+		It("extracts the metadata of the .pivotal file", func() {
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{Raw: []byte(`{}`)}, nil)
+
+			err := command.Execute([]string{"--pivotal-file", "/path/to/a/product.pivotal"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(metadataExtractor.ExtractMetadataArgsForCall(0)).To(Equal("/path/to/a/product.pivotal"))
+		})
+	})
+
+	Describe("with --pivnet-product-slug flag", func() {
+		It("extracts the metadata of the .pivotal file downloaded from Pivotal Network", func() {
+			fakePivnetDownloader.ReleaseForVersionReturns(pivnet.Release{ID: 12}, nil)
+			fakePivnetDownloader.ProductFilesForReleaseReturns([]pivnet.ProductFile{
+				{ID: 1, AWSObjectKey: "product/some-product-1.2.3.pivotal"},
+				{ID: 2, AWSObjectKey: "product/some-product-1.2.3.zip"},
+			}, nil)
+			fakePivnetDownloader.DownloadProductFileStub = func(file *os.File, _ string, _ int, _ int, _ io.Writer) error {
+				_, err := file.WriteString("some-product")
+				return err
+			}
+
+			var downloadedFile string
+			metadataExtractor.ExtractMetadataStub = func(path string) (extractor.Metadata, error) {
+				downloadedFile = path
+				contents, err := ioutil.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("some-product"))
+
+				return extractor.Metadata{Raw: []byte(`{}`)}, nil
+			}
+
+			err := command.Execute([]string{
+				"--pivnet-product-slug", "some-product",
+				"--product-version", "1.2.3",
+				"--pivnet-api-token", "some-token",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pivnetConfig.Token).To(Equal("some-token"))
+
+			slug, version := fakePivnetDownloader.ReleaseForVersionArgsForCall(0)
+			Expect(slug).To(Equal("some-product"))
+			Expect(version).To(Equal("1.2.3"))
+
+			_, slug, releaseID, productFileID, _ := fakePivnetDownloader.DownloadProductFileArgsForCall(0)
+			Expect(slug).To(Equal("some-product"))
+			Expect(releaseID).To(Equal(12))
+			Expect(productFileID).To(Equal(1))
+
+			Expect(downloadedFile).NotTo(BeEmpty())
+			Expect(downloadedFile).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("with --output-directory flag", func() {
+		var outputDirectory string
+
+		BeforeEach(func() {
+			var err error
+			outputDirectory, err = ioutil.TempDir("", "config-template")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(outputDirectory)
+		})
+
+		It("writes the template and a vars file for its credentials", func() {
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+				Raw: []byte(`---
+property_blueprints:
+- name: some-string-property
+  type: string
+  default: some-value
+  optional: true
+  configurable: true
+- name: some-credentials
+  type: simple_credentials
+  optional: false
+  configurable: true
+- name: some-secret
+  type: secret
+  optional: false
+  configurable: true
+`),
+			}, nil)
+
+			err := command.Execute([]string{
+				"--pivotal-file", "/path/to/a/product.pivotal",
+				"--output-directory", outputDirectory,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			product, err := ioutil.ReadFile(filepath.Join(outputDirectory, "product.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(product).To(MatchYAML(`---
+product-properties:
+  .properties.some-string-property:
+    value: some-value
+  .properties.some-credentials:
+    value:
+      identity: ((properties_some-credentials.identity))
+      password: ((properties_some-credentials.password))
+  .properties.some-secret:
+    value:
+      secret: ((properties_some-secret.secret))
+`))
+
+			secretsVars, err := ioutil.ReadFile(filepath.Join(outputDirectory, "secrets-vars.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secretsVars).To(MatchYAML(`---
+properties_some-credentials:
+  identity: ""
+  password: ""
+properties_some-secret:
+  secret: ""
+`))
+
+			Expect(logger.PrintlnCallCount()).To(Equal(0))
+			Expect(logger.PrintfCallCount()).To(Equal(1))
+		})
+	})
+
 	Describe("Usage", func() {
 		It("returns usage information for the command", func() {
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
-				Description:      "**EXPERIMENTAL** This command generates a configuration template that can be passed in to om configure-product",
+				Description:      "**EXPERIMENTAL** This command generates a configuration template that can be passed in to om configure-product, from a local .pivotal file or one downloaded from Pivotal Network. Every property is annotated with its type and whether it is required, and has its default value. With --output-directory, the credentials are written as placeholders to a separate vars file",
 				ShortDescription: "**EXPERIMENTAL** generates a config template for the product",
 				Flags:            command.Options,
 			}))
@@ -426,10 +582,59 @@ product-properties:
 			})
 		})
 
-		Context("when the product flag is not provided", func() {
+		Context("when the product is not provided", func() {
 			It("returns an error", func() {
 				err := command.Execute([]string{})
-				Expect(err).To(MatchError("could not parse config-template flags: missing required flag \"--product\""))
+				Expect(err).To(MatchError("please provide either --pivotal-file or --pivnet-product-slug"))
+			})
+		})
+
+		Context("when both a .pivotal file and a pivnet product are provided", func() {
+			It("returns an error", func() {
+				err := command.Execute([]string{
+					"--pivotal-file", "/path/to/a/product.pivotal",
+					"--pivnet-product-slug", "some-product",
+				})
+				Expect(err).To(MatchError("cannot use both --pivotal-file and --pivnet-product-slug; please choose one or the other"))
+			})
+		})
+
+		Context("when the pivnet product has no version or token", func() {
+			It("returns an error", func() {
+				err := command.Execute([]string{
+					"--pivnet-product-slug", "some-product",
+					"--product-version", "1.2.3",
+				})
+				Expect(err).To(MatchError("--product-version and --pivnet-api-token are required with --pivnet-product-slug"))
+			})
+		})
+
+		Context("when the pivnet product cannot be found", func() {
+			It("returns an error", func() {
+				fakePivnetDownloader.ReleaseForVersionReturns(pivnet.Release{}, errors.New("no release"))
+
+				err := command.Execute([]string{
+					"--pivnet-product-slug", "some-product",
+					"--product-version", "1.2.3",
+					"--pivnet-api-token", "some-token",
+				})
+				Expect(err).To(MatchError("could not find the product on Pivotal Network: could not fetch the release for some-product 1.2.3: no release"))
+			})
+		})
+
+		Context("when the pivnet product cannot be downloaded", func() {
+			It("returns an error", func() {
+				fakePivnetDownloader.ProductFilesForReleaseReturns([]pivnet.ProductFile{
+					{ID: 1, AWSObjectKey: "product/some-product-1.2.3.pivotal"},
+				}, nil)
+				fakePivnetDownloader.DownloadProductFileReturns(errors.New("connection reset"))
+
+				err := command.Execute([]string{
+					"--pivnet-product-slug", "some-product",
+					"--product-version", "1.2.3",
+					"--pivnet-api-token", "some-token",
+				})
+				Expect(err).To(MatchError("could not download product: could not download product file some-product: connection reset"))
 			})
 		})
 
@@ -17,9 +17,11 @@ type ConfigureDirector struct {
 	logger      logger
 	Options     struct {
 		ConfigFile string   `short:"c" long:"config" description:"path to yml file containing all config fields (see docs/configure-director/README.md for format)" required:"true"`
-		VarsFile   []string `long:"vars-file"  description:"Load variables from a YAML file"`
+		VarsFile   []string `short:"l" long:"vars-file"  description:"Load variables from a YAML file"`
 		VarsEnv    []string `long:"vars-env"   description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
-		OpsFile    []string `long:"ops-file"  description:"YAML operations file"`
+		Vars       []string `long:"var"        description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore  string   `long:"vars-store" description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		OpsFile    []string `short:"o" long:"ops-file"  description:"YAML operations file"`
 	}
 }
 
@@ -125,6 +127,8 @@ func (c ConfigureDirector) interpolateConfig() (*directorConfig, error) {
 		varsFiles:    c.Options.VarsFile,
 		environFunc:  c.environFunc,
 		varsEnvs:     c.Options.VarsEnv,
+		vars:         c.Options.Vars,
+		varsStore:    c.Options.VarsStore,
 		opsFiles:     c.Options.OpsFile,
 	}, "")
 	if err != nil {
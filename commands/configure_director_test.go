@@ -413,6 +413,77 @@ properties-configuration:
 						})
 					})
 
+					Context("passed on the command line (--var)", func() {
+						It("interpolates variables into the configuration", func() {
+							configFile, err := ioutil.TempFile("", "config.yaml")
+							Expect(err).ToNot(HaveOccurred())
+							_, err = configFile.Write(templateConfigurationJSON)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(configFile.Close()).ToNot(HaveOccurred())
+
+							err = command.Execute([]string{
+								"--config", configFile.Name(),
+								"--var", "network_name=network",
+							})
+							Expect(err).NotTo(HaveOccurred())
+
+							ExpectDirectorToBeConfiguredCorrectly()
+						})
+					})
+
+					Context("replaced by an ops file (--ops-file)", func() {
+						It("applies the operations to the configuration", func() {
+							configFile, err := ioutil.TempFile("", "config.yaml")
+							Expect(err).ToNot(HaveOccurred())
+							_, err = configFile.Write(templateConfigurationJSON)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(configFile.Close()).ToNot(HaveOccurred())
+
+							opsFile, err := ioutil.TempFile("", "ops.yaml")
+							Expect(err).ToNot(HaveOccurred())
+							_, err = opsFile.WriteString(`---
+- type: replace
+  path: /network-assignment/network/name
+  value: network
+`)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(opsFile.Close()).ToNot(HaveOccurred())
+
+							err = command.Execute([]string{
+								"--config", configFile.Name(),
+								"-o", opsFile.Name(),
+							})
+							Expect(err).NotTo(HaveOccurred())
+
+							ExpectDirectorToBeConfiguredCorrectly()
+						})
+					})
+
+					Context("when the ops file is invalid", func() {
+						It("returns an error", func() {
+							configFile, err := ioutil.TempFile("", "config.yaml")
+							Expect(err).ToNot(HaveOccurred())
+							_, err = configFile.Write(completeConfigurationJSON)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(configFile.Close()).ToNot(HaveOccurred())
+
+							opsFile, err := ioutil.TempFile("", "ops.yaml")
+							Expect(err).ToNot(HaveOccurred())
+							_, err = opsFile.WriteString(`- type: replace
+  path: /network-assignment/missing/name
+  value: network
+`)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(opsFile.Close()).ToNot(HaveOccurred())
+
+							err = command.Execute([]string{
+								"--config", configFile.Name(),
+								"--ops-file", opsFile.Name(),
+							})
+							Expect(err).To(MatchError(ContainSubstring("Expected to find a map key 'missing'")))
+						})
+					})
+
 				})
 
 			})
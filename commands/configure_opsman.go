@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"gopkg.in/yaml.v2"
+)
+
+type ConfigureOpsman struct {
+	environFunc func() []string
+	service     configureOpsmanService
+	logger      logger
+	Options     struct {
+		ConfigFile string   `short:"c" long:"config" description:"path to yml file containing all config fields (see docs/configure-opsman/README.md for format)" required:"true"`
+		VarsFile   []string `short:"l" long:"vars-file"  description:"Load variables from a YAML file"`
+		VarsEnv    []string `long:"vars-env"   description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars       []string `long:"var"        description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore  string   `long:"vars-store" description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		OpsFile    []string `short:"o" long:"ops-file"  description:"YAML operations file"`
+	}
+}
+
+type opsmanConfig struct {
+	SSLCertificate *struct {
+		Certificate string `yaml:"certificate"`
+		PrivateKey  string `yaml:"private_key"`
+	} `yaml:"ssl-certificate"`
+	BannerSettings   *api.BannerSettings    `yaml:"banner-settings"`
+	RBACSettings     *api.RBACSettings      `yaml:"rbac-settings"`
+	TokensExpiration *api.TokensExpiration  `yaml:"tokens-expiration"`
+	SyslogSettings   *api.SyslogSettings    `yaml:"syslog-settings"`
+	Field            map[string]interface{} `yaml:",inline"`
+}
+
+//go:generate counterfeiter -o ./fakes/configure_opsman_service.go --fake-name ConfigureOpsmanService . configureOpsmanService
+type configureOpsmanService interface {
+	EnableRBAC(api.RBACSettings) error
+	UpdateBanner(api.BannerSettings) error
+	UpdateSSLCertificate(api.SSLCertificateInput) error
+	UpdateSyslogSettings(api.SyslogSettings) error
+	UpdateTokensExpiration(api.TokensExpiration) error
+}
+
+func NewConfigureOpsman(environFunc func() []string, service configureOpsmanService, logger logger) ConfigureOpsman {
+	return ConfigureOpsman{
+		environFunc: environFunc,
+		service:     service,
+		logger:      logger,
+	}
+}
+
+func (c ConfigureOpsman) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command configures the settings of the Ops Manager itself: its SSL certificate, banners, role-based access control, UAA token expiration and syslog. Only the settings in the config are updated.",
+		ShortDescription: "configures the settings of the Ops Manager",
+		Flags:            c.Options,
+	}
+}
+
+func (c ConfigureOpsman) Execute(args []string) error {
+	if _, err := jhanda.Parse(&c.Options, args); err != nil {
+		return fmt.Errorf("could not parse configure-opsman flags: %s", err)
+	}
+
+	configContents, err := interpolate(interpolateOptions{
+		templateFile: c.Options.ConfigFile,
+		varsFiles:    c.Options.VarsFile,
+		environFunc:  c.environFunc,
+		varsEnvs:     c.Options.VarsEnv,
+		vars:         c.Options.Vars,
+		varsStore:    c.Options.VarsStore,
+		opsFiles:     c.Options.OpsFile,
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	var config opsmanConfig
+	err = yaml.UnmarshalStrict(configContents, &config)
+	if err != nil {
+		return fmt.Errorf("could not be parsed as valid configuration: %s: %s", c.Options.ConfigFile, err)
+	}
+
+	if len(config.Field) > 0 {
+		var unrecognizedKeys []string
+		for key := range config.Field {
+			unrecognizedKeys = append(unrecognizedKeys, key)
+		}
+		sort.Strings(unrecognizedKeys)
+
+		return fmt.Errorf("the config file contains unrecognized keys: \"%s\"", strings.Join(unrecognizedKeys, "\", \""))
+	}
+
+	if config.SSLCertificate != nil {
+		c.logger.Printf("started updating the ssl certificate")
+		err = c.service.UpdateSSLCertificate(api.SSLCertificateInput{
+			CertPem:       config.SSLCertificate.Certificate,
+			PrivateKeyPem: config.SSLCertificate.PrivateKey,
+		})
+		if err != nil {
+			return fmt.Errorf("ssl certificate could not be applied: %s", err)
+		}
+		c.logger.Printf("finished updating the ssl certificate")
+	}
+
+	if config.BannerSettings != nil {
+		c.logger.Printf("started updating the banner settings")
+		err = c.service.UpdateBanner(*config.BannerSettings)
+		if err != nil {
+			return fmt.Errorf("banner settings could not be applied: %s", err)
+		}
+		c.logger.Printf("finished updating the banner settings")
+	}
+
+	if config.RBACSettings != nil {
+		c.logger.Printf("started updating the rbac settings")
+		err = c.service.EnableRBAC(*config.RBACSettings)
+		if err != nil {
+			return fmt.Errorf("rbac settings could not be applied: %s", err)
+		}
+		c.logger.Printf("finished updating the rbac settings")
+	}
+
+	if config.TokensExpiration != nil {
+		c.logger.Printf("started updating the tokens expiration")
+		err = c.service.UpdateTokensExpiration(*config.TokensExpiration)
+		if err != nil {
+			return fmt.Errorf("tokens expiration could not be applied: %s", err)
+		}
+		c.logger.Printf("finished updating the tokens expiration")
+	}
+
+	if config.SyslogSettings != nil {
+		c.logger.Printf("started updating the syslog settings")
+		err = c.service.UpdateSyslogSettings(*config.SyslogSettings)
+		if err != nil {
+			return fmt.Errorf("syslog settings could not be applied: %s", err)
+		}
+		c.logger.Printf("finished updating the syslog settings")
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package commands_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfigureOpsman", func() {
+	var (
+		logger     *fakes.Logger
+		service    *fakes.ConfigureOpsmanService
+		command    commands.ConfigureOpsman
+		configFile *os.File
+	)
+
+	writeConfig := func(contents string) {
+		_, err := configFile.WriteString(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configFile.Close()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		service = &fakes.ConfigureOpsmanService{}
+
+		var err error
+		configFile, err = ioutil.TempFile("", "opsman.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		command = commands.NewConfigureOpsman(func() []string { return nil }, service, logger)
+	})
+
+	AfterEach(func() {
+		os.Remove(configFile.Name())
+	})
+
+	Describe("Execute", func() {
+		It("updates every setting in the config", func() {
+			writeConfig(`---
+ssl-certificate:
+  certificate: some-certificate
+  private_key: ((private-key))
+banner-settings:
+  ui_banner_contents: some-ui-banner
+  ssh_banner_contents: some-ssh-banner
+rbac-settings:
+  ldap_rbac_admin_group_name: some-admin-group
+tokens-expiration:
+  access_token_expiration: 3600
+  refresh_token_expiration: 7200
+  session_idle_timeout: 1800
+syslog-settings:
+  enabled: true
+  address: logs.example.com
+  port: 514
+  transport_protocol: tcp
+`)
+
+			err := command.Execute([]string{
+				"--config", configFile.Name(),
+				"--var", "private-key=some-private-key",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.UpdateSSLCertificateArgsForCall(0)).To(Equal(api.SSLCertificateInput{
+				CertPem:       "some-certificate",
+				PrivateKeyPem: "some-private-key",
+			}))
+			Expect(service.UpdateBannerArgsForCall(0)).To(Equal(api.BannerSettings{
+				UIBannerContents:  "some-ui-banner",
+				SSHBannerContents: "some-ssh-banner",
+			}))
+			Expect(service.EnableRBACArgsForCall(0)).To(Equal(api.RBACSettings{
+				LDAPAdminGroupName: "some-admin-group",
+			}))
+			Expect(service.UpdateTokensExpirationArgsForCall(0)).To(Equal(api.TokensExpiration{
+				AccessTokenExpiration:  3600,
+				RefreshTokenExpiration: 7200,
+				SessionIdleTimeout:     1800,
+			}))
+			Expect(service.UpdateSyslogSettingsArgsForCall(0)).To(Equal(api.SyslogSettings{
+				Enabled:           true,
+				Address:           "logs.example.com",
+				Port:              514,
+				TransportProtocol: "tcp",
+			}))
+
+			Expect(logger.PrintfCallCount()).To(Equal(10))
+			format, _ := logger.PrintfArgsForCall(0)
+			Expect(format).To(Equal("started updating the ssl certificate"))
+			format, _ = logger.PrintfArgsForCall(9)
+			Expect(format).To(Equal("finished updating the syslog settings"))
+		})
+
+		It("only updates the settings in the config", func() {
+			writeConfig(`---
+banner-settings:
+  ui_banner_contents: some-ui-banner
+`)
+
+			err := command.Execute([]string{"--config", configFile.Name()})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.UpdateBannerCallCount()).To(Equal(1))
+			Expect(service.UpdateSSLCertificateCallCount()).To(Equal(0))
+			Expect(service.EnableRBACCallCount()).To(Equal(0))
+			Expect(service.UpdateTokensExpirationCallCount()).To(Equal(0))
+			Expect(service.UpdateSyslogSettingsCallCount()).To(Equal(0))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the flags cannot be parsed", func() {
+				err := command.Execute([]string{"--invalid"})
+				Expect(err).To(MatchError("could not parse configure-opsman flags: flag provided but not defined: -invalid"))
+			})
+
+			It("returns an error when the config cannot be interpolated", func() {
+				writeConfig(`banner-settings: ((missing))`)
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError(ContainSubstring("Expected to find variables: missing")))
+			})
+
+			It("returns an error when the config has unrecognized keys", func() {
+				writeConfig(`---
+banner-settings: {}
+uaa-settings: {}
+saml-settings: {}
+`)
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError(`the config file contains unrecognized keys: "saml-settings", "uaa-settings"`))
+				Expect(service.UpdateBannerCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when a section has unrecognized keys", func() {
+				writeConfig(`---
+syslog-settings:
+  hostname: logs.example.com
+`)
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError(ContainSubstring("could not be parsed as valid configuration: " + configFile.Name())))
+			})
+
+			It("returns an error when a setting cannot be applied", func() {
+				writeConfig(`---
+ssl-certificate:
+  certificate: some-certificate
+  private_key: some-private-key
+banner-settings:
+  ui_banner_contents: some-ui-banner
+`)
+				service.UpdateSSLCertificateReturns(errors.New("invalid certificate"))
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError("ssl certificate could not be applied: invalid certificate"))
+				Expect(service.UpdateBannerCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the syslog settings cannot be applied", func() {
+				writeConfig(`---
+syslog-settings:
+  enabled: false
+`)
+				service.UpdateSyslogSettingsReturns(errors.New("server error"))
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError("syslog settings could not be applied: server error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command configures the settings of the Ops Manager itself: its SSL certificate, banners, role-based access control, UAA token expiration and syslog. Only the settings in the config are updated.",
+				ShortDescription: "configures the settings of the Ops Manager",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/kiln/proofing"
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/config"
 
@@ -15,21 +16,27 @@ import (
 )
 
 type ConfigureProduct struct {
-	environFunc func() []string
-	service     configureProductService
-	logger      logger
-	target      string
-	Options     struct {
-		ConfigFile string   `long:"config"    short:"c" description:"path to yml file containing all config fields (see docs/configure-product/README.md for format)" required:"true"`
-		VarsFile   []string `long:"vars-file" short:"l" description:"Load variables from a YAML file"`
-		VarsEnv    []string `long:"vars-env"            description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
-		OpsFile    []string `long:"ops-file"  short:"o" description:"YAML operations file"`
+	environFunc       func() []string
+	service           configureProductService
+	metadataExtractor metadataExtractor
+	logger            logger
+	target            string
+	Options           struct {
+		ConfigFile  string   `long:"config"    short:"c" description:"path to yml file containing all config fields (see docs/configure-product/README.md for format)" required:"true"`
+		VarsFile    []string `long:"vars-file" short:"l" description:"Load variables from a YAML file"`
+		VarsEnv     []string `long:"vars-env"            description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars        []string `long:"var"                 description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore   string   `long:"vars-store"          description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		OpsFile     []string `long:"ops-file"  short:"o" description:"YAML operations file"`
+		Strict      bool     `long:"strict"              description:"fail, without configuring the product, when the staged product has configured properties that are not in the config"`
+		PivotalFile string   `long:"pivotal-file"        description:"path to the .pivotal file of the product, the properties left to the default of its metadata are not reported by --strict"`
 	}
 }
 
 //go:generate counterfeiter -o ./fakes/configure_product_service.go --fake-name ConfigureProductService . configureProductService
 type configureProductService interface {
 	GetStagedProductJobResourceConfig(productGUID, jobGUID string) (api.JobProperties, error)
+	GetStagedProductProperties(product string) (map[string]api.ResponseProperty, error)
 	ListInstallations() ([]api.InstallationsServiceOutput, error)
 	ListStagedPendingChanges() (api.PendingChangesOutput, error)
 	ListStagedProductJobs(productGUID string) (map[string]string, error)
@@ -46,12 +53,13 @@ type configureProduct struct {
 	Field                       map[string]interface{} `yaml:",inline"`
 }
 
-func NewConfigureProduct(environFunc func() []string, service configureProductService, target string, logger logger) ConfigureProduct {
+func NewConfigureProduct(environFunc func() []string, service configureProductService, metadataExtractor metadataExtractor, target string, logger logger) ConfigureProduct {
 	return ConfigureProduct{
-		environFunc: environFunc,
-		service:     service,
-		target:      target,
-		logger:      logger,
+		environFunc:       environFunc,
+		service:           service,
+		metadataExtractor: metadataExtractor,
+		target:            target,
+		logger:            logger,
 	}
 }
 
@@ -84,6 +92,13 @@ func (cp ConfigureProduct) Execute(args []string) error {
 		return err
 	}
 
+	if cp.Options.Strict {
+		err = cp.checkUnmanagedProperties(cfg, productGUID)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = cp.configureNetwork(cfg, productGUID)
 	if err != nil {
 		return err
@@ -288,6 +303,8 @@ func (cp *ConfigureProduct) interpolateConfig(cfg configureProduct) (configurePr
 		varsFiles:    cp.Options.VarsFile,
 		environFunc:  cp.environFunc,
 		varsEnvs:     cp.Options.VarsEnv,
+		vars:         cp.Options.Vars,
+		varsStore:    cp.Options.VarsStore,
 		opsFiles:     cp.Options.OpsFile,
 	}, "")
 	if err != nil {
@@ -340,6 +357,140 @@ func (cp ConfigureProduct) getProductGUID(cfg configureProduct) (string, error)
 	return productGUID, nil
 }
 
+// checkUnmanagedProperties reports the properties that have a value on the staged
+// product but are not in the config, such as the ones set in the Ops Manager UI.
+// Credentials cannot be read back and the properties of the options of a selector
+// that are not selected are not in use, so neither is reported. With the
+// metadata of the product, neither are the properties left to their default.
+func (cp ConfigureProduct) checkUnmanagedProperties(cfg configureProduct, productGUID string) error {
+	defaults, err := cp.metadataDefaults()
+	if err != nil {
+		return err
+	}
+
+	properties, err := cp.service.GetStagedProductProperties(productGUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the staged properties of %s: %s", cfg.ProductName, err)
+	}
+
+	var names []string
+	for name, property := range properties {
+		if _, ok := cfg.ProductProperties[name]; ok {
+			continue
+		}
+
+		if !property.Configurable || property.IsCredential || isEmptyValue(property.Value) || isUnselectedOption(name, properties) {
+			continue
+		}
+
+		if defaultValue, ok := defaults[name]; ok && isDefaultValue(property, defaultValue) {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	sort.Strings(names)
+
+	cp.logger.Println("product-properties:")
+	for _, name := range names {
+		value := properties[name].Value
+		if _, isMap := asMap(value); isMap || isSecretPath("", name) {
+			cp.logger.Println(fmt.Sprintf("  - %s: not in the config", name))
+			continue
+		}
+
+		cp.logger.Println(fmt.Sprintf("  - %s: %s is not in the config", name, formatValue(value)))
+	}
+
+	return fmt.Errorf("the staged product %s has %d configured properties that are not in %s, and --strict is set", cfg.ProductName, len(names), cp.Options.ConfigFile)
+}
+
+// metadataDefaults are the defaults of the properties of the product
+// metadata of --pivotal-file, by property name
+func (cp ConfigureProduct) metadataDefaults() (map[string]interface{}, error) {
+	if cp.Options.PivotalFile == "" {
+		return nil, nil
+	}
+
+	metadata, err := cp.metadataExtractor.ExtractMetadata(cp.Options.PivotalFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not extract the metadata of %s: %s", cp.Options.PivotalFile, err)
+	}
+
+	var template proofing.ProductTemplate
+	err = yaml.Unmarshal(metadata.Raw, &template)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the metadata of %s: %s", cp.Options.PivotalFile, err)
+	}
+
+	defaults := map[string]interface{}{}
+	for _, pair := range makePropertyBluePrintPair(&template) {
+		if pair.NormalizedPropertyBlueprint.Default != nil {
+			defaults[pair.Property] = pair.NormalizedPropertyBlueprint.Default
+		}
+	}
+
+	return defaults, nil
+}
+
+// isDefaultValue compares the staged value with the metadata default as
+// yaml, as numbers and maps are not decoded the same. The default of a
+// selector may be the name of its option rather than its value.
+func isDefaultValue(property api.ResponseProperty, defaultValue interface{}) bool {
+	if property.Type == "selector" && property.SelectedOption != "" && property.SelectedOption == fmt.Sprintf("%v", defaultValue) {
+		return true
+	}
+
+	value, err := yaml.Marshal(property.Value)
+	if err != nil {
+		return false // un-tested
+	}
+
+	defaultYAML, err := yaml.Marshal(defaultValue)
+	if err != nil {
+		return false // un-tested
+	}
+
+	return string(value) == string(defaultYAML)
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[interface{}]interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// isUnselectedOption is true for the properties of an option of a selector
+// that is not the selected option, such as `.properties.selector.option.property`.
+func isUnselectedOption(name string, properties map[string]api.ResponseProperty) bool {
+	for selectorName, selector := range properties {
+		if selector.Type != "selector" || selector.SelectedOption == "" || !strings.HasPrefix(name, selectorName+".") {
+			continue
+		}
+
+		option := strings.SplitN(strings.TrimPrefix(name, selectorName+"."), ".", 2)[0]
+		if option != selector.SelectedOption {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (cp ConfigureProduct) validateConfigComplete(productGUID string) error {
 	pendingChanges, err := cp.service.ListStagedPendingChanges()
 	if err != nil {
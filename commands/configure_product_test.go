@@ -10,6 +10,7 @@ import (
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/extractor"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -45,7 +46,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("configures the given product's properties", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -78,7 +79,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("check configuration is complete after configuring", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "example.com", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "example.com", logger)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -117,7 +118,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("returns a helpful error message if configuration completeness cannot be validated", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "example.com", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "example.com", logger)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -158,7 +159,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("configures a product's network", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
@@ -197,7 +198,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("configures the resource that is provided", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
 						{GUID: "some-product-guid", Type: "cf"},
@@ -358,7 +359,7 @@ var _ = Describe("ConfigureProduct", func() {
 			Context("when the config file contains variables", func() {
 				Context("passed in a vars-file", func() {
 					It("can interpolate variables into the configuration", func() {
-						client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+						client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 						configFile, err = ioutil.TempFile("", "")
 						Expect(err).NotTo(HaveOccurred())
@@ -382,7 +383,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 				Context("passed as environment variables", func() {
 					It("can interpolate variables into the configuration", func() {
-						client := commands.NewConfigureProduct(func() []string { return []string{"OM_VAR_password=something-secure"} }, service, "", logger)
+						client := commands.NewConfigureProduct(func() []string { return []string{"OM_VAR_password=something-secure"} }, service, &fakes.MetadataExtractor{}, "", logger)
 
 						configFile, err = ioutil.TempFile("", "")
 						Expect(err).NotTo(HaveOccurred())
@@ -398,8 +399,26 @@ var _ = Describe("ConfigureProduct", func() {
 					})
 				})
 
+				Context("passed on the command line", func() {
+					It("can interpolate variables into the configuration", func() {
+						client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
+
+						configFile, err = ioutil.TempFile("", "")
+						Expect(err).NotTo(HaveOccurred())
+
+						_, err = configFile.WriteString(productPropertiesWithVariables)
+						Expect(err).NotTo(HaveOccurred())
+
+						err = client.Execute([]string{
+							"--config", configFile.Name(),
+							"--var", "password=something-secure",
+						})
+						Expect(err).NotTo(HaveOccurred())
+					})
+				})
+
 				It("returns an error if missing variables", func() {
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).NotTo(HaveOccurred())
@@ -417,7 +436,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 			Context("when an ops-file is provided", func() {
 				It("can interpolate ops-files into the configuration", func() {
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).NotTo(HaveOccurred())
@@ -444,7 +463,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error if the ops file is invalid", func() {
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).NotTo(HaveOccurred())
@@ -474,7 +493,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("configures the resource that is provided", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
 						{GUID: "some-product-guid", Type: "cf"},
@@ -531,7 +550,7 @@ var _ = Describe("ConfigureProduct", func() {
 				config = fmt.Sprintf(`{"product-name": "cf", "resource-config": %s}`, resourceConfig)
 			})
 			It("returns an error", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 				service.ListStagedProductsReturns(api.StagedProductsOutput{
 					Products: []api.StagedProduct{
 						{GUID: "some-product-guid", Type: "cf"},
@@ -565,7 +584,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("logs and then does nothing if network is empty", func() {
-				command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 				err := command.Execute([]string{
 					"--config", configFile.Name(),
@@ -587,6 +606,174 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 		})
 
+		Context("when only the errands are provided in the config", func() {
+			BeforeEach(func() {
+				config = errandConfigFile
+				service.ListStagedProductsReturns(api.StagedProductsOutput{
+					Products: []api.StagedProduct{
+						{GUID: "some-product-guid", Type: "cf"},
+					},
+				}, nil)
+			})
+
+			It("only sets the state of the errands", func() {
+				command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
+
+				err := command.Execute([]string{
+					"--config", configFile.Name(),
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.UpdateStagedProductPropertiesCallCount()).To(Equal(0))
+				Expect(service.UpdateStagedProductNetworksAndAZsCallCount()).To(Equal(0))
+				Expect(service.UpdateStagedProductJobResourceConfigCallCount()).To(Equal(0))
+
+				Expect(service.UpdateStagedProductErrandsCallCount()).To(Equal(2))
+
+				productGUID, errandName, postDeployState, preDeleteState := service.UpdateStagedProductErrandsArgsForCall(0)
+				Expect(productGUID).To(Equal("some-product-guid"))
+				Expect(errandName).To(Equal("push-usage-service"))
+				Expect(postDeployState).To(Equal(false))
+				Expect(preDeleteState).To(Equal("when-changed"))
+
+				productGUID, errandName, postDeployState, preDeleteState = service.UpdateStagedProductErrandsArgsForCall(1)
+				Expect(productGUID).To(Equal("some-product-guid"))
+				Expect(errandName).To(Equal("smoke_tests"))
+				Expect(postDeployState).To(Equal(true))
+				Expect(preDeleteState).To(Equal("default"))
+			})
+		})
+
+		Context("with --strict", func() {
+			BeforeEach(func() {
+				config = fmt.Sprintf(`{"product-name": "cf", "product-properties": %s}`, productProperties)
+
+				service.ListStagedProductsReturns(api.StagedProductsOutput{
+					Products: []api.StagedProduct{
+						{GUID: "some-product-guid", Type: "cf"},
+					},
+				}, nil)
+				service.GetStagedProductPropertiesReturns(map[string]api.ResponseProperty{
+					".properties.something":                   {Value: "configure-me", Configurable: true},
+					".a-job.job-property":                     {Value: map[interface{}]interface{}{"secret": "***"}, Configurable: true, IsCredential: true, Type: "simple_credentials"},
+					".properties.some-generated":              {Value: "some-guid", Configurable: false},
+					".properties.some-empty":                  {Value: nil, Configurable: true},
+					".properties.some-ui-property":            {Value: "set-in-the-ui", Configurable: true},
+					".properties.some-collection":             {Value: []interface{}{map[interface{}]interface{}{"name": "some-item"}}, Configurable: true, Type: "collection"},
+					".properties.some-selector":               {Value: "Internal", SelectedOption: "internal", Configurable: true, Type: "selector"},
+					".properties.some-selector.internal.size": {Value: 3, Configurable: true},
+					".properties.some-selector.external.host": {Value: "db.example.com", Configurable: true},
+				}, nil)
+			})
+
+			It("fails without configuring the product when staged properties are not in the config", func() {
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
+				err := client.Execute([]string{
+					"--config", configFile.Name(),
+					"--strict",
+				})
+				Expect(err).To(MatchError(fmt.Sprintf("the staged product cf has 4 configured properties that are not in %s, and --strict is set", configFile.Name())))
+
+				Expect(service.GetStagedProductPropertiesArgsForCall(0)).To(Equal("some-product-guid"))
+				Expect(service.UpdateStagedProductPropertiesCallCount()).To(Equal(0))
+
+				var lines []string
+				for i := 0; i < logger.PrintlnCallCount(); i++ {
+					lines = append(lines, logger.PrintlnArgsForCall(i)[0].(string))
+				}
+				Expect(lines).To(Equal([]string{
+					"product-properties:",
+					"  - .properties.some-collection: not in the config",
+					"  - .properties.some-selector: Internal is not in the config",
+					"  - .properties.some-selector.internal.size: 3 is not in the config",
+					"  - .properties.some-ui-property: set-in-the-ui is not in the config",
+				}))
+			})
+
+			It("configures the product when every staged property is in the config", func() {
+				service.GetStagedProductPropertiesReturns(map[string]api.ResponseProperty{
+					".properties.something": {Value: "configure-me", Configurable: true},
+				}, nil)
+
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
+				err := client.Execute([]string{
+					"--config", configFile.Name(),
+					"--strict",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.UpdateStagedProductPropertiesCallCount()).To(Equal(1))
+			})
+
+			It("does not report the properties left to the default of the product metadata", func() {
+				metadataExtractor := &fakes.MetadataExtractor{}
+				metadataExtractor.ExtractMetadataReturns(extractor.Metadata{Raw: []byte(`---
+name: cf
+property_blueprints:
+- name: some-ui-property
+  type: string
+  configurable: true
+  default: set-in-the-ui
+- name: some-selector
+  type: selector
+  configurable: true
+  default: internal
+  option_templates:
+  - name: internal
+    select_value: Internal
+    property_blueprints:
+    - name: size
+      type: integer
+      configurable: true
+      default: 1
+`)}, nil)
+
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, metadataExtractor, "", logger)
+				err := client.Execute([]string{
+					"--config", configFile.Name(),
+					"--strict",
+					"--pivotal-file", "/path/to/cf.pivotal",
+				})
+				Expect(err).To(MatchError(fmt.Sprintf("the staged product cf has 2 configured properties that are not in %s, and --strict is set", configFile.Name())))
+
+				Expect(metadataExtractor.ExtractMetadataArgsForCall(0)).To(Equal("/path/to/cf.pivotal"))
+
+				var lines []string
+				for i := 0; i < logger.PrintlnCallCount(); i++ {
+					lines = append(lines, logger.PrintlnArgsForCall(i)[0].(string))
+				}
+				Expect(lines).To(Equal([]string{
+					"product-properties:",
+					"  - .properties.some-collection: not in the config",
+					"  - .properties.some-selector.internal.size: 3 is not in the config",
+				}))
+			})
+
+			It("returns an error when the metadata of the product cannot be extracted", func() {
+				metadataExtractor := &fakes.MetadataExtractor{}
+				metadataExtractor.ExtractMetadataReturns(extractor.Metadata{}, errors.New("some error"))
+
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, metadataExtractor, "", logger)
+				err := client.Execute([]string{
+					"--config", configFile.Name(),
+					"--strict",
+					"--pivotal-file", "/path/to/cf.pivotal",
+				})
+				Expect(err).To(MatchError("could not extract the metadata of /path/to/cf.pivotal: some error"))
+			})
+
+			It("returns an error when the staged properties cannot be fetched", func() {
+				service.GetStagedProductPropertiesReturns(nil, errors.New("some error"))
+
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
+				err := client.Execute([]string{
+					"--config", configFile.Name(),
+					"--strict",
+				})
+				Expect(err).To(MatchError("failed to fetch the staged properties of cf: some error"))
+			})
+		})
+
 		Context("when there is a running installation", func() {
 			BeforeEach(func() {
 				service.ListInstallationsReturns([]api.InstallationsServiceOutput{
@@ -604,7 +791,7 @@ var _ = Describe("ConfigureProduct", func() {
 			})
 
 			It("returns an error", func() {
-				client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+				client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 				err := client.Execute([]string{"--config", configFile.Name()})
 				Expect(err).To(MatchError("OpsManager does not allow configuration or staging changes while apply changes are running to prevent data loss for configuration and/or staging changes"))
 				Expect(service.ListInstallationsCallCount()).To(Equal(1))
@@ -618,7 +805,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 			Context("when the product does not exist", func() {
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
 						Products: []api.StagedProduct{
@@ -639,7 +826,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
 						Products: []api.StagedProduct{
 							{GUID: "some-product-guid", Type: "cf"},
@@ -657,7 +844,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
 						Products: []api.StagedProduct{
 							{GUID: "some-product-guid", Type: "cf"},
@@ -680,7 +867,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
 						Products: []api.StagedProduct{
 							{GUID: "some-product-guid", Type: "cf"},
@@ -701,7 +888,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 			Context("when an unknown flag is provided", func() {
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 					err := command.Execute([]string{"--badflag"})
 					Expect(err).To(MatchError("could not parse configure-product flags: flag provided but not defined: -badflag"))
 				})
@@ -713,7 +900,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 					err := command.Execute([]string{"--config", configFile.Name()})
 					Expect(err).To(MatchError("could not parse configure-product config: \"product-name\" is required"))
 				})
@@ -722,7 +909,7 @@ var _ = Describe("ConfigureProduct", func() {
 			Context("when the --config flag is passed", func() {
 				Context("when the provided config path does not exist", func() {
 					It("returns an error", func() {
-						command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+						command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 						service.ListStagedProductsReturns(api.StagedProductsOutput{
 							Products: []api.StagedProduct{
 								{GUID: "some-product-guid", Type: "cf"},
@@ -745,7 +932,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 					It("returns an error", func() {
 						invalidConfig := "this is not a valid config"
-						client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+						client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 						service.ListStagedProductsReturns(api.StagedProductsOutput{
 							Products: []api.StagedProduct{
 								{GUID: "some-product-guid", Type: "cf"},
@@ -772,7 +959,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 					service.UpdateStagedProductPropertiesReturns(errors.New("some product error"))
 
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
@@ -792,7 +979,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 
 				It("returns an error", func() {
-					command := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					command := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 					service.UpdateStagedProductNetworksAndAZsReturns(errors.New("some product error"))
 
 					service.ListStagedProductsReturns(api.StagedProductsOutput{
@@ -824,7 +1011,7 @@ var _ = Describe("ConfigureProduct", func() {
 				})
 				It("errors when calling api", func() {
 					service.UpdateStagedProductErrandsReturns(errors.New("error configuring errand"))
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 
 					configFile, err = ioutil.TempFile("", "")
 					Expect(err).NotTo(HaveOccurred())
@@ -848,7 +1035,7 @@ var _ = Describe("ConfigureProduct", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(configFile.Close()).ToNot(HaveOccurred())
 
-					client := commands.NewConfigureProduct(func() []string { return nil }, service, "", logger)
+					client := commands.NewConfigureProduct(func() []string { return nil }, service, &fakes.MetadataExtractor{}, "", logger)
 					err = client.Execute([]string{
 						"--config", configFile.Name(),
 					})
@@ -861,7 +1048,7 @@ var _ = Describe("ConfigureProduct", func() {
 
 	Describe("Usage", func() {
 		It("returns usage information for the command", func() {
-			command := commands.NewConfigureProduct(nil, nil, "", nil)
+			command := commands.NewConfigureProduct(nil, nil, nil, "", nil)
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
 				Description:      "This authenticated command configures a staged product",
 				ShortDescription: "configures a staged product",
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"gopkg.in/yaml.v2"
+)
+
+//go:generate counterfeiter -o ./fakes/configure_resource_config_service.go --fake-name ConfigureResourceConfigService . configureResourceConfigService
+type configureResourceConfigService interface {
+	GetStagedProductByName(productName string) (api.StagedProductsFindOutput, error)
+	GetStagedProductJobResourceConfig(productGUID, jobGUID string) (api.JobProperties, error)
+	ListInstallations() ([]api.InstallationsServiceOutput, error)
+	ListStagedProductJobs(productGUID string) (map[string]string, error)
+	UpdateStagedProductJobResourceConfig(productGUID, jobGUID string, jobProperties api.JobProperties) error
+}
+
+type ConfigureResourceConfig struct {
+	environFunc func() []string
+	service     configureResourceConfigService
+	logger      logger
+	Options     struct {
+		Product    string   `long:"product-name" short:"p" required:"true" description:"name of the staged product"`
+		ConfigFile string   `long:"config"       short:"c" required:"true" description:"path to yml file with the resource config of the jobs (see docs/configure-resource-config/README.md for format)"`
+		VarsFile   []string `long:"vars-file"    short:"l"                 description:"Load variables from a YAML file"`
+		VarsEnv    []string `long:"vars-env"                               description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars       []string `long:"var"                                    description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore  string   `long:"vars-store"                             description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		OpsFile    []string `long:"ops-file"     short:"o"                 description:"YAML operations file"`
+	}
+}
+
+func NewConfigureResourceConfig(environFunc func() []string, service configureResourceConfigService, logger logger) ConfigureResourceConfig {
+	return ConfigureResourceConfig{
+		environFunc: environFunc,
+		service:     service,
+		logger:      logger,
+	}
+}
+
+func (c ConfigureResourceConfig) Execute(args []string) error {
+	if _, err := jhanda.Parse(&c.Options, args); err != nil {
+		return fmt.Errorf("could not parse configure-resource-config flags: %s", err)
+	}
+
+	err := checkRunningInstallation(c.service.ListInstallations)
+	if err != nil {
+		return err
+	}
+
+	configContents, err := interpolate(interpolateOptions{
+		templateFile: c.Options.ConfigFile,
+		varsFiles:    c.Options.VarsFile,
+		environFunc:  c.environFunc,
+		varsEnvs:     c.Options.VarsEnv,
+		vars:         c.Options.Vars,
+		varsStore:    c.Options.VarsStore,
+		opsFiles:     c.Options.OpsFile,
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	var resourceConfig map[string]interface{}
+	err = yaml.Unmarshal(configContents, &resourceConfig)
+	if err != nil {
+		return fmt.Errorf("%s could not be parsed as valid configuration: %s", c.Options.ConfigFile, err)
+	}
+
+	if len(resourceConfig) == 0 {
+		c.logger.Println("resource config properties are not provided, nothing to do here")
+		return nil
+	}
+
+	resourceConfigJSON, err := getJSONProperties(resourceConfig)
+	if err != nil {
+		return err
+	}
+
+	var userProvidedConfig map[string]json.RawMessage
+	err = json.Unmarshal([]byte(resourceConfigJSON), &userProvidedConfig)
+	if err != nil {
+		return fmt.Errorf("could not decode resource config json: %s", err)
+	}
+
+	findOutput, err := c.service.GetStagedProductByName(c.Options.Product)
+	if err != nil {
+		return fmt.Errorf("failed to find the staged product %q: %s", c.Options.Product, err)
+	}
+	productGUID := findOutput.Product.GUID
+
+	jobs, err := c.service.ListStagedProductJobs(productGUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jobs: %s", err)
+	}
+
+	var names, unknownNames []string
+	for name := range userProvidedConfig {
+		names = append(names, name)
+		if _, ok := jobs[name]; !ok {
+			unknownNames = append(unknownNames, name)
+		}
+	}
+	sort.Strings(names)
+
+	if len(unknownNames) > 0 {
+		var jobNames []string
+		for name := range jobs {
+			jobNames = append(jobNames, name)
+		}
+		sort.Strings(unknownNames)
+		sort.Strings(jobNames)
+
+		return fmt.Errorf("unknown jobs of %s: %s\nthe jobs are: %s", c.Options.Product, strings.Join(unknownNames, ", "), strings.Join(jobNames, ", "))
+	}
+
+	c.logger.Printf("applying resource configuration for the following jobs:")
+	for _, name := range names {
+		c.logger.Printf("\t%s", name)
+		jobProperties, err := c.service.GetStagedProductJobResourceConfig(productGUID, jobs[name])
+		if err != nil {
+			return fmt.Errorf("could not fetch existing job configuration: %s", err)
+		}
+
+		err = json.Unmarshal(userProvidedConfig[name], &jobProperties)
+		if err != nil {
+			return fmt.Errorf("could not decode the resource config of the job %s: %s", name, err)
+		}
+
+		err = c.service.UpdateStagedProductJobResourceConfig(productGUID, jobs[name], jobProperties)
+		if err != nil {
+			return fmt.Errorf("failed to configure resources: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (c ConfigureResourceConfig) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command configures the resource config of the jobs of a staged product from a yml file. The properties that are not in the file are left as they are staged.",
+		ShortDescription: "configures the resource config of the jobs of a staged product",
+		Flags:            c.Options,
+	}
+}
@@ -0,0 +1,191 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+)
+
+var _ = Describe("ConfigureResourceConfig", func() {
+	var (
+		fakeService *fakes.ConfigureResourceConfigService
+		fakeLogger  *fakes.Logger
+		command     commands.ConfigureResourceConfig
+		configFile  *os.File
+	)
+
+	writeConfigFile := func(contents string) string {
+		var err error
+		configFile, err = ioutil.TempFile("", "resource-config.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = configFile.WriteString(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configFile.Close()).To(Succeed())
+
+		return configFile.Name()
+	}
+
+	BeforeEach(func() {
+		fakeService = &fakes.ConfigureResourceConfigService{}
+		fakeLogger = &fakes.Logger{}
+		command = commands.NewConfigureResourceConfig(func() []string { return nil }, fakeService, fakeLogger)
+
+		fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{
+			Product: api.StagedProduct{GUID: "cf-guid", Type: "cf"},
+		}, nil)
+		fakeService.ListStagedProductJobsReturns(map[string]string{
+			"router":     "router-guid",
+			"diego_cell": "diego-cell-guid",
+			"database":   "database-guid",
+		}, nil)
+		fakeService.GetStagedProductJobResourceConfigReturns(api.JobProperties{
+			Instances:    "automatic",
+			InstanceType: api.InstanceType{ID: "automatic"},
+		}, nil)
+	})
+
+	AfterEach(func() {
+		if configFile != nil {
+			os.RemoveAll(configFile.Name())
+		}
+	})
+
+	Describe("Execute", func() {
+		It("updates the resource config of the jobs in the file, over their staged config", func() {
+			err := command.Execute([]string{
+				"--product-name", "cf",
+				"--config", writeConfigFile(`---
+router:
+  instances: 3
+  additional_vm_extensions: [web-lb]
+diego_cell:
+  instance_type:
+    id: ((cell_instance_type))
+`),
+				"--var", "cell_instance_type=xlarge",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.GetStagedProductByNameArgsForCall(0)).To(Equal("cf"))
+			Expect(fakeService.UpdateStagedProductJobResourceConfigCallCount()).To(Equal(2))
+
+			productGUID, jobGUID, jobProperties := fakeService.UpdateStagedProductJobResourceConfigArgsForCall(0)
+			Expect(productGUID).To(Equal("cf-guid"))
+			Expect(jobGUID).To(Equal("diego-cell-guid"))
+			Expect(jobProperties).To(Equal(api.JobProperties{
+				Instances:    "automatic",
+				InstanceType: api.InstanceType{ID: "xlarge"},
+			}))
+
+			_, jobGUID, jobProperties = fakeService.UpdateStagedProductJobResourceConfigArgsForCall(1)
+			Expect(jobGUID).To(Equal("router-guid"))
+			Expect(jobProperties).To(Equal(api.JobProperties{
+				Instances:              float64(3),
+				InstanceType:           api.InstanceType{ID: "automatic"},
+				AdditionalVMExtensions: []string{"web-lb"},
+			}))
+
+			format, content := fakeLogger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, content...)).To(Equal("applying resource configuration for the following jobs:"))
+			format, content = fakeLogger.PrintfArgsForCall(1)
+			Expect(fmt.Sprintf(format, content...)).To(Equal("\tdiego_cell"))
+			format, content = fakeLogger.PrintfArgsForCall(2)
+			Expect(fmt.Sprintf(format, content...)).To(Equal("\trouter"))
+		})
+
+		It("does nothing when the file is empty", func() {
+			err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("---\n")})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.UpdateStagedProductJobResourceConfigCallCount()).To(Equal(0))
+			Expect(fakeLogger.PrintlnArgsForCall(0)).To(Equal([]interface{}{"resource config properties are not provided, nothing to do here"}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the config flag is missing", func() {
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError("could not parse configure-resource-config flags: missing required flag \"--config\""))
+			})
+
+			It("returns an error when the vars store is not supported", func() {
+				err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("router: {instances: ((instances))}"), "--vars-store", "some-store"})
+				Expect(err).To(MatchError(`unknown vars store "some-store", supported vars stores are: credhub, aws-secrets-manager, gcp-secret-manager, vault`))
+
+				Expect(fakeService.UpdateStagedProductJobResourceConfigCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when an installation is running", func() {
+				fakeService.ListInstallationsReturns([]api.InstallationsServiceOutput{{Status: "running"}}, nil)
+
+				err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("router: {instances: 1}")})
+				Expect(err).To(MatchError(ContainSubstring("OpsManager does not allow configuration or staging changes while apply changes are running")))
+			})
+
+			It("returns an error when the file cannot be parsed", func() {
+				path := writeConfigFile("- router")
+
+				err := command.Execute([]string{"--product-name", "cf", "--config", path})
+				Expect(err).To(MatchError(ContainSubstring(path + " could not be parsed as valid configuration")))
+			})
+
+			It("does not update anything when a job is unknown", func() {
+				err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("router: {instances: 1}\nrouters: {instances: 1}")})
+				Expect(err).To(MatchError("unknown jobs of cf: routers\nthe jobs are: database, diego_cell, router"))
+
+				Expect(fakeService.UpdateStagedProductJobResourceConfigCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the product is not staged", func() {
+				fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("router: {instances: 1}")})
+				Expect(err).To(MatchError(`failed to find the staged product "cf": some error`))
+			})
+
+			It("returns an error when the jobs cannot be listed", func() {
+				fakeService.ListStagedProductJobsReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("router: {instances: 1}")})
+				Expect(err).To(MatchError("failed to fetch jobs: some error"))
+			})
+
+			It("returns an error when the staged config of a job cannot be fetched", func() {
+				fakeService.GetStagedProductJobResourceConfigReturns(api.JobProperties{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("router: {instances: 1}")})
+				Expect(err).To(MatchError("could not fetch existing job configuration: some error"))
+			})
+
+			It("returns an error when the config of a job does not match the resource config", func() {
+				err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("router: {instance_type: xlarge}")})
+				Expect(err).To(MatchError(ContainSubstring("could not decode the resource config of the job router")))
+			})
+
+			It("returns an error when the resource config cannot be updated", func() {
+				fakeService.UpdateStagedProductJobResourceConfigReturns(errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf", "--config", writeConfigFile("router: {instances: 1}")})
+				Expect(err).To(MatchError("failed to configure resources: some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command configures the resource config of the jobs of a staged product from a yml file. The properties that are not in the file are left as they are staged.",
+				ShortDescription: "configures the resource config of the jobs of a staged product",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -18,7 +18,7 @@ type ConfigureSAMLAuthentication struct {
 		HTTPSProxyURL        string `long:"https-proxy-url"                                  description:"proxy for outbound HTTPS network traffic"`
 		NoProxy              string `long:"no-proxy"                                         description:"comma-separated list of hosts that do not go through the proxy"`
 		IDPMetadata          string `long:"saml-idp-metadata"                required:"true" description:"XML, or URL to XML, for the IDP that Ops Manager should use"`
-		BoshIDPMetadata      string `long:"saml-bosh-idp-metadata"           required:"true" description:"XML, or URL to XML, for the IDP that BOSH should use"`
+		BoshIDPMetadata      string `long:"saml-bosh-idp-metadata"                          description:"XML, or URL to XML, for the IDP that BOSH should use (defaults to the IDP of Ops Manager)"`
 		RBACAdminGroup       string `long:"saml-rbac-admin-group"            required:"true" description:"If SAML is specified, please provide the admin group for your SAML"`
 		RBACGroupsAttribute  string `long:"saml-rbac-groups-attribute"       required:"true" description:"If SAML is specified, please provide the groups attribute for your SAML"`
 	}
@@ -53,6 +53,11 @@ func (ca ConfigureSAMLAuthentication) Execute(args []string) error {
 
 	ca.logger.Printf("configuring SAML authentication...")
 
+	boshIDPMetadata := ca.Options.BoshIDPMetadata
+	if boshIDPMetadata == "" {
+		boshIDPMetadata = ca.Options.IDPMetadata
+	}
+
 	_, err = ca.service.Setup(api.SetupInput{
 		IdentityProvider:                 "saml",
 		DecryptionPassphrase:             ca.Options.DecryptionPassphrase,
@@ -62,7 +67,7 @@ func (ca ConfigureSAMLAuthentication) Execute(args []string) error {
 		NoProxy:                          ca.Options.NoProxy,
 		EULAAccepted:                     "true",
 		IDPMetadata:                      ca.Options.IDPMetadata,
-		BoshIDPMetadata:                  ca.Options.BoshIDPMetadata,
+		BoshIDPMetadata:                  boshIDPMetadata,
 		RBACAdminGroup:                   ca.Options.RBACAdminGroup,
 		RBACGroupsAttribute:              ca.Options.RBACGroupsAttribute,
 	})
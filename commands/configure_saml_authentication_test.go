@@ -65,6 +65,27 @@ var _ = Describe("ConfigureSAMLAuthentication", func() {
 			Expect(fmt.Sprintf(format, content...)).To(Equal("configuration complete"))
 		})
 
+		It("uses the IDP of Ops Manager for BOSH when --saml-bosh-idp-metadata is not provided", func() {
+			service := &fakes.ConfigureAuthenticationService{}
+			service.EnsureAvailabilityStub = func(api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error) {
+				if service.EnsureAvailabilityCallCount() == 1 {
+					return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusUnstarted}, nil
+				}
+				return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil
+			}
+
+			command := commands.NewConfigureSAMLAuthentication(service, &fakes.Logger{})
+			err := command.Execute([]string{
+				"--decryption-passphrase", "some-passphrase",
+				"--saml-idp-metadata", "https://saml.example.com:8080",
+				"--saml-rbac-admin-group", "opsman.full_control",
+				"--saml-rbac-groups-attribute", "myenterprise",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.SetupArgsForCall(0).BoshIDPMetadata).To(Equal("https://saml.example.com:8080"))
+		})
+
 		Context("when the authentication setup has already been configured", func() {
 			It("returns without configuring the authentication system", func() {
 				service := &fakes.ConfigureAuthenticationService{}
@@ -320,21 +341,6 @@ decryption-passphrase: some-passphrase
 				})
 			})
 
-			Context("when the --saml-bosh-idp-metadata field is not configured with others", func() {
-				It("returns an error", func() {
-					command := commands.NewConfigureSAMLAuthentication(nil, nil)
-					err := command.Execute([]string{
-						"--decryption-passphrase", "some-passphrase",
-						"--saml-idp-metadata", "https://saml.example.com:8080",
-						"--saml-rbac-admin-group", "opsman.full_control",
-						"--saml-rbac-groups-attribute", "myenterprise",
-					})
-					Expect(err).To(HaveOccurred())
-
-					Expect(err).To(MatchError("could not parse configure-saml-authentication flags: missing required flag \"--saml-bosh-idp-metadata\""))
-				})
-			})
-
 			Context("when the --saml-rbac-admin-group field is not configured with others", func() {
 				It("returns an error", func() {
 					command := commands.NewConfigureSAMLAuthentication(nil, nil)
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"gopkg.in/yaml.v2"
+)
+
+type ConfigureSyslog struct {
+	environFunc func() []string
+	service     configureSyslogService
+	logger      logger
+	Options     struct {
+		ConfigFile string   `short:"c" long:"config" description:"path to yml file containing the syslog configuration (see docs/configure-syslog/README.md for format)" required:"true"`
+		VarsFile   []string `short:"l" long:"vars-file"  description:"Load variables from a YAML file"`
+		VarsEnv    []string `long:"vars-env"   description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars       []string `long:"var"        description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore  string   `long:"vars-store" description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		OpsFile    []string `short:"o" long:"ops-file"  description:"YAML operations file"`
+	}
+}
+
+type syslogConfig struct {
+	SyslogConfiguration *api.SyslogSettings    `yaml:"syslog-configuration"`
+	ProductNames        []string               `yaml:"product-names"`
+	Field               map[string]interface{} `yaml:",inline"`
+}
+
+//go:generate counterfeiter -o ./fakes/configure_syslog_service.go --fake-name ConfigureSyslogService . configureSyslogService
+type configureSyslogService interface {
+	GetStagedProductByName(productName string) (api.StagedProductsFindOutput, error)
+	ListInstallations() ([]api.InstallationsServiceOutput, error)
+	UpdateStagedDirectorSyslogConfiguration(api.SyslogSettings) error
+	UpdateStagedProductSyslogConfiguration(productGUID string, settings api.SyslogSettings) error
+}
+
+func NewConfigureSyslog(environFunc func() []string, service configureSyslogService, logger logger) ConfigureSyslog {
+	return ConfigureSyslog{
+		environFunc: environFunc,
+		service:     service,
+		logger:      logger,
+	}
+}
+
+func (c ConfigureSyslog) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command configures where the director, and the staged products given in the config, forward their logs to.",
+		ShortDescription: "configures syslog forwarding of the director and products",
+		Flags:            c.Options,
+	}
+}
+
+func (c ConfigureSyslog) Execute(args []string) error {
+	if _, err := jhanda.Parse(&c.Options, args); err != nil {
+		return fmt.Errorf("could not parse configure-syslog flags: %s", err)
+	}
+
+	configContents, err := interpolate(interpolateOptions{
+		templateFile: c.Options.ConfigFile,
+		varsFiles:    c.Options.VarsFile,
+		environFunc:  c.environFunc,
+		varsEnvs:     c.Options.VarsEnv,
+		vars:         c.Options.Vars,
+		varsStore:    c.Options.VarsStore,
+		opsFiles:     c.Options.OpsFile,
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	var config syslogConfig
+	err = yaml.UnmarshalStrict(configContents, &config)
+	if err != nil {
+		return fmt.Errorf("could not be parsed as valid configuration: %s: %s", c.Options.ConfigFile, err)
+	}
+
+	if len(config.Field) > 0 {
+		var unrecognizedKeys []string
+		for key := range config.Field {
+			unrecognizedKeys = append(unrecognizedKeys, key)
+		}
+		sort.Strings(unrecognizedKeys)
+
+		return fmt.Errorf("the config file contains unrecognized keys: \"%s\"", strings.Join(unrecognizedKeys, "\", \""))
+	}
+
+	if config.SyslogConfiguration == nil {
+		return errors.New("the config file must contain syslog-configuration")
+	}
+
+	err = checkRunningInstallation(c.service.ListInstallations)
+	if err != nil {
+		return err
+	}
+
+	productGUIDs := map[string]string{}
+	for _, productName := range config.ProductNames {
+		findOutput, err := c.service.GetStagedProductByName(productName)
+		if err != nil {
+			return fmt.Errorf("could not find the staged product %q: %s", productName, err)
+		}
+		productGUIDs[productName] = findOutput.Product.GUID
+	}
+
+	c.logger.Printf("started updating the syslog configuration of the director")
+	err = c.service.UpdateStagedDirectorSyslogConfiguration(*config.SyslogConfiguration)
+	if err != nil {
+		return fmt.Errorf("syslog configuration of the director could not be applied: %s", err)
+	}
+	c.logger.Printf("finished updating the syslog configuration of the director")
+
+	for _, productName := range config.ProductNames {
+		c.logger.Printf("started updating the syslog configuration of %s", productName)
+		err = c.service.UpdateStagedProductSyslogConfiguration(productGUIDs[productName], *config.SyslogConfiguration)
+		if err != nil {
+			return fmt.Errorf("syslog configuration of %s could not be applied, the product might not support syslog forwarding: %s", productName, err)
+		}
+		c.logger.Printf("finished updating the syslog configuration of %s", productName)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfigureSyslog", func() {
+	var (
+		logger     *fakes.Logger
+		service    *fakes.ConfigureSyslogService
+		command    commands.ConfigureSyslog
+		configFile *os.File
+	)
+
+	writeConfig := func(contents string) {
+		_, err := configFile.WriteString(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configFile.Close()).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		service = &fakes.ConfigureSyslogService{}
+		service.GetStagedProductByNameStub = func(productName string) (api.StagedProductsFindOutput, error) {
+			return api.StagedProductsFindOutput{
+				Product: api.StagedProduct{GUID: productName + "-guid", Type: productName},
+			}, nil
+		}
+
+		var err error
+		configFile, err = ioutil.TempFile("", "syslog.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		command = commands.NewConfigureSyslog(func() []string { return nil }, service, logger)
+	})
+
+	AfterEach(func() {
+		os.Remove(configFile.Name())
+	})
+
+	expectedSettings := api.SyslogSettings{
+		Enabled:                    true,
+		Address:                    "logs.example.com",
+		Port:                       6514,
+		TransportProtocol:          "tcp",
+		TLSEnabled:                 true,
+		PermittedPeer:              "*.example.com",
+		SSLCACertificate:           "some-ca",
+		CustomRsyslogConfiguration: "if $programname == 'uaa' then stop",
+	}
+
+	Describe("Execute", func() {
+		It("configures the syslog of the director and of the products", func() {
+			writeConfig(`---
+syslog-configuration:
+  enabled: true
+  address: logs.example.com
+  port: 6514
+  transport_protocol: tcp
+  tls_enabled: true
+  permitted_peer: "*.example.com"
+  ssl_ca_certificate: ((syslog_ca))
+  custom_rsyslog_configuration: if $programname == 'uaa' then stop
+product-names:
+- cf
+- p-mysql
+`)
+
+			err := command.Execute([]string{"--config", configFile.Name(), "--var", "syslog_ca=some-ca"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.UpdateStagedDirectorSyslogConfigurationCallCount()).To(Equal(1))
+			Expect(service.UpdateStagedDirectorSyslogConfigurationArgsForCall(0)).To(Equal(expectedSettings))
+
+			Expect(service.UpdateStagedProductSyslogConfigurationCallCount()).To(Equal(2))
+			productGUID, settings := service.UpdateStagedProductSyslogConfigurationArgsForCall(0)
+			Expect(productGUID).To(Equal("cf-guid"))
+			Expect(settings).To(Equal(expectedSettings))
+			productGUID, _ = service.UpdateStagedProductSyslogConfigurationArgsForCall(1)
+			Expect(productGUID).To(Equal("p-mysql-guid"))
+
+			var messages []string
+			for i := 0; i < logger.PrintfCallCount(); i++ {
+				format, args := logger.PrintfArgsForCall(i)
+				messages = append(messages, fmt.Sprintf(format, args...))
+			}
+			Expect(messages).To(Equal([]string{
+				"started updating the syslog configuration of the director",
+				"finished updating the syslog configuration of the director",
+				"started updating the syslog configuration of cf",
+				"finished updating the syslog configuration of cf",
+				"started updating the syslog configuration of p-mysql",
+				"finished updating the syslog configuration of p-mysql",
+			}))
+		})
+
+		It("configures only the director when no product is given", func() {
+			writeConfig(`syslog-configuration: {enabled: false}`)
+
+			err := command.Execute([]string{"--config", configFile.Name()})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.UpdateStagedDirectorSyslogConfigurationArgsForCall(0)).To(Equal(api.SyslogSettings{}))
+			Expect(service.UpdateStagedProductSyslogConfigurationCallCount()).To(Equal(0))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the config flag is missing", func() {
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not parse configure-syslog flags: missing required flag \"--config\""))
+			})
+
+			It("returns an error when the config has unrecognized keys", func() {
+				writeConfig(`{syslog-configuration: {enabled: true}, products: [cf]}`)
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError(`the config file contains unrecognized keys: "products"`))
+			})
+
+			It("returns an error when the syslog configuration is missing", func() {
+				writeConfig(`product-names: [cf]`)
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError("the config file must contain syslog-configuration"))
+			})
+
+			It("returns an error when the syslog configuration has an unknown field", func() {
+				writeConfig(`syslog-configuration: {enabled: true, host: logs.example.com}`)
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError(ContainSubstring("could not be parsed as valid configuration")))
+			})
+
+			It("returns an error when an installation is running", func() {
+				writeConfig(`syslog-configuration: {enabled: true}`)
+				service.ListInstallationsReturns([]api.InstallationsServiceOutput{{Status: "running"}}, nil)
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError(ContainSubstring("OpsManager does not allow configuration or staging changes while apply changes are running")))
+			})
+
+			It("configures nothing when a product is not staged", func() {
+				writeConfig(`{syslog-configuration: {enabled: true}, product-names: [cf, p-unknown]}`)
+				service.GetStagedProductByNameStub = nil
+				service.GetStagedProductByNameReturnsOnCall(1, api.StagedProductsFindOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError(`could not find the staged product "p-unknown": some error`))
+
+				Expect(service.UpdateStagedDirectorSyslogConfigurationCallCount()).To(Equal(0))
+				Expect(service.UpdateStagedProductSyslogConfigurationCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the syslog of the director cannot be configured", func() {
+				writeConfig(`syslog-configuration: {enabled: true}`)
+				service.UpdateStagedDirectorSyslogConfigurationReturns(errors.New("some error"))
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError("syslog configuration of the director could not be applied: some error"))
+			})
+
+			It("returns an error when the syslog of a product cannot be configured", func() {
+				writeConfig(`{syslog-configuration: {enabled: true}, product-names: [cf]}`)
+				service.UpdateStagedProductSyslogConfigurationReturns(errors.New("some error"))
+
+				err := command.Execute([]string{"--config", configFile.Name()})
+				Expect(err).To(MatchError("syslog configuration of cf could not be applied, the product might not support syslog forwarding: some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command configures where the director, and the staged products given in the config, forward their logs to.",
+				ShortDescription: "configures syslog forwarding of the director and products",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
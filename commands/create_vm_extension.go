@@ -24,6 +24,8 @@ type CreateVMExtension struct {
 		ConfigFile      string   `long:"config"             short:"c"   description:"path to yml file containing all config fields (see docs/create-vm-extension/README.md for format)"`
 		VarsFile        []string `long:"vars-file"          short:"l"   description:"Load variables from a YAML file"`
 		VarsEnv         []string `long:"vars-env"                       description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars            []string `long:"var"                            description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore       string   `long:"vars-store"                     description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
 		OpsFile         []string `long:"ops-file"           short:"o"   description:"YAML operations file"`
 		CloudProperties string   `long:"cloud-properties"   short:"cp"  description:"cloud properties in JSON format"`
 	}
@@ -53,6 +55,8 @@ func (c CreateVMExtension) Execute(args []string) error {
 			varsFiles:    c.Options.VarsFile,
 			environFunc:  c.environFunc,
 			varsEnvs:     c.Options.VarsEnv,
+			vars:         c.Options.Vars,
+			varsStore:    c.Options.VarsStore,
 			opsFiles:     c.Options.OpsFile,
 		}, "")
 		if err != nil {
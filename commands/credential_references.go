@@ -51,16 +51,21 @@ func (cr CredentialReferences) Execute(args []string) error {
 	}
 
 	output, err := cr.service.ListDeployedProductCredentials(deployedProductGUID)
-	sort.Strings(output.Credentials)
 	if err != nil {
 		return fmt.Errorf("failed to list credential references: %s", err)
 	}
+	sort.Strings(output.Credentials)
 
-	if len(output.Credentials) == 0 {
+	// the json is parsed by scripts, which expect a list even when it is empty
+	if len(output.Credentials) == 0 && cr.Options.Format != "json" {
 		cr.logger.Printf("no credential references found")
 		return nil
 	}
 
+	if output.Credentials == nil {
+		output.Credentials = []string{}
+	}
+
 	cr.presenter.SetFormat(cr.Options.Format)
 	cr.presenter.PresentCredentialReferences(output.Credentials)
 
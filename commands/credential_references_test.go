@@ -123,6 +123,23 @@ var _ = Describe("CredentialReferences", func() {
 				})
 			})
 
+			Context("when there are no credential references to list as json", func() {
+				It("presents an empty list", func() {
+					fakeService.ListDeployedProductCredentialsReturns(api.CredentialReferencesOutput{}, nil)
+
+					err := command.Execute([]string{
+						"--product-name", "some-product",
+						"--format", "json",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintfCallCount()).To(Equal(0))
+					Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+					Expect(fakePresenter.PresentCredentialReferencesCallCount()).To(Equal(1))
+					Expect(fakePresenter.PresentCredentialReferencesArgsForCall(0)).To(Equal([]string{}))
+				})
+			})
+
 			Context("when the credential references cannot be fetched", func() {
 				It("returns an error", func() {
 					command := commands.NewCredentialReferences(fakeService, fakePresenter, logger)
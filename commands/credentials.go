@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
@@ -15,7 +17,7 @@ type Credentials struct {
 	Options   struct {
 		Product             string `long:"product-name"         short:"p" required:"true" description:"name of deployed product"`
 		CredentialReference string `long:"credential-reference" short:"c" required:"true" description:"name of credential reference"`
-		CredentialField     string `long:"credential-field"     short:"f"                 description:"single credential field to output"`
+		CredentialField     string `long:"credential-field"     short:"f"                 description:"single credential field to output, printed as is regardless of the format"`
 		Format              string `long:"format"               short:"t" default:"table" description:"Format to print as (options: table,json)"`
 	}
 }
@@ -32,7 +34,7 @@ func NewCredentials(csService credentialsService, presenter presenters.Formatted
 
 func (cs Credentials) Execute(args []string) error {
 	if _, err := jhanda.Parse(&cs.Options, args); err != nil {
-		return fmt.Errorf("could not parse credential-references flags: %s", err)
+		return fmt.Errorf("could not parse credentials flags: %s", err)
 	}
 
 	deployedProductGUID := ""
@@ -70,7 +72,13 @@ func (cs Credentials) Execute(args []string) error {
 		if value, ok := output.Credential.Value[cs.Options.CredentialField]; ok {
 			cs.logger.Println(value)
 		} else {
-			return fmt.Errorf("credential field %q not found", cs.Options.CredentialField)
+			var fields []string
+			for field := range output.Credential.Value {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+
+			return fmt.Errorf("credential field %q not found, the fields of %q are: %s", cs.Options.CredentialField, cs.Options.CredentialReference, strings.Join(fields, ", "))
 		}
 	}
 
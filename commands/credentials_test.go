@@ -92,7 +92,7 @@ var _ = Describe("Credentials", func() {
 					err := command.Execute([]string{
 						"--credential-reference", "some-credential",
 					})
-					Expect(err).To(MatchError("could not parse credential-references flags: missing required flag \"--product-name\""))
+					Expect(err).To(MatchError("could not parse credentials flags: missing required flag \"--product-name\""))
 				})
 			})
 
@@ -103,7 +103,7 @@ var _ = Describe("Credentials", func() {
 					err := command.Execute([]string{
 						"--product-name", "some-product",
 					})
-					Expect(err).To(MatchError("could not parse credential-references flags: missing required flag \"--credential-reference\""))
+					Expect(err).To(MatchError("could not parse credentials flags: missing required flag \"--credential-reference\""))
 				})
 			})
 
@@ -211,7 +211,7 @@ var _ = Describe("Credentials", func() {
 						"--credential-reference", "some-credential",
 						"--credential-field", "missing-field",
 					})
-					Expect(err).To(MatchError(ContainSubstring(`credential field "missing-field" not found`)))
+					Expect(err).To(MatchError(`credential field "missing-field" not found, the fields of "some-credential" are: identity, password`))
 				})
 			})
 		})
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"fmt"
+
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
 )
@@ -8,11 +10,16 @@ import (
 type DeleteUnusedProducts struct {
 	service deleteUnusedProductsService
 	logger  logger
+	Options struct {
+		Product string `long:"product-name" short:"p" description:"only delete unused versions of the named product"`
+	}
 }
 
 //go:generate counterfeiter -o ./fakes/delete_unused_products_service.go --fake-name DeleteUnusedProductsService . deleteUnusedProductsService
 type deleteUnusedProductsService interface {
 	DeleteAvailableProducts(input api.DeleteAvailableProductsInput) error
+	GetDiagnosticReport() (api.DiagnosticReport, error)
+	ListAvailableProducts() (api.AvailableProductsOutput, error)
 }
 
 func NewDeleteUnusedProducts(service deleteUnusedProductsService, logger logger) DeleteUnusedProducts {
@@ -23,23 +30,91 @@ func NewDeleteUnusedProducts(service deleteUnusedProductsService, logger logger)
 }
 
 func (dup DeleteUnusedProducts) Execute(args []string) error {
-	dup.logger.Printf("trashing unused products")
+	if _, err := jhanda.Parse(&dup.Options, args); err != nil {
+		return fmt.Errorf("could not parse delete-unused-products flags: %s", err)
+	}
+
+	before, err := dup.service.ListAvailableProducts()
+	if err != nil {
+		return err
+	}
+
+	if dup.Options.Product == "" {
+		dup.logger.Printf("trashing unused products")
 
-	err := dup.service.DeleteAvailableProducts(api.DeleteAvailableProductsInput{
-		ShouldDeleteAllProducts: true,
-	})
+		err = dup.service.DeleteAvailableProducts(api.DeleteAvailableProductsInput{
+			ShouldDeleteAllProducts: true,
+		})
+	} else {
+		dup.logger.Printf("trashing unused versions of %s", dup.Options.Product)
+
+		err = dup.deleteUnusedVersions(before.ProductsList)
+	}
+	if err != nil {
+		return err
+	}
+
+	after, err := dup.service.ListAvailableProducts()
 	if err != nil {
 		return err
 	}
 
+	remaining := map[api.ProductInfo]bool{}
+	for _, product := range after.ProductsList {
+		remaining[product] = true
+	}
+
+	var deleted int
+	for _, product := range before.ProductsList {
+		if !remaining[product] {
+			dup.logger.Printf("deleted %s %s", product.Name, product.Version)
+			deleted++
+		}
+	}
+
+	if deleted == 0 {
+		dup.logger.Printf("no unused products were found")
+	}
+
 	dup.logger.Printf("done")
 
 	return nil
 }
 
+func (dup DeleteUnusedProducts) deleteUnusedVersions(availableProducts []api.ProductInfo) error {
+	report, err := dup.service.GetDiagnosticReport()
+	if err != nil {
+		return err
+	}
+
+	inUse := map[string]bool{}
+	for _, product := range append(report.StagedProducts, report.DeployedProducts...) {
+		if product.Name == dup.Options.Product {
+			inUse[product.Version] = true
+		}
+	}
+
+	for _, product := range availableProducts {
+		if product.Name != dup.Options.Product || inUse[product.Version] {
+			continue
+		}
+
+		err = dup.service.DeleteAvailableProducts(api.DeleteAvailableProductsInput{
+			ProductName:    product.Name,
+			ProductVersion: product.Version,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (dup DeleteUnusedProducts) Usage() jhanda.Usage {
 	return jhanda.Usage{
 		Description:      "This command deletes unused products in the targeted Ops Manager",
 		ShortDescription: "deletes unused products on the Ops Manager targeted",
+		Flags:            dup.Options,
 	}
 }
@@ -27,7 +27,27 @@ var _ = Describe("DeleteProduct", func() {
 	})
 
 	Describe("Execute", func() {
+		var availableProducts api.AvailableProductsOutput
+
+		BeforeEach(func() {
+			availableProducts = api.AvailableProductsOutput{
+				ProductsList: []api.ProductInfo{
+					{Name: "some-product", Version: "1.0.0"},
+					{Name: "some-product", Version: "1.1.0"},
+					{Name: "some-product", Version: "1.2.0"},
+					{Name: "other-product", Version: "2.0.0"},
+				},
+			}
+			fakeService.ListAvailableProductsReturnsOnCall(0, availableProducts, nil)
+		})
+
 		It("deletes all the product", func() {
+			fakeService.ListAvailableProductsReturnsOnCall(1, api.AvailableProductsOutput{
+				ProductsList: []api.ProductInfo{
+					{Name: "some-product", Version: "1.2.0"},
+				},
+			}, nil)
+
 			err := command.Execute([]string{})
 			Expect(err).NotTo(HaveOccurred())
 
@@ -42,19 +62,106 @@ var _ = Describe("DeleteProduct", func() {
 			Expect(fmt.Sprintf(format, content...)).To(Equal("trashing unused products"))
 
 			format, content = logger.PrintfArgsForCall(1)
+			Expect(fmt.Sprintf(format, content...)).To(Equal("deleted some-product 1.0.0"))
+
+			format, content = logger.PrintfArgsForCall(2)
+			Expect(fmt.Sprintf(format, content...)).To(Equal("deleted some-product 1.1.0"))
+
+			format, content = logger.PrintfArgsForCall(3)
+			Expect(fmt.Sprintf(format, content...)).To(Equal("deleted other-product 2.0.0"))
+
+			format, content = logger.PrintfArgsForCall(4)
 			Expect(fmt.Sprintf(format, content...)).To(Equal("done"))
 		})
+
+		Context("when there are no unused products", func() {
+			It("reports that nothing was deleted", func() {
+				fakeService.ListAvailableProductsReturnsOnCall(1, availableProducts, nil)
+
+				err := command.Execute([]string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				format, content := logger.PrintfArgsForCall(1)
+				Expect(fmt.Sprintf(format, content...)).To(Equal("no unused products were found"))
+			})
+		})
+
+		Context("when the product-name flag is provided", func() {
+			It("only deletes the versions of the product that are not staged or deployed", func() {
+				fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{
+					StagedProducts: []api.DiagnosticProduct{
+						{Name: "some-product", Version: "1.2.0"},
+					},
+					DeployedProducts: []api.DiagnosticProduct{
+						{Name: "some-product", Version: "1.1.0"},
+						{Name: "other-product", Version: "1.0.0"},
+					},
+				}, nil)
+				fakeService.ListAvailableProductsReturnsOnCall(1, api.AvailableProductsOutput{
+					ProductsList: []api.ProductInfo{
+						{Name: "some-product", Version: "1.1.0"},
+						{Name: "some-product", Version: "1.2.0"},
+						{Name: "other-product", Version: "2.0.0"},
+					},
+				}, nil)
+
+				err := command.Execute([]string{"--product-name", "some-product"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeService.DeleteAvailableProductsCallCount()).To(Equal(1))
+				Expect(fakeService.DeleteAvailableProductsArgsForCall(0)).To(Equal(api.DeleteAvailableProductsInput{
+					ProductName:    "some-product",
+					ProductVersion: "1.0.0",
+				}))
+
+				format, content := logger.PrintfArgsForCall(0)
+				Expect(fmt.Sprintf(format, content...)).To(Equal("trashing unused versions of some-product"))
+
+				format, content = logger.PrintfArgsForCall(1)
+				Expect(fmt.Sprintf(format, content...)).To(Equal("deleted some-product 1.0.0"))
+
+				format, content = logger.PrintfArgsForCall(2)
+				Expect(fmt.Sprintf(format, content...)).To(Equal("done"))
+			})
+		})
 	})
 
 	Context("when an error occurs", func() {
+		Context("when an unknown flag is provided", func() {
+			It("returns an error", func() {
+				err := command.Execute([]string{"--badflag"})
+				Expect(err).To(MatchError("could not parse delete-unused-products flags: flag provided but not defined: -badflag"))
+			})
+		})
+
 		Context("when deleting all products fails", func() {
 			It("returns an error", func() {
 				fakeService.DeleteAvailableProductsReturns(errors.New("something bad happened"))
 
-				err := command.Execute([]string{"-p", "nah", "-v", "nope"})
+				err := command.Execute([]string{})
 				Expect(err).To(MatchError("something bad happened"))
 			})
 		})
+
+		Context("when the available products cannot be listed", func() {
+			It("returns an error", func() {
+				fakeService.ListAvailableProductsReturns(api.AvailableProductsOutput{}, errors.New("could not list products"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list products"))
+				Expect(fakeService.DeleteAvailableProductsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the diagnostic report cannot be fetched", func() {
+			It("returns an error", func() {
+				fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{}, errors.New("bad diagnostic report"))
+
+				err := command.Execute([]string{"--product-name", "some-product"})
+				Expect(err).To(MatchError("bad diagnostic report"))
+				Expect(fakeService.DeleteAvailableProductsCallCount()).To(Equal(0))
+			})
+		})
 	})
 
 	Describe("Usage", func() {
@@ -63,6 +170,7 @@ var _ = Describe("DeleteProduct", func() {
 			Expect(usage).To(Equal(jhanda.Usage{
 				Description:      "This command deletes unused products in the targeted Ops Manager",
 				ShortDescription: "deletes unused products on the Ops Manager targeted",
+				Flags:            command.Options,
 			}))
 		})
 	})
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/config"
+	"gopkg.in/yaml.v2"
+)
+
+//go:generate counterfeiter -o ./fakes/delete_vm_extension_service.go --fake-name DeleteVMExtensionService . deleteVMExtensionService
+type deleteVMExtensionService interface {
+	DeleteVMExtension(name string) error
+}
+
+type DeleteVMExtension struct {
+	environFunc func() []string
+	service     deleteVMExtensionService
+	logger      logger
+	Options     struct {
+		Name       string   `long:"name"      short:"n" description:"VM extension name"`
+		ConfigFile string   `long:"config"    short:"c" description:"path to the yml file given to create-vm-extension, the name of the VM extension is read from it"`
+		VarsFile   []string `long:"vars-file" short:"l" description:"Load variables from a YAML file"`
+		VarsEnv    []string `long:"vars-env"            description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars       []string `long:"var"                 description:"Load variable from the command line. Format: VAR=VAL"`
+	}
+}
+
+func NewDeleteVMExtension(environFunc func() []string, service deleteVMExtensionService, logger logger) DeleteVMExtension {
+	return DeleteVMExtension{
+		environFunc: environFunc,
+		service:     service,
+		logger:      logger,
+	}
+}
+
+func (d DeleteVMExtension) Execute(args []string) error {
+	if _, err := jhanda.Parse(&d.Options, args); err != nil {
+		return fmt.Errorf("could not parse delete-vm-extension flags: %s", err)
+	}
+
+	if (d.Options.Name == "") == (d.Options.ConfigFile == "") {
+		return errors.New("VM Extension name must be provided by either the --name or the --config flag")
+	}
+
+	name := d.Options.Name
+	if d.Options.ConfigFile != "" {
+		configContents, err := interpolate(interpolateOptions{
+			templateFile: d.Options.ConfigFile,
+			varsFiles:    d.Options.VarsFile,
+			environFunc:  d.environFunc,
+			varsEnvs:     d.Options.VarsEnv,
+			vars:         d.Options.Vars,
+		}, "")
+		if err != nil {
+			return err
+		}
+
+		var cfg config.VMExtensionConfig
+		err = yaml.Unmarshal(configContents, &cfg)
+		if err != nil {
+			return fmt.Errorf("%s could not be parsed as valid configuration: %s", d.Options.ConfigFile, err)
+		}
+
+		if cfg.VMExtension.Name == "" {
+			return errors.New("Config file must contain name element")
+		}
+		name = cfg.VMExtension.Name
+	}
+
+	err := d.service.DeleteVMExtension(name)
+	if err != nil {
+		return fmt.Errorf("could not delete the VM extension '%s': %s", name, err)
+	}
+
+	d.logger.Printf("VM Extension '%s' deleted\n", name)
+
+	return nil
+}
+
+func (d DeleteVMExtension) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This deletes a VM extension, given by its name or by the config file it was created from",
+		ShortDescription: "deletes a VM extension",
+		Flags:            d.Options,
+	}
+}
@@ -0,0 +1,113 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+)
+
+var _ = Describe("DeleteVMExtension", func() {
+	var (
+		fakeService *fakes.DeleteVMExtensionService
+		fakeLogger  *fakes.Logger
+		command     commands.DeleteVMExtension
+		configFile  *os.File
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.DeleteVMExtensionService{}
+		fakeLogger = &fakes.Logger{}
+		command = commands.NewDeleteVMExtension(func() []string { return nil }, fakeService, fakeLogger)
+	})
+
+	AfterEach(func() {
+		if configFile != nil {
+			os.RemoveAll(configFile.Name())
+		}
+	})
+
+	writeConfigFile := func(contents string) string {
+		var err error
+		configFile, err = ioutil.TempFile("", "vm-extension.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = configFile.WriteString(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configFile.Close()).To(Succeed())
+
+		return configFile.Name()
+	}
+
+	Describe("Execute", func() {
+		It("deletes the VM extension given by name", func() {
+			err := command.Execute([]string{"--name", "some-vm-extension"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.DeleteVMExtensionCallCount()).To(Equal(1))
+			Expect(fakeService.DeleteVMExtensionArgsForCall(0)).To(Equal("some-vm-extension"))
+
+			format, content := fakeLogger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, content...)).To(Equal("VM Extension 'some-vm-extension' deleted\n"))
+		})
+
+		It("deletes the VM extension named in the config file", func() {
+			err := command.Execute([]string{
+				"--config", writeConfigFile(ymlVMExtensionFile),
+				"--var", "vm_extension_name=some-vm-extension",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.DeleteVMExtensionArgsForCall(0)).To(Equal("some-vm-extension"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when neither the name nor the config is given", func() {
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("VM Extension name must be provided by either the --name or the --config flag"))
+			})
+
+			It("returns an error when both the name and the config are given", func() {
+				err := command.Execute([]string{"--name", "some-vm-extension", "--config", "some-file.yml"})
+				Expect(err).To(MatchError("VM Extension name must be provided by either the --name or the --config flag"))
+			})
+
+			It("returns an error when the config file has no name", func() {
+				err := command.Execute([]string{"--config", writeConfigFile(ymlVMExtensionNoNameFile)})
+				Expect(err).To(MatchError("Config file must contain name element"))
+
+				Expect(fakeService.DeleteVMExtensionCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the config file cannot be parsed", func() {
+				path := writeConfigFile("vm-extension-config: some-string")
+
+				err := command.Execute([]string{"--config", path})
+				Expect(err).To(MatchError(ContainSubstring(path + " could not be parsed as valid configuration")))
+			})
+
+			It("returns an error when the VM extension cannot be deleted", func() {
+				fakeService.DeleteVMExtensionReturns(errors.New("some error"))
+
+				err := command.Execute([]string{"--name", "some-vm-extension"})
+				Expect(err).To(MatchError("could not delete the VM extension 'some-vm-extension': some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This deletes a VM extension, given by its name or by the config file it was created from",
+				ShortDescription: "deletes a VM extension",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -3,16 +3,23 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
+	"gopkg.in/yaml.v2"
 )
 
+// redactedValue replaces the values of the manifest that look like secrets
+const redactedValue = "(redacted)"
+
 type DeployedManifest struct {
 	service deployedManifestService
 	logger  logger
 	Options struct {
-		ProductName string `long:"product-name" short:"p" required:"true" description:"name of product"`
+		ProductName string `long:"product-name" short:"p" description:"name of product"`
+		Director    bool   `long:"director"               description:"print the deployed manifest of the director instead of a product"`
+		NoRedact    bool   `long:"no-redact"              description:"include the values that look like secrets, which are redacted by default"`
 	}
 }
 
@@ -20,6 +27,7 @@ type DeployedManifest struct {
 type deployedManifestService interface {
 	ListDeployedProducts() ([]api.DeployedProductOutput, error)
 	GetDeployedProductManifest(guid string) (string, error)
+	GetDeployedDirectorManifest() (string, error)
 }
 
 func NewDeployedManifest(service deployedManifestService, logger logger) DeployedManifest {
@@ -34,11 +42,45 @@ func (dm DeployedManifest) Execute(args []string) error {
 		return fmt.Errorf("could not parse staged-manifest flags: %s", err)
 	}
 
-	output, err := dm.service.ListDeployedProducts()
+	if dm.Options.ProductName == "" && !dm.Options.Director {
+		return errors.New("please provide either --product-name or --director")
+	}
+
+	if dm.Options.ProductName != "" && dm.Options.Director {
+		return errors.New("cannot use both --product-name and --director; please choose one or the other")
+	}
+
+	var (
+		manifest string
+		err      error
+	)
+	if dm.Options.Director {
+		manifest, err = dm.service.GetDeployedDirectorManifest()
+	} else {
+		manifest, err = dm.productManifest()
+	}
 	if err != nil {
 		return err
 	}
 
+	if !dm.Options.NoRedact {
+		manifest, err = redactManifest(manifest)
+		if err != nil {
+			return fmt.Errorf("could not redact the deployed manifest: %s", err)
+		}
+	}
+
+	dm.logger.Print(manifest)
+
+	return nil
+}
+
+func (dm DeployedManifest) productManifest() (string, error) {
+	output, err := dm.service.ListDeployedProducts()
+	if err != nil {
+		return "", err
+	}
+
 	var guid string
 	for _, product := range output {
 		if product.Type == dm.Options.ProductName {
@@ -48,23 +90,57 @@ func (dm DeployedManifest) Execute(args []string) error {
 	}
 
 	if guid == "" {
-		return errors.New("could not find given product")
+		return "", errors.New("could not find given product")
 	}
 
-	manifest, err := dm.service.GetDeployedProductManifest(guid)
+	return dm.service.GetDeployedProductManifest(guid)
+}
+
+// redactManifest replaces the values of the manifest whose key looks like a
+// secret, as in diff, and the PEM encoded values, which are certificates or
+// their keys.
+func redactManifest(manifest string) (string, error) {
+	var contents interface{}
+	err := yaml.Unmarshal([]byte(manifest), &contents)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	dm.logger.Print(manifest)
+	redacted, err := yaml.Marshal(redactValue(contents))
+	if err != nil {
+		return "", err // un-tested
+	}
 
-	return nil
+	return string(redacted), nil
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			if child != nil && secretPathPattern.MatchString(fmt.Sprintf("%v", key)) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(child)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = redactValue(element)
+		}
+	case string:
+		if strings.Contains(v, "-----BEGIN") {
+			return redactedValue
+		}
+	}
+
+	return value
 }
 
 func (dm DeployedManifest) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "This authenticated command prints the deployed manifest for a product",
-		ShortDescription: "prints the deployed manifest for a product",
+		Description:      "This authenticated command prints the deployed manifest for a product or the director. The values that look like secrets are redacted, unless --no-redact is set",
+		ShortDescription: "prints the deployed manifest for a product or the director",
 		Flags:            dm.Options,
 	}
 }
@@ -52,6 +52,77 @@ key: value
 `))
 	})
 
+	It("prints the manifest of the deployed director", func() {
+		fakeService.GetDeployedDirectorManifestReturns(`---
+name: p-bosh
+`, nil)
+
+		err := command.Execute([]string{"--director"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeService.GetDeployedDirectorManifestCallCount()).To(Equal(1))
+		Expect(fakeService.ListDeployedProductsCallCount()).To(Equal(0))
+
+		Expect(logger.PrintArgsForCall(0)[0]).To(MatchYAML("name: p-bosh"))
+	})
+
+	Describe("the secrets of the manifest", func() {
+		const manifest = `---
+name: some-product
+instance_groups:
+- name: router
+  properties:
+    router:
+      status:
+        user: router-status
+        password: some-password
+      tls:
+        ca: |
+          -----BEGIN CERTIFICATE-----
+          some-ca
+          -----END CERTIFICATE-----
+      client_secret: ((client_secret))
+    uaa:
+      credentials:
+        username: admin
+        password: some-password
+      empty_secret:
+`
+
+		BeforeEach(func() {
+			fakeService.GetDeployedProductManifestReturns(manifest, nil)
+		})
+
+		It("redacts the values that look like secrets", func() {
+			err := command.Execute([]string{"--product-name", "some-product"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintArgsForCall(0)[0]).To(MatchYAML(`---
+name: some-product
+instance_groups:
+- name: router
+  properties:
+    router:
+      status:
+        user: router-status
+        password: (redacted)
+      tls:
+        ca: (redacted)
+      client_secret: (redacted)
+    uaa:
+      credentials: (redacted)
+      empty_secret:
+`))
+		})
+
+		It("prints them with --no-redact", func() {
+			err := command.Execute([]string{"--product-name", "some-product", "--no-redact"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintArgsForCall(0)[0]).To(MatchYAML(manifest))
+		})
+	})
+
 	Context("failure cases", func() {
 		Context("when the flags cannot be parsed", func() {
 			It("returns an error", func() {
@@ -62,6 +133,32 @@ key: value
 			})
 		})
 
+		It("returns an error when neither the product nor the director is given", func() {
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("please provide either --product-name or --director"))
+		})
+
+		It("returns an error when both the product and the director are given", func() {
+			err := command.Execute([]string{"--product-name", "some-product", "--director"})
+			Expect(err).To(MatchError("cannot use both --product-name and --director; please choose one or the other"))
+		})
+
+		Context("when the manifest of the director cannot be returned", func() {
+			It("returns an error", func() {
+				fakeService.GetDeployedDirectorManifestReturns("", errors.New("manifest could not be retrieved"))
+				err := command.Execute([]string{"--director"})
+				Expect(err).To(MatchError("manifest could not be retrieved"))
+			})
+		})
+
+		Context("when the manifest cannot be parsed to be redacted", func() {
+			It("returns an error", func() {
+				fakeService.GetDeployedProductManifestReturns("%%%", nil)
+				err := command.Execute([]string{"--product-name", "some-product"})
+				Expect(err).To(MatchError(ContainSubstring("could not redact the deployed manifest")))
+			})
+		})
+
 		Context("when the deployed products cannot be listed", func() {
 			It("returns an error", func() {
 				fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{}, errors.New("deployed products cannot be listed"))
@@ -97,8 +194,8 @@ key: value
 		It("returns usage info", func() {
 			usage := command.Usage()
 			Expect(usage).To(Equal(jhanda.Usage{
-				Description:      "This authenticated command prints the deployed manifest for a product",
-				ShortDescription: "prints the deployed manifest for a product",
+				Description:      "This authenticated command prints the deployed manifest for a product or the director. The values that look like secrets are redacted, unless --no-redact is set",
+				ShortDescription: "prints the deployed manifest for a product or the director",
 				Flags:            command.Options,
 			}))
 		})
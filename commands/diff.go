@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"gopkg.in/yaml.v2"
+)
+
+type Diff struct {
+	service diffService
+	logger  logger
+	Options struct {
+		ProductNames []string `long:"product-name" short:"p" description:"only diff the manifests of the given product(s), the director is not diffed"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/diff_service.go --fake-name DiffService . diffService
+type diffService interface {
+	GetStagedDirectorManifest() (string, error)
+	GetDeployedDirectorManifest() (string, error)
+	ListStagedProducts() (api.StagedProductsOutput, error)
+	ListDeployedProducts() ([]api.DeployedProductOutput, error)
+	GetStagedProductManifest(guid string) (string, error)
+	GetDeployedProductManifest(guid string) (string, error)
+}
+
+func NewDiff(service diffService, logger logger) Diff {
+	return Diff{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (d Diff) Execute(args []string) error {
+	if _, err := jhanda.Parse(&d.Options, args); err != nil {
+		return fmt.Errorf("could not parse diff flags: %s", err)
+	}
+
+	if len(d.Options.ProductNames) == 0 {
+		err := d.diffDirector()
+		if err != nil {
+			return err
+		}
+	}
+
+	stagedProducts, err := d.service.ListStagedProducts()
+	if err != nil {
+		return fmt.Errorf("could not list staged products: %s", err)
+	}
+
+	deployedProducts, err := d.service.ListDeployedProducts()
+	if err != nil {
+		return fmt.Errorf("could not list deployed products: %s", err)
+	}
+
+	var names []string
+	staged := map[string]string{}
+	for _, product := range stagedProducts.Products {
+		if product.Type == "p-bosh" {
+			continue
+		}
+		names = append(names, product.Type)
+		staged[product.Type] = product.GUID
+	}
+
+	deployed := map[string]string{}
+	for _, product := range deployedProducts {
+		if product.Type == "p-bosh" {
+			continue
+		}
+		if _, ok := staged[product.Type]; !ok {
+			names = append(names, product.Type)
+		}
+		deployed[product.Type] = product.GUID
+	}
+
+	if len(d.Options.ProductNames) > 0 {
+		for _, name := range d.Options.ProductNames {
+			if staged[name] == "" && deployed[name] == "" {
+				return fmt.Errorf("could not find product %s: it is not staged or deployed", name)
+			}
+		}
+		names = d.Options.ProductNames
+	}
+
+	for _, name := range names {
+		err := d.diffProduct(name, staged[name], deployed[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d Diff) diffDirector() error {
+	d.logger.Printf("## director")
+
+	deployedManifest, err := d.service.GetDeployedDirectorManifest()
+	if err != nil {
+		return fmt.Errorf("could not fetch the deployed director manifest: %s", err)
+	}
+
+	stagedManifest, err := d.service.GetStagedDirectorManifest()
+	if err != nil {
+		return fmt.Errorf("could not fetch the staged director manifest: %s", err)
+	}
+
+	return d.printDiff("director", deployedManifest, stagedManifest)
+}
+
+func (d Diff) diffProduct(name, stagedGUID, deployedGUID string) error {
+	d.logger.Printf("## product: %s", name)
+
+	if deployedGUID == "" {
+		d.logger.Printf("not deployed yet, it will be installed on the next apply-changes")
+		return nil
+	}
+
+	if stagedGUID == "" {
+		d.logger.Printf("no longer staged, it will be deleted on the next apply-changes")
+		return nil
+	}
+
+	deployedManifest, err := d.service.GetDeployedProductManifest(deployedGUID)
+	if err != nil {
+		return fmt.Errorf("could not fetch the deployed manifest of %s: %s", name, err)
+	}
+
+	stagedManifest, err := d.service.GetStagedProductManifest(stagedGUID)
+	if err != nil {
+		return fmt.Errorf("could not fetch the staged manifest of %s: %s", name, err)
+	}
+
+	return d.printDiff(name, deployedManifest, stagedManifest)
+}
+
+func (d Diff) printDiff(name, deployedManifest, stagedManifest string) error {
+	var deployed, staged map[interface{}]interface{}
+
+	err := yaml.Unmarshal([]byte(deployedManifest), &deployed)
+	if err != nil {
+		return fmt.Errorf("could not parse the deployed manifest of %s: %s", name, err)
+	}
+
+	err = yaml.Unmarshal([]byte(stagedManifest), &staged)
+	if err != nil {
+		return fmt.Errorf("could not parse the staged manifest of %s: %s", name, err)
+	}
+
+	lines := manifestDiff(deployed, staged)
+	if len(lines) == 0 {
+		d.logger.Printf("no changes")
+		return nil
+	}
+
+	for _, line := range lines {
+		d.logger.Printf("%s", line)
+	}
+
+	return nil
+}
+
+func (d Diff) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This authenticated command compares the deployed and staged manifests of the director and products, " +
+			"listing the changed releases, stemcells, instance groups and properties that the next apply-changes will deploy. " +
+			"Values that look like secrets are redacted.",
+		ShortDescription: "displays the changes between the deployed and staged manifests",
+		Flags:            d.Options,
+	}
+}
@@ -0,0 +1,264 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const deployedCFManifest = `---
+name: cf-guid
+releases:
+- name: cf-networking
+  version: "2.8.0"
+- name: capi
+  version: "1.71.0"
+- name: old-release
+  version: "1"
+stemcells:
+- alias: default
+  os: ubuntu-xenial
+  version: "97.28"
+instance_groups:
+- name: diego_cell
+  instances: 3
+  azs: [z1, z2]
+  jobs:
+  - name: rep
+    properties:
+      diego:
+        executor:
+          memory_capacity_mb: 1000
+      tls:
+        private_key: some-old-key
+- name: router
+  instances: 2
+properties:
+  uaa:
+    admin_password: old-password
+update:
+  canaries: 1
+`
+
+const stagedCFManifest = `---
+name: cf-guid
+releases:
+- name: cf-networking
+  version: "2.9.0"
+- name: capi
+  version: "1.71.0"
+- name: routing
+  version: "0.180.0"
+stemcells:
+- alias: default
+  os: ubuntu-xenial
+  version: "97.43"
+instance_groups:
+- name: diego_cell
+  instances: 4
+  azs: [z1, z2, z3]
+  jobs:
+  - name: rep
+    properties:
+      diego:
+        executor:
+          memory_capacity_mb: 2000
+      tls:
+        private_key: some-new-key
+- name: router
+  instances: 2
+- name: tcp_router
+  instances: 1
+properties:
+  uaa:
+    admin_password: new-password
+update:
+  canaries: 1
+  max_in_flight: 2
+`
+
+var _ = Describe("Diff", func() {
+	var (
+		service *fakes.DiffService
+		logger  *fakes.Logger
+		command commands.Diff
+		output  []string
+	)
+
+	BeforeEach(func() {
+		service = &fakes.DiffService{}
+		logger = &fakes.Logger{}
+		command = commands.NewDiff(service, logger)
+
+		output = []string{}
+		logger.PrintfStub = func(format string, v ...interface{}) {
+			output = append(output, fmt.Sprintf(format, v...))
+		}
+
+		service.GetDeployedDirectorManifestReturns("name: p-bosh\ninstance_groups: [{name: bosh, instances: 1}]\n", nil)
+		service.GetStagedDirectorManifestReturns("name: p-bosh\ninstance_groups: [{name: bosh, instances: 1}]\n", nil)
+
+		service.ListStagedProductsReturns(api.StagedProductsOutput{
+			Products: []api.StagedProduct{
+				{GUID: "p-bosh-guid", Type: "p-bosh"},
+				{GUID: "cf-guid", Type: "cf"},
+				{GUID: "p-redis-guid", Type: "p-redis"},
+			},
+		}, nil)
+		service.ListDeployedProductsReturns([]api.DeployedProductOutput{
+			{GUID: "p-bosh-guid", Type: "p-bosh"},
+			{GUID: "cf-guid", Type: "cf"},
+			{GUID: "p-mysql-guid", Type: "p-mysql"},
+		}, nil)
+
+		service.GetDeployedProductManifestReturns(deployedCFManifest, nil)
+		service.GetStagedProductManifestReturns(stagedCFManifest, nil)
+	})
+
+	Describe("Execute", func() {
+		It("diffs the deployed and staged manifests of the director and every product", func() {
+			err := command.Execute([]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.GetDeployedProductManifestArgsForCall(0)).To(Equal("cf-guid"))
+			Expect(service.GetStagedProductManifestArgsForCall(0)).To(Equal("cf-guid"))
+
+			Expect(output).To(Equal([]string{
+				"## director",
+				"no changes",
+				"## product: cf",
+				"releases:",
+				"  ~ cf-networking.version: 2.8.0 -> 2.9.0",
+				"  - old-release",
+				"  + routing",
+				"stemcells:",
+				"  ~ default.version: 97.28 -> 97.43",
+				"instance_groups:",
+				"  ~ diego_cell.azs: [z1 z2] -> [z1 z2 z3]",
+				"  ~ diego_cell.instances: 3 -> 4",
+				"  ~ diego_cell.jobs.rep.properties.diego.executor.memory_capacity_mb: 1000 -> 2000",
+				"  ~ diego_cell.jobs.rep.properties.tls.private_key: (redacted)",
+				"  + tcp_router",
+				"properties:",
+				"  ~ uaa.admin_password: (redacted)",
+				"update:",
+				"  + max_in_flight: 2",
+				"## product: p-redis",
+				"not deployed yet, it will be installed on the next apply-changes",
+				"## product: p-mysql",
+				"no longer staged, it will be deleted on the next apply-changes",
+			}))
+		})
+
+		It("redacts certificates wherever they are", func() {
+			service.GetDeployedProductManifestReturns("properties:\n  some_value: \"-----BEGIN CERTIFICATE-----\\nold\"\n", nil)
+			service.GetStagedProductManifestReturns("properties:\n  some_value: \"-----BEGIN CERTIFICATE-----\\nnew\"\n", nil)
+
+			err := command.Execute([]string{"--product-name", "cf"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(output).To(Equal([]string{
+				"## product: cf",
+				"properties:",
+				"  ~ some_value: (redacted) -> (redacted)",
+			}))
+		})
+
+		Context("when product names are given", func() {
+			It("only diffs those products", func() {
+				err := command.Execute([]string{"--product-name", "p-redis", "--product-name", "p-mysql"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.GetDeployedDirectorManifestCallCount()).To(Equal(0))
+				Expect(service.GetStagedDirectorManifestCallCount()).To(Equal(0))
+
+				Expect(output).To(Equal([]string{
+					"## product: p-redis",
+					"not deployed yet, it will be installed on the next apply-changes",
+					"## product: p-mysql",
+					"no longer staged, it will be deleted on the next apply-changes",
+				}))
+			})
+
+			It("returns an error when a product is not staged or deployed", func() {
+				err := command.Execute([]string{"--product-name", "unknown"})
+				Expect(err).To(MatchError("could not find product unknown: it is not staged or deployed"))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when an unknown flag is provided", func() {
+				err := command.Execute([]string{"--badflag"})
+				Expect(err).To(MatchError("could not parse diff flags: flag provided but not defined: -badflag"))
+			})
+
+			It("returns an error when the deployed director manifest cannot be fetched", func() {
+				service.GetDeployedDirectorManifestReturns("", errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not fetch the deployed director manifest: some error"))
+			})
+
+			It("returns an error when the staged director manifest cannot be fetched", func() {
+				service.GetStagedDirectorManifestReturns("", errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not fetch the staged director manifest: some error"))
+			})
+
+			It("returns an error when the staged products cannot be listed", func() {
+				service.ListStagedProductsReturns(api.StagedProductsOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list staged products: some error"))
+			})
+
+			It("returns an error when the deployed products cannot be listed", func() {
+				service.ListDeployedProductsReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list deployed products: some error"))
+			})
+
+			It("returns an error when the deployed manifest of a product cannot be fetched", func() {
+				service.GetDeployedProductManifestReturns("", errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError("could not fetch the deployed manifest of cf: some error"))
+			})
+
+			It("returns an error when the staged manifest of a product cannot be fetched", func() {
+				service.GetStagedProductManifestReturns("", errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError("could not fetch the staged manifest of cf: some error"))
+			})
+
+			It("returns an error when a manifest cannot be parsed", func() {
+				service.GetStagedProductManifestReturns("%%%", nil)
+
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError(ContainSubstring("could not parse the staged manifest of cf:")))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description: "This authenticated command compares the deployed and staged manifests of the director and products, " +
+					"listing the changed releases, stemcells, instance groups and properties that the next apply-changes will deploy. " +
+					"Values that look like secrets are redacted.",
+				ShortDescription: "displays the changes between the deployed and staged manifests",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type DirectorVerifiers struct {
+	service   directorVerifiersService
+	presenter presenters.FormattedPresenter
+	Options   struct {
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/director_verifiers_service.go --fake-name DirectorVerifiersService . directorVerifiersService
+type directorVerifiersService interface {
+	ListDirectorVerifiers() ([]api.Verifier, error)
+}
+
+func NewDirectorVerifiers(service directorVerifiersService, presenter presenters.FormattedPresenter) DirectorVerifiers {
+	return DirectorVerifiers{service: service, presenter: presenter}
+}
+
+func (d DirectorVerifiers) Execute(args []string) error {
+	if _, err := jhanda.Parse(&d.Options, args); err != nil {
+		return fmt.Errorf("could not parse director-verifiers flags: %s", err)
+	}
+
+	verifiers, err := d.service.ListDirectorVerifiers()
+	if err != nil {
+		return fmt.Errorf("could not list the director verifiers: %s", err)
+	}
+
+	d.presenter.SetFormat(d.Options.Format)
+	d.presenter.PresentVerifiers(verifiers)
+
+	return nil
+}
+
+func (d DirectorVerifiers) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command lists the verifiers run against the director before an installation, and whether they are enabled.",
+		ShortDescription: "lists the director verifiers",
+		Flags:            d.Options,
+	}
+}
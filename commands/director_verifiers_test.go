@@ -0,0 +1,67 @@
+package commands_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("DirectorVerifiers", func() {
+	var (
+		fakeService   *fakes.DirectorVerifiersService
+		fakePresenter *presenterfakes.FormattedPresenter
+		command       commands.DirectorVerifiers
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.DirectorVerifiersService{}
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		command = commands.NewDirectorVerifiers(fakeService, fakePresenter)
+	})
+
+	Describe("Execute", func() {
+		It("presents the director verifiers", func() {
+			verifiers := []api.Verifier{
+				{Type: "NetworksPingableVerifier", Enabled: true},
+				{Type: "IaasConfigurationVerifier", Enabled: false},
+			}
+			fakeService.ListDirectorVerifiersReturns(verifiers, nil)
+
+			err := command.Execute([]string{"--format", "json"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+			Expect(fakePresenter.PresentVerifiersArgsForCall(0)).To(Equal(verifiers))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when an unknown flag is provided", func() {
+				err := command.Execute([]string{"--badflag"})
+				Expect(err).To(MatchError("could not parse director-verifiers flags: flag provided but not defined: -badflag"))
+			})
+
+			It("returns an error when the verifiers cannot be listed", func() {
+				fakeService.ListDirectorVerifiersReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list the director verifiers: some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command lists the verifiers run against the director before an installation, and whether they are enabled.",
+				ShortDescription: "lists the director verifiers",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
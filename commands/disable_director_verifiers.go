@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+)
+
+type DisableDirectorVerifiers struct {
+	service disableDirectorVerifiersService
+	logger  logger
+	Options struct {
+		VerifierTypes []string `long:"type" short:"t" required:"true" description:"type of the verifier to disable, repeated or delimited by comma (e.g. NetworksPingableVerifier)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/disable_director_verifiers_service.go --fake-name DisableDirectorVerifiersService . disableDirectorVerifiersService
+type disableDirectorVerifiersService interface {
+	ListDirectorVerifiers() ([]api.Verifier, error)
+	DisableDirectorVerifiers(verifierTypes []string) error
+}
+
+func NewDisableDirectorVerifiers(service disableDirectorVerifiersService, logger logger) DisableDirectorVerifiers {
+	return DisableDirectorVerifiers{service: service, logger: logger}
+}
+
+func (d DisableDirectorVerifiers) Execute(args []string) error {
+	if _, err := jhanda.Parse(&d.Options, args); err != nil {
+		return fmt.Errorf("could not parse disable-director-verifiers flags: %s", err)
+	}
+
+	verifierTypes := splitVerifierTypes(d.Options.VerifierTypes)
+
+	verifiers, err := d.service.ListDirectorVerifiers()
+	if err != nil {
+		return fmt.Errorf("could not list the director verifiers: %s", err)
+	}
+
+	err = checkVerifierTypes(verifiers, verifierTypes, "director verifiers")
+	if err != nil {
+		return err
+	}
+
+	err = d.service.DisableDirectorVerifiers(verifierTypes)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Printf("Disabled the director verifiers: %s\n", strings.Join(verifierTypes, ", "))
+
+	return nil
+}
+
+// splitVerifierTypes returns the types of a repeated flag whose values can
+// also be delimited by comma.
+func splitVerifierTypes(values []string) []string {
+	var verifierTypes []string
+	for _, value := range values {
+		for _, verifierType := range strings.Split(value, ",") {
+			if verifierType = strings.TrimSpace(verifierType); verifierType != "" {
+				verifierTypes = append(verifierTypes, verifierType)
+			}
+		}
+	}
+
+	return verifierTypes
+}
+
+// checkVerifierTypes rejects the types that are not among the verifiers, so
+// that nothing is disabled when one of them is misspelled.
+func checkVerifierTypes(verifiers []api.Verifier, verifierTypes []string, kind string) error {
+	known := map[string]bool{}
+	var knownTypes []string
+	for _, verifier := range verifiers {
+		known[verifier.Type] = true
+		knownTypes = append(knownTypes, verifier.Type)
+	}
+
+	var unknownTypes []string
+	for _, verifierType := range verifierTypes {
+		if !known[verifierType] {
+			unknownTypes = append(unknownTypes, verifierType)
+		}
+	}
+
+	if len(unknownTypes) > 0 {
+		return fmt.Errorf("unknown %s: %s\nthe %s are: %s", kind, strings.Join(unknownTypes, ", "), kind, strings.Join(knownTypes, ", "))
+	}
+
+	return nil
+}
+
+func (d DisableDirectorVerifiers) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command disables the given verifiers of the director, so that they are not run before the next installations.",
+		ShortDescription: "disables director verifiers",
+		Flags:            d.Options,
+	}
+}
@@ -0,0 +1,91 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+)
+
+var _ = Describe("DisableDirectorVerifiers", func() {
+	var (
+		fakeService *fakes.DisableDirectorVerifiersService
+		logger      *fakes.Logger
+		command     commands.DisableDirectorVerifiers
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.DisableDirectorVerifiersService{}
+		logger = &fakes.Logger{}
+		command = commands.NewDisableDirectorVerifiers(fakeService, logger)
+
+		fakeService.ListDirectorVerifiersReturns([]api.Verifier{
+			{Type: "NetworksPingableVerifier", Enabled: true},
+			{Type: "IaasConfigurationVerifier", Enabled: true},
+			{Type: "DirectorStemcellVerifier", Enabled: true},
+		}, nil)
+	})
+
+	Describe("Execute", func() {
+		It("disables the verifiers given, repeated or delimited by comma", func() {
+			err := command.Execute([]string{
+				"--type", "NetworksPingableVerifier, IaasConfigurationVerifier",
+				"--type", "DirectorStemcellVerifier",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.DisableDirectorVerifiersCallCount()).To(Equal(1))
+			Expect(fakeService.DisableDirectorVerifiersArgsForCall(0)).To(Equal([]string{
+				"NetworksPingableVerifier",
+				"IaasConfigurationVerifier",
+				"DirectorStemcellVerifier",
+			}))
+
+			format, args := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, args...)).To(Equal("Disabled the director verifiers: NetworksPingableVerifier, IaasConfigurationVerifier, DirectorStemcellVerifier\n"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the type flag is missing", func() {
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not parse disable-director-verifiers flags: missing required flag \"--type\""))
+			})
+
+			It("does not disable anything when a verifier is unknown", func() {
+				err := command.Execute([]string{"--type", "NetworksPingableVerifier,SomeVerifier"})
+				Expect(err).To(MatchError("unknown director verifiers: SomeVerifier\nthe director verifiers are: NetworksPingableVerifier, IaasConfigurationVerifier, DirectorStemcellVerifier"))
+
+				Expect(fakeService.DisableDirectorVerifiersCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the verifiers cannot be listed", func() {
+				fakeService.ListDirectorVerifiersReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{"--type", "NetworksPingableVerifier"})
+				Expect(err).To(MatchError("could not list the director verifiers: some error"))
+			})
+
+			It("returns an error when a verifier cannot be disabled", func() {
+				fakeService.DisableDirectorVerifiersReturns(errors.New("some error"))
+
+				err := command.Execute([]string{"--type", "NetworksPingableVerifier"})
+				Expect(err).To(MatchError("some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command disables the given verifiers of the director, so that they are not run before the next installations.",
+				ShortDescription: "disables director verifiers",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type DisableProductVerifiers struct {
+	service   disableProductVerifiersService
+	presenter presenters.FormattedPresenter
+	logger    logger
+	Options   struct {
+		ProductName   string   `long:"product-name" short:"p" required:"true" description:"name of the staged product"`
+		VerifierTypes []string `long:"type"         short:"t"                 description:"type of the verifier to disable, repeated or delimited by comma; the verifiers of the product are listed when not given"`
+		Format        string   `long:"format"       short:"f" default:"table" description:"Format to list the verifiers as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/disable_product_verifiers_service.go --fake-name DisableProductVerifiersService . disableProductVerifiersService
+type disableProductVerifiersService interface {
+	GetStagedProductByName(productName string) (api.StagedProductsFindOutput, error)
+	ListProductVerifiers(productGUID string) ([]api.Verifier, error)
+	DisableProductVerifiers(productGUID string, verifierTypes []string) error
+}
+
+func NewDisableProductVerifiers(service disableProductVerifiersService, presenter presenters.FormattedPresenter, logger logger) DisableProductVerifiers {
+	return DisableProductVerifiers{service: service, presenter: presenter, logger: logger}
+}
+
+func (d DisableProductVerifiers) Execute(args []string) error {
+	if _, err := jhanda.Parse(&d.Options, args); err != nil {
+		return fmt.Errorf("could not parse disable-product-verifiers flags: %s", err)
+	}
+
+	product, err := d.service.GetStagedProductByName(d.Options.ProductName)
+	if err != nil {
+		return fmt.Errorf("could not find the staged product %q: %s", d.Options.ProductName, err)
+	}
+
+	verifiers, err := d.service.ListProductVerifiers(product.Product.GUID)
+	if err != nil {
+		return fmt.Errorf("could not list the verifiers of %q: %s", d.Options.ProductName, err)
+	}
+
+	verifierTypes := splitVerifierTypes(d.Options.VerifierTypes)
+	if len(verifierTypes) == 0 {
+		d.presenter.SetFormat(d.Options.Format)
+		d.presenter.PresentVerifiers(verifiers)
+		return nil
+	}
+
+	err = checkVerifierTypes(verifiers, verifierTypes, fmt.Sprintf("verifiers of %q", d.Options.ProductName))
+	if err != nil {
+		return err
+	}
+
+	err = d.service.DisableProductVerifiers(product.Product.GUID, verifierTypes)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Printf("Disabled the verifiers of %s: %s\n", d.Options.ProductName, strings.Join(verifierTypes, ", "))
+
+	return nil
+}
+
+func (d DisableProductVerifiers) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command disables the given verifiers of a staged product, so that they are not run before the next installations. Without --type, it lists the verifiers of the product, and whether they are enabled.",
+		ShortDescription: "disables product verifiers",
+		Flags:            d.Options,
+	}
+}
@@ -0,0 +1,121 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("DisableProductVerifiers", func() {
+	var (
+		fakeService   *fakes.DisableProductVerifiersService
+		fakePresenter *presenterfakes.FormattedPresenter
+		logger        *fakes.Logger
+		command       commands.DisableProductVerifiers
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.DisableProductVerifiersService{}
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		logger = &fakes.Logger{}
+		command = commands.NewDisableProductVerifiers(fakeService, fakePresenter, logger)
+
+		fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{
+			Product: api.StagedProduct{GUID: "cf-guid", Type: "cf"},
+		}, nil)
+		fakeService.ListProductVerifiersReturns([]api.Verifier{
+			{Type: "WildcardDomainVerifier", Enabled: true},
+			{Type: "AppSSHVerifier", Enabled: true},
+		}, nil)
+	})
+
+	Describe("Execute", func() {
+		It("disables the verifiers of the product given, repeated or delimited by comma", func() {
+			err := command.Execute([]string{
+				"--product-name", "cf",
+				"--type", "WildcardDomainVerifier,",
+				"--type", "AppSSHVerifier",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.GetStagedProductByNameArgsForCall(0)).To(Equal("cf"))
+			Expect(fakeService.ListProductVerifiersArgsForCall(0)).To(Equal("cf-guid"))
+
+			Expect(fakeService.DisableProductVerifiersCallCount()).To(Equal(1))
+			guid, verifierTypes := fakeService.DisableProductVerifiersArgsForCall(0)
+			Expect(guid).To(Equal("cf-guid"))
+			Expect(verifierTypes).To(Equal([]string{"WildcardDomainVerifier", "AppSSHVerifier"}))
+
+			format, args := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, args...)).To(Equal("Disabled the verifiers of cf: WildcardDomainVerifier, AppSSHVerifier\n"))
+
+			Expect(fakePresenter.PresentVerifiersCallCount()).To(Equal(0))
+		})
+
+		It("lists the verifiers of the product when no type is given", func() {
+			err := command.Execute([]string{"--product-name", "cf", "--format", "json"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+			Expect(fakePresenter.PresentVerifiersArgsForCall(0)).To(Equal([]api.Verifier{
+				{Type: "WildcardDomainVerifier", Enabled: true},
+				{Type: "AppSSHVerifier", Enabled: true},
+			}))
+
+			Expect(fakeService.DisableProductVerifiersCallCount()).To(Equal(0))
+			Expect(logger.PrintfCallCount()).To(Equal(0))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the product name flag is missing", func() {
+				err := command.Execute([]string{"--type", "AppSSHVerifier"})
+				Expect(err).To(MatchError("could not parse disable-product-verifiers flags: missing required flag \"--product-name\""))
+			})
+
+			It("returns an error when the product is not staged", func() {
+				fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError(`could not find the staged product "cf": some error`))
+			})
+
+			It("returns an error when the verifiers cannot be listed", func() {
+				fakeService.ListProductVerifiersReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError(`could not list the verifiers of "cf": some error`))
+			})
+
+			It("does not disable anything when a verifier is unknown", func() {
+				err := command.Execute([]string{"--product-name", "cf", "--type", "AppSSHVerifier,SomeVerifier"})
+				Expect(err).To(MatchError("unknown verifiers of \"cf\": SomeVerifier\nthe verifiers of \"cf\" are: WildcardDomainVerifier, AppSSHVerifier"))
+
+				Expect(fakeService.DisableProductVerifiersCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when a verifier cannot be disabled", func() {
+				fakeService.DisableProductVerifiersReturns(errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf", "--type", "AppSSHVerifier"})
+				Expect(err).To(MatchError("some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command disables the given verifiers of a staged product, so that they are not run before the next installations. Without --type, it lists the verifiers of the product, and whether they are enabled.",
+				ShortDescription: "disables product verifiers",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
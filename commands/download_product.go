@@ -1,38 +1,77 @@
 package commands
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/graymeta/stow"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/telemetry"
 	"github.com/pivotal-cf/pivnet-cli/filter"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/pivotal-cf/go-pivnet"
 	pivnetlog "github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/progress"
 	"github.com/pivotal-cf/om/validator"
 	"github.com/pivotal-cf/pivnet-cli/gp"
 )
 
 const DownloadProductOutputFilename = "download-file.json"
 
+// sha256FileExtension is the extension of the file that the sha256 published
+// by Pivotal Network for a stemcell is written to, to be persisted along with
+// the stemcell in the blobstore, and verified when it is downloaded from it.
+const sha256FileExtension = ".sha256"
+
+// the verification of a stemcell against the sha256 published for it
+const (
+	sha256Verified   = "verified"
+	sha256Unverified = "unverified"
+)
+
+const (
+	outputLayoutFlat        = "flat"
+	outputLayoutSlug        = "slug"
+	outputLayoutSlugVersion = "slug/version"
+)
+
+var outputLayouts = []string{outputLayoutFlat, outputLayoutSlug, outputLayoutSlugVersion}
+
+// latestVersionPattern matches the versions resolved to the newest version in
+// the blobstore: latest, or the latest of a line such as 2.latest or 2.6.latest
+var latestVersionPattern = regexp.MustCompile(`^(?:(\d+)\.(?:(\d+)\.)?)?latest$`)
+
 type outputList struct {
-	ProductPath     string `json:"product_path,omitempty"`
-	ProductSlug     string `json:"product_slug,omitempty"`
-	StemcellPath    string `json:"stemcell_path,omitempty"`
-	StemcellVersion string `json:"stemcell_version,omitempty"`
+	ProductPath                string `json:"product_path,omitempty"`
+	ProductSlug                string `json:"product_slug,omitempty"`
+	ProductSHA256              string `json:"product_sha256,omitempty"`
+	ProductSHA256Verification  string `json:"product_sha256_verification,omitempty"`
+	StemcellPath               string `json:"stemcell_path,omitempty"`
+	StemcellVersion            string `json:"stemcell_version,omitempty"`
+	StemcellSHA256             string `json:"stemcell_sha256,omitempty"`
+	StemcellSHA256Verification string `json:"stemcell_sha256_verification,omitempty"`
 }
 
 type ProductDownloader interface {
 	GetAllProductVersions(slug string) ([]string, error)
 	GetLatestProductFile(slug, version, glob string) (*FileArtifact, error)
 	DownloadProductToFile(fa *FileArtifact, file *os.File) error
+	DownloadProductStream(fa *FileArtifact) (io.ReadCloser, int64, error)
 	DownloadProductStemcell(fa *FileArtifact) (*stemcell, error)
 }
 
@@ -40,6 +79,13 @@ func DefaultPivnetFactory(config pivnet.ClientConfig, logger pivnetlog.Logger) P
 	return gp.NewClient(config, logger)
 }
 
+//go:generate counterfeiter -o ./fakes/download_product_service.go --fake-name DownloadProductService . downloadProductService
+type downloadProductService interface {
+	UploadAvailableProduct(api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error)
+	ListAvailableProducts() (api.AvailableProductsOutput, error)
+	DeleteAvailableProducts(api.DeleteAvailableProductsInput) error
+}
+
 type DefaultStow struct{}
 
 func (d DefaultStow) Dial(kind string, config Config) (stow.Location, error) {
@@ -50,34 +96,58 @@ func (d DefaultStow) Walk(container stow.Container, prefix string, pageSize int,
 	return stow.Walk(container, prefix, pageSize, fn)
 }
 
+// BucketRegion asks AWS for the region of the bucket. The request is not
+// signed, and the hint only picks the partition that is asked.
+func (d DefaultStow) BucketRegion(bucket, regionHint string) (string, error) {
+	sess, err := session.NewSession()
+	if err != nil {
+		return "", err
+	}
+
+	return s3manager.GetBucketRegion(aws.BackgroundContext(), sess, bucket, regionHint)
+}
+
 type DownloadProduct struct {
 	environFunc    func() []string
 	logger         pivnetlog.Logger
 	progressWriter io.Writer
+	stdout         io.Writer
 	pivnetFactory  PivnetFactory
 	stower         Stower
+	multipart      multipart
+	service        downloadProductService
 	downloadClient ProductDownloader
 	Options        struct {
 		Blobstore           string   `long:"blobstore"             short:"b"  description:"enables download from external blobstores when set to \"s3\". if not provided, files will be downloaded from Pivnet"`
 		ConfigFile          string   `long:"config"                short:"c"  description:"path to yml file for configuration (keys must match the following command line flags)"`
-		OutputDir           string   `long:"output-directory"      short:"o"  description:"directory path to which the file will be outputted. File Name will be preserved from Pivotal Network" required:"true"`
+		OutputDir           string   `long:"output-directory"      short:"o"  description:"directory path to which the file will be outputted. File Name will be preserved from Pivotal Network. required unless --stdout or --upload-to-opsman is set, download-file.json is only written when it is given with --upload-to-opsman"`
+		OutputLayout        string   `long:"output-layout"         default:"flat" description:"layout of the files in the output directory (options: flat,slug,slug/version). slug writes the files of a product in a directory named after its slug, slug/version in a subdirectory of it named after the version"`
 		PivnetFileGlob      string   `long:"pivnet-file-glob"      short:"f"  description:"glob to match files within Pivotal Network product to be downloaded." required:"true"`
 		PivnetProductSlug   string   `long:"pivnet-product-slug"   short:"p"  description:"path to product" required:"true"`
 		PivnetToken         string   `long:"pivnet-api-token"      short:"t"  description:"API token to use when interacting with Pivnet. Can be retrieved from your profile page in Pivnet." required:"true"`
-		ProductVersion      string   `long:"product-version"       short:"v"  description:"version of the product-slug to download files from. Incompatible with --product-version-regex flag."`
+		ProductVersion      string   `long:"product-version"       short:"v"  description:"version of the product-slug to download files from. With --blobstore s3, 'latest' downloads the newest version, and e.g. '2.latest' or '2.6.latest' the newest version of a major or minor line. Incompatible with --product-version-regex flag."`
 		ProductVersionRegex string   `long:"product-version-regex" short:"r"  description:"regex pattern matching versions of the product-slug to download files from. Highest-versioned match will be used. Incompatible with --product-version flag."`
+		IncludePrereleases  bool     `long:"include-prereleases"              description:"consider the prerelease versions, e.g. 2.9.12-build.1, when resolving a latest --product-version with --blobstore s3. they are skipped by default"`
 		S3Bucket            string   `long:"s3-bucket"                        description:"bucket name where the product resides in the s3 compatible blobstore"`
 		S3AccessKeyID       string   `long:"s3-access-key-id"                 description:"access key for the s3 compatible blobstore"`
 		S3SecretAccessKey   string   `long:"s3-secret-access-key"             description:"secret key for the s3 compatible blobstore"`
-		S3RegionName        string   `long:"s3-region-name"                   description:"bucket region in the s3 compatible blobstore. If not using AWS, this value is 'region'"`
+		S3RegionName        string   `long:"s3-region-name"                   description:"bucket region in the s3 compatible blobstore. If not using AWS, this value is 'region'. Looked up when omitted for AWS"`
 		S3Endpoint          string   `long:"s3-endpoint"                      description:"the endpoint to access the s3 compatible blobstore. If not using AWS, this is required"`
+		S3Partition         string   `long:"s3-partition"                     description:"the aws partition of the bucket region (options: aws,aws-us-gov,aws-cn,aws-iso,aws-iso-b). the partition is determined from the region when not given, and chooses the signing region and endpoint domain when the region-name is not an aws region, e.g. with a custom endpoint"`
 		S3DisableSSL        bool     `long:"s3-disable-ssl"                   description:"whether to disable ssl validation when contacting  the s3 compatible blobstore"`
 		S3EnableV2Signing   bool     `long:"s3-enable-v2-signing"             description:"whether to use v2 signing with your s3 compatible blobstore. (if you don't know what this is, leave blank, or set to 'false')"`
 		S3Path              string   `long:"s3-path"                          description:"specify the lookup path where the s3 artifacts are stored. for example, \"/location-name/\" will look for files under s3://bucket-name/location-name/"`
+		S3SlugAliases       []string `long:"s3-slug-alias"                    description:"another slug the files of a product were persisted under, e.g. before the product was renamed on Pivotal Network, as <slug>:<persisted slug> (e.g. elastic-runtime:cf). can be given multiple times"`
 		Stemcell            bool     `long:"download-stemcell"                description:"no-op for backwards compatibility"`
 		StemcellIaas        string   `long:"stemcell-iaas"                    description:"download the latest available stemcell for the product for the specified iaas. for example 'vsphere' or 'vcloud' or 'openstack' or 'google' or 'azure' or 'aws'"`
+		Stdout              bool     `long:"stdout"                           description:"stream the product from the blobstore to stdout instead of writing it to the output directory, e.g. to pipe it into another command. status is written to stderr. only supported with --blobstore s3"`
+		NotifyURL           string   `long:"notify-url"                       description:"url to POST a JSON summary of the download to when download-product finishes"`
+		NotifyFormat        string   `long:"notify-format"         default:"json" description:"payload posted to --notify-url (options: json,slack)"`
+		UploadToOpsman      bool     `long:"upload-to-opsman"                 description:"stream the product from the blobstore directly to the targeted Ops Manager instead of writing it to the output directory. only supported with --blobstore s3"`
 		VarsEnv             []string `long:"vars-env"                         description:"load variables from environment variables matching the provided prefix (e.g.: 'MY' to load MY_var=value)"`
 		VarsFile            []string `long:"vars-file"             short:"l"  description:"load variables from a YAML file"`
+		Vars                []string `long:"var"                              description:"load variable from the command line. Format: VAR=VAL"`
+		VarsStore           string   `long:"vars-store"                       description:"load the variables that are not provided from a secret store. supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
 	}
 }
 
@@ -85,15 +155,21 @@ func NewDownloadProduct(
 	environFunc func() []string,
 	logger pivnetlog.Logger,
 	progressWriter io.Writer,
+	stdout io.Writer,
 	factory PivnetFactory,
 	stower Stower,
+	multipart multipart,
+	service downloadProductService,
 ) *DownloadProduct {
 	return &DownloadProduct{
 		environFunc:    environFunc,
 		logger:         logger,
 		progressWriter: progressWriter,
+		stdout:         stdout,
 		pivnetFactory:  factory,
 		stower:         stower,
+		multipart:      multipart,
+		service:        service,
 	}
 }
 
@@ -106,70 +182,142 @@ func (c DownloadProduct) Usage() jhanda.Usage {
 }
 
 func (c *DownloadProduct) Execute(args []string) error {
+	started := time.Now()
+	products, err := c.execute(args)
+
+	notifyErr := notify(c.Options.NotifyURL, c.Options.NotifyFormat, newNotification("download-product", started, products, err))
+	if notifyErr != nil {
+		c.logger.Info(fmt.Sprintf("warning: %s", notifyErr))
+	}
+
+	return err
+}
+
+// execute downloads the product, and returns its slug and version once that
+// version is known.
+func (c *DownloadProduct) execute(args []string) ([]string, error) {
 	err := loadConfigFile(args, &c.Options, c.environFunc)
 	if err != nil {
-		return fmt.Errorf("could not parse download-product flags: %s", err)
+		return nil, fmt.Errorf("could not parse download-product flags: %s", err)
 	}
 
 	err = c.validate()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = c.createClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	productVersion, err := c.determineProductVersion()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	products := []string{fmt.Sprintf("%s %s", c.Options.PivnetProductSlug, productVersion)}
+
+	if c.Options.UploadToOpsman {
+		return products, c.uploadProductToOpsman(productVersion)
+	}
+
+	if c.Options.Stdout {
+		return products, c.streamProductToStdout(productVersion)
 	}
 
 	prefixPath := fmt.Sprintf("[%s,%s]", c.Options.PivnetProductSlug, productVersion)
-	productFileName, productFileArtifact, err := c.downloadProductFile(c.Options.PivnetProductSlug, productVersion, c.Options.PivnetFileGlob, prefixPath)
+	productFile, err := c.productFile(c.Options.PivnetProductSlug, productVersion, c.Options.PivnetFileGlob, prefixPath)
 	if err != nil {
-		return fmt.Errorf("could not download product: %s", err)
+		return products, fmt.Errorf("could not download product: %s", err)
 	}
 
 	if c.Options.StemcellIaas == "" {
-		return c.writeOutputFile(productFileName, "", "")
+		err = c.downloadFile(c.downloadClient, productFile)
+		if err != nil {
+			return products, fmt.Errorf("could not download product: %s", err)
+		}
+
+		output := outputList{ProductPath: productFile.path}
+
+		// a stemcell can be downloaded as the product, e.g. to persist it
+		if isStemcellSlug(c.Options.PivnetProductSlug) {
+			output.ProductSHA256, output.ProductSHA256Verification, err = c.verifyStemcell(productFile)
+			if err != nil {
+				return products, err
+			}
+		}
+
+		return products, c.writeOutputFile(output)
 	}
 
 	c.logger.Info("Downloading stemcell")
 
-	nameParts := strings.Split(productFileName, ".")
+	nameParts := strings.Split(productFile.path, ".")
 	if nameParts[len(nameParts)-1] != "pivotal" {
 		c.logger.Info("the downloaded file is not a .pivotal file. Not determining and fetching required stemcell.")
-		return nil
+
+		err = c.downloadFile(c.downloadClient, productFile)
+		if err != nil {
+			return products, fmt.Errorf("could not download product: %s", err)
+		}
+
+		return products, nil
 	}
 
-	stemcell, err := c.downloadClient.DownloadProductStemcell(productFileArtifact)
+	stemcell, err := c.downloadClient.DownloadProductStemcell(productFile.artifact)
 	if err != nil {
-		return fmt.Errorf("could not information about stemcell: %s", err)
+		return products, fmt.Errorf("could not information about stemcell: %s", err)
 	}
 
 	prefixPath = ""
-	stemcellFileName, _, err := c.downloadProductFile(stemcell.Slug, stemcell.Version, fmt.Sprintf("*%s*", c.Options.StemcellIaas), prefixPath)
+	stemcellFile, err := c.productFile(stemcell.Slug, stemcell.Version, fmt.Sprintf("*%s*", c.Options.StemcellIaas), prefixPath)
+	if err != nil {
+		return products, fmt.Errorf("could not download stemcell: %s", err)
+	}
+
+	err = c.downloadProductAndStemcell(productFile, stemcellFile)
 	if err != nil {
-		return fmt.Errorf("could not download stemcell: %s", err)
+		return products, err
 	}
 
-	return c.writeOutputFile(productFileName, stemcellFileName, stemcell.Version)
+	output := outputList{
+		ProductPath:     productFile.path,
+		StemcellPath:    stemcellFile.path,
+		StemcellVersion: stemcell.Version,
+	}
+
+	output.StemcellSHA256, output.StemcellSHA256Verification, err = c.verifyStemcell(stemcellFile)
+	if err != nil {
+		return products, err
+	}
+
+	return products, c.writeOutputFile(output)
 }
 
-func (c DownloadProduct) createS3Config() S3Configuration {
+func (c DownloadProduct) createS3Config() (S3Configuration, error) {
+	slugAliases := map[string][]string{}
+	for _, alias := range c.Options.S3SlugAliases {
+		parts := strings.Split(alias, ":")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return S3Configuration{}, fmt.Errorf("invalid --s3-slug-alias %q, expected <slug>:<persisted slug>", alias)
+		}
+		slugAliases[parts[0]] = append(slugAliases[parts[0]], parts[1])
+	}
+
 	config := S3Configuration{
 		Bucket:          c.Options.S3Bucket,
 		AccessKeyID:     c.Options.S3AccessKeyID,
 		SecretAccessKey: c.Options.S3SecretAccessKey,
 		RegionName:      c.Options.S3RegionName,
 		Endpoint:        c.Options.S3Endpoint,
+		Partition:       c.Options.S3Partition,
 		DisableSSL:      c.Options.S3DisableSSL,
 		EnableV2Signing: c.Options.S3EnableV2Signing,
 		Path:            c.Options.S3Path,
+		SlugAliases:     slugAliases,
 	}
-	return config
+	return config, nil
 }
 
 func (c *DownloadProduct) determineProductVersion() (string, error) {
@@ -206,26 +354,97 @@ func (c *DownloadProduct) determineProductVersion() (string, error) {
 
 		return versions[len(versions)-1].Original(), nil
 	}
+
+	if c.Options.Blobstore == "s3" {
+		if match := latestVersionPattern.FindStringSubmatch(c.Options.ProductVersion); match != nil {
+			return c.latestBlobstoreVersion(match[1], match[2])
+		}
+	}
+
 	return c.Options.ProductVersion, nil
 }
 
+// latestBlobstoreVersion is the newest semver version of the product in the
+// blobstore, within the major and the minor line when they are given. The
+// prerelease versions are skipped, unless --include-prereleases is set.
+func (c *DownloadProduct) latestBlobstoreVersion(major, minor string) (string, error) {
+	productVersions, err := c.downloadClient.GetAllProductVersions(c.Options.PivnetProductSlug)
+	if err != nil {
+		return "", err
+	}
+
+	semver := regexp.MustCompile(`^` + Semver2Regex + `$`)
+
+	var versions version.Collection
+	for _, productVersion := range productVersions {
+		match := semver.FindStringSubmatch(productVersion)
+		if match == nil {
+			c.logger.Info(fmt.Sprintf("warning: could not parse semver version from: %s", productVersion))
+			continue
+		}
+
+		if (major != "" && match[1] != major) || (minor != "" && match[2] != minor) {
+			continue
+		}
+
+		v, err := version.NewVersion(productVersion)
+		if err != nil {
+			continue // un-tested
+		}
+
+		if v.Prerelease() != "" && !c.Options.IncludePrereleases {
+			continue
+		}
+		versions = append(versions, v)
+	}
+
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no versions of product '%s' matching '%s' found in the blobstore", c.Options.PivnetProductSlug, c.Options.ProductVersion)
+	}
+
+	sort.Sort(versions)
+
+	latest := versions[len(versions)-1].Original()
+	c.logger.Info(fmt.Sprintf("Resolved %s to version %s of %s", c.Options.ProductVersion, latest, c.Options.PivnetProductSlug))
+
+	return latest, nil
+}
+
 func (c *DownloadProduct) createClient() error {
+	progressWriter := c.progressWriter
+	if c.Options.Stdout {
+		// stdout only carries the product, so keep the progress bar off it
+		progressWriter = ioutil.Discard
+	}
+
 	var err error
+	c.downloadClient, err = c.newClient(progressWriter)
+	return err
+}
+
+func (c *DownloadProduct) newClient(progressWriter io.Writer) (ProductDownloader, error) {
 	switch c.Options.Blobstore {
 	case "s3":
-		config := c.createS3Config()
-		c.downloadClient, err = NewS3Client(c.stower, config, c.progressWriter)
+		config, err := c.createS3Config()
 		if err != nil {
-			return fmt.Errorf("could not create an s3 client: %s", err)
+			return nil, fmt.Errorf("could not create an s3 client: %s", err)
 		}
+		client, err := NewS3Client(c.stower, config, progressWriter)
+		if err != nil {
+			return nil, fmt.Errorf("could not create an s3 client: %s", err)
+		}
+		return client, nil
 	default:
 		filter := filter.NewFilter(c.logger)
-		c.downloadClient = NewPivnetClient(c.logger, c.progressWriter, c.pivnetFactory, c.Options.PivnetToken, filter)
+		return NewPivnetClient(c.logger, progressWriter, c.pivnetFactory, c.Options.PivnetToken, filter), nil
 	}
-	return nil
 }
 
 func (c *DownloadProduct) validate() error {
+	if c.Options.OutputDir == "" && !c.Options.Stdout && !c.Options.UploadToOpsman {
+		return fmt.Errorf("could not parse download-product flags: missing required flag \"--output-directory\"")
+	}
+
 	if c.Options.ProductVersionRegex != "" && c.Options.ProductVersion != "" {
 		return fmt.Errorf("cannot use both --product-version and --product-version-regex; please choose one or the other")
 	}
@@ -233,18 +452,87 @@ func (c *DownloadProduct) validate() error {
 	if c.Options.ProductVersionRegex == "" && c.Options.ProductVersion == "" {
 		return fmt.Errorf("no version information provided; please provide either --product-version or --product-version-regex")
 	}
+
+	if err := validateNotifyFormat(c.Options.NotifyFormat); err != nil {
+		return fmt.Errorf("could not parse download-product flags: %s", err)
+	}
+
+	if !containsString(outputLayouts, c.Options.OutputLayout) {
+		return fmt.Errorf("unknown --output-layout %q, expected one of: %s", c.Options.OutputLayout, strings.Join(outputLayouts, ", "))
+	}
+
+	if c.Options.UploadToOpsman {
+		if c.Options.Blobstore != "s3" {
+			return fmt.Errorf("--upload-to-opsman is only supported when downloading from a blobstore; please provide --blobstore s3")
+		}
+
+		if c.Options.StemcellIaas != "" {
+			return fmt.Errorf("--upload-to-opsman cannot be used with --stemcell-iaas")
+		}
+	}
+
+	if c.Options.Stdout {
+		if c.Options.Blobstore != "s3" {
+			return fmt.Errorf("--stdout is only supported when downloading from a blobstore; please provide --blobstore s3")
+		}
+
+		if c.Options.StemcellIaas != "" {
+			return fmt.Errorf("--stdout cannot be used with --stemcell-iaas")
+		}
+
+		if c.Options.UploadToOpsman {
+			return fmt.Errorf("--stdout cannot be used with --upload-to-opsman")
+		}
+	}
 	return nil
 }
 
-func (c DownloadProduct) writeOutputFile(productFileName string, stemcellFileName string, stemcellVersion string) error {
-	c.logger.Info(fmt.Sprintf("Writing a list of downloaded artifact to %s", DownloadProductOutputFilename))
-	outputList := outputList{
-		ProductPath:     productFileName,
-		StemcellPath:    stemcellFileName,
-		ProductSlug:     c.Options.PivnetProductSlug,
-		StemcellVersion: stemcellVersion,
+func (c DownloadProduct) writeOutputFile(output outputList) error {
+	output.ProductSlug = c.Options.PivnetProductSlug
+	return c.writeOutputList(output)
+}
+
+func isStemcellSlug(slug string) bool {
+	return strings.Contains(slug, "stemcells")
+}
+
+// verifyStemcell checks the downloaded stemcell against the sha256 that was
+// published by Pivotal Network for it, which the blobstore has when it was
+// persisted along with the stemcell. A corrupted stemcell is removed, for it
+// not to be uploaded. When the stemcell is downloaded to be persisted, the
+// sha256 is written next to it.
+func (c DownloadProduct) verifyStemcell(file productFileDownload) (string, string, error) {
+	sum, err := validator.NewSHA256Calculator().Checksum(file.path)
+	if err != nil {
+		return "", "", fmt.Errorf("could not calculate the sha256 of the stemcell %s: %s", file.path, err)
+	}
+
+	published := file.artifact.sha256
+	if published == "" {
+		c.logger.Info(fmt.Sprintf("warning: no sha256 was published for the stemcell %s, it cannot be verified", path.Base(file.path)))
+		return sum, sha256Unverified, nil
+	}
+
+	if !strings.EqualFold(sum, published) {
+		_ = os.Remove(file.path)
+		return "", "", fmt.Errorf("the stemcell %s is corrupted and was removed: its sha256 is %s, the published sha256 is %s", path.Base(file.path), sum, published)
+	}
+
+	c.logger.Info(fmt.Sprintf("Verified the sha256 of the stemcell %s", path.Base(file.path)))
+
+	if c.Options.Blobstore == "" && c.Options.S3Bucket != "" {
+		err = ioutil.WriteFile(file.path+sha256FileExtension, []byte(published+"\n"), 0644)
+		if err != nil {
+			return "", "", fmt.Errorf("could not write the sha256 of the stemcell: %s", err)
+		}
 	}
 
+	return sum, sha256Verified, nil
+}
+
+func (c DownloadProduct) writeOutputList(outputList outputList) error {
+	c.logger.Info(fmt.Sprintf("Writing a list of downloaded artifact to %s", DownloadProductOutputFilename))
+
 	outputFile, err := os.Create(path.Join(c.Options.OutputDir, DownloadProductOutputFilename))
 	if err != nil {
 		return fmt.Errorf("could not create %s: %s", DownloadProductOutputFilename, err)
@@ -254,36 +542,295 @@ func (c DownloadProduct) writeOutputFile(productFileName string, stemcellFileNam
 	return json.NewEncoder(outputFile).Encode(outputList)
 }
 
-func (c *DownloadProduct) downloadProductFile(slug, version, glob, prefixPath string) (string, *FileArtifact, error) {
+// productFileDownload is a file to download, and the path it is written to.
+type productFileDownload struct {
+	path     string
+	artifact *FileArtifact
+}
+
+// productFile finds the file of the product matching the glob, and the path
+// to write it to.
+func (c *DownloadProduct) productFile(slug, version, glob, prefixPath string) (productFileDownload, error) {
 	fileArtifact, err := c.downloadClient.GetLatestProductFile(slug, version, glob)
 	if err != nil {
-		return "", nil, err
+		return productFileDownload{}, err
+	}
+
+	outputDir, err := c.outputDirectory(slug, version)
+	if err != nil {
+		return productFileDownload{}, err
 	}
 
 	var productFilePath string
 	if c.Options.Blobstore != "" || c.Options.S3Bucket == "" {
-		productFilePath = path.Join(c.Options.OutputDir, path.Base(fileArtifact.Name))
+		productFilePath = path.Join(outputDir, path.Base(fileArtifact.Name))
 	} else {
-		productFilePath = path.Join(c.Options.OutputDir, prefixPath+path.Base(fileArtifact.Name))
+		productFilePath = path.Join(outputDir, prefixPath+path.Base(fileArtifact.Name))
 	}
 
-	exist, err := checkFileExists(productFilePath, fileArtifact.sha256)
+	return productFileDownload{path: productFilePath, artifact: fileArtifact}, nil
+}
+
+// downloadFile downloads the file, unless it has already been downloaded.
+func (c *DownloadProduct) downloadFile(client ProductDownloader, file productFileDownload) error {
+	exist, err := checkFileExists(file.path, file.artifact.sha256)
 	if err != nil {
-		return productFilePath, nil, err
+		return err
 	}
 
 	if exist {
-		c.logger.Info(fmt.Sprintf("%s already exists, skip downloading", productFilePath))
-		return productFilePath, fileArtifact, nil
+		c.logger.Info(fmt.Sprintf("%s already exists, skip downloading", file.path))
+		return nil
 	}
 
-	productFile, err := os.Create(productFilePath)
+	productFile, err := os.Create(file.path)
 	if err != nil {
-		return "", nil, fmt.Errorf("could not create file %s: %s", productFilePath, err)
+		return fmt.Errorf("could not create file %s: %s", file.path, err)
 	}
 	defer productFile.Close()
 
-	return productFilePath, fileArtifact, c.downloadClient.DownloadProductToFile(fileArtifact, productFile)
+	span := telemetry.Start("download", telemetry.Attributes{"file": path.Base(file.path)})
+	err = client.DownloadProductToFile(file.artifact, productFile)
+	span.End(err)
+	if err != nil {
+		return err
+	}
+
+	if info, err := productFile.Stat(); err == nil {
+		telemetry.Count("om.download.bytes", "By", info.Size(), telemetry.Attributes{"file": path.Base(file.path)})
+	}
+
+	return nil
+}
+
+// downloadProductAndStemcell downloads the product and its stemcell at the
+// same time, each with a client of its own whose progress is labelled.
+func (c *DownloadProduct) downloadProductAndStemcell(product, stemcell productFileDownload) error {
+	lock := &sync.Mutex{}
+
+	productClient, err := c.newClient(progress.NewLabelledWriter(c.progressWriter, lock, "[product] "))
+	if err != nil {
+		return err // un-tested
+	}
+
+	stemcellClient, err := c.newClient(progress.NewLabelledWriter(c.progressWriter, lock, "[stemcell] "))
+	if err != nil {
+		return err // un-tested
+	}
+
+	var (
+		wg                      sync.WaitGroup
+		productErr, stemcellErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		productErr = c.downloadFile(productClient, product)
+	}()
+	go func() {
+		defer wg.Done()
+		stemcellErr = c.downloadFile(stemcellClient, stemcell)
+	}()
+	wg.Wait()
+
+	if productErr != nil {
+		return fmt.Errorf("could not download product: %s", productErr)
+	}
+
+	if stemcellErr != nil {
+		return fmt.Errorf("could not download stemcell: %s", stemcellErr)
+	}
+
+	return nil
+}
+
+// outputDirectory is the directory that the files of the version of the
+// product are written to, created when the --output-layout nests it.
+func (c *DownloadProduct) outputDirectory(slug, version string) (string, error) {
+	var dir string
+	switch c.Options.OutputLayout {
+	case outputLayoutSlug:
+		dir = path.Join(c.Options.OutputDir, slug)
+	case outputLayoutSlugVersion:
+		dir = path.Join(c.Options.OutputDir, slug, version)
+	default:
+		return c.Options.OutputDir, nil
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("could not create directory %s: %s", dir, err)
+	}
+
+	return dir, nil
+}
+
+func (c *DownloadProduct) uploadProductToOpsman(productVersion string) error {
+	fileArtifact, err := c.downloadClient.GetLatestProductFile(c.Options.PivnetProductSlug, productVersion, c.Options.PivnetFileGlob)
+	if err != nil {
+		return fmt.Errorf("could not download product: %s", err)
+	}
+
+	// the products that were already available are listed, for the product
+	// to be deleted when it does not match its sha256 once it is uploaded
+	var available []api.ProductInfo
+	if fileArtifact.sha256 != "" {
+		output, err := c.service.ListAvailableProducts()
+		if err != nil {
+			return fmt.Errorf("could not list the available products: %s", err)
+		}
+		available = output.ProductsList
+	}
+
+	blobReader, size, err := c.downloadClient.DownloadProductStream(fileArtifact)
+	if err != nil {
+		return fmt.Errorf("could not download product: %s", err)
+	}
+	defer blobReader.Close()
+
+	hash := sha256.New()
+	err = c.multipart.AddReader("product[file]", path.Base(fileArtifact.Name), ioutil.NopCloser(io.TeeReader(blobReader, hash)), size)
+	if err != nil {
+		return fmt.Errorf("could not load product: %s", err)
+	}
+
+	submission := c.multipart.Finalize()
+
+	c.logger.Info(fmt.Sprintf("Streaming %s to Ops Manager", path.Base(fileArtifact.Name)))
+	span := telemetry.Start("upload-product", telemetry.Attributes{"file": path.Base(fileArtifact.Name)})
+	_, err = c.service.UploadAvailableProduct(api.UploadAvailableProductInput{
+		Product:         submission.Content,
+		ContentType:     submission.ContentType,
+		ContentLength:   submission.ContentLength,
+		PollingInterval: 1,
+	})
+	span.End(err)
+	if err != nil {
+		return fmt.Errorf("could not upload product to Ops Manager: %s", err)
+	}
+
+	telemetry.Count("om.download.bytes", "By", size, telemetry.Attributes{"file": path.Base(fileArtifact.Name)})
+	telemetry.Count("om.upload.bytes", "By", size, telemetry.Attributes{"operation": "upload-product"})
+
+	shasum := hex.EncodeToString(hash.Sum(nil))
+	verification, err := c.verifyStreamedFile(fileArtifact, shasum)
+	if err != nil {
+		return c.deleteCorruptedProduct(path.Base(fileArtifact.Name), available, err)
+	}
+
+	c.logger.Info(fmt.Sprintf("Uploaded %s to Ops Manager (sha256: %s)", path.Base(fileArtifact.Name), shasum))
+
+	if c.Options.OutputDir == "" {
+		return nil
+	}
+
+	return c.writeOutputList(outputList{
+		ProductSlug:               c.Options.PivnetProductSlug,
+		ProductSHA256:             shasum,
+		ProductSHA256Verification: verification,
+	})
+}
+
+// deleteCorruptedProduct deletes the products that were not available
+// before the corrupted product was uploaded, which are the upload. The
+// product is left when its version was already available, as it cannot be
+// told apart from the one that was there.
+func (c *DownloadProduct) deleteCorruptedProduct(name string, available []api.ProductInfo, verifyErr error) error {
+	output, err := c.service.ListAvailableProducts()
+	if err != nil {
+		return fmt.Errorf("the product %s uploaded to Ops Manager is corrupted, and the available products could not be listed to delete it: %s: %s", name, verifyErr, err)
+	}
+
+	var deleted bool
+	for _, product := range output.ProductsList {
+		if containsProductInfo(available, product) {
+			continue
+		}
+
+		err := c.service.DeleteAvailableProducts(api.DeleteAvailableProductsInput{
+			ProductName:    product.Name,
+			ProductVersion: product.Version,
+		})
+		if err != nil {
+			return fmt.Errorf("the product %s uploaded to Ops Manager is corrupted, and could not be deleted, delete it with delete-unused-products: %s: %s", name, verifyErr, err)
+		}
+		deleted = true
+	}
+
+	if !deleted {
+		return fmt.Errorf("the product %s uploaded to Ops Manager is corrupted, its version was already available and is not deleted, delete it with delete-unused-products if it is not used: %s", name, verifyErr)
+	}
+
+	return fmt.Errorf("the product %s uploaded to Ops Manager is corrupted and was deleted: %s", name, verifyErr)
+}
+
+func containsProductInfo(products []api.ProductInfo, product api.ProductInfo) bool {
+	for _, p := range products {
+		if p == product {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyStreamedFile checks the sha256 of a file that was streamed from the
+// blobstore against the sha256 persisted along with it. The file cannot be
+// verified when no sha256 was persisted, which is only reported for a
+// stemcell, as for verifyStemcell.
+func (c DownloadProduct) verifyStreamedFile(file *FileArtifact, sum string) (string, error) {
+	if file.sha256 == "" {
+		if isStemcellSlug(c.Options.PivnetProductSlug) {
+			c.logger.Info(fmt.Sprintf("warning: no sha256 was published for the stemcell %s, it cannot be verified", path.Base(file.Name)))
+		}
+		return sha256Unverified, nil
+	}
+
+	if !strings.EqualFold(sum, file.sha256) {
+		return "", fmt.Errorf("its sha256 is %s, the published sha256 is %s", sum, file.sha256)
+	}
+
+	return sha256Verified, nil
+}
+
+// streamProductToStdout copies the product from the blobstore to stdout,
+// reporting its progress on the logger so that stdout can be piped.
+func (c *DownloadProduct) streamProductToStdout(productVersion string) error {
+	fileArtifact, err := c.downloadClient.GetLatestProductFile(c.Options.PivnetProductSlug, productVersion, c.Options.PivnetFileGlob)
+	if err != nil {
+		return fmt.Errorf("could not download product: %s", err)
+	}
+
+	blobReader, size, err := c.downloadClient.DownloadProductStream(fileArtifact)
+	if err != nil {
+		return fmt.Errorf("could not download product: %s", err)
+	}
+	defer blobReader.Close()
+
+	c.logger.Info(fmt.Sprintf("Streaming %s to stdout", path.Base(fileArtifact.Name)))
+
+	span := telemetry.Start("download", telemetry.Attributes{"file": path.Base(fileArtifact.Name)})
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(c.stdout, hash), blobReader)
+	span.End(err)
+	telemetry.Count("om.download.bytes", "By", written, telemetry.Attributes{"file": path.Base(fileArtifact.Name)})
+	if err != nil {
+		return fmt.Errorf("could not stream product to stdout: %s", err)
+	}
+
+	if written != size {
+		return fmt.Errorf("could not stream product to stdout: expected %d bytes, streamed %d", size, written)
+	}
+
+	shasum := hex.EncodeToString(hash.Sum(nil))
+	_, err = c.verifyStreamedFile(fileArtifact, shasum)
+	if err != nil {
+		return fmt.Errorf("the product %s streamed to stdout is corrupted: %s", path.Base(fileArtifact.Name), err)
+	}
+
+	c.logger.Info(fmt.Sprintf("Streamed %s to stdout (sha256: %s)", path.Base(fileArtifact.Name), shasum))
+
+	return nil
 }
 
 func checkFileExists(path, expectedSum string) (bool, error) {
@@ -1,20 +1,33 @@
 package commands_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/go-pivnet"
 	log "github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logger/loggerfakes"
 	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/formcontent"
+	"github.com/pivotal-cf/om/progress"
 	"github.com/pivotal-cf/om/validator"
 )
 
@@ -25,7 +38,10 @@ var _ = Describe("DownloadProduct", func() {
 		logger               *loggerfakes.FakeLogger
 		fakePivnetDownloader *fakes.PivnetDownloader
 		fakeStower           *mockStower
+		multipart            *formcontent.Form
+		fakeService          *fakes.DownloadProductService
 		environFunc          func() []string
+		stdout               *bytes.Buffer
 		tempDir              string
 		err                  error
 	)
@@ -34,15 +50,31 @@ var _ = Describe("DownloadProduct", func() {
 		return fakePivnetDownloader
 	}
 
+	// downloadedFile is the file that the product of the slug was downloaded
+	// to, as the product and the stemcell are downloaded concurrently
+	downloadedFile := func(slug string) (*os.File, int, int) {
+		for i := 0; i < fakePivnetDownloader.DownloadProductFileCallCount(); i++ {
+			file, downloadedSlug, releaseID, fileID, _ := fakePivnetDownloader.DownloadProductFileArgsForCall(i)
+			if downloadedSlug == slug {
+				return file, releaseID, fileID
+			}
+		}
+		Fail(fmt.Sprintf("%s was not downloaded", slug))
+		return nil, 0, 0
+	}
+
 	BeforeEach(func() {
 		logger = &loggerfakes.FakeLogger{}
 		fakePivnetDownloader = &fakes.PivnetDownloader{}
 		fakeStower = newMockStower([]mockItem{})
+		multipart = formcontent.NewForm()
+		fakeService = &fakes.DownloadProductService{}
 		environFunc = func() []string { return nil }
+		stdout = &bytes.Buffer{}
 	})
 
 	JustBeforeEach(func() {
-		command = commands.NewDownloadProduct(environFunc, logger, GinkgoWriter, fakePivnetFactory, fakeStower)
+		command = commands.NewDownloadProduct(environFunc, logger, GinkgoWriter, stdout, fakePivnetFactory, fakeStower, multipart, fakeService)
 	})
 
 	Context("when the flags are set correctly", func() {
@@ -84,6 +116,37 @@ var _ = Describe("DownloadProduct", func() {
 			Expect(fakeStower.dialCallCount).To(Equal(0))
 		})
 
+		It("writes the product in the directory of its slug with the slug layout", func() {
+			err = command.Execute(append(commandArgs, "--output-layout", "slug"))
+			Expect(err).NotTo(HaveOccurred())
+
+			productFile, _, _, _, _ := fakePivnetDownloader.DownloadProductFileArgsForCall(0)
+			Expect(productFile.Name()).To(Equal(path.Join(tempDir, "elastic-runtime", "cf-2.0-build.1.pivotal")))
+			Expect(path.Join(tempDir, commands.DownloadProductOutputFilename)).To(BeAnExistingFile())
+		})
+
+		It("does not resolve latest with Pivotal Network", func() {
+			err = command.Execute([]string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*.pivotal",
+				"--pivnet-product-slug", "elastic-runtime",
+				"--product-version", "latest",
+				"--output-directory", tempDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, version := fakePivnetDownloader.ReleaseForVersionArgsForCall(0)
+			Expect(version).To(Equal("latest"))
+		})
+
+		It("writes the product in the output directory with the flat layout", func() {
+			err = command.Execute(append(commandArgs, "--output-layout", "flat"))
+			Expect(err).NotTo(HaveOccurred())
+
+			productFile, _, _, _, _ := fakePivnetDownloader.DownloadProductFileArgsForCall(0)
+			Expect(productFile.Name()).To(Equal(path.Join(tempDir, "cf-2.0-build.1.pivotal")))
+		})
+
 		When("the blobstore flag is set to s3", func() {
 			BeforeEach(func() {
 				commandArgs = []string{
@@ -107,6 +170,11 @@ var _ = Describe("DownloadProduct", func() {
 				Expect(fakeStower.dialCallCount).Should(BeNumerically(">", 0))
 				Expect(fakePivnetDownloader.ReleaseForVersionCallCount()).To(Equal(0))
 			})
+
+			It("returns an error when an alias of a slug is not a pair of slugs", func() {
+				err = command.Execute(append(commandArgs, "--s3-slug-alias", "elastic-runtime"))
+				Expect(err).To(MatchError(`could not create an s3 client: invalid --s3-slug-alias "elastic-runtime", expected <slug>:<persisted slug>`))
+			})
 		})
 
 		Context("when a valid product-version-regex is provided", func() {
@@ -316,11 +384,8 @@ var _ = Describe("DownloadProduct", func() {
 				Expect(version).To(Equal("97.19"))
 				Expect(str).To(Equal("stemcells-ubuntu-xenial"))
 
-				fakePivnetDownloader.DownloadProductFileArgsForCall(0)
-
-				stemcellFile, slug, releaseID, fileID, _ := fakePivnetDownloader.DownloadProductFileArgsForCall(1)
+				stemcellFile, releaseID, fileID := downloadedFile("stemcells-ubuntu-xenial")
 				Expect(stemcellFile.Name()).To(Equal(path.Join(tempDir, "light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz")))
-				Expect(slug).To(Equal("stemcells-ubuntu-xenial"))
 				Expect(releaseID).To(Equal(9999))
 				Expect(fileID).To(Equal(5678))
 
@@ -334,10 +399,219 @@ var _ = Describe("DownloadProduct", func() {
 						"product_path": "%s",
 						"product_slug": "elastic-runtime",
 						"stemcell_path": "%s",
-						"stemcell_version": "97.19"
+						"stemcell_version": "97.19",
+						"stemcell_sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						"stemcell_sha256_verification": "unverified"
 					}`, downloadedFilePath, stemcellFile.Name())))
 			})
 
+			It("downloads the product and the stemcell concurrently, with a labelled progress each", func() {
+				stemcellStarted := make(chan struct{})
+				var progressWriters []io.Writer
+				var lock sync.Mutex
+				fakePivnetDownloader.DownloadProductFileStub = func(file *os.File, slug string, releaseID, fileID int, progressWriter io.Writer) error {
+					lock.Lock()
+					progressWriters = append(progressWriters, progressWriter)
+					lock.Unlock()
+
+					if slug == "stemcells-ubuntu-xenial" {
+						close(stemcellStarted)
+						return nil
+					}
+
+					select {
+					case <-stemcellStarted:
+						return nil
+					case <-time.After(5 * time.Second):
+						return errors.New("the stemcell was not downloaded while the product was downloading")
+					}
+				}
+
+				err = command.Execute([]string{
+					"--pivnet-api-token", "token",
+					"--pivnet-file-glob", "*.pivotal",
+					"--pivnet-product-slug", "elastic-runtime",
+					"--product-version", "2.0.0",
+					"--output-directory", tempDir,
+					"--stemcell-iaas", "google",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(progressWriters).To(HaveLen(2))
+				Expect(progressWriters[0]).To(BeAssignableToTypeOf(&progress.LabelledWriter{}))
+				Expect(progressWriters[1]).To(BeAssignableToTypeOf(&progress.LabelledWriter{}))
+				Expect(progressWriters[0]).NotTo(BeIdenticalTo(progressWriters[1]))
+			})
+
+			It("returns an error when the stemcell cannot be downloaded", func() {
+				fakePivnetDownloader.DownloadProductFileStub = func(file *os.File, slug string, releaseID, fileID int, progressWriter io.Writer) error {
+					if slug == "stemcells-ubuntu-xenial" {
+						return errors.New("connection reset")
+					}
+					return nil
+				}
+
+				err = command.Execute([]string{
+					"--pivnet-api-token", "token",
+					"--pivnet-file-glob", "*.pivotal",
+					"--pivnet-product-slug", "elastic-runtime",
+					"--product-version", "2.0.0",
+					"--output-directory", tempDir,
+					"--stemcell-iaas", "google",
+				})
+				Expect(err).To(MatchError("could not download stemcell: could not download product file stemcells-ubuntu-xenial: connection reset"))
+				Expect(path.Join(tempDir, commands.DownloadProductOutputFilename)).NotTo(BeAnExistingFile())
+			})
+
+			It("writes the product and the stemcell in the directories of their slug and version with the slug/version layout", func() {
+				err = command.Execute([]string{
+					"--pivnet-api-token", "token",
+					"--pivnet-file-glob", "*.pivotal",
+					"--pivnet-product-slug", "elastic-runtime",
+					"--product-version", "2.0.0",
+					"--output-directory", tempDir,
+					"--output-layout", "slug/version",
+					"--stemcell-iaas", "google",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				productFile, _, _ := downloadedFile("elastic-runtime")
+				Expect(productFile.Name()).To(Equal(path.Join(tempDir, "elastic-runtime", "2.0.0", "cf-2.0-build.1.pivotal")))
+
+				stemcellFile, _, _ := downloadedFile("stemcells-ubuntu-xenial")
+				Expect(stemcellFile.Name()).To(Equal(path.Join(tempDir, "stemcells-ubuntu-xenial", "97.19", "light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz")))
+
+				fileContent, err := ioutil.ReadFile(path.Join(tempDir, commands.DownloadProductOutputFilename))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(fileContent)).To(MatchJSON(fmt.Sprintf(`
+					{
+						"product_path": "%s",
+						"product_slug": "elastic-runtime",
+						"stemcell_path": "%s",
+						"stemcell_version": "97.19",
+						"stemcell_sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						"stemcell_sha256_verification": "unverified"
+					}`, productFile.Name(), stemcellFile.Name())))
+			})
+
+			Context("when Pivotal Network published the sha256 of the stemcell", func() {
+				// the sha256 of the empty files that the fake downloader writes
+				const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+				var stemcellSHA256 string
+
+				BeforeEach(func() {
+					stemcellSHA256 = emptySHA256
+				})
+
+				JustBeforeEach(func() {
+					fakePivnetDownloader.ProductFilesForReleaseReturnsOnCall(1, []pivnet.ProductFile{
+						{
+							ID:           5678,
+							AWSObjectKey: "/some-account/some-bucket/light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz",
+							Name:         "Example Stemcell For GCP",
+							SHA256:       stemcellSHA256,
+						},
+					}, nil)
+				})
+
+				It("verifies the stemcell and records it in the output file", func() {
+					err = command.Execute([]string{
+						"--pivnet-api-token", "token",
+						"--pivnet-file-glob", "*.pivotal",
+						"--pivnet-product-slug", "elastic-runtime",
+						"--product-version", "2.0.0",
+						"--output-directory", tempDir,
+						"--stemcell-iaas", "google",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					stemcellFile, _, _ := downloadedFile("stemcells-ubuntu-xenial")
+					Expect(stemcellFile.Name() + ".sha256").NotTo(BeAnExistingFile())
+
+					fileContent, err := ioutil.ReadFile(path.Join(tempDir, commands.DownloadProductOutputFilename))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(fileContent)).To(MatchJSON(fmt.Sprintf(`
+						{
+							"product_path": "%s",
+							"product_slug": "elastic-runtime",
+							"stemcell_path": "%s",
+							"stemcell_version": "97.19",
+							"stemcell_sha256": "%s",
+							"stemcell_sha256_verification": "verified"
+						}`, path.Join(tempDir, "cf-2.0-build.1.pivotal"), stemcellFile.Name(), emptySHA256)))
+				})
+
+				It("writes the sha256 next to the stemcell when it is downloaded to be persisted in a blobstore", func() {
+					err = command.Execute([]string{
+						"--pivnet-api-token", "token",
+						"--pivnet-file-glob", "*.pivotal",
+						"--pivnet-product-slug", "elastic-runtime",
+						"--product-version", "2.0.0",
+						"--output-directory", tempDir,
+						"--stemcell-iaas", "google",
+						"--s3-bucket", "bucket",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					stemcellFile, _, _ := downloadedFile("stemcells-ubuntu-xenial")
+					contents, err := ioutil.ReadFile(stemcellFile.Name() + ".sha256")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal(emptySHA256 + "\n"))
+				})
+
+				Context("and the stemcell does not match it", func() {
+					BeforeEach(func() {
+						stemcellSHA256 = "some-other-sha256"
+					})
+
+					It("removes the stemcell and returns an error", func() {
+						err = command.Execute([]string{
+							"--pivnet-api-token", "token",
+							"--pivnet-file-glob", "*.pivotal",
+							"--pivnet-product-slug", "elastic-runtime",
+							"--product-version", "2.0.0",
+							"--output-directory", tempDir,
+							"--stemcell-iaas", "google",
+						})
+						Expect(err).To(MatchError(fmt.Sprintf("the stemcell light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz is corrupted and was removed: its sha256 is %s, the published sha256 is some-other-sha256", emptySHA256)))
+
+						stemcellFile, _, _ := downloadedFile("stemcells-ubuntu-xenial")
+						Expect(stemcellFile.Name()).NotTo(BeAnExistingFile())
+						Expect(path.Join(tempDir, commands.DownloadProductOutputFilename)).NotTo(BeAnExistingFile())
+					})
+				})
+			})
+
+			It("verifies a stemcell that is downloaded as the product", func() {
+				fakePivnetDownloader.ProductFilesForReleaseReturnsOnCall(0, []pivnet.ProductFile{
+					{
+						ID:           5678,
+						AWSObjectKey: "/some-account/some-bucket/light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz",
+						SHA256:       "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+					},
+				}, nil)
+
+				err = command.Execute([]string{
+					"--pivnet-api-token", "token",
+					"--pivnet-file-glob", "*google*",
+					"--pivnet-product-slug", "stemcells-ubuntu-xenial",
+					"--product-version", "97.19",
+					"--output-directory", tempDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				fileContent, err := ioutil.ReadFile(path.Join(tempDir, commands.DownloadProductOutputFilename))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(fileContent)).To(MatchJSON(fmt.Sprintf(`
+					{
+						"product_path": "%s",
+						"product_slug": "stemcells-ubuntu-xenial",
+						"product_sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						"product_sha256_verification": "verified"
+					}`, path.Join(tempDir, "light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz"))))
+			})
+
 			Context("when the product is not a tile and download-stemcell flag is set", func() {
 				BeforeEach(func() {
 					fakePivnetDownloader.ReleaseForVersionReturnsOnCall(0, pivnet.Release{
@@ -571,6 +845,16 @@ output-directory: %s
 						Expect(err).NotTo(HaveOccurred())
 					})
 				})
+
+				Context("passed on the command line", func() {
+					It("can interpolate variables into the configuration", func() {
+						err = command.Execute([]string{
+							"--config", configFile.Name(),
+							"--var", "product-slug=elastic-runtime",
+						})
+						Expect(err).NotTo(HaveOccurred())
+					})
+				})
 			})
 		})
 
@@ -712,6 +996,598 @@ output-directory: %s
 		})
 	})
 
+	When("the product version is latest with the s3 blobstore", func() {
+		var blobstoreDir string
+
+		BeforeEach(func() {
+			tempDir, err = ioutil.TempDir("", "om-tests-")
+			Expect(err).NotTo(HaveOccurred())
+
+			blobstoreDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			var items []mockItem
+			for _, version := range []string{"1.10.0", "2.9.3", "2.10.1", "2.2", "2.9.12-build.1"} {
+				filename := filepath.Join(blobstoreDir, fmt.Sprintf("[mayhem-crew,%s]my-great-product.pivotal", version))
+				err = ioutil.WriteFile(filename, []byte("some-product-contents"), 0777)
+				Expect(err).NotTo(HaveOccurred())
+				items = append(items, newMockItem(filename))
+			}
+
+			fakeStower = &mockStower{
+				location:  mockLocation{container: &mockContainer{item: items[0]}},
+				itemsList: items,
+			}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+			os.RemoveAll(blobstoreDir)
+		})
+
+		execute := func(productVersion string, args ...string) error {
+			return command.Execute(append([]string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*.pivotal",
+				"--pivnet-product-slug", "mayhem-crew",
+				"--product-version", productVersion,
+				"--blobstore", "s3",
+				"--s3-bucket", "validBucket",
+				"--s3-access-key-id", "access-key",
+				"--s3-secret-access-key", "secret-key",
+				"--s3-region-name", "some-region",
+				"--s3-path", blobstoreDir,
+				"--output-directory", tempDir,
+			}, args...))
+		}
+
+		DescribeTable("downloads the newest version of the line",
+			func(productVersion, expectedVersion string, args ...string) {
+				err = execute(productVersion, args...)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(path.Join(tempDir, fmt.Sprintf("[mayhem-crew,%s]my-great-product.pivotal", expectedVersion))).To(BeAnExistingFile())
+
+				var messages []string
+				for i := 0; i < logger.InfoCallCount(); i++ {
+					message, _ := logger.InfoArgsForCall(i)
+					messages = append(messages, message)
+				}
+				Expect(messages).To(ContainElement(fmt.Sprintf("Resolved %s to version %s of mayhem-crew", productVersion, expectedVersion)))
+				Expect(messages).To(ContainElement("warning: could not parse semver version from: 2.2"))
+			},
+			Entry("latest", "latest", "2.10.1"),
+			Entry("the latest of a major line", "1.latest", "1.10.0"),
+			Entry("the latest of a minor line", "2.9.latest", "2.9.3"),
+			Entry("the latest of a minor line, with the prereleases", "2.9.latest", "2.9.12-build.1", "--include-prereleases"),
+		)
+
+		It("returns an error when the line only has prereleases", func() {
+			fakeStower.itemsList = []mockItem{newMockItem(filepath.Join(blobstoreDir, "[mayhem-crew,2.9.12-build.1]my-great-product.pivotal"))}
+
+			err = execute("2.9.latest")
+			Expect(err).To(MatchError("no versions of product 'mayhem-crew' matching '2.9.latest' found in the blobstore"))
+		})
+
+		It("downloads the files persisted under an alias of the slug", func() {
+			err = command.Execute([]string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*.pivotal",
+				"--pivnet-product-slug", "mayhem",
+				"--product-version", "latest",
+				"--blobstore", "s3",
+				"--s3-bucket", "validBucket",
+				"--s3-access-key-id", "access-key",
+				"--s3-secret-access-key", "secret-key",
+				"--s3-region-name", "some-region",
+				"--s3-path", blobstoreDir,
+				"--s3-slug-alias", "mayhem:mayhem-crew",
+				"--output-directory", tempDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(path.Join(tempDir, "[mayhem-crew,2.10.1]my-great-product.pivotal")).To(BeAnExistingFile())
+		})
+
+		It("returns an error when no version of the line is in the blobstore", func() {
+			err = execute("3.latest")
+			Expect(err).To(MatchError("no versions of product 'mayhem-crew' matching '3.latest' found in the blobstore"))
+		})
+	})
+
+	When("the stemcell is downloaded from the s3 blobstore with the sha256 persisted along with it", func() {
+		var (
+			blobstoreDir   string
+			stemcellName   string
+			stemcellSHA256 string
+		)
+
+		BeforeEach(func() {
+			tempDir, err = ioutil.TempDir("", "om-tests-")
+			Expect(err).NotTo(HaveOccurred())
+
+			blobstoreDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			stemcellName = "[stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz"
+			stemcellFile := filepath.Join(blobstoreDir, stemcellName)
+			err = ioutil.WriteFile(stemcellFile, []byte("some-stemcell-contents"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			// the sha256 of some-stemcell-contents
+			stemcellSHA256 = "67d40911f5fb0903403ddda806ba90f4af29d9bb4fd47a4d56c43666847a0113"
+
+			stemcellItem := newMockItem(stemcellFile)
+			sha256Item := newMockItem(stemcellFile + ".sha256")
+			fakeStower = &mockStower{
+				location: mockLocation{container: &mockContainer{
+					item:  stemcellItem,
+					items: map[string]mockItem{sha256Item.ID(): sha256Item},
+				}},
+				itemsList: []mockItem{stemcellItem, sha256Item},
+			}
+		})
+
+		JustBeforeEach(func() {
+			err = ioutil.WriteFile(filepath.Join(blobstoreDir, stemcellName+".sha256"), []byte(stemcellSHA256+"\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+			os.RemoveAll(blobstoreDir)
+		})
+
+		execute := func() error {
+			return command.Execute([]string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*google*",
+				"--pivnet-product-slug", "stemcells-ubuntu-xenial",
+				"--product-version", "97.19",
+				"--blobstore", "s3",
+				"--s3-bucket", "validBucket",
+				"--s3-access-key-id", "access-key",
+				"--s3-secret-access-key", "secret-key",
+				"--s3-region-name", "some-region",
+				"--s3-path", blobstoreDir,
+				"--output-directory", tempDir,
+			})
+		}
+
+		It("verifies the stemcell against the persisted sha256", func() {
+			err = execute()
+			Expect(err).NotTo(HaveOccurred())
+
+			fileContent, err := ioutil.ReadFile(path.Join(tempDir, commands.DownloadProductOutputFilename))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContent)).To(ContainSubstring(`"product_sha256_verification":"verified"`))
+		})
+
+		When("the persisted sha256 is in upper case", func() {
+			BeforeEach(func() {
+				stemcellSHA256 = strings.ToUpper(stemcellSHA256)
+			})
+
+			It("verifies the stemcell", func() {
+				err = execute()
+				Expect(err).NotTo(HaveOccurred())
+
+				fileContent, err := ioutil.ReadFile(path.Join(tempDir, commands.DownloadProductOutputFilename))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(fileContent)).To(ContainSubstring(`"product_sha256_verification":"verified"`))
+			})
+		})
+
+		When("the stemcell does not match the persisted sha256", func() {
+			BeforeEach(func() {
+				stemcellSHA256 = "some-other-sha256"
+			})
+
+			It("removes the stemcell and returns an error", func() {
+				err = execute()
+				Expect(err).To(MatchError(ContainSubstring("is corrupted and was removed")))
+				Expect(path.Join(tempDir, stemcellName)).NotTo(BeAnExistingFile())
+			})
+		})
+	})
+
+	When("the product is streamed to Ops Manager", func() {
+		var blobstoreDir string
+
+		BeforeEach(func() {
+			tempDir, err = ioutil.TempDir("", "om-tests-")
+			Expect(err).NotTo(HaveOccurred())
+
+			blobstoreDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			filename := filepath.Join(blobstoreDir, "[mayhem-crew,2.0.0]my-great-product.pivotal")
+			err = ioutil.WriteFile(filename, []byte("some-product-contents"), 0777)
+			Expect(err).NotTo(HaveOccurred())
+
+			item := newMockItem(filename)
+			fakeStower = &mockStower{
+				location:  mockLocation{container: &mockContainer{item: item}},
+				itemsList: []mockItem{item},
+			}
+
+			commandArgs = []string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*.pivotal",
+				"--pivnet-product-slug", "mayhem-crew",
+				"--product-version", "2.0.0",
+				"--blobstore", "s3",
+				"--s3-bucket", "validBucket",
+				"--s3-access-key-id", "access-key",
+				"--s3-secret-access-key", "secret-key",
+				"--s3-region-name", "some-region",
+				"--s3-path", blobstoreDir,
+				"--output-directory", tempDir,
+				"--upload-to-opsman",
+			}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+			os.RemoveAll(blobstoreDir)
+		})
+
+		It("uploads the product from the blobstore without writing it to disk", func() {
+			var uploadedContent []byte
+			fakeService.UploadAvailableProductStub = func(input api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error) {
+				uploadedContent, err = ioutil.ReadAll(input.Product)
+				return api.UploadAvailableProductOutput{}, err
+			}
+
+			err = command.Execute(commandArgs)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(1))
+			Expect(string(uploadedContent)).To(ContainSubstring(`filename="[mayhem-crew,2.0.0]my-great-product.pivotal"`))
+			Expect(string(uploadedContent)).To(ContainSubstring("some-product-contents"))
+
+			Expect(path.Join(tempDir, "[mayhem-crew,2.0.0]my-great-product.pivotal")).NotTo(BeAnExistingFile())
+
+			fileContent, err := ioutil.ReadFile(path.Join(tempDir, commands.DownloadProductOutputFilename))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContent)).To(MatchJSON(`{
+				"product_slug": "mayhem-crew",
+				"product_sha256": "3a76d14af57a9cc2ededca88affdd81117e1616d761c6a3eb1daa4d82f772eb2",
+				"product_sha256_verification": "unverified"
+			}`))
+		})
+
+		When("the sha256 of the product is persisted along with it", func() {
+			var productSHA256 string
+
+			BeforeEach(func() {
+				// the sha256 of some-product-contents
+				productSHA256 = "3a76d14af57a9cc2ededca88affdd81117e1616d761c6a3eb1daa4d82f772eb2"
+
+				productItem := newMockItem(filepath.Join(blobstoreDir, "[mayhem-crew,2.0.0]my-great-product.pivotal"))
+				sha256Item := newMockItem(productItem.ID() + ".sha256")
+				fakeStower = &mockStower{
+					location: mockLocation{container: &mockContainer{
+						item:  productItem,
+						items: map[string]mockItem{sha256Item.ID(): sha256Item},
+					}},
+					itemsList: []mockItem{productItem, sha256Item},
+				}
+
+				// the sha256 is calculated while Ops Manager reads the product
+				fakeService.UploadAvailableProductStub = func(input api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error) {
+					_, err := ioutil.ReadAll(input.Product)
+					return api.UploadAvailableProductOutput{}, err
+				}
+			})
+
+			JustBeforeEach(func() {
+				err = ioutil.WriteFile(filepath.Join(blobstoreDir, "[mayhem-crew,2.0.0]my-great-product.pivotal.sha256"), []byte(productSHA256+"\n"), 0644)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("verifies the uploaded product against it", func() {
+				err = command.Execute(commandArgs)
+				Expect(err).NotTo(HaveOccurred())
+
+				fileContent, err := ioutil.ReadFile(path.Join(tempDir, commands.DownloadProductOutputFilename))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(fileContent)).To(ContainSubstring(`"product_sha256_verification":"verified"`))
+			})
+
+			When("the uploaded product does not match it", func() {
+				BeforeEach(func() {
+					productSHA256 = "some-other-sha256"
+
+					fakeService.ListAvailableProductsReturnsOnCall(0, api.AvailableProductsOutput{ProductsList: []api.ProductInfo{
+						{Name: "cf", Version: "2.10.0"},
+					}}, nil)
+					fakeService.ListAvailableProductsReturnsOnCall(1, api.AvailableProductsOutput{ProductsList: []api.ProductInfo{
+						{Name: "cf", Version: "2.10.0"},
+						{Name: "mayhem-crew", Version: "2.0.0"},
+					}}, nil)
+				})
+
+				It("deletes the uploaded product and returns an error", func() {
+					err = command.Execute(commandArgs)
+					Expect(err).To(MatchError("the product [mayhem-crew,2.0.0]my-great-product.pivotal uploaded to Ops Manager is corrupted and was deleted: its sha256 is 3a76d14af57a9cc2ededca88affdd81117e1616d761c6a3eb1daa4d82f772eb2, the published sha256 is some-other-sha256"))
+
+					Expect(fakeService.DeleteAvailableProductsCallCount()).To(Equal(1))
+					Expect(fakeService.DeleteAvailableProductsArgsForCall(0)).To(Equal(api.DeleteAvailableProductsInput{
+						ProductName:    "mayhem-crew",
+						ProductVersion: "2.0.0",
+					}))
+
+					Expect(path.Join(tempDir, commands.DownloadProductOutputFilename)).NotTo(BeAnExistingFile())
+				})
+
+				It("does not delete the product when its version was already available", func() {
+					fakeService.ListAvailableProductsReturnsOnCall(0, api.AvailableProductsOutput{ProductsList: []api.ProductInfo{
+						{Name: "mayhem-crew", Version: "2.0.0"},
+					}}, nil)
+					fakeService.ListAvailableProductsReturnsOnCall(1, api.AvailableProductsOutput{ProductsList: []api.ProductInfo{
+						{Name: "mayhem-crew", Version: "2.0.0"},
+					}}, nil)
+
+					err = command.Execute(commandArgs)
+					Expect(err).To(MatchError(ContainSubstring("its version was already available and is not deleted, delete it with delete-unused-products if it is not used")))
+
+					Expect(fakeService.DeleteAvailableProductsCallCount()).To(Equal(0))
+				})
+
+				It("returns an error when the uploaded product cannot be deleted", func() {
+					fakeService.DeleteAvailableProductsReturns(errors.New("some delete error"))
+
+					err = command.Execute(commandArgs)
+					Expect(err).To(MatchError(ContainSubstring("could not be deleted, delete it with delete-unused-products")))
+					Expect(err).To(MatchError(ContainSubstring("some delete error")))
+				})
+			})
+
+			It("returns an error when the available products cannot be listed", func() {
+				fakeService.ListAvailableProductsReturns(api.AvailableProductsOutput{}, errors.New("some list error"))
+
+				err = command.Execute(commandArgs)
+				Expect(err).To(MatchError("could not list the available products: some list error"))
+				Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(0))
+			})
+		})
+
+		It("does not require an output directory, and only uploads the product without it", func() {
+			var args []string
+			for i := 0; i < len(commandArgs); i++ {
+				if commandArgs[i] == "--output-directory" {
+					i++
+					continue
+				}
+				args = append(args, commandArgs[i])
+			}
+
+			err = command.Execute(args)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(1))
+			Expect(path.Join(tempDir, commands.DownloadProductOutputFilename)).NotTo(BeAnExistingFile())
+		})
+
+		It("posts a summary of the download to the notify-url", func() {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+			}))
+			defer server.Close()
+
+			err = command.Execute(append(commandArgs, "--notify-url", server.URL))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(payload).To(HaveKeyWithValue("command", "download-product"))
+			Expect(payload).To(HaveKeyWithValue("status", "succeeded"))
+			Expect(payload).To(HaveKeyWithValue("products", []interface{}{"mayhem-crew 2.0.0"}))
+			Expect(payload).To(HaveKeyWithValue("errors", BeEmpty()))
+		})
+
+		It("returns an error when the upload fails", func() {
+			fakeService.UploadAvailableProductReturns(api.UploadAvailableProductOutput{}, errors.New("some upload error"))
+
+			err = command.Execute(commandArgs)
+			Expect(err).To(MatchError("could not upload product to Ops Manager: some upload error"))
+		})
+
+		It("returns an error when the blobstore is not s3", func() {
+			err = command.Execute([]string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*.pivotal",
+				"--pivnet-product-slug", "mayhem-crew",
+				"--product-version", "2.0.0",
+				"--output-directory", tempDir,
+				"--upload-to-opsman",
+			})
+			Expect(err).To(MatchError("--upload-to-opsman is only supported when downloading from a blobstore; please provide --blobstore s3"))
+		})
+	})
+
+	When("the product is streamed to stdout", func() {
+		var blobstoreDir string
+
+		BeforeEach(func() {
+			blobstoreDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			filename := filepath.Join(blobstoreDir, "[mayhem-crew,2.0.0]my-great-product.pivotal")
+			err = ioutil.WriteFile(filename, []byte("some-product-contents"), 0777)
+			Expect(err).NotTo(HaveOccurred())
+
+			item := newMockItem(filename)
+			fakeStower = &mockStower{
+				location:  mockLocation{container: &mockContainer{item: item}},
+				itemsList: []mockItem{item},
+			}
+
+			commandArgs = []string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*.pivotal",
+				"--pivnet-product-slug", "mayhem-crew",
+				"--product-version", "2.0.0",
+				"--blobstore", "s3",
+				"--s3-bucket", "validBucket",
+				"--s3-access-key-id", "access-key",
+				"--s3-secret-access-key", "secret-key",
+				"--s3-region-name", "some-region",
+				"--s3-path", blobstoreDir,
+				"--stdout",
+			}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(blobstoreDir)
+		})
+
+		It("writes only the product to stdout, without an output directory", func() {
+			err = command.Execute(commandArgs)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stdout.String()).To(Equal("some-product-contents"))
+
+			var messages []string
+			for i := 0; i < logger.InfoCallCount(); i++ {
+				message, _ := logger.InfoArgsForCall(i)
+				messages = append(messages, message)
+			}
+			Expect(messages).To(ContainElement("Streaming [mayhem-crew,2.0.0]my-great-product.pivotal to stdout"))
+			Expect(messages).To(ContainElement("Streamed [mayhem-crew,2.0.0]my-great-product.pivotal to stdout (sha256: 3a76d14af57a9cc2ededca88affdd81117e1616d761c6a3eb1daa4d82f772eb2)"))
+		})
+
+		It("returns an error when the blobstore is not s3", func() {
+			err = command.Execute([]string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*.pivotal",
+				"--pivnet-product-slug", "mayhem-crew",
+				"--product-version", "2.0.0",
+				"--stdout",
+			})
+			Expect(err).To(MatchError("--stdout is only supported when downloading from a blobstore; please provide --blobstore s3"))
+		})
+
+		It("returns an error when the stemcell is downloaded too", func() {
+			err = command.Execute(append(commandArgs, "--stemcell-iaas", "google"))
+			Expect(err).To(MatchError("--stdout cannot be used with --stemcell-iaas"))
+		})
+
+		It("returns an error when the product is uploaded to Ops Manager too", func() {
+			err = command.Execute(append(commandArgs, "--upload-to-opsman"))
+			Expect(err).To(MatchError("--stdout cannot be used with --upload-to-opsman"))
+		})
+	})
+
+	When("a stemcell is streamed from the blobstore with the sha256 persisted along with it", func() {
+		var (
+			blobstoreDir   string
+			stemcellSHA256 string
+		)
+
+		BeforeEach(func() {
+			tempDir, err = ioutil.TempDir("", "om-tests-")
+			Expect(err).NotTo(HaveOccurred())
+
+			blobstoreDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			stemcellFile := filepath.Join(blobstoreDir, "[stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz")
+			err = ioutil.WriteFile(stemcellFile, []byte("some-stemcell-contents"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			// the sha256 of some-stemcell-contents
+			stemcellSHA256 = "67d40911f5fb0903403ddda806ba90f4af29d9bb4fd47a4d56c43666847a0113"
+
+			stemcellItem := newMockItem(stemcellFile)
+			sha256Item := newMockItem(stemcellFile + ".sha256")
+			fakeStower = &mockStower{
+				location: mockLocation{container: &mockContainer{
+					item:  stemcellItem,
+					items: map[string]mockItem{sha256Item.ID(): sha256Item},
+				}},
+				itemsList: []mockItem{stemcellItem, sha256Item},
+			}
+
+			fakeService.UploadAvailableProductStub = func(input api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error) {
+				_, err := ioutil.ReadAll(input.Product)
+				return api.UploadAvailableProductOutput{}, err
+			}
+
+			commandArgs = []string{
+				"--pivnet-api-token", "token",
+				"--pivnet-file-glob", "*google*",
+				"--pivnet-product-slug", "stemcells-ubuntu-xenial",
+				"--product-version", "97.19",
+				"--blobstore", "s3",
+				"--s3-bucket", "validBucket",
+				"--s3-access-key-id", "access-key",
+				"--s3-secret-access-key", "secret-key",
+				"--s3-region-name", "some-region",
+				"--s3-path", blobstoreDir,
+			}
+		})
+
+		JustBeforeEach(func() {
+			err = ioutil.WriteFile(filepath.Join(blobstoreDir, "[stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz.sha256"), []byte(stemcellSHA256+"\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(tempDir)
+			os.RemoveAll(blobstoreDir)
+		})
+
+		It("verifies the stemcell uploaded to Ops Manager", func() {
+			err = command.Execute(append(commandArgs, "--upload-to-opsman", "--output-directory", tempDir))
+			Expect(err).NotTo(HaveOccurred())
+
+			fileContent, err := ioutil.ReadFile(path.Join(tempDir, commands.DownloadProductOutputFilename))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(fileContent)).To(ContainSubstring(`"product_sha256_verification":"verified"`))
+		})
+
+		It("verifies the stemcell streamed to stdout", func() {
+			err = command.Execute(append(commandArgs, "--stdout"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stdout.String()).To(Equal("some-stemcell-contents"))
+		})
+
+		When("the persisted sha256 is in upper case", func() {
+			BeforeEach(func() {
+				stemcellSHA256 = strings.ToUpper(stemcellSHA256)
+			})
+
+			DescribeTable("verifies the stemcell", func(args []string) {
+				err = command.Execute(append(commandArgs, args...))
+				Expect(err).NotTo(HaveOccurred())
+			},
+				Entry("when it is uploaded to Ops Manager", []string{"--upload-to-opsman"}),
+				Entry("when it is streamed to stdout", []string{"--stdout"}),
+			)
+		})
+
+		When("the stemcell does not match the persisted sha256", func() {
+			BeforeEach(func() {
+				stemcellSHA256 = "some-other-sha256"
+
+				fakeService.ListAvailableProductsReturnsOnCall(1, api.AvailableProductsOutput{ProductsList: []api.ProductInfo{
+					{Name: "stemcells-ubuntu-xenial", Version: "97.19"},
+				}}, nil)
+			})
+
+			DescribeTable("returns an error", func(args []string, message string) {
+				err = command.Execute(append(commandArgs, args...))
+				Expect(err).To(MatchError(fmt.Sprintf("the product [stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz %s: its sha256 is 67d40911f5fb0903403ddda806ba90f4af29d9bb4fd47a4d56c43666847a0113, the published sha256 is some-other-sha256", message)))
+			},
+				Entry("when it is uploaded to Ops Manager", []string{"--upload-to-opsman"}, "uploaded to Ops Manager is corrupted and was deleted"),
+				Entry("when it is streamed to stdout", []string{"--stdout"}, "streamed to stdout is corrupted"),
+			)
+		})
+	})
+
 	Context("failure cases", func() {
 		Context("when an unknown flag is provided", func() {
 			It("returns an error", func() {
@@ -723,6 +1599,16 @@ output-directory: %s
 		Context("when a required flag is not provided", func() {
 			It("returns an error", func() {
 				err = command.Execute([]string{})
+				Expect(err).To(MatchError("could not parse download-product flags: missing required flag \"--pivnet-file-glob\""))
+			})
+
+			It("requires the output directory unless the product is streamed to stdout", func() {
+				err = command.Execute([]string{
+					"--pivnet-api-token", "token",
+					"--pivnet-file-glob", "*.pivotal",
+					"--pivnet-product-slug", "mayhem-crew",
+					"--product-version", "2.0.0",
+				})
 				Expect(err).To(MatchError("could not parse download-product flags: missing required flag \"--output-directory\""))
 			})
 		})
@@ -755,6 +1641,20 @@ output-directory: %s
 			})
 		})
 
+		Context("when the output layout is unknown", func() {
+			It("returns an error", func() {
+				err = command.Execute([]string{
+					"--pivnet-api-token", "token",
+					"--pivnet-file-glob", "*.pivotal",
+					"--pivnet-product-slug", "elastic-runtime",
+					"--product-version", "2.0.0",
+					"--output-directory", tempDir,
+					"--output-layout", "version",
+				})
+				Expect(err).To(MatchError(`unknown --output-layout "version", expected one of: flat, slug, slug/version`))
+			})
+		})
+
 		Context("when the release specified is not available", func() {
 			BeforeEach(func() {
 				fakePivnetDownloader.ReleaseForVersionReturns(pivnet.Release{}, fmt.Errorf("some-error"))
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+var expiresWithinPattern = regexp.MustCompile(`^[1-9][0-9]*[dwmy]$`)
+
+type ExpiringCertificates struct {
+	service   expiringCertificatesService
+	presenter presenters.FormattedPresenter
+	logger    logger
+	Options   struct {
+		ExpiresWithin string `long:"expires-within" default:"3m" description:"lists the certificates expiring within this duration, in days, weeks, months or years (e.g. 10d, 2w, 3m, 1y)"`
+		Format        string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/expiring_certificates_service.go --fake-name ExpiringCertificatesService . expiringCertificatesService
+type expiringCertificatesService interface {
+	ListExpiringCertificates(expiresWithin string) ([]api.ExpiringCertificate, error)
+}
+
+func NewExpiringCertificates(service expiringCertificatesService, presenter presenters.FormattedPresenter, logger logger) ExpiringCertificates {
+	return ExpiringCertificates{service: service, presenter: presenter, logger: logger}
+}
+
+func (e ExpiringCertificates) Execute(args []string) error {
+	if _, err := jhanda.Parse(&e.Options, args); err != nil {
+		return fmt.Errorf("could not parse expiring-certificates flags: %s", err)
+	}
+
+	// the duration is passed on to Ops Manager, so it is only validated here
+	if _, err := expiresWithinCutoff(time.Now(), e.Options.ExpiresWithin); err != nil {
+		return err
+	}
+
+	certificates, err := e.service.ListExpiringCertificates(e.Options.ExpiresWithin)
+	if err != nil {
+		return fmt.Errorf("could not list the expiring certificates: %s", err)
+	}
+
+	sort.SliceStable(certificates, func(i, j int) bool {
+		return certificates[i].ValidUntil < certificates[j].ValidUntil
+	})
+
+	if len(certificates) == 0 && e.Options.Format != "json" {
+		e.logger.Printf("no certificates expire within %s\n", e.Options.ExpiresWithin)
+		return nil
+	}
+
+	if certificates == nil {
+		certificates = []api.ExpiringCertificate{}
+	}
+
+	e.presenter.SetFormat(e.Options.Format)
+	e.presenter.PresentExpiringCertificates(certificates)
+
+	return nil
+}
+
+func (e ExpiringCertificates) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command lists the certificates of the director and of the deployed products, CAs included, that expire within the given duration, soonest first.",
+		ShortDescription: "lists the certificates expiring soon",
+		Flags:            e.Options,
+	}
+}
@@ -0,0 +1,118 @@
+package commands_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("ExpiringCertificates", func() {
+	var (
+		fakeService   *fakes.ExpiringCertificatesService
+		fakePresenter *presenterfakes.FormattedPresenter
+		logger        *fakes.Logger
+
+		command commands.ExpiringCertificates
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.ExpiringCertificatesService{}
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		logger = &fakes.Logger{}
+
+		command = commands.NewExpiringCertificates(fakeService, fakePresenter, logger)
+	})
+
+	Describe("Execute", func() {
+		BeforeEach(func() {
+			fakeService.ListExpiringCertificatesReturns([]api.ExpiringCertificate{
+				{ProductGUID: "cf-guid", ValidUntil: "2020-03-01T00:00:00Z"},
+				{ProductGUID: "p-bosh-guid", IsCA: true, ValidUntil: "2020-01-01T00:00:00Z"},
+			}, nil)
+		})
+
+		It("presents the certificates expiring within three months, soonest first", func() {
+			err := command.Execute([]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.ListExpiringCertificatesArgsForCall(0)).To(Equal("3m"))
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("table"))
+			Expect(fakePresenter.PresentExpiringCertificatesArgsForCall(0)).To(Equal([]api.ExpiringCertificate{
+				{ProductGUID: "p-bosh-guid", IsCA: true, ValidUntil: "2020-01-01T00:00:00Z"},
+				{ProductGUID: "cf-guid", ValidUntil: "2020-03-01T00:00:00Z"},
+			}))
+		})
+
+		It("lists the certificates expiring within the given duration", func() {
+			err := command.Execute([]string{"--expires-within", "10d", "--format", "json"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.ListExpiringCertificatesArgsForCall(0)).To(Equal("10d"))
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+		})
+
+		Context("when no certificates expire within the duration", func() {
+			BeforeEach(func() {
+				fakeService.ListExpiringCertificatesReturns(nil, nil)
+			})
+
+			It("prints a message instead of a table", func() {
+				err := command.Execute([]string{"--expires-within", "1y"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePresenter.PresentExpiringCertificatesCallCount()).To(Equal(0))
+
+				format, args := logger.PrintfArgsForCall(0)
+				Expect(format).To(Equal("no certificates expire within %s\n"))
+				Expect(args).To(Equal([]interface{}{"1y"}))
+			})
+
+			It("presents an empty list as json", func() {
+				err := command.Execute([]string{"--format", "json"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCallCount()).To(Equal(0))
+				Expect(fakePresenter.PresentExpiringCertificatesArgsForCall(0)).To(Equal([]api.ExpiringCertificate{}))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when an unknown flag is provided", func() {
+				err := command.Execute([]string{"--badflag"})
+				Expect(err).To(MatchError("could not parse expiring-certificates flags: flag provided but not defined: -badflag"))
+			})
+
+			It("returns an error when the duration cannot be parsed", func() {
+				err := command.Execute([]string{"--expires-within", "3 months"})
+				Expect(err).To(MatchError(`could not parse --expires-within "3 months": expected a number of days, weeks, months or years, such as 10d, 2w, 3m or 1y`))
+
+				Expect(fakeService.ListExpiringCertificatesCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the certificates cannot be listed", func() {
+				fakeService.ListExpiringCertificatesReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list the expiring certificates: some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			command := commands.NewExpiringCertificates(nil, nil, nil)
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command lists the certificates of the director and of the deployed products, CAs included, that expire within the given duration, soonest first.",
+				ShortDescription: "lists the certificates expiring soon",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type ExpiringLicenses struct {
+	service   expiringLicensesService
+	presenter presenters.FormattedPresenter
+	logger    logger
+	Options   struct {
+		ExpiresWithin string `long:"expires-within" default:"3m" description:"lists the licenses expiring within this duration, in days, weeks, months or years (e.g. 10d, 2w, 3m, 1y)"`
+		Staged        bool   `long:"staged" description:"only check the licenses of the staged products"`
+		Deployed      bool   `long:"deployed" description:"only check the licenses of the deployed products"`
+		Format        string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/expiring_licenses_service.go --fake-name ExpiringLicensesService . expiringLicensesService
+type expiringLicensesService interface {
+	ListStagedProducts() (api.StagedProductsOutput, error)
+	ListDeployedProducts() ([]api.DeployedProductOutput, error)
+}
+
+func NewExpiringLicenses(service expiringLicensesService, presenter presenters.FormattedPresenter, logger logger) ExpiringLicenses {
+	return ExpiringLicenses{service: service, presenter: presenter, logger: logger}
+}
+
+func (e ExpiringLicenses) Execute(args []string) error {
+	if _, err := jhanda.Parse(&e.Options, args); err != nil {
+		return fmt.Errorf("could not parse expiring-licenses flags: %s", err)
+	}
+
+	cutoff, err := expiresWithinCutoff(time.Now(), e.Options.ExpiresWithin)
+	if err != nil {
+		return err
+	}
+
+	if e.Options.Staged && e.Options.Deployed {
+		return errors.New("--staged and --deployed cannot both be given, the licenses of both are checked by default")
+	}
+
+	// the staged and the deployed versions of a product usually share their
+	// license, which is then listed once with both states
+	var licenses []models.ExpiringLicense
+	add := func(guid, state string, metadata []api.LicenseMetadata) error {
+		for _, license := range metadata {
+			expiresAt, err := time.Parse("2006-01-02", license.ExpiresAt)
+			if err != nil {
+				return fmt.Errorf("could not parse the expiry %q of the license %s of %s: %s", license.ExpiresAt, license.PropertyReference, guid, err)
+			}
+
+			if expiresAt.After(cutoff) {
+				continue
+			}
+
+			found := false
+			for i := range licenses {
+				if licenses[i].GUID == guid && licenses[i].PropertyReference == license.PropertyReference && licenses[i].ExpiresAt.Equal(expiresAt) {
+					licenses[i].ProductState = append(licenses[i].ProductState, state)
+					found = true
+				}
+			}
+
+			if !found {
+				licenses = append(licenses, models.ExpiringLicense{
+					ProductName:       license.ProductName,
+					GUID:              guid,
+					ProductState:      []string{state},
+					PropertyReference: license.PropertyReference,
+					ExpiresAt:         expiresAt,
+				})
+			}
+		}
+
+		return nil
+	}
+
+	if !e.Options.Staged {
+		deployedProducts, err := e.service.ListDeployedProducts()
+		if err != nil {
+			return fmt.Errorf("could not list the deployed products: %s", err)
+		}
+
+		for _, product := range deployedProducts {
+			if err := add(product.GUID, "deployed", product.LicenseMetadata); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !e.Options.Deployed {
+		stagedProducts, err := e.service.ListStagedProducts()
+		if err != nil {
+			return fmt.Errorf("could not list the staged products: %s", err)
+		}
+
+		for _, product := range stagedProducts.Products {
+			if err := add(product.GUID, "staged", product.LicenseMetadata); err != nil {
+				return err
+			}
+		}
+	}
+
+	sort.SliceStable(licenses, func(i, j int) bool {
+		return licenses[i].ExpiresAt.Before(licenses[j].ExpiresAt)
+	})
+
+	if len(licenses) == 0 && e.Options.Format != "json" {
+		e.logger.Printf("no licenses expire within %s\n", e.Options.ExpiresWithin)
+		return nil
+	}
+
+	if licenses == nil {
+		licenses = []models.ExpiringLicense{}
+	}
+
+	e.presenter.SetFormat(e.Options.Format)
+	e.presenter.PresentExpiringLicenses(licenses)
+
+	return nil
+}
+
+// expiresWithinCutoff returns the time after the duration, given in the format
+// of --expires-within, since now.
+func expiresWithinCutoff(now time.Time, expiresWithin string) (time.Time, error) {
+	if !expiresWithinPattern.MatchString(expiresWithin) {
+		return time.Time{}, fmt.Errorf("could not parse --expires-within %q: expected a number of days, weeks, months or years, such as 10d, 2w, 3m or 1y", expiresWithin)
+	}
+
+	count, err := strconv.Atoi(expiresWithin[:len(expiresWithin)-1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse --expires-within %q: %s", expiresWithin, err) // un-tested
+	}
+
+	switch expiresWithin[len(expiresWithin)-1] {
+	case 'd':
+		return now.AddDate(0, 0, count), nil
+	case 'w':
+		return now.AddDate(0, 0, 7*count), nil
+	case 'm':
+		return now.AddDate(0, count, 0), nil
+	default:
+		return now.AddDate(count, 0, 0), nil
+	}
+}
+
+func (e ExpiringLicenses) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command lists the licenses of the staged and deployed products that have expired, or expire within the given duration, soonest first.",
+		ShortDescription: "lists the licenses of the products expiring soon",
+		Flags:            e.Options,
+	}
+}
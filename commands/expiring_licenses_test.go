@@ -0,0 +1,205 @@
+package commands_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/models"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("ExpiringLicenses", func() {
+	var (
+		fakeService   *fakes.ExpiringLicensesService
+		fakePresenter *presenterfakes.FormattedPresenter
+		logger        *fakes.Logger
+
+		command commands.ExpiringLicenses
+
+		inAMonth, inAYear, lastWeek time.Time
+	)
+
+	date := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	license := func(reference string, expiresAt time.Time) api.LicenseMetadata {
+		return api.LicenseMetadata{
+			PropertyReference: reference,
+			ExpiresAt:         expiresAt.Format("2006-01-02"),
+			ProductName:       "some-product",
+		}
+	}
+
+	BeforeEach(func() {
+		fakeService = &fakes.ExpiringLicensesService{}
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		logger = &fakes.Logger{}
+
+		command = commands.NewExpiringLicenses(fakeService, fakePresenter, logger)
+
+		now := time.Now()
+		inAMonth = date(now.AddDate(0, 1, 0))
+		inAYear = date(now.AddDate(1, 0, 0))
+		lastWeek = date(now.AddDate(0, 0, -7))
+
+		fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+			{
+				GUID:            "some-product-guid",
+				Type:            "some-product",
+				LicenseMetadata: []api.LicenseMetadata{license(".properties.license", inAMonth)},
+			},
+			{
+				GUID:            "other-product-guid",
+				Type:            "other-product",
+				LicenseMetadata: []api.LicenseMetadata{license(".properties.license", inAYear)},
+			},
+		}, nil)
+
+		fakeService.ListStagedProductsReturns(api.StagedProductsOutput{
+			Products: []api.StagedProduct{
+				{
+					GUID: "some-product-guid",
+					Type: "some-product",
+					LicenseMetadata: []api.LicenseMetadata{
+						license(".properties.license", inAMonth),
+						license(".properties.other_license", lastWeek),
+					},
+				},
+			},
+		}, nil)
+	})
+
+	Describe("Execute", func() {
+		It("presents the licenses, staged or deployed, expiring within three months, soonest first", func() {
+			err := command.Execute([]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("table"))
+			Expect(fakePresenter.PresentExpiringLicensesArgsForCall(0)).To(Equal([]models.ExpiringLicense{
+				{
+					ProductName:       "some-product",
+					GUID:              "some-product-guid",
+					ProductState:      []string{"staged"},
+					PropertyReference: ".properties.other_license",
+					ExpiresAt:         lastWeek,
+				},
+				{
+					ProductName:       "some-product",
+					GUID:              "some-product-guid",
+					ProductState:      []string{"deployed", "staged"},
+					PropertyReference: ".properties.license",
+					ExpiresAt:         inAMonth,
+				},
+			}))
+		})
+
+		It("lists the licenses expiring within the given duration", func() {
+			err := command.Execute([]string{"--expires-within", "2y", "--format", "json"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+			Expect(fakePresenter.PresentExpiringLicensesArgsForCall(0)).To(HaveLen(3))
+		})
+
+		It("only checks the deployed products with --deployed", func() {
+			err := command.Execute([]string{"--deployed"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.ListStagedProductsCallCount()).To(Equal(0))
+			licenses := fakePresenter.PresentExpiringLicensesArgsForCall(0)
+			Expect(licenses).To(HaveLen(1))
+			Expect(licenses[0].ProductState).To(Equal([]string{"deployed"}))
+		})
+
+		It("only checks the staged products with --staged", func() {
+			err := command.Execute([]string{"--staged"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.ListDeployedProductsCallCount()).To(Equal(0))
+			Expect(fakePresenter.PresentExpiringLicensesArgsForCall(0)).To(HaveLen(2))
+		})
+
+		Context("when no licenses expire within the duration", func() {
+			BeforeEach(func() {
+				fakeService.ListStagedProductsReturns(api.StagedProductsOutput{}, nil)
+				fakeService.ListDeployedProductsReturns(nil, nil)
+			})
+
+			It("prints a message instead of a table", func() {
+				err := command.Execute([]string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePresenter.PresentExpiringLicensesCallCount()).To(Equal(0))
+
+				format, args := logger.PrintfArgsForCall(0)
+				Expect(format).To(Equal("no licenses expire within %s\n"))
+				Expect(args).To(Equal([]interface{}{"3m"}))
+			})
+
+			It("presents an empty list as json", func() {
+				err := command.Execute([]string{"--format", "json"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePresenter.PresentExpiringLicensesArgsForCall(0)).To(Equal([]models.ExpiringLicense{}))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when an unknown flag is provided", func() {
+				err := command.Execute([]string{"--badflag"})
+				Expect(err).To(MatchError("could not parse expiring-licenses flags: flag provided but not defined: -badflag"))
+			})
+
+			It("returns an error when the duration cannot be parsed", func() {
+				err := command.Execute([]string{"--expires-within", "3x"})
+				Expect(err).To(MatchError(`could not parse --expires-within "3x": expected a number of days, weeks, months or years, such as 10d, 2w, 3m or 1y`))
+			})
+
+			It("returns an error when both --staged and --deployed are given", func() {
+				err := command.Execute([]string{"--staged", "--deployed"})
+				Expect(err).To(MatchError("--staged and --deployed cannot both be given, the licenses of both are checked by default"))
+			})
+
+			It("returns an error when the expiry of a license cannot be parsed", func() {
+				fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{{
+					GUID:            "some-product-guid",
+					LicenseMetadata: []api.LicenseMetadata{{PropertyReference: ".properties.license", ExpiresAt: "soon"}},
+				}}, nil)
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError(ContainSubstring(`could not parse the expiry "soon" of the license .properties.license of some-product-guid`)))
+			})
+
+			It("returns an error when the deployed products cannot be listed", func() {
+				fakeService.ListDeployedProductsReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list the deployed products: some error"))
+			})
+
+			It("returns an error when the staged products cannot be listed", func() {
+				fakeService.ListStagedProductsReturns(api.StagedProductsOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list the staged products: some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command lists the licenses of the staged and deployed products that have expired, or expire within the given duration, soonest first.",
+				ShortDescription: "lists the licenses of the products expiring soon",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
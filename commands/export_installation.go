@@ -1,22 +1,32 @@
 package commands
 
 import (
+	"archive/zip"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/encryption"
 )
 
 type ExportInstallation struct {
 	logger  logger
 	service exportInstallationService
 	Options struct {
-		OutputFile string `long:"output-file"      short:"o"  required:"true" description:"output path to write installation to"`
+		OutputFile        string `long:"output-file"        short:"o"  required:"true"             description:"output path to write installation to"`
+		Resume            bool   `long:"resume"                                                    description:"resumes the export written partially to the output file, when Ops Manager allows it; otherwise the installation is exported from the start"`
+		EncryptPassphrase string `long:"encrypt-passphrase"            env:"OM_ENCRYPT_PASSPHRASE" description:"encrypts the exported installation with a key derived from this passphrase (AES-256-GCM), so that it is never written to the output file in plaintext"`
+		EncryptKeyFile    string `long:"encrypt-key-file"                                          description:"encrypts the exported installation with the base64 encoded 256 bit key of this file (AES-256-GCM), e.g. generated with openssl rand -base64 32"`
 	}
 }
 
 //go:generate counterfeiter -o ./fakes/export_installation_service.go --fake-name ExportInstallationService . exportInstallationService
 type exportInstallationService interface {
-	DownloadInstallationAssetCollection(outputFile string) error
+	DownloadInstallationAssetCollection(outputFile string, resume bool) (api.DownloadInstallationAssetCollectionOutput, error)
 }
 
 func NewExportInstallation(service exportInstallationService, logger logger) ExportInstallation {
@@ -28,7 +38,7 @@ func NewExportInstallation(service exportInstallationService, logger logger) Exp
 
 func (ei ExportInstallation) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "This command will export the current installation of the target Ops Manager.",
+		Description:      "This command will export the current installation of the target Ops Manager, and verify the integrity of the exported archive. The archive contains every secret of the installation, it can be encrypted with --encrypt-passphrase or --encrypt-key-file.",
 		ShortDescription: "exports the installation of the target Ops Manager",
 		Flags:            ei.Options,
 	}
@@ -39,14 +49,152 @@ func (ei ExportInstallation) Execute(args []string) error {
 		return fmt.Errorf("could not parse export-installation flags: %s", err)
 	}
 
+	secret, err := ei.encryptionSecret()
+	if err != nil {
+		return err
+	}
+
+	exportFile := ei.Options.OutputFile
+	if secret != nil {
+		// the archive is verified before it is encrypted, so it is downloaded
+		// in a file readable only by the user, next to the output file, and
+		// removed once encrypted
+		exportFile, err = temporaryFile(filepath.Dir(ei.Options.OutputFile), ".om-export-installation-")
+		if err != nil {
+			return fmt.Errorf("could not create the file to export the installation to: %s", err)
+		}
+		defer os.Remove(exportFile)
+	}
+
 	ei.logger.Printf("exporting installation")
 
-	err := ei.service.DownloadInstallationAssetCollection(ei.Options.OutputFile)
+	output, err := ei.service.DownloadInstallationAssetCollection(exportFile, ei.Options.Resume)
 	if err != nil {
-		return fmt.Errorf("failed to export installation: %s", err)
+		if ei.Options.Resume || secret != nil {
+			return fmt.Errorf("failed to export installation: %s", err)
+		}
+		return fmt.Errorf("failed to export installation: %s\nthe export can be resumed with --resume", err)
+	}
+
+	if output.ResumedFrom > 0 {
+		ei.logger.Printf("resumed the export from byte %d", output.ResumedFrom)
+	}
+
+	ei.logger.Printf("verifying the exported installation")
+
+	err = verifyInstallationArchive(exportFile)
+	if err != nil {
+		if output.ResumedFrom > 0 {
+			return fmt.Errorf("the exported installation %s is corrupted, export it again without --resume: %s", ei.Options.OutputFile, err)
+		}
+		return fmt.Errorf("the exported installation %s is corrupted: %s", ei.Options.OutputFile, err)
+	}
+
+	if secret != nil {
+		ei.logger.Printf("encrypting the exported installation")
+
+		err = encryptFile(ei.Options.OutputFile, exportFile, *secret)
+		if err != nil {
+			return fmt.Errorf("could not encrypt the exported installation: %s", err)
+		}
 	}
 
 	ei.logger.Printf("finished exporting installation")
 
 	return nil
 }
+
+func (ei ExportInstallation) encryptionSecret() (*encryption.Secret, error) {
+	if ei.Options.EncryptPassphrase == "" && ei.Options.EncryptKeyFile == "" {
+		return nil, nil
+	}
+
+	if ei.Options.EncryptPassphrase != "" && ei.Options.EncryptKeyFile != "" {
+		return nil, fmt.Errorf("--encrypt-passphrase and --encrypt-key-file cannot be used together")
+	}
+
+	if ei.Options.Resume {
+		return nil, fmt.Errorf("--resume cannot be used with --encrypt-passphrase or --encrypt-key-file, the partial export is not kept in plaintext")
+	}
+
+	if ei.Options.EncryptPassphrase != "" {
+		return &encryption.Secret{Passphrase: ei.Options.EncryptPassphrase}, nil
+	}
+
+	key, err := encryption.ReadKeyFile(ei.Options.EncryptKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the key of %s: %s", ei.Options.EncryptKeyFile, err)
+	}
+
+	return &encryption.Secret{Key: key}, nil
+}
+
+// temporaryFile creates an empty file that only the user can read in dir.
+func temporaryFile(dir, prefix string) (string, error) {
+	file, err := ioutil.TempFile(dir, prefix)
+	if err != nil {
+		return "", err
+	}
+
+	return file.Name(), file.Close()
+}
+
+// encryptFile writes the encrypted contents of the source to the destination,
+// which is removed when the encryption fails.
+func encryptFile(destination, source string, secret encryption.Secret) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = encryption.Encrypt(dst, src, secret)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(destination)
+		return err
+	}
+
+	return nil
+}
+
+// verifyInstallationArchive reads every file of the archive, so that their
+// checksums are verified, and checks that it contains an installation.
+func verifyInstallationArchive(path string) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	found := false
+	for _, file := range archive.File {
+		if file.Name == "installation.yml" {
+			found = true
+		}
+
+		contents, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("could not read %s: %s", file.Name, err)
+		}
+
+		_, err = io.Copy(ioutil.Discard, contents)
+		contents.Close()
+		if err != nil {
+			return fmt.Errorf("could not read %s: %s", file.Name, err)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("installation.yml is missing")
+	}
+
+	return nil
+}
@@ -1,12 +1,20 @@
 package commands_test
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/encryption"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,33 +24,182 @@ var _ = Describe("ExportInstallation", func() {
 	var (
 		fakeService *fakes.ExportInstallationService
 		logger      *fakes.Logger
+		outputDir   string
+		outputFile  string
 	)
 
+	writeArchive := func(path string, files map[string]string) {
+		file, err := os.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+
+		archive := zip.NewWriter(file)
+		for name, contents := range files {
+			f, err := archive.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = f.Write([]byte(contents))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(archive.Close()).To(Succeed())
+	}
+
 	BeforeEach(func() {
 		fakeService = &fakes.ExportInstallationService{}
 		logger = &fakes.Logger{}
+
+		var err error
+		outputDir, err = ioutil.TempDir("", "export-installation")
+		Expect(err).NotTo(HaveOccurred())
+		outputFile = filepath.Join(outputDir, "installation.zip")
+
+		fakeService.DownloadInstallationAssetCollectionStub = func(path string, _ bool) (api.DownloadInstallationAssetCollectionOutput, error) {
+			writeArchive(path, map[string]string{"installation.yml": "some-installation", "deployments/cf.yml": "some-deployment"})
+			return api.DownloadInstallationAssetCollectionOutput{}, nil
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputDir)
 	})
 
+	logMessages := func() []string {
+		var messages []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, v := logger.PrintfArgsForCall(i)
+			messages = append(messages, fmt.Sprintf(format, v...))
+		}
+		return messages
+	}
+
 	It("exports the installation", func() {
 		command := commands.NewExportInstallation(fakeService, logger)
 
 		err := command.Execute([]string{
-			"--output-file", "/path/to/output.zip",
+			"--output-file", outputFile,
 		})
 		Expect(err).NotTo(HaveOccurred())
 
 		By("calling export on the installation service")
 		Expect(fakeService.DownloadInstallationAssetCollectionCallCount()).To(Equal(1))
-		outputFile := fakeService.DownloadInstallationAssetCollectionArgsForCall(0)
-		Expect(outputFile).To(Equal("/path/to/output.zip"))
+		file, resume := fakeService.DownloadInstallationAssetCollectionArgsForCall(0)
+		Expect(file).To(Equal(outputFile))
+		Expect(resume).To(BeFalse())
 
 		By("printing correct log messages")
-		Expect(logger.PrintfCallCount()).To(Equal(2))
-		format, v := logger.PrintfArgsForCall(0)
-		Expect(fmt.Sprintf(format, v...)).To(Equal("exporting installation"))
+		Expect(logMessages()).To(Equal([]string{
+			"exporting installation",
+			"verifying the exported installation",
+			"finished exporting installation",
+		}))
+	})
+
+	It("resumes the export", func() {
+		fakeService.DownloadInstallationAssetCollectionStub = func(string, bool) (api.DownloadInstallationAssetCollectionOutput, error) {
+			writeArchive(outputFile, map[string]string{"installation.yml": "some-installation"})
+			return api.DownloadInstallationAssetCollectionOutput{ResumedFrom: 1024}, nil
+		}
+		command := commands.NewExportInstallation(fakeService, logger)
 
-		format, v = logger.PrintfArgsForCall(1)
-		Expect(fmt.Sprintf(format, v...)).To(Equal("finished exporting installation"))
+		err := command.Execute([]string{"--output-file", outputFile, "--resume"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, resume := fakeService.DownloadInstallationAssetCollectionArgsForCall(0)
+		Expect(resume).To(BeTrue())
+		Expect(logMessages()).To(ContainElement("resumed the export from byte 1024"))
+	})
+
+	Context("when the installation is encrypted", func() {
+		decrypt := func(secret encryption.Secret) []byte {
+			encrypted, err := os.Open(outputFile)
+			Expect(err).NotTo(HaveOccurred())
+			defer encrypted.Close()
+
+			var decrypted bytes.Buffer
+			Expect(encryption.Decrypt(&decrypted, encrypted, secret)).To(Succeed())
+			return decrypted.Bytes()
+		}
+
+		expectOnlyOutputFile := func() {
+			files, err := ioutil.ReadDir(outputDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+			Expect(files[0].Name()).To(Equal("installation.zip"))
+			Expect(files[0].Mode().Perm()).To(Equal(os.FileMode(0600)))
+		}
+
+		It("encrypts the exported installation with a passphrase", func() {
+			command := commands.NewExportInstallation(fakeService, logger)
+
+			err := command.Execute([]string{"--output-file", outputFile, "--encrypt-passphrase", "some-passphrase"})
+			Expect(err).NotTo(HaveOccurred())
+
+			file, _ := fakeService.DownloadInstallationAssetCollectionArgsForCall(0)
+			Expect(file).NotTo(Equal(outputFile))
+			Expect(filepath.Dir(file)).To(Equal(outputDir))
+
+			archive := decrypt(encryption.Secret{Passphrase: "some-passphrase"})
+			_, err = zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+			Expect(err).NotTo(HaveOccurred())
+
+			expectOnlyOutputFile()
+			Expect(logMessages()).To(Equal([]string{
+				"exporting installation",
+				"verifying the exported installation",
+				"encrypting the exported installation",
+				"finished exporting installation",
+			}))
+		})
+
+		It("encrypts the exported installation with a key", func() {
+			key := bytes.Repeat([]byte{7}, encryption.KeySize)
+			keyFile := filepath.Join(outputDir, "key")
+			Expect(ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600)).To(Succeed())
+			defer os.Remove(keyFile)
+
+			command := commands.NewExportInstallation(fakeService, logger)
+
+			err := command.Execute([]string{"--output-file", outputFile, "--encrypt-key-file", keyFile})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(decrypt(encryption.Secret{Key: key})).NotTo(BeEmpty())
+		})
+
+		It("removes the plaintext export when it fails", func() {
+			fakeService.DownloadInstallationAssetCollectionStub = func(path string, _ bool) (api.DownloadInstallationAssetCollectionOutput, error) {
+				Expect(ioutil.WriteFile(path, []byte("some-partial-installation"), 0600)).To(Succeed())
+				return api.DownloadInstallationAssetCollectionOutput{}, errors.New("some error")
+			}
+			command := commands.NewExportInstallation(fakeService, logger)
+
+			err := command.Execute([]string{"--output-file", outputFile, "--encrypt-passphrase", "some-passphrase"})
+			Expect(err).To(MatchError("failed to export installation: some error"))
+
+			files, err := ioutil.ReadDir(outputDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+
+		It("returns an error when both a passphrase and a key file are given", func() {
+			command := commands.NewExportInstallation(fakeService, logger)
+
+			err := command.Execute([]string{"--output-file", outputFile, "--encrypt-passphrase", "some-passphrase", "--encrypt-key-file", "some-file"})
+			Expect(err).To(MatchError("--encrypt-passphrase and --encrypt-key-file cannot be used together"))
+			Expect(fakeService.DownloadInstallationAssetCollectionCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when resuming", func() {
+			command := commands.NewExportInstallation(fakeService, logger)
+
+			err := command.Execute([]string{"--output-file", outputFile, "--encrypt-passphrase", "some-passphrase", "--resume"})
+			Expect(err).To(MatchError("--resume cannot be used with --encrypt-passphrase or --encrypt-key-file, the partial export is not kept in plaintext"))
+		})
+
+		It("returns an error when the key file cannot be read", func() {
+			command := commands.NewExportInstallation(fakeService, logger)
+
+			err := command.Execute([]string{"--output-file", outputFile, "--encrypt-key-file", "/does/not/exist"})
+			Expect(err).To(MatchError(ContainSubstring("could not read the key of /does/not/exist: ")))
+		})
 	})
 
 	Context("failure cases", func() {
@@ -63,21 +220,67 @@ var _ = Describe("ExportInstallation", func() {
 		})
 
 		Context("when the installation cannot be exported", func() {
-			It("returns an error", func() {
+			It("returns an error suggesting to resume", func() {
 				command := commands.NewExportInstallation(fakeService, logger)
-				fakeService.DownloadInstallationAssetCollectionReturns(errors.New("some error"))
+				fakeService.DownloadInstallationAssetCollectionStub = nil
+				fakeService.DownloadInstallationAssetCollectionReturns(api.DownloadInstallationAssetCollectionOutput{}, errors.New("some error"))
 
 				err := command.Execute([]string{"--output-file", "/some/path"})
+				Expect(err).To(MatchError("failed to export installation: some error\nthe export can be resumed with --resume"))
+			})
+
+			It("returns the error when resuming", func() {
+				command := commands.NewExportInstallation(fakeService, logger)
+				fakeService.DownloadInstallationAssetCollectionStub = nil
+				fakeService.DownloadInstallationAssetCollectionReturns(api.DownloadInstallationAssetCollectionOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--output-file", "/some/path", "--resume"})
 				Expect(err).To(MatchError("failed to export installation: some error"))
 			})
 		})
+
+		Context("when the exported installation is corrupted", func() {
+			It("returns an error when it is not a zip", func() {
+				fakeService.DownloadInstallationAssetCollectionStub = func(string, bool) (api.DownloadInstallationAssetCollectionOutput, error) {
+					Expect(ioutil.WriteFile(outputFile, []byte("some-truncated-installation"), 0644)).To(Succeed())
+					return api.DownloadInstallationAssetCollectionOutput{}, nil
+				}
+				command := commands.NewExportInstallation(fakeService, logger)
+
+				err := command.Execute([]string{"--output-file", outputFile})
+				Expect(err).To(MatchError(fmt.Sprintf("the exported installation %s is corrupted: zip: not a valid zip file", outputFile)))
+				Expect(logMessages()).NotTo(ContainElement("finished exporting installation"))
+			})
+
+			It("returns an error when the installation is missing", func() {
+				fakeService.DownloadInstallationAssetCollectionStub = func(string, bool) (api.DownloadInstallationAssetCollectionOutput, error) {
+					writeArchive(outputFile, map[string]string{"some-file": "some-contents"})
+					return api.DownloadInstallationAssetCollectionOutput{}, nil
+				}
+				command := commands.NewExportInstallation(fakeService, logger)
+
+				err := command.Execute([]string{"--output-file", outputFile})
+				Expect(err).To(MatchError(fmt.Sprintf("the exported installation %s is corrupted: installation.yml is missing", outputFile)))
+			})
+
+			It("suggests to export again when the export was resumed", func() {
+				fakeService.DownloadInstallationAssetCollectionStub = func(string, bool) (api.DownloadInstallationAssetCollectionOutput, error) {
+					Expect(ioutil.WriteFile(outputFile, []byte("some-truncated-installation"), 0644)).To(Succeed())
+					return api.DownloadInstallationAssetCollectionOutput{ResumedFrom: 10}, nil
+				}
+				command := commands.NewExportInstallation(fakeService, logger)
+
+				err := command.Execute([]string{"--output-file", outputFile, "--resume"})
+				Expect(err).To(MatchError(ContainSubstring("is corrupted, export it again without --resume")))
+			})
+		})
 	})
 
 	Describe("Usage", func() {
 		It("returns usage information for the command", func() {
 			command := commands.NewExportInstallation(nil, nil)
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
-				Description:      "This command will export the current installation of the target Ops Manager.",
+				Description:      "This command will export the current installation of the target Ops Manager, and verify the integrity of the exported archive. The archive contains every secret of the installation, it can be encrypted with --encrypt-passphrase or --encrypt-key-file.",
 				ShortDescription: "exports the installation of the target Ops Manager",
 				Flags:            command.Options,
 			}))
@@ -74,6 +74,18 @@ type ApplyChangesService struct {
 		result1 []api.InstallationsServiceOutput
 		result2 error
 	}
+	ListStagedProductsStub        func() (api.StagedProductsOutput, error)
+	listStagedProductsMutex       sync.RWMutex
+	listStagedProductsArgsForCall []struct {
+	}
+	listStagedProductsReturns struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}
+	listStagedProductsReturnsOnCall map[int]struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}
 	RunningInstallationStub        func() (api.InstallationsServiceOutput, error)
 	runningInstallationMutex       sync.RWMutex
 	runningInstallationArgsForCall []struct {
@@ -86,6 +98,17 @@ type ApplyChangesService struct {
 		result1 api.InstallationsServiceOutput
 		result2 error
 	}
+	UpdateStagedDirectorPropertiesStub        func(api.DirectorProperties) error
+	updateStagedDirectorPropertiesMutex       sync.RWMutex
+	updateStagedDirectorPropertiesArgsForCall []struct {
+		arg1 api.DirectorProperties
+	}
+	updateStagedDirectorPropertiesReturns struct {
+		result1 error
+	}
+	updateStagedDirectorPropertiesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -397,6 +420,61 @@ func (fake *ApplyChangesService) ListInstallationsReturnsOnCall(i int, result1 [
 	}{result1, result2}
 }
 
+func (fake *ApplyChangesService) ListStagedProducts() (api.StagedProductsOutput, error) {
+	fake.listStagedProductsMutex.Lock()
+	ret, specificReturn := fake.listStagedProductsReturnsOnCall[len(fake.listStagedProductsArgsForCall)]
+	fake.listStagedProductsArgsForCall = append(fake.listStagedProductsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListStagedProducts", []interface{}{})
+	fake.listStagedProductsMutex.Unlock()
+	if fake.ListStagedProductsStub != nil {
+		return fake.ListStagedProductsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listStagedProductsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ApplyChangesService) ListStagedProductsCallCount() int {
+	fake.listStagedProductsMutex.RLock()
+	defer fake.listStagedProductsMutex.RUnlock()
+	return len(fake.listStagedProductsArgsForCall)
+}
+
+func (fake *ApplyChangesService) ListStagedProductsCalls(stub func() (api.StagedProductsOutput, error)) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = stub
+}
+
+func (fake *ApplyChangesService) ListStagedProductsReturns(result1 api.StagedProductsOutput, result2 error) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = nil
+	fake.listStagedProductsReturns = struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ApplyChangesService) ListStagedProductsReturnsOnCall(i int, result1 api.StagedProductsOutput, result2 error) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = nil
+	if fake.listStagedProductsReturnsOnCall == nil {
+		fake.listStagedProductsReturnsOnCall = make(map[int]struct {
+			result1 api.StagedProductsOutput
+			result2 error
+		})
+	}
+	fake.listStagedProductsReturnsOnCall[i] = struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ApplyChangesService) RunningInstallation() (api.InstallationsServiceOutput, error) {
 	fake.runningInstallationMutex.Lock()
 	ret, specificReturn := fake.runningInstallationReturnsOnCall[len(fake.runningInstallationArgsForCall)]
@@ -452,6 +530,66 @@ func (fake *ApplyChangesService) RunningInstallationReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *ApplyChangesService) UpdateStagedDirectorProperties(arg1 api.DirectorProperties) error {
+	fake.updateStagedDirectorPropertiesMutex.Lock()
+	ret, specificReturn := fake.updateStagedDirectorPropertiesReturnsOnCall[len(fake.updateStagedDirectorPropertiesArgsForCall)]
+	fake.updateStagedDirectorPropertiesArgsForCall = append(fake.updateStagedDirectorPropertiesArgsForCall, struct {
+		arg1 api.DirectorProperties
+	}{arg1})
+	fake.recordInvocation("UpdateStagedDirectorProperties", []interface{}{arg1})
+	fake.updateStagedDirectorPropertiesMutex.Unlock()
+	if fake.UpdateStagedDirectorPropertiesStub != nil {
+		return fake.UpdateStagedDirectorPropertiesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.updateStagedDirectorPropertiesReturns
+	return fakeReturns.result1
+}
+
+func (fake *ApplyChangesService) UpdateStagedDirectorPropertiesCallCount() int {
+	fake.updateStagedDirectorPropertiesMutex.RLock()
+	defer fake.updateStagedDirectorPropertiesMutex.RUnlock()
+	return len(fake.updateStagedDirectorPropertiesArgsForCall)
+}
+
+func (fake *ApplyChangesService) UpdateStagedDirectorPropertiesCalls(stub func(api.DirectorProperties) error) {
+	fake.updateStagedDirectorPropertiesMutex.Lock()
+	defer fake.updateStagedDirectorPropertiesMutex.Unlock()
+	fake.UpdateStagedDirectorPropertiesStub = stub
+}
+
+func (fake *ApplyChangesService) UpdateStagedDirectorPropertiesArgsForCall(i int) api.DirectorProperties {
+	fake.updateStagedDirectorPropertiesMutex.RLock()
+	defer fake.updateStagedDirectorPropertiesMutex.RUnlock()
+	argsForCall := fake.updateStagedDirectorPropertiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ApplyChangesService) UpdateStagedDirectorPropertiesReturns(result1 error) {
+	fake.updateStagedDirectorPropertiesMutex.Lock()
+	defer fake.updateStagedDirectorPropertiesMutex.Unlock()
+	fake.UpdateStagedDirectorPropertiesStub = nil
+	fake.updateStagedDirectorPropertiesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ApplyChangesService) UpdateStagedDirectorPropertiesReturnsOnCall(i int, result1 error) {
+	fake.updateStagedDirectorPropertiesMutex.Lock()
+	defer fake.updateStagedDirectorPropertiesMutex.Unlock()
+	fake.UpdateStagedDirectorPropertiesStub = nil
+	if fake.updateStagedDirectorPropertiesReturnsOnCall == nil {
+		fake.updateStagedDirectorPropertiesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStagedDirectorPropertiesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ApplyChangesService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -465,8 +603,12 @@ func (fake *ApplyChangesService) Invocations() map[string][][]interface{} {
 	defer fake.infoMutex.RUnlock()
 	fake.listInstallationsMutex.RLock()
 	defer fake.listInstallationsMutex.RUnlock()
+	fake.listStagedProductsMutex.RLock()
+	defer fake.listStagedProductsMutex.RUnlock()
 	fake.runningInstallationMutex.RLock()
 	defer fake.runningInstallationMutex.RUnlock()
+	fake.updateStagedDirectorPropertiesMutex.RLock()
+	defer fake.updateStagedDirectorPropertiesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,104 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type CertificateRotationsService struct {
+	ListCertificateRotationsStub        func() (api.CertificateRotationsOutput, error)
+	listCertificateRotationsMutex       sync.RWMutex
+	listCertificateRotationsArgsForCall []struct {
+	}
+	listCertificateRotationsReturns struct {
+		result1 api.CertificateRotationsOutput
+		result2 error
+	}
+	listCertificateRotationsReturnsOnCall map[int]struct {
+		result1 api.CertificateRotationsOutput
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CertificateRotationsService) ListCertificateRotations() (api.CertificateRotationsOutput, error) {
+	fake.listCertificateRotationsMutex.Lock()
+	ret, specificReturn := fake.listCertificateRotationsReturnsOnCall[len(fake.listCertificateRotationsArgsForCall)]
+	fake.listCertificateRotationsArgsForCall = append(fake.listCertificateRotationsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListCertificateRotations", []interface{}{})
+	fake.listCertificateRotationsMutex.Unlock()
+	if fake.ListCertificateRotationsStub != nil {
+		return fake.ListCertificateRotationsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listCertificateRotationsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CertificateRotationsService) ListCertificateRotationsCallCount() int {
+	fake.listCertificateRotationsMutex.RLock()
+	defer fake.listCertificateRotationsMutex.RUnlock()
+	return len(fake.listCertificateRotationsArgsForCall)
+}
+
+func (fake *CertificateRotationsService) ListCertificateRotationsCalls(stub func() (api.CertificateRotationsOutput, error)) {
+	fake.listCertificateRotationsMutex.Lock()
+	defer fake.listCertificateRotationsMutex.Unlock()
+	fake.ListCertificateRotationsStub = stub
+}
+
+func (fake *CertificateRotationsService) ListCertificateRotationsReturns(result1 api.CertificateRotationsOutput, result2 error) {
+	fake.listCertificateRotationsMutex.Lock()
+	defer fake.listCertificateRotationsMutex.Unlock()
+	fake.ListCertificateRotationsStub = nil
+	fake.listCertificateRotationsReturns = struct {
+		result1 api.CertificateRotationsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateRotationsService) ListCertificateRotationsReturnsOnCall(i int, result1 api.CertificateRotationsOutput, result2 error) {
+	fake.listCertificateRotationsMutex.Lock()
+	defer fake.listCertificateRotationsMutex.Unlock()
+	fake.ListCertificateRotationsStub = nil
+	if fake.listCertificateRotationsReturnsOnCall == nil {
+		fake.listCertificateRotationsReturnsOnCall = make(map[int]struct {
+			result1 api.CertificateRotationsOutput
+			result2 error
+		})
+	}
+	fake.listCertificateRotationsReturnsOnCall[i] = struct {
+		result1 api.CertificateRotationsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CertificateRotationsService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listCertificateRotationsMutex.RLock()
+	defer fake.listCertificateRotationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CertificateRotationsService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,780 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/om/api"
+)
+
+type CompareConfigService struct {
+	GetStagedDirectorAvailabilityZonesStub        func() (api.AvailabilityZonesOutput, error)
+	getStagedDirectorAvailabilityZonesMutex       sync.RWMutex
+	getStagedDirectorAvailabilityZonesArgsForCall []struct {
+	}
+	getStagedDirectorAvailabilityZonesReturns struct {
+		result1 api.AvailabilityZonesOutput
+		result2 error
+	}
+	getStagedDirectorAvailabilityZonesReturnsOnCall map[int]struct {
+		result1 api.AvailabilityZonesOutput
+		result2 error
+	}
+	GetStagedDirectorNetworksStub        func() (api.NetworksConfigurationOutput, error)
+	getStagedDirectorNetworksMutex       sync.RWMutex
+	getStagedDirectorNetworksArgsForCall []struct {
+	}
+	getStagedDirectorNetworksReturns struct {
+		result1 api.NetworksConfigurationOutput
+		result2 error
+	}
+	getStagedDirectorNetworksReturnsOnCall map[int]struct {
+		result1 api.NetworksConfigurationOutput
+		result2 error
+	}
+	GetStagedDirectorPropertiesStub        func(bool) (map[string]map[string]interface{}, error)
+	getStagedDirectorPropertiesMutex       sync.RWMutex
+	getStagedDirectorPropertiesArgsForCall []struct {
+		arg1 bool
+	}
+	getStagedDirectorPropertiesReturns struct {
+		result1 map[string]map[string]interface{}
+		result2 error
+	}
+	getStagedDirectorPropertiesReturnsOnCall map[int]struct {
+		result1 map[string]map[string]interface{}
+		result2 error
+	}
+	GetStagedProductByNameStub        func(string) (api.StagedProductsFindOutput, error)
+	getStagedProductByNameMutex       sync.RWMutex
+	getStagedProductByNameArgsForCall []struct {
+		arg1 string
+	}
+	getStagedProductByNameReturns struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}
+	getStagedProductByNameReturnsOnCall map[int]struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}
+	GetStagedProductJobResourceConfigStub        func(string, string) (api.JobProperties, error)
+	getStagedProductJobResourceConfigMutex       sync.RWMutex
+	getStagedProductJobResourceConfigArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getStagedProductJobResourceConfigReturns struct {
+		result1 api.JobProperties
+		result2 error
+	}
+	getStagedProductJobResourceConfigReturnsOnCall map[int]struct {
+		result1 api.JobProperties
+		result2 error
+	}
+	GetStagedProductNetworksAndAZsStub        func(string) (map[string]interface{}, error)
+	getStagedProductNetworksAndAZsMutex       sync.RWMutex
+	getStagedProductNetworksAndAZsArgsForCall []struct {
+		arg1 string
+	}
+	getStagedProductNetworksAndAZsReturns struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	getStagedProductNetworksAndAZsReturnsOnCall map[int]struct {
+		result1 map[string]interface{}
+		result2 error
+	}
+	GetStagedProductPropertiesStub        func(string) (map[string]api.ResponseProperty, error)
+	getStagedProductPropertiesMutex       sync.RWMutex
+	getStagedProductPropertiesArgsForCall []struct {
+		arg1 string
+	}
+	getStagedProductPropertiesReturns struct {
+		result1 map[string]api.ResponseProperty
+		result2 error
+	}
+	getStagedProductPropertiesReturnsOnCall map[int]struct {
+		result1 map[string]api.ResponseProperty
+		result2 error
+	}
+	ListStagedProductErrandsStub        func(string) (api.ErrandsListOutput, error)
+	listStagedProductErrandsMutex       sync.RWMutex
+	listStagedProductErrandsArgsForCall []struct {
+		arg1 string
+	}
+	listStagedProductErrandsReturns struct {
+		result1 api.ErrandsListOutput
+		result2 error
+	}
+	listStagedProductErrandsReturnsOnCall map[int]struct {
+		result1 api.ErrandsListOutput
+		result2 error
+	}
+	ListStagedProductJobsStub        func(string) (map[string]string, error)
+	listStagedProductJobsMutex       sync.RWMutex
+	listStagedProductJobsArgsForCall []struct {
+		arg1 string
+	}
+	listStagedProductJobsReturns struct {
+		result1 map[string]string
+		result2 error
+	}
+	listStagedProductJobsReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 error
+	}
+	ListStagedVMExtensionsStub        func() ([]api.VMExtension, error)
+	listStagedVMExtensionsMutex       sync.RWMutex
+	listStagedVMExtensionsArgsForCall []struct {
+	}
+	listStagedVMExtensionsReturns struct {
+		result1 []api.VMExtension
+		result2 error
+	}
+	listStagedVMExtensionsReturnsOnCall map[int]struct {
+		result1 []api.VMExtension
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CompareConfigService) GetStagedDirectorAvailabilityZones() (api.AvailabilityZonesOutput, error) {
+	fake.getStagedDirectorAvailabilityZonesMutex.Lock()
+	ret, specificReturn := fake.getStagedDirectorAvailabilityZonesReturnsOnCall[len(fake.getStagedDirectorAvailabilityZonesArgsForCall)]
+	fake.getStagedDirectorAvailabilityZonesArgsForCall = append(fake.getStagedDirectorAvailabilityZonesArgsForCall, struct {
+	}{})
+	stub := fake.GetStagedDirectorAvailabilityZonesStub
+	fakeReturns := fake.getStagedDirectorAvailabilityZonesReturns
+	fake.recordInvocation("GetStagedDirectorAvailabilityZones", []interface{}{})
+	fake.getStagedDirectorAvailabilityZonesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) GetStagedDirectorAvailabilityZonesCallCount() int {
+	fake.getStagedDirectorAvailabilityZonesMutex.RLock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.RUnlock()
+	return len(fake.getStagedDirectorAvailabilityZonesArgsForCall)
+}
+
+func (fake *CompareConfigService) GetStagedDirectorAvailabilityZonesCalls(stub func() (api.AvailabilityZonesOutput, error)) {
+	fake.getStagedDirectorAvailabilityZonesMutex.Lock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.Unlock()
+	fake.GetStagedDirectorAvailabilityZonesStub = stub
+}
+
+func (fake *CompareConfigService) GetStagedDirectorAvailabilityZonesReturns(result1 api.AvailabilityZonesOutput, result2 error) {
+	fake.getStagedDirectorAvailabilityZonesMutex.Lock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.Unlock()
+	fake.GetStagedDirectorAvailabilityZonesStub = nil
+	fake.getStagedDirectorAvailabilityZonesReturns = struct {
+		result1 api.AvailabilityZonesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedDirectorAvailabilityZonesReturnsOnCall(i int, result1 api.AvailabilityZonesOutput, result2 error) {
+	fake.getStagedDirectorAvailabilityZonesMutex.Lock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.Unlock()
+	fake.GetStagedDirectorAvailabilityZonesStub = nil
+	if fake.getStagedDirectorAvailabilityZonesReturnsOnCall == nil {
+		fake.getStagedDirectorAvailabilityZonesReturnsOnCall = make(map[int]struct {
+			result1 api.AvailabilityZonesOutput
+			result2 error
+		})
+	}
+	fake.getStagedDirectorAvailabilityZonesReturnsOnCall[i] = struct {
+		result1 api.AvailabilityZonesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedDirectorNetworks() (api.NetworksConfigurationOutput, error) {
+	fake.getStagedDirectorNetworksMutex.Lock()
+	ret, specificReturn := fake.getStagedDirectorNetworksReturnsOnCall[len(fake.getStagedDirectorNetworksArgsForCall)]
+	fake.getStagedDirectorNetworksArgsForCall = append(fake.getStagedDirectorNetworksArgsForCall, struct {
+	}{})
+	stub := fake.GetStagedDirectorNetworksStub
+	fakeReturns := fake.getStagedDirectorNetworksReturns
+	fake.recordInvocation("GetStagedDirectorNetworks", []interface{}{})
+	fake.getStagedDirectorNetworksMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) GetStagedDirectorNetworksCallCount() int {
+	fake.getStagedDirectorNetworksMutex.RLock()
+	defer fake.getStagedDirectorNetworksMutex.RUnlock()
+	return len(fake.getStagedDirectorNetworksArgsForCall)
+}
+
+func (fake *CompareConfigService) GetStagedDirectorNetworksCalls(stub func() (api.NetworksConfigurationOutput, error)) {
+	fake.getStagedDirectorNetworksMutex.Lock()
+	defer fake.getStagedDirectorNetworksMutex.Unlock()
+	fake.GetStagedDirectorNetworksStub = stub
+}
+
+func (fake *CompareConfigService) GetStagedDirectorNetworksReturns(result1 api.NetworksConfigurationOutput, result2 error) {
+	fake.getStagedDirectorNetworksMutex.Lock()
+	defer fake.getStagedDirectorNetworksMutex.Unlock()
+	fake.GetStagedDirectorNetworksStub = nil
+	fake.getStagedDirectorNetworksReturns = struct {
+		result1 api.NetworksConfigurationOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedDirectorNetworksReturnsOnCall(i int, result1 api.NetworksConfigurationOutput, result2 error) {
+	fake.getStagedDirectorNetworksMutex.Lock()
+	defer fake.getStagedDirectorNetworksMutex.Unlock()
+	fake.GetStagedDirectorNetworksStub = nil
+	if fake.getStagedDirectorNetworksReturnsOnCall == nil {
+		fake.getStagedDirectorNetworksReturnsOnCall = make(map[int]struct {
+			result1 api.NetworksConfigurationOutput
+			result2 error
+		})
+	}
+	fake.getStagedDirectorNetworksReturnsOnCall[i] = struct {
+		result1 api.NetworksConfigurationOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedDirectorProperties(arg1 bool) (map[string]map[string]interface{}, error) {
+	fake.getStagedDirectorPropertiesMutex.Lock()
+	ret, specificReturn := fake.getStagedDirectorPropertiesReturnsOnCall[len(fake.getStagedDirectorPropertiesArgsForCall)]
+	fake.getStagedDirectorPropertiesArgsForCall = append(fake.getStagedDirectorPropertiesArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.GetStagedDirectorPropertiesStub
+	fakeReturns := fake.getStagedDirectorPropertiesReturns
+	fake.recordInvocation("GetStagedDirectorProperties", []interface{}{arg1})
+	fake.getStagedDirectorPropertiesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) GetStagedDirectorPropertiesCallCount() int {
+	fake.getStagedDirectorPropertiesMutex.RLock()
+	defer fake.getStagedDirectorPropertiesMutex.RUnlock()
+	return len(fake.getStagedDirectorPropertiesArgsForCall)
+}
+
+func (fake *CompareConfigService) GetStagedDirectorPropertiesCalls(stub func(bool) (map[string]map[string]interface{}, error)) {
+	fake.getStagedDirectorPropertiesMutex.Lock()
+	defer fake.getStagedDirectorPropertiesMutex.Unlock()
+	fake.GetStagedDirectorPropertiesStub = stub
+}
+
+func (fake *CompareConfigService) GetStagedDirectorPropertiesArgsForCall(i int) bool {
+	fake.getStagedDirectorPropertiesMutex.RLock()
+	defer fake.getStagedDirectorPropertiesMutex.RUnlock()
+	argsForCall := fake.getStagedDirectorPropertiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CompareConfigService) GetStagedDirectorPropertiesReturns(result1 map[string]map[string]interface{}, result2 error) {
+	fake.getStagedDirectorPropertiesMutex.Lock()
+	defer fake.getStagedDirectorPropertiesMutex.Unlock()
+	fake.GetStagedDirectorPropertiesStub = nil
+	fake.getStagedDirectorPropertiesReturns = struct {
+		result1 map[string]map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedDirectorPropertiesReturnsOnCall(i int, result1 map[string]map[string]interface{}, result2 error) {
+	fake.getStagedDirectorPropertiesMutex.Lock()
+	defer fake.getStagedDirectorPropertiesMutex.Unlock()
+	fake.GetStagedDirectorPropertiesStub = nil
+	if fake.getStagedDirectorPropertiesReturnsOnCall == nil {
+		fake.getStagedDirectorPropertiesReturnsOnCall = make(map[int]struct {
+			result1 map[string]map[string]interface{}
+			result2 error
+		})
+	}
+	fake.getStagedDirectorPropertiesReturnsOnCall[i] = struct {
+		result1 map[string]map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedProductByName(arg1 string) (api.StagedProductsFindOutput, error) {
+	fake.getStagedProductByNameMutex.Lock()
+	ret, specificReturn := fake.getStagedProductByNameReturnsOnCall[len(fake.getStagedProductByNameArgsForCall)]
+	fake.getStagedProductByNameArgsForCall = append(fake.getStagedProductByNameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetStagedProductByNameStub
+	fakeReturns := fake.getStagedProductByNameReturns
+	fake.recordInvocation("GetStagedProductByName", []interface{}{arg1})
+	fake.getStagedProductByNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) GetStagedProductByNameCallCount() int {
+	fake.getStagedProductByNameMutex.RLock()
+	defer fake.getStagedProductByNameMutex.RUnlock()
+	return len(fake.getStagedProductByNameArgsForCall)
+}
+
+func (fake *CompareConfigService) GetStagedProductByNameCalls(stub func(string) (api.StagedProductsFindOutput, error)) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = stub
+}
+
+func (fake *CompareConfigService) GetStagedProductByNameArgsForCall(i int) string {
+	fake.getStagedProductByNameMutex.RLock()
+	defer fake.getStagedProductByNameMutex.RUnlock()
+	argsForCall := fake.getStagedProductByNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CompareConfigService) GetStagedProductByNameReturns(result1 api.StagedProductsFindOutput, result2 error) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = nil
+	fake.getStagedProductByNameReturns = struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedProductByNameReturnsOnCall(i int, result1 api.StagedProductsFindOutput, result2 error) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = nil
+	if fake.getStagedProductByNameReturnsOnCall == nil {
+		fake.getStagedProductByNameReturnsOnCall = make(map[int]struct {
+			result1 api.StagedProductsFindOutput
+			result2 error
+		})
+	}
+	fake.getStagedProductByNameReturnsOnCall[i] = struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedProductJobResourceConfig(arg1 string, arg2 string) (api.JobProperties, error) {
+	fake.getStagedProductJobResourceConfigMutex.Lock()
+	ret, specificReturn := fake.getStagedProductJobResourceConfigReturnsOnCall[len(fake.getStagedProductJobResourceConfigArgsForCall)]
+	fake.getStagedProductJobResourceConfigArgsForCall = append(fake.getStagedProductJobResourceConfigArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetStagedProductJobResourceConfigStub
+	fakeReturns := fake.getStagedProductJobResourceConfigReturns
+	fake.recordInvocation("GetStagedProductJobResourceConfig", []interface{}{arg1, arg2})
+	fake.getStagedProductJobResourceConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) GetStagedProductJobResourceConfigCallCount() int {
+	fake.getStagedProductJobResourceConfigMutex.RLock()
+	defer fake.getStagedProductJobResourceConfigMutex.RUnlock()
+	return len(fake.getStagedProductJobResourceConfigArgsForCall)
+}
+
+func (fake *CompareConfigService) GetStagedProductJobResourceConfigCalls(stub func(string, string) (api.JobProperties, error)) {
+	fake.getStagedProductJobResourceConfigMutex.Lock()
+	defer fake.getStagedProductJobResourceConfigMutex.Unlock()
+	fake.GetStagedProductJobResourceConfigStub = stub
+}
+
+func (fake *CompareConfigService) GetStagedProductJobResourceConfigArgsForCall(i int) (string, string) {
+	fake.getStagedProductJobResourceConfigMutex.RLock()
+	defer fake.getStagedProductJobResourceConfigMutex.RUnlock()
+	argsForCall := fake.getStagedProductJobResourceConfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *CompareConfigService) GetStagedProductJobResourceConfigReturns(result1 api.JobProperties, result2 error) {
+	fake.getStagedProductJobResourceConfigMutex.Lock()
+	defer fake.getStagedProductJobResourceConfigMutex.Unlock()
+	fake.GetStagedProductJobResourceConfigStub = nil
+	fake.getStagedProductJobResourceConfigReturns = struct {
+		result1 api.JobProperties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedProductJobResourceConfigReturnsOnCall(i int, result1 api.JobProperties, result2 error) {
+	fake.getStagedProductJobResourceConfigMutex.Lock()
+	defer fake.getStagedProductJobResourceConfigMutex.Unlock()
+	fake.GetStagedProductJobResourceConfigStub = nil
+	if fake.getStagedProductJobResourceConfigReturnsOnCall == nil {
+		fake.getStagedProductJobResourceConfigReturnsOnCall = make(map[int]struct {
+			result1 api.JobProperties
+			result2 error
+		})
+	}
+	fake.getStagedProductJobResourceConfigReturnsOnCall[i] = struct {
+		result1 api.JobProperties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedProductNetworksAndAZs(arg1 string) (map[string]interface{}, error) {
+	fake.getStagedProductNetworksAndAZsMutex.Lock()
+	ret, specificReturn := fake.getStagedProductNetworksAndAZsReturnsOnCall[len(fake.getStagedProductNetworksAndAZsArgsForCall)]
+	fake.getStagedProductNetworksAndAZsArgsForCall = append(fake.getStagedProductNetworksAndAZsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetStagedProductNetworksAndAZsStub
+	fakeReturns := fake.getStagedProductNetworksAndAZsReturns
+	fake.recordInvocation("GetStagedProductNetworksAndAZs", []interface{}{arg1})
+	fake.getStagedProductNetworksAndAZsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) GetStagedProductNetworksAndAZsCallCount() int {
+	fake.getStagedProductNetworksAndAZsMutex.RLock()
+	defer fake.getStagedProductNetworksAndAZsMutex.RUnlock()
+	return len(fake.getStagedProductNetworksAndAZsArgsForCall)
+}
+
+func (fake *CompareConfigService) GetStagedProductNetworksAndAZsCalls(stub func(string) (map[string]interface{}, error)) {
+	fake.getStagedProductNetworksAndAZsMutex.Lock()
+	defer fake.getStagedProductNetworksAndAZsMutex.Unlock()
+	fake.GetStagedProductNetworksAndAZsStub = stub
+}
+
+func (fake *CompareConfigService) GetStagedProductNetworksAndAZsArgsForCall(i int) string {
+	fake.getStagedProductNetworksAndAZsMutex.RLock()
+	defer fake.getStagedProductNetworksAndAZsMutex.RUnlock()
+	argsForCall := fake.getStagedProductNetworksAndAZsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CompareConfigService) GetStagedProductNetworksAndAZsReturns(result1 map[string]interface{}, result2 error) {
+	fake.getStagedProductNetworksAndAZsMutex.Lock()
+	defer fake.getStagedProductNetworksAndAZsMutex.Unlock()
+	fake.GetStagedProductNetworksAndAZsStub = nil
+	fake.getStagedProductNetworksAndAZsReturns = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedProductNetworksAndAZsReturnsOnCall(i int, result1 map[string]interface{}, result2 error) {
+	fake.getStagedProductNetworksAndAZsMutex.Lock()
+	defer fake.getStagedProductNetworksAndAZsMutex.Unlock()
+	fake.GetStagedProductNetworksAndAZsStub = nil
+	if fake.getStagedProductNetworksAndAZsReturnsOnCall == nil {
+		fake.getStagedProductNetworksAndAZsReturnsOnCall = make(map[int]struct {
+			result1 map[string]interface{}
+			result2 error
+		})
+	}
+	fake.getStagedProductNetworksAndAZsReturnsOnCall[i] = struct {
+		result1 map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedProductProperties(arg1 string) (map[string]api.ResponseProperty, error) {
+	fake.getStagedProductPropertiesMutex.Lock()
+	ret, specificReturn := fake.getStagedProductPropertiesReturnsOnCall[len(fake.getStagedProductPropertiesArgsForCall)]
+	fake.getStagedProductPropertiesArgsForCall = append(fake.getStagedProductPropertiesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetStagedProductPropertiesStub
+	fakeReturns := fake.getStagedProductPropertiesReturns
+	fake.recordInvocation("GetStagedProductProperties", []interface{}{arg1})
+	fake.getStagedProductPropertiesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) GetStagedProductPropertiesCallCount() int {
+	fake.getStagedProductPropertiesMutex.RLock()
+	defer fake.getStagedProductPropertiesMutex.RUnlock()
+	return len(fake.getStagedProductPropertiesArgsForCall)
+}
+
+func (fake *CompareConfigService) GetStagedProductPropertiesCalls(stub func(string) (map[string]api.ResponseProperty, error)) {
+	fake.getStagedProductPropertiesMutex.Lock()
+	defer fake.getStagedProductPropertiesMutex.Unlock()
+	fake.GetStagedProductPropertiesStub = stub
+}
+
+func (fake *CompareConfigService) GetStagedProductPropertiesArgsForCall(i int) string {
+	fake.getStagedProductPropertiesMutex.RLock()
+	defer fake.getStagedProductPropertiesMutex.RUnlock()
+	argsForCall := fake.getStagedProductPropertiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CompareConfigService) GetStagedProductPropertiesReturns(result1 map[string]api.ResponseProperty, result2 error) {
+	fake.getStagedProductPropertiesMutex.Lock()
+	defer fake.getStagedProductPropertiesMutex.Unlock()
+	fake.GetStagedProductPropertiesStub = nil
+	fake.getStagedProductPropertiesReturns = struct {
+		result1 map[string]api.ResponseProperty
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) GetStagedProductPropertiesReturnsOnCall(i int, result1 map[string]api.ResponseProperty, result2 error) {
+	fake.getStagedProductPropertiesMutex.Lock()
+	defer fake.getStagedProductPropertiesMutex.Unlock()
+	fake.GetStagedProductPropertiesStub = nil
+	if fake.getStagedProductPropertiesReturnsOnCall == nil {
+		fake.getStagedProductPropertiesReturnsOnCall = make(map[int]struct {
+			result1 map[string]api.ResponseProperty
+			result2 error
+		})
+	}
+	fake.getStagedProductPropertiesReturnsOnCall[i] = struct {
+		result1 map[string]api.ResponseProperty
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) ListStagedProductErrands(arg1 string) (api.ErrandsListOutput, error) {
+	fake.listStagedProductErrandsMutex.Lock()
+	ret, specificReturn := fake.listStagedProductErrandsReturnsOnCall[len(fake.listStagedProductErrandsArgsForCall)]
+	fake.listStagedProductErrandsArgsForCall = append(fake.listStagedProductErrandsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListStagedProductErrandsStub
+	fakeReturns := fake.listStagedProductErrandsReturns
+	fake.recordInvocation("ListStagedProductErrands", []interface{}{arg1})
+	fake.listStagedProductErrandsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) ListStagedProductErrandsCallCount() int {
+	fake.listStagedProductErrandsMutex.RLock()
+	defer fake.listStagedProductErrandsMutex.RUnlock()
+	return len(fake.listStagedProductErrandsArgsForCall)
+}
+
+func (fake *CompareConfigService) ListStagedProductErrandsCalls(stub func(string) (api.ErrandsListOutput, error)) {
+	fake.listStagedProductErrandsMutex.Lock()
+	defer fake.listStagedProductErrandsMutex.Unlock()
+	fake.ListStagedProductErrandsStub = stub
+}
+
+func (fake *CompareConfigService) ListStagedProductErrandsArgsForCall(i int) string {
+	fake.listStagedProductErrandsMutex.RLock()
+	defer fake.listStagedProductErrandsMutex.RUnlock()
+	argsForCall := fake.listStagedProductErrandsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CompareConfigService) ListStagedProductErrandsReturns(result1 api.ErrandsListOutput, result2 error) {
+	fake.listStagedProductErrandsMutex.Lock()
+	defer fake.listStagedProductErrandsMutex.Unlock()
+	fake.ListStagedProductErrandsStub = nil
+	fake.listStagedProductErrandsReturns = struct {
+		result1 api.ErrandsListOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) ListStagedProductErrandsReturnsOnCall(i int, result1 api.ErrandsListOutput, result2 error) {
+	fake.listStagedProductErrandsMutex.Lock()
+	defer fake.listStagedProductErrandsMutex.Unlock()
+	fake.ListStagedProductErrandsStub = nil
+	if fake.listStagedProductErrandsReturnsOnCall == nil {
+		fake.listStagedProductErrandsReturnsOnCall = make(map[int]struct {
+			result1 api.ErrandsListOutput
+			result2 error
+		})
+	}
+	fake.listStagedProductErrandsReturnsOnCall[i] = struct {
+		result1 api.ErrandsListOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) ListStagedProductJobs(arg1 string) (map[string]string, error) {
+	fake.listStagedProductJobsMutex.Lock()
+	ret, specificReturn := fake.listStagedProductJobsReturnsOnCall[len(fake.listStagedProductJobsArgsForCall)]
+	fake.listStagedProductJobsArgsForCall = append(fake.listStagedProductJobsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListStagedProductJobsStub
+	fakeReturns := fake.listStagedProductJobsReturns
+	fake.recordInvocation("ListStagedProductJobs", []interface{}{arg1})
+	fake.listStagedProductJobsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) ListStagedProductJobsCallCount() int {
+	fake.listStagedProductJobsMutex.RLock()
+	defer fake.listStagedProductJobsMutex.RUnlock()
+	return len(fake.listStagedProductJobsArgsForCall)
+}
+
+func (fake *CompareConfigService) ListStagedProductJobsCalls(stub func(string) (map[string]string, error)) {
+	fake.listStagedProductJobsMutex.Lock()
+	defer fake.listStagedProductJobsMutex.Unlock()
+	fake.ListStagedProductJobsStub = stub
+}
+
+func (fake *CompareConfigService) ListStagedProductJobsArgsForCall(i int) string {
+	fake.listStagedProductJobsMutex.RLock()
+	defer fake.listStagedProductJobsMutex.RUnlock()
+	argsForCall := fake.listStagedProductJobsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *CompareConfigService) ListStagedProductJobsReturns(result1 map[string]string, result2 error) {
+	fake.listStagedProductJobsMutex.Lock()
+	defer fake.listStagedProductJobsMutex.Unlock()
+	fake.ListStagedProductJobsStub = nil
+	fake.listStagedProductJobsReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) ListStagedProductJobsReturnsOnCall(i int, result1 map[string]string, result2 error) {
+	fake.listStagedProductJobsMutex.Lock()
+	defer fake.listStagedProductJobsMutex.Unlock()
+	fake.ListStagedProductJobsStub = nil
+	if fake.listStagedProductJobsReturnsOnCall == nil {
+		fake.listStagedProductJobsReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 error
+		})
+	}
+	fake.listStagedProductJobsReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) ListStagedVMExtensions() ([]api.VMExtension, error) {
+	fake.listStagedVMExtensionsMutex.Lock()
+	ret, specificReturn := fake.listStagedVMExtensionsReturnsOnCall[len(fake.listStagedVMExtensionsArgsForCall)]
+	fake.listStagedVMExtensionsArgsForCall = append(fake.listStagedVMExtensionsArgsForCall, struct {
+	}{})
+	stub := fake.ListStagedVMExtensionsStub
+	fakeReturns := fake.listStagedVMExtensionsReturns
+	fake.recordInvocation("ListStagedVMExtensions", []interface{}{})
+	fake.listStagedVMExtensionsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CompareConfigService) ListStagedVMExtensionsCallCount() int {
+	fake.listStagedVMExtensionsMutex.RLock()
+	defer fake.listStagedVMExtensionsMutex.RUnlock()
+	return len(fake.listStagedVMExtensionsArgsForCall)
+}
+
+func (fake *CompareConfigService) ListStagedVMExtensionsCalls(stub func() ([]api.VMExtension, error)) {
+	fake.listStagedVMExtensionsMutex.Lock()
+	defer fake.listStagedVMExtensionsMutex.Unlock()
+	fake.ListStagedVMExtensionsStub = stub
+}
+
+func (fake *CompareConfigService) ListStagedVMExtensionsReturns(result1 []api.VMExtension, result2 error) {
+	fake.listStagedVMExtensionsMutex.Lock()
+	defer fake.listStagedVMExtensionsMutex.Unlock()
+	fake.ListStagedVMExtensionsStub = nil
+	fake.listStagedVMExtensionsReturns = struct {
+		result1 []api.VMExtension
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) ListStagedVMExtensionsReturnsOnCall(i int, result1 []api.VMExtension, result2 error) {
+	fake.listStagedVMExtensionsMutex.Lock()
+	defer fake.listStagedVMExtensionsMutex.Unlock()
+	fake.ListStagedVMExtensionsStub = nil
+	if fake.listStagedVMExtensionsReturnsOnCall == nil {
+		fake.listStagedVMExtensionsReturnsOnCall = make(map[int]struct {
+			result1 []api.VMExtension
+			result2 error
+		})
+	}
+	fake.listStagedVMExtensionsReturnsOnCall[i] = struct {
+		result1 []api.VMExtension
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CompareConfigService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CompareConfigService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -22,6 +22,19 @@ type ConfigureProductService struct {
 		result1 api.JobProperties
 		result2 error
 	}
+	GetStagedProductPropertiesStub        func(string) (map[string]api.ResponseProperty, error)
+	getStagedProductPropertiesMutex       sync.RWMutex
+	getStagedProductPropertiesArgsForCall []struct {
+		arg1 string
+	}
+	getStagedProductPropertiesReturns struct {
+		result1 map[string]api.ResponseProperty
+		result2 error
+	}
+	getStagedProductPropertiesReturnsOnCall map[int]struct {
+		result1 map[string]api.ResponseProperty
+		result2 error
+	}
 	ListInstallationsStub        func() ([]api.InstallationsServiceOutput, error)
 	listInstallationsMutex       sync.RWMutex
 	listInstallationsArgsForCall []struct {
@@ -188,6 +201,69 @@ func (fake *ConfigureProductService) GetStagedProductJobResourceConfigReturnsOnC
 	}{result1, result2}
 }
 
+func (fake *ConfigureProductService) GetStagedProductProperties(arg1 string) (map[string]api.ResponseProperty, error) {
+	fake.getStagedProductPropertiesMutex.Lock()
+	ret, specificReturn := fake.getStagedProductPropertiesReturnsOnCall[len(fake.getStagedProductPropertiesArgsForCall)]
+	fake.getStagedProductPropertiesArgsForCall = append(fake.getStagedProductPropertiesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetStagedProductProperties", []interface{}{arg1})
+	fake.getStagedProductPropertiesMutex.Unlock()
+	if fake.GetStagedProductPropertiesStub != nil {
+		return fake.GetStagedProductPropertiesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStagedProductPropertiesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ConfigureProductService) GetStagedProductPropertiesCallCount() int {
+	fake.getStagedProductPropertiesMutex.RLock()
+	defer fake.getStagedProductPropertiesMutex.RUnlock()
+	return len(fake.getStagedProductPropertiesArgsForCall)
+}
+
+func (fake *ConfigureProductService) GetStagedProductPropertiesCalls(stub func(string) (map[string]api.ResponseProperty, error)) {
+	fake.getStagedProductPropertiesMutex.Lock()
+	defer fake.getStagedProductPropertiesMutex.Unlock()
+	fake.GetStagedProductPropertiesStub = stub
+}
+
+func (fake *ConfigureProductService) GetStagedProductPropertiesArgsForCall(i int) string {
+	fake.getStagedProductPropertiesMutex.RLock()
+	defer fake.getStagedProductPropertiesMutex.RUnlock()
+	argsForCall := fake.getStagedProductPropertiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ConfigureProductService) GetStagedProductPropertiesReturns(result1 map[string]api.ResponseProperty, result2 error) {
+	fake.getStagedProductPropertiesMutex.Lock()
+	defer fake.getStagedProductPropertiesMutex.Unlock()
+	fake.GetStagedProductPropertiesStub = nil
+	fake.getStagedProductPropertiesReturns = struct {
+		result1 map[string]api.ResponseProperty
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ConfigureProductService) GetStagedProductPropertiesReturnsOnCall(i int, result1 map[string]api.ResponseProperty, result2 error) {
+	fake.getStagedProductPropertiesMutex.Lock()
+	defer fake.getStagedProductPropertiesMutex.Unlock()
+	fake.GetStagedProductPropertiesStub = nil
+	if fake.getStagedProductPropertiesReturnsOnCall == nil {
+		fake.getStagedProductPropertiesReturnsOnCall = make(map[int]struct {
+			result1 map[string]api.ResponseProperty
+			result2 error
+		})
+	}
+	fake.getStagedProductPropertiesReturnsOnCall[i] = struct {
+		result1 map[string]api.ResponseProperty
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ConfigureProductService) ListInstallations() ([]api.InstallationsServiceOutput, error) {
 	fake.listInstallationsMutex.Lock()
 	ret, specificReturn := fake.listInstallationsReturnsOnCall[len(fake.listInstallationsArgsForCall)]
@@ -666,6 +742,8 @@ func (fake *ConfigureProductService) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.getStagedProductJobResourceConfigMutex.RLock()
 	defer fake.getStagedProductJobResourceConfigMutex.RUnlock()
+	fake.getStagedProductPropertiesMutex.RLock()
+	defer fake.getStagedProductPropertiesMutex.RUnlock()
 	fake.listInstallationsMutex.RLock()
 	defer fake.listInstallationsMutex.RUnlock()
 	fake.listStagedPendingChangesMutex.RLock()
@@ -0,0 +1,330 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type ConfigureSyslogService struct {
+	GetStagedProductByNameStub        func(string) (api.StagedProductsFindOutput, error)
+	getStagedProductByNameMutex       sync.RWMutex
+	getStagedProductByNameArgsForCall []struct {
+		arg1 string
+	}
+	getStagedProductByNameReturns struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}
+	getStagedProductByNameReturnsOnCall map[int]struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}
+	ListInstallationsStub        func() ([]api.InstallationsServiceOutput, error)
+	listInstallationsMutex       sync.RWMutex
+	listInstallationsArgsForCall []struct {
+	}
+	listInstallationsReturns struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}
+	listInstallationsReturnsOnCall map[int]struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}
+	UpdateStagedDirectorSyslogConfigurationStub        func(api.SyslogSettings) error
+	updateStagedDirectorSyslogConfigurationMutex       sync.RWMutex
+	updateStagedDirectorSyslogConfigurationArgsForCall []struct {
+		arg1 api.SyslogSettings
+	}
+	updateStagedDirectorSyslogConfigurationReturns struct {
+		result1 error
+	}
+	updateStagedDirectorSyslogConfigurationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStagedProductSyslogConfigurationStub        func(string, api.SyslogSettings) error
+	updateStagedProductSyslogConfigurationMutex       sync.RWMutex
+	updateStagedProductSyslogConfigurationArgsForCall []struct {
+		arg1 string
+		arg2 api.SyslogSettings
+	}
+	updateStagedProductSyslogConfigurationReturns struct {
+		result1 error
+	}
+	updateStagedProductSyslogConfigurationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ConfigureSyslogService) GetStagedProductByName(arg1 string) (api.StagedProductsFindOutput, error) {
+	fake.getStagedProductByNameMutex.Lock()
+	ret, specificReturn := fake.getStagedProductByNameReturnsOnCall[len(fake.getStagedProductByNameArgsForCall)]
+	fake.getStagedProductByNameArgsForCall = append(fake.getStagedProductByNameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetStagedProductByName", []interface{}{arg1})
+	fake.getStagedProductByNameMutex.Unlock()
+	if fake.GetStagedProductByNameStub != nil {
+		return fake.GetStagedProductByNameStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStagedProductByNameReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ConfigureSyslogService) GetStagedProductByNameCallCount() int {
+	fake.getStagedProductByNameMutex.RLock()
+	defer fake.getStagedProductByNameMutex.RUnlock()
+	return len(fake.getStagedProductByNameArgsForCall)
+}
+
+func (fake *ConfigureSyslogService) GetStagedProductByNameCalls(stub func(string) (api.StagedProductsFindOutput, error)) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = stub
+}
+
+func (fake *ConfigureSyslogService) GetStagedProductByNameArgsForCall(i int) string {
+	fake.getStagedProductByNameMutex.RLock()
+	defer fake.getStagedProductByNameMutex.RUnlock()
+	argsForCall := fake.getStagedProductByNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ConfigureSyslogService) GetStagedProductByNameReturns(result1 api.StagedProductsFindOutput, result2 error) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = nil
+	fake.getStagedProductByNameReturns = struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ConfigureSyslogService) GetStagedProductByNameReturnsOnCall(i int, result1 api.StagedProductsFindOutput, result2 error) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = nil
+	if fake.getStagedProductByNameReturnsOnCall == nil {
+		fake.getStagedProductByNameReturnsOnCall = make(map[int]struct {
+			result1 api.StagedProductsFindOutput
+			result2 error
+		})
+	}
+	fake.getStagedProductByNameReturnsOnCall[i] = struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ConfigureSyslogService) ListInstallations() ([]api.InstallationsServiceOutput, error) {
+	fake.listInstallationsMutex.Lock()
+	ret, specificReturn := fake.listInstallationsReturnsOnCall[len(fake.listInstallationsArgsForCall)]
+	fake.listInstallationsArgsForCall = append(fake.listInstallationsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListInstallations", []interface{}{})
+	fake.listInstallationsMutex.Unlock()
+	if fake.ListInstallationsStub != nil {
+		return fake.ListInstallationsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listInstallationsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ConfigureSyslogService) ListInstallationsCallCount() int {
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	return len(fake.listInstallationsArgsForCall)
+}
+
+func (fake *ConfigureSyslogService) ListInstallationsCalls(stub func() ([]api.InstallationsServiceOutput, error)) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = stub
+}
+
+func (fake *ConfigureSyslogService) ListInstallationsReturns(result1 []api.InstallationsServiceOutput, result2 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	fake.listInstallationsReturns = struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ConfigureSyslogService) ListInstallationsReturnsOnCall(i int, result1 []api.InstallationsServiceOutput, result2 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	if fake.listInstallationsReturnsOnCall == nil {
+		fake.listInstallationsReturnsOnCall = make(map[int]struct {
+			result1 []api.InstallationsServiceOutput
+			result2 error
+		})
+	}
+	fake.listInstallationsReturnsOnCall[i] = struct {
+		result1 []api.InstallationsServiceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedDirectorSyslogConfiguration(arg1 api.SyslogSettings) error {
+	fake.updateStagedDirectorSyslogConfigurationMutex.Lock()
+	ret, specificReturn := fake.updateStagedDirectorSyslogConfigurationReturnsOnCall[len(fake.updateStagedDirectorSyslogConfigurationArgsForCall)]
+	fake.updateStagedDirectorSyslogConfigurationArgsForCall = append(fake.updateStagedDirectorSyslogConfigurationArgsForCall, struct {
+		arg1 api.SyslogSettings
+	}{arg1})
+	fake.recordInvocation("UpdateStagedDirectorSyslogConfiguration", []interface{}{arg1})
+	fake.updateStagedDirectorSyslogConfigurationMutex.Unlock()
+	if fake.UpdateStagedDirectorSyslogConfigurationStub != nil {
+		return fake.UpdateStagedDirectorSyslogConfigurationStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.updateStagedDirectorSyslogConfigurationReturns
+	return fakeReturns.result1
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedDirectorSyslogConfigurationCallCount() int {
+	fake.updateStagedDirectorSyslogConfigurationMutex.RLock()
+	defer fake.updateStagedDirectorSyslogConfigurationMutex.RUnlock()
+	return len(fake.updateStagedDirectorSyslogConfigurationArgsForCall)
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedDirectorSyslogConfigurationCalls(stub func(api.SyslogSettings) error) {
+	fake.updateStagedDirectorSyslogConfigurationMutex.Lock()
+	defer fake.updateStagedDirectorSyslogConfigurationMutex.Unlock()
+	fake.UpdateStagedDirectorSyslogConfigurationStub = stub
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedDirectorSyslogConfigurationArgsForCall(i int) api.SyslogSettings {
+	fake.updateStagedDirectorSyslogConfigurationMutex.RLock()
+	defer fake.updateStagedDirectorSyslogConfigurationMutex.RUnlock()
+	argsForCall := fake.updateStagedDirectorSyslogConfigurationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedDirectorSyslogConfigurationReturns(result1 error) {
+	fake.updateStagedDirectorSyslogConfigurationMutex.Lock()
+	defer fake.updateStagedDirectorSyslogConfigurationMutex.Unlock()
+	fake.UpdateStagedDirectorSyslogConfigurationStub = nil
+	fake.updateStagedDirectorSyslogConfigurationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedDirectorSyslogConfigurationReturnsOnCall(i int, result1 error) {
+	fake.updateStagedDirectorSyslogConfigurationMutex.Lock()
+	defer fake.updateStagedDirectorSyslogConfigurationMutex.Unlock()
+	fake.UpdateStagedDirectorSyslogConfigurationStub = nil
+	if fake.updateStagedDirectorSyslogConfigurationReturnsOnCall == nil {
+		fake.updateStagedDirectorSyslogConfigurationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStagedDirectorSyslogConfigurationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedProductSyslogConfiguration(arg1 string, arg2 api.SyslogSettings) error {
+	fake.updateStagedProductSyslogConfigurationMutex.Lock()
+	ret, specificReturn := fake.updateStagedProductSyslogConfigurationReturnsOnCall[len(fake.updateStagedProductSyslogConfigurationArgsForCall)]
+	fake.updateStagedProductSyslogConfigurationArgsForCall = append(fake.updateStagedProductSyslogConfigurationArgsForCall, struct {
+		arg1 string
+		arg2 api.SyslogSettings
+	}{arg1, arg2})
+	fake.recordInvocation("UpdateStagedProductSyslogConfiguration", []interface{}{arg1, arg2})
+	fake.updateStagedProductSyslogConfigurationMutex.Unlock()
+	if fake.UpdateStagedProductSyslogConfigurationStub != nil {
+		return fake.UpdateStagedProductSyslogConfigurationStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.updateStagedProductSyslogConfigurationReturns
+	return fakeReturns.result1
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedProductSyslogConfigurationCallCount() int {
+	fake.updateStagedProductSyslogConfigurationMutex.RLock()
+	defer fake.updateStagedProductSyslogConfigurationMutex.RUnlock()
+	return len(fake.updateStagedProductSyslogConfigurationArgsForCall)
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedProductSyslogConfigurationCalls(stub func(string, api.SyslogSettings) error) {
+	fake.updateStagedProductSyslogConfigurationMutex.Lock()
+	defer fake.updateStagedProductSyslogConfigurationMutex.Unlock()
+	fake.UpdateStagedProductSyslogConfigurationStub = stub
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedProductSyslogConfigurationArgsForCall(i int) (string, api.SyslogSettings) {
+	fake.updateStagedProductSyslogConfigurationMutex.RLock()
+	defer fake.updateStagedProductSyslogConfigurationMutex.RUnlock()
+	argsForCall := fake.updateStagedProductSyslogConfigurationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedProductSyslogConfigurationReturns(result1 error) {
+	fake.updateStagedProductSyslogConfigurationMutex.Lock()
+	defer fake.updateStagedProductSyslogConfigurationMutex.Unlock()
+	fake.UpdateStagedProductSyslogConfigurationStub = nil
+	fake.updateStagedProductSyslogConfigurationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ConfigureSyslogService) UpdateStagedProductSyslogConfigurationReturnsOnCall(i int, result1 error) {
+	fake.updateStagedProductSyslogConfigurationMutex.Lock()
+	defer fake.updateStagedProductSyslogConfigurationMutex.Unlock()
+	fake.UpdateStagedProductSyslogConfigurationStub = nil
+	if fake.updateStagedProductSyslogConfigurationReturnsOnCall == nil {
+		fake.updateStagedProductSyslogConfigurationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStagedProductSyslogConfigurationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ConfigureSyslogService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getStagedProductByNameMutex.RLock()
+	defer fake.getStagedProductByNameMutex.RUnlock()
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	fake.updateStagedDirectorSyslogConfigurationMutex.RLock()
+	defer fake.updateStagedDirectorSyslogConfigurationMutex.RUnlock()
+	fake.updateStagedProductSyslogConfigurationMutex.RLock()
+	defer fake.updateStagedProductSyslogConfigurationMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ConfigureSyslogService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
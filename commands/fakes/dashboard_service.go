@@ -1,246 +0,0 @@
-// Code generated by counterfeiter. DO NOT EDIT.
-package fakes
-
-import (
-	sync "sync"
-
-	ui "github.com/pivotal-cf/om/ui"
-)
-
-type DashboardService struct {
-	GetInstallFormStub        func() (ui.Form, error)
-	getInstallFormMutex       sync.RWMutex
-	getInstallFormArgsForCall []struct {
-	}
-	getInstallFormReturns struct {
-		result1 ui.Form
-		result2 error
-	}
-	getInstallFormReturnsOnCall map[int]struct {
-		result1 ui.Form
-		result2 error
-	}
-	GetRevertFormStub        func() (ui.Form, error)
-	getRevertFormMutex       sync.RWMutex
-	getRevertFormArgsForCall []struct {
-	}
-	getRevertFormReturns struct {
-		result1 ui.Form
-		result2 error
-	}
-	getRevertFormReturnsOnCall map[int]struct {
-		result1 ui.Form
-		result2 error
-	}
-	PostInstallFormStub        func(ui.PostFormInput) error
-	postInstallFormMutex       sync.RWMutex
-	postInstallFormArgsForCall []struct {
-		arg1 ui.PostFormInput
-	}
-	postInstallFormReturns struct {
-		result1 error
-	}
-	postInstallFormReturnsOnCall map[int]struct {
-		result1 error
-	}
-	invocations      map[string][][]interface{}
-	invocationsMutex sync.RWMutex
-}
-
-func (fake *DashboardService) GetInstallForm() (ui.Form, error) {
-	fake.getInstallFormMutex.Lock()
-	ret, specificReturn := fake.getInstallFormReturnsOnCall[len(fake.getInstallFormArgsForCall)]
-	fake.getInstallFormArgsForCall = append(fake.getInstallFormArgsForCall, struct {
-	}{})
-	fake.recordInvocation("GetInstallForm", []interface{}{})
-	fake.getInstallFormMutex.Unlock()
-	if fake.GetInstallFormStub != nil {
-		return fake.GetInstallFormStub()
-	}
-	if specificReturn {
-		return ret.result1, ret.result2
-	}
-	fakeReturns := fake.getInstallFormReturns
-	return fakeReturns.result1, fakeReturns.result2
-}
-
-func (fake *DashboardService) GetInstallFormCallCount() int {
-	fake.getInstallFormMutex.RLock()
-	defer fake.getInstallFormMutex.RUnlock()
-	return len(fake.getInstallFormArgsForCall)
-}
-
-func (fake *DashboardService) GetInstallFormCalls(stub func() (ui.Form, error)) {
-	fake.getInstallFormMutex.Lock()
-	defer fake.getInstallFormMutex.Unlock()
-	fake.GetInstallFormStub = stub
-}
-
-func (fake *DashboardService) GetInstallFormReturns(result1 ui.Form, result2 error) {
-	fake.getInstallFormMutex.Lock()
-	defer fake.getInstallFormMutex.Unlock()
-	fake.GetInstallFormStub = nil
-	fake.getInstallFormReturns = struct {
-		result1 ui.Form
-		result2 error
-	}{result1, result2}
-}
-
-func (fake *DashboardService) GetInstallFormReturnsOnCall(i int, result1 ui.Form, result2 error) {
-	fake.getInstallFormMutex.Lock()
-	defer fake.getInstallFormMutex.Unlock()
-	fake.GetInstallFormStub = nil
-	if fake.getInstallFormReturnsOnCall == nil {
-		fake.getInstallFormReturnsOnCall = make(map[int]struct {
-			result1 ui.Form
-			result2 error
-		})
-	}
-	fake.getInstallFormReturnsOnCall[i] = struct {
-		result1 ui.Form
-		result2 error
-	}{result1, result2}
-}
-
-func (fake *DashboardService) GetRevertForm() (ui.Form, error) {
-	fake.getRevertFormMutex.Lock()
-	ret, specificReturn := fake.getRevertFormReturnsOnCall[len(fake.getRevertFormArgsForCall)]
-	fake.getRevertFormArgsForCall = append(fake.getRevertFormArgsForCall, struct {
-	}{})
-	fake.recordInvocation("GetRevertForm", []interface{}{})
-	fake.getRevertFormMutex.Unlock()
-	if fake.GetRevertFormStub != nil {
-		return fake.GetRevertFormStub()
-	}
-	if specificReturn {
-		return ret.result1, ret.result2
-	}
-	fakeReturns := fake.getRevertFormReturns
-	return fakeReturns.result1, fakeReturns.result2
-}
-
-func (fake *DashboardService) GetRevertFormCallCount() int {
-	fake.getRevertFormMutex.RLock()
-	defer fake.getRevertFormMutex.RUnlock()
-	return len(fake.getRevertFormArgsForCall)
-}
-
-func (fake *DashboardService) GetRevertFormCalls(stub func() (ui.Form, error)) {
-	fake.getRevertFormMutex.Lock()
-	defer fake.getRevertFormMutex.Unlock()
-	fake.GetRevertFormStub = stub
-}
-
-func (fake *DashboardService) GetRevertFormReturns(result1 ui.Form, result2 error) {
-	fake.getRevertFormMutex.Lock()
-	defer fake.getRevertFormMutex.Unlock()
-	fake.GetRevertFormStub = nil
-	fake.getRevertFormReturns = struct {
-		result1 ui.Form
-		result2 error
-	}{result1, result2}
-}
-
-func (fake *DashboardService) GetRevertFormReturnsOnCall(i int, result1 ui.Form, result2 error) {
-	fake.getRevertFormMutex.Lock()
-	defer fake.getRevertFormMutex.Unlock()
-	fake.GetRevertFormStub = nil
-	if fake.getRevertFormReturnsOnCall == nil {
-		fake.getRevertFormReturnsOnCall = make(map[int]struct {
-			result1 ui.Form
-			result2 error
-		})
-	}
-	fake.getRevertFormReturnsOnCall[i] = struct {
-		result1 ui.Form
-		result2 error
-	}{result1, result2}
-}
-
-func (fake *DashboardService) PostInstallForm(arg1 ui.PostFormInput) error {
-	fake.postInstallFormMutex.Lock()
-	ret, specificReturn := fake.postInstallFormReturnsOnCall[len(fake.postInstallFormArgsForCall)]
-	fake.postInstallFormArgsForCall = append(fake.postInstallFormArgsForCall, struct {
-		arg1 ui.PostFormInput
-	}{arg1})
-	fake.recordInvocation("PostInstallForm", []interface{}{arg1})
-	fake.postInstallFormMutex.Unlock()
-	if fake.PostInstallFormStub != nil {
-		return fake.PostInstallFormStub(arg1)
-	}
-	if specificReturn {
-		return ret.result1
-	}
-	fakeReturns := fake.postInstallFormReturns
-	return fakeReturns.result1
-}
-
-func (fake *DashboardService) PostInstallFormCallCount() int {
-	fake.postInstallFormMutex.RLock()
-	defer fake.postInstallFormMutex.RUnlock()
-	return len(fake.postInstallFormArgsForCall)
-}
-
-func (fake *DashboardService) PostInstallFormCalls(stub func(ui.PostFormInput) error) {
-	fake.postInstallFormMutex.Lock()
-	defer fake.postInstallFormMutex.Unlock()
-	fake.PostInstallFormStub = stub
-}
-
-func (fake *DashboardService) PostInstallFormArgsForCall(i int) ui.PostFormInput {
-	fake.postInstallFormMutex.RLock()
-	defer fake.postInstallFormMutex.RUnlock()
-	argsForCall := fake.postInstallFormArgsForCall[i]
-	return argsForCall.arg1
-}
-
-func (fake *DashboardService) PostInstallFormReturns(result1 error) {
-	fake.postInstallFormMutex.Lock()
-	defer fake.postInstallFormMutex.Unlock()
-	fake.PostInstallFormStub = nil
-	fake.postInstallFormReturns = struct {
-		result1 error
-	}{result1}
-}
-
-func (fake *DashboardService) PostInstallFormReturnsOnCall(i int, result1 error) {
-	fake.postInstallFormMutex.Lock()
-	defer fake.postInstallFormMutex.Unlock()
-	fake.PostInstallFormStub = nil
-	if fake.postInstallFormReturnsOnCall == nil {
-		fake.postInstallFormReturnsOnCall = make(map[int]struct {
-			result1 error
-		})
-	}
-	fake.postInstallFormReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
-}
-
-func (fake *DashboardService) Invocations() map[string][][]interface{} {
-	fake.invocationsMutex.RLock()
-	defer fake.invocationsMutex.RUnlock()
-	fake.getInstallFormMutex.RLock()
-	defer fake.getInstallFormMutex.RUnlock()
-	fake.getRevertFormMutex.RLock()
-	defer fake.getRevertFormMutex.RUnlock()
-	fake.postInstallFormMutex.RLock()
-	defer fake.postInstallFormMutex.RUnlock()
-	copiedInvocations := map[string][][]interface{}{}
-	for key, value := range fake.invocations {
-		copiedInvocations[key] = value
-	}
-	return copiedInvocations
-}
-
-func (fake *DashboardService) recordInvocation(key string, args []interface{}) {
-	fake.invocationsMutex.Lock()
-	defer fake.invocationsMutex.Unlock()
-	if fake.invocations == nil {
-		fake.invocations = map[string][][]interface{}{}
-	}
-	if fake.invocations[key] == nil {
-		fake.invocations[key] = [][]interface{}{}
-	}
-	fake.invocations[key] = append(fake.invocations[key], args)
-}
@@ -19,6 +19,30 @@ type DeleteUnusedProductsService struct {
 	deleteAvailableProductsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	GetDiagnosticReportStub        func() (api.DiagnosticReport, error)
+	getDiagnosticReportMutex       sync.RWMutex
+	getDiagnosticReportArgsForCall []struct {
+	}
+	getDiagnosticReportReturns struct {
+		result1 api.DiagnosticReport
+		result2 error
+	}
+	getDiagnosticReportReturnsOnCall map[int]struct {
+		result1 api.DiagnosticReport
+		result2 error
+	}
+	ListAvailableProductsStub        func() (api.AvailableProductsOutput, error)
+	listAvailableProductsMutex       sync.RWMutex
+	listAvailableProductsArgsForCall []struct {
+	}
+	listAvailableProductsReturns struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}
+	listAvailableProductsReturnsOnCall map[int]struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -83,11 +107,125 @@ func (fake *DeleteUnusedProductsService) DeleteAvailableProductsReturnsOnCall(i
 	}{result1}
 }
 
+func (fake *DeleteUnusedProductsService) GetDiagnosticReport() (api.DiagnosticReport, error) {
+	fake.getDiagnosticReportMutex.Lock()
+	ret, specificReturn := fake.getDiagnosticReportReturnsOnCall[len(fake.getDiagnosticReportArgsForCall)]
+	fake.getDiagnosticReportArgsForCall = append(fake.getDiagnosticReportArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetDiagnosticReport", []interface{}{})
+	fake.getDiagnosticReportMutex.Unlock()
+	if fake.GetDiagnosticReportStub != nil {
+		return fake.GetDiagnosticReportStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getDiagnosticReportReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DeleteUnusedProductsService) GetDiagnosticReportCallCount() int {
+	fake.getDiagnosticReportMutex.RLock()
+	defer fake.getDiagnosticReportMutex.RUnlock()
+	return len(fake.getDiagnosticReportArgsForCall)
+}
+
+func (fake *DeleteUnusedProductsService) GetDiagnosticReportCalls(stub func() (api.DiagnosticReport, error)) {
+	fake.getDiagnosticReportMutex.Lock()
+	defer fake.getDiagnosticReportMutex.Unlock()
+	fake.GetDiagnosticReportStub = stub
+}
+
+func (fake *DeleteUnusedProductsService) GetDiagnosticReportReturns(result1 api.DiagnosticReport, result2 error) {
+	fake.getDiagnosticReportMutex.Lock()
+	defer fake.getDiagnosticReportMutex.Unlock()
+	fake.GetDiagnosticReportStub = nil
+	fake.getDiagnosticReportReturns = struct {
+		result1 api.DiagnosticReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DeleteUnusedProductsService) GetDiagnosticReportReturnsOnCall(i int, result1 api.DiagnosticReport, result2 error) {
+	fake.getDiagnosticReportMutex.Lock()
+	defer fake.getDiagnosticReportMutex.Unlock()
+	fake.GetDiagnosticReportStub = nil
+	if fake.getDiagnosticReportReturnsOnCall == nil {
+		fake.getDiagnosticReportReturnsOnCall = make(map[int]struct {
+			result1 api.DiagnosticReport
+			result2 error
+		})
+	}
+	fake.getDiagnosticReportReturnsOnCall[i] = struct {
+		result1 api.DiagnosticReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DeleteUnusedProductsService) ListAvailableProducts() (api.AvailableProductsOutput, error) {
+	fake.listAvailableProductsMutex.Lock()
+	ret, specificReturn := fake.listAvailableProductsReturnsOnCall[len(fake.listAvailableProductsArgsForCall)]
+	fake.listAvailableProductsArgsForCall = append(fake.listAvailableProductsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListAvailableProducts", []interface{}{})
+	fake.listAvailableProductsMutex.Unlock()
+	if fake.ListAvailableProductsStub != nil {
+		return fake.ListAvailableProductsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listAvailableProductsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DeleteUnusedProductsService) ListAvailableProductsCallCount() int {
+	fake.listAvailableProductsMutex.RLock()
+	defer fake.listAvailableProductsMutex.RUnlock()
+	return len(fake.listAvailableProductsArgsForCall)
+}
+
+func (fake *DeleteUnusedProductsService) ListAvailableProductsCalls(stub func() (api.AvailableProductsOutput, error)) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = stub
+}
+
+func (fake *DeleteUnusedProductsService) ListAvailableProductsReturns(result1 api.AvailableProductsOutput, result2 error) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = nil
+	fake.listAvailableProductsReturns = struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DeleteUnusedProductsService) ListAvailableProductsReturnsOnCall(i int, result1 api.AvailableProductsOutput, result2 error) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = nil
+	if fake.listAvailableProductsReturnsOnCall == nil {
+		fake.listAvailableProductsReturnsOnCall = make(map[int]struct {
+			result1 api.AvailableProductsOutput
+			result2 error
+		})
+	}
+	fake.listAvailableProductsReturnsOnCall[i] = struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *DeleteUnusedProductsService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.deleteAvailableProductsMutex.RLock()
 	defer fake.deleteAvailableProductsMutex.RUnlock()
+	fake.getDiagnosticReportMutex.RLock()
+	defer fake.getDiagnosticReportMutex.RUnlock()
+	fake.listAvailableProductsMutex.RLock()
+	defer fake.listAvailableProductsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,106 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+)
+
+type DeleteVMExtensionService struct {
+	DeleteVMExtensionStub        func(string) error
+	deleteVMExtensionMutex       sync.RWMutex
+	deleteVMExtensionArgsForCall []struct {
+		arg1 string
+	}
+	deleteVMExtensionReturns struct {
+		result1 error
+	}
+	deleteVMExtensionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *DeleteVMExtensionService) DeleteVMExtension(arg1 string) error {
+	fake.deleteVMExtensionMutex.Lock()
+	ret, specificReturn := fake.deleteVMExtensionReturnsOnCall[len(fake.deleteVMExtensionArgsForCall)]
+	fake.deleteVMExtensionArgsForCall = append(fake.deleteVMExtensionArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteVMExtension", []interface{}{arg1})
+	fake.deleteVMExtensionMutex.Unlock()
+	if fake.DeleteVMExtensionStub != nil {
+		return fake.DeleteVMExtensionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteVMExtensionReturns
+	return fakeReturns.result1
+}
+
+func (fake *DeleteVMExtensionService) DeleteVMExtensionCallCount() int {
+	fake.deleteVMExtensionMutex.RLock()
+	defer fake.deleteVMExtensionMutex.RUnlock()
+	return len(fake.deleteVMExtensionArgsForCall)
+}
+
+func (fake *DeleteVMExtensionService) DeleteVMExtensionCalls(stub func(string) error) {
+	fake.deleteVMExtensionMutex.Lock()
+	defer fake.deleteVMExtensionMutex.Unlock()
+	fake.DeleteVMExtensionStub = stub
+}
+
+func (fake *DeleteVMExtensionService) DeleteVMExtensionArgsForCall(i int) string {
+	fake.deleteVMExtensionMutex.RLock()
+	defer fake.deleteVMExtensionMutex.RUnlock()
+	argsForCall := fake.deleteVMExtensionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *DeleteVMExtensionService) DeleteVMExtensionReturns(result1 error) {
+	fake.deleteVMExtensionMutex.Lock()
+	defer fake.deleteVMExtensionMutex.Unlock()
+	fake.DeleteVMExtensionStub = nil
+	fake.deleteVMExtensionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *DeleteVMExtensionService) DeleteVMExtensionReturnsOnCall(i int, result1 error) {
+	fake.deleteVMExtensionMutex.Lock()
+	defer fake.deleteVMExtensionMutex.Unlock()
+	fake.DeleteVMExtensionStub = nil
+	if fake.deleteVMExtensionReturnsOnCall == nil {
+		fake.deleteVMExtensionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteVMExtensionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *DeleteVMExtensionService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteVMExtensionMutex.RLock()
+	defer fake.deleteVMExtensionMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *DeleteVMExtensionService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -8,6 +8,18 @@ import (
 )
 
 type DeployedManifestService struct {
+	GetDeployedDirectorManifestStub        func() (string, error)
+	getDeployedDirectorManifestMutex       sync.RWMutex
+	getDeployedDirectorManifestArgsForCall []struct {
+	}
+	getDeployedDirectorManifestReturns struct {
+		result1 string
+		result2 error
+	}
+	getDeployedDirectorManifestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	GetDeployedProductManifestStub        func(string) (string, error)
 	getDeployedProductManifestMutex       sync.RWMutex
 	getDeployedProductManifestArgsForCall []struct {
@@ -37,6 +49,61 @@ type DeployedManifestService struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *DeployedManifestService) GetDeployedDirectorManifest() (string, error) {
+	fake.getDeployedDirectorManifestMutex.Lock()
+	ret, specificReturn := fake.getDeployedDirectorManifestReturnsOnCall[len(fake.getDeployedDirectorManifestArgsForCall)]
+	fake.getDeployedDirectorManifestArgsForCall = append(fake.getDeployedDirectorManifestArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetDeployedDirectorManifest", []interface{}{})
+	fake.getDeployedDirectorManifestMutex.Unlock()
+	if fake.GetDeployedDirectorManifestStub != nil {
+		return fake.GetDeployedDirectorManifestStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getDeployedDirectorManifestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DeployedManifestService) GetDeployedDirectorManifestCallCount() int {
+	fake.getDeployedDirectorManifestMutex.RLock()
+	defer fake.getDeployedDirectorManifestMutex.RUnlock()
+	return len(fake.getDeployedDirectorManifestArgsForCall)
+}
+
+func (fake *DeployedManifestService) GetDeployedDirectorManifestCalls(stub func() (string, error)) {
+	fake.getDeployedDirectorManifestMutex.Lock()
+	defer fake.getDeployedDirectorManifestMutex.Unlock()
+	fake.GetDeployedDirectorManifestStub = stub
+}
+
+func (fake *DeployedManifestService) GetDeployedDirectorManifestReturns(result1 string, result2 error) {
+	fake.getDeployedDirectorManifestMutex.Lock()
+	defer fake.getDeployedDirectorManifestMutex.Unlock()
+	fake.GetDeployedDirectorManifestStub = nil
+	fake.getDeployedDirectorManifestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DeployedManifestService) GetDeployedDirectorManifestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getDeployedDirectorManifestMutex.Lock()
+	defer fake.getDeployedDirectorManifestMutex.Unlock()
+	fake.GetDeployedDirectorManifestStub = nil
+	if fake.getDeployedDirectorManifestReturnsOnCall == nil {
+		fake.getDeployedDirectorManifestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getDeployedDirectorManifestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *DeployedManifestService) GetDeployedProductManifest(arg1 string) (string, error) {
 	fake.getDeployedProductManifestMutex.Lock()
 	ret, specificReturn := fake.getDeployedProductManifestReturnsOnCall[len(fake.getDeployedProductManifestArgsForCall)]
@@ -158,6 +225,8 @@ func (fake *DeployedManifestService) ListDeployedProductsReturnsOnCall(i int, re
 func (fake *DeployedManifestService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.getDeployedDirectorManifestMutex.RLock()
+	defer fake.getDeployedDirectorManifestMutex.RUnlock()
 	fake.getDeployedProductManifestMutex.RLock()
 	defer fake.getDeployedProductManifestMutex.RUnlock()
 	fake.listDeployedProductsMutex.RLock()
@@ -0,0 +1,467 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type DiffService struct {
+	GetDeployedDirectorManifestStub        func() (string, error)
+	getDeployedDirectorManifestMutex       sync.RWMutex
+	getDeployedDirectorManifestArgsForCall []struct {
+	}
+	getDeployedDirectorManifestReturns struct {
+		result1 string
+		result2 error
+	}
+	getDeployedDirectorManifestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetDeployedProductManifestStub        func(string) (string, error)
+	getDeployedProductManifestMutex       sync.RWMutex
+	getDeployedProductManifestArgsForCall []struct {
+		arg1 string
+	}
+	getDeployedProductManifestReturns struct {
+		result1 string
+		result2 error
+	}
+	getDeployedProductManifestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetStagedDirectorManifestStub        func() (string, error)
+	getStagedDirectorManifestMutex       sync.RWMutex
+	getStagedDirectorManifestArgsForCall []struct {
+	}
+	getStagedDirectorManifestReturns struct {
+		result1 string
+		result2 error
+	}
+	getStagedDirectorManifestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetStagedProductManifestStub        func(string) (string, error)
+	getStagedProductManifestMutex       sync.RWMutex
+	getStagedProductManifestArgsForCall []struct {
+		arg1 string
+	}
+	getStagedProductManifestReturns struct {
+		result1 string
+		result2 error
+	}
+	getStagedProductManifestReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	ListDeployedProductsStub        func() ([]api.DeployedProductOutput, error)
+	listDeployedProductsMutex       sync.RWMutex
+	listDeployedProductsArgsForCall []struct {
+	}
+	listDeployedProductsReturns struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}
+	listDeployedProductsReturnsOnCall map[int]struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}
+	ListStagedProductsStub        func() (api.StagedProductsOutput, error)
+	listStagedProductsMutex       sync.RWMutex
+	listStagedProductsArgsForCall []struct {
+	}
+	listStagedProductsReturns struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}
+	listStagedProductsReturnsOnCall map[int]struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *DiffService) GetDeployedDirectorManifest() (string, error) {
+	fake.getDeployedDirectorManifestMutex.Lock()
+	ret, specificReturn := fake.getDeployedDirectorManifestReturnsOnCall[len(fake.getDeployedDirectorManifestArgsForCall)]
+	fake.getDeployedDirectorManifestArgsForCall = append(fake.getDeployedDirectorManifestArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetDeployedDirectorManifest", []interface{}{})
+	fake.getDeployedDirectorManifestMutex.Unlock()
+	if fake.GetDeployedDirectorManifestStub != nil {
+		return fake.GetDeployedDirectorManifestStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getDeployedDirectorManifestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DiffService) GetDeployedDirectorManifestCallCount() int {
+	fake.getDeployedDirectorManifestMutex.RLock()
+	defer fake.getDeployedDirectorManifestMutex.RUnlock()
+	return len(fake.getDeployedDirectorManifestArgsForCall)
+}
+
+func (fake *DiffService) GetDeployedDirectorManifestCalls(stub func() (string, error)) {
+	fake.getDeployedDirectorManifestMutex.Lock()
+	defer fake.getDeployedDirectorManifestMutex.Unlock()
+	fake.GetDeployedDirectorManifestStub = stub
+}
+
+func (fake *DiffService) GetDeployedDirectorManifestReturns(result1 string, result2 error) {
+	fake.getDeployedDirectorManifestMutex.Lock()
+	defer fake.getDeployedDirectorManifestMutex.Unlock()
+	fake.GetDeployedDirectorManifestStub = nil
+	fake.getDeployedDirectorManifestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) GetDeployedDirectorManifestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getDeployedDirectorManifestMutex.Lock()
+	defer fake.getDeployedDirectorManifestMutex.Unlock()
+	fake.GetDeployedDirectorManifestStub = nil
+	if fake.getDeployedDirectorManifestReturnsOnCall == nil {
+		fake.getDeployedDirectorManifestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getDeployedDirectorManifestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) GetDeployedProductManifest(arg1 string) (string, error) {
+	fake.getDeployedProductManifestMutex.Lock()
+	ret, specificReturn := fake.getDeployedProductManifestReturnsOnCall[len(fake.getDeployedProductManifestArgsForCall)]
+	fake.getDeployedProductManifestArgsForCall = append(fake.getDeployedProductManifestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetDeployedProductManifest", []interface{}{arg1})
+	fake.getDeployedProductManifestMutex.Unlock()
+	if fake.GetDeployedProductManifestStub != nil {
+		return fake.GetDeployedProductManifestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getDeployedProductManifestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DiffService) GetDeployedProductManifestCallCount() int {
+	fake.getDeployedProductManifestMutex.RLock()
+	defer fake.getDeployedProductManifestMutex.RUnlock()
+	return len(fake.getDeployedProductManifestArgsForCall)
+}
+
+func (fake *DiffService) GetDeployedProductManifestCalls(stub func(string) (string, error)) {
+	fake.getDeployedProductManifestMutex.Lock()
+	defer fake.getDeployedProductManifestMutex.Unlock()
+	fake.GetDeployedProductManifestStub = stub
+}
+
+func (fake *DiffService) GetDeployedProductManifestArgsForCall(i int) string {
+	fake.getDeployedProductManifestMutex.RLock()
+	defer fake.getDeployedProductManifestMutex.RUnlock()
+	argsForCall := fake.getDeployedProductManifestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *DiffService) GetDeployedProductManifestReturns(result1 string, result2 error) {
+	fake.getDeployedProductManifestMutex.Lock()
+	defer fake.getDeployedProductManifestMutex.Unlock()
+	fake.GetDeployedProductManifestStub = nil
+	fake.getDeployedProductManifestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) GetDeployedProductManifestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getDeployedProductManifestMutex.Lock()
+	defer fake.getDeployedProductManifestMutex.Unlock()
+	fake.GetDeployedProductManifestStub = nil
+	if fake.getDeployedProductManifestReturnsOnCall == nil {
+		fake.getDeployedProductManifestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getDeployedProductManifestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) GetStagedDirectorManifest() (string, error) {
+	fake.getStagedDirectorManifestMutex.Lock()
+	ret, specificReturn := fake.getStagedDirectorManifestReturnsOnCall[len(fake.getStagedDirectorManifestArgsForCall)]
+	fake.getStagedDirectorManifestArgsForCall = append(fake.getStagedDirectorManifestArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetStagedDirectorManifest", []interface{}{})
+	fake.getStagedDirectorManifestMutex.Unlock()
+	if fake.GetStagedDirectorManifestStub != nil {
+		return fake.GetStagedDirectorManifestStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStagedDirectorManifestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DiffService) GetStagedDirectorManifestCallCount() int {
+	fake.getStagedDirectorManifestMutex.RLock()
+	defer fake.getStagedDirectorManifestMutex.RUnlock()
+	return len(fake.getStagedDirectorManifestArgsForCall)
+}
+
+func (fake *DiffService) GetStagedDirectorManifestCalls(stub func() (string, error)) {
+	fake.getStagedDirectorManifestMutex.Lock()
+	defer fake.getStagedDirectorManifestMutex.Unlock()
+	fake.GetStagedDirectorManifestStub = stub
+}
+
+func (fake *DiffService) GetStagedDirectorManifestReturns(result1 string, result2 error) {
+	fake.getStagedDirectorManifestMutex.Lock()
+	defer fake.getStagedDirectorManifestMutex.Unlock()
+	fake.GetStagedDirectorManifestStub = nil
+	fake.getStagedDirectorManifestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) GetStagedDirectorManifestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getStagedDirectorManifestMutex.Lock()
+	defer fake.getStagedDirectorManifestMutex.Unlock()
+	fake.GetStagedDirectorManifestStub = nil
+	if fake.getStagedDirectorManifestReturnsOnCall == nil {
+		fake.getStagedDirectorManifestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getStagedDirectorManifestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) GetStagedProductManifest(arg1 string) (string, error) {
+	fake.getStagedProductManifestMutex.Lock()
+	ret, specificReturn := fake.getStagedProductManifestReturnsOnCall[len(fake.getStagedProductManifestArgsForCall)]
+	fake.getStagedProductManifestArgsForCall = append(fake.getStagedProductManifestArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetStagedProductManifest", []interface{}{arg1})
+	fake.getStagedProductManifestMutex.Unlock()
+	if fake.GetStagedProductManifestStub != nil {
+		return fake.GetStagedProductManifestStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStagedProductManifestReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DiffService) GetStagedProductManifestCallCount() int {
+	fake.getStagedProductManifestMutex.RLock()
+	defer fake.getStagedProductManifestMutex.RUnlock()
+	return len(fake.getStagedProductManifestArgsForCall)
+}
+
+func (fake *DiffService) GetStagedProductManifestCalls(stub func(string) (string, error)) {
+	fake.getStagedProductManifestMutex.Lock()
+	defer fake.getStagedProductManifestMutex.Unlock()
+	fake.GetStagedProductManifestStub = stub
+}
+
+func (fake *DiffService) GetStagedProductManifestArgsForCall(i int) string {
+	fake.getStagedProductManifestMutex.RLock()
+	defer fake.getStagedProductManifestMutex.RUnlock()
+	argsForCall := fake.getStagedProductManifestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *DiffService) GetStagedProductManifestReturns(result1 string, result2 error) {
+	fake.getStagedProductManifestMutex.Lock()
+	defer fake.getStagedProductManifestMutex.Unlock()
+	fake.GetStagedProductManifestStub = nil
+	fake.getStagedProductManifestReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) GetStagedProductManifestReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getStagedProductManifestMutex.Lock()
+	defer fake.getStagedProductManifestMutex.Unlock()
+	fake.GetStagedProductManifestStub = nil
+	if fake.getStagedProductManifestReturnsOnCall == nil {
+		fake.getStagedProductManifestReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getStagedProductManifestReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) ListDeployedProducts() ([]api.DeployedProductOutput, error) {
+	fake.listDeployedProductsMutex.Lock()
+	ret, specificReturn := fake.listDeployedProductsReturnsOnCall[len(fake.listDeployedProductsArgsForCall)]
+	fake.listDeployedProductsArgsForCall = append(fake.listDeployedProductsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListDeployedProducts", []interface{}{})
+	fake.listDeployedProductsMutex.Unlock()
+	if fake.ListDeployedProductsStub != nil {
+		return fake.ListDeployedProductsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listDeployedProductsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DiffService) ListDeployedProductsCallCount() int {
+	fake.listDeployedProductsMutex.RLock()
+	defer fake.listDeployedProductsMutex.RUnlock()
+	return len(fake.listDeployedProductsArgsForCall)
+}
+
+func (fake *DiffService) ListDeployedProductsCalls(stub func() ([]api.DeployedProductOutput, error)) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = stub
+}
+
+func (fake *DiffService) ListDeployedProductsReturns(result1 []api.DeployedProductOutput, result2 error) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = nil
+	fake.listDeployedProductsReturns = struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) ListDeployedProductsReturnsOnCall(i int, result1 []api.DeployedProductOutput, result2 error) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = nil
+	if fake.listDeployedProductsReturnsOnCall == nil {
+		fake.listDeployedProductsReturnsOnCall = make(map[int]struct {
+			result1 []api.DeployedProductOutput
+			result2 error
+		})
+	}
+	fake.listDeployedProductsReturnsOnCall[i] = struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) ListStagedProducts() (api.StagedProductsOutput, error) {
+	fake.listStagedProductsMutex.Lock()
+	ret, specificReturn := fake.listStagedProductsReturnsOnCall[len(fake.listStagedProductsArgsForCall)]
+	fake.listStagedProductsArgsForCall = append(fake.listStagedProductsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListStagedProducts", []interface{}{})
+	fake.listStagedProductsMutex.Unlock()
+	if fake.ListStagedProductsStub != nil {
+		return fake.ListStagedProductsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listStagedProductsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DiffService) ListStagedProductsCallCount() int {
+	fake.listStagedProductsMutex.RLock()
+	defer fake.listStagedProductsMutex.RUnlock()
+	return len(fake.listStagedProductsArgsForCall)
+}
+
+func (fake *DiffService) ListStagedProductsCalls(stub func() (api.StagedProductsOutput, error)) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = stub
+}
+
+func (fake *DiffService) ListStagedProductsReturns(result1 api.StagedProductsOutput, result2 error) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = nil
+	fake.listStagedProductsReturns = struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) ListStagedProductsReturnsOnCall(i int, result1 api.StagedProductsOutput, result2 error) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = nil
+	if fake.listStagedProductsReturnsOnCall == nil {
+		fake.listStagedProductsReturnsOnCall = make(map[int]struct {
+			result1 api.StagedProductsOutput
+			result2 error
+		})
+	}
+	fake.listStagedProductsReturnsOnCall[i] = struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DiffService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getDeployedDirectorManifestMutex.RLock()
+	defer fake.getDeployedDirectorManifestMutex.RUnlock()
+	fake.getDeployedProductManifestMutex.RLock()
+	defer fake.getDeployedProductManifestMutex.RUnlock()
+	fake.getStagedDirectorManifestMutex.RLock()
+	defer fake.getStagedDirectorManifestMutex.RUnlock()
+	fake.getStagedProductManifestMutex.RLock()
+	defer fake.getStagedProductManifestMutex.RUnlock()
+	fake.listDeployedProductsMutex.RLock()
+	defer fake.listDeployedProductsMutex.RUnlock()
+	fake.listStagedProductsMutex.RLock()
+	defer fake.listStagedProductsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *DiffService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
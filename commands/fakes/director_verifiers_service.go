@@ -0,0 +1,104 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type DirectorVerifiersService struct {
+	ListDirectorVerifiersStub        func() ([]api.Verifier, error)
+	listDirectorVerifiersMutex       sync.RWMutex
+	listDirectorVerifiersArgsForCall []struct {
+	}
+	listDirectorVerifiersReturns struct {
+		result1 []api.Verifier
+		result2 error
+	}
+	listDirectorVerifiersReturnsOnCall map[int]struct {
+		result1 []api.Verifier
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *DirectorVerifiersService) ListDirectorVerifiers() ([]api.Verifier, error) {
+	fake.listDirectorVerifiersMutex.Lock()
+	ret, specificReturn := fake.listDirectorVerifiersReturnsOnCall[len(fake.listDirectorVerifiersArgsForCall)]
+	fake.listDirectorVerifiersArgsForCall = append(fake.listDirectorVerifiersArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListDirectorVerifiers", []interface{}{})
+	fake.listDirectorVerifiersMutex.Unlock()
+	if fake.ListDirectorVerifiersStub != nil {
+		return fake.ListDirectorVerifiersStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listDirectorVerifiersReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DirectorVerifiersService) ListDirectorVerifiersCallCount() int {
+	fake.listDirectorVerifiersMutex.RLock()
+	defer fake.listDirectorVerifiersMutex.RUnlock()
+	return len(fake.listDirectorVerifiersArgsForCall)
+}
+
+func (fake *DirectorVerifiersService) ListDirectorVerifiersCalls(stub func() ([]api.Verifier, error)) {
+	fake.listDirectorVerifiersMutex.Lock()
+	defer fake.listDirectorVerifiersMutex.Unlock()
+	fake.ListDirectorVerifiersStub = stub
+}
+
+func (fake *DirectorVerifiersService) ListDirectorVerifiersReturns(result1 []api.Verifier, result2 error) {
+	fake.listDirectorVerifiersMutex.Lock()
+	defer fake.listDirectorVerifiersMutex.Unlock()
+	fake.ListDirectorVerifiersStub = nil
+	fake.listDirectorVerifiersReturns = struct {
+		result1 []api.Verifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DirectorVerifiersService) ListDirectorVerifiersReturnsOnCall(i int, result1 []api.Verifier, result2 error) {
+	fake.listDirectorVerifiersMutex.Lock()
+	defer fake.listDirectorVerifiersMutex.Unlock()
+	fake.ListDirectorVerifiersStub = nil
+	if fake.listDirectorVerifiersReturnsOnCall == nil {
+		fake.listDirectorVerifiersReturnsOnCall = make(map[int]struct {
+			result1 []api.Verifier
+			result2 error
+		})
+	}
+	fake.listDirectorVerifiersReturnsOnCall[i] = struct {
+		result1 []api.Verifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DirectorVerifiersService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listDirectorVerifiersMutex.RLock()
+	defer fake.listDirectorVerifiersMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *DirectorVerifiersService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,271 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type DisableProductVerifiersService struct {
+	DisableProductVerifiersStub        func(string, []string) error
+	disableProductVerifiersMutex       sync.RWMutex
+	disableProductVerifiersArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	disableProductVerifiersReturns struct {
+		result1 error
+	}
+	disableProductVerifiersReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetStagedProductByNameStub        func(string) (api.StagedProductsFindOutput, error)
+	getStagedProductByNameMutex       sync.RWMutex
+	getStagedProductByNameArgsForCall []struct {
+		arg1 string
+	}
+	getStagedProductByNameReturns struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}
+	getStagedProductByNameReturnsOnCall map[int]struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}
+	ListProductVerifiersStub        func(string) ([]api.Verifier, error)
+	listProductVerifiersMutex       sync.RWMutex
+	listProductVerifiersArgsForCall []struct {
+		arg1 string
+	}
+	listProductVerifiersReturns struct {
+		result1 []api.Verifier
+		result2 error
+	}
+	listProductVerifiersReturnsOnCall map[int]struct {
+		result1 []api.Verifier
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *DisableProductVerifiersService) DisableProductVerifiers(arg1 string, arg2 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.disableProductVerifiersMutex.Lock()
+	ret, specificReturn := fake.disableProductVerifiersReturnsOnCall[len(fake.disableProductVerifiersArgsForCall)]
+	fake.disableProductVerifiersArgsForCall = append(fake.disableProductVerifiersArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2Copy})
+	fake.recordInvocation("DisableProductVerifiers", []interface{}{arg1, arg2Copy})
+	fake.disableProductVerifiersMutex.Unlock()
+	if fake.DisableProductVerifiersStub != nil {
+		return fake.DisableProductVerifiersStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.disableProductVerifiersReturns
+	return fakeReturns.result1
+}
+
+func (fake *DisableProductVerifiersService) DisableProductVerifiersCallCount() int {
+	fake.disableProductVerifiersMutex.RLock()
+	defer fake.disableProductVerifiersMutex.RUnlock()
+	return len(fake.disableProductVerifiersArgsForCall)
+}
+
+func (fake *DisableProductVerifiersService) DisableProductVerifiersCalls(stub func(string, []string) error) {
+	fake.disableProductVerifiersMutex.Lock()
+	defer fake.disableProductVerifiersMutex.Unlock()
+	fake.DisableProductVerifiersStub = stub
+}
+
+func (fake *DisableProductVerifiersService) DisableProductVerifiersArgsForCall(i int) (string, []string) {
+	fake.disableProductVerifiersMutex.RLock()
+	defer fake.disableProductVerifiersMutex.RUnlock()
+	argsForCall := fake.disableProductVerifiersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *DisableProductVerifiersService) DisableProductVerifiersReturns(result1 error) {
+	fake.disableProductVerifiersMutex.Lock()
+	defer fake.disableProductVerifiersMutex.Unlock()
+	fake.DisableProductVerifiersStub = nil
+	fake.disableProductVerifiersReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *DisableProductVerifiersService) DisableProductVerifiersReturnsOnCall(i int, result1 error) {
+	fake.disableProductVerifiersMutex.Lock()
+	defer fake.disableProductVerifiersMutex.Unlock()
+	fake.DisableProductVerifiersStub = nil
+	if fake.disableProductVerifiersReturnsOnCall == nil {
+		fake.disableProductVerifiersReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.disableProductVerifiersReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *DisableProductVerifiersService) GetStagedProductByName(arg1 string) (api.StagedProductsFindOutput, error) {
+	fake.getStagedProductByNameMutex.Lock()
+	ret, specificReturn := fake.getStagedProductByNameReturnsOnCall[len(fake.getStagedProductByNameArgsForCall)]
+	fake.getStagedProductByNameArgsForCall = append(fake.getStagedProductByNameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetStagedProductByName", []interface{}{arg1})
+	fake.getStagedProductByNameMutex.Unlock()
+	if fake.GetStagedProductByNameStub != nil {
+		return fake.GetStagedProductByNameStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStagedProductByNameReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DisableProductVerifiersService) GetStagedProductByNameCallCount() int {
+	fake.getStagedProductByNameMutex.RLock()
+	defer fake.getStagedProductByNameMutex.RUnlock()
+	return len(fake.getStagedProductByNameArgsForCall)
+}
+
+func (fake *DisableProductVerifiersService) GetStagedProductByNameCalls(stub func(string) (api.StagedProductsFindOutput, error)) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = stub
+}
+
+func (fake *DisableProductVerifiersService) GetStagedProductByNameArgsForCall(i int) string {
+	fake.getStagedProductByNameMutex.RLock()
+	defer fake.getStagedProductByNameMutex.RUnlock()
+	argsForCall := fake.getStagedProductByNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *DisableProductVerifiersService) GetStagedProductByNameReturns(result1 api.StagedProductsFindOutput, result2 error) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = nil
+	fake.getStagedProductByNameReturns = struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DisableProductVerifiersService) GetStagedProductByNameReturnsOnCall(i int, result1 api.StagedProductsFindOutput, result2 error) {
+	fake.getStagedProductByNameMutex.Lock()
+	defer fake.getStagedProductByNameMutex.Unlock()
+	fake.GetStagedProductByNameStub = nil
+	if fake.getStagedProductByNameReturnsOnCall == nil {
+		fake.getStagedProductByNameReturnsOnCall = make(map[int]struct {
+			result1 api.StagedProductsFindOutput
+			result2 error
+		})
+	}
+	fake.getStagedProductByNameReturnsOnCall[i] = struct {
+		result1 api.StagedProductsFindOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DisableProductVerifiersService) ListProductVerifiers(arg1 string) ([]api.Verifier, error) {
+	fake.listProductVerifiersMutex.Lock()
+	ret, specificReturn := fake.listProductVerifiersReturnsOnCall[len(fake.listProductVerifiersArgsForCall)]
+	fake.listProductVerifiersArgsForCall = append(fake.listProductVerifiersArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListProductVerifiers", []interface{}{arg1})
+	fake.listProductVerifiersMutex.Unlock()
+	if fake.ListProductVerifiersStub != nil {
+		return fake.ListProductVerifiersStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listProductVerifiersReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DisableProductVerifiersService) ListProductVerifiersCallCount() int {
+	fake.listProductVerifiersMutex.RLock()
+	defer fake.listProductVerifiersMutex.RUnlock()
+	return len(fake.listProductVerifiersArgsForCall)
+}
+
+func (fake *DisableProductVerifiersService) ListProductVerifiersCalls(stub func(string) ([]api.Verifier, error)) {
+	fake.listProductVerifiersMutex.Lock()
+	defer fake.listProductVerifiersMutex.Unlock()
+	fake.ListProductVerifiersStub = stub
+}
+
+func (fake *DisableProductVerifiersService) ListProductVerifiersArgsForCall(i int) string {
+	fake.listProductVerifiersMutex.RLock()
+	defer fake.listProductVerifiersMutex.RUnlock()
+	argsForCall := fake.listProductVerifiersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *DisableProductVerifiersService) ListProductVerifiersReturns(result1 []api.Verifier, result2 error) {
+	fake.listProductVerifiersMutex.Lock()
+	defer fake.listProductVerifiersMutex.Unlock()
+	fake.ListProductVerifiersStub = nil
+	fake.listProductVerifiersReturns = struct {
+		result1 []api.Verifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DisableProductVerifiersService) ListProductVerifiersReturnsOnCall(i int, result1 []api.Verifier, result2 error) {
+	fake.listProductVerifiersMutex.Lock()
+	defer fake.listProductVerifiersMutex.Unlock()
+	fake.ListProductVerifiersStub = nil
+	if fake.listProductVerifiersReturnsOnCall == nil {
+		fake.listProductVerifiersReturnsOnCall = make(map[int]struct {
+			result1 []api.Verifier
+			result2 error
+		})
+	}
+	fake.listProductVerifiersReturnsOnCall[i] = struct {
+		result1 []api.Verifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DisableProductVerifiersService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.disableProductVerifiersMutex.RLock()
+	defer fake.disableProductVerifiersMutex.RUnlock()
+	fake.getStagedProductByNameMutex.RLock()
+	defer fake.getStagedProductByNameMutex.RUnlock()
+	fake.listProductVerifiersMutex.RLock()
+	defer fake.listProductVerifiersMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *DisableProductVerifiersService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
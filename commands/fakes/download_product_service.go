@@ -0,0 +1,252 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/om/api"
+)
+
+type DownloadProductService struct {
+	DeleteAvailableProductsStub        func(api.DeleteAvailableProductsInput) error
+	deleteAvailableProductsMutex       sync.RWMutex
+	deleteAvailableProductsArgsForCall []struct {
+		arg1 api.DeleteAvailableProductsInput
+	}
+	deleteAvailableProductsReturns struct {
+		result1 error
+	}
+	deleteAvailableProductsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ListAvailableProductsStub        func() (api.AvailableProductsOutput, error)
+	listAvailableProductsMutex       sync.RWMutex
+	listAvailableProductsArgsForCall []struct {
+	}
+	listAvailableProductsReturns struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}
+	listAvailableProductsReturnsOnCall map[int]struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}
+	UploadAvailableProductStub        func(api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error)
+	uploadAvailableProductMutex       sync.RWMutex
+	uploadAvailableProductArgsForCall []struct {
+		arg1 api.UploadAvailableProductInput
+	}
+	uploadAvailableProductReturns struct {
+		result1 api.UploadAvailableProductOutput
+		result2 error
+	}
+	uploadAvailableProductReturnsOnCall map[int]struct {
+		result1 api.UploadAvailableProductOutput
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *DownloadProductService) DeleteAvailableProducts(arg1 api.DeleteAvailableProductsInput) error {
+	fake.deleteAvailableProductsMutex.Lock()
+	ret, specificReturn := fake.deleteAvailableProductsReturnsOnCall[len(fake.deleteAvailableProductsArgsForCall)]
+	fake.deleteAvailableProductsArgsForCall = append(fake.deleteAvailableProductsArgsForCall, struct {
+		arg1 api.DeleteAvailableProductsInput
+	}{arg1})
+	stub := fake.DeleteAvailableProductsStub
+	fakeReturns := fake.deleteAvailableProductsReturns
+	fake.recordInvocation("DeleteAvailableProducts", []interface{}{arg1})
+	fake.deleteAvailableProductsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *DownloadProductService) DeleteAvailableProductsCallCount() int {
+	fake.deleteAvailableProductsMutex.RLock()
+	defer fake.deleteAvailableProductsMutex.RUnlock()
+	return len(fake.deleteAvailableProductsArgsForCall)
+}
+
+func (fake *DownloadProductService) DeleteAvailableProductsCalls(stub func(api.DeleteAvailableProductsInput) error) {
+	fake.deleteAvailableProductsMutex.Lock()
+	defer fake.deleteAvailableProductsMutex.Unlock()
+	fake.DeleteAvailableProductsStub = stub
+}
+
+func (fake *DownloadProductService) DeleteAvailableProductsArgsForCall(i int) api.DeleteAvailableProductsInput {
+	fake.deleteAvailableProductsMutex.RLock()
+	defer fake.deleteAvailableProductsMutex.RUnlock()
+	argsForCall := fake.deleteAvailableProductsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *DownloadProductService) DeleteAvailableProductsReturns(result1 error) {
+	fake.deleteAvailableProductsMutex.Lock()
+	defer fake.deleteAvailableProductsMutex.Unlock()
+	fake.DeleteAvailableProductsStub = nil
+	fake.deleteAvailableProductsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *DownloadProductService) DeleteAvailableProductsReturnsOnCall(i int, result1 error) {
+	fake.deleteAvailableProductsMutex.Lock()
+	defer fake.deleteAvailableProductsMutex.Unlock()
+	fake.DeleteAvailableProductsStub = nil
+	if fake.deleteAvailableProductsReturnsOnCall == nil {
+		fake.deleteAvailableProductsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteAvailableProductsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *DownloadProductService) ListAvailableProducts() (api.AvailableProductsOutput, error) {
+	fake.listAvailableProductsMutex.Lock()
+	ret, specificReturn := fake.listAvailableProductsReturnsOnCall[len(fake.listAvailableProductsArgsForCall)]
+	fake.listAvailableProductsArgsForCall = append(fake.listAvailableProductsArgsForCall, struct {
+	}{})
+	stub := fake.ListAvailableProductsStub
+	fakeReturns := fake.listAvailableProductsReturns
+	fake.recordInvocation("ListAvailableProducts", []interface{}{})
+	fake.listAvailableProductsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DownloadProductService) ListAvailableProductsCallCount() int {
+	fake.listAvailableProductsMutex.RLock()
+	defer fake.listAvailableProductsMutex.RUnlock()
+	return len(fake.listAvailableProductsArgsForCall)
+}
+
+func (fake *DownloadProductService) ListAvailableProductsCalls(stub func() (api.AvailableProductsOutput, error)) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = stub
+}
+
+func (fake *DownloadProductService) ListAvailableProductsReturns(result1 api.AvailableProductsOutput, result2 error) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = nil
+	fake.listAvailableProductsReturns = struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DownloadProductService) ListAvailableProductsReturnsOnCall(i int, result1 api.AvailableProductsOutput, result2 error) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = nil
+	if fake.listAvailableProductsReturnsOnCall == nil {
+		fake.listAvailableProductsReturnsOnCall = make(map[int]struct {
+			result1 api.AvailableProductsOutput
+			result2 error
+		})
+	}
+	fake.listAvailableProductsReturnsOnCall[i] = struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DownloadProductService) UploadAvailableProduct(arg1 api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error) {
+	fake.uploadAvailableProductMutex.Lock()
+	ret, specificReturn := fake.uploadAvailableProductReturnsOnCall[len(fake.uploadAvailableProductArgsForCall)]
+	fake.uploadAvailableProductArgsForCall = append(fake.uploadAvailableProductArgsForCall, struct {
+		arg1 api.UploadAvailableProductInput
+	}{arg1})
+	stub := fake.UploadAvailableProductStub
+	fakeReturns := fake.uploadAvailableProductReturns
+	fake.recordInvocation("UploadAvailableProduct", []interface{}{arg1})
+	fake.uploadAvailableProductMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *DownloadProductService) UploadAvailableProductCallCount() int {
+	fake.uploadAvailableProductMutex.RLock()
+	defer fake.uploadAvailableProductMutex.RUnlock()
+	return len(fake.uploadAvailableProductArgsForCall)
+}
+
+func (fake *DownloadProductService) UploadAvailableProductCalls(stub func(api.UploadAvailableProductInput) (api.UploadAvailableProductOutput, error)) {
+	fake.uploadAvailableProductMutex.Lock()
+	defer fake.uploadAvailableProductMutex.Unlock()
+	fake.UploadAvailableProductStub = stub
+}
+
+func (fake *DownloadProductService) UploadAvailableProductArgsForCall(i int) api.UploadAvailableProductInput {
+	fake.uploadAvailableProductMutex.RLock()
+	defer fake.uploadAvailableProductMutex.RUnlock()
+	argsForCall := fake.uploadAvailableProductArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *DownloadProductService) UploadAvailableProductReturns(result1 api.UploadAvailableProductOutput, result2 error) {
+	fake.uploadAvailableProductMutex.Lock()
+	defer fake.uploadAvailableProductMutex.Unlock()
+	fake.UploadAvailableProductStub = nil
+	fake.uploadAvailableProductReturns = struct {
+		result1 api.UploadAvailableProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DownloadProductService) UploadAvailableProductReturnsOnCall(i int, result1 api.UploadAvailableProductOutput, result2 error) {
+	fake.uploadAvailableProductMutex.Lock()
+	defer fake.uploadAvailableProductMutex.Unlock()
+	fake.UploadAvailableProductStub = nil
+	if fake.uploadAvailableProductReturnsOnCall == nil {
+		fake.uploadAvailableProductReturnsOnCall = make(map[int]struct {
+			result1 api.UploadAvailableProductOutput
+			result2 error
+		})
+	}
+	fake.uploadAvailableProductReturnsOnCall[i] = struct {
+		result1 api.UploadAvailableProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *DownloadProductService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *DownloadProductService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,113 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type ExpiringCertificatesService struct {
+	ListExpiringCertificatesStub        func(string) ([]api.ExpiringCertificate, error)
+	listExpiringCertificatesMutex       sync.RWMutex
+	listExpiringCertificatesArgsForCall []struct {
+		arg1 string
+	}
+	listExpiringCertificatesReturns struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}
+	listExpiringCertificatesReturnsOnCall map[int]struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ExpiringCertificatesService) ListExpiringCertificates(arg1 string) ([]api.ExpiringCertificate, error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	ret, specificReturn := fake.listExpiringCertificatesReturnsOnCall[len(fake.listExpiringCertificatesArgsForCall)]
+	fake.listExpiringCertificatesArgsForCall = append(fake.listExpiringCertificatesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListExpiringCertificates", []interface{}{arg1})
+	fake.listExpiringCertificatesMutex.Unlock()
+	if fake.ListExpiringCertificatesStub != nil {
+		return fake.ListExpiringCertificatesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listExpiringCertificatesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ExpiringCertificatesService) ListExpiringCertificatesCallCount() int {
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	return len(fake.listExpiringCertificatesArgsForCall)
+}
+
+func (fake *ExpiringCertificatesService) ListExpiringCertificatesCalls(stub func(string) ([]api.ExpiringCertificate, error)) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = stub
+}
+
+func (fake *ExpiringCertificatesService) ListExpiringCertificatesArgsForCall(i int) string {
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	argsForCall := fake.listExpiringCertificatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ExpiringCertificatesService) ListExpiringCertificatesReturns(result1 []api.ExpiringCertificate, result2 error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = nil
+	fake.listExpiringCertificatesReturns = struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ExpiringCertificatesService) ListExpiringCertificatesReturnsOnCall(i int, result1 []api.ExpiringCertificate, result2 error) {
+	fake.listExpiringCertificatesMutex.Lock()
+	defer fake.listExpiringCertificatesMutex.Unlock()
+	fake.ListExpiringCertificatesStub = nil
+	if fake.listExpiringCertificatesReturnsOnCall == nil {
+		fake.listExpiringCertificatesReturnsOnCall = make(map[int]struct {
+			result1 []api.ExpiringCertificate
+			result2 error
+		})
+	}
+	fake.listExpiringCertificatesReturnsOnCall[i] = struct {
+		result1 []api.ExpiringCertificate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ExpiringCertificatesService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listExpiringCertificatesMutex.RLock()
+	defer fake.listExpiringCertificatesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ExpiringCertificatesService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
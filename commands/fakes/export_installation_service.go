@@ -3,40 +3,46 @@ package fakes
 
 import (
 	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
 )
 
 type ExportInstallationService struct {
-	DownloadInstallationAssetCollectionStub        func(string) error
+	DownloadInstallationAssetCollectionStub        func(string, bool) (api.DownloadInstallationAssetCollectionOutput, error)
 	downloadInstallationAssetCollectionMutex       sync.RWMutex
 	downloadInstallationAssetCollectionArgsForCall []struct {
 		arg1 string
+		arg2 bool
 	}
 	downloadInstallationAssetCollectionReturns struct {
-		result1 error
+		result1 api.DownloadInstallationAssetCollectionOutput
+		result2 error
 	}
 	downloadInstallationAssetCollectionReturnsOnCall map[int]struct {
-		result1 error
+		result1 api.DownloadInstallationAssetCollectionOutput
+		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *ExportInstallationService) DownloadInstallationAssetCollection(arg1 string) error {
+func (fake *ExportInstallationService) DownloadInstallationAssetCollection(arg1 string, arg2 bool) (api.DownloadInstallationAssetCollectionOutput, error) {
 	fake.downloadInstallationAssetCollectionMutex.Lock()
 	ret, specificReturn := fake.downloadInstallationAssetCollectionReturnsOnCall[len(fake.downloadInstallationAssetCollectionArgsForCall)]
 	fake.downloadInstallationAssetCollectionArgsForCall = append(fake.downloadInstallationAssetCollectionArgsForCall, struct {
 		arg1 string
-	}{arg1})
-	fake.recordInvocation("DownloadInstallationAssetCollection", []interface{}{arg1})
+		arg2 bool
+	}{arg1, arg2})
+	fake.recordInvocation("DownloadInstallationAssetCollection", []interface{}{arg1, arg2})
 	fake.downloadInstallationAssetCollectionMutex.Unlock()
 	if fake.DownloadInstallationAssetCollectionStub != nil {
-		return fake.DownloadInstallationAssetCollectionStub(arg1)
+		return fake.DownloadInstallationAssetCollectionStub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
 	fakeReturns := fake.downloadInstallationAssetCollectionReturns
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *ExportInstallationService) DownloadInstallationAssetCollectionCallCount() int {
@@ -45,40 +51,43 @@ func (fake *ExportInstallationService) DownloadInstallationAssetCollectionCallCo
 	return len(fake.downloadInstallationAssetCollectionArgsForCall)
 }
 
-func (fake *ExportInstallationService) DownloadInstallationAssetCollectionCalls(stub func(string) error) {
+func (fake *ExportInstallationService) DownloadInstallationAssetCollectionCalls(stub func(string, bool) (api.DownloadInstallationAssetCollectionOutput, error)) {
 	fake.downloadInstallationAssetCollectionMutex.Lock()
 	defer fake.downloadInstallationAssetCollectionMutex.Unlock()
 	fake.DownloadInstallationAssetCollectionStub = stub
 }
 
-func (fake *ExportInstallationService) DownloadInstallationAssetCollectionArgsForCall(i int) string {
+func (fake *ExportInstallationService) DownloadInstallationAssetCollectionArgsForCall(i int) (string, bool) {
 	fake.downloadInstallationAssetCollectionMutex.RLock()
 	defer fake.downloadInstallationAssetCollectionMutex.RUnlock()
 	argsForCall := fake.downloadInstallationAssetCollectionArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *ExportInstallationService) DownloadInstallationAssetCollectionReturns(result1 error) {
+func (fake *ExportInstallationService) DownloadInstallationAssetCollectionReturns(result1 api.DownloadInstallationAssetCollectionOutput, result2 error) {
 	fake.downloadInstallationAssetCollectionMutex.Lock()
 	defer fake.downloadInstallationAssetCollectionMutex.Unlock()
 	fake.DownloadInstallationAssetCollectionStub = nil
 	fake.downloadInstallationAssetCollectionReturns = struct {
-		result1 error
-	}{result1}
+		result1 api.DownloadInstallationAssetCollectionOutput
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *ExportInstallationService) DownloadInstallationAssetCollectionReturnsOnCall(i int, result1 error) {
+func (fake *ExportInstallationService) DownloadInstallationAssetCollectionReturnsOnCall(i int, result1 api.DownloadInstallationAssetCollectionOutput, result2 error) {
 	fake.downloadInstallationAssetCollectionMutex.Lock()
 	defer fake.downloadInstallationAssetCollectionMutex.Unlock()
 	fake.DownloadInstallationAssetCollectionStub = nil
 	if fake.downloadInstallationAssetCollectionReturnsOnCall == nil {
 		fake.downloadInstallationAssetCollectionReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 api.DownloadInstallationAssetCollectionOutput
+			result2 error
 		})
 	}
 	fake.downloadInstallationAssetCollectionReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 api.DownloadInstallationAssetCollectionOutput
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *ExportInstallationService) Invocations() map[string][][]interface{} {
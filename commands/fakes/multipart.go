@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	io "io"
 	sync "sync"
 
 	formcontent "github.com/pivotal-cf/om/formcontent"
@@ -32,6 +33,20 @@ type Multipart struct {
 	addFileReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AddReaderStub        func(string, string, io.ReadCloser, int64) error
+	addReaderMutex       sync.RWMutex
+	addReaderArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 io.ReadCloser
+		arg4 int64
+	}
+	addReaderReturns struct {
+		result1 error
+	}
+	addReaderReturnsOnCall map[int]struct {
+		result1 error
+	}
 	FinalizeStub        func() formcontent.ContentSubmission
 	finalizeMutex       sync.RWMutex
 	finalizeArgsForCall []struct {
@@ -172,6 +187,69 @@ func (fake *Multipart) AddFileReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Multipart) AddReader(arg1 string, arg2 string, arg3 io.ReadCloser, arg4 int64) error {
+	fake.addReaderMutex.Lock()
+	ret, specificReturn := fake.addReaderReturnsOnCall[len(fake.addReaderArgsForCall)]
+	fake.addReaderArgsForCall = append(fake.addReaderArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 io.ReadCloser
+		arg4 int64
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("AddReader", []interface{}{arg1, arg2, arg3, arg4})
+	fake.addReaderMutex.Unlock()
+	if fake.AddReaderStub != nil {
+		return fake.AddReaderStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addReaderReturns
+	return fakeReturns.result1
+}
+
+func (fake *Multipart) AddReaderCallCount() int {
+	fake.addReaderMutex.RLock()
+	defer fake.addReaderMutex.RUnlock()
+	return len(fake.addReaderArgsForCall)
+}
+
+func (fake *Multipart) AddReaderCalls(stub func(string, string, io.ReadCloser, int64) error) {
+	fake.addReaderMutex.Lock()
+	defer fake.addReaderMutex.Unlock()
+	fake.AddReaderStub = stub
+}
+
+func (fake *Multipart) AddReaderArgsForCall(i int) (string, string, io.ReadCloser, int64) {
+	fake.addReaderMutex.RLock()
+	defer fake.addReaderMutex.RUnlock()
+	argsForCall := fake.addReaderArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *Multipart) AddReaderReturns(result1 error) {
+	fake.addReaderMutex.Lock()
+	defer fake.addReaderMutex.Unlock()
+	fake.AddReaderStub = nil
+	fake.addReaderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Multipart) AddReaderReturnsOnCall(i int, result1 error) {
+	fake.addReaderMutex.Lock()
+	defer fake.addReaderMutex.Unlock()
+	fake.AddReaderStub = nil
+	if fake.addReaderReturnsOnCall == nil {
+		fake.addReaderReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addReaderReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Multipart) Finalize() formcontent.ContentSubmission {
 	fake.finalizeMutex.Lock()
 	ret, specificReturn := fake.finalizeReturnsOnCall[len(fake.finalizeArgsForCall)]
@@ -254,6 +332,8 @@ func (fake *Multipart) Invocations() map[string][][]interface{} {
 	defer fake.addFieldMutex.RUnlock()
 	fake.addFileMutex.RLock()
 	defer fake.addFileMutex.RUnlock()
+	fake.addReaderMutex.RLock()
+	defer fake.addReaderMutex.RUnlock()
 	fake.finalizeMutex.RLock()
 	defer fake.finalizeMutex.RUnlock()
 	fake.resetMutex.RLock()
@@ -0,0 +1,251 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type PreDeployCheckService struct {
+	GetDirectorPreDeployCheckStub        func() (api.PreDeployCheck, error)
+	getDirectorPreDeployCheckMutex       sync.RWMutex
+	getDirectorPreDeployCheckArgsForCall []struct {
+	}
+	getDirectorPreDeployCheckReturns struct {
+		result1 api.PreDeployCheck
+		result2 error
+	}
+	getDirectorPreDeployCheckReturnsOnCall map[int]struct {
+		result1 api.PreDeployCheck
+		result2 error
+	}
+	GetProductPreDeployCheckStub        func(string) (api.PreDeployCheck, error)
+	getProductPreDeployCheckMutex       sync.RWMutex
+	getProductPreDeployCheckArgsForCall []struct {
+		arg1 string
+	}
+	getProductPreDeployCheckReturns struct {
+		result1 api.PreDeployCheck
+		result2 error
+	}
+	getProductPreDeployCheckReturnsOnCall map[int]struct {
+		result1 api.PreDeployCheck
+		result2 error
+	}
+	ListStagedProductsStub        func() (api.StagedProductsOutput, error)
+	listStagedProductsMutex       sync.RWMutex
+	listStagedProductsArgsForCall []struct {
+	}
+	listStagedProductsReturns struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}
+	listStagedProductsReturnsOnCall map[int]struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PreDeployCheckService) GetDirectorPreDeployCheck() (api.PreDeployCheck, error) {
+	fake.getDirectorPreDeployCheckMutex.Lock()
+	ret, specificReturn := fake.getDirectorPreDeployCheckReturnsOnCall[len(fake.getDirectorPreDeployCheckArgsForCall)]
+	fake.getDirectorPreDeployCheckArgsForCall = append(fake.getDirectorPreDeployCheckArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetDirectorPreDeployCheck", []interface{}{})
+	fake.getDirectorPreDeployCheckMutex.Unlock()
+	if fake.GetDirectorPreDeployCheckStub != nil {
+		return fake.GetDirectorPreDeployCheckStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getDirectorPreDeployCheckReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PreDeployCheckService) GetDirectorPreDeployCheckCallCount() int {
+	fake.getDirectorPreDeployCheckMutex.RLock()
+	defer fake.getDirectorPreDeployCheckMutex.RUnlock()
+	return len(fake.getDirectorPreDeployCheckArgsForCall)
+}
+
+func (fake *PreDeployCheckService) GetDirectorPreDeployCheckCalls(stub func() (api.PreDeployCheck, error)) {
+	fake.getDirectorPreDeployCheckMutex.Lock()
+	defer fake.getDirectorPreDeployCheckMutex.Unlock()
+	fake.GetDirectorPreDeployCheckStub = stub
+}
+
+func (fake *PreDeployCheckService) GetDirectorPreDeployCheckReturns(result1 api.PreDeployCheck, result2 error) {
+	fake.getDirectorPreDeployCheckMutex.Lock()
+	defer fake.getDirectorPreDeployCheckMutex.Unlock()
+	fake.GetDirectorPreDeployCheckStub = nil
+	fake.getDirectorPreDeployCheckReturns = struct {
+		result1 api.PreDeployCheck
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PreDeployCheckService) GetDirectorPreDeployCheckReturnsOnCall(i int, result1 api.PreDeployCheck, result2 error) {
+	fake.getDirectorPreDeployCheckMutex.Lock()
+	defer fake.getDirectorPreDeployCheckMutex.Unlock()
+	fake.GetDirectorPreDeployCheckStub = nil
+	if fake.getDirectorPreDeployCheckReturnsOnCall == nil {
+		fake.getDirectorPreDeployCheckReturnsOnCall = make(map[int]struct {
+			result1 api.PreDeployCheck
+			result2 error
+		})
+	}
+	fake.getDirectorPreDeployCheckReturnsOnCall[i] = struct {
+		result1 api.PreDeployCheck
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PreDeployCheckService) GetProductPreDeployCheck(arg1 string) (api.PreDeployCheck, error) {
+	fake.getProductPreDeployCheckMutex.Lock()
+	ret, specificReturn := fake.getProductPreDeployCheckReturnsOnCall[len(fake.getProductPreDeployCheckArgsForCall)]
+	fake.getProductPreDeployCheckArgsForCall = append(fake.getProductPreDeployCheckArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetProductPreDeployCheck", []interface{}{arg1})
+	fake.getProductPreDeployCheckMutex.Unlock()
+	if fake.GetProductPreDeployCheckStub != nil {
+		return fake.GetProductPreDeployCheckStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getProductPreDeployCheckReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PreDeployCheckService) GetProductPreDeployCheckCallCount() int {
+	fake.getProductPreDeployCheckMutex.RLock()
+	defer fake.getProductPreDeployCheckMutex.RUnlock()
+	return len(fake.getProductPreDeployCheckArgsForCall)
+}
+
+func (fake *PreDeployCheckService) GetProductPreDeployCheckCalls(stub func(string) (api.PreDeployCheck, error)) {
+	fake.getProductPreDeployCheckMutex.Lock()
+	defer fake.getProductPreDeployCheckMutex.Unlock()
+	fake.GetProductPreDeployCheckStub = stub
+}
+
+func (fake *PreDeployCheckService) GetProductPreDeployCheckArgsForCall(i int) string {
+	fake.getProductPreDeployCheckMutex.RLock()
+	defer fake.getProductPreDeployCheckMutex.RUnlock()
+	argsForCall := fake.getProductPreDeployCheckArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PreDeployCheckService) GetProductPreDeployCheckReturns(result1 api.PreDeployCheck, result2 error) {
+	fake.getProductPreDeployCheckMutex.Lock()
+	defer fake.getProductPreDeployCheckMutex.Unlock()
+	fake.GetProductPreDeployCheckStub = nil
+	fake.getProductPreDeployCheckReturns = struct {
+		result1 api.PreDeployCheck
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PreDeployCheckService) GetProductPreDeployCheckReturnsOnCall(i int, result1 api.PreDeployCheck, result2 error) {
+	fake.getProductPreDeployCheckMutex.Lock()
+	defer fake.getProductPreDeployCheckMutex.Unlock()
+	fake.GetProductPreDeployCheckStub = nil
+	if fake.getProductPreDeployCheckReturnsOnCall == nil {
+		fake.getProductPreDeployCheckReturnsOnCall = make(map[int]struct {
+			result1 api.PreDeployCheck
+			result2 error
+		})
+	}
+	fake.getProductPreDeployCheckReturnsOnCall[i] = struct {
+		result1 api.PreDeployCheck
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PreDeployCheckService) ListStagedProducts() (api.StagedProductsOutput, error) {
+	fake.listStagedProductsMutex.Lock()
+	ret, specificReturn := fake.listStagedProductsReturnsOnCall[len(fake.listStagedProductsArgsForCall)]
+	fake.listStagedProductsArgsForCall = append(fake.listStagedProductsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListStagedProducts", []interface{}{})
+	fake.listStagedProductsMutex.Unlock()
+	if fake.ListStagedProductsStub != nil {
+		return fake.ListStagedProductsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listStagedProductsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PreDeployCheckService) ListStagedProductsCallCount() int {
+	fake.listStagedProductsMutex.RLock()
+	defer fake.listStagedProductsMutex.RUnlock()
+	return len(fake.listStagedProductsArgsForCall)
+}
+
+func (fake *PreDeployCheckService) ListStagedProductsCalls(stub func() (api.StagedProductsOutput, error)) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = stub
+}
+
+func (fake *PreDeployCheckService) ListStagedProductsReturns(result1 api.StagedProductsOutput, result2 error) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = nil
+	fake.listStagedProductsReturns = struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PreDeployCheckService) ListStagedProductsReturnsOnCall(i int, result1 api.StagedProductsOutput, result2 error) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = nil
+	if fake.listStagedProductsReturnsOnCall == nil {
+		fake.listStagedProductsReturnsOnCall = make(map[int]struct {
+			result1 api.StagedProductsOutput
+			result2 error
+		})
+	}
+	fake.listStagedProductsReturnsOnCall[i] = struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PreDeployCheckService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getDirectorPreDeployCheckMutex.RLock()
+	defer fake.getDirectorPreDeployCheckMutex.RUnlock()
+	fake.getProductPreDeployCheckMutex.RLock()
+	defer fake.getProductPreDeployCheckMutex.RUnlock()
+	fake.listStagedProductsMutex.RLock()
+	defer fake.listStagedProductsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PreDeployCheckService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,102 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+)
+
+type RevertStagedChangesService struct {
+	RevertStagedChangesStub        func() (bool, error)
+	revertStagedChangesMutex       sync.RWMutex
+	revertStagedChangesArgsForCall []struct {
+	}
+	revertStagedChangesReturns struct {
+		result1 bool
+		result2 error
+	}
+	revertStagedChangesReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RevertStagedChangesService) RevertStagedChanges() (bool, error) {
+	fake.revertStagedChangesMutex.Lock()
+	ret, specificReturn := fake.revertStagedChangesReturnsOnCall[len(fake.revertStagedChangesArgsForCall)]
+	fake.revertStagedChangesArgsForCall = append(fake.revertStagedChangesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("RevertStagedChanges", []interface{}{})
+	fake.revertStagedChangesMutex.Unlock()
+	if fake.RevertStagedChangesStub != nil {
+		return fake.RevertStagedChangesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.revertStagedChangesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RevertStagedChangesService) RevertStagedChangesCallCount() int {
+	fake.revertStagedChangesMutex.RLock()
+	defer fake.revertStagedChangesMutex.RUnlock()
+	return len(fake.revertStagedChangesArgsForCall)
+}
+
+func (fake *RevertStagedChangesService) RevertStagedChangesCalls(stub func() (bool, error)) {
+	fake.revertStagedChangesMutex.Lock()
+	defer fake.revertStagedChangesMutex.Unlock()
+	fake.RevertStagedChangesStub = stub
+}
+
+func (fake *RevertStagedChangesService) RevertStagedChangesReturns(result1 bool, result2 error) {
+	fake.revertStagedChangesMutex.Lock()
+	defer fake.revertStagedChangesMutex.Unlock()
+	fake.RevertStagedChangesStub = nil
+	fake.revertStagedChangesReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RevertStagedChangesService) RevertStagedChangesReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.revertStagedChangesMutex.Lock()
+	defer fake.revertStagedChangesMutex.Unlock()
+	fake.RevertStagedChangesStub = nil
+	if fake.revertStagedChangesReturnsOnCall == nil {
+		fake.revertStagedChangesReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.revertStagedChangesReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RevertStagedChangesService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.revertStagedChangesMutex.RLock()
+	defer fake.revertStagedChangesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RevertStagedChangesService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,461 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type RotateCertificateAuthorityService struct {
+	ActivateCertificateAuthorityStub        func(api.ActivateCertificateAuthorityInput) error
+	activateCertificateAuthorityMutex       sync.RWMutex
+	activateCertificateAuthorityArgsForCall []struct {
+		arg1 api.ActivateCertificateAuthorityInput
+	}
+	activateCertificateAuthorityReturns struct {
+		result1 error
+	}
+	activateCertificateAuthorityReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateCertificateAuthorityStub        func(api.CertificateAuthorityInput) (api.CA, error)
+	createCertificateAuthorityMutex       sync.RWMutex
+	createCertificateAuthorityArgsForCall []struct {
+		arg1 api.CertificateAuthorityInput
+	}
+	createCertificateAuthorityReturns struct {
+		result1 api.CA
+		result2 error
+	}
+	createCertificateAuthorityReturnsOnCall map[int]struct {
+		result1 api.CA
+		result2 error
+	}
+	DeleteCertificateAuthorityStub        func(api.DeleteCertificateAuthorityInput) error
+	deleteCertificateAuthorityMutex       sync.RWMutex
+	deleteCertificateAuthorityArgsForCall []struct {
+		arg1 api.DeleteCertificateAuthorityInput
+	}
+	deleteCertificateAuthorityReturns struct {
+		result1 error
+	}
+	deleteCertificateAuthorityReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GenerateCertificateAuthorityStub        func() (api.CA, error)
+	generateCertificateAuthorityMutex       sync.RWMutex
+	generateCertificateAuthorityArgsForCall []struct {
+	}
+	generateCertificateAuthorityReturns struct {
+		result1 api.CA
+		result2 error
+	}
+	generateCertificateAuthorityReturnsOnCall map[int]struct {
+		result1 api.CA
+		result2 error
+	}
+	ListCertificateAuthoritiesStub        func() (api.CertificateAuthoritiesOutput, error)
+	listCertificateAuthoritiesMutex       sync.RWMutex
+	listCertificateAuthoritiesArgsForCall []struct {
+	}
+	listCertificateAuthoritiesReturns struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}
+	listCertificateAuthoritiesReturnsOnCall map[int]struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}
+	RegenerateCertificatesStub        func() error
+	regenerateCertificatesMutex       sync.RWMutex
+	regenerateCertificatesArgsForCall []struct {
+	}
+	regenerateCertificatesReturns struct {
+		result1 error
+	}
+	regenerateCertificatesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthority(arg1 api.ActivateCertificateAuthorityInput) error {
+	fake.activateCertificateAuthorityMutex.Lock()
+	ret, specificReturn := fake.activateCertificateAuthorityReturnsOnCall[len(fake.activateCertificateAuthorityArgsForCall)]
+	fake.activateCertificateAuthorityArgsForCall = append(fake.activateCertificateAuthorityArgsForCall, struct {
+		arg1 api.ActivateCertificateAuthorityInput
+	}{arg1})
+	fake.recordInvocation("ActivateCertificateAuthority", []interface{}{arg1})
+	fake.activateCertificateAuthorityMutex.Unlock()
+	if fake.ActivateCertificateAuthorityStub != nil {
+		return fake.ActivateCertificateAuthorityStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.activateCertificateAuthorityReturns
+	return fakeReturns.result1
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityCallCount() int {
+	fake.activateCertificateAuthorityMutex.RLock()
+	defer fake.activateCertificateAuthorityMutex.RUnlock()
+	return len(fake.activateCertificateAuthorityArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityCalls(stub func(api.ActivateCertificateAuthorityInput) error) {
+	fake.activateCertificateAuthorityMutex.Lock()
+	defer fake.activateCertificateAuthorityMutex.Unlock()
+	fake.ActivateCertificateAuthorityStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityArgsForCall(i int) api.ActivateCertificateAuthorityInput {
+	fake.activateCertificateAuthorityMutex.RLock()
+	defer fake.activateCertificateAuthorityMutex.RUnlock()
+	argsForCall := fake.activateCertificateAuthorityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityReturns(result1 error) {
+	fake.activateCertificateAuthorityMutex.Lock()
+	defer fake.activateCertificateAuthorityMutex.Unlock()
+	fake.ActivateCertificateAuthorityStub = nil
+	fake.activateCertificateAuthorityReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) ActivateCertificateAuthorityReturnsOnCall(i int, result1 error) {
+	fake.activateCertificateAuthorityMutex.Lock()
+	defer fake.activateCertificateAuthorityMutex.Unlock()
+	fake.ActivateCertificateAuthorityStub = nil
+	if fake.activateCertificateAuthorityReturnsOnCall == nil {
+		fake.activateCertificateAuthorityReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.activateCertificateAuthorityReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) CreateCertificateAuthority(arg1 api.CertificateAuthorityInput) (api.CA, error) {
+	fake.createCertificateAuthorityMutex.Lock()
+	ret, specificReturn := fake.createCertificateAuthorityReturnsOnCall[len(fake.createCertificateAuthorityArgsForCall)]
+	fake.createCertificateAuthorityArgsForCall = append(fake.createCertificateAuthorityArgsForCall, struct {
+		arg1 api.CertificateAuthorityInput
+	}{arg1})
+	fake.recordInvocation("CreateCertificateAuthority", []interface{}{arg1})
+	fake.createCertificateAuthorityMutex.Unlock()
+	if fake.CreateCertificateAuthorityStub != nil {
+		return fake.CreateCertificateAuthorityStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.createCertificateAuthorityReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) CreateCertificateAuthorityCallCount() int {
+	fake.createCertificateAuthorityMutex.RLock()
+	defer fake.createCertificateAuthorityMutex.RUnlock()
+	return len(fake.createCertificateAuthorityArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) CreateCertificateAuthorityCalls(stub func(api.CertificateAuthorityInput) (api.CA, error)) {
+	fake.createCertificateAuthorityMutex.Lock()
+	defer fake.createCertificateAuthorityMutex.Unlock()
+	fake.CreateCertificateAuthorityStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) CreateCertificateAuthorityArgsForCall(i int) api.CertificateAuthorityInput {
+	fake.createCertificateAuthorityMutex.RLock()
+	defer fake.createCertificateAuthorityMutex.RUnlock()
+	argsForCall := fake.createCertificateAuthorityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RotateCertificateAuthorityService) CreateCertificateAuthorityReturns(result1 api.CA, result2 error) {
+	fake.createCertificateAuthorityMutex.Lock()
+	defer fake.createCertificateAuthorityMutex.Unlock()
+	fake.CreateCertificateAuthorityStub = nil
+	fake.createCertificateAuthorityReturns = struct {
+		result1 api.CA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) CreateCertificateAuthorityReturnsOnCall(i int, result1 api.CA, result2 error) {
+	fake.createCertificateAuthorityMutex.Lock()
+	defer fake.createCertificateAuthorityMutex.Unlock()
+	fake.CreateCertificateAuthorityStub = nil
+	if fake.createCertificateAuthorityReturnsOnCall == nil {
+		fake.createCertificateAuthorityReturnsOnCall = make(map[int]struct {
+			result1 api.CA
+			result2 error
+		})
+	}
+	fake.createCertificateAuthorityReturnsOnCall[i] = struct {
+		result1 api.CA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthority(arg1 api.DeleteCertificateAuthorityInput) error {
+	fake.deleteCertificateAuthorityMutex.Lock()
+	ret, specificReturn := fake.deleteCertificateAuthorityReturnsOnCall[len(fake.deleteCertificateAuthorityArgsForCall)]
+	fake.deleteCertificateAuthorityArgsForCall = append(fake.deleteCertificateAuthorityArgsForCall, struct {
+		arg1 api.DeleteCertificateAuthorityInput
+	}{arg1})
+	fake.recordInvocation("DeleteCertificateAuthority", []interface{}{arg1})
+	fake.deleteCertificateAuthorityMutex.Unlock()
+	if fake.DeleteCertificateAuthorityStub != nil {
+		return fake.DeleteCertificateAuthorityStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteCertificateAuthorityReturns
+	return fakeReturns.result1
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityCallCount() int {
+	fake.deleteCertificateAuthorityMutex.RLock()
+	defer fake.deleteCertificateAuthorityMutex.RUnlock()
+	return len(fake.deleteCertificateAuthorityArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityCalls(stub func(api.DeleteCertificateAuthorityInput) error) {
+	fake.deleteCertificateAuthorityMutex.Lock()
+	defer fake.deleteCertificateAuthorityMutex.Unlock()
+	fake.DeleteCertificateAuthorityStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityArgsForCall(i int) api.DeleteCertificateAuthorityInput {
+	fake.deleteCertificateAuthorityMutex.RLock()
+	defer fake.deleteCertificateAuthorityMutex.RUnlock()
+	argsForCall := fake.deleteCertificateAuthorityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityReturns(result1 error) {
+	fake.deleteCertificateAuthorityMutex.Lock()
+	defer fake.deleteCertificateAuthorityMutex.Unlock()
+	fake.DeleteCertificateAuthorityStub = nil
+	fake.deleteCertificateAuthorityReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) DeleteCertificateAuthorityReturnsOnCall(i int, result1 error) {
+	fake.deleteCertificateAuthorityMutex.Lock()
+	defer fake.deleteCertificateAuthorityMutex.Unlock()
+	fake.DeleteCertificateAuthorityStub = nil
+	if fake.deleteCertificateAuthorityReturnsOnCall == nil {
+		fake.deleteCertificateAuthorityReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteCertificateAuthorityReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthority() (api.CA, error) {
+	fake.generateCertificateAuthorityMutex.Lock()
+	ret, specificReturn := fake.generateCertificateAuthorityReturnsOnCall[len(fake.generateCertificateAuthorityArgsForCall)]
+	fake.generateCertificateAuthorityArgsForCall = append(fake.generateCertificateAuthorityArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GenerateCertificateAuthority", []interface{}{})
+	fake.generateCertificateAuthorityMutex.Unlock()
+	if fake.GenerateCertificateAuthorityStub != nil {
+		return fake.GenerateCertificateAuthorityStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.generateCertificateAuthorityReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthorityCallCount() int {
+	fake.generateCertificateAuthorityMutex.RLock()
+	defer fake.generateCertificateAuthorityMutex.RUnlock()
+	return len(fake.generateCertificateAuthorityArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthorityCalls(stub func() (api.CA, error)) {
+	fake.generateCertificateAuthorityMutex.Lock()
+	defer fake.generateCertificateAuthorityMutex.Unlock()
+	fake.GenerateCertificateAuthorityStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthorityReturns(result1 api.CA, result2 error) {
+	fake.generateCertificateAuthorityMutex.Lock()
+	defer fake.generateCertificateAuthorityMutex.Unlock()
+	fake.GenerateCertificateAuthorityStub = nil
+	fake.generateCertificateAuthorityReturns = struct {
+		result1 api.CA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) GenerateCertificateAuthorityReturnsOnCall(i int, result1 api.CA, result2 error) {
+	fake.generateCertificateAuthorityMutex.Lock()
+	defer fake.generateCertificateAuthorityMutex.Unlock()
+	fake.GenerateCertificateAuthorityStub = nil
+	if fake.generateCertificateAuthorityReturnsOnCall == nil {
+		fake.generateCertificateAuthorityReturnsOnCall = make(map[int]struct {
+			result1 api.CA
+			result2 error
+		})
+	}
+	fake.generateCertificateAuthorityReturnsOnCall[i] = struct {
+		result1 api.CA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthorities() (api.CertificateAuthoritiesOutput, error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	ret, specificReturn := fake.listCertificateAuthoritiesReturnsOnCall[len(fake.listCertificateAuthoritiesArgsForCall)]
+	fake.listCertificateAuthoritiesArgsForCall = append(fake.listCertificateAuthoritiesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListCertificateAuthorities", []interface{}{})
+	fake.listCertificateAuthoritiesMutex.Unlock()
+	if fake.ListCertificateAuthoritiesStub != nil {
+		return fake.ListCertificateAuthoritiesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listCertificateAuthoritiesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthoritiesCallCount() int {
+	fake.listCertificateAuthoritiesMutex.RLock()
+	defer fake.listCertificateAuthoritiesMutex.RUnlock()
+	return len(fake.listCertificateAuthoritiesArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthoritiesCalls(stub func() (api.CertificateAuthoritiesOutput, error)) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthoritiesReturns(result1 api.CertificateAuthoritiesOutput, result2 error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = nil
+	fake.listCertificateAuthoritiesReturns = struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) ListCertificateAuthoritiesReturnsOnCall(i int, result1 api.CertificateAuthoritiesOutput, result2 error) {
+	fake.listCertificateAuthoritiesMutex.Lock()
+	defer fake.listCertificateAuthoritiesMutex.Unlock()
+	fake.ListCertificateAuthoritiesStub = nil
+	if fake.listCertificateAuthoritiesReturnsOnCall == nil {
+		fake.listCertificateAuthoritiesReturnsOnCall = make(map[int]struct {
+			result1 api.CertificateAuthoritiesOutput
+			result2 error
+		})
+	}
+	fake.listCertificateAuthoritiesReturnsOnCall[i] = struct {
+		result1 api.CertificateAuthoritiesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificates() error {
+	fake.regenerateCertificatesMutex.Lock()
+	ret, specificReturn := fake.regenerateCertificatesReturnsOnCall[len(fake.regenerateCertificatesArgsForCall)]
+	fake.regenerateCertificatesArgsForCall = append(fake.regenerateCertificatesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("RegenerateCertificates", []interface{}{})
+	fake.regenerateCertificatesMutex.Unlock()
+	if fake.RegenerateCertificatesStub != nil {
+		return fake.RegenerateCertificatesStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.regenerateCertificatesReturns
+	return fakeReturns.result1
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificatesCallCount() int {
+	fake.regenerateCertificatesMutex.RLock()
+	defer fake.regenerateCertificatesMutex.RUnlock()
+	return len(fake.regenerateCertificatesArgsForCall)
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificatesCalls(stub func() error) {
+	fake.regenerateCertificatesMutex.Lock()
+	defer fake.regenerateCertificatesMutex.Unlock()
+	fake.RegenerateCertificatesStub = stub
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificatesReturns(result1 error) {
+	fake.regenerateCertificatesMutex.Lock()
+	defer fake.regenerateCertificatesMutex.Unlock()
+	fake.RegenerateCertificatesStub = nil
+	fake.regenerateCertificatesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) RegenerateCertificatesReturnsOnCall(i int, result1 error) {
+	fake.regenerateCertificatesMutex.Lock()
+	defer fake.regenerateCertificatesMutex.Unlock()
+	fake.RegenerateCertificatesStub = nil
+	if fake.regenerateCertificatesReturnsOnCall == nil {
+		fake.regenerateCertificatesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.regenerateCertificatesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *RotateCertificateAuthorityService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.activateCertificateAuthorityMutex.RLock()
+	defer fake.activateCertificateAuthorityMutex.RUnlock()
+	fake.createCertificateAuthorityMutex.RLock()
+	defer fake.createCertificateAuthorityMutex.RUnlock()
+	fake.deleteCertificateAuthorityMutex.RLock()
+	defer fake.deleteCertificateAuthorityMutex.RUnlock()
+	fake.generateCertificateAuthorityMutex.RLock()
+	defer fake.generateCertificateAuthorityMutex.RUnlock()
+	fake.listCertificateAuthoritiesMutex.RLock()
+	defer fake.listCertificateAuthoritiesMutex.RUnlock()
+	fake.regenerateCertificatesMutex.RLock()
+	defer fake.regenerateCertificatesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RotateCertificateAuthorityService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
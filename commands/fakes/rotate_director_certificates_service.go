@@ -0,0 +1,184 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type RotateDirectorCertificatesService struct {
+	ListCertificateRotationsStub        func() (api.CertificateRotationsOutput, error)
+	listCertificateRotationsMutex       sync.RWMutex
+	listCertificateRotationsArgsForCall []struct {
+	}
+	listCertificateRotationsReturns struct {
+		result1 api.CertificateRotationsOutput
+		result2 error
+	}
+	listCertificateRotationsReturnsOnCall map[int]struct {
+		result1 api.CertificateRotationsOutput
+		result2 error
+	}
+	StartCertificateRotationPhaseStub        func(string, string) (api.CertificateRotation, error)
+	startCertificateRotationPhaseMutex       sync.RWMutex
+	startCertificateRotationPhaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	startCertificateRotationPhaseReturns struct {
+		result1 api.CertificateRotation
+		result2 error
+	}
+	startCertificateRotationPhaseReturnsOnCall map[int]struct {
+		result1 api.CertificateRotation
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RotateDirectorCertificatesService) ListCertificateRotations() (api.CertificateRotationsOutput, error) {
+	fake.listCertificateRotationsMutex.Lock()
+	ret, specificReturn := fake.listCertificateRotationsReturnsOnCall[len(fake.listCertificateRotationsArgsForCall)]
+	fake.listCertificateRotationsArgsForCall = append(fake.listCertificateRotationsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListCertificateRotations", []interface{}{})
+	fake.listCertificateRotationsMutex.Unlock()
+	if fake.ListCertificateRotationsStub != nil {
+		return fake.ListCertificateRotationsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listCertificateRotationsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateDirectorCertificatesService) ListCertificateRotationsCallCount() int {
+	fake.listCertificateRotationsMutex.RLock()
+	defer fake.listCertificateRotationsMutex.RUnlock()
+	return len(fake.listCertificateRotationsArgsForCall)
+}
+
+func (fake *RotateDirectorCertificatesService) ListCertificateRotationsCalls(stub func() (api.CertificateRotationsOutput, error)) {
+	fake.listCertificateRotationsMutex.Lock()
+	defer fake.listCertificateRotationsMutex.Unlock()
+	fake.ListCertificateRotationsStub = stub
+}
+
+func (fake *RotateDirectorCertificatesService) ListCertificateRotationsReturns(result1 api.CertificateRotationsOutput, result2 error) {
+	fake.listCertificateRotationsMutex.Lock()
+	defer fake.listCertificateRotationsMutex.Unlock()
+	fake.ListCertificateRotationsStub = nil
+	fake.listCertificateRotationsReturns = struct {
+		result1 api.CertificateRotationsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateDirectorCertificatesService) ListCertificateRotationsReturnsOnCall(i int, result1 api.CertificateRotationsOutput, result2 error) {
+	fake.listCertificateRotationsMutex.Lock()
+	defer fake.listCertificateRotationsMutex.Unlock()
+	fake.ListCertificateRotationsStub = nil
+	if fake.listCertificateRotationsReturnsOnCall == nil {
+		fake.listCertificateRotationsReturnsOnCall = make(map[int]struct {
+			result1 api.CertificateRotationsOutput
+			result2 error
+		})
+	}
+	fake.listCertificateRotationsReturnsOnCall[i] = struct {
+		result1 api.CertificateRotationsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateDirectorCertificatesService) StartCertificateRotationPhase(arg1 string, arg2 string) (api.CertificateRotation, error) {
+	fake.startCertificateRotationPhaseMutex.Lock()
+	ret, specificReturn := fake.startCertificateRotationPhaseReturnsOnCall[len(fake.startCertificateRotationPhaseArgsForCall)]
+	fake.startCertificateRotationPhaseArgsForCall = append(fake.startCertificateRotationPhaseArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("StartCertificateRotationPhase", []interface{}{arg1, arg2})
+	fake.startCertificateRotationPhaseMutex.Unlock()
+	if fake.StartCertificateRotationPhaseStub != nil {
+		return fake.StartCertificateRotationPhaseStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.startCertificateRotationPhaseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *RotateDirectorCertificatesService) StartCertificateRotationPhaseCallCount() int {
+	fake.startCertificateRotationPhaseMutex.RLock()
+	defer fake.startCertificateRotationPhaseMutex.RUnlock()
+	return len(fake.startCertificateRotationPhaseArgsForCall)
+}
+
+func (fake *RotateDirectorCertificatesService) StartCertificateRotationPhaseCalls(stub func(string, string) (api.CertificateRotation, error)) {
+	fake.startCertificateRotationPhaseMutex.Lock()
+	defer fake.startCertificateRotationPhaseMutex.Unlock()
+	fake.StartCertificateRotationPhaseStub = stub
+}
+
+func (fake *RotateDirectorCertificatesService) StartCertificateRotationPhaseArgsForCall(i int) (string, string) {
+	fake.startCertificateRotationPhaseMutex.RLock()
+	defer fake.startCertificateRotationPhaseMutex.RUnlock()
+	argsForCall := fake.startCertificateRotationPhaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *RotateDirectorCertificatesService) StartCertificateRotationPhaseReturns(result1 api.CertificateRotation, result2 error) {
+	fake.startCertificateRotationPhaseMutex.Lock()
+	defer fake.startCertificateRotationPhaseMutex.Unlock()
+	fake.StartCertificateRotationPhaseStub = nil
+	fake.startCertificateRotationPhaseReturns = struct {
+		result1 api.CertificateRotation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateDirectorCertificatesService) StartCertificateRotationPhaseReturnsOnCall(i int, result1 api.CertificateRotation, result2 error) {
+	fake.startCertificateRotationPhaseMutex.Lock()
+	defer fake.startCertificateRotationPhaseMutex.Unlock()
+	fake.StartCertificateRotationPhaseStub = nil
+	if fake.startCertificateRotationPhaseReturnsOnCall == nil {
+		fake.startCertificateRotationPhaseReturnsOnCall = make(map[int]struct {
+			result1 api.CertificateRotation
+			result2 error
+		})
+	}
+	fake.startCertificateRotationPhaseReturnsOnCall[i] = struct {
+		result1 api.CertificateRotation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *RotateDirectorCertificatesService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listCertificateRotationsMutex.RLock()
+	defer fake.listCertificateRotationsMutex.RUnlock()
+	fake.startCertificateRotationPhaseMutex.RLock()
+	defer fake.startCertificateRotationPhaseMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RotateDirectorCertificatesService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
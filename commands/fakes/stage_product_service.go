@@ -34,6 +34,18 @@ type StageProductService struct {
 		result1 api.DiagnosticReport
 		result2 error
 	}
+	ListAvailableProductsStub        func() (api.AvailableProductsOutput, error)
+	listAvailableProductsMutex       sync.RWMutex
+	listAvailableProductsArgsForCall []struct {
+	}
+	listAvailableProductsReturns struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}
+	listAvailableProductsReturnsOnCall map[int]struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}
 	ListDeployedProductsStub        func() ([]api.DeployedProductOutput, error)
 	listDeployedProductsMutex       sync.RWMutex
 	listDeployedProductsArgsForCall []struct {
@@ -193,6 +205,61 @@ func (fake *StageProductService) GetDiagnosticReportReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *StageProductService) ListAvailableProducts() (api.AvailableProductsOutput, error) {
+	fake.listAvailableProductsMutex.Lock()
+	ret, specificReturn := fake.listAvailableProductsReturnsOnCall[len(fake.listAvailableProductsArgsForCall)]
+	fake.listAvailableProductsArgsForCall = append(fake.listAvailableProductsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListAvailableProducts", []interface{}{})
+	fake.listAvailableProductsMutex.Unlock()
+	if fake.ListAvailableProductsStub != nil {
+		return fake.ListAvailableProductsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listAvailableProductsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StageProductService) ListAvailableProductsCallCount() int {
+	fake.listAvailableProductsMutex.RLock()
+	defer fake.listAvailableProductsMutex.RUnlock()
+	return len(fake.listAvailableProductsArgsForCall)
+}
+
+func (fake *StageProductService) ListAvailableProductsCalls(stub func() (api.AvailableProductsOutput, error)) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = stub
+}
+
+func (fake *StageProductService) ListAvailableProductsReturns(result1 api.AvailableProductsOutput, result2 error) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = nil
+	fake.listAvailableProductsReturns = struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StageProductService) ListAvailableProductsReturnsOnCall(i int, result1 api.AvailableProductsOutput, result2 error) {
+	fake.listAvailableProductsMutex.Lock()
+	defer fake.listAvailableProductsMutex.Unlock()
+	fake.ListAvailableProductsStub = nil
+	if fake.listAvailableProductsReturnsOnCall == nil {
+		fake.listAvailableProductsReturnsOnCall = make(map[int]struct {
+			result1 api.AvailableProductsOutput
+			result2 error
+		})
+	}
+	fake.listAvailableProductsReturnsOnCall[i] = struct {
+		result1 api.AvailableProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *StageProductService) ListDeployedProducts() ([]api.DeployedProductOutput, error) {
 	fake.listDeployedProductsMutex.Lock()
 	ret, specificReturn := fake.listDeployedProductsReturnsOnCall[len(fake.listDeployedProductsArgsForCall)]
@@ -371,6 +438,8 @@ func (fake *StageProductService) Invocations() map[string][][]interface{} {
 	defer fake.checkProductAvailabilityMutex.RUnlock()
 	fake.getDiagnosticReportMutex.RLock()
 	defer fake.getDiagnosticReportMutex.RUnlock()
+	fake.listAvailableProductsMutex.RLock()
+	defer fake.listAvailableProductsMutex.RUnlock()
 	fake.listDeployedProductsMutex.RLock()
 	defer fake.listDeployedProductsMutex.RUnlock()
 	fake.listInstallationsMutex.RLock()
@@ -21,6 +21,43 @@ type StagedConfigService struct {
 		result1 api.GetDeployedProductCredentialOutput
 		result2 error
 	}
+	GetStagedDirectorAvailabilityZonesStub        func() (api.AvailabilityZonesOutput, error)
+	getStagedDirectorAvailabilityZonesMutex       sync.RWMutex
+	getStagedDirectorAvailabilityZonesArgsForCall []struct {
+	}
+	getStagedDirectorAvailabilityZonesReturns struct {
+		result1 api.AvailabilityZonesOutput
+		result2 error
+	}
+	getStagedDirectorAvailabilityZonesReturnsOnCall map[int]struct {
+		result1 api.AvailabilityZonesOutput
+		result2 error
+	}
+	GetStagedDirectorNetworksStub        func() (api.NetworksConfigurationOutput, error)
+	getStagedDirectorNetworksMutex       sync.RWMutex
+	getStagedDirectorNetworksArgsForCall []struct {
+	}
+	getStagedDirectorNetworksReturns struct {
+		result1 api.NetworksConfigurationOutput
+		result2 error
+	}
+	getStagedDirectorNetworksReturnsOnCall map[int]struct {
+		result1 api.NetworksConfigurationOutput
+		result2 error
+	}
+	GetStagedDirectorPropertiesStub        func(bool) (map[string]map[string]interface{}, error)
+	getStagedDirectorPropertiesMutex       sync.RWMutex
+	getStagedDirectorPropertiesArgsForCall []struct {
+		arg1 bool
+	}
+	getStagedDirectorPropertiesReturns struct {
+		result1 map[string]map[string]interface{}
+		result2 error
+	}
+	getStagedDirectorPropertiesReturnsOnCall map[int]struct {
+		result1 map[string]map[string]interface{}
+		result2 error
+	}
 	GetStagedProductByNameStub        func(string) (api.StagedProductsFindOutput, error)
 	getStagedProductByNameMutex       sync.RWMutex
 	getStagedProductByNameArgsForCall []struct {
@@ -112,6 +149,30 @@ type StagedConfigService struct {
 		result1 map[string]string
 		result2 error
 	}
+	ListStagedProductsStub        func() (api.StagedProductsOutput, error)
+	listStagedProductsMutex       sync.RWMutex
+	listStagedProductsArgsForCall []struct {
+	}
+	listStagedProductsReturns struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}
+	listStagedProductsReturnsOnCall map[int]struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}
+	ListStagedVMExtensionsStub        func() ([]api.VMExtension, error)
+	listStagedVMExtensionsMutex       sync.RWMutex
+	listStagedVMExtensionsArgsForCall []struct {
+	}
+	listStagedVMExtensionsReturns struct {
+		result1 []api.VMExtension
+		result2 error
+	}
+	listStagedVMExtensionsReturnsOnCall map[int]struct {
+		result1 []api.VMExtension
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -179,6 +240,179 @@ func (fake *StagedConfigService) GetDeployedProductCredentialReturnsOnCall(i int
 	}{result1, result2}
 }
 
+func (fake *StagedConfigService) GetStagedDirectorAvailabilityZones() (api.AvailabilityZonesOutput, error) {
+	fake.getStagedDirectorAvailabilityZonesMutex.Lock()
+	ret, specificReturn := fake.getStagedDirectorAvailabilityZonesReturnsOnCall[len(fake.getStagedDirectorAvailabilityZonesArgsForCall)]
+	fake.getStagedDirectorAvailabilityZonesArgsForCall = append(fake.getStagedDirectorAvailabilityZonesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetStagedDirectorAvailabilityZones", []interface{}{})
+	fake.getStagedDirectorAvailabilityZonesMutex.Unlock()
+	if fake.GetStagedDirectorAvailabilityZonesStub != nil {
+		return fake.GetStagedDirectorAvailabilityZonesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStagedDirectorAvailabilityZonesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StagedConfigService) GetStagedDirectorAvailabilityZonesCallCount() int {
+	fake.getStagedDirectorAvailabilityZonesMutex.RLock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.RUnlock()
+	return len(fake.getStagedDirectorAvailabilityZonesArgsForCall)
+}
+
+func (fake *StagedConfigService) GetStagedDirectorAvailabilityZonesCalls(stub func() (api.AvailabilityZonesOutput, error)) {
+	fake.getStagedDirectorAvailabilityZonesMutex.Lock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.Unlock()
+	fake.GetStagedDirectorAvailabilityZonesStub = stub
+}
+
+func (fake *StagedConfigService) GetStagedDirectorAvailabilityZonesReturns(result1 api.AvailabilityZonesOutput, result2 error) {
+	fake.getStagedDirectorAvailabilityZonesMutex.Lock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.Unlock()
+	fake.GetStagedDirectorAvailabilityZonesStub = nil
+	fake.getStagedDirectorAvailabilityZonesReturns = struct {
+		result1 api.AvailabilityZonesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StagedConfigService) GetStagedDirectorAvailabilityZonesReturnsOnCall(i int, result1 api.AvailabilityZonesOutput, result2 error) {
+	fake.getStagedDirectorAvailabilityZonesMutex.Lock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.Unlock()
+	fake.GetStagedDirectorAvailabilityZonesStub = nil
+	if fake.getStagedDirectorAvailabilityZonesReturnsOnCall == nil {
+		fake.getStagedDirectorAvailabilityZonesReturnsOnCall = make(map[int]struct {
+			result1 api.AvailabilityZonesOutput
+			result2 error
+		})
+	}
+	fake.getStagedDirectorAvailabilityZonesReturnsOnCall[i] = struct {
+		result1 api.AvailabilityZonesOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StagedConfigService) GetStagedDirectorNetworks() (api.NetworksConfigurationOutput, error) {
+	fake.getStagedDirectorNetworksMutex.Lock()
+	ret, specificReturn := fake.getStagedDirectorNetworksReturnsOnCall[len(fake.getStagedDirectorNetworksArgsForCall)]
+	fake.getStagedDirectorNetworksArgsForCall = append(fake.getStagedDirectorNetworksArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetStagedDirectorNetworks", []interface{}{})
+	fake.getStagedDirectorNetworksMutex.Unlock()
+	if fake.GetStagedDirectorNetworksStub != nil {
+		return fake.GetStagedDirectorNetworksStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStagedDirectorNetworksReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StagedConfigService) GetStagedDirectorNetworksCallCount() int {
+	fake.getStagedDirectorNetworksMutex.RLock()
+	defer fake.getStagedDirectorNetworksMutex.RUnlock()
+	return len(fake.getStagedDirectorNetworksArgsForCall)
+}
+
+func (fake *StagedConfigService) GetStagedDirectorNetworksCalls(stub func() (api.NetworksConfigurationOutput, error)) {
+	fake.getStagedDirectorNetworksMutex.Lock()
+	defer fake.getStagedDirectorNetworksMutex.Unlock()
+	fake.GetStagedDirectorNetworksStub = stub
+}
+
+func (fake *StagedConfigService) GetStagedDirectorNetworksReturns(result1 api.NetworksConfigurationOutput, result2 error) {
+	fake.getStagedDirectorNetworksMutex.Lock()
+	defer fake.getStagedDirectorNetworksMutex.Unlock()
+	fake.GetStagedDirectorNetworksStub = nil
+	fake.getStagedDirectorNetworksReturns = struct {
+		result1 api.NetworksConfigurationOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StagedConfigService) GetStagedDirectorNetworksReturnsOnCall(i int, result1 api.NetworksConfigurationOutput, result2 error) {
+	fake.getStagedDirectorNetworksMutex.Lock()
+	defer fake.getStagedDirectorNetworksMutex.Unlock()
+	fake.GetStagedDirectorNetworksStub = nil
+	if fake.getStagedDirectorNetworksReturnsOnCall == nil {
+		fake.getStagedDirectorNetworksReturnsOnCall = make(map[int]struct {
+			result1 api.NetworksConfigurationOutput
+			result2 error
+		})
+	}
+	fake.getStagedDirectorNetworksReturnsOnCall[i] = struct {
+		result1 api.NetworksConfigurationOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StagedConfigService) GetStagedDirectorProperties(arg1 bool) (map[string]map[string]interface{}, error) {
+	fake.getStagedDirectorPropertiesMutex.Lock()
+	ret, specificReturn := fake.getStagedDirectorPropertiesReturnsOnCall[len(fake.getStagedDirectorPropertiesArgsForCall)]
+	fake.getStagedDirectorPropertiesArgsForCall = append(fake.getStagedDirectorPropertiesArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	fake.recordInvocation("GetStagedDirectorProperties", []interface{}{arg1})
+	fake.getStagedDirectorPropertiesMutex.Unlock()
+	if fake.GetStagedDirectorPropertiesStub != nil {
+		return fake.GetStagedDirectorPropertiesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStagedDirectorPropertiesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StagedConfigService) GetStagedDirectorPropertiesCallCount() int {
+	fake.getStagedDirectorPropertiesMutex.RLock()
+	defer fake.getStagedDirectorPropertiesMutex.RUnlock()
+	return len(fake.getStagedDirectorPropertiesArgsForCall)
+}
+
+func (fake *StagedConfigService) GetStagedDirectorPropertiesCalls(stub func(bool) (map[string]map[string]interface{}, error)) {
+	fake.getStagedDirectorPropertiesMutex.Lock()
+	defer fake.getStagedDirectorPropertiesMutex.Unlock()
+	fake.GetStagedDirectorPropertiesStub = stub
+}
+
+func (fake *StagedConfigService) GetStagedDirectorPropertiesArgsForCall(i int) bool {
+	fake.getStagedDirectorPropertiesMutex.RLock()
+	defer fake.getStagedDirectorPropertiesMutex.RUnlock()
+	argsForCall := fake.getStagedDirectorPropertiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *StagedConfigService) GetStagedDirectorPropertiesReturns(result1 map[string]map[string]interface{}, result2 error) {
+	fake.getStagedDirectorPropertiesMutex.Lock()
+	defer fake.getStagedDirectorPropertiesMutex.Unlock()
+	fake.GetStagedDirectorPropertiesStub = nil
+	fake.getStagedDirectorPropertiesReturns = struct {
+		result1 map[string]map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StagedConfigService) GetStagedDirectorPropertiesReturnsOnCall(i int, result1 map[string]map[string]interface{}, result2 error) {
+	fake.getStagedDirectorPropertiesMutex.Lock()
+	defer fake.getStagedDirectorPropertiesMutex.Unlock()
+	fake.GetStagedDirectorPropertiesStub = nil
+	if fake.getStagedDirectorPropertiesReturnsOnCall == nil {
+		fake.getStagedDirectorPropertiesReturnsOnCall = make(map[int]struct {
+			result1 map[string]map[string]interface{}
+			result2 error
+		})
+	}
+	fake.getStagedDirectorPropertiesReturnsOnCall[i] = struct {
+		result1 map[string]map[string]interface{}
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *StagedConfigService) GetStagedProductByName(arg1 string) (api.StagedProductsFindOutput, error) {
 	fake.getStagedProductByNameMutex.Lock()
 	ret, specificReturn := fake.getStagedProductByNameReturnsOnCall[len(fake.getStagedProductByNameArgsForCall)]
@@ -613,11 +847,127 @@ func (fake *StagedConfigService) ListStagedProductJobsReturnsOnCall(i int, resul
 	}{result1, result2}
 }
 
+func (fake *StagedConfigService) ListStagedProducts() (api.StagedProductsOutput, error) {
+	fake.listStagedProductsMutex.Lock()
+	ret, specificReturn := fake.listStagedProductsReturnsOnCall[len(fake.listStagedProductsArgsForCall)]
+	fake.listStagedProductsArgsForCall = append(fake.listStagedProductsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListStagedProducts", []interface{}{})
+	fake.listStagedProductsMutex.Unlock()
+	if fake.ListStagedProductsStub != nil {
+		return fake.ListStagedProductsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listStagedProductsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StagedConfigService) ListStagedProductsCallCount() int {
+	fake.listStagedProductsMutex.RLock()
+	defer fake.listStagedProductsMutex.RUnlock()
+	return len(fake.listStagedProductsArgsForCall)
+}
+
+func (fake *StagedConfigService) ListStagedProductsCalls(stub func() (api.StagedProductsOutput, error)) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = stub
+}
+
+func (fake *StagedConfigService) ListStagedProductsReturns(result1 api.StagedProductsOutput, result2 error) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = nil
+	fake.listStagedProductsReturns = struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StagedConfigService) ListStagedProductsReturnsOnCall(i int, result1 api.StagedProductsOutput, result2 error) {
+	fake.listStagedProductsMutex.Lock()
+	defer fake.listStagedProductsMutex.Unlock()
+	fake.ListStagedProductsStub = nil
+	if fake.listStagedProductsReturnsOnCall == nil {
+		fake.listStagedProductsReturnsOnCall = make(map[int]struct {
+			result1 api.StagedProductsOutput
+			result2 error
+		})
+	}
+	fake.listStagedProductsReturnsOnCall[i] = struct {
+		result1 api.StagedProductsOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StagedConfigService) ListStagedVMExtensions() ([]api.VMExtension, error) {
+	fake.listStagedVMExtensionsMutex.Lock()
+	ret, specificReturn := fake.listStagedVMExtensionsReturnsOnCall[len(fake.listStagedVMExtensionsArgsForCall)]
+	fake.listStagedVMExtensionsArgsForCall = append(fake.listStagedVMExtensionsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListStagedVMExtensions", []interface{}{})
+	fake.listStagedVMExtensionsMutex.Unlock()
+	if fake.ListStagedVMExtensionsStub != nil {
+		return fake.ListStagedVMExtensionsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listStagedVMExtensionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StagedConfigService) ListStagedVMExtensionsCallCount() int {
+	fake.listStagedVMExtensionsMutex.RLock()
+	defer fake.listStagedVMExtensionsMutex.RUnlock()
+	return len(fake.listStagedVMExtensionsArgsForCall)
+}
+
+func (fake *StagedConfigService) ListStagedVMExtensionsCalls(stub func() ([]api.VMExtension, error)) {
+	fake.listStagedVMExtensionsMutex.Lock()
+	defer fake.listStagedVMExtensionsMutex.Unlock()
+	fake.ListStagedVMExtensionsStub = stub
+}
+
+func (fake *StagedConfigService) ListStagedVMExtensionsReturns(result1 []api.VMExtension, result2 error) {
+	fake.listStagedVMExtensionsMutex.Lock()
+	defer fake.listStagedVMExtensionsMutex.Unlock()
+	fake.ListStagedVMExtensionsStub = nil
+	fake.listStagedVMExtensionsReturns = struct {
+		result1 []api.VMExtension
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StagedConfigService) ListStagedVMExtensionsReturnsOnCall(i int, result1 []api.VMExtension, result2 error) {
+	fake.listStagedVMExtensionsMutex.Lock()
+	defer fake.listStagedVMExtensionsMutex.Unlock()
+	fake.ListStagedVMExtensionsStub = nil
+	if fake.listStagedVMExtensionsReturnsOnCall == nil {
+		fake.listStagedVMExtensionsReturnsOnCall = make(map[int]struct {
+			result1 []api.VMExtension
+			result2 error
+		})
+	}
+	fake.listStagedVMExtensionsReturnsOnCall[i] = struct {
+		result1 []api.VMExtension
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *StagedConfigService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getDeployedProductCredentialMutex.RLock()
 	defer fake.getDeployedProductCredentialMutex.RUnlock()
+	fake.getStagedDirectorAvailabilityZonesMutex.RLock()
+	defer fake.getStagedDirectorAvailabilityZonesMutex.RUnlock()
+	fake.getStagedDirectorNetworksMutex.RLock()
+	defer fake.getStagedDirectorNetworksMutex.RUnlock()
+	fake.getStagedDirectorPropertiesMutex.RLock()
+	defer fake.getStagedDirectorPropertiesMutex.RUnlock()
 	fake.getStagedProductByNameMutex.RLock()
 	defer fake.getStagedProductByNameMutex.RUnlock()
 	fake.getStagedProductJobResourceConfigMutex.RLock()
@@ -632,6 +982,10 @@ func (fake *StagedConfigService) Invocations() map[string][][]interface{} {
 	defer fake.listStagedProductErrandsMutex.RUnlock()
 	fake.listStagedProductJobsMutex.RLock()
 	defer fake.listStagedProductJobsMutex.RUnlock()
+	fake.listStagedProductsMutex.RLock()
+	defer fake.listStagedProductsMutex.RUnlock()
+	fake.listStagedVMExtensionsMutex.RLock()
+	defer fake.listStagedVMExtensionsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
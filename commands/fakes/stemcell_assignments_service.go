@@ -0,0 +1,104 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type StemcellAssignmentsService struct {
+	ListStemcellsStub        func() (api.ProductStemcells, error)
+	listStemcellsMutex       sync.RWMutex
+	listStemcellsArgsForCall []struct {
+	}
+	listStemcellsReturns struct {
+		result1 api.ProductStemcells
+		result2 error
+	}
+	listStemcellsReturnsOnCall map[int]struct {
+		result1 api.ProductStemcells
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *StemcellAssignmentsService) ListStemcells() (api.ProductStemcells, error) {
+	fake.listStemcellsMutex.Lock()
+	ret, specificReturn := fake.listStemcellsReturnsOnCall[len(fake.listStemcellsArgsForCall)]
+	fake.listStemcellsArgsForCall = append(fake.listStemcellsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListStemcells", []interface{}{})
+	fake.listStemcellsMutex.Unlock()
+	if fake.ListStemcellsStub != nil {
+		return fake.ListStemcellsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listStemcellsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StemcellAssignmentsService) ListStemcellsCallCount() int {
+	fake.listStemcellsMutex.RLock()
+	defer fake.listStemcellsMutex.RUnlock()
+	return len(fake.listStemcellsArgsForCall)
+}
+
+func (fake *StemcellAssignmentsService) ListStemcellsCalls(stub func() (api.ProductStemcells, error)) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = stub
+}
+
+func (fake *StemcellAssignmentsService) ListStemcellsReturns(result1 api.ProductStemcells, result2 error) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = nil
+	fake.listStemcellsReturns = struct {
+		result1 api.ProductStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StemcellAssignmentsService) ListStemcellsReturnsOnCall(i int, result1 api.ProductStemcells, result2 error) {
+	fake.listStemcellsMutex.Lock()
+	defer fake.listStemcellsMutex.Unlock()
+	fake.ListStemcellsStub = nil
+	if fake.listStemcellsReturnsOnCall == nil {
+		fake.listStemcellsReturnsOnCall = make(map[int]struct {
+			result1 api.ProductStemcells
+			result2 error
+		})
+	}
+	fake.listStemcellsReturnsOnCall[i] = struct {
+		result1 api.ProductStemcells
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StemcellAssignmentsService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listStemcellsMutex.RLock()
+	defer fake.listStemcellsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *StemcellAssignmentsService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
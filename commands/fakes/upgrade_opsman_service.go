@@ -0,0 +1,404 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+
+	api "github.com/pivotal-cf/om/api"
+)
+
+type UpgradeOpsmanService struct {
+	DownloadInstallationAssetCollectionStub        func(string, bool) (api.DownloadInstallationAssetCollectionOutput, error)
+	downloadInstallationAssetCollectionMutex       sync.RWMutex
+	downloadInstallationAssetCollectionArgsForCall []struct {
+		arg1 string
+		arg2 bool
+	}
+	downloadInstallationAssetCollectionReturns struct {
+		result1 api.DownloadInstallationAssetCollectionOutput
+		result2 error
+	}
+	downloadInstallationAssetCollectionReturnsOnCall map[int]struct {
+		result1 api.DownloadInstallationAssetCollectionOutput
+		result2 error
+	}
+	EnsureAvailabilityStub        func(api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error)
+	ensureAvailabilityMutex       sync.RWMutex
+	ensureAvailabilityArgsForCall []struct {
+		arg1 api.EnsureAvailabilityInput
+	}
+	ensureAvailabilityReturns struct {
+		result1 api.EnsureAvailabilityOutput
+		result2 error
+	}
+	ensureAvailabilityReturnsOnCall map[int]struct {
+		result1 api.EnsureAvailabilityOutput
+		result2 error
+	}
+	InfoStub        func() (api.Info, error)
+	infoMutex       sync.RWMutex
+	infoArgsForCall []struct {
+	}
+	infoReturns struct {
+		result1 api.Info
+		result2 error
+	}
+	infoReturnsOnCall map[int]struct {
+		result1 api.Info
+		result2 error
+	}
+	ListDeployedProductsStub        func() ([]api.DeployedProductOutput, error)
+	listDeployedProductsMutex       sync.RWMutex
+	listDeployedProductsArgsForCall []struct {
+	}
+	listDeployedProductsReturns struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}
+	listDeployedProductsReturnsOnCall map[int]struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}
+	UploadInstallationAssetCollectionStub        func(api.ImportInstallationInput) error
+	uploadInstallationAssetCollectionMutex       sync.RWMutex
+	uploadInstallationAssetCollectionArgsForCall []struct {
+		arg1 api.ImportInstallationInput
+	}
+	uploadInstallationAssetCollectionReturns struct {
+		result1 error
+	}
+	uploadInstallationAssetCollectionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *UpgradeOpsmanService) DownloadInstallationAssetCollection(arg1 string, arg2 bool) (api.DownloadInstallationAssetCollectionOutput, error) {
+	fake.downloadInstallationAssetCollectionMutex.Lock()
+	ret, specificReturn := fake.downloadInstallationAssetCollectionReturnsOnCall[len(fake.downloadInstallationAssetCollectionArgsForCall)]
+	fake.downloadInstallationAssetCollectionArgsForCall = append(fake.downloadInstallationAssetCollectionArgsForCall, struct {
+		arg1 string
+		arg2 bool
+	}{arg1, arg2})
+	fake.recordInvocation("DownloadInstallationAssetCollection", []interface{}{arg1, arg2})
+	fake.downloadInstallationAssetCollectionMutex.Unlock()
+	if fake.DownloadInstallationAssetCollectionStub != nil {
+		return fake.DownloadInstallationAssetCollectionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.downloadInstallationAssetCollectionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UpgradeOpsmanService) DownloadInstallationAssetCollectionCallCount() int {
+	fake.downloadInstallationAssetCollectionMutex.RLock()
+	defer fake.downloadInstallationAssetCollectionMutex.RUnlock()
+	return len(fake.downloadInstallationAssetCollectionArgsForCall)
+}
+
+func (fake *UpgradeOpsmanService) DownloadInstallationAssetCollectionCalls(stub func(string, bool) (api.DownloadInstallationAssetCollectionOutput, error)) {
+	fake.downloadInstallationAssetCollectionMutex.Lock()
+	defer fake.downloadInstallationAssetCollectionMutex.Unlock()
+	fake.DownloadInstallationAssetCollectionStub = stub
+}
+
+func (fake *UpgradeOpsmanService) DownloadInstallationAssetCollectionArgsForCall(i int) (string, bool) {
+	fake.downloadInstallationAssetCollectionMutex.RLock()
+	defer fake.downloadInstallationAssetCollectionMutex.RUnlock()
+	argsForCall := fake.downloadInstallationAssetCollectionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *UpgradeOpsmanService) DownloadInstallationAssetCollectionReturns(result1 api.DownloadInstallationAssetCollectionOutput, result2 error) {
+	fake.downloadInstallationAssetCollectionMutex.Lock()
+	defer fake.downloadInstallationAssetCollectionMutex.Unlock()
+	fake.DownloadInstallationAssetCollectionStub = nil
+	fake.downloadInstallationAssetCollectionReturns = struct {
+		result1 api.DownloadInstallationAssetCollectionOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpgradeOpsmanService) DownloadInstallationAssetCollectionReturnsOnCall(i int, result1 api.DownloadInstallationAssetCollectionOutput, result2 error) {
+	fake.downloadInstallationAssetCollectionMutex.Lock()
+	defer fake.downloadInstallationAssetCollectionMutex.Unlock()
+	fake.DownloadInstallationAssetCollectionStub = nil
+	if fake.downloadInstallationAssetCollectionReturnsOnCall == nil {
+		fake.downloadInstallationAssetCollectionReturnsOnCall = make(map[int]struct {
+			result1 api.DownloadInstallationAssetCollectionOutput
+			result2 error
+		})
+	}
+	fake.downloadInstallationAssetCollectionReturnsOnCall[i] = struct {
+		result1 api.DownloadInstallationAssetCollectionOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpgradeOpsmanService) EnsureAvailability(arg1 api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error) {
+	fake.ensureAvailabilityMutex.Lock()
+	ret, specificReturn := fake.ensureAvailabilityReturnsOnCall[len(fake.ensureAvailabilityArgsForCall)]
+	fake.ensureAvailabilityArgsForCall = append(fake.ensureAvailabilityArgsForCall, struct {
+		arg1 api.EnsureAvailabilityInput
+	}{arg1})
+	fake.recordInvocation("EnsureAvailability", []interface{}{arg1})
+	fake.ensureAvailabilityMutex.Unlock()
+	if fake.EnsureAvailabilityStub != nil {
+		return fake.EnsureAvailabilityStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.ensureAvailabilityReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UpgradeOpsmanService) EnsureAvailabilityCallCount() int {
+	fake.ensureAvailabilityMutex.RLock()
+	defer fake.ensureAvailabilityMutex.RUnlock()
+	return len(fake.ensureAvailabilityArgsForCall)
+}
+
+func (fake *UpgradeOpsmanService) EnsureAvailabilityCalls(stub func(api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error)) {
+	fake.ensureAvailabilityMutex.Lock()
+	defer fake.ensureAvailabilityMutex.Unlock()
+	fake.EnsureAvailabilityStub = stub
+}
+
+func (fake *UpgradeOpsmanService) EnsureAvailabilityArgsForCall(i int) api.EnsureAvailabilityInput {
+	fake.ensureAvailabilityMutex.RLock()
+	defer fake.ensureAvailabilityMutex.RUnlock()
+	argsForCall := fake.ensureAvailabilityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *UpgradeOpsmanService) EnsureAvailabilityReturns(result1 api.EnsureAvailabilityOutput, result2 error) {
+	fake.ensureAvailabilityMutex.Lock()
+	defer fake.ensureAvailabilityMutex.Unlock()
+	fake.EnsureAvailabilityStub = nil
+	fake.ensureAvailabilityReturns = struct {
+		result1 api.EnsureAvailabilityOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpgradeOpsmanService) EnsureAvailabilityReturnsOnCall(i int, result1 api.EnsureAvailabilityOutput, result2 error) {
+	fake.ensureAvailabilityMutex.Lock()
+	defer fake.ensureAvailabilityMutex.Unlock()
+	fake.EnsureAvailabilityStub = nil
+	if fake.ensureAvailabilityReturnsOnCall == nil {
+		fake.ensureAvailabilityReturnsOnCall = make(map[int]struct {
+			result1 api.EnsureAvailabilityOutput
+			result2 error
+		})
+	}
+	fake.ensureAvailabilityReturnsOnCall[i] = struct {
+		result1 api.EnsureAvailabilityOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpgradeOpsmanService) Info() (api.Info, error) {
+	fake.infoMutex.Lock()
+	ret, specificReturn := fake.infoReturnsOnCall[len(fake.infoArgsForCall)]
+	fake.infoArgsForCall = append(fake.infoArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Info", []interface{}{})
+	fake.infoMutex.Unlock()
+	if fake.InfoStub != nil {
+		return fake.InfoStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.infoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UpgradeOpsmanService) InfoCallCount() int {
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	return len(fake.infoArgsForCall)
+}
+
+func (fake *UpgradeOpsmanService) InfoCalls(stub func() (api.Info, error)) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = stub
+}
+
+func (fake *UpgradeOpsmanService) InfoReturns(result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	fake.infoReturns = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpgradeOpsmanService) InfoReturnsOnCall(i int, result1 api.Info, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	if fake.infoReturnsOnCall == nil {
+		fake.infoReturnsOnCall = make(map[int]struct {
+			result1 api.Info
+			result2 error
+		})
+	}
+	fake.infoReturnsOnCall[i] = struct {
+		result1 api.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpgradeOpsmanService) ListDeployedProducts() ([]api.DeployedProductOutput, error) {
+	fake.listDeployedProductsMutex.Lock()
+	ret, specificReturn := fake.listDeployedProductsReturnsOnCall[len(fake.listDeployedProductsArgsForCall)]
+	fake.listDeployedProductsArgsForCall = append(fake.listDeployedProductsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListDeployedProducts", []interface{}{})
+	fake.listDeployedProductsMutex.Unlock()
+	if fake.ListDeployedProductsStub != nil {
+		return fake.ListDeployedProductsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listDeployedProductsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *UpgradeOpsmanService) ListDeployedProductsCallCount() int {
+	fake.listDeployedProductsMutex.RLock()
+	defer fake.listDeployedProductsMutex.RUnlock()
+	return len(fake.listDeployedProductsArgsForCall)
+}
+
+func (fake *UpgradeOpsmanService) ListDeployedProductsCalls(stub func() ([]api.DeployedProductOutput, error)) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = stub
+}
+
+func (fake *UpgradeOpsmanService) ListDeployedProductsReturns(result1 []api.DeployedProductOutput, result2 error) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = nil
+	fake.listDeployedProductsReturns = struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpgradeOpsmanService) ListDeployedProductsReturnsOnCall(i int, result1 []api.DeployedProductOutput, result2 error) {
+	fake.listDeployedProductsMutex.Lock()
+	defer fake.listDeployedProductsMutex.Unlock()
+	fake.ListDeployedProductsStub = nil
+	if fake.listDeployedProductsReturnsOnCall == nil {
+		fake.listDeployedProductsReturnsOnCall = make(map[int]struct {
+			result1 []api.DeployedProductOutput
+			result2 error
+		})
+	}
+	fake.listDeployedProductsReturnsOnCall[i] = struct {
+		result1 []api.DeployedProductOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *UpgradeOpsmanService) UploadInstallationAssetCollection(arg1 api.ImportInstallationInput) error {
+	fake.uploadInstallationAssetCollectionMutex.Lock()
+	ret, specificReturn := fake.uploadInstallationAssetCollectionReturnsOnCall[len(fake.uploadInstallationAssetCollectionArgsForCall)]
+	fake.uploadInstallationAssetCollectionArgsForCall = append(fake.uploadInstallationAssetCollectionArgsForCall, struct {
+		arg1 api.ImportInstallationInput
+	}{arg1})
+	fake.recordInvocation("UploadInstallationAssetCollection", []interface{}{arg1})
+	fake.uploadInstallationAssetCollectionMutex.Unlock()
+	if fake.UploadInstallationAssetCollectionStub != nil {
+		return fake.UploadInstallationAssetCollectionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.uploadInstallationAssetCollectionReturns
+	return fakeReturns.result1
+}
+
+func (fake *UpgradeOpsmanService) UploadInstallationAssetCollectionCallCount() int {
+	fake.uploadInstallationAssetCollectionMutex.RLock()
+	defer fake.uploadInstallationAssetCollectionMutex.RUnlock()
+	return len(fake.uploadInstallationAssetCollectionArgsForCall)
+}
+
+func (fake *UpgradeOpsmanService) UploadInstallationAssetCollectionCalls(stub func(api.ImportInstallationInput) error) {
+	fake.uploadInstallationAssetCollectionMutex.Lock()
+	defer fake.uploadInstallationAssetCollectionMutex.Unlock()
+	fake.UploadInstallationAssetCollectionStub = stub
+}
+
+func (fake *UpgradeOpsmanService) UploadInstallationAssetCollectionArgsForCall(i int) api.ImportInstallationInput {
+	fake.uploadInstallationAssetCollectionMutex.RLock()
+	defer fake.uploadInstallationAssetCollectionMutex.RUnlock()
+	argsForCall := fake.uploadInstallationAssetCollectionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *UpgradeOpsmanService) UploadInstallationAssetCollectionReturns(result1 error) {
+	fake.uploadInstallationAssetCollectionMutex.Lock()
+	defer fake.uploadInstallationAssetCollectionMutex.Unlock()
+	fake.UploadInstallationAssetCollectionStub = nil
+	fake.uploadInstallationAssetCollectionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UpgradeOpsmanService) UploadInstallationAssetCollectionReturnsOnCall(i int, result1 error) {
+	fake.uploadInstallationAssetCollectionMutex.Lock()
+	defer fake.uploadInstallationAssetCollectionMutex.Unlock()
+	fake.UploadInstallationAssetCollectionStub = nil
+	if fake.uploadInstallationAssetCollectionReturnsOnCall == nil {
+		fake.uploadInstallationAssetCollectionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.uploadInstallationAssetCollectionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UpgradeOpsmanService) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.downloadInstallationAssetCollectionMutex.RLock()
+	defer fake.downloadInstallationAssetCollectionMutex.RUnlock()
+	fake.ensureAvailabilityMutex.RLock()
+	defer fake.ensureAvailabilityMutex.RUnlock()
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	fake.listDeployedProductsMutex.RLock()
+	defer fake.listDeployedProductsMutex.RUnlock()
+	fake.uploadInstallationAssetCollectionMutex.RLock()
+	defer fake.uploadInstallationAssetCollectionMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *UpgradeOpsmanService) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,184 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+)
+
+type VMManager struct {
+	CreateVMStub        func(string) (string, error)
+	createVMMutex       sync.RWMutex
+	createVMArgsForCall []struct {
+		arg1 string
+	}
+	createVMReturns struct {
+		result1 string
+		result2 error
+	}
+	createVMReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	DeleteVMStub        func(string) error
+	deleteVMMutex       sync.RWMutex
+	deleteVMArgsForCall []struct {
+		arg1 string
+	}
+	deleteVMReturns struct {
+		result1 error
+	}
+	deleteVMReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *VMManager) CreateVM(arg1 string) (string, error) {
+	fake.createVMMutex.Lock()
+	ret, specificReturn := fake.createVMReturnsOnCall[len(fake.createVMArgsForCall)]
+	fake.createVMArgsForCall = append(fake.createVMArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("CreateVM", []interface{}{arg1})
+	fake.createVMMutex.Unlock()
+	if fake.CreateVMStub != nil {
+		return fake.CreateVMStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.createVMReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *VMManager) CreateVMCallCount() int {
+	fake.createVMMutex.RLock()
+	defer fake.createVMMutex.RUnlock()
+	return len(fake.createVMArgsForCall)
+}
+
+func (fake *VMManager) CreateVMCalls(stub func(string) (string, error)) {
+	fake.createVMMutex.Lock()
+	defer fake.createVMMutex.Unlock()
+	fake.CreateVMStub = stub
+}
+
+func (fake *VMManager) CreateVMArgsForCall(i int) string {
+	fake.createVMMutex.RLock()
+	defer fake.createVMMutex.RUnlock()
+	argsForCall := fake.createVMArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *VMManager) CreateVMReturns(result1 string, result2 error) {
+	fake.createVMMutex.Lock()
+	defer fake.createVMMutex.Unlock()
+	fake.CreateVMStub = nil
+	fake.createVMReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *VMManager) CreateVMReturnsOnCall(i int, result1 string, result2 error) {
+	fake.createVMMutex.Lock()
+	defer fake.createVMMutex.Unlock()
+	fake.CreateVMStub = nil
+	if fake.createVMReturnsOnCall == nil {
+		fake.createVMReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.createVMReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *VMManager) DeleteVM(arg1 string) error {
+	fake.deleteVMMutex.Lock()
+	ret, specificReturn := fake.deleteVMReturnsOnCall[len(fake.deleteVMArgsForCall)]
+	fake.deleteVMArgsForCall = append(fake.deleteVMArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteVM", []interface{}{arg1})
+	fake.deleteVMMutex.Unlock()
+	if fake.DeleteVMStub != nil {
+		return fake.DeleteVMStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteVMReturns
+	return fakeReturns.result1
+}
+
+func (fake *VMManager) DeleteVMCallCount() int {
+	fake.deleteVMMutex.RLock()
+	defer fake.deleteVMMutex.RUnlock()
+	return len(fake.deleteVMArgsForCall)
+}
+
+func (fake *VMManager) DeleteVMCalls(stub func(string) error) {
+	fake.deleteVMMutex.Lock()
+	defer fake.deleteVMMutex.Unlock()
+	fake.DeleteVMStub = stub
+}
+
+func (fake *VMManager) DeleteVMArgsForCall(i int) string {
+	fake.deleteVMMutex.RLock()
+	defer fake.deleteVMMutex.RUnlock()
+	argsForCall := fake.deleteVMArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *VMManager) DeleteVMReturns(result1 error) {
+	fake.deleteVMMutex.Lock()
+	defer fake.deleteVMMutex.Unlock()
+	fake.DeleteVMStub = nil
+	fake.deleteVMReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *VMManager) DeleteVMReturnsOnCall(i int, result1 error) {
+	fake.deleteVMMutex.Lock()
+	defer fake.deleteVMMutex.Unlock()
+	fake.DeleteVMStub = nil
+	if fake.deleteVMReturnsOnCall == nil {
+		fake.deleteVMReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteVMReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *VMManager) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createVMMutex.RLock()
+	defer fake.createVMMutex.RUnlock()
+	fake.deleteVMMutex.RLock()
+	defer fake.deleteVMMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *VMManager) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
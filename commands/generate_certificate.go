@@ -1,18 +1,23 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/pivotal-cf/om/api"
+	"io/ioutil"
 	"strings"
 
 	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
 )
 
 type GenerateCertificate struct {
 	service generateCertificateService
 	logger  logger
 	Options struct {
-		Domains string `long:"domains" short:"d" required:"true" description:"domains to generate certificates, delimited by comma, can include wildcard domains"`
+		Domains         string `long:"domains"          short:"d" required:"true" description:"domains to generate certificates, delimited by comma, can include wildcard domains"`
+		CertificateFile string `long:"certificate-file"                           description:"file to write the certificate to, instead of printing it"`
+		PrivateKeyFile  string `long:"private-key-file"                           description:"file to write the private key to, only readable by the user, instead of printing it"`
 	}
 }
 
@@ -30,9 +35,19 @@ func (g GenerateCertificate) Execute(args []string) error {
 		return fmt.Errorf("could not parse generate-certificate flags: %s", err)
 	}
 
-	domains := strings.Split(g.Options.Domains, ",")
-	for i, domain := range domains {
-		domains[i] = strings.TrimSpace(domain)
+	if (g.Options.CertificateFile == "") != (g.Options.PrivateKeyFile == "") {
+		return errors.New("--certificate-file and --private-key-file must be given together")
+	}
+
+	var domains []string
+	for _, domain := range strings.Split(g.Options.Domains, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+
+	if len(domains) == 0 {
+		return errors.New("--domains must contain at least one domain")
 	}
 
 	output, err := g.service.GenerateCertificate(api.DomainsInput{
@@ -43,7 +58,32 @@ func (g GenerateCertificate) Execute(args []string) error {
 		return err
 	}
 
-	g.logger.Printf(output)
+	if g.Options.CertificateFile == "" {
+		g.logger.Printf("%s", output)
+		return nil
+	}
+
+	var certificate struct {
+		Certificate string `json:"certificate"`
+		Key         string `json:"key"`
+	}
+	err = json.Unmarshal([]byte(output), &certificate)
+	if err != nil {
+		return fmt.Errorf("could not parse the generated certificate: %s", err)
+	}
+
+	err = ioutil.WriteFile(g.Options.CertificateFile, []byte(certificate.Certificate), 0644)
+	if err != nil {
+		return fmt.Errorf("could not write the certificate: %s", err)
+	}
+
+	err = ioutil.WriteFile(g.Options.PrivateKeyFile, []byte(certificate.Key), 0600)
+	if err != nil {
+		return fmt.Errorf("could not write the private key: %s", err)
+	}
+
+	g.logger.Printf("Wrote the certificate to %s and the private key to %s\n", g.Options.CertificateFile, g.Options.PrivateKeyFile)
+
 	return nil
 }
 
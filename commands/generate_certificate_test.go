@@ -3,10 +3,14 @@ package commands_test
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
 )
@@ -47,7 +51,101 @@ var _ = Describe("GenerateCertificate", func() {
 			Expect(fmt.Sprintf(format, content...)).To(Equal(`some-json-response`))
 		})
 
+		It("does not send empty domains", func() {
+			err := command.Execute([]string{
+				"--domains", "*.apps.example.com,, *.sys.example.com,",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.GenerateCertificateArgsForCall(0)).To(Equal(api.DomainsInput{
+				Domains: []string{"*.apps.example.com", "*.sys.example.com"},
+			}))
+		})
+
+		It("prints the output as is, even when it contains a percent sign", func() {
+			fakeService.GenerateCertificateReturns(`{"key": "100%"}`, nil)
+
+			err := command.Execute([]string{"--domains", "*.apps.example.com"})
+			Expect(err).NotTo(HaveOccurred())
+
+			format, content := fakeLogger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, content...)).To(Equal(`{"key": "100%"}`))
+		})
+
+		Context("when the certificate and private key files are given", func() {
+			var dir string
+
+			BeforeEach(func() {
+				var err error
+				dir, err = ioutil.TempDir("", "generate-certificate")
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeService.GenerateCertificateReturns(`{"certificate": "some-certificate", "key": "some-key"}`, nil)
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(dir)
+			})
+
+			It("writes the certificate and the private key to the files instead of printing them", func() {
+				certFile := filepath.Join(dir, "cert.pem")
+				keyFile := filepath.Join(dir, "key.pem")
+
+				err := command.Execute([]string{
+					"--domains", "*.apps.example.com",
+					"--certificate-file", certFile,
+					"--private-key-file", keyFile,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(ioutil.ReadFile(certFile)).To(Equal([]byte("some-certificate")))
+				Expect(ioutil.ReadFile(keyFile)).To(Equal([]byte("some-key")))
+
+				info, err := os.Stat(keyFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+				Expect(fakeLogger.PrintfCallCount()).To(Equal(1))
+				format, content := fakeLogger.PrintfArgsForCall(0)
+				Expect(fmt.Sprintf(format, content...)).To(Equal(fmt.Sprintf("Wrote the certificate to %s and the private key to %s\n", certFile, keyFile)))
+			})
+
+			It("returns an error when only one of the files is given", func() {
+				err := command.Execute([]string{
+					"--domains", "*.apps.example.com",
+					"--certificate-file", filepath.Join(dir, "cert.pem"),
+				})
+				Expect(err).To(MatchError("--certificate-file and --private-key-file must be given together"))
+				Expect(fakeService.GenerateCertificateCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the certificate cannot be written", func() {
+				err := command.Execute([]string{
+					"--domains", "*.apps.example.com",
+					"--certificate-file", filepath.Join(dir, "missing", "cert.pem"),
+					"--private-key-file", filepath.Join(dir, "key.pem"),
+				})
+				Expect(err).To(MatchError(ContainSubstring("could not write the certificate")))
+			})
+
+			It("returns an error when the response cannot be parsed", func() {
+				fakeService.GenerateCertificateReturns(`%%%`, nil)
+
+				err := command.Execute([]string{
+					"--domains", "*.apps.example.com",
+					"--certificate-file", filepath.Join(dir, "cert.pem"),
+					"--private-key-file", filepath.Join(dir, "key.pem"),
+				})
+				Expect(err).To(MatchError(ContainSubstring("could not parse the generated certificate")))
+			})
+		})
+
 		Context("failure cases", func() {
+			It("returns an error when there are no domains", func() {
+				err := command.Execute([]string{"--domains", " , "})
+				Expect(err).To(MatchError("--domains must contain at least one domain"))
+			})
+
 			Context("when the domains flag is missing", func() {
 				It("returns an error", func() {
 					err := command.Execute([]string{})
@@ -5,22 +5,32 @@ import (
 	"fmt"
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/encryption"
+	"io/ioutil"
 	"os"
 	"strings"
 	"time"
 )
 
-const maxRetries = 3
+// defaultImportTimeout is how long import-installation waits for Ops Manager
+// to be available again when --timeout is not given.
+const defaultImportTimeout = time.Hour
 
 type ImportInstallation struct {
 	multipart  multipart
 	logger     logger
 	service    importInstallationService
 	passphrase string
+	secret     *encryption.Secret
 	Options    struct {
-		ConfigFile      string `long:"config"                short:"c"                  description:"path to yml file for configuration (keys must match the following command line flags)"`
-		Installation    string `long:"installation"          short:"i"  required:"true" description:"path to installation."`
-		PollingInterval int    `long:"polling-interval"      short:"pi"                 description:"interval (in seconds) to check OpsManager availability" default:"10"`
+		ConfigFile        string        `long:"config"                short:"c"                  description:"path to yml file for configuration (keys must match the following command line flags)"`
+		Installation      string        `long:"installation"          short:"i"  required:"true" description:"path to installation."`
+		PollingInterval   int           `long:"polling-interval"      short:"pi"                 description:"interval (in seconds) to check OpsManager availability" default:"10"`
+		Timeout           time.Duration `long:"timeout"                                          description:"stop waiting for Ops Manager to be available after this long, e.g. 30m (default: 1h)"`
+		EncryptPassphrase string        `long:"encrypt-passphrase"    env:"OM_ENCRYPT_PASSPHRASE" description:"passphrase the installation was encrypted with by export-installation --encrypt-passphrase"`
+		EncryptKeyFile    string        `long:"encrypt-key-file"                                 description:"file of the key the installation was encrypted with by export-installation --encrypt-key-file"`
+		NotifyURL         string        `long:"notify-url"                                       description:"url to POST a JSON summary of the import to when import-installation finishes"`
+		NotifyFormat      string        `long:"notify-format"                                    description:"payload posted to --notify-url (options: json,slack)" default:"json"`
 	}
 }
 
@@ -41,13 +51,25 @@ func NewImportInstallation(multipart multipart, service importInstallationServic
 
 func (ii ImportInstallation) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "This unauthenticated command attempts to import an installation to the Ops Manager targeted.",
+		Description:      "This unauthenticated command attempts to import an installation to the Ops Manager targeted, then waits for Ops Manager to restart and be available with the imported installation.",
 		ShortDescription: "imports a given installation to the Ops Manager targeted",
 		Flags:            ii.Options,
 	}
 }
 
 func (ii *ImportInstallation) Execute(args []string) error {
+	started := time.Now()
+	err := ii.execute(args)
+
+	notifyErr := notify(ii.Options.NotifyURL, ii.Options.NotifyFormat, newNotification("import-installation", started, nil, err))
+	if notifyErr != nil {
+		ii.logger.Printf("warning: %s", notifyErr)
+	}
+
+	return err
+}
+
+func (ii *ImportInstallation) execute(args []string) error {
 	err := ii.validate(args)
 	if err != nil {
 		return err
@@ -63,9 +85,22 @@ func (ii *ImportInstallation) Execute(args []string) error {
 		return nil
 	}
 
+	installation := ii.Options.Installation
+	if ii.secret != nil {
+		ii.logger.Printf("decrypting installation")
+
+		installation, err = ii.decryptInstallation()
+		if installation != "" {
+			defer os.Remove(installation)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	ii.logger.Printf("processing installation")
 
-	err = ii.multipart.AddFile("installation[file]", ii.Options.Installation)
+	err = ii.multipart.AddFile("installation[file]", installation)
 	if err != nil {
 		return fmt.Errorf("failed to load installation: %s", err)
 	}
@@ -103,25 +138,64 @@ func (ii *ImportInstallation) Execute(args []string) error {
 	return nil
 }
 
+// ensureAvailability waits for Ops Manager to be available with the imported
+// installation. The web server restarts during the import, so the errors
+// of an unreachable or unavailable Ops Manager are retried until the timeout.
 func (ii ImportInstallation) ensureAvailability() error {
-	var tryCount int
+	timeout := defaultImportTimeout
+	if ii.Options.Timeout > 0 {
+		timeout = ii.Options.Timeout
+	}
+
+	startedWaiting := time.Now()
+	status := api.EnsureAvailabilityStatusUnknown
+	restarting := false
 
 	for {
 		time.Sleep(time.Second * time.Duration(ii.Options.PollingInterval))
 		ensureAvailabilityOutput, err := ii.service.EnsureAvailability(api.EnsureAvailabilityInput{})
-		if err != nil {
-			if strings.Contains(err.Error(), "connection refused") && tryCount < maxRetries {
+		switch {
+		case err != nil && isOpsManagerRestarting(err):
+			if !restarting {
 				ii.logger.Printf("waiting for ops manager web server boots up...")
-				tryCount++
-				continue
+				restarting = true
 			}
+		case err != nil:
 			return fmt.Errorf("could not check Ops Manager Status: %s", err)
+		case ensureAvailabilityOutput.Status == api.EnsureAvailabilityStatusComplete:
+			ii.logger.Printf("Ops Manager is ready after %s", time.Since(startedWaiting).Round(time.Second))
+			return nil
+		default:
+			restarting = false
+			if ensureAvailabilityOutput.Status == api.EnsureAvailabilityStatusPending && status != api.EnsureAvailabilityStatusPending {
+				ii.logger.Printf("waiting for the authentication system of Ops Manager to start...")
+			}
+			status = ensureAvailabilityOutput.Status
 		}
-		if ensureAvailabilityOutput.Status == api.EnsureAvailabilityStatusComplete {
-			break
+
+		if time.Since(startedWaiting) >= timeout {
+			return fmt.Errorf("timed out after %s waiting for Ops Manager to be available, the import might still be in progress", timeout)
 		}
 	}
-	return nil
+}
+
+// isOpsManagerRestarting is true for the errors of an Ops Manager whose web
+// server is not listening yet, or is not reachable from its load balancer.
+func isOpsManagerRestarting(err error) bool {
+	for _, reason := range []string{
+		"connection refused",
+		"connection reset",
+		"EOF",
+		"Unexpected response code: 502",
+		"Unexpected response code: 503",
+		"Unexpected response code: 504",
+	} {
+		if strings.Contains(err.Error(), reason) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (ii *ImportInstallation) validate(args []string) error {
@@ -134,26 +208,95 @@ func (ii *ImportInstallation) validate(args []string) error {
 		return fmt.Errorf("could not parse import-installation flags: %s", err)
 	}
 
+	if err := validateNotifyFormat(ii.Options.NotifyFormat); err != nil {
+		return fmt.Errorf("could not parse import-installation flags: %s", err)
+	}
+
 	if _, err := os.Stat(ii.Options.Installation); err != nil {
 		return fmt.Errorf("file: \"%s\" does not exist. Please check the name and try again.", ii.Options.Installation)
 	}
 
-	if zipper, err := zip.OpenReader(ii.Options.Installation); err != nil {
-		return fmt.Errorf("file: \"%s\" is not a valid zip file", ii.Options.Installation)
-	} else {
-		defer zipper.Close()
-		found := false
-		for _, f := range zipper.File {
-			if f.Name == "installation.yml" {
-				found = true
-				break
-			}
+	ii.secret = nil
+	encrypted, err := isEncryptedFile(ii.Options.Installation)
+	if err != nil {
+		return fmt.Errorf("could not read file: \"%s\": %s", ii.Options.Installation, err)
+	}
+
+	if !encrypted {
+		if ii.Options.EncryptPassphrase != "" || ii.Options.EncryptKeyFile != "" {
+			return fmt.Errorf("file: \"%s\" is not encrypted, remove --encrypt-passphrase and --encrypt-key-file", ii.Options.Installation)
 		}
 
-		if !found {
-			return fmt.Errorf("file: \"%s\" is not a valid installation file. Validate that the provided installation file is correct, or run \"om export-installation\" and try again.", ii.Options.Installation)
+		return validateInstallationFile(ii.Options.Installation, ii.Options.Installation)
+	}
+
+	switch {
+	case ii.Options.EncryptPassphrase != "" && ii.Options.EncryptKeyFile != "":
+		return fmt.Errorf("--encrypt-passphrase and --encrypt-key-file cannot be used together")
+	case ii.Options.EncryptPassphrase != "":
+		ii.secret = &encryption.Secret{Passphrase: ii.Options.EncryptPassphrase}
+	case ii.Options.EncryptKeyFile != "":
+		key, err := encryption.ReadKeyFile(ii.Options.EncryptKeyFile)
+		if err != nil {
+			return fmt.Errorf("could not read the key of %s: %s", ii.Options.EncryptKeyFile, err)
 		}
+		ii.secret = &encryption.Secret{Key: key}
+	default:
+		return fmt.Errorf("file: \"%s\" is encrypted, provide the --encrypt-passphrase or the --encrypt-key-file it was exported with", ii.Options.Installation)
 	}
 
 	return nil
 }
+
+// decryptInstallation decrypts the installation in a temporary file that only
+// the user can read, and returns its path so that it is removed after the import.
+func (ii ImportInstallation) decryptInstallation() (string, error) {
+	src, err := os.Open(ii.Options.Installation)
+	if err != nil {
+		return "", fmt.Errorf("could not read file: \"%s\": %s", ii.Options.Installation, err)
+	}
+	defer src.Close()
+
+	dst, err := ioutil.TempFile("", "om-import-installation-")
+	if err != nil {
+		return "", fmt.Errorf("could not create the file to decrypt the installation to: %s", err)
+	}
+
+	err = encryption.Decrypt(dst, src, *ii.secret)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return dst.Name(), fmt.Errorf("could not decrypt file: \"%s\": %s", ii.Options.Installation, err)
+	}
+
+	return dst.Name(), validateInstallationFile(dst.Name(), ii.Options.Installation)
+}
+
+func isEncryptedFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	return encryption.IsEncrypted(file)
+}
+
+// validateInstallationFile checks that the file at path is an installation,
+// reporting the errors with the name of the file given by the user.
+func validateInstallationFile(path, name string) error {
+	zipper, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("file: \"%s\" is not a valid zip file", name)
+	}
+	defer zipper.Close()
+
+	for _, f := range zipper.File {
+		if f.Name == "installation.yml" {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("file: \"%s\" is not a valid installation file. Validate that the provided installation file is correct, or run \"om export-installation\" and try again.", name)
+}
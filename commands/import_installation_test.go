@@ -2,15 +2,19 @@ package commands_test
 
 import (
 	"archive/zip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/encryption"
 	"github.com/pivotal-cf/om/formcontent"
 
 	. "github.com/onsi/ginkgo"
@@ -114,6 +118,12 @@ var _ = Describe("ImportInstallation", func() {
 		Expect(fmt.Sprintf(format, v...)).To(Equal("waiting for import to complete, this should take only a couple minutes..."))
 
 		format, v = logger.PrintfArgsForCall(3)
+		Expect(fmt.Sprintf(format, v...)).To(Equal("waiting for the authentication system of Ops Manager to start..."))
+
+		format, v = logger.PrintfArgsForCall(4)
+		Expect(fmt.Sprintf(format, v...)).To(Equal("Ops Manager is ready after 0s"))
+
+		format, v = logger.PrintfArgsForCall(5)
 		Expect(fmt.Sprintf(format, v...)).To(Equal("finished import"))
 	})
 
@@ -136,6 +146,110 @@ var _ = Describe("ImportInstallation", func() {
 		})
 	})
 
+	Context("when a notify-url is provided", func() {
+		It("posts a summary of the import when it finishes", func() {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+			}))
+			defer server.Close()
+
+			fakeService.EnsureAvailabilityReturns(api.EnsureAvailabilityOutput{}, errors.New("some error"))
+
+			command := commands.NewImportInstallation(multipart, fakeService, "some-passphrase", logger)
+
+			err := command.Execute([]string{"--polling-interval", "0",
+				"--installation", installationFile,
+				"--notify-url", server.URL,
+			})
+			Expect(err).To(MatchError("could not check Ops Manager status: some error"))
+
+			Expect(payload).To(HaveKeyWithValue("command", "import-installation"))
+			Expect(payload).To(HaveKeyWithValue("status", "failed"))
+			Expect(payload).To(HaveKeyWithValue("errors", []interface{}{"could not check Ops Manager status: some error"}))
+		})
+	})
+
+	Context("when the installation is encrypted", func() {
+		var encryptedFile string
+
+		BeforeEach(func() {
+			src, err := os.Open(installationFile)
+			Expect(err).NotTo(HaveOccurred())
+			defer src.Close()
+
+			dst, err := ioutil.TempFile("", "")
+			Expect(err).NotTo(HaveOccurred())
+			defer dst.Close()
+			encryptedFile = dst.Name()
+
+			Expect(encryption.Encrypt(dst, src, encryption.Secret{Passphrase: "some-passphrase"})).To(Succeed())
+
+			fakeService.EnsureAvailabilityStub = func(api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error) {
+				if fakeService.EnsureAvailabilityCallCount() == 1 {
+					return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusUnstarted}, nil
+				}
+				return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil
+			}
+		})
+
+		AfterEach(func() {
+			os.Remove(encryptedFile)
+		})
+
+		It("imports the decrypted installation and removes it", func() {
+			var uploaded []byte
+			multipart.AddFileStub = func(_, path string) error {
+				var err error
+				uploaded, err = ioutil.ReadFile(path)
+				return err
+			}
+
+			command := commands.NewImportInstallation(multipart, fakeService, "some-passphrase", logger)
+
+			err := command.Execute([]string{"--polling-interval", "0",
+				"--installation", encryptedFile,
+				"--encrypt-passphrase", "some-passphrase",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			original, err := ioutil.ReadFile(installationFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uploaded).To(Equal(original))
+
+			_, file := multipart.AddFileArgsForCall(0)
+			Expect(file).NotTo(Equal(encryptedFile))
+			Expect(file).NotTo(BeAnExistingFile())
+
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("decrypting installation"))
+		})
+
+		It("returns an error when the passphrase is not given", func() {
+			command := commands.NewImportInstallation(multipart, fakeService, "some-passphrase", logger)
+
+			err := command.Execute([]string{"--installation", encryptedFile})
+			Expect(err).To(MatchError(fmt.Sprintf("file: \"%s\" is encrypted, provide the --encrypt-passphrase or the --encrypt-key-file it was exported with", encryptedFile)))
+			Expect(fakeService.EnsureAvailabilityCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the passphrase is incorrect", func() {
+			command := commands.NewImportInstallation(multipart, fakeService, "some-passphrase", logger)
+
+			err := command.Execute([]string{"--installation", encryptedFile, "--encrypt-passphrase", "other-passphrase"})
+			Expect(err).To(MatchError(fmt.Sprintf("could not decrypt file: \"%s\": could not decrypt the file: the secret is incorrect, or the file is corrupted", encryptedFile)))
+			Expect(fakeService.UploadInstallationAssetCollectionCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the installation is not encrypted", func() {
+			command := commands.NewImportInstallation(multipart, fakeService, "some-passphrase", logger)
+
+			err := command.Execute([]string{"--installation", installationFile, "--encrypt-passphrase", "some-passphrase"})
+			Expect(err).To(MatchError(fmt.Sprintf("file: \"%s\" is not encrypted, remove --encrypt-passphrase and --encrypt-key-file", installationFile)))
+		})
+	})
+
 	Context("when config file is provided", func() {
 		var configFile *os.File
 
@@ -225,7 +339,7 @@ var _ = Describe("ImportInstallation", func() {
 		})
 	})
 
-	Context("when EnsureAvailability returns 'connection refused'", func() {
+	Context("when Ops Manager is restarting after the import", func() {
 		var command *commands.ImportInstallation
 
 		BeforeEach(func() {
@@ -261,7 +375,7 @@ var _ = Describe("ImportInstallation", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeService.EnsureAvailabilityCallCount()).To(Equal(5))
 
-			Expect(logger.PrintfCallCount()).To(Equal(5))
+			Expect(logger.PrintfCallCount()).To(Equal(7))
 
 			format, v := logger.PrintfArgsForCall(0)
 			Expect(fmt.Sprintf(format, v...)).To(Equal("processing installation"))
@@ -273,15 +387,48 @@ var _ = Describe("ImportInstallation", func() {
 			Expect(fmt.Sprintf(format, v...)).To(Equal("waiting for import to complete, this should take only a couple minutes..."))
 
 			format, v = logger.PrintfArgsForCall(3)
-			Expect(fmt.Sprintf(format, v...)).To(Equal("waiting for ops manager web server boots up..."))
+			Expect(fmt.Sprintf(format, v...)).To(Equal("waiting for the authentication system of Ops Manager to start..."))
 
 			format, v = logger.PrintfArgsForCall(4)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("waiting for ops manager web server boots up..."))
+
+			format, v = logger.PrintfArgsForCall(5)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("Ops Manager is ready after 0s"))
+
+			format, v = logger.PrintfArgsForCall(6)
 			Expect(fmt.Sprintf(format, v...)).To(Equal("finished import"))
 		})
 
-		It("it only retries 3 times before giving up", func(done Done) {
+		It("retries while Ops Manager is unavailable until it is ready", func() {
+			fakeService.EnsureAvailabilityStub = func(api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error) {
+				switch count := fakeService.EnsureAvailabilityCallCount(); {
+				case count == 1:
+					return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusUnstarted}, nil
+				case count < 5:
+					return api.EnsureAvailabilityOutput{}, fmt.Errorf("could not make request round trip: dial tcp: connection refused")
+				case count < 10:
+					return api.EnsureAvailabilityOutput{}, fmt.Errorf("Unexpected response code: 502 Bad Gateway")
+				default:
+					return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil
+				}
+			}
+
+			err := command.Execute([]string{"--polling-interval", "0",
+				"--installation", installationFile,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeService.EnsureAvailabilityCallCount()).To(Equal(10))
+
+			format, v := logger.PrintfArgsForCall(3)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("waiting for ops manager web server boots up..."))
+
+			format, v = logger.PrintfArgsForCall(4)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("Ops Manager is ready after 0s"))
+		})
+
+		It("gives up when the timeout is reached", func(done Done) {
 			fakeService.EnsureAvailabilityStub = func(api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error) {
-				if fakeService.EnsureAvailabilityCallCount() > 2 {
+				if fakeService.EnsureAvailabilityCallCount() > 1 {
 					return api.EnsureAvailabilityOutput{}, fmt.Errorf("connection refused")
 				}
 
@@ -290,11 +437,27 @@ var _ = Describe("ImportInstallation", func() {
 
 			err := command.Execute([]string{"--polling-interval", "0",
 				"--installation", installationFile,
+				"--timeout", "10ms",
 			})
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("could not check Ops Manager Status:"))
+			Expect(err).To(MatchError("timed out after 10ms waiting for Ops Manager to be available, the import might still be in progress"))
 			close(done)
 		}, 1)
+
+		It("does not retry the other errors", func() {
+			fakeService.EnsureAvailabilityStub = func(api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error) {
+				if fakeService.EnsureAvailabilityCallCount() > 1 {
+					return api.EnsureAvailabilityOutput{}, fmt.Errorf("Unexpected redirect location: /somewhere")
+				}
+
+				return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusUnstarted}, nil
+			}
+
+			err := command.Execute([]string{"--polling-interval", "0",
+				"--installation", installationFile,
+			})
+			Expect(err).To(MatchError("could not check Ops Manager Status: Unexpected redirect location: /somewhere"))
+			Expect(fakeService.EnsureAvailabilityCallCount()).To(Equal(2))
+		})
 	})
 
 	Context("failure cases", func() {
@@ -416,7 +579,7 @@ var _ = Describe("ImportInstallation", func() {
 		It("returns usage information for the command", func() {
 			command := commands.NewImportInstallation(nil, nil, "", nil)
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
-				Description:      "This unauthenticated command attempts to import an installation to the Ops Manager targeted.",
+				Description:      "This unauthenticated command attempts to import an installation to the Ops Manager targeted, then waits for Ops Manager to restart and be available with the imported installation.",
 				ShortDescription: "imports a given installation to the Ops Manager targeted",
 				Flags:            command.Options,
 			}))
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
@@ -11,7 +12,8 @@ type InstallationLog struct {
 	service installationLogService
 	logger  logger
 	Options struct {
-		Id int `long:"id" required:"true" description:"id of the installation to retrieve logs for"`
+		Id         int    `long:"id"          required:"true" description:"id of the installation to retrieve logs for"`
+		OutputFile string `long:"output-file" short:"o"       description:"path to write the logs to, instead of printing them"`
 	}
 }
 
@@ -36,6 +38,17 @@ func (i InstallationLog) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
+
+	if i.Options.OutputFile != "" {
+		err = ioutil.WriteFile(i.Options.OutputFile, []byte(output.Logs), 0644)
+		if err != nil {
+			return fmt.Errorf("could not write the installation logs: %s", err)
+		}
+
+		i.logger.Printf("wrote the logs of installation %d to %s", i.Options.Id, i.Options.OutputFile)
+		return nil
+	}
+
 	i.logger.Print(output.Logs)
 	return nil
 }
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	installationLogStepPattern       = regexp.MustCompile(`^===== .* Running "(.*)"`)
+	installationLogDeploymentPattern = regexp.MustCompile(`(?:--deployment[= ]|-d )([^\s"]+)`)
+)
+
+// installationLogFilter sits in front of a logWriter and only passes on the
+// installation log lines that should be printed.
+//
+// The installation log is made of steps, each starting with a
+// `===== <time> Running "<bosh command>"` line. A step belongs to the product
+// whose deployment its bosh command targets. Any other step, such as deploying
+// the director or uploading stemcells, belongs to the director.
+type installationLogFilter struct {
+	writer       logWriter
+	products     map[string]bool
+	skipDirector bool
+	prefix       bool
+	productNames map[string]string
+
+	offset     int
+	deployment string
+	filtered   strings.Builder
+}
+
+// Flush only handles complete lines, the rest of the logs is handled
+// once its line is complete.
+func (f *installationLogFilter) Flush(logs string) error {
+	complete := strings.LastIndex(logs, "\n") + 1
+	if complete <= f.offset {
+		return nil
+	}
+
+	f.filter(logs[f.offset:complete])
+	f.offset = complete
+
+	return f.writer.Flush(f.filtered.String())
+}
+
+// FlushAll handles the rest of the logs as well, once the installation is
+// over and the last line will not be completed.
+func (f *installationLogFilter) FlushAll(logs string) error {
+	if len(logs) <= f.offset {
+		return nil
+	}
+
+	f.filter(logs[f.offset:])
+	f.offset = len(logs)
+
+	return f.writer.Flush(f.filtered.String())
+}
+
+func (f *installationLogFilter) filter(logs string) {
+	for _, line := range strings.SplitAfter(logs, "\n") {
+		if line == "" {
+			continue
+		}
+
+		if step := installationLogStepPattern.FindStringSubmatch(line); step != nil {
+			f.deployment = ""
+			if deployment := installationLogDeploymentPattern.FindStringSubmatch(step[1]); deployment != nil {
+				f.deployment = deployment[1]
+			}
+		}
+
+		if !f.shouldPrint() {
+			continue
+		}
+
+		if f.prefix {
+			f.filtered.WriteString(fmt.Sprintf("[%s] ", f.name()))
+		}
+		f.filtered.WriteString(line)
+	}
+}
+
+func (f *installationLogFilter) shouldPrint() bool {
+	if f.deployment == "" {
+		return !f.skipDirector && len(f.products) == 0
+	}
+
+	if len(f.products) == 0 {
+		return true
+	}
+
+	return f.products[f.deployment] || f.products[f.productNames[f.deployment]]
+}
+
+func (f *installationLogFilter) name() string {
+	if f.deployment == "" {
+		return "director"
+	}
+
+	if name, ok := f.productNames[f.deployment]; ok {
+		return name
+	}
+
+	return f.deployment
+}
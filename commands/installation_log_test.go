@@ -2,6 +2,10 @@ package commands_test
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -42,6 +46,49 @@ var _ = Describe("InstallationLog", func() {
 			Expect(outputLogs).To(Equal("some log output"))
 		})
 
+		Context("when an output file is provided", func() {
+			var outputDir string
+
+			BeforeEach(func() {
+				var err error
+				outputDir, err = ioutil.TempDir("", "")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(outputDir)
+			})
+
+			It("writes the logs to the file instead of printing them", func() {
+				fakeService.GetInstallationLogsReturns(api.InstallationsServiceOutput{Logs: "some log output"}, nil)
+				outputFile := filepath.Join(outputDir, "installation.log")
+
+				err := command.Execute([]string{
+					"--id", "999",
+					"--output-file", outputFile,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				contents, err := ioutil.ReadFile(outputFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("some log output"))
+
+				Expect(logger.PrintCallCount()).To(Equal(0))
+				format, content := logger.PrintfArgsForCall(0)
+				Expect(fmt.Sprintf(format, content...)).To(Equal(fmt.Sprintf("wrote the logs of installation 999 to %s", outputFile)))
+			})
+
+			It("returns an error when the file cannot be written", func() {
+				fakeService.GetInstallationLogsReturns(api.InstallationsServiceOutput{Logs: "some log output"}, nil)
+
+				err := command.Execute([]string{
+					"--id", "999",
+					"--output-file", filepath.Join(outputDir, "missing-dir", "installation.log"),
+				})
+				Expect(err).To(MatchError(ContainSubstring("could not write the installation logs:")))
+			})
+		})
+
 		Context("Failure cases", func() {
 			Context("when an unknown flag is provided", func() {
 				It("returns an error", func() {
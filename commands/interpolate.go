@@ -9,6 +9,10 @@ import (
 	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
 	"github.com/cppforlife/go-patch/patch"
 	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/awssecretsmanager"
+	"github.com/pivotal-cf/om/credhub"
+	"github.com/pivotal-cf/om/gcpsecretmanager"
+	"github.com/pivotal-cf/om/vault"
 	"gopkg.in/yaml.v2"
 )
 
@@ -20,6 +24,8 @@ type Interpolate struct {
 		Path       string   `long:"path"                                description:"Extract specified value out of the interpolated file (e.g.: /private_key). The rest of the file will not be printed."`
 		VarsEnv    []string `long:"vars-env"                            description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
 		VarsFile   []string `long:"vars-file" short:"l"                 description:"Load variables from a YAML file"`
+		Vars       []string `long:"var"                                 description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore  string   `long:"vars-store"                          description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
 		OpsFile    []string `long:"ops-file"  short:"o"                 description:"YAML operations files"`
 	}
 }
@@ -28,6 +34,8 @@ type interpolateOptions struct {
 	templateFile string
 	varsEnvs     []string
 	varsFiles    []string
+	vars         []string
+	varsStore    string
 	opsFiles     []string
 	environFunc  func() []string
 }
@@ -49,6 +57,8 @@ func (c Interpolate) Execute(args []string) error {
 		varsFiles:    c.Options.VarsFile,
 		environFunc:  c.environFunc,
 		varsEnvs:     c.Options.VarsEnv,
+		vars:         c.Options.Vars,
+		varsStore:    c.Options.VarsStore,
 		opsFiles:     c.Options.OpsFile,
 	}, c.Options.Path)
 	if err != nil {
@@ -74,6 +84,12 @@ func interpolate(o interpolateOptions, pathStr string) ([]byte, error) {
 		return nil, err
 	}
 
+	// the ((path#key)) references are only those of vault keys when vault
+	// is the vars store
+	if o.varsStore == "vault" {
+		contents = vault.NormalizeReferences(contents)
+	}
+
 	tpl := boshtpl.NewTemplate(contents)
 	staticVars := boshtpl.StaticVariables{}
 	ops := patch.Ops{}
@@ -122,6 +138,15 @@ func interpolate(o interpolateOptions, pathStr string) ([]byte, error) {
 		}
 	}
 
+	for _, v := range o.vars {
+		pieces := strings.SplitN(v, "=", 2)
+		if len(pieces) != 2 {
+			return []byte{}, fmt.Errorf("Expected variable %q to be key-value pair", v)
+		}
+
+		staticVars[pieces[0]] = pieces[1]
+	}
+
 	for _, path := range o.opsFiles {
 		var opDefs []patch.OpDefinition
 		err = readYAMLFile(path, &opDefs)
@@ -149,7 +174,22 @@ func interpolate(o interpolateOptions, pathStr string) ([]byte, error) {
 		evalOpts.PostVarSubstitutionOp = patch.FindOp{Path: path}
 	}
 
-	bytes, err := tpl.Evaluate(staticVars, ops, evalOpts)
+	var environ []string
+	if o.environFunc != nil {
+		environ = o.environFunc()
+	}
+
+	vars := []boshtpl.Variables{staticVars}
+
+	if o.varsStore != "" {
+		storeVars, err := varsStore(o.varsStore, environ)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, storeVars)
+	}
+
+	bytes, err := tpl.Evaluate(boshtpl.NewMultiVars(vars), ops, evalOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -157,6 +197,21 @@ func interpolate(o interpolateOptions, pathStr string) ([]byte, error) {
 	return bytes, nil
 }
 
+func varsStore(name string, environ []string) (boshtpl.Variables, error) {
+	switch name {
+	case "credhub":
+		return credhub.NewVariablesFromEnv(environ)
+	case "aws-secrets-manager":
+		return awssecretsmanager.NewVariablesFromEnv(environ)
+	case "gcp-secret-manager":
+		return gcpsecretmanager.NewVariablesFromEnv(environ)
+	case "vault":
+		return vault.NewVariablesFromEnv(environ)
+	}
+
+	return nil, fmt.Errorf("unknown vars store %q, supported vars stores are: credhub, aws-secrets-manager, gcp-secret-manager, vault", name)
+}
+
 func readYAMLFile(path string, dataType interface{}) error {
 	payload, err := ioutil.ReadFile(path)
 	if err != nil {
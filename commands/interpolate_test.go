@@ -2,6 +2,8 @@ package commands_test
 
 import (
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 
 	. "github.com/onsi/ginkgo"
@@ -127,6 +129,148 @@ var _ = Describe("Interpolate", func() {
 			})
 		})
 
+		Context("with var input", func() {
+			It("succeeds", func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateWithParameters), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--var", "hello=world",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				content := logger.PrintlnArgsForCall(0)
+				Expect(content[0].(string)).To(MatchYAML("hello: world"))
+			})
+
+			It("takes precedence over the vars files", func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateWithParameters), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(varsFile, []byte(varsFileParameter), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--vars-file", varsFile,
+					"--var", "hello=new world",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				content := logger.PrintlnArgsForCall(0)
+				Expect(content[0].(string)).To(MatchYAML("hello: new world"))
+			})
+
+			It("returns an error when the var is not a key-value pair", func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateWithParameters), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--var", "hello",
+				})
+				Expect(err).To(MatchError(`Expected variable "hello" to be key-value pair`))
+			})
+		})
+
+		Context("with vault variables", func() {
+			var server *httptest.Server
+
+			BeforeEach(func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					Expect(req.URL.Path).To(Equal("/v1/secret/cf"))
+					Expect(req.Header.Get("X-Vault-Token")).To(Equal("some-token"))
+					w.Write([]byte(`{"data": {"password": "some-password"}}`))
+				}))
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("reads the keys of the referenced secrets from vault", func() {
+				command = commands.NewInterpolate(func() []string {
+					return []string{"VAULT_ADDR=" + server.URL, "VAULT_TOKEN=some-token"}
+				}, logger)
+
+				err := ioutil.WriteFile(inputFile, []byte(`{"password": "((secret/cf#password))", "hello": "((hello))"}`), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--var", "hello=world",
+					"--vars-store", "vault",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				content := logger.PrintlnArgsForCall(0)
+				Expect(content[0].(string)).To(MatchYAML(`{"password": "some-password", "hello": "world"}`))
+			})
+
+			It("returns an error when vault is not fully configured", func() {
+				command = commands.NewInterpolate(func() []string {
+					return []string{"VAULT_ADDR=" + server.URL}
+				}, logger)
+
+				err := ioutil.WriteFile(inputFile, []byte(templateNoParameters), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{"--config", inputFile, "--vars-store", "vault"})
+				Expect(err).To(MatchError(ContainSubstring("must be set along with VAULT_ADDR")))
+			})
+
+			It("does not read from vault without --vars-store vault, even when VAULT_ADDR is set", func() {
+				command = commands.NewInterpolate(func() []string {
+					return []string{"VAULT_ADDR=" + server.URL}
+				}, logger)
+
+				err := ioutil.WriteFile(inputFile, []byte(`{"password": "((secret/cf#password))"}`), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{"--config", inputFile})
+				Expect(err).NotTo(HaveOccurred())
+
+				content := logger.PrintlnArgsForCall(0)
+				Expect(content[0].(string)).To(MatchYAML(`{"password": "((secret/cf#password))"}`))
+			})
+		})
+
+		Context("with a vars store", func() {
+			It("returns an error when the vars store is not supported", func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateNoParameters), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--vars-store", "some-store",
+				})
+				Expect(err).To(MatchError(`unknown vars store "some-store", supported vars stores are: credhub, aws-secrets-manager, gcp-secret-manager, vault`))
+			})
+
+			It("returns an error when credhub is not configured", func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateNoParameters), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--vars-store", "credhub",
+				})
+				Expect(err).To(MatchError(ContainSubstring("must be set to read variables from credhub")))
+			})
+
+			It("returns an error when aws secrets manager is not configured", func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateNoParameters), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--vars-store", "aws-secrets-manager",
+				})
+				Expect(err).To(MatchError("AWS_REGION must be set to read variables from aws secrets manager"))
+			})
+
+			It("returns an error when gcp secret manager is not configured", func() {
+				err := ioutil.WriteFile(inputFile, []byte(templateNoParameters), 0755)
+				Expect(err).NotTo(HaveOccurred())
+				err = command.Execute([]string{
+					"--config", inputFile,
+					"--vars-store", "gcp-secret-manager",
+				})
+				Expect(err).To(MatchError("GOOGLE_APPLICATION_CREDENTIALS must be set to read variables from gcp secret manager"))
+			})
+		})
+
 		Context("with ops file input", func() {
 			It("succeeds", func() {
 				err := ioutil.WriteFile(inputFile, []byte(templateNoParameters), 0755)
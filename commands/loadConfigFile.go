@@ -9,7 +9,7 @@ import (
 
 // Load the config file, (optionally) load the vars file, vars env as well
 // To use this function, `Config` field must be defined in the command struct being passed in.
-// To load vars, VarsFile and/or VarsEnv must exist in the command struct being passed in.
+// To load vars, VarsFile, VarsEnv, Vars and/or VarsStore must exist in the command struct being passed in.
 // If VarsEnv is used, envFunc must be defined instead of nil
 func loadConfigFile(args []string, command interface{}, envFunc func() []string) error {
 	_, err := jhanda.Parse(command, args)
@@ -21,10 +21,14 @@ func loadConfigFile(args []string, command interface{}, envFunc func() []string)
 
 	varsFileField := commandValue.FieldByName("VarsFile")
 	varsEnvField := commandValue.FieldByName("VarsEnv")
+	varsInlineField := commandValue.FieldByName("Vars")
+	varsStoreField := commandValue.FieldByName("VarsStore")
 
 	var (
 		varsField []string
 		varsEnv   []string
+		vars      []string
+		varsStore string
 		ok        bool
 		options   map[string]string
 		contents  []byte
@@ -42,10 +46,24 @@ func loadConfigFile(args []string, command interface{}, envFunc func() []string)
 		}
 	}
 
+	if varsInlineField.IsValid() {
+		if vars, ok = varsInlineField.Interface().([]string); !ok {
+			return fmt.Errorf("expect Vars field to be a `[]string`, found %s", varsInlineField.Type())
+		}
+	}
+
+	if varsStoreField.IsValid() {
+		if varsStore, ok = varsStoreField.Interface().(string); !ok {
+			return fmt.Errorf("expect VarsStore field to be a `string`, found %s", varsStoreField.Type())
+		}
+	}
+
 	contents, err = interpolate(interpolateOptions{
 		templateFile: configFile,
 		varsEnvs:     varsEnv,
 		varsFiles:    varsField,
+		vars:         vars,
+		varsStore:    varsStore,
 		environFunc:  envFunc,
 		opsFiles:     nil,
 	}, "")
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var secretPathPattern = regexp.MustCompile(`(?i)(password|passphrase|secret|token|credentials?|private_key|cert|certificate|_key)$`)
+
+// manifestSectionOrder lists the manifest sections that are diffed first,
+// every other top-level key follows in alphabetical order.
+var manifestSectionOrder = []string{"releases", "stemcells", "instance_groups", "properties"}
+
+// manifestDiff returns the changes between two BOSH manifests, grouped by their
+// top-level section. Lists of named elements, such as releases, instance groups
+// and jobs, are compared by name rather than position.
+func manifestDiff(deployed, staged map[interface{}]interface{}) []string {
+	var sections []string
+	seen := map[string]bool{}
+	for _, section := range manifestSectionOrder {
+		sections = append(sections, section)
+		seen[section] = true
+	}
+
+	var others []string
+	for _, manifest := range []map[interface{}]interface{}{deployed, staged} {
+		for key := range manifest {
+			section := fmt.Sprintf("%v", key)
+			if !seen[section] {
+				others = append(others, section)
+				seen[section] = true
+			}
+		}
+	}
+	sort.Strings(others)
+	sections = append(sections, others...)
+
+	var lines []string
+	for _, section := range sections {
+		var changes []string
+		diffValue(section, "", deployed[section], staged[section], &changes)
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		lines = append(lines, section+":")
+		lines = append(lines, changes...)
+	}
+
+	return lines
+}
+
+func diffValue(section, path string, deployed, staged interface{}, changes *[]string) {
+	if reflect.DeepEqual(deployed, staged) {
+		return
+	}
+
+	switch {
+	case deployed == nil:
+		*changes = append(*changes, "  + "+describeValue(section, path, staged))
+		return
+	case staged == nil:
+		*changes = append(*changes, "  - "+describeValue(section, path, deployed))
+		return
+	}
+
+	deployedMap, deployedIsMap := asMap(deployed)
+	stagedMap, stagedIsMap := asMap(staged)
+	if deployedIsMap && stagedIsMap {
+		for _, key := range mapKeys(deployedMap, stagedMap) {
+			diffValue(section, joinPath(path, key), deployedMap[key], stagedMap[key], changes)
+		}
+		return
+	}
+
+	if isSecretPath(section, path) {
+		*changes = append(*changes, fmt.Sprintf("  ~ %s: (redacted)", displayPath(section, path)))
+		return
+	}
+
+	*changes = append(*changes, fmt.Sprintf("  ~ %s: %s -> %s", displayPath(section, path), formatValue(deployed), formatValue(staged)))
+}
+
+// asMap returns maps, and lists whose elements all have a name, as maps
+// keyed by the name of the elements. An empty list is an empty map.
+func asMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, child := range v {
+			m[fmt.Sprintf("%v", key)] = child
+		}
+		return m, true
+	case []interface{}:
+		m := map[string]interface{}{}
+		for _, element := range v {
+			name, ok := elementName(element)
+			if !ok {
+				return nil, false
+			}
+			m[name] = element
+		}
+		return m, true
+	}
+
+	return nil, false
+}
+
+func elementName(element interface{}) (string, bool) {
+	m, ok := element.(map[interface{}]interface{})
+	if !ok {
+		return "", false
+	}
+
+	for _, key := range []string{"name", "alias"} {
+		if name, ok := m[key].(string); ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+func mapKeys(maps ...map[string]interface{}) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				keys = append(keys, key)
+				seen[key] = true
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func describeValue(section, path string, value interface{}) string {
+	if _, isMap := asMap(value); isMap {
+		return displayPath(section, path)
+	}
+
+	if isSecretPath(section, path) {
+		return displayPath(section, path) + ": (redacted)"
+	}
+
+	return fmt.Sprintf("%s: %s", displayPath(section, path), formatValue(value))
+}
+
+func formatValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		if strings.Contains(s, "-----BEGIN") {
+			return "(redacted)"
+		}
+
+		if strings.Contains(s, "\n") {
+			return fmt.Sprintf("%q", s)
+		}
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+func isSecretPath(section, path string) bool {
+	segments := strings.Split(joinPath(section, path), ".")
+	return secretPathPattern.MatchString(segments[len(segments)-1])
+}
+
+func displayPath(section, path string) string {
+	if path == "" {
+		return section
+	}
+
+	return path
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	notifyFormatJSON  = "json"
+	notifyFormatSlack = "slack"
+)
+
+var notifyClient = &http.Client{Timeout: 30 * time.Second}
+
+// notification is the summary of a command that is posted to --notify-url
+// when the command finishes.
+type notification struct {
+	Command         string   `json:"command"`
+	Status          string   `json:"status"`
+	Duration        string   `json:"duration"`
+	DurationSeconds int64    `json:"duration_seconds"`
+	Products        []string `json:"products"`
+	Errors          []string `json:"errors"`
+}
+
+func newNotification(command string, started time.Time, products []string, err error) notification {
+	duration := time.Since(started).Round(time.Second)
+
+	n := notification{
+		Command:         command,
+		Status:          "succeeded",
+		Duration:        duration.String(),
+		DurationSeconds: int64(duration.Seconds()),
+		Products:        products,
+		Errors:          []string{},
+	}
+	if n.Products == nil {
+		n.Products = []string{}
+	}
+	if err != nil {
+		n.Status = "failed"
+		n.Errors = []string{err.Error()}
+	}
+	if err == ErrInstallationTimedOut {
+		n.Status = "timed_out"
+	}
+
+	return n
+}
+
+func validateNotifyFormat(format string) error {
+	if format != notifyFormatJSON && format != notifyFormatSlack {
+		return fmt.Errorf("--notify-format must be %s or %s, got %q", notifyFormatJSON, notifyFormatSlack, format)
+	}
+	return nil
+}
+
+// notify posts the notification to the url, as is or as a Slack message.
+// Nothing is posted when no url is given, or when the format is not
+// supported, which the command has already failed with.
+func notify(notifyURL, format string, n notification) error {
+	if notifyURL == "" || validateNotifyFormat(format) != nil {
+		return nil
+	}
+
+	var payload interface{} = n
+	if format == notifyFormatSlack {
+		payload = struct {
+			Text string `json:"text"`
+		}{n.slackText()}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err // un-tested
+	}
+
+	resp, err := notifyClient.Post(notifyURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// the error of the client has the whole url too
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("could not notify %s: %s", redactedNotifyURL(notifyURL), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("could not notify %s: unexpected response %d", redactedNotifyURL(notifyURL), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// redactedNotifyURL is the scheme and the host of the url, as the webhooks
+// of Slack, Teams and the like have their secret in the path.
+func redactedNotifyURL(notifyURL string) string {
+	u, err := url.Parse(notifyURL)
+	if err != nil || u.Host == "" {
+		return "--notify-url"
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
+func (n notification) slackText() string {
+	text := fmt.Sprintf("om %s %s after %s", n.Command, n.Status, n.Duration)
+	if len(n.Products) > 0 {
+		text += fmt.Sprintf(" (products: %s)", strings.Join(n.Products, ", "))
+	}
+	if len(n.Errors) > 0 {
+		text += ": " + strings.Join(n.Errors, "; ")
+	}
+	return text
+}
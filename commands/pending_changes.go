@@ -13,6 +13,7 @@ type PendingChanges struct {
 	presenter presenters.FormattedPresenter
 	Options   struct {
 		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+		Check  bool   `long:"check"                               description:"Exit 1 if there are any pending changes. Useful for validating that Ops Manager is in a clean state."`
 	}
 }
 
@@ -41,6 +42,14 @@ func (pc PendingChanges) Execute(args []string) error {
 	pc.presenter.SetFormat(pc.Options.Format)
 	pc.presenter.PresentPendingChanges(output.ChangeList)
 
+	if pc.Options.Check {
+		for _, change := range output.ChangeList {
+			if change.Action != "unchanged" {
+				return fmt.Errorf("there are pending changes")
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -71,6 +71,29 @@ var _ = Describe("PendingChanges", func() {
 			})
 		})
 
+		Context("when the check flag is provided", func() {
+			It("returns an error if there are pending changes", func() {
+				err := command.Execute([]string{"--check"})
+				Expect(err).To(MatchError("there are pending changes"))
+
+				Expect(presenter.PresentPendingChangesCallCount()).To(Equal(1))
+			})
+
+			It("does not return an error if all products are unchanged", func() {
+				pcService.ListStagedPendingChangesReturns(api.PendingChangesOutput{
+					ChangeList: []api.ProductChange{
+						{GUID: "some-product", Action: "unchanged"},
+						{GUID: "some-other-product", Action: "unchanged"},
+					},
+				}, nil)
+
+				err := command.Execute([]string{"--check"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(presenter.PresentPendingChangesCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("failure cases", func() {
 			Context("when an unknown flag is passed", func() {
 				It("returns an error", func() {
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"github.com/pivotal-cf/go-pivnet"
 	pivnetlog "github.com/pivotal-cf/go-pivnet/logger"
@@ -116,6 +117,10 @@ func (p *pivnetClient) DownloadProductToFile(fa *FileArtifact, file *os.File) er
 	return nil
 }
 
+func (p *pivnetClient) DownloadProductStream(fa *FileArtifact) (io.ReadCloser, int64, error) {
+	return nil, 0, errors.New("streaming products from Pivotal Network is not supported at this time")
+}
+
 func (p *pivnetClient) DownloadProductStemcell(fa *FileArtifact) (*stemcell, error) {
 	dependencies, err := p.downloader.ReleaseDependencies(fa.slug, fa.releaseID)
 	if err != nil {
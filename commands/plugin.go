@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pivotal-cf/jhanda"
+)
+
+// pluginPrefix is the prefix of the executables that om runs as the
+// commands it does not know, e.g. om-compliance for om compliance
+const pluginPrefix = "om-"
+
+// Plugin runs an om-<name> executable, with the target and a token of
+// om in its environment, as the om <name> command.
+type Plugin struct {
+	runner      commandRunner
+	path        string
+	environment func() ([]string, error)
+}
+
+// FindPlugin returns the path of the om-<name> executable, looked up in the
+// plugins directory first, when one is given, then on the PATH.
+func FindPlugin(name, pluginsDir string) (string, bool) {
+	if name == "" || filepath.Base(name) != name {
+		return "", false
+	}
+
+	if pluginsDir != "" {
+		path := filepath.Join(pluginsDir, pluginPrefix+name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return path, true
+		}
+	}
+
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return "", false
+	}
+
+	return path, true
+}
+
+// NewPlugin creates the command that runs the executable at the path. The
+// environment is only computed when the plugin runs, as it can require a
+// token of the UAA of Ops Manager.
+func NewPlugin(runner commandRunner, path string, environment func() ([]string, error)) Plugin {
+	return Plugin{
+		runner:      runner,
+		path:        path,
+		environment: environment,
+	}
+}
+
+// Execute runs the plugin with the args as they are, the plugin parses its
+// own flags. The error of the executable is returned as is, for om to exit
+// with the exit code of the plugin.
+func (p Plugin) Execute(args []string) error {
+	env, err := p.environment()
+	if err != nil {
+		return fmt.Errorf("could not run the plugin %s: %s", p.path, err)
+	}
+
+	return p.runner.Run(p.path, args, env)
+}
+
+func (p Plugin) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      fmt.Sprintf("This command runs the plugin %s, with the target and a token of om in its environment.", p.path),
+		ShortDescription: "runs a plugin",
+	}
+}
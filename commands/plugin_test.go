@@ -0,0 +1,132 @@
+package commands_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugin", func() {
+	Describe("FindPlugin", func() {
+		var (
+			pluginsDir string
+			pathDir    string
+			path       string
+		)
+
+		writeExecutable := func(dir, name string, mode os.FileMode) string {
+			executable := filepath.Join(dir, name)
+			err := ioutil.WriteFile(executable, []byte("#!/bin/sh\n"), mode)
+			Expect(err).NotTo(HaveOccurred())
+			return executable
+		}
+
+		BeforeEach(func() {
+			var err error
+			pluginsDir, err = ioutil.TempDir("", "plugins")
+			Expect(err).NotTo(HaveOccurred())
+
+			pathDir, err = ioutil.TempDir("", "path")
+			Expect(err).NotTo(HaveOccurred())
+
+			path = os.Getenv("PATH")
+			os.Setenv("PATH", pathDir)
+		})
+
+		AfterEach(func() {
+			os.Setenv("PATH", path)
+			os.RemoveAll(pluginsDir)
+			os.RemoveAll(pathDir)
+		})
+
+		It("finds the plugin in the plugins directory before the PATH", func() {
+			executable := writeExecutable(pluginsDir, "om-compliance", 0755)
+			writeExecutable(pathDir, "om-compliance", 0755)
+
+			found, ok := commands.FindPlugin("compliance", pluginsDir)
+			Expect(ok).To(BeTrue())
+			Expect(found).To(Equal(executable))
+		})
+
+		It("finds the plugin on the PATH", func() {
+			executable := writeExecutable(pathDir, "om-compliance", 0755)
+
+			found, ok := commands.FindPlugin("compliance", pluginsDir)
+			Expect(ok).To(BeTrue())
+			Expect(found).To(Equal(executable))
+
+			found, ok = commands.FindPlugin("compliance", "")
+			Expect(ok).To(BeTrue())
+			Expect(found).To(Equal(executable))
+		})
+
+		It("does not find the files of the plugins directory that cannot be executed", func() {
+			writeExecutable(pluginsDir, "om-compliance", 0644)
+
+			_, ok := commands.FindPlugin("compliance", pluginsDir)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("does not find a plugin that does not exist", func() {
+			_, ok := commands.FindPlugin("compliance", pluginsDir)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("does not find the names that are paths", func() {
+			Expect(os.Mkdir(filepath.Join(pluginsDir, "om-nested"), 0755)).To(Succeed())
+			writeExecutable(filepath.Join(pluginsDir, "om-nested"), "compliance", 0755)
+
+			_, ok := commands.FindPlugin("nested/compliance", pluginsDir)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Execute", func() {
+		It("runs the plugin with the args and the environment", func() {
+			fakeRunner := &fakes.CommandRunner{}
+			command := commands.NewPlugin(fakeRunner, "/plugins/om-compliance", func() ([]string, error) {
+				return []string{"OM_TARGET=https://opsman.example.com"}, nil
+			})
+
+			err := command.Execute([]string{"--strict", "cf"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeRunner.RunCallCount()).To(Equal(1))
+			name, args, env := fakeRunner.RunArgsForCall(0)
+			Expect(name).To(Equal("/plugins/om-compliance"))
+			Expect(args).To(Equal([]string{"--strict", "cf"}))
+			Expect(env).To(Equal([]string{"OM_TARGET=https://opsman.example.com"}))
+		})
+
+		It("returns the error of the plugin as is", func() {
+			fakeRunner := &fakes.CommandRunner{}
+			pluginErr := errors.New("exit status 3")
+			fakeRunner.RunReturns(pluginErr)
+
+			command := commands.NewPlugin(fakeRunner, "/plugins/om-compliance", func() ([]string, error) {
+				return nil, nil
+			})
+
+			err := command.Execute(nil)
+			Expect(err).To(BeIdenticalTo(pluginErr))
+		})
+
+		It("does not run the plugin when its environment cannot be computed", func() {
+			fakeRunner := &fakes.CommandRunner{}
+			command := commands.NewPlugin(fakeRunner, "/plugins/om-compliance", func() ([]string, error) {
+				return nil, errors.New("could not authenticate")
+			})
+
+			err := command.Execute(nil)
+			Expect(err).To(MatchError("could not run the plugin /plugins/om-compliance: could not authenticate"))
+			Expect(fakeRunner.RunCallCount()).To(Equal(0))
+		})
+	})
+})
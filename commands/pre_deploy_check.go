@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+)
+
+type PreDeployCheck struct {
+	service preDeployCheckService
+	logger  logger
+}
+
+//go:generate counterfeiter -o ./fakes/pre_deploy_check_service.go --fake-name PreDeployCheckService . preDeployCheckService
+type preDeployCheckService interface {
+	ListStagedProducts() (api.StagedProductsOutput, error)
+	GetDirectorPreDeployCheck() (api.PreDeployCheck, error)
+	GetProductPreDeployCheck(productGUID string) (api.PreDeployCheck, error)
+}
+
+func NewPreDeployCheck(service preDeployCheckService, logger logger) PreDeployCheck {
+	return PreDeployCheck{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (pdc PreDeployCheck) Execute(args []string) error {
+	pdc.logger.Printf("checking the configuration of the director and staged products...")
+
+	var incomplete []string
+
+	directorCheck, err := pdc.service.GetDirectorPreDeployCheck()
+	if err != nil {
+		return fmt.Errorf("could not check the director configuration: %s", err)
+	}
+	if !pdc.report("director", directorCheck) {
+		incomplete = append(incomplete, "director")
+	}
+
+	stagedProducts, err := pdc.service.ListStagedProducts()
+	if err != nil {
+		return fmt.Errorf("could not list staged products: %s", err)
+	}
+
+	for _, product := range stagedProducts.Products {
+		if product.Type == "p-bosh" {
+			continue
+		}
+
+		productCheck, err := pdc.service.GetProductPreDeployCheck(product.GUID)
+		if err != nil {
+			return fmt.Errorf("could not check the configuration of %s: %s", product.Type, err)
+		}
+		if !pdc.report(product.Type, productCheck) {
+			incomplete = append(incomplete, product.Type)
+		}
+	}
+
+	if len(incomplete) > 0 {
+		return fmt.Errorf("the following are not fully configured: %s", strings.Join(incomplete, ", "))
+	}
+
+	pdc.logger.Printf("the director and all staged products are fully configured")
+
+	return nil
+}
+
+// report prints every problem found by the pre-deploy check
+// and returns whether the configuration is complete.
+func (pdc PreDeployCheck) report(name string, check api.PreDeployCheck) bool {
+	if check.Complete {
+		pdc.logger.Printf("[✓] %s", name)
+		return true
+	}
+
+	pdc.logger.Printf("[X] %s", name)
+
+	if !check.Network.Assigned {
+		pdc.logger.Printf("    network is not assigned")
+	}
+
+	if !check.AvailabilityZone.Assigned {
+		pdc.logger.Printf("    availability zone is not assigned")
+	}
+
+	for _, stemcell := range check.Stemcells {
+		if !stemcell.Assigned {
+			pdc.logger.Printf("    stemcell is not assigned: requires %s %s", stemcell.RequiredStemcellOS, stemcell.RequiredStemcellVersion)
+		}
+	}
+
+	for _, property := range check.Properties {
+		for _, propertyError := range property.Errors {
+			pdc.logger.Printf("    property %s: %s", property.Name, propertyError)
+		}
+	}
+
+	for _, job := range check.Resources.Jobs {
+		for _, jobError := range job.Errors {
+			pdc.logger.Printf("    resource %s: %s", job.Identifier, jobError)
+		}
+	}
+
+	for _, verifier := range check.Verifiers {
+		for _, verifierError := range verifier.Errors {
+			pdc.logger.Printf("    verifier %s: %s", verifier.Type, verifierError)
+		}
+	}
+
+	return false
+}
+
+func (pdc PreDeployCheck) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This authenticated command checks that the director and every staged product are fully configured, " +
+			"reporting any missing properties, resources, networks, availability zones or stemcells. " +
+			"It exits non-zero if anything is not fully configured. Requires Ops Manager 2.6 or newer.",
+		ShortDescription: "checks that the director and staged products are ready to be deployed",
+	}
+}
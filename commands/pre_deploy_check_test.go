@@ -0,0 +1,156 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreDeployCheck", func() {
+	var (
+		fakeService *fakes.PreDeployCheckService
+		logger      *fakes.Logger
+		command     commands.PreDeployCheck
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.PreDeployCheckService{}
+		logger = &fakes.Logger{}
+		command = commands.NewPreDeployCheck(fakeService, logger)
+
+		fakeService.ListStagedProductsReturns(api.StagedProductsOutput{
+			Products: []api.StagedProduct{
+				{GUID: "p-bosh-guid", Type: "p-bosh"},
+				{GUID: "cf-guid", Type: "cf"},
+			},
+		}, nil)
+	})
+
+	logLines := func() []string {
+		var lines []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, v := logger.PrintfArgsForCall(i)
+			lines = append(lines, fmt.Sprintf(format, v...))
+		}
+		return lines
+	}
+
+	Context("when the director and all staged products are fully configured", func() {
+		BeforeEach(func() {
+			fakeService.GetDirectorPreDeployCheckReturns(api.PreDeployCheck{Identifier: "p-bosh-guid", Complete: true}, nil)
+			fakeService.GetProductPreDeployCheckReturns(api.PreDeployCheck{Identifier: "cf-guid", Complete: true}, nil)
+		})
+
+		It("reports that everything is configured", func() {
+			err := command.Execute([]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.GetProductPreDeployCheckCallCount()).To(Equal(1))
+			Expect(fakeService.GetProductPreDeployCheckArgsForCall(0)).To(Equal("cf-guid"))
+
+			Expect(logLines()).To(Equal([]string{
+				"checking the configuration of the director and staged products...",
+				"[✓] director",
+				"[✓] cf",
+				"the director and all staged products are fully configured",
+			}))
+		})
+	})
+
+	Context("when the director or a staged product is not fully configured", func() {
+		BeforeEach(func() {
+			fakeService.GetDirectorPreDeployCheckReturns(api.PreDeployCheck{
+				Identifier:       "p-bosh-guid",
+				Complete:         false,
+				Network:          api.PreDeployNetwork{Assigned: false},
+				AvailabilityZone: api.PreDeployAvailabilityZone{Assigned: false},
+				Verifiers: []api.PreDeployVerifier{
+					{Type: "NetworksExistenceVerifier", Errors: []string{"network does not exist"}},
+				},
+			}, nil)
+			fakeService.GetProductPreDeployCheckReturns(api.PreDeployCheck{
+				Identifier:       "cf-guid",
+				Complete:         false,
+				Network:          api.PreDeployNetwork{Assigned: true},
+				AvailabilityZone: api.PreDeployAvailabilityZone{Assigned: true},
+				Stemcells: []api.PreDeployStemcell{
+					{Assigned: false, RequiredStemcellOS: "ubuntu-xenial", RequiredStemcellVersion: "97.57"},
+				},
+				Properties: []api.PreDeployProperty{
+					{Name: ".properties.system_domain", Errors: []string{"can't be blank"}},
+				},
+				Resources: api.PreDeployResources{
+					Jobs: []api.PreDeployJob{
+						{Identifier: "router", Errors: []string{"Instance count must be at least 1"}},
+					},
+				},
+			}, nil)
+		})
+
+		It("reports every problem and returns an error", func() {
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("the following are not fully configured: director, cf"))
+
+			Expect(logLines()).To(Equal([]string{
+				"checking the configuration of the director and staged products...",
+				"[X] director",
+				"    network is not assigned",
+				"    availability zone is not assigned",
+				"    verifier NetworksExistenceVerifier: network does not exist",
+				"[X] cf",
+				"    stemcell is not assigned: requires ubuntu-xenial 97.57",
+				"    property .properties.system_domain: can't be blank",
+				"    resource router: Instance count must be at least 1",
+			}))
+		})
+	})
+
+	Context("failure cases", func() {
+		Context("when the director check fails", func() {
+			It("returns an error", func() {
+				fakeService.GetDirectorPreDeployCheckReturns(api.PreDeployCheck{}, errors.New("some api error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not check the director configuration: some api error"))
+			})
+		})
+
+		Context("when the staged products cannot be listed", func() {
+			It("returns an error", func() {
+				fakeService.GetDirectorPreDeployCheckReturns(api.PreDeployCheck{Complete: true}, nil)
+				fakeService.ListStagedProductsReturns(api.StagedProductsOutput{}, errors.New("some api error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list staged products: some api error"))
+			})
+		})
+
+		Context("when a product check fails", func() {
+			It("returns an error", func() {
+				fakeService.GetDirectorPreDeployCheckReturns(api.PreDeployCheck{Complete: true}, nil)
+				fakeService.GetProductPreDeployCheckReturns(api.PreDeployCheck{}, errors.New("some api error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not check the configuration of cf: some api error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description: "This authenticated command checks that the director and every staged product are fully configured, " +
+					"reporting any missing properties, resources, networks, availability zones or stemcells. " +
+					"It exits non-zero if anything is not fully configured. Requires Ops Manager 2.6 or newer.",
+				ShortDescription: "checks that the director and staged products are ready to be deployed",
+			}))
+		})
+	})
+})
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/jhanda"
+	"gopkg.in/yaml.v2"
+)
+
+type ProductMetadata struct {
+	metadataExtractor metadataExtractor
+	logger            logger
+	Options           struct {
+		PivotalFile string `long:"pivotal-file" short:"p" required:"true" description:"path to the .pivotal file of the product, or its http(s):// or s3:// URL"`
+		Format      string `long:"format"       short:"f" default:"json"  description:"Format to print as (options: json,yaml)"`
+	}
+}
+
+type productMetadataOutput struct {
+	Name                 string                   `json:"name"                   yaml:"name"`
+	Version              string                   `json:"version"                yaml:"version"`
+	Stemcell             productMetadataStemcell  `json:"stemcell"               yaml:"stemcell"`
+	MinimumOpsmanVersion string                   `json:"minimum_opsman_version" yaml:"minimum_opsman_version"`
+	Releases             []productMetadataRelease `json:"releases"               yaml:"releases"`
+}
+
+type productMetadataStemcell struct {
+	OS      string `json:"os"      yaml:"os"`
+	Version string `json:"version" yaml:"version"`
+}
+
+type productMetadataRelease struct {
+	Name    string `json:"name"    yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	File    string `json:"file"    yaml:"file"`
+}
+
+func NewProductMetadata(metadataExtractor metadataExtractor, logger logger) ProductMetadata {
+	return ProductMetadata{
+		metadataExtractor: metadataExtractor,
+		logger:            logger,
+	}
+}
+
+func (pm ProductMetadata) Execute(args []string) error {
+	if _, err := jhanda.Parse(&pm.Options, args); err != nil {
+		return fmt.Errorf("could not parse product-metadata flags: %s", err)
+	}
+
+	if pm.Options.Format != "json" && pm.Options.Format != "yaml" {
+		return fmt.Errorf("could not parse product-metadata flags: --format must be json or yaml, got %q", pm.Options.Format)
+	}
+
+	pivotalFile := pm.Options.PivotalFile
+	if isRemoteFile(pivotalFile) {
+		var (
+			cleanup func()
+			err     error
+		)
+		pivotalFile, cleanup, err = pm.downloadPivotalFile()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
+
+	metadata, err := pm.metadataExtractor.ExtractMetadata(pivotalFile)
+	if err != nil {
+		return fmt.Errorf("could not extract the metadata of %s: %s", remoteFileName(pm.Options.PivotalFile), err)
+	}
+
+	var raw struct {
+		MetadataVersion  string `yaml:"metadata_version"`
+		StemcellCriteria struct {
+			OS      string `yaml:"os"`
+			Version string `yaml:"version"`
+		} `yaml:"stemcell_criteria"`
+		Releases []productMetadataRelease `yaml:"releases"`
+	}
+	err = yaml.Unmarshal(metadata.Raw, &raw)
+	if err != nil {
+		return fmt.Errorf("could not parse the metadata of %s: %s", remoteFileName(pm.Options.PivotalFile), err) // un-tested
+	}
+
+	output := productMetadataOutput{
+		Name:                 metadata.Name,
+		Version:              metadata.Version,
+		Stemcell:             productMetadataStemcell{OS: raw.StemcellCriteria.OS, Version: raw.StemcellCriteria.Version},
+		MinimumOpsmanVersion: raw.MetadataVersion,
+		Releases:             raw.Releases,
+	}
+	if output.Releases == nil {
+		output.Releases = []productMetadataRelease{}
+	}
+
+	var contents []byte
+	if pm.Options.Format == "yaml" {
+		contents, err = yaml.Marshal(output)
+	} else {
+		contents, err = json.MarshalIndent(output, "", "  ")
+	}
+	if err != nil {
+		return err // un-tested
+	}
+
+	pm.logger.Println(string(contents))
+
+	return nil
+}
+
+// downloadPivotalFile downloads a remote .pivotal file to a temporary file,
+// as its metadata can only be read from a file that can be seeked.
+func (pm ProductMetadata) downloadPivotalFile() (string, func(), error) {
+	remote, err := openRemoteFile(pm.Options.PivotalFile)
+	if err != nil {
+		return "", nil, err
+	}
+	defer remote.Content.Close()
+
+	file, err := ioutil.TempFile("", "product-metadata")
+	if err != nil {
+		return "", nil, err // un-tested
+	}
+	defer file.Close()
+
+	cleanup := func() { os.Remove(file.Name()) }
+
+	_, err = io.Copy(file, remote.Content)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("could not download %s: %s", remote.Name, err)
+	}
+
+	return file.Name(), cleanup, nil
+}
+
+func (pm ProductMetadata) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command prints the name, the version, the required stemcell, the minimum Ops Manager version and the releases of a product, read from the metadata of its .pivotal file, without uploading it. The .pivotal file can be local, or downloaded from an http(s):// or s3:// URL.",
+		ShortDescription: "prints the metadata of a .pivotal file",
+		Flags:            pm.Options,
+	}
+}
@@ -0,0 +1,179 @@
+package commands_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/extractor"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const productMetadataYAML = `---
+name: some-product
+product_version: 1.2.3
+metadata_version: "2.6"
+stemcell_criteria:
+  os: ubuntu-xenial
+  version: "250.17"
+releases:
+- name: some-release
+  version: 1.0.0
+  file: some-release-1.0.0.tgz
+- name: other-release
+  version: 2.0.0
+  file: other-release-2.0.0.tgz
+`
+
+var _ = Describe("ProductMetadata", func() {
+	var (
+		metadataExtractor *fakes.MetadataExtractor
+		logger            *fakes.Logger
+		command           commands.ProductMetadata
+	)
+
+	BeforeEach(func() {
+		metadataExtractor = &fakes.MetadataExtractor{}
+		metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+			Name:    "some-product",
+			Version: "1.2.3",
+			Raw:     []byte(productMetadataYAML),
+		}, nil)
+		logger = &fakes.Logger{}
+
+		command = commands.NewProductMetadata(metadataExtractor, logger)
+	})
+
+	It("prints the metadata of the product as json", func() {
+		err := command.Execute([]string{"--pivotal-file", "/path/to/some-product.pivotal"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(metadataExtractor.ExtractMetadataArgsForCall(0)).To(Equal("/path/to/some-product.pivotal"))
+
+		Expect(logger.PrintlnCallCount()).To(Equal(1))
+		Expect(logger.PrintlnArgsForCall(0)[0]).To(MatchJSON(`{
+			"name": "some-product",
+			"version": "1.2.3",
+			"stemcell": {"os": "ubuntu-xenial", "version": "250.17"},
+			"minimum_opsman_version": "2.6",
+			"releases": [
+				{"name": "some-release", "version": "1.0.0", "file": "some-release-1.0.0.tgz"},
+				{"name": "other-release", "version": "2.0.0", "file": "other-release-2.0.0.tgz"}
+			]
+		}`))
+	})
+
+	It("prints the metadata of the product as yaml", func() {
+		err := command.Execute([]string{"--pivotal-file", "/path/to/some-product.pivotal", "--format", "yaml"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(logger.PrintlnArgsForCall(0)[0]).To(MatchYAML(`
+name: some-product
+version: 1.2.3
+stemcell: {os: ubuntu-xenial, version: "250.17"}
+minimum_opsman_version: "2.6"
+releases:
+- {name: some-release, version: 1.0.0, file: some-release-1.0.0.tgz}
+- {name: other-release, version: 2.0.0, file: other-release-2.0.0.tgz}
+`))
+	})
+
+	It("prints an empty list when the product has no releases", func() {
+		metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+			Name:    "some-product",
+			Version: "1.2.3",
+			Raw:     []byte("name: some-product\nproduct_version: 1.2.3\n"),
+		}, nil)
+
+		err := command.Execute([]string{"--pivotal-file", "/path/to/some-product.pivotal"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(logger.PrintlnArgsForCall(0)[0]).To(MatchJSON(`{
+			"name": "some-product",
+			"version": "1.2.3",
+			"stemcell": {"os": "", "version": ""},
+			"minimum_opsman_version": "",
+			"releases": []
+		}`))
+	})
+
+	Context("when the .pivotal file is remote", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/some-product.pivotal" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte("some-product-contents"))
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("downloads it to read its metadata", func() {
+			var contents []byte
+			metadataExtractor.ExtractMetadataStub = func(path string) (extractor.Metadata, error) {
+				var err error
+				contents, err = ioutil.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				return extractor.Metadata{Name: "some-product", Version: "1.2.3"}, nil
+			}
+
+			err := command.Execute([]string{"--pivotal-file", server.URL + "/some-product.pivotal?signature=secret"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(contents)).To(Equal("some-product-contents"))
+			Expect(metadataExtractor.ExtractMetadataArgsForCall(0)).NotTo(BeAnExistingFile())
+		})
+
+		It("returns an error when it cannot be downloaded", func() {
+			err := command.Execute([]string{"--pivotal-file", server.URL + "/missing.pivotal"})
+			Expect(err).To(MatchError(ContainSubstring("could not download " + server.URL + "/missing.pivotal: unexpected response 404")))
+
+			Expect(metadataExtractor.ExtractMetadataCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the flags cannot be parsed", func() {
+			err := command.Execute([]string{"--unknown-flag"})
+			Expect(err).To(MatchError(ContainSubstring("could not parse product-metadata flags")))
+		})
+
+		It("requires the .pivotal file", func() {
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError(ContainSubstring("could not parse product-metadata flags")))
+		})
+
+		It("returns an error when the format is not supported", func() {
+			err := command.Execute([]string{"--pivotal-file", "some-product.pivotal", "--format", "table"})
+			Expect(err).To(MatchError(`could not parse product-metadata flags: --format must be json or yaml, got "table"`))
+		})
+
+		It("returns an error when the metadata cannot be extracted", func() {
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{}, errors.New("no metadata file was found in provided .pivotal"))
+
+			err := command.Execute([]string{"--pivotal-file", "/path/to/some-product.pivotal"})
+			Expect(err).To(MatchError("could not extract the metadata of some-product.pivotal: no metadata file was found in provided .pivotal"))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			usage := command.Usage()
+			Expect(usage.ShortDescription).To(Equal("prints the metadata of a .pivotal file"))
+			Expect(usage.Flags).To(BeAssignableToTypeOf(command.Options))
+			Expect(usage).To(BeAssignableToTypeOf(jhanda.Usage{}))
+		})
+	})
+})
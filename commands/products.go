@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type Products struct {
+	presenter presenters.FormattedPresenter
+	service   productsService
+	Options   struct {
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/products_service.go --fake-name ProductsService . productsService
+type productsService interface {
+	GetDiagnosticReport() (api.DiagnosticReport, error)
+	ListAvailableProducts() (api.AvailableProductsOutput, error)
+}
+
+func NewProducts(presenter presenters.FormattedPresenter, service productsService) Products {
+	return Products{
+		presenter: presenter,
+		service:   service,
+	}
+}
+
+func (p Products) Execute(args []string) error {
+	if _, err := jhanda.Parse(&p.Options, args); err != nil {
+		return fmt.Errorf("could not parse products flags: %s", err)
+	}
+
+	availableProducts, err := p.service.ListAvailableProducts()
+	if err != nil {
+		return fmt.Errorf("failed to list available products: %s", err)
+	}
+
+	diagnosticReport, err := p.service.GetDiagnosticReport()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve staged and deployed products: %s", err)
+	}
+
+	products := map[string]*models.ProductVersions{}
+	product := func(name string) *models.ProductVersions {
+		if _, ok := products[name]; !ok {
+			products[name] = &models.ProductVersions{Name: name, Available: []string{}}
+		}
+		return products[name]
+	}
+
+	for _, available := range availableProducts.ProductsList {
+		product(available.Name).Available = append(product(available.Name).Available, available.Version)
+	}
+
+	for _, staged := range diagnosticReport.StagedProducts {
+		product(staged.Name).Staged = staged.Version
+	}
+
+	for _, deployed := range diagnosticReport.DeployedProducts {
+		product(deployed.Name).Deployed = deployed.Version
+	}
+
+	var names []string
+	for name := range products {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inventory := []models.ProductVersions{}
+	for _, name := range names {
+		inventory = append(inventory, *products[name])
+	}
+
+	p.presenter.SetFormat(p.Options.Format)
+	p.presenter.PresentProducts(inventory)
+
+	return nil
+}
+
+func (p Products) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command lists every product with its available, staged and deployed versions.",
+		ShortDescription: "lists the available, staged and deployed versions of products",
+		Flags:            p.Options,
+	}
+}
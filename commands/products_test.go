@@ -0,0 +1,123 @@
+package commands_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/models"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("Products", func() {
+	var (
+		presenter   *presenterfakes.FormattedPresenter
+		fakeService *fakes.ProductsService
+		command     commands.Products
+	)
+
+	BeforeEach(func() {
+		presenter = &presenterfakes.FormattedPresenter{}
+		fakeService = &fakes.ProductsService{}
+		command = commands.NewProducts(presenter, fakeService)
+	})
+
+	Describe("Execute", func() {
+		BeforeEach(func() {
+			fakeService.ListAvailableProductsReturns(api.AvailableProductsOutput{
+				ProductsList: []api.ProductInfo{
+					{Name: "p-redis", Version: "1.14.0"},
+					{Name: "cf", Version: "2.2.1"},
+					{Name: "cf", Version: "2.3.0"},
+				},
+			}, nil)
+
+			fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{
+				StagedProducts: []api.DiagnosticProduct{
+					{Name: "p-bosh", Version: "2.3-build.170"},
+					{Name: "cf", Version: "2.3.0"},
+					{Name: "p-redis", Version: "1.14.0"},
+				},
+				DeployedProducts: []api.DiagnosticProduct{
+					{Name: "p-bosh", Version: "2.3-build.170"},
+					{Name: "cf", Version: "2.2.1"},
+				},
+			}, nil)
+		})
+
+		It("lists the available, staged and deployed versions of every product", func() {
+			err := command.Execute([]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(presenter.SetFormatArgsForCall(0)).To(Equal("table"))
+			Expect(presenter.PresentProductsCallCount()).To(Equal(1))
+			Expect(presenter.PresentProductsArgsForCall(0)).To(Equal([]models.ProductVersions{
+				{Name: "cf", Available: []string{"2.2.1", "2.3.0"}, Staged: "2.3.0", Deployed: "2.2.1"},
+				{Name: "p-bosh", Available: []string{}, Staged: "2.3-build.170", Deployed: "2.3-build.170"},
+				{Name: "p-redis", Available: []string{"1.14.0"}, Staged: "1.14.0"},
+			}))
+		})
+
+		It("lists products that are only available", func() {
+			fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{}, nil)
+
+			err := command.Execute([]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(presenter.PresentProductsArgsForCall(0)).To(Equal([]models.ProductVersions{
+				{Name: "cf", Available: []string{"2.2.1", "2.3.0"}},
+				{Name: "p-redis", Available: []string{"1.14.0"}},
+			}))
+		})
+
+		Context("when the format flag is provided", func() {
+			It("sets the format on the presenter", func() {
+				err := command.Execute([]string{"--format", "json"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(presenter.SetFormatArgsForCall(0)).To(Equal("json"))
+			})
+		})
+
+		Context("failure cases", func() {
+			Context("when an unknown flag is provided", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{"--badflag"})
+					Expect(err).To(MatchError("could not parse products flags: flag provided but not defined: -badflag"))
+				})
+			})
+
+			Context("when the available products cannot be listed", func() {
+				It("returns an error", func() {
+					fakeService.ListAvailableProductsReturns(api.AvailableProductsOutput{}, errors.New("some error"))
+
+					err := command.Execute([]string{})
+					Expect(err).To(MatchError("failed to list available products: some error"))
+				})
+			})
+
+			Context("when the diagnostic report cannot be retrieved", func() {
+				It("returns an error", func() {
+					fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{}, errors.New("some error"))
+
+					err := command.Execute([]string{})
+					Expect(err).To(MatchError("failed to retrieve staged and deployed products: some error"))
+				})
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command lists every product with its available, staged and deployed versions.",
+				ShortDescription: "lists the available, staged and deployed versions of products",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
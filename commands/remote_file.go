@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/graymeta/stow"
+	"github.com/graymeta/stow/s3"
+)
+
+// remoteFileClient downloads the files given as http(s) urls. Only the
+// connection and the wait for the response are bounded, as a file can take
+// longer than the request timeout to stream.
+var remoteFileClient = newRemoteFileClient(10*time.Second, 1800*time.Second)
+
+// SetRemoteFileTimeouts bounds the connection to the server of a remote
+// file with --connect-timeout, and the wait for its response with
+// --request-timeout.
+func SetRemoteFileTimeouts(connectTimeout, requestTimeout time.Duration) {
+	remoteFileClient = newRemoteFileClient(connectTimeout, requestTimeout)
+}
+
+func newRemoteFileClient(connectTimeout, requestTimeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			Dial: (&net.Dialer{
+				Timeout:   connectTimeout,
+				KeepAlive: 30 * time.Second,
+			}).Dial,
+			TLSHandshakeTimeout:   connectTimeout,
+			ResponseHeaderTimeout: requestTimeout,
+		},
+	}
+}
+
+type remoteFile struct {
+	Name    string
+	Size    int64
+	Content io.ReadCloser
+}
+
+func isRemoteFile(filePath string) bool {
+	u, err := url.Parse(filePath)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "http", "https", "s3":
+		return true
+	}
+
+	return false
+}
+
+// remoteFileName returns the name of the file being uploaded, without
+// any query parameters (such as a presigned signature) for remote files.
+func remoteFileName(filePath string) string {
+	if !isRemoteFile(filePath) {
+		return filepath.Base(filePath)
+	}
+
+	u, _ := url.Parse(filePath)
+	return path.Base(u.Path)
+}
+
+func openRemoteFile(filePath string) (remoteFile, error) {
+	u, err := url.Parse(filePath)
+	if err != nil {
+		return remoteFile{}, err
+	}
+
+	if u.Scheme == "s3" {
+		return openS3File(u)
+	}
+
+	return openHTTPFile(u)
+}
+
+func openHTTPFile(u *url.URL) (remoteFile, error) {
+	resp, err := remoteFileClient.Get(u.String())
+	if err != nil {
+		return remoteFile{}, fmt.Errorf("could not download %s: %s", redactedURL(u), err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return remoteFile{}, fmt.Errorf("could not download %s: unexpected response %s", redactedURL(u), resp.Status)
+	}
+
+	if resp.ContentLength <= 0 {
+		resp.Body.Close()
+		return remoteFile{}, fmt.Errorf("could not determine the size of %s: the server did not provide a Content-Length", redactedURL(u))
+	}
+
+	return remoteFile{
+		Name:    path.Base(u.Path),
+		Size:    resp.ContentLength,
+		Content: resp.Body,
+	}, nil
+}
+
+// openS3File uses the default AWS credential chain (environment variables,
+// shared credentials file or instance profile) to read s3://bucket/key.
+// The region of the bucket can be given as a query parameter, e.g. s3://bucket/key?region=us-west-2
+func openS3File(u *url.URL) (remoteFile, error) {
+	region := u.Query().Get("region")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	location, err := DefaultStow{}.Dial("s3", stow.ConfigMap{
+		s3.ConfigAuthType: "iam",
+		s3.ConfigRegion:   region,
+	})
+	if err != nil {
+		return remoteFile{}, fmt.Errorf("could not connect to s3: %s", err)
+	}
+
+	container, err := location.Container(u.Host)
+	if err != nil {
+		return remoteFile{}, fmt.Errorf("could not find bucket %s: %s", u.Host, err)
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+	item, err := container.Item(key)
+	if err != nil {
+		return remoteFile{}, fmt.Errorf("could not find %s in bucket %s: %s", key, u.Host, err)
+	}
+
+	size, err := item.Size()
+	if err != nil {
+		return remoteFile{}, fmt.Errorf("could not determine the size of %s: %s", redactedURL(u), err)
+	}
+
+	content, err := item.Open()
+	if err != nil {
+		return remoteFile{}, fmt.Errorf("could not download %s: %s", redactedURL(u), err)
+	}
+
+	return remoteFile{
+		Name:    path.Base(key),
+		Size:    size,
+		Content: content,
+	}, nil
+}
+
+func addFileToForm(form multipart, key, filePath string) error {
+	if !isRemoteFile(filePath) {
+		return form.AddFile(key, filePath)
+	}
+
+	file, err := openRemoteFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	err = form.AddReader(key, file.Name, file.Content, file.Size)
+	if err != nil {
+		file.Content.Close()
+	}
+	return err
+}
+
+func redactedURL(u *url.URL) string {
+	redacted := *u
+	redacted.RawQuery = ""
+	redacted.User = nil
+	return redacted.String()
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+//go:generate counterfeiter -o ./fakes/resource_config_service.go --fake-name ResourceConfigService . resourceConfigService
+type resourceConfigService interface {
+	GetStagedProductByName(productName string) (api.StagedProductsFindOutput, error)
+	ListStagedProductJobs(productGUID string) (map[string]string, error)
+	GetStagedProductJobResourceConfig(productGUID, jobGUID string) (api.JobProperties, error)
+}
+
+type ResourceConfig struct {
+	service   resourceConfigService
+	presenter presenters.FormattedPresenter
+	Options   struct {
+		Product string `long:"product-name" short:"p" required:"true" description:"name of the staged product"`
+		Format  string `long:"format"       short:"f" default:"table" description:"Format to print as (options: table,json); the json can be given to configure-resource-config"`
+	}
+}
+
+func NewResourceConfig(service resourceConfigService, presenter presenters.FormattedPresenter) ResourceConfig {
+	return ResourceConfig{
+		service:   service,
+		presenter: presenter,
+	}
+}
+
+func (r ResourceConfig) Execute(args []string) error {
+	if _, err := jhanda.Parse(&r.Options, args); err != nil {
+		return fmt.Errorf("could not parse resource-config flags: %s", err)
+	}
+
+	findOutput, err := r.service.GetStagedProductByName(r.Options.Product)
+	if err != nil {
+		return fmt.Errorf("failed to find the staged product %q: %s", r.Options.Product, err)
+	}
+	productGUID := findOutput.Product.GUID
+
+	jobs, err := r.service.ListStagedProductJobs(productGUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jobs: %s", err)
+	}
+
+	resourceConfig := map[string]api.JobProperties{}
+	for name, jobGUID := range jobs {
+		jobProperties, err := r.service.GetStagedProductJobResourceConfig(productGUID, jobGUID)
+		if err != nil {
+			return fmt.Errorf("could not fetch the resource config of the job %s: %s", name, err)
+		}
+		resourceConfig[name] = jobProperties
+	}
+
+	r.presenter.SetFormat(r.Options.Format)
+	r.presenter.PresentResourceConfig(resourceConfig)
+
+	return nil
+}
+
+func (r ResourceConfig) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command lists the resource config of the jobs of a staged product: the instances, VM type, persistent disk, internet connection and additional VM extensions",
+		ShortDescription: "lists the resource config of the jobs of a staged product",
+		Flags:            r.Options,
+	}
+}
@@ -0,0 +1,99 @@
+package commands_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("ResourceConfig", func() {
+	var (
+		fakeService   *fakes.ResourceConfigService
+		fakePresenter *presenterfakes.FormattedPresenter
+		command       commands.ResourceConfig
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.ResourceConfigService{}
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		command = commands.NewResourceConfig(fakeService, fakePresenter)
+
+		fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{
+			Product: api.StagedProduct{GUID: "cf-guid", Type: "cf"},
+		}, nil)
+		fakeService.ListStagedProductJobsReturns(map[string]string{
+			"router":     "router-guid",
+			"diego_cell": "diego-cell-guid",
+		}, nil)
+		fakeService.GetStagedProductJobResourceConfigStub = func(productGUID, jobGUID string) (api.JobProperties, error) {
+			return api.JobProperties{
+				Instances:    float64(3),
+				InstanceType: api.InstanceType{ID: jobGUID + "-type"},
+			}, nil
+		}
+	})
+
+	Describe("Execute", func() {
+		It("presents the resource config of every job of the product", func() {
+			err := command.Execute([]string{"--product-name", "cf", "--format", "json"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.GetStagedProductByNameArgsForCall(0)).To(Equal("cf"))
+			Expect(fakeService.ListStagedProductJobsArgsForCall(0)).To(Equal("cf-guid"))
+			Expect(fakeService.GetStagedProductJobResourceConfigCallCount()).To(Equal(2))
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+			Expect(fakePresenter.PresentResourceConfigArgsForCall(0)).To(Equal(map[string]api.JobProperties{
+				"router":     {Instances: float64(3), InstanceType: api.InstanceType{ID: "router-guid-type"}},
+				"diego_cell": {Instances: float64(3), InstanceType: api.InstanceType{ID: "diego-cell-guid-type"}},
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the product name is missing", func() {
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not parse resource-config flags: missing required flag \"--product-name\""))
+			})
+
+			It("returns an error when the product is not staged", func() {
+				fakeService.GetStagedProductByNameReturns(api.StagedProductsFindOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError(`failed to find the staged product "cf": some error`))
+			})
+
+			It("returns an error when the jobs cannot be listed", func() {
+				fakeService.ListStagedProductJobsReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError("failed to fetch jobs: some error"))
+			})
+
+			It("returns an error when the resource config of a job cannot be fetched", func() {
+				fakeService.ListStagedProductJobsReturns(map[string]string{"router": "router-guid"}, nil)
+				fakeService.GetStagedProductJobResourceConfigStub = nil
+				fakeService.GetStagedProductJobResourceConfigReturns(api.JobProperties{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--product-name", "cf"})
+				Expect(err).To(MatchError("could not fetch the resource config of the job router: some error"))
+				Expect(fakePresenter.PresentResourceConfigCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command lists the resource config of the jobs of a staged product: the instances, VM type, persistent disk, internet connection and additional VM extensions",
+				ShortDescription: "lists the resource config of the jobs of a staged product",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
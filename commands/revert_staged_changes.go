@@ -3,52 +3,36 @@ package commands
 import (
 	"fmt"
 
-	"github.com/google/go-querystring/query"
 	"github.com/pivotal-cf/jhanda"
-	"github.com/pivotal-cf/om/ui"
 )
 
 type RevertStagedChanges struct {
-	service dashboardService
+	service revertStagedChangesService
 	logger  logger
 }
 
-//go:generate counterfeiter -o ./fakes/dashboard_service.go --fake-name DashboardService . dashboardService
-type dashboardService interface {
-	GetInstallForm() (ui.Form, error)
-	GetRevertForm() (ui.Form, error)
-	PostInstallForm(ui.PostFormInput) error
+//go:generate counterfeiter -o ./fakes/revert_staged_changes_service.go --fake-name RevertStagedChangesService . revertStagedChangesService
+type revertStagedChangesService interface {
+	RevertStagedChanges() (bool, error)
 }
 
-func NewRevertStagedChanges(s dashboardService, l logger) RevertStagedChanges {
+func NewRevertStagedChanges(s revertStagedChangesService, l logger) RevertStagedChanges {
 	return RevertStagedChanges{service: s, logger: l}
 }
 
 func (c RevertStagedChanges) Execute(args []string) error {
-	form, err := c.service.GetRevertForm()
+	c.logger.Printf("reverting staged changes on the targeted Ops Manager")
+
+	reverted, err := c.service.RevertStagedChanges()
 	if err != nil {
-		return fmt.Errorf("could not fetch form: %s", err)
+		return fmt.Errorf("failed to revert staged changes: %s", err)
 	}
 
-	if form == (ui.Form{}) {
+	if !reverted {
+		c.logger.Printf("no staged changes to revert")
 		return nil
 	}
 
-	var formConfig CommonConfiguration
-	formConfig.AuthenticityToken = form.AuthenticityToken
-	formConfig.Method = "delete"
-	formConfig.Commit = "Confirm"
-
-	formValues, err := query.Values(formConfig)
-	if err != nil {
-		return err // cannot be tested
-	}
-
-	c.logger.Printf("reverting staged changes on the targeted Ops Manager")
-	err = c.service.PostInstallForm(ui.PostFormInput{Form: form, EncodedPayload: formValues.Encode()})
-	if err != nil {
-		return fmt.Errorf("failed to revert staged changes: %s", err)
-	}
 	c.logger.Printf("done")
 
 	return nil
@@ -56,7 +40,7 @@ func (c RevertStagedChanges) Execute(args []string) error {
 
 func (c RevertStagedChanges) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "reverts staged changes on the installation dashboard page in the target Ops Manager",
+		Description:      "This authenticated command reverts all staged changes on the Ops Manager targeted, leaving the configuration of the last successful installation.",
 		ShortDescription: "reverts staged changes on the Ops Manager targeted",
 	}
 }
@@ -7,7 +7,6 @@ import (
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/commands/fakes"
-	"github.com/pivotal-cf/om/ui"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -15,12 +14,12 @@ import (
 
 var _ = Describe("RevertStagedChanges", func() {
 	var (
-		service *fakes.DashboardService
+		service *fakes.RevertStagedChangesService
 		logger  *fakes.Logger
 	)
 
 	BeforeEach(func() {
-		service = &fakes.DashboardService{}
+		service = &fakes.RevertStagedChangesService{}
 		logger = &fakes.Logger{}
 	})
 
@@ -28,24 +27,12 @@ var _ = Describe("RevertStagedChanges", func() {
 		It("reverts staged changes on the targeted OpsMan", func() {
 			command := commands.NewRevertStagedChanges(service, logger)
 
-			service.GetRevertFormReturns(ui.Form{
-				Action:            "/installation",
-				AuthenticityToken: "some-auth-token",
-				RailsMethod:       "the-rails",
-			}, nil)
+			service.RevertStagedChangesReturns(true, nil)
 
 			err := command.Execute([]string{})
-
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(service.PostInstallFormArgsForCall(0)).To(Equal(ui.PostFormInput{
-				Form: ui.Form{
-					Action:            "/installation",
-					AuthenticityToken: "some-auth-token",
-					RailsMethod:       "the-rails",
-				},
-				EncodedPayload: "_method=delete&authenticity_token=some-auth-token&commit=Confirm",
-			}))
+			Expect(service.RevertStagedChangesCallCount()).To(Equal(1))
 
 			format, content := logger.PrintfArgsForCall(0)
 			Expect(fmt.Sprintf(format, content...)).To(Equal("reverting staged changes on the targeted Ops Manager"))
@@ -56,38 +43,25 @@ var _ = Describe("RevertStagedChanges", func() {
 		Context("when there are no staged changes to revert", func() {
 			It("returns without error", func() {
 				command := commands.NewRevertStagedChanges(service, logger)
-				service.GetRevertFormReturns(ui.Form{}, nil)
+
+				service.RevertStagedChangesReturns(false, nil)
+
 				err := command.Execute([]string{})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(service.PostInstallFormCallCount()).To(Equal(0))
+
+				format, content := logger.PrintfArgsForCall(1)
+				Expect(fmt.Sprintf(format, content...)).To(Equal("no staged changes to revert"))
 			})
 		})
 
 		Context("error cases", func() {
-			Context("when the form can't be fetched", func() {
-				It("returns an error", func() {
-					service.GetRevertFormReturns(ui.Form{}, errors.New("meow meow meow"))
-
-					command := commands.NewRevertStagedChanges(service, logger)
-
-					err := command.Execute([]string{""})
-					Expect(err).To(MatchError("could not fetch form: meow meow meow"))
-				})
-			})
-
-			Context("when the form can't be posted", func() {
+			Context("when the staged changes can't be reverted", func() {
 				It("returns an error", func() {
-					service.GetRevertFormReturns(ui.Form{
-						Action:            "/installation",
-						AuthenticityToken: "some-auth-token",
-						RailsMethod:       "the-rails",
-					}, nil)
-
-					service.PostInstallFormReturns(errors.New("meow meow meow"))
+					service.RevertStagedChangesReturns(false, errors.New("meow meow meow"))
 
 					command := commands.NewRevertStagedChanges(service, logger)
 
-					err := command.Execute([]string{""})
+					err := command.Execute([]string{})
 					Expect(err).To(MatchError("failed to revert staged changes: meow meow meow"))
 				})
 			})
@@ -95,11 +69,10 @@ var _ = Describe("RevertStagedChanges", func() {
 	})
 
 	Describe("Usage", func() {
-		It("returns the usage for the command", func() {
+		It("returns usage information for the command", func() {
 			command := commands.NewRevertStagedChanges(nil, nil)
-
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
-				Description:      "reverts staged changes on the installation dashboard page in the target Ops Manager",
+				Description:      "This authenticated command reverts all staged changes on the Ops Manager targeted, leaving the configuration of the last successful installation.",
 				ShortDescription: "reverts staged changes on the Ops Manager targeted",
 			}))
 		})
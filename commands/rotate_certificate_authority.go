@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+const (
+	rotationPhaseStatus   = "status"
+	rotationPhaseCreate   = "create"
+	rotationPhaseActivate = "activate"
+	rotationPhaseCleanup  = "cleanup"
+)
+
+type RotateCertificateAuthority struct {
+	service   rotateCertificateAuthorityService
+	presenter presenters.FormattedPresenter
+	logger    logger
+	Options   struct {
+		Phase      string `long:"phase"           required:"true" description:"phase of the rotation to run (options: status,create,activate,cleanup)"`
+		CertPem    string `long:"certificate-pem"                 description:"certificate of the new certificate authority, generated by Ops Manager if not given (create phase)"`
+		PrivateKey string `long:"private-key-pem"                 description:"private key of the new certificate authority (create phase)"`
+		Format     string `long:"format"          short:"f"       default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/rotate_certificate_authority_service.go --fake-name RotateCertificateAuthorityService . rotateCertificateAuthorityService
+type rotateCertificateAuthorityService interface {
+	ListCertificateAuthorities() (api.CertificateAuthoritiesOutput, error)
+	GenerateCertificateAuthority() (api.CA, error)
+	CreateCertificateAuthority(api.CertificateAuthorityInput) (api.CA, error)
+	ActivateCertificateAuthority(api.ActivateCertificateAuthorityInput) error
+	RegenerateCertificates() error
+	DeleteCertificateAuthority(api.DeleteCertificateAuthorityInput) error
+}
+
+func NewRotateCertificateAuthority(service rotateCertificateAuthorityService, presenter presenters.FormattedPresenter, logger logger) RotateCertificateAuthority {
+	return RotateCertificateAuthority{service: service, presenter: presenter, logger: logger}
+}
+
+func (r RotateCertificateAuthority) Execute(args []string) error {
+	if _, err := jhanda.Parse(&r.Options, args); err != nil {
+		return fmt.Errorf("could not parse rotate-certificate-authority flags: %s", err)
+	}
+
+	if (r.Options.CertPem == "") != (r.Options.PrivateKey == "") {
+		return errors.New("--certificate-pem and --private-key-pem must be given together")
+	}
+
+	if r.Options.CertPem != "" && r.Options.Phase != rotationPhaseCreate {
+		return fmt.Errorf("--certificate-pem and --private-key-pem can only be given to the %s phase", rotationPhaseCreate)
+	}
+
+	cas, err := r.service.ListCertificateAuthorities()
+	if err != nil {
+		return fmt.Errorf("could not list the certificate authorities: %s", err)
+	}
+
+	inactive := inactiveCertificateAuthorities(cas.CAs)
+
+	switch r.Options.Phase {
+	case rotationPhaseStatus:
+		r.logger.Println(nextRotationStep(inactive))
+	case rotationPhaseCreate:
+		err = r.create(inactive)
+	case rotationPhaseActivate:
+		err = r.activate(cas.CAs, inactive)
+	case rotationPhaseCleanup:
+		err = r.cleanup(inactive)
+	default:
+		return fmt.Errorf("unknown phase %q, expected one of: %s", r.Options.Phase, strings.Join([]string{
+			rotationPhaseStatus, rotationPhaseCreate, rotationPhaseActivate, rotationPhaseCleanup,
+		}, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	if r.Options.Phase != rotationPhaseStatus {
+		cas, err = r.service.ListCertificateAuthorities()
+		if err != nil {
+			return fmt.Errorf("could not list the certificate authorities: %s", err)
+		}
+	}
+
+	r.presenter.SetFormat(r.Options.Format)
+	r.presenter.PresentCertificateAuthorities(cas.CAs)
+
+	return nil
+}
+
+func (r RotateCertificateAuthority) create(inactive []api.CA) error {
+	if len(inactive) > 0 {
+		return fmt.Errorf("a rotation is already in progress, the certificate authority '%s' is inactive: run the %s or the %s phase first", inactive[0].GUID, rotationPhaseActivate, rotationPhaseCleanup)
+	}
+
+	var (
+		ca  api.CA
+		err error
+	)
+	if r.Options.CertPem != "" {
+		ca, err = r.service.CreateCertificateAuthority(api.CertificateAuthorityInput{
+			CertPem:       r.Options.CertPem,
+			PrivateKeyPem: r.Options.PrivateKey,
+		})
+	} else {
+		ca, err = r.service.GenerateCertificateAuthority()
+	}
+	if err != nil {
+		return fmt.Errorf("could not create the new certificate authority: %s", err)
+	}
+
+	r.logger.Printf("Created the new certificate authority '%s'.\n", ca.GUID)
+	r.logger.Printf("Run apply-changes so that it is trusted by every VM, then run the %s phase.\n", rotationPhaseActivate)
+
+	return nil
+}
+
+func (r RotateCertificateAuthority) activate(cas, inactive []api.CA) error {
+	switch len(inactive) {
+	case 0:
+		return fmt.Errorf("there is no new certificate authority to activate, run the %s phase first", rotationPhaseCreate)
+	case 1:
+	default:
+		return fmt.Errorf("there are %d inactive certificate authorities, activate the new one with activate-certificate-authority", len(inactive))
+	}
+
+	// after the activation, the inactive certificate authority is the old one,
+	// which must not be activated again
+	for _, ca := range cas {
+		if ca.Active && inactive[0].CreatedOn < ca.CreatedOn {
+			return fmt.Errorf("the inactive certificate authority '%s' is older than the active one, the new certificate authority has already been activated: run the %s phase", inactive[0].GUID, rotationPhaseCleanup)
+		}
+	}
+
+	err := r.service.ActivateCertificateAuthority(api.ActivateCertificateAuthorityInput{GUID: inactive[0].GUID})
+	if err != nil {
+		return fmt.Errorf("could not activate the certificate authority '%s': %s", inactive[0].GUID, err)
+	}
+	r.logger.Printf("Activated the certificate authority '%s'.\n", inactive[0].GUID)
+
+	err = r.service.RegenerateCertificates()
+	if err != nil {
+		return fmt.Errorf("could not regenerate the certificates: %s", err)
+	}
+	r.logger.Printf("Deleted the non-configurable certificates, so that they are signed by the new certificate authority.\n")
+	r.logger.Printf("Run apply-changes to regenerate them, then run the %s phase.\n", rotationPhaseCleanup)
+
+	return nil
+}
+
+func (r RotateCertificateAuthority) cleanup(inactive []api.CA) error {
+	if len(inactive) == 0 {
+		r.logger.Printf("There are no inactive certificate authorities to delete.\n")
+		return nil
+	}
+
+	for _, ca := range inactive {
+		err := r.service.DeleteCertificateAuthority(api.DeleteCertificateAuthorityInput{GUID: ca.GUID})
+		if err != nil {
+			return fmt.Errorf("could not delete the certificate authority '%s': %s", ca.GUID, err)
+		}
+		r.logger.Printf("Deleted the certificate authority '%s'.\n", ca.GUID)
+	}
+
+	r.logger.Printf("Run apply-changes so that the old certificate authority is no longer trusted.\n")
+
+	return nil
+}
+
+// nextRotationStep describes the phase to run next. An inactive certificate
+// authority is either the new one, waiting to be activated, or the old one,
+// waiting to be deleted; they cannot be told apart, so both are mentioned.
+func nextRotationStep(inactive []api.CA) string {
+	if len(inactive) == 0 {
+		return fmt.Sprintf("No rotation in progress, run the %s phase to start one.", rotationPhaseCreate)
+	}
+
+	return fmt.Sprintf("Rotation in progress: run the %s phase if the newest certificate authority is not active yet, or the %s phase once the certificates have been regenerated.", rotationPhaseActivate, rotationPhaseCleanup)
+}
+
+func inactiveCertificateAuthorities(cas []api.CA) []api.CA {
+	var inactive []api.CA
+	for _, ca := range cas {
+		if !ca.Active {
+			inactive = append(inactive, ca)
+		}
+	}
+
+	return inactive
+}
+
+func (r RotateCertificateAuthority) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command rotates the certificate authority of the Ops Manager, one phase at a time: create a new certificate authority, activate it and delete the non-configurable certificates so they are regenerated, then delete the old certificate authority. Run apply-changes between the phases.",
+		ShortDescription: "rotates the certificate authority of the Ops Manager",
+		Flags:            r.Options,
+	}
+}
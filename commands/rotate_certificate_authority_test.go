@@ -0,0 +1,279 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("RotateCertificateAuthority", func() {
+	var (
+		fakePresenter *presenterfakes.FormattedPresenter
+		fakeService   *fakes.RotateCertificateAuthorityService
+		logger        *fakes.Logger
+		command       commands.RotateCertificateAuthority
+
+		activeCA, inactiveCA api.CA
+	)
+
+	logged := func() []string {
+		var lines []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, args := logger.PrintfArgsForCall(i)
+			lines = append(lines, fmt.Sprintf(format, args...))
+		}
+		for i := 0; i < logger.PrintlnCallCount(); i++ {
+			lines = append(lines, fmt.Sprint(logger.PrintlnArgsForCall(i)...))
+		}
+		return lines
+	}
+
+	BeforeEach(func() {
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		fakeService = &fakes.RotateCertificateAuthorityService{}
+		logger = &fakes.Logger{}
+		command = commands.NewRotateCertificateAuthority(fakeService, fakePresenter, logger)
+
+		activeCA = api.CA{GUID: "active-guid", CreatedOn: "2017-01-09", Active: true}
+		inactiveCA = api.CA{GUID: "inactive-guid", CreatedOn: "2018-01-09", Active: false}
+	})
+
+	Describe("Execute", func() {
+		Context("the status phase", func() {
+			It("presents the certificate authorities and the next phase to run", func() {
+				fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA}}, nil)
+
+				err := command.Execute([]string{"--phase", "status", "--format", "json"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logged()).To(ConsistOf("No rotation in progress, run the create phase to start one."))
+
+				Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+				Expect(fakePresenter.PresentCertificateAuthoritiesArgsForCall(0)).To(Equal([]api.CA{activeCA}))
+
+				Expect(fakeService.GenerateCertificateAuthorityCallCount()).To(Equal(0))
+				Expect(fakeService.ActivateCertificateAuthorityCallCount()).To(Equal(0))
+				Expect(fakeService.DeleteCertificateAuthorityCallCount()).To(Equal(0))
+			})
+
+			It("mentions the phases to continue a rotation in progress", func() {
+				fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA, inactiveCA}}, nil)
+
+				err := command.Execute([]string{"--phase", "status"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logged()[0]).To(ContainSubstring("run the activate phase"))
+				Expect(logged()[0]).To(ContainSubstring("or the cleanup phase"))
+			})
+		})
+
+		Context("the create phase", func() {
+			BeforeEach(func() {
+				fakeService.ListCertificateAuthoritiesReturnsOnCall(0, api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA}}, nil)
+				fakeService.ListCertificateAuthoritiesReturnsOnCall(1, api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA, inactiveCA}}, nil)
+				fakeService.GenerateCertificateAuthorityReturns(inactiveCA, nil)
+				fakeService.CreateCertificateAuthorityReturns(inactiveCA, nil)
+			})
+
+			It("generates a new certificate authority and presents the certificate authorities", func() {
+				err := command.Execute([]string{"--phase", "create"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeService.GenerateCertificateAuthorityCallCount()).To(Equal(1))
+				Expect(fakeService.CreateCertificateAuthorityCallCount()).To(Equal(0))
+				Expect(fakeService.ActivateCertificateAuthorityCallCount()).To(Equal(0))
+
+				Expect(logged()).To(Equal([]string{
+					"Created the new certificate authority 'inactive-guid'.\n",
+					"Run apply-changes so that it is trusted by every VM, then run the activate phase.\n",
+				}))
+
+				Expect(fakePresenter.PresentCertificateAuthoritiesArgsForCall(0)).To(Equal([]api.CA{activeCA, inactiveCA}))
+			})
+
+			It("creates the new certificate authority from the certificate and private key given", func() {
+				err := command.Execute([]string{
+					"--phase", "create",
+					"--certificate-pem", "some-cert",
+					"--private-key-pem", "some-key",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeService.GenerateCertificateAuthorityCallCount()).To(Equal(0))
+				Expect(fakeService.CreateCertificateAuthorityArgsForCall(0)).To(Equal(api.CertificateAuthorityInput{
+					CertPem:       "some-cert",
+					PrivateKeyPem: "some-key",
+				}))
+			})
+
+			Context("when a rotation is already in progress", func() {
+				It("returns an error", func() {
+					fakeService.ListCertificateAuthoritiesReturnsOnCall(0, api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA, inactiveCA}}, nil)
+
+					err := command.Execute([]string{"--phase", "create"})
+					Expect(err).To(MatchError("a rotation is already in progress, the certificate authority 'inactive-guid' is inactive: run the activate or the cleanup phase first"))
+
+					Expect(fakeService.GenerateCertificateAuthorityCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the certificate authority cannot be generated", func() {
+				It("returns an error", func() {
+					fakeService.GenerateCertificateAuthorityReturns(api.CA{}, errors.New("some error"))
+
+					err := command.Execute([]string{"--phase", "create"})
+					Expect(err).To(MatchError("could not create the new certificate authority: some error"))
+				})
+			})
+		})
+
+		Context("the activate phase", func() {
+			BeforeEach(func() {
+				fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA, inactiveCA}}, nil)
+			})
+
+			It("activates the new certificate authority and regenerates the certificates", func() {
+				err := command.Execute([]string{"--phase", "activate"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeService.ActivateCertificateAuthorityCallCount()).To(Equal(1))
+				Expect(fakeService.ActivateCertificateAuthorityArgsForCall(0)).To(Equal(api.ActivateCertificateAuthorityInput{GUID: "inactive-guid"}))
+				Expect(fakeService.RegenerateCertificatesCallCount()).To(Equal(1))
+				Expect(fakeService.DeleteCertificateAuthorityCallCount()).To(Equal(0))
+
+				Expect(logged()).To(ContainElement("Run apply-changes to regenerate them, then run the cleanup phase.\n"))
+				Expect(fakePresenter.PresentCertificateAuthoritiesCallCount()).To(Equal(1))
+			})
+
+			Context("when there is no new certificate authority", func() {
+				It("returns an error", func() {
+					fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA}}, nil)
+
+					err := command.Execute([]string{"--phase", "activate"})
+					Expect(err).To(MatchError("there is no new certificate authority to activate, run the create phase first"))
+				})
+			})
+
+			Context("when there are several inactive certificate authorities", func() {
+				It("returns an error", func() {
+					fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA, inactiveCA, inactiveCA}}, nil)
+
+					err := command.Execute([]string{"--phase", "activate"})
+					Expect(err).To(MatchError("there are 2 inactive certificate authorities, activate the new one with activate-certificate-authority"))
+					Expect(fakeService.ActivateCertificateAuthorityCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the new certificate authority has already been activated", func() {
+				It("does not activate the old one again", func() {
+					oldCA := api.CA{GUID: "old-guid", CreatedOn: "2016-01-09", Active: false}
+					fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{oldCA, activeCA}}, nil)
+
+					err := command.Execute([]string{"--phase", "activate"})
+					Expect(err).To(MatchError("the inactive certificate authority 'old-guid' is older than the active one, the new certificate authority has already been activated: run the cleanup phase"))
+					Expect(fakeService.ActivateCertificateAuthorityCallCount()).To(Equal(0))
+					Expect(fakeService.RegenerateCertificatesCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the certificate authority cannot be activated", func() {
+				It("does not regenerate the certificates", func() {
+					fakeService.ActivateCertificateAuthorityReturns(errors.New("some error"))
+
+					err := command.Execute([]string{"--phase", "activate"})
+					Expect(err).To(MatchError("could not activate the certificate authority 'inactive-guid': some error"))
+					Expect(fakeService.RegenerateCertificatesCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the certificates cannot be regenerated", func() {
+				It("returns an error", func() {
+					fakeService.RegenerateCertificatesReturns(errors.New("some error"))
+
+					err := command.Execute([]string{"--phase", "activate"})
+					Expect(err).To(MatchError("could not regenerate the certificates: some error"))
+				})
+			})
+		})
+
+		Context("the cleanup phase", func() {
+			It("deletes the inactive certificate authorities", func() {
+				fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA, inactiveCA}}, nil)
+
+				err := command.Execute([]string{"--phase", "cleanup"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeService.DeleteCertificateAuthorityCallCount()).To(Equal(1))
+				Expect(fakeService.DeleteCertificateAuthorityArgsForCall(0)).To(Equal(api.DeleteCertificateAuthorityInput{GUID: "inactive-guid"}))
+				Expect(logged()).To(ContainElement("Deleted the certificate authority 'inactive-guid'.\n"))
+			})
+
+			It("does nothing when there are no inactive certificate authorities", func() {
+				fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA}}, nil)
+
+				err := command.Execute([]string{"--phase", "cleanup"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeService.DeleteCertificateAuthorityCallCount()).To(Equal(0))
+				Expect(logged()).To(ConsistOf("There are no inactive certificate authorities to delete.\n"))
+			})
+
+			Context("when a certificate authority cannot be deleted", func() {
+				It("returns an error", func() {
+					fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{CAs: []api.CA{activeCA, inactiveCA}}, nil)
+					fakeService.DeleteCertificateAuthorityReturns(errors.New("some error"))
+
+					err := command.Execute([]string{"--phase", "cleanup"})
+					Expect(err).To(MatchError("could not delete the certificate authority 'inactive-guid': some error"))
+				})
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when an unknown flag is passed", func() {
+				err := command.Execute([]string{"--unknown-flag"})
+				Expect(err).To(MatchError("could not parse rotate-certificate-authority flags: flag provided but not defined: -unknown-flag"))
+			})
+
+			It("returns an error on an unknown phase", func() {
+				err := command.Execute([]string{"--phase", "banana"})
+				Expect(err).To(MatchError(`unknown phase "banana", expected one of: status, create, activate, cleanup`))
+			})
+
+			It("returns an error when only the certificate is given", func() {
+				err := command.Execute([]string{"--phase", "create", "--certificate-pem", "some-cert"})
+				Expect(err).To(MatchError("--certificate-pem and --private-key-pem must be given together"))
+			})
+
+			It("returns an error when the certificate is given to another phase", func() {
+				err := command.Execute([]string{"--phase", "activate", "--certificate-pem", "some-cert", "--private-key-pem", "some-key"})
+				Expect(err).To(MatchError("--certificate-pem and --private-key-pem can only be given to the create phase"))
+			})
+
+			It("returns an error when the certificate authorities cannot be listed", func() {
+				fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{"--phase", "status"})
+				Expect(err).To(MatchError("could not list the certificate authorities: some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage info", func() {
+			usage := command.Usage()
+			Expect(usage).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command rotates the certificate authority of the Ops Manager, one phase at a time: create a new certificate authority, activate it and delete the non-configurable certificates so they are regenerated, then delete the old certificate authority. Run apply-changes between the phases.",
+				ShortDescription: "rotates the certificate authority of the Ops Manager",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+// the phases that a rotation of the director certificates is in, as
+// reported by Ops Manager
+const (
+	certificateRotationNone      = "none"
+	certificateRotationCreated   = "created"
+	certificateRotationActivated = "activated"
+)
+
+var directorCertificateTypes = []string{"nats", "bosh-dns"}
+
+type RotateDirectorCertificates struct {
+	service   rotateDirectorCertificatesService
+	presenter presenters.FormattedPresenter
+	logger    logger
+	Options   struct {
+		Type   string `long:"type"   required:"true" description:"certificates to rotate (options: nats,bosh-dns)"`
+		Phase  string `long:"phase"  required:"true" description:"phase of the rotation to run (options: status,create,activate,cleanup)"`
+		Format string `long:"format" short:"f"       default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/rotate_director_certificates_service.go --fake-name RotateDirectorCertificatesService . rotateDirectorCertificatesService
+type rotateDirectorCertificatesService interface {
+	ListCertificateRotations() (api.CertificateRotationsOutput, error)
+	StartCertificateRotationPhase(rotationType, phase string) (api.CertificateRotation, error)
+}
+
+func NewRotateDirectorCertificates(service rotateDirectorCertificatesService, presenter presenters.FormattedPresenter, logger logger) RotateDirectorCertificates {
+	return RotateDirectorCertificates{service: service, presenter: presenter, logger: logger}
+}
+
+func (r RotateDirectorCertificates) Execute(args []string) error {
+	if _, err := jhanda.Parse(&r.Options, args); err != nil {
+		return fmt.Errorf("could not parse rotate-director-certificates flags: %s", err)
+	}
+
+	if !containsString(directorCertificateTypes, r.Options.Type) {
+		return fmt.Errorf("unknown type %q, expected one of: %s", r.Options.Type, strings.Join(directorCertificateTypes, ", "))
+	}
+
+	// the phase that the rotation must be in for each phase to run
+	required := map[string]string{
+		rotationPhaseCreate:   certificateRotationNone,
+		rotationPhaseActivate: certificateRotationCreated,
+		rotationPhaseCleanup:  certificateRotationActivated,
+	}
+	if _, ok := required[r.Options.Phase]; !ok && r.Options.Phase != rotationPhaseStatus {
+		return fmt.Errorf("unknown phase %q, expected one of: %s", r.Options.Phase, strings.Join([]string{
+			rotationPhaseStatus, rotationPhaseCreate, rotationPhaseActivate, rotationPhaseCleanup,
+		}, ", "))
+	}
+
+	rotation, err := r.currentRotation()
+	if err != nil {
+		return err
+	}
+
+	if r.Options.Phase == rotationPhaseStatus {
+		r.logger.Println(nextDirectorRotationStep(rotation))
+	} else {
+		if rotation.ApplyChangesRequired {
+			return fmt.Errorf("the %s phase of the rotation of the %s certificates has not been applied yet: run apply-changes first", completedRotationPhase(rotation), r.Options.Type)
+		}
+
+		if rotation.Phase != required[r.Options.Phase] {
+			return fmt.Errorf("the %s phase cannot be run, the rotation of the %s certificates is %s: %s", r.Options.Phase, r.Options.Type, rotation.Phase, nextDirectorRotationStep(rotation))
+		}
+
+		rotation, err = r.service.StartCertificateRotationPhase(r.Options.Type, r.Options.Phase)
+		if err != nil {
+			return fmt.Errorf("could not run the %s phase of the rotation of the %s certificates: %s", r.Options.Phase, r.Options.Type, err)
+		}
+
+		r.logger.Printf("Ran the %s phase of the rotation of the %s certificates.\n", r.Options.Phase, r.Options.Type)
+		r.logger.Println(nextDirectorRotationStep(rotation))
+	}
+
+	r.presenter.SetFormat(r.Options.Format)
+	r.presenter.PresentCertificateRotations([]api.CertificateRotation{rotation})
+
+	return nil
+}
+
+func (r RotateDirectorCertificates) currentRotation() (api.CertificateRotation, error) {
+	output, err := r.service.ListCertificateRotations()
+	if err != nil {
+		return api.CertificateRotation{}, fmt.Errorf("could not list the certificate rotations: %s", err)
+	}
+
+	for _, rotation := range output.Rotations {
+		if rotation.Type == r.Options.Type {
+			if rotation.Phase == "" {
+				rotation.Phase = certificateRotationNone
+			}
+			return rotation, nil
+		}
+	}
+
+	return api.CertificateRotation{}, fmt.Errorf("the rotation of the %s certificates is not supported by this version of Ops Manager", r.Options.Type)
+}
+
+// completedRotationPhase is the phase that brought the rotation to its
+// current phase; a rotation is back to none once it has been cleaned up.
+func completedRotationPhase(rotation api.CertificateRotation) string {
+	switch rotation.Phase {
+	case certificateRotationCreated:
+		return rotationPhaseCreate
+	case certificateRotationActivated:
+		return rotationPhaseActivate
+	default:
+		return rotationPhaseCleanup
+	}
+}
+
+func nextDirectorRotationStep(rotation api.CertificateRotation) string {
+	if rotation.ApplyChangesRequired {
+		return fmt.Sprintf("Run apply-changes to apply the %s phase of the rotation of the %s certificates.", completedRotationPhase(rotation), rotation.Type)
+	}
+
+	switch rotation.Phase {
+	case certificateRotationCreated:
+		return fmt.Sprintf("Rotation in progress: the new certificate authority is trusted by every VM, run the %s phase.", rotationPhaseActivate)
+	case certificateRotationActivated:
+		return fmt.Sprintf("Rotation in progress: the certificates are signed by the new certificate authority, run the %s phase.", rotationPhaseCleanup)
+	default:
+		return fmt.Sprintf("No rotation in progress, run the %s phase to start one.", rotationPhaseCreate)
+	}
+}
+
+func (r RotateDirectorCertificates) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command rotates the NATS or the BOSH DNS certificates of the director, one phase at a time: create a new certificate authority trusted along with the old one, activate it so that the certificates are signed by it, then clean up the old certificate authority. Run apply-changes after every phase; a phase is refused until the previous one has been applied.",
+		ShortDescription: "rotates the NATS or BOSH DNS certificates of the director",
+		Flags:            r.Options,
+	}
+}
@@ -0,0 +1,185 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RotateDirectorCertificates", func() {
+	var (
+		fakePresenter *presenterfakes.FormattedPresenter
+		fakeService   *fakes.RotateDirectorCertificatesService
+		logger        *fakes.Logger
+		command       commands.RotateDirectorCertificates
+	)
+
+	logged := func() []string {
+		var lines []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, args := logger.PrintfArgsForCall(i)
+			lines = append(lines, fmt.Sprintf(format, args...))
+		}
+		for i := 0; i < logger.PrintlnCallCount(); i++ {
+			lines = append(lines, fmt.Sprint(logger.PrintlnArgsForCall(i)...))
+		}
+		return lines
+	}
+
+	rotationsOf := func(rotations ...api.CertificateRotation) api.CertificateRotationsOutput {
+		return api.CertificateRotationsOutput{Rotations: append([]api.CertificateRotation{
+			{Type: "bosh-dns", Phase: "none"},
+		}, rotations...)}
+	}
+
+	BeforeEach(func() {
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		fakeService = &fakes.RotateDirectorCertificatesService{}
+		logger = &fakes.Logger{}
+		command = commands.NewRotateDirectorCertificates(fakeService, fakePresenter, logger)
+	})
+
+	Context("the status phase", func() {
+		It("presents the rotation and the next phase to run", func() {
+			fakeService.ListCertificateRotationsReturns(rotationsOf(api.CertificateRotation{Type: "nats", Phase: "created"}), nil)
+
+			err := command.Execute([]string{"--type", "nats", "--phase", "status", "--format", "json"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logged()).To(ConsistOf("Rotation in progress: the new certificate authority is trusted by every VM, run the activate phase."))
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+			Expect(fakePresenter.PresentCertificateRotationsArgsForCall(0)).To(Equal([]api.CertificateRotation{{Type: "nats", Phase: "created"}}))
+
+			Expect(fakeService.StartCertificateRotationPhaseCallCount()).To(Equal(0))
+		})
+
+		It("asks for apply-changes when the last phase has not been applied", func() {
+			fakeService.ListCertificateRotationsReturns(rotationsOf(api.CertificateRotation{Type: "nats", Phase: "activated", ApplyChangesRequired: true}), nil)
+
+			err := command.Execute([]string{"--type", "nats", "--phase", "status"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logged()).To(ConsistOf("Run apply-changes to apply the activate phase of the rotation of the nats certificates."))
+		})
+
+		It("treats a rotation without a phase as not started", func() {
+			fakeService.ListCertificateRotationsReturns(rotationsOf(api.CertificateRotation{Type: "nats"}), nil)
+
+			err := command.Execute([]string{"--type", "nats", "--phase", "status"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logged()).To(ConsistOf("No rotation in progress, run the create phase to start one."))
+			Expect(fakePresenter.PresentCertificateRotationsArgsForCall(0)).To(Equal([]api.CertificateRotation{{Type: "nats", Phase: "none"}}))
+		})
+	})
+
+	DescribeTable("running a phase",
+		func(phase, currentPhase, newPhase, completed string) {
+			fakeService.ListCertificateRotationsReturns(rotationsOf(api.CertificateRotation{Type: "nats", Phase: currentPhase}), nil)
+			fakeService.StartCertificateRotationPhaseReturns(api.CertificateRotation{Type: "nats", Phase: newPhase, ApplyChangesRequired: true}, nil)
+
+			err := command.Execute([]string{"--type", "nats", "--phase", phase})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.StartCertificateRotationPhaseCallCount()).To(Equal(1))
+			rotationType, startedPhase := fakeService.StartCertificateRotationPhaseArgsForCall(0)
+			Expect(rotationType).To(Equal("nats"))
+			Expect(startedPhase).To(Equal(phase))
+
+			Expect(logged()).To(ConsistOf(
+				fmt.Sprintf("Ran the %s phase of the rotation of the nats certificates.\n", phase),
+				fmt.Sprintf("Run apply-changes to apply the %s phase of the rotation of the nats certificates.", completed),
+			))
+			Expect(fakePresenter.PresentCertificateRotationsArgsForCall(0)).To(Equal([]api.CertificateRotation{{Type: "nats", Phase: newPhase, ApplyChangesRequired: true}}))
+		},
+		Entry("create", "create", "none", "created", "create"),
+		Entry("activate", "activate", "created", "activated", "activate"),
+		Entry("cleanup", "cleanup", "activated", "none", "cleanup"),
+	)
+
+	It("rotates the bosh-dns certificates", func() {
+		fakeService.ListCertificateRotationsReturns(rotationsOf(), nil)
+
+		err := command.Execute([]string{"--type", "bosh-dns", "--phase", "create"})
+		Expect(err).NotTo(HaveOccurred())
+
+		rotationType, _ := fakeService.StartCertificateRotationPhaseArgsForCall(0)
+		Expect(rotationType).To(Equal("bosh-dns"))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the flags cannot be parsed", func() {
+			err := command.Execute([]string{"--unknown-flag"})
+			Expect(err).To(MatchError(ContainSubstring("could not parse rotate-director-certificates flags")))
+		})
+
+		It("returns an error when the type is unknown", func() {
+			err := command.Execute([]string{"--type", "uaa", "--phase", "status"})
+			Expect(err).To(MatchError(`unknown type "uaa", expected one of: nats, bosh-dns`))
+		})
+
+		It("returns an error when the phase is unknown", func() {
+			err := command.Execute([]string{"--type", "nats", "--phase", "rotate"})
+			Expect(err).To(MatchError(`unknown phase "rotate", expected one of: status, create, activate, cleanup`))
+		})
+
+		It("returns an error when the rotations cannot be listed", func() {
+			fakeService.ListCertificateRotationsReturns(api.CertificateRotationsOutput{}, errors.New("network error"))
+
+			err := command.Execute([]string{"--type", "nats", "--phase", "status"})
+			Expect(err).To(MatchError("could not list the certificate rotations: network error"))
+		})
+
+		It("returns an error when Ops Manager does not support the rotation", func() {
+			fakeService.ListCertificateRotationsReturns(rotationsOf(), nil)
+
+			err := command.Execute([]string{"--type", "nats", "--phase", "status"})
+			Expect(err).To(MatchError("the rotation of the nats certificates is not supported by this version of Ops Manager"))
+		})
+
+		It("refuses a phase until the previous one has been applied", func() {
+			fakeService.ListCertificateRotationsReturns(rotationsOf(api.CertificateRotation{Type: "nats", Phase: "created", ApplyChangesRequired: true}), nil)
+
+			err := command.Execute([]string{"--type", "nats", "--phase", "activate"})
+			Expect(err).To(MatchError("the create phase of the rotation of the nats certificates has not been applied yet: run apply-changes first"))
+
+			Expect(fakeService.StartCertificateRotationPhaseCallCount()).To(Equal(0))
+		})
+
+		It("refuses a phase out of order", func() {
+			fakeService.ListCertificateRotationsReturns(rotationsOf(api.CertificateRotation{Type: "nats", Phase: "none"}), nil)
+
+			err := command.Execute([]string{"--type", "nats", "--phase", "cleanup"})
+			Expect(err).To(MatchError("the cleanup phase cannot be run, the rotation of the nats certificates is none: No rotation in progress, run the create phase to start one."))
+
+			Expect(fakeService.StartCertificateRotationPhaseCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the phase cannot be run", func() {
+			fakeService.ListCertificateRotationsReturns(rotationsOf(api.CertificateRotation{Type: "nats", Phase: "created"}), nil)
+			fakeService.StartCertificateRotationPhaseReturns(api.CertificateRotation{}, errors.New("server error"))
+
+			err := command.Execute([]string{"--type", "nats", "--phase", "activate"})
+			Expect(err).To(MatchError("could not run the activate phase of the rotation of the nats certificates: server error"))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			usage := command.Usage()
+			Expect(usage.ShortDescription).To(Equal("rotates the NATS or BOSH DNS certificates of the director"))
+			Expect(usage.Flags).To(BeAssignableToTypeOf(command.Options))
+			Expect(usage).To(BeAssignableToTypeOf(jhanda.Usage{}))
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -25,17 +26,97 @@ type Config interface {
 type Stower interface {
 	Dial(kind string, config Config) (stow.Location, error)
 	Walk(container stow.Container, prefix string, pageSize int, fn stow.WalkFunc) error
+	// BucketRegion is the region of the AWS bucket, looked up in the
+	// partition of the region hint
+	BucketRegion(bucket, regionHint string) (string, error)
 }
 
 type S3Configuration struct {
 	Bucket          string `yaml:"bucket" validate:"required"`
 	AccessKeyID     string `yaml:"access-key-id" validate:"required"`
 	SecretAccessKey string `yaml:"secret-access-key" validate:"required"`
-	RegionName      string `yaml:"region-name" validate:"required"`
+	RegionName      string `yaml:"region-name"`
 	Endpoint        string `yaml:"endpoint"`
+	Partition       string `yaml:"partition"`
 	DisableSSL      bool   `yaml:"disable-ssl"`
 	EnableV2Signing bool   `yaml:"enable-v2-signing"`
 	Path            string `yaml:"path"`
+	// SlugAliases are the other slugs the files of a product were persisted
+	// under, by the slug that references the product, e.g. after the product
+	// was renamed on Pivotal Network
+	SlugAliases map[string][]string `yaml:"slug-aliases"`
+}
+
+// s3Partition is a group of AWS regions that share the domain of their
+// endpoints, e.g. GovCloud or China.
+type s3Partition struct {
+	id        string
+	dnsSuffix string
+	regions   *regexp.Regexp
+	// signingRegion is the region requests are signed for when the
+	// partition is given without one of its regions
+	signingRegion string
+	// resolvedByAWS is whether the aws sdk knows the endpoints of the
+	// partition; the endpoint is constructed explicitly otherwise
+	resolvedByAWS bool
+}
+
+// awsRegionPattern matches the names of the AWS regions, any of them that
+// is of no other partition is a commercial region
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// s3Partitions are in the order they are matched against a region, as the
+// GovCloud and ISO regions are us- regions as well
+var s3Partitions = []s3Partition{
+	{id: "aws-us-gov", dnsSuffix: "amazonaws.com", regions: regexp.MustCompile(`^us-gov-\w+-\d+$`), signingRegion: "us-gov-west-1", resolvedByAWS: true},
+	{id: "aws-iso-b", dnsSuffix: "sc2s.sgov.gov", regions: regexp.MustCompile(`^us-isob-\w+-\d+$`), signingRegion: "us-isob-east-1"},
+	{id: "aws-iso", dnsSuffix: "c2s.ic.gov", regions: regexp.MustCompile(`^us-iso-\w+-\d+$`), signingRegion: "us-iso-east-1"},
+	{id: "aws-cn", dnsSuffix: "amazonaws.com.cn", regions: regexp.MustCompile(`^cn-\w+-\d+$`), signingRegion: "cn-north-1", resolvedByAWS: true},
+	{id: "aws", dnsSuffix: "amazonaws.com", regions: awsRegionPattern, signingRegion: "us-east-1", resolvedByAWS: true},
+}
+
+// defaultRegionHint is the region the region of a bucket is looked up from
+// when the configured region is not an AWS region
+const defaultRegionHint = "us-east-1"
+
+// s3Endpoint is the endpoint to reach the bucket through, and the region
+// requests are signed for. The partition is determined from the region
+// unless it is given, in which case a region-name that is not an AWS region,
+// e.g. with a custom endpoint, is signed for as the partition.
+func s3Endpoint(config S3Configuration) (string, string, error) {
+	region := config.RegionName
+
+	var partition s3Partition
+	if config.Partition != "" {
+		var ids []string
+		for _, p := range s3Partitions {
+			ids = append(ids, p.id)
+			if p.id == config.Partition {
+				partition = p
+			}
+		}
+
+		if partition.id == "" {
+			return "", "", fmt.Errorf("unknown partition %q, expected one of: %s", config.Partition, strings.Join(ids, ", "))
+		}
+
+		if !awsRegionPattern.MatchString(region) {
+			region = partition.signingRegion
+		}
+	} else {
+		for _, p := range s3Partitions {
+			if p.regions.MatchString(region) {
+				partition = p
+				break
+			}
+		}
+	}
+
+	if config.Endpoint != "" || partition.id == "" || partition.resolvedByAWS {
+		return config.Endpoint, region, nil
+	}
+
+	return fmt.Sprintf("s3.%s.%s", region, partition.dnsSuffix), region, nil
 }
 
 type S3Client struct {
@@ -44,6 +125,10 @@ type S3Client struct {
 	Config         stow.Config
 	progressWriter io.Writer
 	path           string
+	// lookupRegion is whether the region of the bucket can be looked up, as
+	// only AWS, whose endpoints are resolved from the region, can tell it
+	lookupRegion bool
+	slugAliases  map[string][]string
 }
 
 func NewS3Client(stower Stower, config S3Configuration, progressWriter io.Writer) (*S3Client, error) {
@@ -53,13 +138,22 @@ func NewS3Client(stower Stower, config S3Configuration, progressWriter io.Writer
 		return nil, err
 	}
 
+	if config.RegionName == "" && config.Endpoint != "" && config.Partition == "" {
+		return nil, errors.New("region-name or partition is required with an endpoint")
+	}
+
+	endpoint, region, err := s3Endpoint(config)
+	if err != nil {
+		return nil, err
+	}
+
 	disableSSL := strconv.FormatBool(config.DisableSSL)
 	enableV2Signing := strconv.FormatBool(config.EnableV2Signing)
 	stowConfig := stow.ConfigMap{
 		s3.ConfigAccessKeyID: config.AccessKeyID,
 		s3.ConfigSecretKey:   config.SecretAccessKey,
-		s3.ConfigRegion:      config.RegionName,
-		s3.ConfigEndpoint:    config.Endpoint,
+		s3.ConfigRegion:      region,
+		s3.ConfigEndpoint:    endpoint,
 		s3.ConfigDisableSSL:  disableSSL,
 		s3.ConfigV2Signing:   enableV2Signing,
 	}
@@ -70,6 +164,8 @@ func NewS3Client(stower Stower, config S3Configuration, progressWriter io.Writer
 		bucket:         config.Bucket,
 		progressWriter: progressWriter,
 		path:           config.Path,
+		lookupRegion:   endpoint == "",
+		slugAliases:    config.SlugAliases,
 	}, nil
 }
 
@@ -80,9 +176,9 @@ func (s3 S3Client) GetAllProductVersions(slug string) ([]string, error) {
 	}
 
 	productFileCompiledRegex := regexp.MustCompile(
-		fmt.Sprintf(`^/?%s/?\[%s,(.*?)\]`,
+		fmt.Sprintf(`^/?%s/?\[(?:%s),(.*?)\]`,
 			regexp.QuoteMeta(strings.Trim(s3.path, "/")),
-			slug,
+			strings.Join(s3.slugs(slug), "|"),
 		),
 	)
 
@@ -113,19 +209,33 @@ func (s3 S3Client) GetLatestProductFile(slug, version, glob string) (*FileArtifa
 		return nil, err
 	}
 
-	validFile := regexp.MustCompile(
-		fmt.Sprintf(`^/?%s/?\[%s,%s\]`,
-			regexp.QuoteMeta(strings.Trim(s3.path, "/")),
-			slug,
-			regexp.QuoteMeta(version),
-		),
-	)
 	var prefixedFilepaths []string
 	var globMatchedFilepaths []string
 
-	for _, f := range files {
-		if validFile.MatchString(f) {
-			prefixedFilepaths = append(prefixedFilepaths, f)
+	// the files persisted under the slug are preferred to the ones persisted
+	// under its aliases, for a version persisted under both
+	for _, persistedSlug := range s3.slugs(slug) {
+		validFile := regexp.MustCompile(
+			fmt.Sprintf(`^/?%s/?\[%s,%s\]`,
+				regexp.QuoteMeta(strings.Trim(s3.path, "/")),
+				persistedSlug,
+				regexp.QuoteMeta(version),
+			),
+		)
+
+		for _, f := range files {
+			// the sha256 persisted along with a file is not a file of the product
+			if strings.HasSuffix(f, sha256FileExtension) {
+				continue
+			}
+
+			if validFile.MatchString(f) {
+				prefixedFilepaths = append(prefixedFilepaths, f)
+			}
+		}
+
+		if len(prefixedFilepaths) > 0 {
+			break
 		}
 	}
 
@@ -148,7 +258,44 @@ func (s3 S3Client) GetLatestProductFile(slug, version, glob string) (*FileArtifa
 		return nil, fmt.Errorf("the glob '%s' matches no file", glob)
 	}
 
-	return &FileArtifact{Name: globMatchedFilepaths[0]}, nil
+	fileArtifact := &FileArtifact{Name: globMatchedFilepaths[0]}
+	if containsString(files, fileArtifact.Name+sha256FileExtension) {
+		fileArtifact.sha256, err = s3.readSHA256(fileArtifact.Name + sha256FileExtension)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the sha256 persisted with %s: %s", fileArtifact.Name, err)
+		}
+	}
+
+	return fileArtifact, nil
+}
+
+// slugs are the slug and its aliases, which the files of the product can be
+// persisted under
+func (s3 S3Client) slugs(slug string) []string {
+	return append([]string{slug}, s3.slugAliases[slug]...)
+}
+
+// readSHA256 reads the sha256 that was published by Pivotal Network for a
+// file, and persisted along with it, as written by download-product or by
+// sha256sum.
+func (s3 S3Client) readSHA256(filename string) (string, error) {
+	reader, _, err := s3.initializeBlobReader(filename)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(contents))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s is empty", filename)
+	}
+
+	return fields[0], nil
 }
 
 func (s3 S3Client) DownloadProductToFile(fa *FileArtifact, destinationFile *os.File) error {
@@ -167,30 +314,92 @@ func (s3 S3Client) DownloadProductToFile(fa *FileArtifact, destinationFile *os.F
 	return nil
 }
 
+func (s3 S3Client) DownloadProductStream(fa *FileArtifact) (io.ReadCloser, int64, error) {
+	return s3.initializeBlobReader(fa.Name)
+}
+
 func (s *S3Client) initializeBlobReader(filename string) (blobToRead io.ReadCloser, fileSize int64, err error) {
+	err = s.inBucketRegion(func() error {
+		container, err := s.container()
+		if err != nil {
+			return err
+		}
+		item, err := container.Item(filename)
+		if err != nil {
+			return err
+		}
+
+		fileSize, err = item.Size()
+		if err != nil {
+			return err
+		}
+		blobToRead, err = item.Open()
+		return err
+	})
+
+	return blobToRead, fileSize, err
+}
+
+func (s *S3Client) container() (stow.Container, error) {
 	location, err := s.stower.Dial("s3", s.Config)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	container, err := location.Container(s.bucket)
 	if err != nil {
 		endpoint, _ := s.Config.Config("endpoint")
 		if endpoint != "" {
-			return nil, 0, errors.New(fmt.Sprintf(InvalidEndpointErrorMessageTemplate, endpoint, err.Error()))
+			return nil, errors.New(fmt.Sprintf(InvalidEndpointErrorMessageTemplate, endpoint, err.Error()))
 		}
-		return nil, 0, err
+		return nil, err
 	}
-	item, err := container.Item(filename)
-	if err != nil {
-		return nil, 0, err
+
+	return container, nil
+}
+
+// inBucketRegion reads the bucket in its region. The region is looked up
+// when it is not configured, and when the bucket cannot be read in the
+// configured region, as AWS answers with a redirect or a malformed
+// authorization header error rather than the region of the bucket.
+func (s *S3Client) inBucketRegion(read func() error) error {
+	configured, _ := s.Config.Config(s3.ConfigRegion)
+	if s.lookupRegion && configured == "" {
+		region, err := s.stower.BucketRegion(s.bucket, defaultRegionHint)
+		if err != nil {
+			return fmt.Errorf("could not look up the region of the bucket %s, set region-name: %s", s.bucket, err)
+		}
+		s.Config.Set(s3.ConfigRegion, region)
+
+		return read()
+	}
+
+	err := read()
+	if err == nil || !s.lookupRegion {
+		return err
 	}
 
-	fileSize, err = item.Size()
+	hint := defaultRegionHint
+	for _, p := range s3Partitions {
+		if p.resolvedByAWS && p.regions.MatchString(configured) {
+			hint = configured
+			break
+		}
+	}
+
+	region, lookupErr := s.stower.BucketRegion(s.bucket, hint)
+	if lookupErr != nil || region == "" || region == configured {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(s.progressWriter, "warning: bucket %s is in region %s, you configured %s\n", s.bucket, region, configured)
+	s.Config.Set(s3.ConfigRegion, region)
+
+	err = read()
 	if err != nil {
-		return nil, 0, err
+		return fmt.Errorf("bucket %s is in region %s, you configured %s: %s", s.bucket, region, configured, err)
 	}
-	blobToRead, err = item.Open()
-	return blobToRead, fileSize, err
+
+	return nil
 }
 
 func (s3 S3Client) startProgressBar(size int64, item io.Reader) (progressBar *progress.Bar, reader io.Reader) {
@@ -215,26 +424,21 @@ func (s3 S3Client) DownloadProductStemcell(fa *FileArtifact) (*stemcell, error)
 var InvalidEndpointErrorMessageTemplate = "Could not reach provided endpoint: '%s': %s"
 
 func (s *S3Client) listFiles() ([]string, error) {
-	location, err := s.stower.Dial("s3", s.Config)
-	if err != nil {
-		return nil, err
-	}
-	container, err := location.Container(s.bucket)
-	if err != nil {
-		endpoint, _ := s.Config.Config("endpoint")
-		if endpoint != "" {
-			return nil, errors.New(fmt.Sprintf(InvalidEndpointErrorMessageTemplate, endpoint, err.Error()))
-		}
-		return nil, err
-	}
-
 	var paths []string
-	err = s.stower.Walk(container, stow.NoPrefix, 100, func(item stow.Item, err error) error {
+	err := s.inBucketRegion(func() error {
+		container, err := s.container()
 		if err != nil {
 			return err
 		}
-		paths = append(paths, item.ID())
-		return nil
+
+		paths = nil
+		return s.stower.Walk(container, stow.NoPrefix, 100, func(item stow.Item, err error) error {
+			if err != nil {
+				return err
+			}
+			paths = append(paths, item.ID())
+			return nil
+		})
 	})
 
 	if err != nil {
@@ -248,4 +452,15 @@ func (s *S3Client) listFiles() ([]string, error) {
 	return paths, nil
 }
 
+func (s *S3Client) deleteFile(filename string) error {
+	return s.inBucketRegion(func() error {
+		container, err := s.container()
+		if err != nil {
+			return err
+		}
+
+		return container.RemoveItem(filename)
+	})
+}
+
 const Semver2Regex = `(?P<major>0|[1-9]\d*)\.(?P<minor>0|[1-9]\d*)\.(?P<patch>0|[1-9]\d*)(?:-(?P<prerelease>(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+(?P<buildmetadata>[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?`
@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 
 	"io"
 	"io/ioutil"
@@ -57,6 +58,29 @@ var _ = Describe("S3Client", func() {
 			})
 		})
 
+		It("reports the versions persisted under the aliases of the slug", func() {
+			stower := newMockStower([]mockItem{
+				newMockItem("[cf,2.4.1]cf-2.4.1-build.1.pivotal"),
+				newMockItem("[elastic-runtime,2.5.0]cf-2.5.0-build.1.pivotal"),
+				newMockItem("[elastic-runtime-windows,2.5.0]pas-windows-2.5.0-build.1.pivotal"),
+			})
+			config := commands.S3Configuration{
+				Bucket:          "bucket",
+				AccessKeyID:     "access-key-id",
+				SecretAccessKey: "secret-access-key",
+				RegionName:      "region",
+				Endpoint:        "endpoint",
+				SlugAliases:     map[string][]string{"elastic-runtime": {"cf"}},
+			}
+
+			client, err := commands.NewS3Client(stower, config, GinkgoWriter)
+			Expect(err).ToNot(HaveOccurred())
+
+			versions, err := client.GetAllProductVersions("elastic-runtime")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(versions).To(Equal([]string{"2.4.1", "2.5.0"}))
+		})
+
 		DescribeTable("the path variable", func(path string) {
 			var (
 				stower *mockStower
@@ -254,6 +278,88 @@ var _ = Describe("S3Client", func() {
 			Expect(err.Error()).To(ContainSubstring("the glob '*.zip' matches no file"))
 		})
 
+		Describe("the aliases of the slug", func() {
+			newClient := func(itemsList []mockItem) *commands.S3Client {
+				config := commands.S3Configuration{
+					Bucket:          "bucket",
+					AccessKeyID:     "access-key-id",
+					SecretAccessKey: "secret-access-key",
+					RegionName:      "region",
+					Endpoint:        "endpoint",
+					SlugAliases:     map[string][]string{"elastic-runtime": {"cf"}},
+				}
+
+				client, err := commands.NewS3Client(newMockStower(itemsList), config, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+				return client
+			}
+
+			It("returns the file persisted under an alias", func() {
+				client := newClient([]mockItem{
+					newMockItem("[cf,2.4.1]cf-2.4.1-build.1.pivotal"),
+				})
+
+				fileArtifact, err := client.GetLatestProductFile("elastic-runtime", "2.4.1", "*.pivotal")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fileArtifact.Name).To(Equal("[cf,2.4.1]cf-2.4.1-build.1.pivotal"))
+			})
+
+			It("prefers the file persisted under the slug", func() {
+				client := newClient([]mockItem{
+					newMockItem("[cf,2.4.1]cf-2.4.1-build.1.pivotal"),
+					newMockItem("[elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal"),
+				})
+
+				fileArtifact, err := client.GetLatestProductFile("elastic-runtime", "2.4.1", "*.pivotal")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fileArtifact.Name).To(Equal("[elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal"))
+			})
+
+			It("does not return the files of the slug for its alias", func() {
+				client := newClient([]mockItem{
+					newMockItem("[elastic-runtime,2.4.1]cf-2.4.1-build.1.pivotal"),
+				})
+
+				_, err := client.GetLatestProductFile("cf", "2.4.1", "*.pivotal")
+				Expect(err).To(MatchError(ContainSubstring("no product files with expected prefix [cf,2.4.1] found")))
+			})
+		})
+
+		It("reads the sha256 persisted along with the file, which does not match the glob", func() {
+			sha256File, err := ioutil.TempFile("", "")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.Remove(sha256File.Name())
+
+			_, err = sha256File.WriteString("some-sha256  light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sha256File.Close()).To(Succeed())
+
+			sha256Item := newMockItem("[stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz.sha256")
+			sha256Item.fakeFileName = sha256File.Name()
+
+			stower := &mockStower{
+				location: mockLocation{container: &mockContainer{item: sha256Item}},
+				itemsList: []mockItem{
+					newMockItem("[stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz"),
+					sha256Item,
+				},
+			}
+			config := commands.S3Configuration{
+				Bucket:          "bucket",
+				AccessKeyID:     "access-key-id",
+				SecretAccessKey: "secret-access-key",
+				RegionName:      "region",
+				Endpoint:        "endpoint",
+			}
+
+			client, err := commands.NewS3Client(stower, config, GinkgoWriter)
+			Expect(err).ToNot(HaveOccurred())
+
+			fileArtifact, err := client.GetLatestProductFile("stemcells-ubuntu-xenial", "97.19", "*google*")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fileArtifact.Name).To(Equal("[stemcells-ubuntu-xenial,97.19]light-bosh-stemcell-97.19-google-kvm-ubuntu-xenial-go_agent.tgz"))
+		})
+
 		DescribeTable("the item exists in the path in the bucket", func(path string) {
 			itemsList := []mockItem{
 				newMockItem("/some-path/nested/[product-slug,1.0.0]pcf-vsphere-2.1-build.341.ova"),
@@ -399,7 +505,6 @@ var _ = Describe("S3Client", func() {
 			Entry("requires Bucket", "Bucket"),
 			Entry("requires AccessKeyID", "AccessKeyID"),
 			Entry("requires SecretAccessKey", "SecretAccessKey"),
-			Entry("requires RegionName", "RegionName"),
 		)
 
 		It("defaults optional properties", func() {
@@ -433,6 +538,165 @@ var _ = Describe("S3Client", func() {
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
+
+		Describe("aws partitions", func() {
+			newClient := func(region, endpoint, partition string) (*commands.S3Client, error) {
+				config := commands.S3Configuration{
+					Bucket:          "bucket",
+					AccessKeyID:     "access-key-id",
+					SecretAccessKey: "secret-access-key",
+					RegionName:      region,
+					Endpoint:        endpoint,
+					Partition:       partition,
+				}
+				return commands.NewS3Client(&mockStower{}, config, GinkgoWriter)
+			}
+
+			DescribeTable("the endpoint of the region", func(region, partition, expectedEndpoint string) {
+				client, err := newClient(region, "", partition)
+				Expect(err).ToNot(HaveOccurred())
+
+				endpoint, _ := client.Config.Config("endpoint")
+				Expect(endpoint).To(Equal(expectedEndpoint))
+
+				signingRegion, _ := client.Config.Config("region")
+				Expect(signingRegion).To(Equal(region))
+			},
+				Entry("is resolved by aws for a commercial region", "us-east-1", "", ""),
+				Entry("is resolved by aws for a GovCloud region", "us-gov-west-1", "aws-us-gov", ""),
+				Entry("is resolved by aws for a China region", "cn-northwest-1", "", ""),
+				Entry("is constructed for an ISO region", "us-iso-east-1", "", "s3.us-iso-east-1.c2s.ic.gov"),
+				Entry("is constructed for an ISO-B region", "us-isob-east-1", "aws-iso-b", "s3.us-isob-east-1.sc2s.sgov.gov"),
+				Entry("is resolved by aws for any other commercial region", "il-central-1", "", ""),
+				Entry("is resolved by aws for a region of no partition", "region", "", ""),
+				Entry("is constructed for a region the given partition does not match yet", "us-iso-south-1", "aws-iso", "s3.us-iso-south-1.c2s.ic.gov"),
+			)
+
+			DescribeTable("the signing region of the partition", func(region, endpoint, partition, expectedEndpoint, expectedRegion string) {
+				client, err := newClient(region, endpoint, partition)
+				Expect(err).ToNot(HaveOccurred())
+
+				actualEndpoint, _ := client.Config.Config("endpoint")
+				Expect(actualEndpoint).To(Equal(expectedEndpoint))
+
+				signingRegion, _ := client.Config.Config("region")
+				Expect(signingRegion).To(Equal(expectedRegion))
+			},
+				Entry("is the region of the partition with a custom endpoint", "region", "https://s3.example.com", "aws-cn", "https://s3.example.com", "cn-north-1"),
+				Entry("is the region of the partition without a region", "", "", "aws-iso-b", "s3.us-isob-east-1.sc2s.sgov.gov", "us-isob-east-1"),
+				Entry("is the given region when it is an aws region", "mx-central-1", "https://s3.example.com", "aws", "https://s3.example.com", "mx-central-1"),
+			)
+
+			It("keeps a custom endpoint for a region of the given partition", func() {
+				client, err := newClient("us-gov-east-1", "https://s3-fips.us-gov-east-1.amazonaws.com", "aws-us-gov")
+				Expect(err).ToNot(HaveOccurred())
+
+				endpoint, _ := client.Config.Config("endpoint")
+				Expect(endpoint).To(Equal("https://s3-fips.us-gov-east-1.amazonaws.com"))
+			})
+
+			It("returns an error when the partition is unknown", func() {
+				_, err := newClient("us-east-1", "", "aws-moon")
+				Expect(err).To(MatchError(`unknown partition "aws-moon", expected one of: aws-us-gov, aws-iso-b, aws-iso, aws-cn, aws`))
+			})
+		})
+
+		Describe("the region of the bucket", func() {
+			var (
+				stower   *mockStower
+				progress *gbytes.Buffer
+			)
+
+			BeforeEach(func() {
+				stower = &mockStower{
+					itemsList:      []mockItem{newMockItem("[product-slug,1.0.0]product.pivotal")},
+					regionOfBucket: "eu-west-1",
+				}
+				progress = gbytes.NewBuffer()
+			})
+
+			newClient := func(region, endpoint string) *commands.S3Client {
+				client, err := commands.NewS3Client(stower, commands.S3Configuration{
+					Bucket:          "bucket",
+					AccessKeyID:     "access-key-id",
+					SecretAccessKey: "secret-access-key",
+					RegionName:      region,
+					Endpoint:        endpoint,
+				}, progress)
+				Expect(err).ToNot(HaveOccurred())
+				return client
+			}
+
+			It("is looked up when it is not configured", func() {
+				client := newClient("", "")
+
+				versions, err := client.GetAllProductVersions("product-slug")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(versions).To(Equal([]string{"1.0.0"}))
+
+				region, _ := client.Config.Config("region")
+				Expect(region).To(Equal("eu-west-1"))
+				Expect(stower.regionHints).To(Equal([]string{"us-east-1"}))
+				Expect(progress.Contents()).To(BeEmpty())
+			})
+
+			It("is looked up, and the bucket read there, when the bucket cannot be read in the configured region", func() {
+				client := newClient("us-west-2", "")
+
+				versions, err := client.GetAllProductVersions("product-slug")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(versions).To(Equal([]string{"1.0.0"}))
+
+				Expect(stower.regionHints).To(Equal([]string{"us-west-2"}))
+				Expect(progress).To(gbytes.Say("warning: bucket bucket is in region eu-west-1, you configured us-west-2"))
+
+				_, err = client.GetAllProductVersions("product-slug")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stower.regionHints).To(HaveLen(1))
+			})
+
+			It("returns an error with both regions when the bucket cannot be read in its region either", func() {
+				stower.location = mockLocation{containerError: errors.New("AccessDenied")}
+				client := newClient("us-west-2", "")
+
+				_, err := client.GetAllProductVersions("product-slug")
+				Expect(err).To(MatchError("bucket bucket is in region eu-west-1, you configured us-west-2: AccessDenied"))
+			})
+
+			It("returns the error of the configured region when the region cannot be looked up", func() {
+				stower.bucketRegionError = errors.New("NotFound")
+				client := newClient("us-west-2", "")
+
+				_, err := client.GetAllProductVersions("product-slug")
+				Expect(err).To(MatchError(ContainSubstring("PermanentRedirect")))
+			})
+
+			It("returns an error when it is not configured and cannot be looked up", func() {
+				stower.bucketRegionError = errors.New("NotFound")
+				client := newClient("", "")
+
+				_, err := client.GetAllProductVersions("product-slug")
+				Expect(err).To(MatchError("could not look up the region of the bucket bucket, set region-name: NotFound"))
+			})
+
+			It("is not looked up with a custom endpoint", func() {
+				client := newClient("region", "https://minio.example.com")
+
+				_, err := client.GetAllProductVersions("product-slug")
+				Expect(err).To(MatchError(ContainSubstring("PermanentRedirect")))
+				Expect(stower.regionHints).To(BeEmpty())
+			})
+
+			It("is required with a custom endpoint", func() {
+				_, err := commands.NewS3Client(stower, commands.S3Configuration{
+					Bucket:          "bucket",
+					AccessKeyID:     "access-key-id",
+					SecretAccessKey: "secret-access-key",
+					Endpoint:        "https://minio.example.com",
+				}, progress)
+				Expect(err).To(MatchError("region-name or partition is required with an endpoint"))
+			})
+		})
 	})
 
 	It("returns an error on stower failure", func() {
@@ -466,6 +730,10 @@ type mockStower struct {
 	containerError error
 	itemError      error
 	config         commands.Config
+	// the bucket can only be read in its region, when it is given
+	regionOfBucket    string
+	bucketRegionError error
+	regionHints       []string
 }
 
 func newMockStower(itemsList []mockItem) *mockStower {
@@ -481,6 +749,10 @@ func (s *mockStower) Dial(kind string, config commands.Config) (stow.Location, e
 		return nil, s.dialError
 	}
 
+	if region, _ := config.Config("region"); s.regionOfBucket != "" && region != s.regionOfBucket {
+		return nil, errors.New("PermanentRedirect: The bucket you are attempting to access must be addressed using the specified endpoint")
+	}
+
 	return s.location, nil
 }
 
@@ -492,6 +764,11 @@ func (s *mockStower) Walk(container stow.Container, prefix string, pageSize int,
 	return nil
 }
 
+func (s *mockStower) BucketRegion(bucket, regionHint string) (string, error) {
+	s.regionHints = append(s.regionHints, regionHint)
+	return s.regionOfBucket, s.bucketRegionError
+}
+
 type mockLocation struct {
 	io.Closer
 	container      *mockContainer
@@ -519,6 +796,10 @@ func (m mockLocation) ItemByURL(url *url.URL) (stow.Item, error) {
 
 type mockContainer struct {
 	item mockItem
+	// the items returned by their id, instead of the item
+	items map[string]mockItem
+	// the ids of the items that are removed, when it is given
+	removedItems *[]string
 }
 
 func (m mockContainer) ID() string {
@@ -528,12 +809,18 @@ func (m mockContainer) Name() string {
 	return ""
 }
 func (m mockContainer) Item(id string) (stow.Item, error) {
+	if item, ok := m.items[id]; ok {
+		return item, nil
+	}
 	return m.item, nil
 }
 func (m mockContainer) Items(prefix, cursor string, count int) ([]stow.Item, string, error) {
 	return []stow.Item{mockItem{}}, "", nil
 }
 func (m mockContainer) RemoveItem(id string) error {
+	if m.removedItems != nil {
+		*m.removedItems = append(*m.removedItems, id)
+	}
 	return nil
 }
 func (m mockContainer) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (stow.Item, error) {
@@ -573,5 +860,12 @@ func (m mockItem) ID() string {
 }
 
 func (m mockItem) Size() (int64, error) {
+	if m.fakeFileName != "" {
+		info, err := os.Stat(m.fakeFileName)
+		if err == nil {
+			return info.Size(), nil
+		}
+	}
+
 	return 0, nil
 }
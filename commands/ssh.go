@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+)
+
+type SSH struct {
+	service    sshService
+	runner     commandRunner
+	opsmanHost string
+	Options    struct {
+		SSHPrivateKey string `long:"ssh-private-key" short:"i" required:"true"  description:"Location of the ssh private key of the Ops Manager VM"`
+		ProductName   string `long:"product-name"    short:"p"                  description:"name of the deployed product whose VM is reached, through the Ops Manager VM; the Ops Manager VM itself is reached when not given"`
+		Instance      string `long:"instance"                                   description:"instance group and index, or ID, of the VM of the product (e.g. router/0)"`
+		Command       string `long:"command"         short:"c"                  description:"runs the command on the VM, instead of opening an interactive session"`
+		GatewayUser   string `long:"gateway-user"              default:"ubuntu" description:"user of the Ops Manager VM"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/ssh_service.go --fake-name SSHService . sshService
+type sshService interface {
+	GetBoshEnvironment() (api.GetBoshEnvironmentOutput, error)
+	ListCertificateAuthorities() (api.CertificateAuthoritiesOutput, error)
+	ListDeployedProducts() ([]api.DeployedProductOutput, error)
+}
+
+//go:generate counterfeiter -o ./fakes/command_runner.go --fake-name CommandRunner . commandRunner
+type commandRunner interface {
+	Run(name string, args []string, env []string) error
+}
+
+func NewSSH(service sshService, runner commandRunner, opsmanHost string) SSH {
+	return SSH{
+		service:    service,
+		runner:     runner,
+		opsmanHost: opsmanHost,
+	}
+}
+
+func (s SSH) Execute(args []string) error {
+	if _, err := jhanda.Parse(&s.Options, args); err != nil {
+		return fmt.Errorf("could not parse ssh flags: %s", err)
+	}
+
+	if (s.Options.ProductName == "") != (s.Options.Instance == "") {
+		return errors.New("--product-name and --instance must be given together to reach the VM of a product")
+	}
+
+	host := s.host()
+
+	if s.Options.ProductName == "" {
+		sshArgs := []string{"-i", s.Options.SSHPrivateKey, fmt.Sprintf("%s@%s", s.Options.GatewayUser, host)}
+		if s.Options.Command != "" {
+			sshArgs = append(sshArgs, s.Options.Command)
+		}
+
+		err := s.runner.Run("ssh", sshArgs, nil)
+		if err != nil {
+			return fmt.Errorf("could not ssh to the Ops Manager VM: %s", err)
+		}
+
+		return nil
+	}
+
+	deploymentName, err := s.deploymentName()
+	if err != nil {
+		return err
+	}
+
+	variables, err := boshEnvironmentVariables(s.service)
+	if err != nil {
+		return err
+	}
+
+	// the director is usually only reachable through the Ops Manager VM, as
+	// with bosh-env --ssh-private-key; --gw-host only tunnels the ssh session
+	variables["BOSH_ALL_PROXY"] = fmt.Sprintf("ssh+socks5://%s@%s:22?private-key=%s", s.Options.GatewayUser, host, s.Options.SSHPrivateKey)
+
+	var env []string
+	for name, value := range variables {
+		if strings.HasPrefix(name, "BOSH_") {
+			env = append(env, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	sort.Strings(env)
+
+	boshArgs := []string{
+		"-d", deploymentName,
+		"ssh", s.Options.Instance,
+		"--gw-host", host,
+		"--gw-user", s.Options.GatewayUser,
+		"--gw-private-key", s.Options.SSHPrivateKey,
+	}
+	if s.Options.Command != "" {
+		boshArgs = append(boshArgs, "-c", s.Options.Command)
+	}
+
+	err = s.runner.Run("bosh", boshArgs, env)
+	if err != nil {
+		return fmt.Errorf("could not ssh to %s of %s: %s", s.Options.Instance, s.Options.ProductName, err)
+	}
+
+	return nil
+}
+
+// host returns the host of the Ops Manager VM, without the protocol, path
+// and port of the target.
+func (s SSH) host() string {
+	host := strings.SplitN(targetHost(s.opsmanHost), "/", 2)[0]
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return hostname
+	}
+
+	return host
+}
+
+// deploymentName returns the name of the bosh deployment of the product,
+// which is the GUID of the deployed product.
+func (s SSH) deploymentName() (string, error) {
+	deployedProducts, err := s.service.ListDeployedProducts()
+	if err != nil {
+		return "", fmt.Errorf("could not list the deployed products: %s", err)
+	}
+
+	var productNames []string
+	for _, product := range deployedProducts {
+		if product.Type == s.Options.ProductName {
+			return product.GUID, nil
+		}
+		productNames = append(productNames, product.Type)
+	}
+
+	return "", fmt.Errorf("the product %q is not deployed, the deployed products are: %s", s.Options.ProductName, strings.Join(productNames, ", "))
+}
+
+func (s SSH) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command opens an ssh session, or runs a command, on the Ops Manager VM, or on a VM of a deployed product through the Ops Manager VM. The VMs of the products are reached with the bosh cli, which must be installed, with the credentials of the director.",
+		ShortDescription: "opens an ssh session on the Ops Manager VM or a VM of a product",
+		Flags:            s.Options,
+	}
+}
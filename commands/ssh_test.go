@@ -0,0 +1,164 @@
+package commands_test
+
+import (
+	"errors"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SSH", func() {
+	var (
+		fakeService *fakes.SSHService
+		fakeRunner  *fakes.CommandRunner
+		command     commands.SSH
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.SSHService{}
+		fakeRunner = &fakes.CommandRunner{}
+		command = commands.NewSSH(fakeService, fakeRunner, "https://opsman.example.com:443/")
+
+		fakeService.GetBoshEnvironmentReturns(api.GetBoshEnvironmentOutput{
+			Client:       "opsmanager_client",
+			ClientSecret: "my-super-secret",
+			Environment:  "10.0.0.10",
+		}, nil)
+		fakeService.ListCertificateAuthoritiesReturns(api.CertificateAuthoritiesOutput{
+			CAs: []api.CA{
+				{Active: false, CertPEM: "some-inactive-ca"},
+				{Active: true, CertPEM: "some-ca"},
+			},
+		}, nil)
+		fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+			{Type: "p-bosh", GUID: "p-bosh-guid"},
+			{Type: "cf", GUID: "cf-guid"},
+		}, nil)
+	})
+
+	Describe("Execute", func() {
+		Context("without a product", func() {
+			It("opens an ssh session on the Ops Manager VM", func() {
+				err := command.Execute([]string{"--ssh-private-key", "opsman.pem"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeRunner.RunCallCount()).To(Equal(1))
+				name, args, env := fakeRunner.RunArgsForCall(0)
+				Expect(name).To(Equal("ssh"))
+				Expect(args).To(Equal([]string{"-i", "opsman.pem", "ubuntu@opsman.example.com"}))
+				Expect(env).To(BeEmpty())
+
+				Expect(fakeService.GetBoshEnvironmentCallCount()).To(Equal(0))
+			})
+
+			It("runs the command as the gateway user", func() {
+				err := command.Execute([]string{"-i", "opsman.pem", "--gateway-user", "admin", "--command", "df -h"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, args, _ := fakeRunner.RunArgsForCall(0)
+				Expect(args).To(Equal([]string{"-i", "opsman.pem", "admin@opsman.example.com", "df -h"}))
+			})
+
+			It("returns an error when the ssh session fails", func() {
+				fakeRunner.RunReturns(errors.New("exit status 255"))
+
+				err := command.Execute([]string{"-i", "opsman.pem"})
+				Expect(err).To(MatchError("could not ssh to the Ops Manager VM: exit status 255"))
+			})
+		})
+
+		Context("with a product", func() {
+			It("opens a bosh ssh session on the VM through the Ops Manager VM", func() {
+				err := command.Execute([]string{"-i", "opsman.pem", "--product-name", "cf", "--instance", "router/0"})
+				Expect(err).NotTo(HaveOccurred())
+
+				name, args, env := fakeRunner.RunArgsForCall(0)
+				Expect(name).To(Equal("bosh"))
+				Expect(args).To(Equal([]string{
+					"-d", "cf-guid",
+					"ssh", "router/0",
+					"--gw-host", "opsman.example.com",
+					"--gw-user", "ubuntu",
+					"--gw-private-key", "opsman.pem",
+				}))
+				Expect(env).To(Equal([]string{
+					"BOSH_ALL_PROXY=ssh+socks5://ubuntu@opsman.example.com:22?private-key=opsman.pem",
+					"BOSH_CA_CERT=some-ca",
+					"BOSH_CLIENT=opsmanager_client",
+					"BOSH_CLIENT_SECRET=my-super-secret",
+					"BOSH_ENVIRONMENT=10.0.0.10",
+				}))
+			})
+
+			It("runs the command on the VM", func() {
+				err := command.Execute([]string{"-i", "opsman.pem", "-p", "cf", "--instance", "router/0", "-c", "sudo monit summary"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, args, _ := fakeRunner.RunArgsForCall(0)
+				Expect(args[len(args)-2:]).To(Equal([]string{"-c", "sudo monit summary"}))
+			})
+
+			It("reaches the director through the Ops Manager VM as the gateway user", func() {
+				err := command.Execute([]string{"-i", "opsman.pem", "-p", "cf", "--instance", "router/0", "--gateway-user", "admin"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, env := fakeRunner.RunArgsForCall(0)
+				Expect(env).To(ContainElement("BOSH_ALL_PROXY=ssh+socks5://admin@opsman.example.com:22?private-key=opsman.pem"))
+			})
+
+			It("returns an error when the instance is missing", func() {
+				err := command.Execute([]string{"-i", "opsman.pem", "-p", "cf"})
+				Expect(err).To(MatchError("--product-name and --instance must be given together to reach the VM of a product"))
+				Expect(fakeRunner.RunCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the product is not deployed", func() {
+				err := command.Execute([]string{"-i", "opsman.pem", "-p", "p-mysql", "--instance", "mysql/0"})
+				Expect(err).To(MatchError(`the product "p-mysql" is not deployed, the deployed products are: p-bosh, cf`))
+				Expect(fakeRunner.RunCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the deployed products cannot be listed", func() {
+				fakeService.ListDeployedProductsReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{"-i", "opsman.pem", "-p", "cf", "--instance", "router/0"})
+				Expect(err).To(MatchError("could not list the deployed products: some error"))
+			})
+
+			It("returns an error when the credentials of the director cannot be fetched", func() {
+				fakeService.GetBoshEnvironmentReturns(api.GetBoshEnvironmentOutput{}, errors.New("some error"))
+
+				err := command.Execute([]string{"-i", "opsman.pem", "-p", "cf", "--instance", "router/0"})
+				Expect(err).To(MatchError("some error"))
+				Expect(fakeRunner.RunCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the bosh ssh session fails", func() {
+				fakeRunner.RunReturns(errors.New("exit status 1"))
+
+				err := command.Execute([]string{"-i", "opsman.pem", "-p", "cf", "--instance", "router/0"})
+				Expect(err).To(MatchError("could not ssh to router/0 of cf: exit status 1"))
+			})
+		})
+
+		It("returns an error when the private key is missing", func() {
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("could not parse ssh flags: missing required flag \"--ssh-private-key\""))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command opens an ssh session, or runs a command, on the Ops Manager VM, or on a VM of a deployed product through the Ops Manager VM. The VMs of the products are reached with the bosh cli, which must be installed, with the credentials of the director.",
+				ShortDescription: "opens an ssh session on the Ops Manager VM or a VM of a product",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -2,7 +2,9 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/hashicorp/go-version"
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
 )
@@ -12,7 +14,8 @@ type StageProduct struct {
 	service stageProductService
 	Options struct {
 		Product string `long:"product-name"    short:"p" required:"true" description:"name of product"`
-		Version string `long:"product-version" short:"v" required:"true" description:"version of product"`
+		Version string `long:"product-version" short:"v"                 description:"version of product"`
+		Latest  bool   `long:"latest"                                     description:"stage the latest uploaded version of the product. Incompatible with --product-version flag."`
 	}
 }
 
@@ -20,6 +23,7 @@ type StageProduct struct {
 type stageProductService interface {
 	CheckProductAvailability(productName string, productVersion string) (bool, error)
 	GetDiagnosticReport() (api.DiagnosticReport, error)
+	ListAvailableProducts() (api.AvailableProductsOutput, error)
 	ListDeployedProducts() ([]api.DeployedProductOutput, error)
 	ListInstallations() ([]api.InstallationsServiceOutput, error)
 	Stage(api.StageProductInput, string) error
@@ -37,11 +41,26 @@ func (sp StageProduct) Execute(args []string) error {
 		return fmt.Errorf("could not parse stage-product flags: %s", err)
 	}
 
+	if sp.Options.Version == "" && !sp.Options.Latest {
+		return fmt.Errorf("could not parse stage-product flags: missing required flag \"--product-version\" or \"--latest\"")
+	}
+
+	if sp.Options.Version != "" && sp.Options.Latest {
+		return fmt.Errorf("could not parse stage-product flags: --product-version and --latest cannot be used together")
+	}
+
 	err := checkRunningInstallation(sp.service.ListInstallations)
 	if err != nil {
 		return err
 	}
 
+	if sp.Options.Latest {
+		sp.Options.Version, err = sp.latestAvailableVersion()
+		if err != nil {
+			return fmt.Errorf("failed to stage product: %s", err)
+		}
+	}
+
 	diagnosticReport, err := sp.service.GetDiagnosticReport()
 	if err != nil {
 		return fmt.Errorf("failed to stage product: %s", err)
@@ -90,6 +109,35 @@ func (sp StageProduct) Execute(args []string) error {
 	return nil
 }
 
+func (sp StageProduct) latestAvailableVersion() (string, error) {
+	availableProducts, err := sp.service.ListAvailableProducts()
+	if err != nil {
+		return "", err
+	}
+
+	var versions version.Collection
+	for _, product := range availableProducts.ProductsList {
+		if product.Name != sp.Options.Product {
+			continue
+		}
+
+		v, err := version.NewVersion(product.Version)
+		if err != nil {
+			sp.logger.Printf("warning: could not parse semver version from: %s", product.Version)
+			continue
+		}
+		versions = append(versions, v)
+	}
+
+	if len(versions) == 0 {
+		return "", fmt.Errorf("cannot find any uploaded versions of product %s", sp.Options.Product)
+	}
+
+	sort.Sort(versions)
+
+	return versions[len(versions)-1].Original(), nil
+}
+
 func (sp StageProduct) Usage() jhanda.Usage {
 	return jhanda.Usage{
 		Description:      "This command attempts to stage a product in the Ops Manager",
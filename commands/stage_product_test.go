@@ -59,6 +59,48 @@ var _ = Describe("StageProduct", func() {
 		Expect(fmt.Sprintf(format, v...)).To(Equal("finished staging"))
 	})
 
+	Context("when the latest flag is provided", func() {
+		BeforeEach(func() {
+			fakeService.CheckProductAvailabilityReturns(true, nil)
+			fakeService.ListAvailableProductsReturns(api.AvailableProductsOutput{
+				ProductsList: []api.ProductInfo{
+					{Name: "some-product", Version: "1.2.0"},
+					{Name: "some-product", Version: "1.10.0-build.2"},
+					{Name: "some-product", Version: "1.10.0-build.10"},
+					{Name: "some-product", Version: "not-a-version"},
+					{Name: "some-other-product", Version: "2.0.0"},
+				},
+			}, nil)
+		})
+
+		It("stages the newest uploaded version of the product", func() {
+			command := commands.NewStageProduct(fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product-name", "some-product",
+				"--latest",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			productName, productVersion := fakeService.CheckProductAvailabilityArgsForCall(0)
+			Expect(productName).To(Equal("some-product"))
+			Expect(productVersion).To(Equal("1.10.0-build.10"))
+
+			Expect(fakeService.StageCallCount()).To(Equal(1))
+			stageProductInput, _ := fakeService.StageArgsForCall(0)
+			Expect(stageProductInput).To(Equal(api.StageProductInput{
+				ProductName:    "some-product",
+				ProductVersion: "1.10.0-build.10",
+			}))
+
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("warning: could not parse semver version from: not-a-version"))
+
+			format, v = logger.PrintfArgsForCall(1)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("staging some-product 1.10.0-build.10"))
+		})
+	})
+
 	Context("when a product has already been deployed", func() {
 		It("stages the product", func() {
 			fakeService.CheckProductAvailabilityReturns(true, nil)
@@ -169,7 +211,40 @@ var _ = Describe("StageProduct", func() {
 			It("returns an error", func() {
 				command := commands.NewStageProduct(fakeService, logger)
 				err := command.Execute([]string{"--product-name", "some-product"})
-				Expect(err).To(MatchError("could not parse stage-product flags: missing required flag \"--product-version\""))
+				Expect(err).To(MatchError("could not parse stage-product flags: missing required flag \"--product-version\" or \"--latest\""))
+			})
+		})
+
+		Context("when both the product-version and latest flags are provided", func() {
+			It("returns an error", func() {
+				command := commands.NewStageProduct(fakeService, logger)
+				err := command.Execute([]string{"--product-name", "some-product", "--product-version", "1.0", "--latest"})
+				Expect(err).To(MatchError("could not parse stage-product flags: --product-version and --latest cannot be used together"))
+			})
+		})
+
+		Context("when the latest flag is provided and the available products cannot be fetched", func() {
+			It("returns an error", func() {
+				command := commands.NewStageProduct(fakeService, logger)
+				fakeService.ListAvailableProductsReturns(api.AvailableProductsOutput{}, errors.New("could not fetch available products"))
+
+				err := command.Execute([]string{"--product-name", "some-product", "--latest"})
+				Expect(err).To(MatchError("failed to stage product: could not fetch available products"))
+			})
+		})
+
+		Context("when the latest flag is provided and no versions of the product have been uploaded", func() {
+			It("returns an error", func() {
+				command := commands.NewStageProduct(fakeService, logger)
+				fakeService.ListAvailableProductsReturns(api.AvailableProductsOutput{
+					ProductsList: []api.ProductInfo{
+						{Name: "some-other-product", Version: "1.0.0"},
+					},
+				}, nil)
+
+				err := command.Execute([]string{"--product-name", "some-product", "--latest"})
+				Expect(err).To(MatchError("failed to stage product: cannot find any uploaded versions of product some-product"))
+				Expect(fakeService.StageCallCount()).To(Equal(0))
 			})
 		})
 
@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
-
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/pivotal-cf/jhanda"
@@ -16,21 +20,22 @@ type StagedConfig struct {
 	service stagedConfigService
 	logger  logger
 	Options struct {
-		Product             string `long:"product-name" short:"p" required:"true" description:"name of product"`
+		Product             string `long:"product-name" short:"p" description:"name of product"`
 		IncludeCredentials  bool   `long:"include-credentials" short:"c" description:"include credentials. note: requires product to have been deployed"`
 		IncludePlaceholders bool   `long:"include-placeholders" short:"r" description:"replace obscured credentials with interpolatable placeholders"`
+		All                 bool   `long:"all" description:"write the configs of the director and of every staged product to --output-dir, instead of the config of --product-name to stdout"`
+		OutputDir           string `long:"output-dir" description:"directory the configs of --all are written to, as director.yml and products/<product-name>.yml"`
 	}
 }
 
 //go:generate counterfeiter -o ./fakes/staged_config_service.go --fake-name StagedConfigService . stagedConfigService
 type stagedConfigService interface {
+	stagedDirectorConfigService
+
 	GetDeployedProductCredential(input api.GetDeployedProductCredentialInput) (api.GetDeployedProductCredentialOutput, error)
-	GetStagedProductByName(product string) (api.StagedProductsFindOutput, error)
-	GetStagedProductJobResourceConfig(productGUID, jobGUID string) (api.JobProperties, error)
-	GetStagedProductNetworksAndAZs(product string) (map[string]interface{}, error)
 	GetStagedProductProperties(product string) (map[string]api.ResponseProperty, error)
 	ListDeployedProducts() ([]api.DeployedProductOutput, error)
-	ListStagedProductJobs(productGUID string) (map[string]string, error)
+	ListStagedProducts() (api.StagedProductsOutput, error)
 	ListStagedProductErrands(productID string) (api.ErrandsListOutput, error)
 }
 
@@ -48,7 +53,7 @@ func NewStagedConfig(service stagedConfigService, logger logger) StagedConfig {
 
 func (ec StagedConfig) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "This command generates a config from a staged product that can be passed in to om configure-product (Note: credentials are not available and will appear as '***')",
+		Description:      "This command generates a config from a staged product that can be passed in to om configure-product, including its properties, networks, resource config and errand states (Note: credentials are not available and are left out, use --include-placeholders to replace them with interpolatable placeholders). With --all, the configs of the director and of every staged product are written to --output-dir",
 		ShortDescription: "**EXPERIMENTAL** generates a config from a staged product",
 		Flags:            ec.Options,
 	}
@@ -59,34 +64,199 @@ func (ec StagedConfig) Execute(args []string) error {
 		return fmt.Errorf("could not parse staged-config flags: %s", err)
 	}
 
+	if ec.Options.All {
+		return ec.writeAll()
+	}
+
+	if ec.Options.Product == "" {
+		return fmt.Errorf("could not parse staged-config flags: missing required flag \"--product-name\"")
+	}
+	if ec.Options.OutputDir != "" {
+		return fmt.Errorf("--output-dir can only be used with --all")
+	}
+
 	if ec.Options.IncludeCredentials {
-		deployedProducts, err := ec.service.ListDeployedProducts()
+		err := ec.checkDeployed([]string{ec.Options.Product})
 		if err != nil {
 			return err
 		}
-		var productDeployed bool
-		for _, p := range deployedProducts {
-			if p.Type == ec.Options.Product {
-				productDeployed = true
-				break
-			}
+	}
+
+	output, err := ec.productConfig(ec.Options.Product)
+	if err != nil {
+		return err
+	}
+
+	ec.logger.Println(string(output))
+	return nil
+}
+
+// writeAll writes the configs of the director and of every staged product,
+// in the layout of the config directory of apply-environment
+func (ec StagedConfig) writeAll() error {
+	if ec.Options.Product != "" {
+		return fmt.Errorf("--product-name cannot be used with --all")
+	}
+	if ec.Options.OutputDir == "" {
+		return fmt.Errorf("--output-dir is required with --all")
+	}
+
+	stagedProducts, err := ec.service.ListStagedProducts()
+	if err != nil {
+		return fmt.Errorf("could not list staged products: %s", err)
+	}
+
+	var products []string
+	for _, product := range stagedProducts.Products {
+		if product.Type != "p-bosh" {
+			products = append(products, product.Type)
 		}
-		if !productDeployed {
-			return fmt.Errorf("cannot retrieve credentials for product '%s': deploy the product and retry", ec.Options.Product)
+	}
+
+	if ec.Options.IncludeCredentials {
+		err := ec.checkDeployed(products)
+		if err != nil {
+			return err
 		}
 	}
 
-	findOutput, err := ec.service.GetStagedProductByName(ec.Options.Product)
+	// the credentials are only written to files that only the user can read
+	mode := os.FileMode(0644)
+	if ec.Options.IncludeCredentials {
+		mode = 0600
+	}
+
+	err = os.MkdirAll(filepath.Join(ec.Options.OutputDir, "products"), 0755)
+	if err != nil {
+		return fmt.Errorf("could not create the output directory: %s", err)
+	}
+
+	directorConfig, err := ec.directorConfig()
+	if err != nil {
+		return fmt.Errorf("could not generate the config of the director: %s", err)
+	}
+
+	err = ec.writeConfig("director.yml", directorConfig, mode)
 	if err != nil {
 		return err
 	}
-	productGUID := findOutput.Product.GUID
 
-	properties, err := ec.service.GetStagedProductProperties(productGUID)
+	for _, product := range products {
+		config, err := ec.productConfig(product)
+		if err != nil {
+			return fmt.Errorf("could not generate the config of %s: %s", product, err)
+		}
+
+		err = ec.writeConfig(filepath.Join("products", product+".yml"), config, mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ec StagedConfig) writeConfig(name string, config []byte, mode os.FileMode) error {
+	path := filepath.Join(ec.Options.OutputDir, name)
+
+	err := ioutil.WriteFile(path, config, mode)
+	if err != nil {
+		return fmt.Errorf("could not write %s: %s", path, err)
+	}
+
+	ec.logger.Printf("wrote %s", path)
+	return nil
+}
+
+func (ec StagedConfig) checkDeployed(products []string) error {
+	deployedProducts, err := ec.service.ListDeployedProducts()
 	if err != nil {
 		return err
 	}
 
+	deployed := map[string]bool{}
+	for _, p := range deployedProducts {
+		deployed[p.Type] = true
+	}
+
+	for _, product := range products {
+		if !deployed[product] {
+			return fmt.Errorf("cannot retrieve credentials for product '%s': deploy the product and retry", product)
+		}
+	}
+
+	return nil
+}
+
+func (ec StagedConfig) productConfig(product string) ([]byte, error) {
+	config, err := stagedProductConfig(ec.service, product, ec.chooseCredentialHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %s", err) // un-tested
+	}
+
+	return output, nil
+}
+
+// directorConfig is the config that staged-director-config prints, with the
+// same handling of the credentials as the configs of the products
+func (ec StagedConfig) directorConfig() ([]byte, error) {
+	var args []string
+	if ec.Options.IncludeCredentials {
+		args = append(args, "--include-credentials")
+	}
+	if ec.Options.IncludePlaceholders {
+		args = append(args, "--include-placeholders")
+	}
+
+	var output bytes.Buffer
+	err := NewStagedDirectorConfig(ec.service, log.New(&output, "", 0)).Execute(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+func (ec StagedConfig) chooseCredentialHandler(productGUID string) configparser.CredentialHandler {
+	if ec.Options.IncludePlaceholders {
+		return configparser.PlaceholderHandler()
+	}
+
+	if ec.Options.IncludeCredentials {
+		return configparser.GetCredentialHandler(productGUID, ec.service)
+	}
+
+	return configparser.NilHandler()
+}
+
+type stagedProductConfigService interface {
+	GetStagedProductByName(product string) (api.StagedProductsFindOutput, error)
+	GetStagedProductJobResourceConfig(productGUID, jobGUID string) (api.JobProperties, error)
+	GetStagedProductNetworksAndAZs(product string) (map[string]interface{}, error)
+	GetStagedProductProperties(product string) (map[string]api.ResponseProperty, error)
+	ListStagedProductJobs(productGUID string) (map[string]string, error)
+	ListStagedProductErrands(productID string) (api.ErrandsListOutput, error)
+}
+
+// stagedProductConfig builds the configure-product config of a staged product,
+// credentials are parsed with the handler returned for the product's GUID.
+func stagedProductConfig(service stagedProductConfigService, productName string, credentialHandler func(productGUID string) configparser.CredentialHandler) (config.ProductConfiguration, error) {
+	findOutput, err := service.GetStagedProductByName(productName)
+	if err != nil {
+		return config.ProductConfiguration{}, err
+	}
+	productGUID := findOutput.Product.GUID
+
+	properties, err := service.GetStagedProductProperties(productGUID)
+	if err != nil {
+		return config.ProductConfiguration{}, err
+	}
+
 	configurableProperties := map[string]interface{}{}
 	selectorProperties := map[string]string{}
 
@@ -105,10 +275,10 @@ func (ec StagedConfig) Execute(args []string) error {
 
 		parser := configparser.NewConfigParser()
 		propertyName := configparser.NewPropertyName(name)
-		output, err = parser.ParseProperties(propertyName, property, ec.chooseCredentialHandler(productGUID))
+		output, err = parser.ParseProperties(propertyName, property, credentialHandler(productGUID))
 
 		if err != nil {
-			return err
+			return config.ProductConfiguration{}, err
 		}
 		if output != nil && len(output) > 0 {
 			configurableProperties[name] = output
@@ -126,34 +296,40 @@ func (ec StagedConfig) Execute(args []string) error {
 		}
 	}
 
-	networks, err := ec.service.GetStagedProductNetworksAndAZs(productGUID)
+	networks, err := service.GetStagedProductNetworksAndAZs(productGUID)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 
-	jobs, err := ec.service.ListStagedProductJobs(productGUID)
+	jobs, err := service.ListStagedProductJobs(productGUID)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 
 	resourceConfig := map[string]interface{}{}
 
 	for name, jobGUID := range jobs {
-		jobProperties, err := ec.service.GetStagedProductJobResourceConfig(productGUID, jobGUID)
+		jobProperties, err := service.GetStagedProductJobResourceConfig(productGUID, jobGUID)
 		if err != nil {
-			return err
+			return config.ProductConfiguration{}, err
 		}
 		resourceConfig[name] = jobProperties
 	}
 
-	errandsListOutput, err := ec.service.ListStagedProductErrands(productGUID)
+	errandsListOutput, err := service.ListStagedProductErrands(productGUID)
 	if err != nil {
-		return err
+		return config.ProductConfiguration{}, err
 	}
 
 	errandConfigs := map[string]config.ErrandConfig{}
 
 	for _, errand := range errandsListOutput.Errands {
+		// an errand without any state cannot be configured,
+		// configure-product would set it to null
+		if errand.PostDeploy == nil && errand.PreDelete == nil {
+			continue
+		}
+
 		errandConfig := config.ErrandConfig{}
 		errandConfig.PostDeployState = errand.PostDeploy
 		errandConfig.PreDeleteState = errand.PreDelete
@@ -161,31 +337,11 @@ func (ec StagedConfig) Execute(args []string) error {
 		errandConfigs[errand.Name] = errandConfig
 	}
 
-	config := config.ProductConfiguration{
-		ProductName:              ec.Options.Product,
+	return config.ProductConfiguration{
+		ProductName:              productName,
 		ProductProperties:        configurableProperties,
 		NetworkProperties:        networks,
 		ResourceConfigProperties: resourceConfig,
 		ErrandConfigs:            errandConfigs,
-	}
-
-	output, err := yaml.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal config: %s", err) // un-tested
-	}
-
-	ec.logger.Println(string(output))
-	return nil
-}
-
-func (ec StagedConfig) chooseCredentialHandler(productGUID string) configparser.CredentialHandler {
-	if ec.Options.IncludePlaceholders {
-		return configparser.PlaceholderHandler()
-	}
-
-	if ec.Options.IncludeCredentials {
-		return configparser.GetCredentialHandler(productGUID, ec.service)
-	}
-
-	return configparser.NilHandler()
+	}, nil
 }
@@ -3,6 +3,9 @@ package commands_test
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
@@ -78,6 +81,8 @@ resource-config:
     instances: 1
     instance_type:
       id: automatic
+    persistent_disk:
+      size_mb: "20480"
 errand-config:
   first-errand:
     post-deploy-state: true
@@ -142,6 +147,8 @@ resource-config:
     instances: 1
     instance_type:
       id: automatic
+    persistent_disk:
+      size_mb: "20480"
 errand-config:
   first-errand:
     post-deploy-state: true
@@ -255,6 +262,8 @@ resource-config:
     instances: 1
     instance_type:
       id: automatic
+    persistent_disk:
+      size_mb: "20480"
 errand-config:
   first-errand:
     post-deploy-state: true
@@ -314,6 +323,145 @@ errand-config:
 				})
 			})
 
+			Context("when listing errands fails", func() {
+				BeforeEach(func() {
+					fakeService.ListStagedProductErrandsReturns(api.ErrandsListOutput{}, errors.New("some-error"))
+				})
+
+				It("returns an error", func() {
+					command := commands.NewStagedConfig(fakeService, logger)
+					err := command.Execute([]string{
+						"--product-name", "some-product",
+					})
+					Expect(err).To(MatchError("some-error"))
+				})
+			})
+		})
+
+		Context("with --all", func() {
+			var outputDir string
+
+			BeforeEach(func() {
+				var err error
+				outputDir, err = ioutil.TempDir("", "staged-configs")
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeService.ListStagedProductsReturns(api.StagedProductsOutput{
+					Products: []api.StagedProduct{
+						{Type: "p-bosh", GUID: "p-bosh-guid"},
+						{Type: "cf", GUID: "cf-guid"},
+						{Type: "p-healthwatch", GUID: "p-healthwatch-guid"},
+					},
+				}, nil)
+				fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+					{Type: "cf", GUID: "cf-guid"},
+				}, nil)
+				fakeService.GetStagedDirectorPropertiesReturns(map[string]map[string]interface{}{
+					"director_configuration": {"ntp_servers_string": "ntp.example.com"},
+				}, nil)
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(outputDir)
+			})
+
+			It("writes the configs of the director and of every staged product", func() {
+				command := commands.NewStagedConfig(fakeService, logger)
+				err := command.Execute([]string{"--all", "--output-dir", outputDir})
+				Expect(err).NotTo(HaveOccurred())
+
+				director, err := ioutil.ReadFile(filepath.Join(outputDir, "director.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(director)).To(ContainSubstring("ntp_servers_string: ntp.example.com"))
+
+				Expect(fakeService.GetStagedProductByNameCallCount()).To(Equal(3))
+				Expect(fakeService.GetStagedProductByNameArgsForCall(0)).To(Equal("p-bosh"))
+				Expect(fakeService.GetStagedProductByNameArgsForCall(1)).To(Equal("cf"))
+				Expect(fakeService.GetStagedProductByNameArgsForCall(2)).To(Equal("p-healthwatch"))
+
+				for _, product := range []string{"cf", "p-healthwatch"} {
+					path := filepath.Join(outputDir, "products", product+".yml")
+					config, err := ioutil.ReadFile(path)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(config)).To(HavePrefix("product-name: " + product + "\n"))
+
+					info, err := os.Stat(path)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(info.Mode().Perm()).To(Equal(os.FileMode(0644)))
+				}
+
+				Expect(logger.PrintfCallCount()).To(Equal(3))
+				format, v := logger.PrintfArgsForCall(2)
+				Expect(fmt.Sprintf(format, v...)).To(Equal("wrote " + filepath.Join(outputDir, "products", "p-healthwatch.yml")))
+			})
+
+			It("fails, without writing anything, when credentials are included and a product is not deployed", func() {
+				command := commands.NewStagedConfig(fakeService, logger)
+				err := command.Execute([]string{"--all", "--output-dir", outputDir, "--include-credentials"})
+				Expect(err).To(MatchError("cannot retrieve credentials for product 'p-healthwatch': deploy the product and retry"))
+
+				_, err = os.Stat(filepath.Join(outputDir, "director.yml"))
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+
+			It("only lets the user read the configs when credentials are included", func() {
+				fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+					{Type: "cf", GUID: "cf-guid"},
+					{Type: "p-healthwatch", GUID: "p-healthwatch-guid"},
+				}, nil)
+
+				command := commands.NewStagedConfig(fakeService, logger)
+				err := command.Execute([]string{"--all", "--output-dir", outputDir, "--include-credentials"})
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(outputDir, "products", "cf.yml"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			})
+
+			Context("failure cases", func() {
+				It("requires --output-dir", func() {
+					command := commands.NewStagedConfig(fakeService, logger)
+					err := command.Execute([]string{"--all"})
+					Expect(err).To(MatchError("--output-dir is required with --all"))
+				})
+
+				It("cannot be used with --product-name", func() {
+					command := commands.NewStagedConfig(fakeService, logger)
+					err := command.Execute([]string{"--all", "--output-dir", outputDir, "--product-name", "cf"})
+					Expect(err).To(MatchError("--product-name cannot be used with --all"))
+				})
+
+				It("returns an error when --output-dir is used without --all", func() {
+					command := commands.NewStagedConfig(fakeService, logger)
+					err := command.Execute([]string{"--output-dir", outputDir, "--product-name", "cf"})
+					Expect(err).To(MatchError("--output-dir can only be used with --all"))
+				})
+
+				It("returns an error when the staged products cannot be listed", func() {
+					fakeService.ListStagedProductsReturns(api.StagedProductsOutput{}, errors.New("some-error"))
+
+					command := commands.NewStagedConfig(fakeService, logger)
+					err := command.Execute([]string{"--all", "--output-dir", outputDir})
+					Expect(err).To(MatchError("could not list staged products: some-error"))
+				})
+
+				It("returns an error when the config of a product cannot be generated", func() {
+					fakeService.GetStagedProductPropertiesReturns(nil, errors.New("some-error"))
+
+					command := commands.NewStagedConfig(fakeService, logger)
+					err := command.Execute([]string{"--all", "--output-dir", outputDir})
+					Expect(err).To(MatchError("could not generate the config of cf: some-error"))
+				})
+
+				It("returns an error when the config of the director cannot be generated", func() {
+					fakeService.GetStagedDirectorNetworksReturns(api.NetworksConfigurationOutput{}, errors.New("some-error"))
+
+					command := commands.NewStagedConfig(fakeService, logger)
+					err := command.Execute([]string{"--all", "--output-dir", outputDir})
+					Expect(err).To(MatchError("could not generate the config of the director: some-error"))
+				})
+			})
 		})
 
 		Context("failure cases", func() {
@@ -403,6 +551,19 @@ errand-config:
 				})
 			})
 
+			Context("when listing errands fails", func() {
+				BeforeEach(func() {
+					fakeService.ListStagedProductErrandsReturns(api.ErrandsListOutput{}, errors.New("some-error"))
+				})
+
+				It("returns an error", func() {
+					command := commands.NewStagedConfig(fakeService, logger)
+					err := command.Execute([]string{
+						"--product-name", "some-product",
+					})
+					Expect(err).To(MatchError("some-error"))
+				})
+			})
 		})
 
 		Describe("Usage", func() {
@@ -411,7 +572,7 @@ errand-config:
 				command := commands.NewStagedConfig(nil, nil)
 
 				Expect(command.Usage()).To(Equal(jhanda.Usage{
-					Description:      "This command generates a config from a staged product that can be passed in to om configure-product (Note: credentials are not available and will appear as '***')",
+					Description:      "This command generates a config from a staged product that can be passed in to om configure-product, including its properties, networks, resource config and errand states (Note: credentials are not available and are left out, use --include-placeholders to replace them with interpolatable placeholders). With --all, the configs of the director and of every staged product are written to --output-dir",
 					ShortDescription: "**EXPERIMENTAL** generates a config from a staged product",
 					Flags:            command.Options,
 				}))
@@ -467,6 +628,8 @@ resource-config:
     instances: 1
     instance_type:
       id: automatic
+    persistent_disk:
+      size_mb: "20480"
 errand-config:
   first-errand:
     post-deploy-state: true
@@ -669,6 +832,7 @@ func setFakeService(internalSelector api.ResponseProperty) *fakes.StagedConfigSe
 		Errands: []api.Errand{
 			{Name: "first-errand", PostDeploy: true, PreDelete: "do-something"},
 			{Name: "second-errand", PostDeploy: false},
+			{Name: "third-errand"},
 		},
 	}, nil)
 	fakeService.ListStagedProductJobsReturns(map[string]string{
@@ -678,7 +842,8 @@ func setFakeService(internalSelector api.ResponseProperty) *fakes.StagedConfigSe
 		InstanceType: api.InstanceType{
 			ID: "automatic",
 		},
-		Instances: 1,
+		Instances:      1,
+		PersistentDisk: &api.Disk{Size: "20480"},
 	}, nil)
 
 	return fakeService
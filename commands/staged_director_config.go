@@ -16,7 +16,7 @@ type StagedDirectorConfig struct {
 	Options struct {
 		IncludeCredentials  bool `long:"include-credentials" short:"c" description:"include credentials. note: requires product to have been deployed"`
 		IncludePlaceholders bool `long:"include-placeholders" short:"r" description:"replace obscured credentials to interpolatable placeholders"`
-		NoRedact            bool `long:"no-redact" description:"Redact IaaS values from director configuration"`
+		NoRedact            bool `long:"no-redact" description:"include the IaaS credentials of the director, which are redacted by default"`
 	}
 }
 
@@ -44,7 +44,7 @@ func NewStagedDirectorConfig(service stagedDirectorConfigService, logger logger)
 
 func (ec StagedDirectorConfig) Usage() jhanda.Usage {
 	return jhanda.Usage{
-		Description:      "This command generates a config from a staged director that can be passed in to om configure-director",
+		Description:      "This command generates a config from a staged director that can be passed in to om configure-director. The IaaS configuration is only included with --no-redact, --include-credentials or --include-placeholders",
 		ShortDescription: "**EXPERIMENTAL** generates a config from a staged director",
 		Flags:            ec.Options,
 	}
@@ -52,7 +52,7 @@ func (ec StagedDirectorConfig) Usage() jhanda.Usage {
 
 func (ec StagedDirectorConfig) Execute(args []string) error {
 	if _, err := jhanda.Parse(&ec.Options, args); err != nil {
-		return fmt.Errorf("could not parse staged-config flags: %s", err)
+		return fmt.Errorf("could not parse staged-director-config flags: %s", err)
 	}
 
 	stagedDirector, err := ec.service.GetStagedProductByName("p-bosh")
@@ -111,7 +111,7 @@ func (ec StagedDirectorConfig) Execute(args []string) error {
 	}
 	config["resource-configuration"] = resourceConfigs
 
-	if !ec.Options.IncludeCredentials && !ec.Options.IncludePlaceholders {
+	if !ec.Options.IncludeCredentials && !ec.Options.IncludePlaceholders && !ec.Options.NoRedact {
 		delete(config["properties-configuration"].(map[string]map[string]interface{}), "iaas_configuration")
 	}
 
@@ -147,6 +147,9 @@ func (ec StagedDirectorConfig) filterSecrets(prefix string, keyName string, valu
 
 	case string, nil:
 		if strings.Contains(prefix, "iaas_configuration") {
+			if ec.Options.NoRedact {
+				return value, nil
+			}
 			if ec.Options.IncludePlaceholders {
 				return "((" + prefix + "))", nil
 			}
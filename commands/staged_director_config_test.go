@@ -172,6 +172,54 @@ properties-configuration:
 			Expect(invocations[0]).To(Equal([]interface{}{false}))
 		})
 
+		It("includes the IaaS credentials when --no-redact is passed", func() {
+			command := commands.NewStagedDirectorConfig(fakeService, logger)
+			err := command.Execute([]string{"--no-redact"})
+			Expect(err).NotTo(HaveOccurred())
+
+			output := logger.PrintlnArgsForCall(0)
+			Expect(output).To(ContainElement(MatchYAML(`
+az-configuration:
+- name: some-az
+  iaas_configuration_guid: some-iaas-guid
+- name: some-other-az
+network-assignment:
+  network:
+    name: network-1
+  singleton_availability_zone:
+    name: some-az
+networks-configuration:
+  icmp_checks_enabled: false
+  networks:
+  - name: network-1
+resource-configuration:
+  some-job:
+    instances: 1
+    instance_type:
+      id: automatic
+vmextensions-configuration:
+  - name: vm_ext1
+    cloud_properties:
+      source_dest_check: false
+  - name: vm_ext2
+    cloud_properties:
+      key_name: operations_keypair
+properties-configuration:
+  syslog_configuration:
+    syslogconfig: awesome
+  security_configuration:
+    trusted_certificates: some-certificate
+  director_configuration:
+    max_threads: 5
+    encryption:
+      providers:
+        client_certificate: user_provided_cert
+  iaas_configuration:
+    key: some-key
+    project: project-id
+`)))
+		})
+
 		Describe("when getting availability_zones returns an empty array", func() {
 			It("doesn't return the az in the config", func() {
 				fakeService.GetStagedDirectorAvailabilityZonesReturns(api.AvailabilityZonesOutput{}, nil)
@@ -335,7 +383,7 @@ properties-configuration:
 			It("returns an error", func() {
 				command := commands.NewStagedDirectorConfig(fakeService, logger)
 				err := command.Execute([]string{"--badflag"})
-				Expect(err).To(MatchError("could not parse staged-config flags: flag provided but not defined: -badflag"))
+				Expect(err).To(MatchError("could not parse staged-director-config flags: flag provided but not defined: -badflag"))
 			})
 		})
 
@@ -432,7 +480,7 @@ properties-configuration:
 			command := commands.NewStagedDirectorConfig(nil, nil)
 
 			Expect(command.Usage()).To(Equal(jhanda.Usage{
-				Description:      "This command generates a config from a staged director that can be passed in to om configure-director",
+				Description:      "This command generates a config from a staged director that can be passed in to om configure-director. The IaaS configuration is only included with --no-redact, --include-credentials or --include-placeholders",
 				ShortDescription: "**EXPERIMENTAL** generates a config from a staged director",
 				Flags:            command.Options,
 			}))
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/models"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+type StemcellAssignments struct {
+	service   stemcellAssignmentsService
+	presenter presenters.FormattedPresenter
+	Options   struct {
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/stemcell_assignments_service.go --fake-name StemcellAssignmentsService . stemcellAssignmentsService
+type stemcellAssignmentsService interface {
+	ListStemcells() (api.ProductStemcells, error)
+}
+
+func NewStemcellAssignments(presenter presenters.FormattedPresenter, service stemcellAssignmentsService) StemcellAssignments {
+	return StemcellAssignments{
+		service:   service,
+		presenter: presenter,
+	}
+}
+
+func (sa StemcellAssignments) Execute(args []string) error {
+	if _, err := jhanda.Parse(&sa.Options, args); err != nil {
+		return fmt.Errorf("could not parse stemcell-assignments flags: %s", err)
+	}
+
+	productStemcells, err := sa.service.ListStemcells()
+	if err != nil {
+		return fmt.Errorf("failed to list stemcell assignments: %s", err)
+	}
+
+	assignments := []models.StemcellAssignment{}
+	for _, productStemcell := range productStemcells.Products {
+		if productStemcell.StagedForDeletion {
+			continue
+		}
+
+		availableVersions := productStemcell.AvailableVersions
+		if availableVersions == nil {
+			availableVersions = []string{}
+		}
+
+		assignments = append(assignments, models.StemcellAssignment{
+			ProductName:                productStemcell.ProductName,
+			RequiredStemcellOS:         productStemcell.RequiredStemcellOS,
+			RequiredStemcellVersion:    productStemcell.RequiredStemcellVersion,
+			StagedStemcellVersion:      productStemcell.StagedStemcellVersion,
+			AvailableStemcellVersions:  availableVersions,
+			CompatibleStemcellUploaded: len(availableVersions) > 0,
+		})
+	}
+
+	sa.presenter.SetFormat(sa.Options.Format)
+	sa.presenter.PresentStemcellAssignments(assignments)
+
+	return nil
+}
+
+func (sa StemcellAssignments) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This authenticated command lists the stemcell required by each staged product, the stemcell currently assigned, " +
+			"and the uploaded stemcells that are compatible with the product.",
+		ShortDescription: "lists the stemcells required by and assigned to staged products",
+		Flags:            sa.Options,
+	}
+}
@@ -0,0 +1,120 @@
+package commands_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/models"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("StemcellAssignments", func() {
+	var (
+		presenter   *presenterfakes.FormattedPresenter
+		fakeService *fakes.StemcellAssignmentsService
+		command     commands.StemcellAssignments
+	)
+
+	BeforeEach(func() {
+		presenter = &presenterfakes.FormattedPresenter{}
+		fakeService = &fakes.StemcellAssignmentsService{}
+		command = commands.NewStemcellAssignments(presenter, fakeService)
+	})
+
+	Describe("Execute", func() {
+		BeforeEach(func() {
+			fakeService.ListStemcellsReturns(api.ProductStemcells{
+				Products: []api.ProductStemcell{
+					{
+						GUID:                    "cf-guid",
+						ProductName:             "cf",
+						StagedStemcellVersion:   "97.28",
+						RequiredStemcellOS:      "ubuntu-xenial",
+						RequiredStemcellVersion: "97.18",
+						AvailableVersions:       []string{"97.28", "97.57"},
+					},
+					{
+						GUID:                    "p-redis-guid",
+						ProductName:             "p-redis",
+						RequiredStemcellOS:      "ubuntu-xenial",
+						RequiredStemcellVersion: "170.15",
+					},
+					{
+						GUID:              "p-mysql-guid",
+						ProductName:       "p-mysql",
+						StagedForDeletion: true,
+					},
+				},
+			}, nil)
+		})
+
+		It("presents the stemcell assignments of the staged products", func() {
+			err := command.Execute([]string{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(presenter.SetFormatArgsForCall(0)).To(Equal("table"))
+			Expect(presenter.PresentStemcellAssignmentsCallCount()).To(Equal(1))
+			Expect(presenter.PresentStemcellAssignmentsArgsForCall(0)).To(Equal([]models.StemcellAssignment{
+				{
+					ProductName:                "cf",
+					RequiredStemcellOS:         "ubuntu-xenial",
+					RequiredStemcellVersion:    "97.18",
+					StagedStemcellVersion:      "97.28",
+					AvailableStemcellVersions:  []string{"97.28", "97.57"},
+					CompatibleStemcellUploaded: true,
+				},
+				{
+					ProductName:                "p-redis",
+					RequiredStemcellOS:         "ubuntu-xenial",
+					RequiredStemcellVersion:    "170.15",
+					AvailableStemcellVersions:  []string{},
+					CompatibleStemcellUploaded: false,
+				},
+			}))
+		})
+
+		Context("when the format flag is provided", func() {
+			It("sets the format on the presenter", func() {
+				err := command.Execute([]string{"--format", "json"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(presenter.SetFormatArgsForCall(0)).To(Equal("json"))
+			})
+		})
+
+		Context("failure cases", func() {
+			Context("when an unknown flag is passed", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{"--unknown-flag"})
+					Expect(err).To(MatchError("could not parse stemcell-assignments flags: flag provided but not defined: -unknown-flag"))
+				})
+			})
+
+			Context("when the stemcell assignments cannot be listed", func() {
+				It("returns an error", func() {
+					fakeService.ListStemcellsReturns(api.ProductStemcells{}, errors.New("some api error"))
+
+					err := command.Execute([]string{})
+					Expect(err).To(MatchError("failed to list stemcell assignments: some api error"))
+				})
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			command := commands.NewStemcellAssignments(nil, nil)
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description: "This authenticated command lists the stemcell required by each staged product, the stemcell currently assigned, " +
+					"and the uploaded stemcells that are compatible with the product.",
+				ShortDescription: "lists the stemcells required by and assigned to staged products",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -18,6 +18,7 @@ type UnstageProduct struct {
 //go:generate counterfeiter -o ./fakes/unstage_product_service.go --fake-name UnstageProductService . unstageProductService
 type unstageProductService interface {
 	DeleteStagedProduct(api.UnstageProductInput) error
+	ListDeployedProducts() ([]api.DeployedProductOutput, error)
 }
 
 func NewUnstageProduct(service unstageProductService, logger logger) UnstageProduct {
@@ -32,9 +33,21 @@ func (up UnstageProduct) Execute(args []string) error {
 		return fmt.Errorf("could not parse unstage-product flags: %s", err)
 	}
 
+	deployedProducts, err := up.service.ListDeployedProducts()
+	if err != nil {
+		return fmt.Errorf("failed to unstage product: %s", err)
+	}
+
+	for _, deployedProduct := range deployedProducts {
+		if deployedProduct.Type == up.Options.Product {
+			up.logger.Printf("warning: %s is already deployed, it will be deleted on the next apply-changes", up.Options.Product)
+			break
+		}
+	}
+
 	up.logger.Printf("unstaging %s", up.Options.Product)
 
-	err := up.service.DeleteStagedProduct(api.UnstageProductInput{
+	err = up.service.DeleteStagedProduct(api.UnstageProductInput{
 		ProductName: up.Options.Product,
 	})
 
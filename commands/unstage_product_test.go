@@ -45,6 +45,30 @@ var _ = Describe("UnstageProduct", func() {
 		Expect(fmt.Sprintf(format, v...)).To(Equal("finished unstaging"))
 	})
 
+	Context("when the product has already been deployed", func() {
+		It("warns that the product will be deleted on the next apply-changes", func() {
+			fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+				{Type: "some-other-product", GUID: "other-guid"},
+				{Type: "some-product", GUID: "some-guid"},
+			}, nil)
+
+			command := commands.NewUnstageProduct(fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product-name", "some-product",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.DeleteStagedProductCallCount()).To(Equal(1))
+
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("warning: some-product is already deployed, it will be deleted on the next apply-changes"))
+
+			format, v = logger.PrintfArgsForCall(1)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("unstaging some-product"))
+		})
+	})
+
 	Context("failure cases", func() {
 		Context("when an unknown flag is provided", func() {
 			It("returns an error", func() {
@@ -62,6 +86,17 @@ var _ = Describe("UnstageProduct", func() {
 			})
 		})
 
+		Context("when the deployed products cannot be fetched", func() {
+			It("returns an error", func() {
+				command := commands.NewUnstageProduct(fakeService, logger)
+				fakeService.ListDeployedProductsReturns(nil, errors.New("could not fetch deployed products"))
+
+				err := command.Execute([]string{"--product-name", "some-product"})
+				Expect(err).To(MatchError("failed to unstage product: could not fetch deployed products"))
+				Expect(fakeService.DeleteStagedProductCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when the product cannot be unstaged", func() {
 			It("returns an error", func() {
 				command := commands.NewUnstageProduct(fakeService, logger)
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
@@ -11,8 +12,10 @@ type UpdateSSLCertificate struct {
 	service updateSSLCertificateService
 	logger  logger
 	Options struct {
-		CertPem    string `long:"certificate-pem" required:"true" description:"certificate"`
-		PrivateKey string `long:"private-key-pem" required:"true" description:"private key"`
+		CertPem        string `long:"certificate-pem"      description:"certificate"`
+		CertPemFile    string `long:"certificate-pem-file" description:"file containing the certificate, instead of --certificate-pem"`
+		PrivateKey     string `long:"private-key-pem"      description:"private key"`
+		PrivateKeyFile string `long:"private-key-pem-file" description:"file containing the private key, instead of --private-key-pem"`
 	}
 }
 
@@ -30,9 +33,19 @@ func (c UpdateSSLCertificate) Execute(args []string) error {
 		return fmt.Errorf("could not parse update-ssl-certificate flags: %s", err)
 	}
 
-	err := c.service.UpdateSSLCertificate(api.SSLCertificateInput{
-		CertPem:       c.Options.CertPem,
-		PrivateKeyPem: c.Options.PrivateKey,
+	certPem, err := pemFromFlags(c.Options.CertPem, c.Options.CertPemFile, "certificate-pem")
+	if err != nil {
+		return fmt.Errorf("could not parse update-ssl-certificate flags: %s", err)
+	}
+
+	privateKey, err := pemFromFlags(c.Options.PrivateKey, c.Options.PrivateKeyFile, "private-key-pem")
+	if err != nil {
+		return fmt.Errorf("could not parse update-ssl-certificate flags: %s", err)
+	}
+
+	err = c.service.UpdateSSLCertificate(api.SSLCertificateInput{
+		CertPem:       certPem,
+		PrivateKeyPem: privateKey,
 	})
 	if err != nil {
 		return err
@@ -44,6 +57,25 @@ func (c UpdateSSLCertificate) Execute(args []string) error {
 	return nil
 }
 
+// pemFromFlags returns the PEM given to --<flag>, or the contents of the file
+// given to --<flag>-file; exactly one of them is required.
+func pemFromFlags(pem, file, flag string) (string, error) {
+	switch {
+	case pem != "" && file != "":
+		return "", fmt.Errorf("--%s and --%s-file cannot both be given", flag, flag)
+	case file != "":
+		contents, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("could not read --%s-file: %s", flag, err)
+		}
+		return string(contents), nil
+	case pem != "":
+		return pem, nil
+	default:
+		return "", fmt.Errorf("missing required flag \"--%s\" or \"--%s-file\"", flag, flag)
+	}
+}
+
 func (c UpdateSSLCertificate) Usage() jhanda.Usage {
 	return jhanda.Usage{
 		Description:      "This authenticated command updates the SSL Certificate on the Ops Manager with the given cert and key",
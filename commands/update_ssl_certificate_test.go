@@ -3,6 +3,8 @@ package commands_test
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -40,6 +42,33 @@ var _ = Describe("UpdateSSLCertificate", func() {
 			}))
 		})
 
+		It("reads the certificate and the private key from files", func() {
+			certFile, err := ioutil.TempFile("", "cert")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(certFile.Name())
+			_, err = certFile.WriteString("some CertPem")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(certFile.Close()).To(Succeed())
+
+			keyFile, err := ioutil.TempFile("", "key")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(keyFile.Name())
+			_, err = keyFile.WriteString("some PrivateKey")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keyFile.Close()).To(Succeed())
+
+			err = command.Execute([]string{
+				"--certificate-pem-file", certFile.Name(),
+				"--private-key-pem-file", keyFile.Name(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.UpdateSSLCertificateArgsForCall(0)).To(Equal(api.SSLCertificateInput{
+				CertPem:       "some CertPem",
+				PrivateKeyPem: "some PrivateKey",
+			}))
+		})
+
 		It("prints a success message saying the custom cert was applied", func() {
 			fakeService.UpdateSSLCertificateReturns(nil)
 
@@ -81,7 +110,29 @@ var _ = Describe("UpdateSSLCertificate", func() {
 					err := command.Execute([]string{
 						"--private-key-pem", "some PrivateKey",
 					})
-					Expect(err).To(MatchError("could not parse update-ssl-certificate flags: missing required flag \"--certificate-pem\""))
+					Expect(err).To(MatchError("could not parse update-ssl-certificate flags: missing required flag \"--certificate-pem\" or \"--certificate-pem-file\""))
+				})
+			})
+
+			Context("when both the certificate and its file are provided", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{
+						"--certificate-pem", "some CertPem",
+						"--certificate-pem-file", "some-file",
+						"--private-key-pem", "some PrivateKey",
+					})
+					Expect(err).To(MatchError("could not parse update-ssl-certificate flags: --certificate-pem and --certificate-pem-file cannot both be given"))
+					Expect(fakeService.UpdateSSLCertificateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the private key file cannot be read", func() {
+				It("returns an error", func() {
+					err := command.Execute([]string{
+						"--certificate-pem", "some CertPem",
+						"--private-key-pem-file", "/does/not/exist",
+					})
+					Expect(err).To(MatchError(ContainSubstring("could not parse update-ssl-certificate flags: could not read --private-key-pem-file: open /does/not/exist")))
 				})
 			})
 
@@ -90,7 +141,7 @@ var _ = Describe("UpdateSSLCertificate", func() {
 					err := command.Execute([]string{
 						"--certificate-pem", "some CertPem",
 					})
-					Expect(err).To(MatchError("could not parse update-ssl-certificate flags: missing required flag \"--private-key-pem\""))
+					Expect(err).To(MatchError("could not parse update-ssl-certificate flags: missing required flag \"--private-key-pem\" or \"--private-key-pem-file\""))
 				})
 			})
 		})
@@ -0,0 +1,325 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"gopkg.in/yaml.v2"
+)
+
+// The checkpoints of upgrade-opsman, in the order they are reached.
+const (
+	upgradeOpsmanExported      = "exported"
+	upgradeOpsmanImported      = "imported"
+	upgradeOpsmanAuthenticated = "authenticated"
+	upgradeOpsmanVerified      = "verified"
+)
+
+type UpgradeOpsman struct {
+	multipart  multipart
+	service    upgradeOpsmanService
+	passphrase string
+	logger     logger
+	Options    struct {
+		Installation      string        `long:"installation"       short:"i"  required:"true"            description:"path to export the installation of the current Ops Manager to, and to import it from"`
+		StateFile         string        `long:"state-file"         short:"s"  required:"true"            description:"path to the file recording the checkpoints of the upgrade, so that it resumes where it stopped when run again"`
+		EncryptPassphrase string        `long:"encrypt-passphrase"            env:"OM_ENCRYPT_PASSPHRASE" description:"encrypts the exported installation with a key derived from this passphrase, see export-installation"`
+		EncryptKeyFile    string        `long:"encrypt-key-file"                                         description:"encrypts the exported installation with the key of this file, see export-installation"`
+		PollingInterval   int           `long:"polling-interval"   short:"pi" default:"10"               description:"interval (in seconds) to check Ops Manager availability during the import"`
+		Timeout           time.Duration `long:"timeout"                                                  description:"stop waiting for the new Ops Manager to be available after this long, e.g. 30m (default: 1h)"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/upgrade_opsman_service.go --fake-name UpgradeOpsmanService . upgradeOpsmanService
+type upgradeOpsmanService interface {
+	EnsureAvailability(input api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error)
+	DownloadInstallationAssetCollection(outputFile string, resume bool) (api.DownloadInstallationAssetCollectionOutput, error)
+	UploadInstallationAssetCollection(api.ImportInstallationInput) error
+	Info() (api.Info, error)
+	ListDeployedProducts() ([]api.DeployedProductOutput, error)
+}
+
+// upgradeOpsmanState is what the state file records of the current Ops Manager,
+// to verify the new one against it.
+type upgradeOpsmanState struct {
+	Checkpoint       string                 `yaml:"checkpoint"`
+	Installation     string                 `yaml:"installation"`
+	OpsmanVersion    string                 `yaml:"opsman-version"`
+	DeployedProducts []upgradeOpsmanProduct `yaml:"deployed-products"`
+}
+
+type upgradeOpsmanProduct struct {
+	Type string `yaml:"type"`
+	GUID string `yaml:"guid"`
+}
+
+func NewUpgradeOpsman(multipart multipart, service upgradeOpsmanService, passphrase string, logger logger) UpgradeOpsman {
+	return UpgradeOpsman{
+		multipart:  multipart,
+		service:    service,
+		passphrase: passphrase,
+		logger:     logger,
+	}
+}
+
+func (u UpgradeOpsman) Execute(args []string) error {
+	if _, err := jhanda.Parse(&u.Options, args); err != nil {
+		return fmt.Errorf("could not parse upgrade-opsman flags: %s", err)
+	}
+
+	if u.passphrase == "" {
+		return fmt.Errorf("the global decryption-passphrase argument is required for this command")
+	}
+
+	state, err := u.loadState()
+	if err != nil {
+		return err
+	}
+
+	if state.Checkpoint == "" {
+		return u.export(&state)
+	}
+
+	if state.Checkpoint == upgradeOpsmanExported {
+		err = u.importInstallation(&state)
+		if err != nil {
+			return err
+		}
+	}
+
+	if state.Checkpoint == upgradeOpsmanImported {
+		err = u.authenticate(&state)
+		if err != nil {
+			return err
+		}
+	}
+
+	if state.Checkpoint == upgradeOpsmanAuthenticated {
+		return u.verify(&state)
+	}
+
+	u.logger.Printf("the upgrade recorded in %s is already complete, remove it to upgrade again", u.Options.StateFile)
+	return nil
+}
+
+// export exports the installation of the current Ops Manager, and records
+// what the new one is verified against.
+func (u UpgradeOpsman) export(state *upgradeOpsmanState) error {
+	availability, err := u.service.EnsureAvailability(api.EnsureAvailabilityInput{})
+	if err != nil {
+		return fmt.Errorf("could not check Ops Manager status: %s", err)
+	}
+
+	if availability.Status != api.EnsureAvailabilityStatusComplete {
+		return fmt.Errorf("the Ops Manager targeted is not configured, there is no installation to export")
+	}
+
+	info, err := u.service.Info()
+	if err != nil {
+		return fmt.Errorf("could not get the version of the current Ops Manager: %s", err)
+	}
+
+	deployedProducts, err := u.service.ListDeployedProducts()
+	if err != nil {
+		return fmt.Errorf("could not list the deployed products of the current Ops Manager: %s", err)
+	}
+
+	err = NewExportInstallation(u.service, u.logger).Execute(u.encryptionArgs("--output-file", u.Options.Installation))
+	if err != nil {
+		return err
+	}
+
+	state.Installation = u.Options.Installation
+	state.OpsmanVersion = info.Version
+	state.DeployedProducts = nil
+	for _, product := range deployedProducts {
+		state.DeployedProducts = append(state.DeployedProducts, upgradeOpsmanProduct{Type: product.Type, GUID: product.GUID})
+	}
+
+	err = u.checkpoint(state, upgradeOpsmanExported)
+	if err != nil {
+		return err
+	}
+
+	u.logger.Printf("exported the installation of Ops Manager %s to %s", info.Version, u.Options.Installation)
+	u.logger.Printf("replace the Ops Manager VM with the new one, at the same address, then run upgrade-opsman again to import the installation")
+
+	return nil
+}
+
+// importInstallation imports the installation once the new Ops Manager VM is
+// running, and is not configured yet.
+func (u UpgradeOpsman) importInstallation(state *upgradeOpsmanState) error {
+	availability, err := u.service.EnsureAvailability(api.EnsureAvailabilityInput{})
+	if err != nil {
+		return fmt.Errorf("could not check the status of the new Ops Manager: %s", err)
+	}
+
+	switch availability.Status {
+	case api.EnsureAvailabilityStatusUnstarted:
+		u.logger.Printf("verified that the new Ops Manager VM is not configured")
+	case api.EnsureAvailabilityStatusComplete:
+		return fmt.Errorf("the Ops Manager targeted is already configured: replace the Ops Manager VM with the new one, then run upgrade-opsman again to import the installation\nif the installation has already been imported, set the checkpoint of %s to %s", u.Options.StateFile, upgradeOpsmanImported)
+	default:
+		return fmt.Errorf("the new Ops Manager is not ready yet, run upgrade-opsman again once it has started")
+	}
+
+	args := u.encryptionArgs(
+		"--installation", state.Installation,
+		"--polling-interval", strconv.Itoa(u.Options.PollingInterval),
+	)
+	if u.Options.Timeout > 0 {
+		args = append(args, "--timeout", u.Options.Timeout.String())
+	}
+
+	err = NewImportInstallation(u.multipart, u.service, u.passphrase, u.logger).Execute(args)
+	if err != nil {
+		return err
+	}
+
+	return u.checkpoint(state, upgradeOpsmanImported)
+}
+
+// authenticate verifies that the new Ops Manager authenticates with the
+// credentials of the imported installation, and is decrypted with its passphrase.
+func (u UpgradeOpsman) authenticate(state *upgradeOpsmanState) error {
+	_, err := u.service.Info()
+	if err != nil {
+		return fmt.Errorf("could not authenticate to the new Ops Manager with the credentials of the imported installation: %s", err)
+	}
+
+	u.logger.Printf("authenticated to the new Ops Manager")
+
+	return u.checkpoint(state, upgradeOpsmanAuthenticated)
+}
+
+// verify checks that the new Ops Manager is at least the version of the
+// current one, and knows of the products that were deployed.
+func (u UpgradeOpsman) verify(state *upgradeOpsmanState) error {
+	info, err := u.service.Info()
+	if err != nil {
+		return fmt.Errorf("could not get the version of the new Ops Manager: %s", err)
+	}
+
+	previous, err := version.NewVersion(state.OpsmanVersion)
+	if err != nil {
+		return fmt.Errorf("could not parse the version of the previous Ops Manager %q: %s", state.OpsmanVersion, err)
+	}
+
+	current, err := version.NewVersion(info.Version)
+	if err != nil {
+		return fmt.Errorf("could not parse the version of the new Ops Manager %q: %s", info.Version, err)
+	}
+
+	if current.LessThan(previous) {
+		return fmt.Errorf("the new Ops Manager %s is older than the previous Ops Manager %s", info.Version, state.OpsmanVersion)
+	}
+
+	deployedProducts, err := u.service.ListDeployedProducts()
+	if err != nil {
+		return fmt.Errorf("could not list the deployed products of the new Ops Manager: %s", err)
+	}
+
+	deployed := map[string]bool{}
+	for _, product := range deployedProducts {
+		deployed[product.GUID] = true
+	}
+
+	var missing []string
+	for _, product := range state.DeployedProducts {
+		if !deployed[product.GUID] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", product.Type, product.GUID))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("the new Ops Manager is missing the deployed products of the imported installation: %s", strings.Join(missing, ", "))
+	}
+
+	err = u.checkpoint(state, upgradeOpsmanVerified)
+	if err != nil {
+		return err
+	}
+
+	if previous.Equal(current) {
+		u.logger.Printf("the new Ops Manager is the same version as the previous one: %s", info.Version)
+	}
+
+	u.logger.Printf("upgraded Ops Manager from %s to %s, with %d deployed products", state.OpsmanVersion, info.Version, len(state.DeployedProducts))
+
+	return nil
+}
+
+func (u UpgradeOpsman) encryptionArgs(args ...string) []string {
+	if u.Options.EncryptPassphrase != "" {
+		args = append(args, "--encrypt-passphrase", u.Options.EncryptPassphrase)
+	}
+
+	if u.Options.EncryptKeyFile != "" {
+		args = append(args, "--encrypt-key-file", u.Options.EncryptKeyFile)
+	}
+
+	return args
+}
+
+func (u UpgradeOpsman) loadState() (upgradeOpsmanState, error) {
+	var state upgradeOpsmanState
+
+	contents, err := ioutil.ReadFile(u.Options.StateFile)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("could not read the state file %s: %s", u.Options.StateFile, err)
+	}
+
+	err = yaml.UnmarshalStrict(contents, &state)
+	if err != nil {
+		return state, fmt.Errorf("could not parse the state file %s: %s", u.Options.StateFile, err)
+	}
+
+	switch state.Checkpoint {
+	case "", upgradeOpsmanExported, upgradeOpsmanImported, upgradeOpsmanAuthenticated, upgradeOpsmanVerified:
+	default:
+		return state, fmt.Errorf("unknown checkpoint %q in the state file %s", state.Checkpoint, u.Options.StateFile)
+	}
+
+	if state.Checkpoint != "" && state.Installation != u.Options.Installation {
+		return state, fmt.Errorf("the upgrade recorded in %s exported the installation to %s, not %s", u.Options.StateFile, state.Installation, u.Options.Installation)
+	}
+
+	return state, nil
+}
+
+func (u UpgradeOpsman) checkpoint(state *upgradeOpsmanState, checkpoint string) error {
+	state.Checkpoint = checkpoint
+
+	contents, err := yaml.Marshal(state)
+	if err != nil {
+		return err // un-tested
+	}
+
+	err = ioutil.WriteFile(u.Options.StateFile, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write the state file %s: %s", u.Options.StateFile, err)
+	}
+
+	u.logger.Printf("checkpoint: %s", checkpoint)
+
+	return nil
+}
+
+func (u UpgradeOpsman) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command upgrades the Ops Manager targeted, in steps that are recorded in the state file: it exports the installation of the current Ops Manager, waits for its VM to be replaced with the new Ops Manager VM, imports the installation, then verifies the authentication, the version and the deployed products of the new Ops Manager. The command stops once the installation is exported, or when a step fails; running it again resumes from the last checkpoint.",
+		ShortDescription: "upgrades the Ops Manager by exporting and importing its installation",
+		Flags:            u.Options,
+	}
+}
@@ -0,0 +1,286 @@
+package commands_test
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/formcontent"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpgradeOpsman", func() {
+	var (
+		fakeService  *fakes.UpgradeOpsmanService
+		multipart    *fakes.Multipart
+		logger       *fakes.Logger
+		dir          string
+		installation string
+		stateFile    string
+		command      commands.UpgradeOpsman
+	)
+
+	logMessages := func() []string {
+		var messages []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, v := logger.PrintfArgsForCall(i)
+			messages = append(messages, fmt.Sprintf(format, v...))
+		}
+		return messages
+	}
+
+	writeState := func(state string) {
+		Expect(ioutil.WriteFile(stateFile, []byte(state), 0600)).To(Succeed())
+	}
+
+	readState := func() string {
+		contents, err := ioutil.ReadFile(stateFile)
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	execute := func(args ...string) error {
+		return command.Execute(append([]string{
+			"--installation", installation,
+			"--state-file", stateFile,
+			"--polling-interval", "0",
+		}, args...))
+	}
+
+	BeforeEach(func() {
+		fakeService = &fakes.UpgradeOpsmanService{}
+		multipart = &fakes.Multipart{}
+		logger = &fakes.Logger{}
+
+		var err error
+		dir, err = ioutil.TempDir("", "upgrade-opsman")
+		Expect(err).NotTo(HaveOccurred())
+		installation = filepath.Join(dir, "installation.zip")
+		stateFile = filepath.Join(dir, "state.yml")
+
+		fakeService.DownloadInstallationAssetCollectionStub = func(path string, _ bool) (api.DownloadInstallationAssetCollectionOutput, error) {
+			file, err := os.Create(path)
+			Expect(err).NotTo(HaveOccurred())
+			defer file.Close()
+
+			archive := zip.NewWriter(file)
+			_, err = archive.Create("installation.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archive.Close()).To(Succeed())
+
+			return api.DownloadInstallationAssetCollectionOutput{}, nil
+		}
+		fakeService.ListDeployedProductsReturns([]api.DeployedProductOutput{
+			{Type: "p-bosh", GUID: "p-bosh-guid"},
+			{Type: "cf", GUID: "cf-guid"},
+		}, nil)
+		multipart.FinalizeReturns(formcontent.ContentSubmission{ContentType: "some content-type", ContentLength: 10})
+
+		command = commands.NewUpgradeOpsman(multipart, fakeService, "some-passphrase", logger)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("exports the installation, then imports it once the VM is replaced and verifies the new Ops Manager", func() {
+		fakeService.EnsureAvailabilityReturns(api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil)
+		fakeService.InfoReturns(api.Info{Version: "2.4-build.100"}, nil)
+
+		err := execute()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeService.DownloadInstallationAssetCollectionCallCount()).To(Equal(1))
+		Expect(fakeService.UploadInstallationAssetCollectionCallCount()).To(Equal(0))
+		Expect(readState()).To(Equal(fmt.Sprintf(`checkpoint: exported
+installation: %s
+opsman-version: 2.4-build.100
+deployed-products:
+- type: p-bosh
+  guid: p-bosh-guid
+- type: cf
+  guid: cf-guid
+`, installation)))
+		Expect(logMessages()).To(ContainElement(fmt.Sprintf("exported the installation of Ops Manager 2.4-build.100 to %s", installation)))
+		Expect(logMessages()).To(ContainElement("replace the Ops Manager VM with the new one, at the same address, then run upgrade-opsman again to import the installation"))
+
+		By("importing the installation in the new Ops Manager")
+		ensureAvailabilityCalls := fakeService.EnsureAvailabilityCallCount()
+		fakeService.EnsureAvailabilityStub = func(api.EnsureAvailabilityInput) (api.EnsureAvailabilityOutput, error) {
+			// the new VM is verified, then checked by import-installation before the import
+			if fakeService.EnsureAvailabilityCallCount()-ensureAvailabilityCalls <= 2 {
+				return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusUnstarted}, nil
+			}
+			return api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil
+		}
+		fakeService.InfoReturns(api.Info{Version: "2.5-build.200"}, nil)
+		logger = &fakes.Logger{}
+		command = commands.NewUpgradeOpsman(multipart, fakeService, "some-passphrase", logger)
+
+		err = execute()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeService.UploadInstallationAssetCollectionCallCount()).To(Equal(1))
+		_, file := multipart.AddFileArgsForCall(0)
+		Expect(file).To(Equal(installation))
+
+		Expect(readState()).To(ContainSubstring("checkpoint: verified\n"))
+		messages := logMessages()
+		Expect(messages[0]).To(Equal("verified that the new Ops Manager VM is not configured"))
+		Expect(messages).To(ContainElement("checkpoint: imported"))
+		Expect(messages).To(ContainElement("authenticated to the new Ops Manager"))
+		Expect(messages).To(ContainElement("checkpoint: authenticated"))
+		Expect(messages).To(ContainElement("checkpoint: verified"))
+		Expect(messages[len(messages)-1]).To(Equal("upgraded Ops Manager from 2.4-build.100 to 2.5-build.200, with 2 deployed products"))
+
+		By("doing nothing once the upgrade is complete")
+		logger = &fakes.Logger{}
+		command = commands.NewUpgradeOpsman(multipart, fakeService, "some-passphrase", logger)
+
+		err = execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeService.UploadInstallationAssetCollectionCallCount()).To(Equal(1))
+		Expect(logMessages()).To(Equal([]string{
+			fmt.Sprintf("the upgrade recorded in %s is already complete, remove it to upgrade again", stateFile),
+		}))
+	})
+
+	It("resumes from the last checkpoint", func() {
+		writeState(fmt.Sprintf(`checkpoint: imported
+installation: %s
+opsman-version: 2.4-build.100
+deployed-products:
+- type: cf
+  guid: cf-guid
+`, installation))
+		fakeService.InfoReturnsOnCall(0, api.Info{}, errors.New("some error"))
+		fakeService.InfoReturns(api.Info{Version: "2.4-build.100"}, nil)
+
+		err := execute()
+		Expect(err).To(MatchError("could not authenticate to the new Ops Manager with the credentials of the imported installation: some error"))
+		Expect(readState()).To(ContainSubstring("checkpoint: imported\n"))
+
+		err = execute()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeService.DownloadInstallationAssetCollectionCallCount()).To(Equal(0))
+		Expect(fakeService.UploadInstallationAssetCollectionCallCount()).To(Equal(0))
+		Expect(readState()).To(ContainSubstring("checkpoint: verified\n"))
+		Expect(logMessages()).To(ContainElement("the new Ops Manager is the same version as the previous one: 2.4-build.100"))
+	})
+
+	It("passes the encryption of the installation to the export and the import", func() {
+		fakeService.EnsureAvailabilityReturns(api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil)
+		fakeService.InfoReturns(api.Info{Version: "2.4-build.100"}, nil)
+
+		err := execute("--encrypt-passphrase", "some-encryption-passphrase")
+		Expect(err).NotTo(HaveOccurred())
+
+		contents, err := ioutil.ReadFile(installation)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(HavePrefix("om-encrypted-v1\n"))
+	})
+
+	Context("failure cases", func() {
+		It("requires the global decryption passphrase", func() {
+			command = commands.NewUpgradeOpsman(multipart, fakeService, "", logger)
+
+			err := execute()
+			Expect(err).To(MatchError("the global decryption-passphrase argument is required for this command"))
+		})
+
+		It("returns an error when an unknown flag is provided", func() {
+			err := command.Execute([]string{"--badflag"})
+			Expect(err).To(MatchError("could not parse upgrade-opsman flags: flag provided but not defined: -badflag"))
+		})
+
+		It("returns an error when the current Ops Manager is not configured", func() {
+			fakeService.EnsureAvailabilityReturns(api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusUnstarted}, nil)
+
+			err := execute()
+			Expect(err).To(MatchError("the Ops Manager targeted is not configured, there is no installation to export"))
+			Expect(stateFile).NotTo(BeAnExistingFile())
+		})
+
+		It("does not record a checkpoint when the export fails", func() {
+			fakeService.EnsureAvailabilityReturns(api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil)
+			fakeService.DownloadInstallationAssetCollectionStub = nil
+			fakeService.DownloadInstallationAssetCollectionReturns(api.DownloadInstallationAssetCollectionOutput{}, errors.New("some error"))
+
+			err := execute()
+			Expect(err).To(MatchError(ContainSubstring("failed to export installation: some error")))
+			Expect(stateFile).NotTo(BeAnExistingFile())
+		})
+
+		It("returns an error when the VM has not been replaced yet", func() {
+			writeState(fmt.Sprintf("checkpoint: exported\ninstallation: %s\nopsman-version: 2.4-build.100\n", installation))
+			fakeService.EnsureAvailabilityReturns(api.EnsureAvailabilityOutput{Status: api.EnsureAvailabilityStatusComplete}, nil)
+
+			err := execute()
+			Expect(err).To(MatchError(ContainSubstring("the Ops Manager targeted is already configured: replace the Ops Manager VM with the new one")))
+			Expect(fakeService.UploadInstallationAssetCollectionCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the new Ops Manager is older", func() {
+			writeState(fmt.Sprintf("checkpoint: authenticated\ninstallation: %s\nopsman-version: 2.5-build.100\n", installation))
+			fakeService.InfoReturns(api.Info{Version: "2.4-build.300"}, nil)
+
+			err := execute()
+			Expect(err).To(MatchError("the new Ops Manager 2.4-build.300 is older than the previous Ops Manager 2.5-build.100"))
+			Expect(readState()).To(ContainSubstring("checkpoint: authenticated\n"))
+		})
+
+		It("returns an error when deployed products are missing", func() {
+			writeState(fmt.Sprintf(`checkpoint: authenticated
+installation: %s
+opsman-version: 2.4-build.100
+deployed-products:
+- type: cf
+  guid: cf-guid
+- type: p-mysql
+  guid: p-mysql-guid
+`, installation))
+			fakeService.InfoReturns(api.Info{Version: "2.5-build.100"}, nil)
+
+			err := execute()
+			Expect(err).To(MatchError("the new Ops Manager is missing the deployed products of the imported installation: p-mysql (p-mysql-guid)"))
+		})
+
+		It("returns an error when the state file belongs to another installation", func() {
+			writeState("checkpoint: exported\ninstallation: /some/other/installation.zip\n")
+
+			err := execute()
+			Expect(err).To(MatchError(fmt.Sprintf("the upgrade recorded in %s exported the installation to /some/other/installation.zip, not %s", stateFile, installation)))
+		})
+
+		It("returns an error when the state file is invalid", func() {
+			writeState("checkpoint: somewhere\n")
+
+			err := execute()
+			Expect(err).To(MatchError(fmt.Sprintf("unknown checkpoint \"somewhere\" in the state file %s", stateFile)))
+
+			writeState("some-key: some-value\n")
+
+			err = execute()
+			Expect(err).To(MatchError(ContainSubstring("could not parse the state file")))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This command upgrades the Ops Manager targeted, in steps that are recorded in the state file: it exports the installation of the current Ops Manager, waits for its VM to be replaced with the new Ops Manager VM, imports the installation, then verifies the authentication, the version and the deployed products of the new Ops Manager. The command stops once the installation is exported, or when a step fails; running it again resumes from the last checkpoint.",
+				ShortDescription: "upgrades the Ops Manager by exporting and importing its installation",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
@@ -7,6 +7,7 @@ import (
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/extractor"
 	"github.com/pivotal-cf/om/network"
+	"github.com/pivotal-cf/om/telemetry"
 	"github.com/pivotal-cf/om/validator"
 )
 
@@ -18,10 +19,11 @@ type UploadProduct struct {
 	service   uploadProductService
 	Options   struct {
 		ConfigFile      string `long:"config"           short:"c"   description:"path to yml file for configuration (keys must match the following command line flags)"`
-		Product         string `long:"product"          short:"p"   description:"path to product" required:"true"`
+		Product         string `long:"product"          short:"p"   description:"path to product, or an http(s):// or s3:// URL to stream the product from" required:"true"`
 		PollingInterval int    `long:"polling-interval" short:"pi"  description:"interval (in seconds) at which to print status" default:"1"`
 		Sha256          string `long:"sha256"                       description:"sha256 of the provided product file to be used for validation"`
 		Version         string `long:"product-version"                      description:"version of the provided product file to be used for validation"`
+		Name            string `long:"product-name"                         description:"name of the provided product file to be used for validation, which a remote product needs with --product-version to be skipped when it is already uploaded"`
 	}
 	metadataExtractor metadataExtractor
 }
@@ -60,47 +62,22 @@ func (up UploadProduct) Execute(args []string) error {
 		return fmt.Errorf("could not parse upload-product flags: %s", err)
 	}
 
-	if up.Options.Sha256 != "" {
-		shaValidator := validator.NewSHA256Calculator()
-		shasum, err := shaValidator.Checksum(up.Options.Product)
-
-		if err != nil {
+	if isRemoteFile(up.Options.Product) {
+		alreadyUploaded, err := up.validateRemoteProduct()
+		if err != nil || alreadyUploaded {
 			return err
 		}
-
-		if shasum != up.Options.Sha256 {
-			return fmt.Errorf("expected shasum %s does not match file shasum %s", up.Options.Sha256, shasum)
-		}
-
-		up.logger.Printf("expected shasum matches product shasum.")
-	}
-
-	metadata, err := up.metadataExtractor.ExtractMetadata(up.Options.Product)
-	if err != nil {
-		return fmt.Errorf("failed to extract product metadata: %s", err)
-	}
-
-	if up.Options.Version != "" {
-		if up.Options.Version != metadata.Version {
-			return fmt.Errorf("expected version %s does not match product version %s", up.Options.Version, metadata.Version)
+	} else {
+		alreadyUploaded, err := up.validateProduct()
+		if err != nil || alreadyUploaded {
+			return err
 		}
-		up.logger.Printf("expected version matches product version.")
-	}
-
-	prodAvailable, err := up.service.CheckProductAvailability(metadata.Name, metadata.Version)
-	if err != nil {
-		return fmt.Errorf("failed to check product availability: %s", err)
-	}
-
-	if prodAvailable {
-		up.logger.Printf("product %s %s is already uploaded, nothing to be done.", metadata.Name, metadata.Version)
-		return nil
 	}
 
 	for i := 0; i <= maxProductUploadRetries; i++ {
 		up.logger.Printf("processing product")
 
-		err = up.multipart.AddFile("product[file]", up.Options.Product)
+		err = addFileToForm(up.multipart, "product[file]", up.Options.Product)
 		if err != nil {
 			return fmt.Errorf("failed to load product: %s", err)
 		}
@@ -109,13 +86,21 @@ func (up UploadProduct) Execute(args []string) error {
 
 		up.logger.Printf("beginning product upload to Ops Manager")
 
+		span := telemetry.Start("upload-product", telemetry.Attributes{"file": remoteFileName(up.Options.Product)})
 		_, err = up.service.UploadAvailableProduct(api.UploadAvailableProductInput{
 			Product:         submission.Content,
 			ContentType:     submission.ContentType,
 			ContentLength:   submission.ContentLength,
 			PollingInterval: up.Options.PollingInterval,
 		})
+		span.End(err)
+
+		if err == nil {
+			telemetry.Count("om.upload.bytes", "By", submission.ContentLength, telemetry.Attributes{"operation": "upload-product"})
+		}
+
 		if network.CanRetry(err) && i < maxProductUploadRetries {
+			telemetry.Count("om.upload.retries", "", 1, telemetry.Attributes{"operation": "upload-product"})
 			up.logger.Printf("retrying product upload after error: %s\n", err)
 			up.multipart.Reset()
 		} else {
@@ -130,3 +115,82 @@ func (up UploadProduct) Execute(args []string) error {
 
 	return nil
 }
+
+// validateRemoteProduct checks whether the product named by --product-name
+// and --product-version is already uploaded, as the metadata of a product
+// that is streamed is not read before it is uploaded.
+func (up UploadProduct) validateRemoteProduct() (bool, error) {
+	if up.Options.Sha256 != "" {
+		return false, fmt.Errorf("--sha256 cannot be used with a remote product")
+	}
+
+	if (up.Options.Name == "") != (up.Options.Version == "") {
+		return false, fmt.Errorf("--product-name and --product-version must be used together with a remote product")
+	}
+
+	if up.Options.Name == "" {
+		up.logger.Printf("product will be streamed from %s, skipping metadata validation", remoteFileName(up.Options.Product))
+		return false, nil
+	}
+
+	prodAvailable, err := up.service.CheckProductAvailability(up.Options.Name, up.Options.Version)
+	if err != nil {
+		return false, fmt.Errorf("failed to check product availability: %s", err)
+	}
+
+	if prodAvailable {
+		up.logger.Printf("product %s %s is already uploaded, nothing to be done.", up.Options.Name, up.Options.Version)
+		return true, nil
+	}
+
+	up.logger.Printf("product %s %s will be streamed from %s, skipping metadata validation", up.Options.Name, up.Options.Version, remoteFileName(up.Options.Product))
+	return false, nil
+}
+
+func (up UploadProduct) validateProduct() (bool, error) {
+	if up.Options.Sha256 != "" {
+		shaValidator := validator.NewSHA256Calculator()
+		shasum, err := shaValidator.Checksum(up.Options.Product)
+
+		if err != nil {
+			return false, err
+		}
+
+		if shasum != up.Options.Sha256 {
+			return false, fmt.Errorf("expected shasum %s does not match file shasum %s", up.Options.Sha256, shasum)
+		}
+
+		up.logger.Printf("expected shasum matches product shasum.")
+	}
+
+	metadata, err := up.metadataExtractor.ExtractMetadata(up.Options.Product)
+	if err != nil {
+		return false, fmt.Errorf("failed to extract product metadata: %s", err)
+	}
+
+	if up.Options.Name != "" {
+		if up.Options.Name != metadata.Name {
+			return false, fmt.Errorf("expected name %s does not match product name %s", up.Options.Name, metadata.Name)
+		}
+		up.logger.Printf("expected name matches product name.")
+	}
+
+	if up.Options.Version != "" {
+		if up.Options.Version != metadata.Version {
+			return false, fmt.Errorf("expected version %s does not match product version %s", up.Options.Version, metadata.Version)
+		}
+		up.logger.Printf("expected version matches product version.")
+	}
+
+	prodAvailable, err := up.service.CheckProductAvailability(metadata.Name, metadata.Version)
+	if err != nil {
+		return false, fmt.Errorf("failed to check product availability: %s", err)
+	}
+
+	if prodAvailable {
+		up.logger.Printf("product %s %s is already uploaded, nothing to be done.", metadata.Name, metadata.Version)
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
@@ -109,6 +112,142 @@ var _ = Describe("UploadProduct", func() {
 		})
 	})
 
+	Context("when the product is a URL", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, err := w.Write([]byte("some product content"))
+				Expect(err).NotTo(HaveOccurred())
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("streams the product from the URL without extracting its metadata", func() {
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product", server.URL + "/products/cf-2.4.0.pivotal",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(metadataExtractor.ExtractMetadataCallCount()).To(Equal(0))
+			Expect(fakeService.CheckProductAvailabilityCallCount()).To(Equal(0))
+			Expect(multipart.AddFileCallCount()).To(Equal(0))
+
+			key, fileName, reader, length := multipart.AddReaderArgsForCall(0)
+			Expect(key).To(Equal("product[file]"))
+			Expect(fileName).To(Equal("cf-2.4.0.pivotal"))
+			Expect(length).To(Equal(int64(20)))
+
+			contents, err := ioutil.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some product content"))
+
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(1))
+
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("product will be streamed from cf-2.4.0.pivotal, skipping metadata validation"))
+		})
+
+		It("does not stream the product when the named version is already uploaded", func() {
+			fakeService.CheckProductAvailabilityReturns(true, nil)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product", server.URL + "/products/cf-2.4.0.pivotal",
+				"--product-name", "cf",
+				"--product-version", "2.4.0",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.CheckProductAvailabilityCallCount()).To(Equal(1))
+			name, version := fakeService.CheckProductAvailabilityArgsForCall(0)
+			Expect(name).To(Equal("cf"))
+			Expect(version).To(Equal("2.4.0"))
+
+			Expect(metadataExtractor.ExtractMetadataCallCount()).To(Equal(0))
+			Expect(multipart.AddReaderCallCount()).To(Equal(0))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(0))
+
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("product cf 2.4.0 is already uploaded, nothing to be done."))
+		})
+
+		It("streams the product when the named version is not uploaded yet", func() {
+			fakeService.CheckProductAvailabilityReturns(false, nil)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product", server.URL + "/products/cf-2.4.0.pivotal",
+				"--product-name", "cf",
+				"--product-version", "2.4.0",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.CheckProductAvailabilityCallCount()).To(Equal(1))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(1))
+
+			format, v := logger.PrintfArgsForCall(0)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("product cf 2.4.0 will be streamed from cf-2.4.0.pivotal, skipping metadata validation"))
+		})
+
+		It("returns an error when the availability of the product cannot be checked", func() {
+			fakeService.CheckProductAvailabilityReturns(false, errors.New("some error"))
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product", server.URL + "/products/cf-2.4.0.pivotal",
+				"--product-name", "cf",
+				"--product-version", "2.4.0",
+			})
+			Expect(err).To(MatchError("failed to check product availability: some error"))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when only one of --product-name and --product-version is provided", func() {
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product", server.URL + "/products/cf-2.4.0.pivotal",
+				"--product-version", "2.4.0",
+			})
+			Expect(err).To(MatchError("--product-name and --product-version must be used together with a remote product"))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when --sha256 is provided", func() {
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product", server.URL + "/products/cf-2.4.0.pivotal",
+				"--sha256", "some-sha256",
+			})
+			Expect(err).To(MatchError("--sha256 cannot be used with a remote product"))
+		})
+
+		It("returns an error when the server does not respond within the request timeout", func() {
+			slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				time.Sleep(time.Second)
+			}))
+			defer slowServer.Close()
+
+			commands.SetRemoteFileTimeouts(time.Second, 10*time.Millisecond)
+			defer commands.SetRemoteFileTimeouts(10*time.Second, 1800*time.Second)
+
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--product", slowServer.URL + "/products/cf-2.4.0.pivotal",
+			})
+			Expect(err).To(MatchError(ContainSubstring("timeout awaiting response headers")))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("when the --sha256 flag is defined", func() {
 		It("proceeds normally when the sha sums match", func() {
 			file, err := ioutil.TempFile("", "test-file.yaml")
@@ -227,6 +366,28 @@ var _ = Describe("UploadProduct", func() {
 		})
 	})
 
+	Context("when the --product-name flag is defined", func() {
+		It("returns an error when the names don't match", func() {
+			file, err := ioutil.TempFile("", "test-file.yaml")
+			Expect(err).ToNot(HaveOccurred())
+			err = file.Close()
+			Expect(err).ToNot(HaveOccurred())
+			defer os.Remove(file.Name())
+
+			metadataExtractor.ExtractMetadataReturns(extractor.Metadata{
+				Name:    "cf",
+				Version: "1.5.0",
+			}, nil)
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+			err = command.Execute([]string{
+				"--product", file.Name(),
+				"--product-name", "p-redis",
+			})
+			Expect(err).To(MatchError("expected name p-redis does not match product name cf"))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(0))
+		})
+	})
+
 	Context("when the product fails to upload the first time with a retryable error", func() {
 		It("tries again", func() {
 			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
@@ -245,6 +406,24 @@ var _ = Describe("UploadProduct", func() {
 		})
 	})
 
+	Context("when the connection is dropped part way through the upload", func() {
+		It("restarts the upload from the beginning", func() {
+			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
+
+			fakeService.UploadAvailableProductReturnsOnCall(0, api.UploadAvailableProductOutput{}, errors.Wrap(io.ErrUnexpectedEOF, "some upload error"))
+			fakeService.UploadAvailableProductReturnsOnCall(1, api.UploadAvailableProductOutput{}, nil)
+
+			err := command.Execute([]string{"--product", "/some/path"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(multipart.ResetCallCount()).To(Equal(1))
+			Expect(fakeService.UploadAvailableProductCallCount()).To(Equal(2))
+
+			format, v := logger.PrintfArgsForCall(2)
+			Expect(fmt.Sprintf(format, v...)).To(Equal("retrying product upload after error: some upload error: unexpected EOF\n"))
+		})
+	})
+
 	Context("when the product fails to upload three times", func() {
 		It("returns an error", func() {
 			command := commands.NewUploadProduct(multipart, metadataExtractor, fakeService, logger)
@@ -2,12 +2,13 @@ package commands
 
 import (
 	"fmt"
-	"path/filepath"
+	"io"
 
 	"github.com/pivotal-cf/jhanda"
 	"github.com/pivotal-cf/om/api"
 	"github.com/pivotal-cf/om/formcontent"
 	"github.com/pivotal-cf/om/network"
+	"github.com/pivotal-cf/om/telemetry"
 	"github.com/pivotal-cf/om/validator"
 
 	"strconv"
@@ -20,10 +21,11 @@ type UploadStemcell struct {
 	logger    logger
 	service   uploadStemcellService
 	Options   struct {
-		Stemcell string `long:"stemcell" short:"s" required:"true" description:"path to stemcell"`
-		Force    bool   `long:"force"    short:"f"                 description:"upload stemcell even if it already exists on the target Ops Manager"`
-		Floating bool   `long:"floating" default:"true"            description:"assigns the stemcell to all compatible products "`
-		Shasum   string `long:"shasum" short:"sha" description:"shasum of the provided stemcell file to be used for validation"`
+		ConfigFile string `long:"config"   short:"c"                 description:"path to yml file for configuration (keys must match the following command line flags)"`
+		Stemcell   string `long:"stemcell" short:"s" required:"true" description:"path to stemcell, or an http(s):// or s3:// URL to stream the stemcell from"`
+		Force      bool   `long:"force"    short:"f"                 description:"upload stemcell even if it already exists on the target Ops Manager"`
+		Floating   bool   `long:"floating" default:"true"            description:"assigns the stemcell to all compatible products "`
+		Shasum     string `long:"shasum"   short:"sha"               description:"shasum of the provided stemcell file to be used for validation"`
 	}
 }
 
@@ -32,6 +34,7 @@ type multipart interface {
 	Finalize() formcontent.ContentSubmission
 	Reset()
 	AddFile(key, path string) error
+	AddReader(key, fileName string, reader io.ReadCloser, length int64) error
 	AddField(key, value string) error
 }
 
@@ -58,10 +61,15 @@ func (us UploadStemcell) Usage() jhanda.Usage {
 }
 
 func (us UploadStemcell) Execute(args []string) error {
-	if _, err := jhanda.Parse(&us.Options, args); err != nil {
+	err := loadConfigFile(args, &us.Options, nil)
+	if err != nil {
 		return fmt.Errorf("could not parse upload-stemcell flags: %s", err)
 	}
 
+	if us.Options.Shasum != "" && isRemoteFile(us.Options.Stemcell) {
+		return fmt.Errorf("--shasum cannot be used with a remote stemcell")
+	}
+
 	if us.Options.Shasum != "" {
 		shaValidator := validator.NewSHA256Calculator()
 		shasum, err := shaValidator.Checksum(us.Options.Stemcell)
@@ -90,16 +98,15 @@ func (us UploadStemcell) Execute(args []string) error {
 		}
 
 		for _, stemcell := range report.Stemcells {
-			if stemcell == filepath.Base(us.Options.Stemcell) {
+			if stemcell == remoteFileName(us.Options.Stemcell) {
 				us.logger.Printf("stemcell has already been uploaded")
 				return nil
 			}
 		}
 	}
 
-	var err error
 	for i := 0; i <= maxStemcellUploadRetries; i++ {
-		err = us.multipart.AddFile("stemcell[file]", us.Options.Stemcell)
+		err = addFileToForm(us.multipart, "stemcell[file]", us.Options.Stemcell)
 		if err != nil {
 			return fmt.Errorf("failed to load stemcell: %s", err)
 		}
@@ -116,12 +123,20 @@ func (us UploadStemcell) Execute(args []string) error {
 
 		us.logger.Printf("beginning stemcell upload to Ops Manager")
 
+		span := telemetry.Start("upload-stemcell", telemetry.Attributes{"file": remoteFileName(us.Options.Stemcell)})
 		_, err = us.service.UploadStemcell(api.StemcellUploadInput{
 			Stemcell:      submission.Content,
 			ContentType:   submission.ContentType,
 			ContentLength: submission.ContentLength,
 		})
+		span.End(err)
+
+		if err == nil {
+			telemetry.Count("om.upload.bytes", "By", submission.ContentLength, telemetry.Attributes{"operation": "upload-stemcell"})
+		}
+
 		if network.CanRetry(err) && i < maxStemcellUploadRetries {
+			telemetry.Count("om.upload.retries", "", 1, telemetry.Attributes{"operation": "upload-stemcell"})
 			us.logger.Printf("retrying stemcell upload after error: %s\n", err)
 			us.multipart.Reset()
 		} else {
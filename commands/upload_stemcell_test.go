@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 
@@ -305,6 +307,131 @@ var _ = Describe("UploadStemcell", func() {
 		})
 	})
 
+	Context("when the stemcell is a URL", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/stemcells/bosh-stemcell.tgz" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				_, err := w.Write([]byte("some stemcell content"))
+				Expect(err).NotTo(HaveOccurred())
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("streams the stemcell from the URL", func() {
+			fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{Stemcells: []string{}}, nil)
+
+			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--stemcell", server.URL + "/stemcells/bosh-stemcell.tgz?signature=some-signature",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(multipart.AddFileCallCount()).To(Equal(0))
+
+			key, fileName, reader, length := multipart.AddReaderArgsForCall(0)
+			Expect(key).To(Equal("stemcell[file]"))
+			Expect(fileName).To(Equal("bosh-stemcell.tgz"))
+			Expect(length).To(Equal(int64(21)))
+
+			contents, err := ioutil.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some stemcell content"))
+		})
+
+		It("skips the upload when the stemcell has already been uploaded", func() {
+			fakeService.GetDiagnosticReportReturns(api.DiagnosticReport{Stemcells: []string{"bosh-stemcell.tgz"}}, nil)
+
+			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--stemcell", server.URL + "/stemcells/bosh-stemcell.tgz?signature=some-signature",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(multipart.AddReaderCallCount()).To(Equal(0))
+			Expect(fakeService.UploadStemcellCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the stemcell cannot be downloaded", func() {
+			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--stemcell", server.URL + "/stemcells/missing.tgz?signature=some-signature",
+				"--force",
+			})
+			Expect(err).To(MatchError(fmt.Sprintf("failed to load stemcell: could not download %s/stemcells/missing.tgz: unexpected response 404 Not Found", server.URL)))
+		})
+
+		It("returns an error when a shasum is provided", func() {
+			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--stemcell", server.URL + "/stemcells/bosh-stemcell.tgz",
+				"--shasum", "some-shasum",
+			})
+			Expect(err).To(MatchError("--shasum cannot be used with a remote stemcell"))
+		})
+	})
+
+	Context("when config file is provided", func() {
+		var configFile *os.File
+
+		BeforeEach(func() {
+			var err error
+			configContent := `
+stemcell: will-be-overridden-by-command-line
+floating: false
+force: true
+`
+			configFile, err = ioutil.TempFile("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = configFile.WriteString(configContent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.Remove(configFile.Name())
+		})
+
+		It("reads configuration from config file", func() {
+			submission := formcontent.ContentSubmission{
+				ContentLength: 10,
+				Content:       ioutil.NopCloser(strings.NewReader("")),
+				ContentType:   "some content-type",
+			}
+			multipart.FinalizeReturns(submission)
+
+			command := commands.NewUploadStemcell(multipart, fakeService, logger)
+
+			err := command.Execute([]string{
+				"--config", configFile.Name(),
+				"--stemcell", "/path/to/stemcell.tgz",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeService.GetDiagnosticReportCallCount()).To(Equal(0))
+
+			key, file := multipart.AddFileArgsForCall(0)
+			Expect(key).To(Equal("stemcell[file]"))
+			Expect(file).To(Equal("/path/to/stemcell.tgz"))
+
+			key, value := multipart.AddFieldArgsForCall(0)
+			Expect(key).To(Equal("stemcell[floating]"))
+			Expect(value).To(Equal("false"))
+		})
+	})
+
 	Context("when the diagnostic report is unavailable", func() {
 		It("uploads the stemcell", func() {
 			submission := formcontent.ContentSubmission{
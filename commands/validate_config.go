@@ -0,0 +1,292 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"gopkg.in/yaml.v2"
+)
+
+// credentialKeys lists the keys of the value of each type of credential.
+var credentialKeys = map[string][]string{
+	"secret":               {"secret"},
+	"simple_credentials":   {"identity", "password"},
+	"rsa_cert_credentials": {"cert_pem", "private_key_pem"},
+	"rsa_pkey_credentials": {"private_key_pem", "public_key_pem"},
+	"salted_credentials":   {"identity", "password", "salt"},
+}
+
+// listPropertyTypes are the types of properties whose value is a list of options.
+var listPropertyTypes = map[string]bool{
+	"multi_select_options":            true,
+	"service_network_az_multi_select": true,
+}
+
+type ValidateConfig struct {
+	environFunc func() []string
+	service     validateConfigService
+	logger      logger
+	Options     struct {
+		Product    string   `long:"product-name" short:"p" required:"true" description:"name of product"`
+		ConfigFile string   `long:"config"       short:"c" required:"true" description:"path to yml file containing the config of the product (see docs/configure-product/README.md for format)"`
+		VarsFile   []string `long:"vars-file"    short:"l"                 description:"Load variables from a YAML file"`
+		VarsEnv    []string `long:"vars-env"                               description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars       []string `long:"var"                                    description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore  string   `long:"vars-store"                             description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		OpsFile    []string `long:"ops-file"     short:"o"                 description:"YAML operations file"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/validate_config_service.go --fake-name ValidateConfigService . validateConfigService
+type validateConfigService interface {
+	GetStagedProductByName(product string) (api.StagedProductsFindOutput, error)
+	GetStagedProductProperties(product string) (map[string]api.ResponseProperty, error)
+}
+
+func NewValidateConfig(environFunc func() []string, service validateConfigService, logger logger) ValidateConfig {
+	return ValidateConfig{
+		environFunc: environFunc,
+		service:     service,
+		logger:      logger,
+	}
+}
+
+func (vc ValidateConfig) Execute(args []string) error {
+	if _, err := jhanda.Parse(&vc.Options, args); err != nil {
+		return fmt.Errorf("could not parse validate-config flags: %s", err)
+	}
+
+	configContents, err := interpolate(interpolateOptions{
+		templateFile: vc.Options.ConfigFile,
+		varsFiles:    vc.Options.VarsFile,
+		environFunc:  vc.environFunc,
+		varsEnvs:     vc.Options.VarsEnv,
+		vars:         vc.Options.Vars,
+		varsStore:    vc.Options.VarsStore,
+		opsFiles:     vc.Options.OpsFile,
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	var config struct {
+		ProductProperties map[string]interface{} `yaml:"product-properties"`
+	}
+	err = yaml.Unmarshal(configContents, &config)
+	if err != nil {
+		return fmt.Errorf("%s could not be parsed as valid configuration: %s", vc.Options.ConfigFile, err)
+	}
+
+	findOutput, err := vc.service.GetStagedProductByName(vc.Options.Product)
+	if err != nil {
+		return fmt.Errorf("could not find the staged product %s: %s", vc.Options.Product, err)
+	}
+
+	properties, err := vc.service.GetStagedProductProperties(findOutput.Product.GUID)
+	if err != nil {
+		return fmt.Errorf("could not retrieve the properties of %s: %s", vc.Options.Product, err)
+	}
+
+	var names []string
+	for name := range config.ProductProperties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		problems = append(problems, validateProperty(name, config.ProductProperties[name], properties)...)
+	}
+
+	if len(problems) == 0 {
+		vc.logger.Printf("the configuration in %s is valid for %s", vc.Options.ConfigFile, vc.Options.Product)
+		return nil
+	}
+
+	vc.logger.Println("product-properties:")
+	for _, problem := range problems {
+		vc.logger.Println("  - " + problem)
+	}
+
+	return fmt.Errorf("the configuration in %s is invalid for %s", vc.Options.ConfigFile, vc.Options.Product)
+}
+
+func validateProperty(name string, setting interface{}, properties map[string]api.ResponseProperty) []string {
+	property, ok := properties[name]
+	if !ok {
+		return []string{fmt.Sprintf("%s: unknown property", name)}
+	}
+
+	if !property.Configurable {
+		return []string{fmt.Sprintf("%s: is not configurable", name)}
+	}
+
+	settingMap, ok := asMap(setting)
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected a map with a value or selected_option", name)}
+	}
+
+	var problems []string
+	for _, key := range mapKeys(settingMap) {
+		if key != "value" && key != "selected_option" {
+			problems = append(problems, fmt.Sprintf("%s: unknown key %s, expected value or selected_option", name, key))
+		}
+	}
+
+	if selectedOption, ok := settingMap["selected_option"]; ok && property.Type == "selector" {
+		options := selectorOptions(name, properties)
+		if len(options) > 0 && !containsString(options, fmt.Sprintf("%v", selectedOption)) {
+			problems = append(problems, fmt.Sprintf("%s: unknown selected_option %v, expected one of: %s", name, selectedOption, strings.Join(options, ", ")))
+		}
+	}
+
+	if value, ok := settingMap["value"]; ok {
+		problems = append(problems, validateValue(name+".value", property.Type, value)...)
+
+		if property.Type == "collection" {
+			problems = append(problems, validateCollection(name+".value", value, property.Value)...)
+		}
+	}
+
+	return problems
+}
+
+// validateValue checks that the value has the shape the type of property expects.
+// The types om has no specific rule for only accept scalars, as strings do.
+func validateValue(path, propertyType string, value interface{}) []string {
+	if value == nil {
+		return nil
+	}
+
+	if keys, ok := credentialKeys[propertyType]; ok {
+		valueMap, ok := asMap(value)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected a %s with the keys %s", path, propertyType, strings.Join(keys, ", "))}
+		}
+
+		var problems []string
+		for _, key := range mapKeys(valueMap) {
+			if !containsString(keys, key) {
+				problems = append(problems, fmt.Sprintf("%s: unknown key %s of %s, expected one of: %s", path, key, propertyType, strings.Join(keys, ", ")))
+			}
+		}
+		return problems
+	}
+
+	var valid bool
+	var expected string
+	switch {
+	case propertyType == "boolean":
+		_, valid = value.(bool)
+		expected = "a boolean"
+	case propertyType == "integer" || propertyType == "port":
+		_, valid = value.(int)
+		expected = "an integer"
+	case propertyType == "collection":
+		_, valid = value.([]interface{})
+		expected = "a list"
+	case listPropertyTypes[propertyType]:
+		_, valid = value.([]interface{})
+		expected = "a list of options"
+	case propertyType == "":
+		return nil
+	default:
+		switch value.(type) {
+		case string, int, float64:
+			valid = true
+		}
+		expected = "a string"
+	}
+
+	if valid {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s: expected %s for type %s, got %s", path, expected, propertyType, formatValue(value))}
+}
+
+// validateCollection checks the items of a collection against the properties
+// of the items that are staged. Collections with no staged items cannot be checked.
+func validateCollection(path string, value, stagedValue interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	stagedItems, ok := stagedValue.([]interface{})
+	if !ok || len(stagedItems) == 0 {
+		return nil
+	}
+
+	innerProperties, ok := asMap(stagedItems[0])
+	if !ok {
+		return nil
+	}
+
+	var problems []string
+	for index, item := range items {
+		itemPath := joinPath(path, strconv.Itoa(index))
+
+		itemMap, ok := asMap(item)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: expected a map of the properties of the item", itemPath))
+			continue
+		}
+
+		for _, key := range mapKeys(itemMap) {
+			innerProperty, ok := asMap(innerProperties[key])
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown property", joinPath(itemPath, key)))
+				continue
+			}
+
+			innerType, _ := innerProperty["type"].(string)
+			problems = append(problems, validateValue(joinPath(itemPath, key), innerType, itemMap[key])...)
+		}
+	}
+
+	return problems
+}
+
+// selectorOptions returns the options of a selector, which are the names
+// its own properties are nested under.
+func selectorOptions(name string, properties map[string]api.ResponseProperty) []string {
+	var options []string
+	for propertyName := range properties {
+		if !strings.HasPrefix(propertyName, name+".") {
+			continue
+		}
+
+		option := strings.SplitN(strings.TrimPrefix(propertyName, name+"."), ".", 2)[0]
+		if !containsString(options, option) {
+			options = append(options, option)
+		}
+	}
+	sort.Strings(options)
+
+	return options
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (vc ValidateConfig) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description: "This authenticated command interpolates a configure-product config and validates its product properties against the properties of the staged product. " +
+			"Unknown and non-configurable properties, values of the wrong type, unknown selector options and unknown properties of collection items are reported. " +
+			"It exits non-zero if the config is invalid.",
+		ShortDescription: "validates a product config against the properties of the staged product",
+		Flags:            vc.Options,
+	}
+}
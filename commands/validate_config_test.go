@@ -0,0 +1,269 @@
+package commands_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateConfig", func() {
+	var (
+		logger     *fakes.Logger
+		service    *fakes.ValidateConfigService
+		command    commands.ValidateConfig
+		configFile *os.File
+	)
+
+	writeConfig := func(contents string) {
+		_, err := configFile.WriteString(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configFile.Close()).To(Succeed())
+	}
+
+	loggedLines := func() []string {
+		var lines []string
+		for i := 0; i < logger.PrintlnCallCount(); i++ {
+			lines = append(lines, logger.PrintlnArgsForCall(i)[0].(string))
+		}
+		return lines
+	}
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		service = &fakes.ValidateConfigService{}
+
+		service.GetStagedProductByNameReturns(api.StagedProductsFindOutput{
+			Product: api.StagedProduct{GUID: "some-product-guid"},
+		}, nil)
+		service.GetStagedProductPropertiesReturns(map[string]api.ResponseProperty{
+			".properties.some-string-property": {
+				Type:         "string",
+				Configurable: true,
+			},
+			".properties.some-boolean-property": {
+				Type:         "boolean",
+				Configurable: true,
+			},
+			".properties.some-integer-property": {
+				Type:         "integer",
+				Configurable: true,
+			},
+			".properties.some-fixed-property": {
+				Type:         "string",
+				Configurable: false,
+			},
+			".properties.some-credentials-property": {
+				Type:         "simple_credentials",
+				Configurable: true,
+				IsCredential: true,
+			},
+			".properties.some-selector": {
+				Type:           "selector",
+				Value:          "Internal",
+				SelectedOption: "internal",
+				Configurable:   true,
+			},
+			".properties.some-selector.internal.size": {
+				Type:         "integer",
+				Configurable: true,
+			},
+			".properties.some-selector.external.host": {
+				Type:         "string",
+				Configurable: true,
+			},
+			".properties.some-collection": {
+				Type:         "collection",
+				Configurable: true,
+				Value: []interface{}{
+					map[interface{}]interface{}{
+						"name":    map[interface{}]interface{}{"type": "string", "configurable": true, "credential": false, "value": "first"},
+						"enabled": map[interface{}]interface{}{"type": "boolean", "configurable": true, "credential": false, "value": true},
+					},
+				},
+			},
+		}, nil)
+
+		var err error
+		configFile, err = ioutil.TempFile("", "product.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		command = commands.NewValidateConfig(func() []string { return nil }, service, logger)
+	})
+
+	AfterEach(func() {
+		os.Remove(configFile.Name())
+	})
+
+	Describe("Execute", func() {
+		Context("when the config is valid", func() {
+			It("succeeds", func() {
+				writeConfig(`---
+product-name: some-product
+product-properties:
+  .properties.some-string-property:
+    value: ((string-value))
+  .properties.some-boolean-property:
+    value: true
+  .properties.some-integer-property:
+    value: 42
+  .properties.some-credentials-property:
+    value:
+      identity: admin
+      password: some-password
+  .properties.some-selector:
+    value: External
+    selected_option: external
+  .properties.some-selector.external.host:
+    value: db.example.com
+  .properties.some-collection:
+    value:
+    - name: second
+      enabled: false
+`)
+
+				err := command.Execute([]string{
+					"--product-name", "some-product",
+					"--config", configFile.Name(),
+					"--var", "string-value=some-value",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(service.GetStagedProductByNameArgsForCall(0)).To(Equal("some-product"))
+				Expect(service.GetStagedProductPropertiesArgsForCall(0)).To(Equal("some-product-guid"))
+				Expect(logger.PrintlnCallCount()).To(Equal(0))
+
+				format, content := logger.PrintfArgsForCall(0)
+				Expect(format).To(Equal("the configuration in %s is valid for %s"))
+				Expect(content).To(Equal([]interface{}{configFile.Name(), "some-product"}))
+			})
+		})
+
+		Context("when the config is invalid", func() {
+			It("reports every problem and returns an error", func() {
+				writeConfig(`---
+product-properties:
+  .properties.some-unknown-property:
+    value: some-value
+  .properties.some-fixed-property:
+    value: some-value
+  .properties.some-boolean-property:
+    value: "yes"
+  .properties.some-integer-property:
+    value: forty-two
+    selected: true
+  .properties.some-string-property: some-value
+  .properties.some-credentials-property:
+    value:
+      username: admin
+  .properties.some-selector:
+    selected_option: cloud
+  .properties.some-collection:
+    value:
+    - name: second
+      enable: false
+`)
+
+				err := command.Execute([]string{
+					"--product-name", "some-product",
+					"--config", configFile.Name(),
+				})
+				Expect(err).To(MatchError("the configuration in " + configFile.Name() + " is invalid for some-product"))
+
+				Expect(loggedLines()).To(Equal([]string{
+					"product-properties:",
+					"  - .properties.some-boolean-property.value: expected a boolean for type boolean, got yes",
+					"  - .properties.some-collection.value.0.enable: unknown property",
+					"  - .properties.some-credentials-property.value: unknown key username of simple_credentials, expected one of: identity, password",
+					"  - .properties.some-fixed-property: is not configurable",
+					"  - .properties.some-integer-property: unknown key selected, expected value or selected_option",
+					"  - .properties.some-integer-property.value: expected an integer for type integer, got forty-two",
+					"  - .properties.some-selector: unknown selected_option cloud, expected one of: external, internal",
+					"  - .properties.some-string-property: expected a map with a value or selected_option",
+					"  - .properties.some-unknown-property: unknown property",
+				}))
+			})
+
+			It("checks the types of the properties of collection items", func() {
+				writeConfig(`---
+product-properties:
+  .properties.some-collection:
+    value:
+    - name: second
+      enabled: sometimes
+    - some-item
+`)
+
+				err := command.Execute([]string{
+					"--product-name", "some-product",
+					"--config", configFile.Name(),
+				})
+				Expect(err).To(HaveOccurred())
+
+				Expect(loggedLines()).To(Equal([]string{
+					"product-properties:",
+					"  - .properties.some-collection.value.0.enabled: expected a boolean for type boolean, got sometimes",
+					"  - .properties.some-collection.value.1: expected a map of the properties of the item",
+				}))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the flags cannot be parsed", func() {
+				err := command.Execute([]string{"--invalid"})
+				Expect(err).To(MatchError("could not parse validate-config flags: flag provided but not defined: -invalid"))
+			})
+
+			It("returns an error when the config cannot be interpolated", func() {
+				writeConfig(`product-properties: ((missing))`)
+
+				err := command.Execute([]string{
+					"--product-name", "some-product",
+					"--config", configFile.Name(),
+				})
+				Expect(err).To(MatchError(ContainSubstring("Expected to find variables: missing")))
+			})
+
+			It("returns an error when the product is not staged", func() {
+				writeConfig(`product-properties: {}`)
+				service.GetStagedProductByNameReturns(api.StagedProductsFindOutput{}, errors.New("not staged"))
+
+				err := command.Execute([]string{
+					"--product-name", "some-product",
+					"--config", configFile.Name(),
+				})
+				Expect(err).To(MatchError("could not find the staged product some-product: not staged"))
+			})
+
+			It("returns an error when the properties cannot be retrieved", func() {
+				writeConfig(`product-properties: {}`)
+				service.GetStagedProductPropertiesReturns(nil, errors.New("server error"))
+
+				err := command.Execute([]string{
+					"--product-name", "some-product",
+					"--config", configFile.Name(),
+				})
+				Expect(err).To(MatchError("could not retrieve the properties of some-product: server error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description: "This authenticated command interpolates a configure-product config and validates its product properties against the properties of the staged product. " +
+					"Unknown and non-configurable properties, values of the wrong type, unknown selector options and unknown properties of collection items are reported. " +
+					"It exits non-zero if the config is invalid.",
+				ShortDescription: "validates a product config against the properties of the staged product",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
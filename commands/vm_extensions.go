@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/presenters"
+)
+
+//go:generate counterfeiter -o ./fakes/vm_extensions_service.go --fake-name VMExtensionsService . vmExtensionsService
+type vmExtensionsService interface {
+	ListStagedVMExtensions() ([]api.VMExtension, error)
+}
+
+type VMExtensions struct {
+	service   vmExtensionsService
+	presenter presenters.FormattedPresenter
+	logger    logger
+	Options   struct {
+		Format string `long:"format" short:"f" default:"table" description:"Format to print as (options: table,json)"`
+	}
+}
+
+func NewVMExtensions(service vmExtensionsService, presenter presenters.FormattedPresenter, logger logger) VMExtensions {
+	return VMExtensions{
+		service:   service,
+		presenter: presenter,
+		logger:    logger,
+	}
+}
+
+func (v VMExtensions) Execute(args []string) error {
+	if _, err := jhanda.Parse(&v.Options, args); err != nil {
+		return fmt.Errorf("could not parse vm-extensions flags: %s", err)
+	}
+
+	vmExtensions, err := v.service.ListStagedVMExtensions()
+	if err != nil {
+		return fmt.Errorf("could not list the VM extensions: %s", err)
+	}
+
+	if len(vmExtensions) == 0 {
+		if v.Options.Format != "json" {
+			v.logger.Printf("no VM extensions found")
+			return nil
+		}
+		vmExtensions = []api.VMExtension{}
+	}
+
+	v.presenter.SetFormat(v.Options.Format)
+	v.presenter.PresentVMExtensions(vmExtensions)
+
+	return nil
+}
+
+func (v VMExtensions) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This authenticated command lists the custom VM extensions staged in Ops Manager, with their cloud properties",
+		ShortDescription: "lists VM extensions",
+		Flags:            v.Options,
+	}
+}
@@ -0,0 +1,87 @@
+package commands_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/api"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	presenterfakes "github.com/pivotal-cf/om/presenters/fakes"
+)
+
+var _ = Describe("VMExtensions", func() {
+	var (
+		fakeService   *fakes.VMExtensionsService
+		fakePresenter *presenterfakes.FormattedPresenter
+		fakeLogger    *fakes.Logger
+		command       commands.VMExtensions
+	)
+
+	BeforeEach(func() {
+		fakeService = &fakes.VMExtensionsService{}
+		fakePresenter = &presenterfakes.FormattedPresenter{}
+		fakeLogger = &fakes.Logger{}
+		command = commands.NewVMExtensions(fakeService, fakePresenter, fakeLogger)
+	})
+
+	Describe("Execute", func() {
+		It("presents the VM extensions", func() {
+			vmExtensions := []api.VMExtension{
+				{Name: "web-lb", CloudProperties: map[string]interface{}{"lb_target_groups": []interface{}{"web"}}},
+			}
+			fakeService.ListStagedVMExtensionsReturns(vmExtensions, nil)
+
+			err := command.Execute([]string{"--format", "json"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePresenter.SetFormatArgsForCall(0)).To(Equal("json"))
+			Expect(fakePresenter.PresentVMExtensionsArgsForCall(0)).To(Equal(vmExtensions))
+		})
+
+		Context("when there are no VM extensions", func() {
+			It("logs a message", func() {
+				err := command.Execute([]string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				format, _ := fakeLogger.PrintfArgsForCall(0)
+				Expect(format).To(Equal("no VM extensions found"))
+				Expect(fakePresenter.PresentVMExtensionsCallCount()).To(Equal(0))
+			})
+
+			It("presents an empty list as json", func() {
+				err := command.Execute([]string{"--format", "json"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePresenter.PresentVMExtensionsArgsForCall(0)).To(Equal([]api.VMExtension{}))
+				Expect(fakeLogger.PrintfCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the flags cannot be parsed", func() {
+				err := command.Execute([]string{"--unknown-flag"})
+				Expect(err).To(MatchError(ContainSubstring("could not parse vm-extensions flags")))
+			})
+
+			It("returns an error when the VM extensions cannot be listed", func() {
+				fakeService.ListStagedVMExtensionsReturns(nil, errors.New("some error"))
+
+				err := command.Execute([]string{})
+				Expect(err).To(MatchError("could not list the VM extensions: some error"))
+			})
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			Expect(command.Usage()).To(Equal(jhanda.Usage{
+				Description:      "This authenticated command lists the custom VM extensions staged in Ops Manager, with their cloud properties",
+				ShortDescription: "lists VM extensions",
+				Flags:            command.Options,
+			}))
+		})
+	})
+})
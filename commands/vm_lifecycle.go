@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/vmlifecycle"
+	"gopkg.in/yaml.v2"
+)
+
+// VMLifecycle creates, deletes and replaces the Ops Manager VM itself, with
+// the IaaS of the config.
+type VMLifecycle struct {
+	environFunc  func() []string
+	newVMManager vmManagerFactory
+	logger       logger
+	Options      struct {
+		ConfigFile string   `short:"c" long:"config"     required:"true" description:"path to yml file containing the configuration of the Ops Manager VM (see docs/vm-lifecycle/README.md for format)"`
+		StateFile  string   `short:"s" long:"state-file" required:"true" description:"path to the file recording the VM that is created, so that it can be deleted or replaced; it is created when it does not exist"`
+		ImageFile  string   `short:"i" long:"image-file"                 description:"image to create the VM from, required by create-vm and replace-vm: the yml of the images of AWS, GCP or Azure, or the OVA of vSphere"`
+		VarsFile   []string `short:"l" long:"vars-file"  description:"Load variables from a YAML file"`
+		VarsEnv    []string `long:"vars-env"   description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
+		Vars       []string `long:"var"        description:"Load variable from the command line. Format: VAR=VAL"`
+		VarsStore  string   `long:"vars-store" description:"Load the variables that are not provided from a secret store. Supported: credhub, aws-secrets-manager, gcp-secret-manager, vault"`
+		OpsFile    []string `short:"o" long:"ops-file"  description:"YAML operations file"`
+	}
+}
+
+//go:generate counterfeiter -o ./fakes/vm_manager.go --fake-name VMManager . vmManager
+type vmManager interface {
+	CreateVM(imageFile string) (string, error)
+	DeleteVM(id string) error
+}
+
+// vmManagerFactory returns the VM manager of the IaaS of the config, and the name of the IaaS
+type vmManagerFactory func(config vmlifecycle.Config) (vmlifecycle.VMManager, string, error)
+
+var vmLifecycleSubcommands = []string{"create-vm", "delete-vm", "replace-vm"}
+
+func NewVMLifecycle(environFunc func() []string, newVMManager vmManagerFactory, logger logger) VMLifecycle {
+	return VMLifecycle{
+		environFunc:  environFunc,
+		newVMManager: newVMManager,
+		logger:       logger,
+	}
+}
+
+func (v VMLifecycle) Execute(args []string) error {
+	if len(args) == 0 || !containsString(vmLifecycleSubcommands, args[0]) {
+		return fmt.Errorf("expected a subcommand of vm-lifecycle: %s", strings.Join(vmLifecycleSubcommands, ", "))
+	}
+	subcommand := args[0]
+
+	if _, err := jhanda.Parse(&v.Options, args[1:]); err != nil {
+		return fmt.Errorf("could not parse vm-lifecycle flags: %s", err)
+	}
+
+	if subcommand != "delete-vm" && v.Options.ImageFile == "" {
+		return fmt.Errorf("could not parse vm-lifecycle flags: %s requires --image-file", subcommand)
+	}
+
+	configContents, err := interpolate(interpolateOptions{
+		templateFile: v.Options.ConfigFile,
+		varsFiles:    v.Options.VarsFile,
+		environFunc:  v.environFunc,
+		varsEnvs:     v.Options.VarsEnv,
+		vars:         v.Options.Vars,
+		varsStore:    v.Options.VarsStore,
+		opsFiles:     v.Options.OpsFile,
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	var config vmlifecycle.Config
+	err = yaml.UnmarshalStrict(configContents, &config)
+	if err != nil {
+		return fmt.Errorf("could not parse the config file %s: %s", v.Options.ConfigFile, err)
+	}
+
+	manager, iaas, err := v.newVMManager(config)
+	if err != nil {
+		return err
+	}
+
+	state, err := vmlifecycle.LoadState(v.Options.StateFile)
+	if err != nil {
+		return err
+	}
+
+	if state.IAAS != "" && state.IAAS != iaas {
+		return fmt.Errorf("the state file %s records a VM on %s, but the config is for %s", v.Options.StateFile, state.IAAS, iaas)
+	}
+	state.IAAS = iaas
+
+	switch subcommand {
+	case "create-vm":
+		if state.ID != "" {
+			v.logger.Printf("the VM %s recorded in %s already exists, it is not created again", state.ID, v.Options.StateFile)
+			return nil
+		}
+
+		return v.createVM(manager, state)
+	case "delete-vm":
+		if state.ID == "" {
+			v.logger.Printf("no VM is recorded in %s, there is nothing to delete", v.Options.StateFile)
+			return nil
+		}
+
+		return v.deleteVM(manager, state)
+	default:
+		if state.ID != "" {
+			err = v.deleteVM(manager, state)
+			if err != nil {
+				return err
+			}
+			state.ID = ""
+		}
+
+		return v.createVM(manager, state)
+	}
+}
+
+func (v VMLifecycle) createVM(manager vmManager, state vmlifecycle.State) error {
+	v.logger.Printf("creating the Ops Manager VM on %s", state.IAAS)
+
+	id, err := manager.CreateVM(v.Options.ImageFile)
+	if id != "" {
+		// the VM is recorded even when it did not start, so that it can be deleted
+		state.ID = id
+		if writeErr := v.writeState(state); writeErr != nil {
+			return writeErr
+		}
+	}
+	if err != nil {
+		if id != "" {
+			return fmt.Errorf("could not create the VM: %s\nthe VM %s is recorded in %s, it can be deleted with delete-vm", err, id, v.Options.StateFile)
+		}
+		return fmt.Errorf("could not create the VM: %s", err)
+	}
+
+	v.logger.Printf("created the VM %s", id)
+
+	return nil
+}
+
+func (v VMLifecycle) deleteVM(manager vmManager, state vmlifecycle.State) error {
+	v.logger.Printf("deleting the Ops Manager VM %s on %s", state.ID, state.IAAS)
+
+	err := manager.DeleteVM(state.ID)
+	if err != nil {
+		return fmt.Errorf("could not delete the VM %s: %s", state.ID, err)
+	}
+
+	id := state.ID
+	state.ID = ""
+	err = v.writeState(state)
+	if err != nil {
+		return err
+	}
+
+	v.logger.Printf("deleted the VM %s", id)
+
+	return nil
+}
+
+func (v VMLifecycle) writeState(state vmlifecycle.State) error {
+	err := vmlifecycle.WriteState(v.Options.StateFile, state)
+	if err != nil {
+		return fmt.Errorf("could not write the state file %s: %s", v.Options.StateFile, err)
+	}
+
+	return nil
+}
+
+func (v VMLifecycle) Usage() jhanda.Usage {
+	return jhanda.Usage{
+		Description:      "This command manages the Ops Manager VM itself on AWS, GCP, Azure or vSphere, with the aws, gcloud, az or govc CLI, which must be installed. Its subcommands are: create-vm, which creates the VM unless the state file records one; delete-vm, which deletes the VM of the state file; and replace-vm, which deletes the VM of the state file, if any, then creates a VM from the new image, e.g. to upgrade Ops Manager. Usage: om vm-lifecycle <create-vm|delete-vm|replace-vm> [<args>]",
+		ShortDescription: "creates, deletes or replaces the Ops Manager VM",
+		Flags:            v.Options,
+	}
+}
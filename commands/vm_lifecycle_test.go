@@ -0,0 +1,278 @@
+package commands_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/jhanda"
+	"github.com/pivotal-cf/om/commands"
+	"github.com/pivotal-cf/om/commands/fakes"
+	"github.com/pivotal-cf/om/vmlifecycle"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VMLifecycle", func() {
+	var (
+		manager       *fakes.VMManager
+		logger        *fakes.Logger
+		dir           string
+		configFile    string
+		stateFile     string
+		imageFile     string
+		iaas          string
+		factoryErr    error
+		factoryConfig vmlifecycle.Config
+		command       commands.VMLifecycle
+	)
+
+	logMessages := func() []string {
+		var messages []string
+		for i := 0; i < logger.PrintfCallCount(); i++ {
+			format, v := logger.PrintfArgsForCall(i)
+			messages = append(messages, fmt.Sprintf(format, v...))
+		}
+		return messages
+	}
+
+	writeState := func(state string) {
+		Expect(ioutil.WriteFile(stateFile, []byte(state), 0600)).To(Succeed())
+	}
+
+	readState := func() string {
+		contents, err := ioutil.ReadFile(stateFile)
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	execute := func(subcommand string, args ...string) error {
+		return command.Execute(append([]string{
+			subcommand,
+			"--config", configFile,
+			"--state-file", stateFile,
+		}, args...))
+	}
+
+	BeforeEach(func() {
+		manager = &fakes.VMManager{}
+		logger = &fakes.Logger{}
+
+		var err error
+		dir, err = ioutil.TempDir("", "vm-lifecycle")
+		Expect(err).NotTo(HaveOccurred())
+
+		configFile = filepath.Join(dir, "config.yml")
+		stateFile = filepath.Join(dir, "state.yml")
+		imageFile = filepath.Join(dir, "image.yml")
+
+		Expect(ioutil.WriteFile(configFile, []byte(`---
+opsman-configuration:
+  aws:
+    region: ((region))
+`), 0600)).To(Succeed())
+
+		iaas = "aws"
+		factoryErr = nil
+		factoryConfig = vmlifecycle.Config{}
+
+		command = commands.NewVMLifecycle(func() []string { return nil }, func(config vmlifecycle.Config) (vmlifecycle.VMManager, string, error) {
+			factoryConfig = config
+			return manager, iaas, factoryErr
+		}, logger)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Describe("create-vm", func() {
+		BeforeEach(func() {
+			manager.CreateVMReturns("i-1234", nil)
+		})
+
+		It("creates the VM from the image and records it in the state file", func() {
+			err := execute("create-vm", "--image-file", imageFile, "--var", "region=us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(factoryConfig.OpsmanConfig.AWS).NotTo(BeNil())
+			Expect(factoryConfig.OpsmanConfig.AWS.Region).To(Equal("us-east-1"))
+
+			Expect(manager.CreateVMCallCount()).To(Equal(1))
+			Expect(manager.CreateVMArgsForCall(0)).To(Equal(imageFile))
+
+			Expect(readState()).To(MatchYAML(`{iaas: aws, vm_id: i-1234}`))
+			Expect(logMessages()).To(Equal([]string{
+				"creating the Ops Manager VM on aws",
+				"created the VM i-1234",
+			}))
+		})
+
+		It("does not create the VM again when the state file records one", func() {
+			writeState(`{iaas: aws, vm_id: i-0000}`)
+
+			err := execute("create-vm", "--image-file", imageFile, "--var", "region=us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(manager.CreateVMCallCount()).To(Equal(0))
+			Expect(readState()).To(MatchYAML(`{iaas: aws, vm_id: i-0000}`))
+			Expect(logMessages()).To(Equal([]string{
+				fmt.Sprintf("the VM i-0000 recorded in %s already exists, it is not created again", stateFile),
+			}))
+		})
+
+		It("records the VM when it is created but fails to start", func() {
+			manager.CreateVMReturns("i-1234", errors.New("the instance did not start"))
+
+			err := execute("create-vm", "--image-file", imageFile, "--var", "region=us-east-1")
+			Expect(err).To(MatchError(fmt.Sprintf("could not create the VM: the instance did not start\nthe VM i-1234 is recorded in %s, it can be deleted with delete-vm", stateFile)))
+
+			Expect(readState()).To(MatchYAML(`{iaas: aws, vm_id: i-1234}`))
+		})
+
+		It("does not write the state file when no VM is created", func() {
+			manager.CreateVMReturns("", errors.New("the image is not found"))
+
+			err := execute("create-vm", "--image-file", imageFile, "--var", "region=us-east-1")
+			Expect(err).To(MatchError("could not create the VM: the image is not found"))
+
+			_, err = os.Stat(stateFile)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("requires the image file", func() {
+			err := execute("create-vm", "--var", "region=us-east-1")
+			Expect(err).To(MatchError("could not parse vm-lifecycle flags: create-vm requires --image-file"))
+		})
+	})
+
+	Describe("delete-vm", func() {
+		It("deletes the VM of the state file and removes it from the state file", func() {
+			writeState(`{iaas: aws, vm_id: i-1234}`)
+
+			err := execute("delete-vm", "--var", "region=us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(manager.DeleteVMCallCount()).To(Equal(1))
+			Expect(manager.DeleteVMArgsForCall(0)).To(Equal("i-1234"))
+
+			Expect(readState()).To(MatchYAML(`{iaas: aws, vm_id: ""}`))
+			Expect(logMessages()).To(Equal([]string{
+				"deleting the Ops Manager VM i-1234 on aws",
+				"deleted the VM i-1234",
+			}))
+		})
+
+		It("does nothing when the state file records no VM", func() {
+			err := execute("delete-vm", "--var", "region=us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(manager.DeleteVMCallCount()).To(Equal(0))
+			Expect(logMessages()).To(Equal([]string{
+				fmt.Sprintf("no VM is recorded in %s, there is nothing to delete", stateFile),
+			}))
+		})
+
+		It("keeps the VM in the state file when it cannot be deleted", func() {
+			writeState(`{iaas: aws, vm_id: i-1234}`)
+			manager.DeleteVMReturns(errors.New("access denied"))
+
+			err := execute("delete-vm", "--var", "region=us-east-1")
+			Expect(err).To(MatchError("could not delete the VM i-1234: access denied"))
+
+			Expect(readState()).To(MatchYAML(`{iaas: aws, vm_id: i-1234}`))
+		})
+	})
+
+	Describe("replace-vm", func() {
+		It("deletes the VM of the state file, then creates the VM from the image", func() {
+			writeState(`{iaas: aws, vm_id: i-0000}`)
+			manager.CreateVMReturns("i-1234", nil)
+
+			err := execute("replace-vm", "--image-file", imageFile, "--var", "region=us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(manager.DeleteVMArgsForCall(0)).To(Equal("i-0000"))
+			Expect(manager.CreateVMArgsForCall(0)).To(Equal(imageFile))
+
+			Expect(readState()).To(MatchYAML(`{iaas: aws, vm_id: i-1234}`))
+		})
+
+		It("creates the VM when the state file records no VM", func() {
+			manager.CreateVMReturns("i-1234", nil)
+
+			err := execute("replace-vm", "--image-file", imageFile, "--var", "region=us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(manager.DeleteVMCallCount()).To(Equal(0))
+			Expect(readState()).To(MatchYAML(`{iaas: aws, vm_id: i-1234}`))
+		})
+
+		It("does not create the VM when the VM of the state file cannot be deleted", func() {
+			writeState(`{iaas: aws, vm_id: i-0000}`)
+			manager.DeleteVMReturns(errors.New("access denied"))
+
+			err := execute("replace-vm", "--image-file", imageFile, "--var", "region=us-east-1")
+			Expect(err).To(MatchError("could not delete the VM i-0000: access denied"))
+
+			Expect(manager.CreateVMCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("requires a subcommand", func() {
+			err := command.Execute([]string{})
+			Expect(err).To(MatchError("expected a subcommand of vm-lifecycle: create-vm, delete-vm, replace-vm"))
+
+			err = command.Execute([]string{"start-vm"})
+			Expect(err).To(MatchError("expected a subcommand of vm-lifecycle: create-vm, delete-vm, replace-vm"))
+		})
+
+		It("returns an error when the flags cannot be parsed", func() {
+			err := command.Execute([]string{"delete-vm", "--unknown-flag"})
+			Expect(err).To(MatchError(ContainSubstring("could not parse vm-lifecycle flags")))
+		})
+
+		It("returns an error when the config cannot be parsed", func() {
+			Expect(ioutil.WriteFile(configFile, []byte("opsman-configuration: {unknown: {}}"), 0600)).To(Succeed())
+
+			err := execute("delete-vm")
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("could not parse the config file %s", configFile))))
+		})
+
+		It("returns an error when the vars store is not supported", func() {
+			err := execute("delete-vm", "--vars-store", "some-store")
+			Expect(err).To(MatchError(`unknown vars store "some-store", supported vars stores are: credhub, aws-secrets-manager, gcp-secret-manager, vault`))
+
+			Expect(manager.DeleteVMCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when the config is invalid", func() {
+			factoryErr = errors.New("invalid aws configuration")
+
+			err := execute("delete-vm", "--var", "region=us-east-1")
+			Expect(err).To(MatchError("invalid aws configuration"))
+		})
+
+		It("returns an error when the state file records a VM on another IaaS", func() {
+			writeState(`{iaas: gcp, vm_id: ops-manager-vm}`)
+
+			err := execute("delete-vm", "--var", "region=us-east-1")
+			Expect(err).To(MatchError(fmt.Sprintf("the state file %s records a VM on gcp, but the config is for aws", stateFile)))
+
+			Expect(manager.DeleteVMCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("Usage", func() {
+		It("returns usage information for the command", func() {
+			usage := command.Usage()
+			Expect(usage.ShortDescription).To(Equal("creates, deletes or replaces the Ops Manager VM"))
+			Expect(usage.Flags).To(BeAssignableToTypeOf(command.Options))
+			Expect(usage).To(BeAssignableToTypeOf(jhanda.Usage{}))
+		})
+	})
+})
@@ -0,0 +1,220 @@
+package credhub
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/envvars"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+type httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Variables resolves variables from the credentials stored in CredHub,
+// the name of a variable being the name of a credential.
+type Variables struct {
+	client      httpClient
+	server      string
+	credentials map[string]interface{}
+}
+
+var _ boshtpl.Variables = &Variables{}
+
+// NewVariables reads the credentials from the CredHub server with
+// a client that is already authenticated.
+func NewVariables(client httpClient, server string) *Variables {
+	return &Variables{
+		client:      client,
+		server:      strings.TrimSuffix(server, "/"),
+		credentials: map[string]interface{}{},
+	}
+}
+
+// NewVariablesFromEnv configures CredHub from the CREDHUB_SERVER, CREDHUB_CLIENT,
+// CREDHUB_SECRET and CREDHUB_CA_CERT environment variables, like the CredHub CLI.
+// The client authenticates with the UAA advertised by the CredHub server.
+func NewVariablesFromEnv(environ []string) (*Variables, error) {
+	env := envvars.Map(environ)
+
+	server := env["CREDHUB_SERVER"]
+	clientID := env["CREDHUB_CLIENT"]
+	clientSecret := env["CREDHUB_SECRET"]
+	if server == "" || clientID == "" || clientSecret == "" {
+		return nil, errors.New("CREDHUB_SERVER, CREDHUB_CLIENT and CREDHUB_SECRET must be set to read variables from credhub")
+	}
+
+	tlsConfig := &tls.Config{}
+	if caCert := env["CREDHUB_CA_CERT"]; caCert != "" {
+		pool, err := certPool(caCert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	unauthenticatedClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+			Dial: (&net.Dialer{
+				Timeout:   5 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).Dial,
+		},
+		Timeout: 30 * time.Second,
+	}
+
+	authServer, err := authServerURL(unauthenticatedClient, server)
+	if err != nil {
+		return nil, err
+	}
+
+	config := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     strings.TrimSuffix(authServer, "/") + "/oauth/token",
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, unauthenticatedClient)
+
+	return NewVariables(config.Client(ctx), server), nil
+}
+
+// certPool accepts either the contents of the CA certificate or the path to it.
+func certPool(caCert string) (*x509.CertPool, error) {
+	contents := []byte(caCert)
+	if !strings.Contains(caCert, "-----BEGIN") {
+		var err error
+		contents, err = ioutil.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CREDHUB_CA_CERT: %s", err)
+		}
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(contents) {
+		return nil, errors.New("could not parse CREDHUB_CA_CERT: no PEM encoded certificate was found")
+	}
+
+	return pool, nil
+}
+
+func authServerURL(client httpClient, server string) (string, error) {
+	request, err := http.NewRequest("GET", strings.TrimSuffix(server, "/")+"/info", nil)
+	if err != nil {
+		return "", fmt.Errorf("could not parse CREDHUB_SERVER: %s", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("could not get the info of credhub: %s", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not get the info of credhub: unexpected response status %d", response.StatusCode)
+	}
+
+	var output struct {
+		AuthServer struct {
+			URL string `json:"url"`
+		} `json:"auth-server"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&output)
+	if err != nil {
+		return "", fmt.Errorf("could not unmarshal the info of credhub: %s", err)
+	}
+
+	if output.AuthServer.URL == "" {
+		return "", errors.New("could not get the info of credhub: no auth server was returned")
+	}
+
+	return output.AuthServer.URL, nil
+}
+
+func (v *Variables) Get(definition boshtpl.VariableDefinition) (interface{}, bool, error) {
+	name := definition.Name
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+
+	if credential, ok := v.credentials[name]; ok {
+		return credential, true, nil
+	}
+
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/data?name=%s&current=true", v.server, url.QueryEscape(name)), nil)
+	if err != nil {
+		return nil, false, err // un-tested
+	}
+
+	response, err := v.client.Do(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read %s from credhub: %s", name, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("could not read %s from credhub: unexpected response status %d", name, response.StatusCode)
+	}
+
+	var output struct {
+		Data []struct {
+			Value interface{} `json:"value"`
+		} `json:"data"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&output)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not unmarshal the %s credential from credhub: %s", name, err)
+	}
+
+	if len(output.Data) == 0 {
+		return nil, false, nil
+	}
+
+	credential := yamlValue(output.Data[0].Value)
+	v.credentials[name] = credential
+
+	return credential, true, nil
+}
+
+// List is empty as the credentials of CredHub are only read when they are referenced.
+func (v *Variables) List() ([]boshtpl.VariableDefinition, error) {
+	return nil, nil
+}
+
+// yamlValue converts the JSON objects of a credential, such as certificates
+// and users, to maps that can be interpolated like YAML.
+func yamlValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		m := map[interface{}]interface{}{}
+		for key, child := range typedValue {
+			m[key] = yamlValue(child)
+		}
+		return m
+	case []interface{}:
+		for i, child := range typedValue {
+			typedValue[i] = yamlValue(child)
+		}
+		return typedValue
+	}
+
+	return value
+}
@@ -0,0 +1,178 @@
+package credhub_test
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/credhub"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Variables", func() {
+	var (
+		server      *httptest.Server
+		caCert      string
+		environ     []string
+		dataHandler func(w http.ResponseWriter, req *http.Request)
+		dataQueries []string
+	)
+
+	BeforeEach(func() {
+		dataQueries = nil
+		dataHandler = func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(`{"data": [{"type": "password", "value": "some-password"}]}`))
+		}
+
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/info":
+				w.Write([]byte(`{"auth-server": {"url": "` + server.URL + `/uaa"}}`))
+			case "/uaa/oauth/token":
+				clientID, clientSecret, ok := req.BasicAuth()
+				if !ok || clientID != "some-client" || clientSecret != "some-secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"access_token": "some-token", "token_type": "bearer", "expires_in": 3600}`))
+			case "/api/v1/data":
+				Expect(req.Header.Get("Authorization")).To(Equal("Bearer some-token"))
+				dataQueries = append(dataQueries, req.URL.RawQuery)
+				dataHandler(w, req)
+			default:
+				w.WriteHeader(http.StatusTeapot)
+			}
+		}))
+
+		caCert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+		environ = []string{
+			"CREDHUB_SERVER=" + server.URL,
+			"CREDHUB_CLIENT=some-client",
+			"CREDHUB_SECRET=some-secret",
+			"CREDHUB_CA_CERT=" + caCert,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewVariablesFromEnv", func() {
+		It("returns an error when the server or client are not set", func() {
+			_, err := credhub.NewVariablesFromEnv([]string{"CREDHUB_SERVER=" + server.URL})
+			Expect(err).To(MatchError("CREDHUB_SERVER, CREDHUB_CLIENT and CREDHUB_SECRET must be set to read variables from credhub"))
+		})
+
+		It("accepts the path of the CA certificate", func() {
+			caCertFile, err := ioutil.TempFile("", "ca.pem")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(caCertFile.Name())
+
+			_, err = caCertFile.WriteString(caCert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(caCertFile.Close()).To(Succeed())
+
+			environ[3] = "CREDHUB_CA_CERT=" + caCertFile.Name()
+
+			_, err = credhub.NewVariablesFromEnv(environ)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when the CA certificate is invalid", func() {
+			environ[3] = "CREDHUB_CA_CERT=-----BEGIN CERTIFICATE-----"
+
+			_, err := credhub.NewVariablesFromEnv(environ)
+			Expect(err).To(MatchError("could not parse CREDHUB_CA_CERT: no PEM encoded certificate was found"))
+		})
+
+		It("returns an error when the server is not trusted", func() {
+			_, err := credhub.NewVariablesFromEnv(environ[:3])
+			Expect(err).To(MatchError(ContainSubstring("could not get the info of credhub")))
+		})
+	})
+
+	Describe("Get", func() {
+		var vars *credhub.Variables
+
+		BeforeEach(func() {
+			var err error
+			vars, err = credhub.NewVariablesFromEnv(environ)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reads the current value of the credential", func() {
+			password, found, err := vars.Get(boshtpl.VariableDefinition{Name: "/concourse/main/password"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(password).To(Equal("some-password"))
+
+			Expect(dataQueries).To(Equal([]string{"name=%2Fconcourse%2Fmain%2Fpassword&current=true"}))
+		})
+
+		It("reads credentials without a leading slash as absolute names", func() {
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(dataQueries).To(Equal([]string{"name=%2Fpassword&current=true"}))
+		})
+
+		It("reads every credential only once", func() {
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = vars.Get(boshtpl.VariableDefinition{Name: "/password"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(dataQueries).To(HaveLen(1))
+		})
+
+		It("returns the keys of structured credentials", func() {
+			dataHandler = func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(`{"data": [{"type": "certificate", "value": {"ca": "some-ca", "certificate": "some-cert", "private_key": "some-key"}}]}`))
+			}
+
+			certificate, found, err := vars.Get(boshtpl.VariableDefinition{Name: "/cert"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(certificate).To(Equal(map[interface{}]interface{}{
+				"ca":          "some-ca",
+				"certificate": "some-cert",
+				"private_key": "some-key",
+			}))
+		})
+
+		It("does not find credentials that do not exist", func() {
+			dataHandler = func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}
+
+			_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "/missing"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns an error when the credential cannot be read", func() {
+			dataHandler = func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			}
+
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "/password"})
+			Expect(err).To(MatchError("could not read /password from credhub: unexpected response status 403"))
+		})
+
+		It("returns an error when the client cannot authenticate", func() {
+			environ[2] = "CREDHUB_SECRET=wrong-secret"
+			vars, err := credhub.NewVariablesFromEnv(environ)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = vars.Get(boshtpl.VariableDefinition{Name: "/password"})
+			Expect(err).To(MatchError(ContainSubstring("could not read /password from credhub")))
+		})
+	})
+})
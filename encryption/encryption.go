@@ -0,0 +1,309 @@
+// Package encryption encrypts the archives exported from Ops Manager, which
+// contain every secret of the foundation, with AES-256-GCM.
+//
+// The archive is sealed in chunks, so that archives of any size can be
+// streamed. The nonce of every chunk is made of a random prefix, the index of
+// the chunk and whether it is the last one, so that chunks cannot be
+// reordered, dropped or truncated without the decryption failing.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// KeySize is the size in bytes of the keys, AES-256.
+	KeySize = 32
+
+	chunkSize   = 64 * 1024
+	saltSize    = 16
+	prefixSize  = 7
+	iterations  = 600000
+	kdfKey      = byte(0)
+	kdfPBKDF2   = byte(1)
+	headerMagic = "om-encrypted-v1\n"
+
+	// the iterations of an encrypted file are bounded, so that a forged
+	// header cannot make the key derivation trivial or endless
+	minIterations = 10000
+	maxIterations = 10000000
+)
+
+// ErrNotEncrypted is returned when decrypting a file that was not encrypted by om.
+var ErrNotEncrypted = errors.New("the file was not encrypted by om")
+
+// Secret is the passphrase or the key that an archive is encrypted with.
+// Exactly one of them is set.
+type Secret struct {
+	Passphrase string
+	Key        []byte
+}
+
+// ReadKeyFile reads a base64 encoded key of KeySize bytes,
+// such as the output of `openssl rand -base64 32`.
+func ReadKeyFile(path string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return nil, fmt.Errorf("the key must be base64 encoded: %s", err)
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("the key must be %d bytes long, got %d", KeySize, len(key))
+	}
+
+	return key, nil
+}
+
+// IsEncrypted is true when the file starts like the files encrypted by om.
+// It reads the beginning of r.
+func IsEncrypted(r io.Reader) (bool, error) {
+	magic := make([]byte, len(headerMagic))
+	_, err := io.ReadFull(r, magic)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return string(magic) == headerMagic, nil
+}
+
+// Encrypt writes the encrypted contents of src to dst.
+func Encrypt(dst io.Writer, src io.Reader, secret Secret) error {
+	header := bytes.NewBufferString(headerMagic)
+
+	var (
+		key []byte
+		err error
+	)
+	switch {
+	case secret.Passphrase != "" && secret.Key == nil:
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+
+		header.WriteByte(kdfPBKDF2)
+		header.Write(salt)
+		binary.Write(header, binary.BigEndian, uint32(iterations))
+
+		key, err = deriveKey(secret.Passphrase, salt, iterations)
+		if err != nil {
+			return fmt.Errorf("could not derive the key: %s", err) // un-tested
+		}
+	case secret.Passphrase == "" && len(secret.Key) == KeySize:
+		header.WriteByte(kdfKey)
+		key = secret.Key
+	default:
+		return fmt.Errorf("either a passphrase or a key of %d bytes is required", KeySize)
+	}
+
+	prefix := make([]byte, prefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return err
+	}
+	header.Write(prefix)
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := dst.Write(header.Bytes()); err != nil {
+		return err
+	}
+
+	// the chunk after the current one is read ahead, so that the last
+	// chunk is known when it is sealed
+	current := make([]byte, chunkSize)
+	next := make([]byte, chunkSize)
+
+	n, err := readChunk(src, current)
+	if err != nil {
+		return err
+	}
+
+	for index := uint32(0); ; index++ {
+		m, err := readChunk(src, next)
+		if err != nil {
+			return err
+		}
+
+		last := m == 0
+		sealed := aead.Seal(nil, nonce(prefix, index, last), current[:n], header.Bytes())
+		if _, err := dst.Write(sealed); err != nil {
+			return err
+		}
+
+		if last {
+			return nil
+		}
+
+		if index == ^uint32(0) {
+			return errors.New("the file is too large to be encrypted")
+		}
+
+		current, next = next, current
+		n = m
+	}
+}
+
+// Decrypt writes the decrypted contents of src to dst. It fails when the
+// secret is not the one the file was encrypted with, or when the file has
+// been modified or truncated. The contents written to dst before the failure
+// must be discarded.
+func Decrypt(dst io.Writer, src io.Reader, secret Secret) error {
+	encrypted, err := IsEncrypted(src)
+	if err != nil {
+		return err
+	}
+	if !encrypted {
+		return ErrNotEncrypted
+	}
+
+	header := bytes.NewBufferString(headerMagic)
+
+	kdf := make([]byte, 1)
+	if _, err := io.ReadFull(src, kdf); err != nil {
+		return truncated(err)
+	}
+	header.Write(kdf)
+
+	var key []byte
+	switch kdf[0] {
+	case kdfPBKDF2:
+		params := make([]byte, saltSize+4)
+		if _, err := io.ReadFull(src, params); err != nil {
+			return truncated(err)
+		}
+		header.Write(params)
+
+		if secret.Passphrase == "" {
+			return errors.New("the file was encrypted with a passphrase")
+		}
+
+		n := int(binary.BigEndian.Uint32(params[saltSize:]))
+		if n < minIterations || n > maxIterations {
+			return fmt.Errorf("could not decrypt the file: %d iterations of the key derivation is not between %d and %d", n, minIterations, maxIterations)
+		}
+
+		key, err = deriveKey(secret.Passphrase, params[:saltSize], n)
+		if err != nil {
+			return fmt.Errorf("could not derive the key: %s", err) // un-tested
+		}
+	case kdfKey:
+		if len(secret.Key) != KeySize {
+			return errors.New("the file was encrypted with a key")
+		}
+
+		key = secret.Key
+	default:
+		return fmt.Errorf("unsupported key derivation %d", kdf[0])
+	}
+
+	prefix := make([]byte, prefixSize)
+	if _, err := io.ReadFull(src, prefix); err != nil {
+		return truncated(err)
+	}
+	header.Write(prefix)
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	sealedSize := chunkSize + aead.Overhead()
+	current := make([]byte, sealedSize)
+	next := make([]byte, sealedSize)
+
+	n, err := readChunk(src, current)
+	if err != nil {
+		return err
+	}
+
+	for index := uint32(0); ; index++ {
+		m, err := readChunk(src, next)
+		if err != nil {
+			return err
+		}
+
+		last := m == 0
+		opened, err := aead.Open(current[:0], nonce(prefix, index, last), current[:n], header.Bytes())
+		if err != nil {
+			return errors.New("could not decrypt the file: the secret is incorrect, or the file is corrupted")
+		}
+
+		if _, err := dst.Write(opened); err != nil {
+			return err
+		}
+
+		if last {
+			return nil
+		}
+
+		current, next = next, current
+		n = m
+	}
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func nonce(prefix []byte, index uint32, last bool) []byte {
+	n := make([]byte, 0, prefixSize+5)
+	n = append(n, prefix...)
+	n = append(n, byte(index>>24), byte(index>>16), byte(index>>8), byte(index))
+	if last {
+		return append(n, 1)
+	}
+
+	return append(n, 0)
+}
+
+// readChunk fills the chunk, unless the reader ends before it is full.
+func readChunk(r io.Reader, chunk []byte) (int, error) {
+	n, err := io.ReadFull(r, chunk)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return n, nil
+	}
+
+	return n, err
+}
+
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("could not decrypt the file: it is truncated")
+	}
+
+	return err
+}
+
+// deriveKey derives a key of KeySize bytes from the passphrase with
+// PBKDF2-HMAC-SHA256 (RFC 8018).
+func deriveKey(passphrase string, salt []byte, iterations int) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, iterations, KeySize)
+}
@@ -0,0 +1,225 @@
+package encryption_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/om/encryption"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("encryption", func() {
+	var (
+		key       []byte
+		plaintext []byte
+	)
+
+	BeforeEach(func() {
+		key = make([]byte, encryption.KeySize)
+		_, err := rand.Read(key)
+		Expect(err).NotTo(HaveOccurred())
+
+		// spans several chunks, the last one being partial
+		plaintext = make([]byte, 200*1024+17)
+		_, err = rand.Read(plaintext)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	encrypt := func(plaintext []byte, secret encryption.Secret) []byte {
+		var encrypted bytes.Buffer
+		err := encryption.Encrypt(&encrypted, bytes.NewReader(plaintext), secret)
+		Expect(err).NotTo(HaveOccurred())
+		return encrypted.Bytes()
+	}
+
+	decrypt := func(encrypted []byte, secret encryption.Secret) ([]byte, error) {
+		var decrypted bytes.Buffer
+		err := encryption.Decrypt(&decrypted, bytes.NewReader(encrypted), secret)
+		return decrypted.Bytes(), err
+	}
+
+	It("encrypts and decrypts with a passphrase", func() {
+		encrypted := encrypt(plaintext, encryption.Secret{Passphrase: "some-passphrase"})
+		Expect(bytes.Contains(encrypted, plaintext[:64])).To(BeFalse())
+
+		decrypted, err := decrypt(encrypted, encryption.Secret{Passphrase: "some-passphrase"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decrypted).To(Equal(plaintext))
+	})
+
+	It("encrypts and decrypts with a key", func() {
+		encrypted := encrypt(plaintext, encryption.Secret{Key: key})
+
+		decrypted, err := decrypt(encrypted, encryption.Secret{Key: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decrypted).To(Equal(plaintext))
+	})
+
+	It("encrypts and decrypts empty contents and contents of a whole number of chunks", func() {
+		for _, contents := range [][]byte{{}, plaintext[:128*1024]} {
+			decrypted, err := decrypt(encrypt(contents, encryption.Secret{Key: key}), encryption.Secret{Key: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decrypted).To(HaveLen(len(contents)))
+			Expect(bytes.Equal(decrypted, contents)).To(BeTrue())
+		}
+	})
+
+	It("encrypts the same contents differently every time", func() {
+		Expect(encrypt(plaintext, encryption.Secret{Key: key})).NotTo(Equal(encrypt(plaintext, encryption.Secret{Key: key})))
+	})
+
+	It("tells whether a file was encrypted", func() {
+		encrypted, err := encryption.IsEncrypted(bytes.NewReader(encrypt(plaintext, encryption.Secret{Key: key})))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encrypted).To(BeTrue())
+
+		encrypted, err = encryption.IsEncrypted(bytes.NewReader(plaintext))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encrypted).To(BeFalse())
+
+		encrypted, err = encryption.IsEncrypted(bytes.NewReader([]byte("om")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encrypted).To(BeFalse())
+	})
+
+	Context("failure cases", func() {
+		It("requires exactly one of a passphrase and a key", func() {
+			for _, secret := range []encryption.Secret{
+				{},
+				{Passphrase: "some-passphrase", Key: key},
+				{Key: key[:16]},
+			} {
+				err := encryption.Encrypt(ioutil.Discard, bytes.NewReader(plaintext), secret)
+				Expect(err).To(MatchError("either a passphrase or a key of 32 bytes is required"))
+			}
+		})
+
+		It("fails with the wrong passphrase", func() {
+			encrypted := encrypt(plaintext, encryption.Secret{Passphrase: "some-passphrase"})
+
+			_, err := decrypt(encrypted, encryption.Secret{Passphrase: "other-passphrase"})
+			Expect(err).To(MatchError("could not decrypt the file: the secret is incorrect, or the file is corrupted"))
+
+			_, err = decrypt(encrypted, encryption.Secret{Key: key})
+			Expect(err).To(MatchError("the file was encrypted with a passphrase"))
+		})
+
+		It("fails with the wrong key", func() {
+			encrypted := encrypt(plaintext, encryption.Secret{Key: key})
+
+			otherKey := make([]byte, encryption.KeySize)
+			_, err := decrypt(encrypted, encryption.Secret{Key: otherKey})
+			Expect(err).To(MatchError("could not decrypt the file: the secret is incorrect, or the file is corrupted"))
+
+			_, err = decrypt(encrypted, encryption.Secret{Passphrase: "some-passphrase"})
+			Expect(err).To(MatchError("the file was encrypted with a key"))
+		})
+
+		It("fails when the file was modified", func() {
+			encrypted := encrypt(plaintext, encryption.Secret{Key: key})
+			encrypted[len(encrypted)/2] ^= 1
+
+			_, err := decrypt(encrypted, encryption.Secret{Key: key})
+			Expect(err).To(MatchError("could not decrypt the file: the secret is incorrect, or the file is corrupted"))
+		})
+
+		It("fails when the file was truncated at the end of a chunk", func() {
+			encrypted := encrypt(plaintext, encryption.Secret{Key: key})
+			header := len(encrypted) - len(plaintext) - 4*16
+
+			_, err := decrypt(encrypted[:header+2*(64*1024+16)], encryption.Secret{Key: key})
+			Expect(err).To(MatchError("could not decrypt the file: the secret is incorrect, or the file is corrupted"))
+
+			_, err = decrypt(encrypted[:10], encryption.Secret{Key: key})
+			Expect(err).To(Equal(encryption.ErrNotEncrypted))
+
+			_, err = decrypt(encrypted[:20], encryption.Secret{Key: key})
+			Expect(err).To(MatchError("could not decrypt the file: it is truncated"))
+		})
+
+		It("fails when the iterations of the key derivation are out of bounds", func() {
+			encrypted := encrypt(plaintext, encryption.Secret{Passphrase: "some-passphrase"})
+			iterations := len("om-encrypted-v1\n") + 1 + 16
+
+			binary.BigEndian.PutUint32(encrypted[iterations:], 1)
+			_, err := decrypt(encrypted, encryption.Secret{Passphrase: "some-passphrase"})
+			Expect(err).To(MatchError("could not decrypt the file: 1 iterations of the key derivation is not between 10000 and 10000000"))
+
+			binary.BigEndian.PutUint32(encrypted[iterations:], 1<<31)
+			_, err = decrypt(encrypted, encryption.Secret{Passphrase: "some-passphrase"})
+			Expect(err).To(MatchError("could not decrypt the file: 2147483648 iterations of the key derivation is not between 10000 and 10000000"))
+		})
+
+		It("fails when the file was not encrypted", func() {
+			_, err := decrypt(plaintext, encryption.Secret{Key: key})
+			Expect(err).To(Equal(encryption.ErrNotEncrypted))
+		})
+	})
+
+	Describe("the key derivation", func() {
+		// RFC 6070 only has vectors of PBKDF2-HMAC-SHA1, these are the
+		// PBKDF2-HMAC-SHA256 keys of its inputs, and the one of RFC 7914
+		DescribeTable("derives the keys of the test vectors", func(passphrase, salt string, iterations int, expected string) {
+			key, err := encryption.DeriveKey(passphrase, []byte(salt), iterations)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hex.EncodeToString(key)).To(Equal(expected))
+		},
+			Entry("with 1 iteration", "password", "salt", 1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"),
+			Entry("with 2 iterations", "password", "salt", 2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"),
+			Entry("with 4096 iterations", "password", "salt", 4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"),
+			Entry("with a longer passphrase and salt", "passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, "348c89dbcbd32b2f32d814b8116e84cf2b17347ebc1800181c4e2a1fb8dd53e1"),
+			Entry("with the vector of RFC 7914, truncated to the key size", "passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"),
+		)
+	})
+
+	Describe("ReadKeyFile", func() {
+		var keyFile string
+
+		BeforeEach(func() {
+			f, err := ioutil.TempFile("", "")
+			Expect(err).NotTo(HaveOccurred())
+			keyFile = f.Name()
+			f.Close()
+		})
+
+		AfterEach(func() {
+			os.Remove(keyFile)
+		})
+
+		It("reads a base64 encoded key", func() {
+			err := ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(encryption.ReadKeyFile(keyFile)).To(Equal(key))
+		})
+
+		It("rejects the keys that are not base64 encoded", func() {
+			err := ioutil.WriteFile(keyFile, []byte("not base64!"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = encryption.ReadKeyFile(keyFile)
+			Expect(err).To(MatchError(ContainSubstring("the key must be base64 encoded")))
+		})
+
+		It("rejects the keys of the wrong size", func() {
+			err := ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key[:16])), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = encryption.ReadKeyFile(keyFile)
+			Expect(err).To(MatchError("the key must be 32 bytes long, got 16"))
+		})
+
+		It("returns an error when the file cannot be read", func() {
+			_, err := encryption.ReadKeyFile("/does/not/exist")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
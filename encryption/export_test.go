@@ -0,0 +1,3 @@
+package encryption
+
+var DeriveKey = deriveKey
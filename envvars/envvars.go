@@ -0,0 +1,18 @@
+package envvars
+
+import "strings"
+
+// Map indexes the environment variables of environ, as returned by
+// os.Environ, by their name. The entries that are not key-value pairs are
+// left out, and the last value of a name wins.
+func Map(environ []string) map[string]string {
+	env := map[string]string{}
+	for _, envVar := range environ {
+		pieces := strings.SplitN(envVar, "=", 2)
+		if len(pieces) == 2 {
+			env[pieces[0]] = pieces[1]
+		}
+	}
+
+	return env
+}
@@ -0,0 +1,24 @@
+package envvars_test
+
+import (
+	"github.com/pivotal-cf/om/envvars"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Map", func() {
+	It("indexes the environment variables by their name", func() {
+		Expect(envvars.Map([]string{
+			"SOME_NAME=some-value",
+			"SOME_URL=https://example.com/?a=b",
+			"SOME_EMPTY=",
+			"NOT_A_PAIR",
+			"SOME_NAME=some-other-value",
+		})).To(Equal(map[string]string{
+			"SOME_NAME":  "some-other-value",
+			"SOME_URL":   "https://example.com/?a=b",
+			"SOME_EMPTY": "",
+		}))
+	})
+})
@@ -0,0 +1,13 @@
+package envvars_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestEnvVars(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "envvars")
+}
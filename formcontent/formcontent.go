@@ -17,11 +17,13 @@ type Form struct {
 	pw          *io.PipeWriter
 	formFields  *bytes.Buffer
 	formWriter  *multipart.Writer
-	files       []string
+	files       []fileOpener
 	fileKeys    []*bytes.Buffer
 	doneWriting chan error
 }
 
+type fileOpener func() (io.ReadCloser, error)
+
 type ContentSubmission struct {
 	Content       io.Reader
 	ContentType   string
@@ -85,15 +87,32 @@ func (f *Form) AddFile(key string, path string) error {
 		return err
 	}
 
-	buf := &bytes.Buffer{}
+	return f.addFile(key, filepath.Base(path), fileLength, func() (io.ReadCloser, error) {
+		return os.Open(path)
+	})
+}
+
+// AddReader adds a file whose content is streamed from the reader rather than read from disk.
+// The length must be known up front to calculate the content length of the submission.
+func (f *Form) AddReader(key string, fileName string, reader io.ReadCloser, length int64) error {
+	if length <= 0 {
+		return errors.New("file provided has no content")
+	}
+
+	return f.addFile(key, fileName, length, func() (io.ReadCloser, error) {
+		return reader, nil
+	})
+}
 
+func (f *Form) addFile(key string, fileName string, fileLength int64, open fileOpener) error {
+	buf := &bytes.Buffer{}
 	fileKey := multipart.NewWriter(buf)
-	err = fileKey.SetBoundary(f.boundary)
+	err := fileKey.SetBoundary(f.boundary)
 	if err != nil {
 		return err
 	}
 
-	_, err = fileKey.CreateFormFile(key, filepath.Base(path))
+	_, err = fileKey.CreateFormFile(key, fileName)
 	if err != nil {
 		return err
 	}
@@ -102,7 +121,7 @@ func (f *Form) AddFile(key string, path string) error {
 	f.length += fileLength
 	f.length += int64(buf.Len())
 
-	f.files = append(f.files, path)
+	f.files = append(f.files, open)
 	f.fileKeys = append(f.fileKeys, buf)
 
 	return nil
@@ -173,8 +192,7 @@ func (f *Form) writeToPipe() {
 			return
 		}
 
-		fileName := f.files[i]
-		err = writeFileToPipe(fileName, f.pw)
+		err = writeFileToPipe(f.files[i], f.pw)
 		if err != nil {
 			_ = f.pw.CloseWithError(err)
 			f.doneWriting <- err
@@ -206,8 +224,8 @@ func (f *Form) writeToPipe() {
 	return
 }
 
-func writeFileToPipe(fileName string, writer *io.PipeWriter) error {
-	fileContent, err := os.Open(fileName)
+func writeFileToPipe(open fileOpener, writer *io.PipeWriter) error {
+	fileContent, err := open()
 	if err != nil {
 		return err
 	}
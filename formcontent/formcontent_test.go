@@ -7,6 +7,7 @@ import (
 
 	"io/ioutil"
 	"os"
+	"strings"
 )
 
 var _ = Describe("Formcontent", func() {
@@ -135,6 +136,34 @@ var _ = Describe("Formcontent", func() {
 		})
 	})
 
+	Describe("AddReader", func() {
+		BeforeEach(func() {
+			form = formcontent.NewForm()
+		})
+
+		It("writes out the content of the reader as a file in the multipart form", func() {
+			err := form.AddReader("something[file1]", "some-stemcell.tgz", ioutil.NopCloser(strings.NewReader("some content")), 12)
+			Expect(err).NotTo(HaveOccurred())
+
+			submission := form.Finalize()
+			content, err := ioutil.ReadAll(submission.Content)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(submission.ContentLength).To(Equal(int64(len(content))))
+			Expect(string(content)).To(MatchRegexp(`^--\w+\r\nContent-Disposition: form-data; name=\"something\[file1\]\"; filename=\"some-stemcell.tgz\"\r\n` +
+				`Content-Type: application/octet-stream\r\n\r\n` +
+				`some content` +
+				`\r\n--\w+--\r\n$`))
+		})
+
+		Context("when the length of the content is not known", func() {
+			It("returns an error", func() {
+				err := form.AddReader("foo", "some-file", ioutil.NopCloser(strings.NewReader("")), -1)
+				Expect(err).To(MatchError("file provided has no content"))
+			})
+		})
+	})
+
 	Describe("AddField", func() {
 		BeforeEach(func() {
 			form = formcontent.NewForm()
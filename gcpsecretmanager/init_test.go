@@ -0,0 +1,13 @@
+package gcpsecretmanager_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGCPSecretManager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "gcpsecretmanager")
+}
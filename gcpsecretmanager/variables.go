@@ -0,0 +1,175 @@
+package gcpsecretmanager
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/envvars"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	apiURL        = "https://secretmanager.googleapis.com"
+	cloudPlatform = "https://www.googleapis.com/auth/cloud-platform"
+	tokenURL      = "https://oauth2.googleapis.com/token"
+)
+
+type httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Variables resolves variables from the latest versions of the secrets stored
+// in GCP Secret Manager, the name of a secret being the prefix followed by the
+// name of the variable.
+type Variables struct {
+	client  httpClient
+	api     string
+	project string
+	prefix  string
+	secrets map[string]interface{}
+}
+
+var _ boshtpl.Variables = &Variables{}
+
+// NewVariables reads the secrets of the project from the Secret Manager API
+// with a client that is already authenticated.
+func NewVariables(client httpClient, api, project, prefix string) *Variables {
+	return &Variables{
+		client:  client,
+		api:     strings.TrimSuffix(api, "/"),
+		project: project,
+		prefix:  prefix,
+		secrets: map[string]interface{}{},
+	}
+}
+
+// NewVariablesFromEnv configures Secret Manager from the GOOGLE_APPLICATION_CREDENTIALS,
+// GOOGLE_CLOUD_PROJECT and OM_VARS_STORE_PREFIX environment variables.
+// The client authenticates with the service account key, whose project
+// is used when GOOGLE_CLOUD_PROJECT is not set.
+func NewVariablesFromEnv(environ []string) (*Variables, error) {
+	env := envvars.Map(environ)
+
+	keyFile := env["GOOGLE_APPLICATION_CREDENTIALS"]
+	if keyFile == "" {
+		return nil, errors.New("GOOGLE_APPLICATION_CREDENTIALS must be set to read variables from gcp secret manager")
+	}
+
+	contents, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read GOOGLE_APPLICATION_CREDENTIALS: %s", err)
+	}
+
+	var key struct {
+		ProjectID    string `json:"project_id"`
+		ClientEmail  string `json:"client_email"`
+		PrivateKey   string `json:"private_key"`
+		PrivateKeyID string `json:"private_key_id"`
+		TokenURI     string `json:"token_uri"`
+	}
+	err = json.Unmarshal(contents, &key)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse GOOGLE_APPLICATION_CREDENTIALS: %s", err)
+	}
+
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, errors.New("could not parse GOOGLE_APPLICATION_CREDENTIALS: the service account key has no client_email or private_key")
+	}
+
+	project := env["GOOGLE_CLOUD_PROJECT"]
+	if project == "" {
+		project = key.ProjectID
+	}
+	if project == "" {
+		return nil, errors.New("GOOGLE_CLOUD_PROJECT must be set when the service account key has no project_id")
+	}
+
+	config := jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       []string{cloudPlatform},
+		TokenURL:     key.TokenURI,
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = tokenURL
+	}
+
+	return NewVariables(config.Client(context.Background()), apiURL, project, env["OM_VARS_STORE_PREFIX"]), nil
+}
+
+func (v *Variables) Get(definition boshtpl.VariableDefinition) (interface{}, bool, error) {
+	name := v.prefix + definition.Name
+
+	if secret, ok := v.secrets[name]; ok {
+		return secret, true, nil
+	}
+
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/projects/%s/secrets/%s/versions/latest:access", v.api, url.PathEscape(v.project), url.PathEscape(name)), nil)
+	if err != nil {
+		return nil, false, err // un-tested
+	}
+
+	response, err := v.client.Do(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read %s from gcp secret manager: %s", name, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("could not read %s from gcp secret manager: unexpected response status %d", name, response.StatusCode)
+	}
+
+	var output struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&output)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not unmarshal the %s secret from gcp secret manager: %s", name, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(output.Payload.Data)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not decode the %s secret from gcp secret manager: %s", name, err)
+	}
+
+	secret := secretValue(string(data))
+	v.secrets[name] = secret
+
+	return secret, true, nil
+}
+
+// List is empty as the secrets of Secret Manager are only read when they are referenced.
+func (v *Variables) List() ([]boshtpl.VariableDefinition, error) {
+	return nil, nil
+}
+
+// secretValue returns the keys of the secrets that are JSON objects, and the
+// other secrets as is.
+func secretValue(value string) interface{} {
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &object); err != nil || object == nil {
+		return value
+	}
+
+	secret := map[interface{}]interface{}{}
+	for key, child := range object {
+		secret[key] = child
+	}
+
+	return secret
+}
@@ -0,0 +1,175 @@
+package gcpsecretmanager_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/gcpsecretmanager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Variables", func() {
+	var (
+		server   *httptest.Server
+		requests []*http.Request
+		handler  func(w http.ResponseWriter, req *http.Request)
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		handler = func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(`{"name": "projects/some-project/secrets/om-password/versions/1", "payload": {"data": "` + base64.StdEncoding.EncodeToString([]byte("some-password")) + `"}}`))
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests = append(requests, req)
+			handler(w, req)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewVariablesFromEnv", func() {
+		var keyFile string
+
+		writeKey := func(key map[string]string) {
+			contents, err := json.Marshal(key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(keyFile, contents, 0600)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			file, err := ioutil.TempFile("", "key.json")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+			keyFile = file.Name()
+
+			privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+
+			writeKey(map[string]string{
+				"type":         "service_account",
+				"project_id":   "some-project",
+				"client_email": "om@some-project.iam.gserviceaccount.com",
+				"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})),
+			})
+		})
+
+		AfterEach(func() {
+			os.Remove(keyFile)
+		})
+
+		It("returns an error when the service account key is not set", func() {
+			_, err := gcpsecretmanager.NewVariablesFromEnv([]string{"GOOGLE_CLOUD_PROJECT=some-project"})
+			Expect(err).To(MatchError("GOOGLE_APPLICATION_CREDENTIALS must be set to read variables from gcp secret manager"))
+		})
+
+		It("accepts a service account key", func() {
+			vars, err := gcpsecretmanager.NewVariablesFromEnv([]string{"GOOGLE_APPLICATION_CREDENTIALS=" + keyFile})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).NotTo(BeNil())
+		})
+
+		It("returns an error when the service account key cannot be read", func() {
+			_, err := gcpsecretmanager.NewVariablesFromEnv([]string{"GOOGLE_APPLICATION_CREDENTIALS=/does/not/exist.json"})
+			Expect(err).To(MatchError(ContainSubstring("could not read GOOGLE_APPLICATION_CREDENTIALS")))
+		})
+
+		It("returns an error when the service account key is incomplete", func() {
+			writeKey(map[string]string{"project_id": "some-project"})
+
+			_, err := gcpsecretmanager.NewVariablesFromEnv([]string{"GOOGLE_APPLICATION_CREDENTIALS=" + keyFile})
+			Expect(err).To(MatchError("could not parse GOOGLE_APPLICATION_CREDENTIALS: the service account key has no client_email or private_key"))
+		})
+
+		It("returns an error when there is no project", func() {
+			writeKey(map[string]string{"client_email": "om@some-project.iam.gserviceaccount.com", "private_key": "some-key"})
+
+			_, err := gcpsecretmanager.NewVariablesFromEnv([]string{"GOOGLE_APPLICATION_CREDENTIALS=" + keyFile})
+			Expect(err).To(MatchError("GOOGLE_CLOUD_PROJECT must be set when the service account key has no project_id"))
+		})
+	})
+
+	Describe("Get", func() {
+		var vars *gcpsecretmanager.Variables
+
+		BeforeEach(func() {
+			vars = gcpsecretmanager.NewVariables(http.DefaultClient, server.URL, "some-project", "om-")
+		})
+
+		It("reads the latest version of the secret named after the prefix and the variable", func() {
+			password, found, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(password).To(Equal("some-password"))
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal("GET"))
+			Expect(requests[0].URL.Path).To(Equal("/v1/projects/some-project/secrets/om-password/versions/latest:access"))
+		})
+
+		It("reads every secret only once", func() {
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(requests).To(HaveLen(1))
+		})
+
+		It("returns the keys of the secrets that are JSON objects", func() {
+			handler = func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(`{"payload": {"data": "` + base64.StdEncoding.EncodeToString([]byte(`{"username": "admin", "password": "some-password"}`)) + `"}}`))
+			}
+
+			user, found, err := vars.Get(boshtpl.VariableDefinition{Name: "user"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(user).To(Equal(map[interface{}]interface{}{
+				"username": "admin",
+				"password": "some-password",
+			}))
+		})
+
+		It("does not find secrets that do not exist", func() {
+			handler = func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}
+
+			_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "missing"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns an error when the secret cannot be read", func() {
+			handler = func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			}
+
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).To(MatchError("could not read om-password from gcp secret manager: unexpected response status 403"))
+		})
+
+		It("returns an error when the payload is not base64", func() {
+			handler = func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(`{"payload": {"data": "%%%"}}`))
+			}
+
+			_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+			Expect(err).To(MatchError(ContainSubstring("could not decode the om-password secret from gcp secret manager")))
+		})
+	})
+})
@@ -3,7 +3,7 @@ module github.com/pivotal-cf/om
 require (
 	github.com/PuerkitoBio/goquery v1.4.0
 	github.com/andybalholm/cascadia v1.0.0 // indirect
-	github.com/aws/aws-sdk-go v1.16.27 // indirect
+	github.com/aws/aws-sdk-go v1.16.27
 	github.com/bmatcuk/doublestar v1.1.1 // indirect
 	github.com/charlievieth/fs v0.0.0-20170613215519-7dc373669fa1 // indirect
 	github.com/cheekybits/is v0.0.0-20150225183255-68e9c0620927 // indirect
@@ -0,0 +1,7 @@
+package jsonlog
+
+import "time"
+
+func (w *Writer) SetNow(now func() time.Time) {
+	w.now = now
+}
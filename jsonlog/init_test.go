@@ -0,0 +1,13 @@
+package jsonlog_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestJSONLog(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "jsonlog")
+}
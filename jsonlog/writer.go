@@ -0,0 +1,93 @@
+// Package jsonlog writes the output of om as JSON records, one per line,
+// for log aggregation systems to parse.
+package jsonlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// escapeSequences are the terminal control sequences of the progress bars,
+// which have no meaning in a record.
+var escapeSequences = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+type record struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Stream  string `json:"stream"`
+	Message string `json:"message"`
+}
+
+// Writer writes every line that is written to it as a record with the time
+// it was written, its level, and the stream it was written to.
+// Progress bars, which redraw a line with carriage returns, write a record for every redraw.
+type Writer struct {
+	mutex  sync.Mutex
+	out    io.Writer
+	level  string
+	stream string
+	line   []byte
+	now    func() time.Time
+}
+
+func NewWriter(out io.Writer, level, stream string) *Writer {
+	return &Writer{
+		out:    out,
+		level:  level,
+		stream: stream,
+		now:    time.Now,
+	}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, b := range p {
+		if b != '\n' && b != '\r' {
+			w.line = append(w.line, b)
+			continue
+		}
+
+		err := w.writeRecord()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the last line, if it has not been terminated.
+func (w *Writer) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.writeRecord()
+}
+
+func (w *Writer) writeRecord() error {
+	message := escapeSequences.ReplaceAll(w.line, nil)
+	w.line = w.line[:0]
+
+	if len(bytes.TrimSpace(message)) == 0 {
+		return nil
+	}
+
+	contents, err := json.Marshal(record{
+		Time:    w.now().UTC().Format(time.RFC3339Nano),
+		Level:   w.level,
+		Stream:  w.stream,
+		Message: string(message),
+	})
+	if err != nil {
+		return err // un-tested
+	}
+
+	_, err = w.out.Write(append(contents, '\n'))
+	return err
+}
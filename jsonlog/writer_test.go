@@ -0,0 +1,62 @@
+package jsonlog_test
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/pivotal-cf/om/jsonlog"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Writer", func() {
+	var (
+		out    *bytes.Buffer
+		writer *jsonlog.Writer
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		writer = jsonlog.NewWriter(out, "info", "stdout")
+		writer.SetNow(func() time.Time {
+			return time.Date(2019, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+		})
+	})
+
+	It("writes every line as a record", func() {
+		_, err := fmt.Fprint(writer, "first line\nsecond ")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = fmt.Fprint(writer, "line\n")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).To(Equal(
+			`{"time":"2019-03-04T04:06:07Z","level":"info","stream":"stdout","message":"first line"}` + "\n" +
+				`{"time":"2019-03-04T04:06:07Z","level":"info","stream":"stdout","message":"second line"}` + "\n",
+		))
+	})
+
+	It("writes a record for every redraw of a progress bar, without the terminal control sequences", func() {
+		_, err := fmt.Fprint(writer, "\x1b[2K10 MiB / 20 MiB\r\x1b[1A\x1b[2K20 MiB / 20 MiB\r\n\n")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).To(Equal(
+			`{"time":"2019-03-04T04:06:07Z","level":"info","stream":"stdout","message":"10 MiB / 20 MiB"}` + "\n" +
+				`{"time":"2019-03-04T04:06:07Z","level":"info","stream":"stdout","message":"20 MiB / 20 MiB"}` + "\n",
+		))
+	})
+
+	It("writes the last line when it is flushed", func() {
+		_, err := fmt.Fprint(writer, "unterminated")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(BeEmpty())
+
+		Expect(writer.Flush()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`"message":"unterminated"`))
+
+		Expect(writer.Flush()).To(Succeed())
+		Expect(out.String()).To(HaveSuffix("}\n"))
+		Expect(bytes.Count(out.Bytes(), []byte("\n"))).To(Equal(1))
+	})
+})
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
 
 	"time"
 
@@ -20,16 +26,21 @@ import (
 	"github.com/pivotal-cf/om/commands"
 	"github.com/pivotal-cf/om/extractor"
 	"github.com/pivotal-cf/om/formcontent"
+	"github.com/pivotal-cf/om/jsonlog"
 	"github.com/pivotal-cf/om/network"
 	"github.com/pivotal-cf/om/presenters"
 	"github.com/pivotal-cf/om/progress"
-	"github.com/pivotal-cf/om/ui"
+	"github.com/pivotal-cf/om/runner"
+	"github.com/pivotal-cf/om/telemetry"
+	"github.com/pivotal-cf/om/vmlifecycle"
 )
 
 var version = "unknown"
 
 var applySleepDurationString = "10s"
 
+const applyChangesTimeoutExitCode = 2
+
 type httpClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
@@ -39,14 +50,23 @@ type options struct {
 	ClientID             string `yaml:"client-id"             short:"c"  long:"client-id"           env:"OM_CLIENT_ID"                           description:"Client ID for the Ops Manager VM (not required for unauthenticated commands)"`
 	ClientSecret         string `yaml:"client-secret"         short:"s"  long:"client-secret"       env:"OM_CLIENT_SECRET"                       description:"Client Secret for the Ops Manager VM (not required for unauthenticated commands)"`
 	Help                 bool   `                             short:"h"  long:"help"                                             default:"false" description:"prints this usage information"`
+	NoCache              bool   `yaml:"no-cache"                         long:"no-cache"            env:"OM_NO_CACHE"            default:"false" description:"do not reuse the UAA token of the previous commands, nor store it for the next ones"`
+	OTLPEndpoint         string `yaml:"otlp-endpoint"                    long:"otlp-endpoint"       env:"OTEL_EXPORTER_OTLP_ENDPOINT"            description:"OpenTelemetry collector to export the traces and the metrics of the downloads, the uploads and apply-changes to over OTLP/HTTP, e.g. http://localhost:4318"`
 	Password             string `yaml:"password"              short:"p"  long:"password"            env:"OM_PASSWORD"                            description:"admin password for the Ops Manager VM (not required for unauthenticated commands)"`
+	PluginsDir           string `yaml:"plugins-dir"                      long:"plugins-dir"         env:"OM_PLUGINS_DIR"                         description:"directory of the om-<name> executables that are run as the om <name> commands, before the ones on the PATH"`
+	PluginsCredentials   bool   `yaml:"plugins-credentials"              long:"plugins-credentials" env:"OM_PLUGINS_CREDENTIALS" default:"false" description:"also give the credentials of om (username, password, client id and secret, decryption passphrase) to the plugins, which only get the target and a token otherwise"`
 	ConnectTimeout       int    `yaml:"connect-timeout"       short:"o"  long:"connect-timeout"     env:"OM_CONNECT_TIMEOUT"     default:"10"    description:"timeout in seconds to make TCP connections"`
 	RequestTimeout       int    `yaml:"request-timeout"       short:"r"  long:"request-timeout"     env:"OM_REQUEST_TIMEOUT"     default:"1800"  description:"timeout in seconds for HTTP requests to Ops Manager"`
+	Retries              int    `yaml:"retries"                          long:"retries"             env:"OM_RETRIES"             default:"3"     description:"number of times the requests that do not modify Ops Manager are retried when the connection is dropped or Ops Manager is unavailable (502, 503 or 504)"`
+	RetryDelay           int    `yaml:"retry-delay"                      long:"retry-delay"         env:"OM_RETRY_DELAY"         default:"1"     description:"delay in seconds before the first retry, doubled after every retry"`
 	SkipSSLValidation    bool   `yaml:"skip-ssl-validation"   short:"k"  long:"skip-ssl-validation" env:"OM_SKIP_SSL_VALIDATION" default:"false" description:"skip ssl certificate validation during http requests"`
 	Target               string `yaml:"target"                short:"t"  long:"target"              env:"OM_TARGET"                              description:"location of the Ops Manager VM"`
-	Trace                bool   `yaml:"trace"                 short:"tr" long:"trace"               env:"OM_TRACE"                               description:"prints HTTP requests and response payloads"`
+	Trace                bool   `yaml:"trace"                 short:"tr" long:"trace"               env:"OM_TRACE"                               description:"prints HTTP requests and responses, with the headers that carry credentials redacted"`
+	TraceBodies          bool   `yaml:"trace-bodies"                     long:"trace-bodies"        env:"OM_TRACE_BODIES"        default:"false" description:"also prints the payloads of the traced requests and responses, which can contain secrets"`
+	TraceFile            string `yaml:"trace-file"                       long:"trace-file"          env:"OM_TRACE_FILE"                          description:"file to append the trace to instead of stderr (implies --trace)"`
 	Username             string `yaml:"username"              short:"u"  long:"username"            env:"OM_USERNAME"                            description:"admin username for the Ops Manager VM (not required for unauthenticated commands)"`
-	Env                  string `                             short:"e"  long:"env"                                                              description:"env file with login credentials"`
+	LogFormat            string `yaml:"log-format"                       long:"log-format"          env:"OM_LOG_FORMAT"          default:"text"  description:"format of the output (options: text,json), json writes every line as a record with its time and level"`
+	Env                  string `                             short:"e"  long:"env"                 env:"OM_ENV"                                 description:"env file with login credentials"`
 	Version              bool   `                             short:"v"  long:"version"                                          default:"false" description:"prints the om release version"`
 }
 
@@ -63,7 +83,7 @@ func main() {
 		stderr.Fatal(err)
 	}
 
-	err = setEnvFileProperties(&global)
+	err = setEnvFileProperties(&global, stderr)
 	if err != nil {
 		stderr.Fatal(err)
 	}
@@ -90,36 +110,94 @@ func main() {
 		command = "help"
 	}
 
+	var stdoutWriter, stderrWriter io.Writer = os.Stdout, os.Stderr
+	fatal := exitLogger{Logger: stderr}
+	switch global.LogFormat {
+	case "text":
+	case "json":
+		jsonStdout := jsonlog.NewWriter(os.Stdout, "info", "stdout")
+		jsonStderr := jsonlog.NewWriter(os.Stderr, "info", "stderr")
+		stdoutWriter, stderrWriter = jsonStdout, jsonStderr
+		fatal = exitLogger{
+			Logger:  log.New(jsonlog.NewWriter(os.Stderr, "error", "stderr"), "", 0),
+			writers: []*jsonlog.Writer{jsonStdout, jsonStderr},
+		}
+
+		stdout.SetOutput(stdoutWriter)
+		stderr.SetOutput(stderrWriter)
+	default:
+		stderr.Fatalf("unknown log format %q, expected text or json", global.LogFormat)
+	}
+	defer fatal.flush()
+
+	if global.OTLPEndpoint != "" {
+		headers, err := telemetry.ParseHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+		if err != nil {
+			fatal.Fatalf("could not parse OTEL_EXPORTER_OTLP_HEADERS: %s", err)
+		}
+
+		telemetry.Configure(telemetry.Config{
+			Endpoint:       global.OTLPEndpoint,
+			Headers:        headers,
+			ServiceName:    os.Getenv("OTEL_SERVICE_NAME"),
+			ServiceVersion: version,
+		})
+	}
+
 	requestTimeout := time.Duration(global.RequestTimeout) * time.Second
 	connectTimeout := time.Duration(global.ConnectTimeout) * time.Second
+	commands.SetRemoteFileTimeouts(connectTimeout, requestTimeout)
 
-	var unauthenticatedClient, authedClient, authedCookieClient, unauthenticatedProgressClient, authedProgressClient httpClient
+	var unauthenticatedClient, authedClient, unauthenticatedProgressClient, authedProgressClient httpClient
 	unauthenticatedClient = network.NewUnauthenticatedClient(global.Target, global.SkipSSLValidation, requestTimeout, connectTimeout)
-	authedClient, err = network.NewOAuthClient(global.Target, global.Username, global.Password, global.ClientID, global.ClientSecret, global.SkipSSLValidation, false, requestTimeout, connectTimeout)
-
-	if global.DecryptionPassphrase != "" {
-		authedClient = network.NewDecryptClient(authedClient, unauthenticatedClient, global.DecryptionPassphrase, os.Stderr)
+	var tokenCache *network.TokenCache
+	if !global.NoCache {
+		tokenCacheDirectory, err := network.DefaultTokenCacheDirectory()
+		if err == nil {
+			tokenCache = network.NewTokenCache(tokenCacheDirectory)
+		}
 	}
 
+	oauthClient, err := network.NewOAuthClient(global.Target, global.Username, global.Password, global.ClientID, global.ClientSecret, global.SkipSSLValidation, false, requestTimeout, connectTimeout, tokenCache)
+
 	if err != nil {
-		stderr.Fatal(err)
+		fatal.Fatal(err)
 	}
-	authedCookieClient, err = network.NewOAuthClient(global.Target, global.Username, global.Password, global.ClientID, global.ClientSecret, global.SkipSSLValidation, true, requestTimeout, connectTimeout)
-	if err != nil {
-		stderr.Fatal(err)
+	authedClient = oauthClient
+
+	retryDelay := time.Duration(global.RetryDelay) * time.Second
+	unauthenticatedClient = network.NewRetryClient(unauthenticatedClient, global.Retries, retryDelay, stderrWriter)
+	authedClient = network.NewRetryClient(authedClient, global.Retries, retryDelay, stderrWriter)
+
+	if global.DecryptionPassphrase != "" {
+		authedClient = network.NewDecryptClient(authedClient, unauthenticatedClient, global.DecryptionPassphrase, stderrWriter)
 	}
 
 	liveWriter := uilive.New()
-	liveWriter.Out = os.Stderr
-	unauthenticatedProgressClient = network.NewProgressClient(unauthenticatedClient, progress.NewBar(), liveWriter)
-	authedProgressClient = network.NewProgressClient(authedClient, progress.NewBar(), liveWriter)
-
-	if global.Trace {
-		unauthenticatedClient = network.NewTraceClient(unauthenticatedClient, os.Stderr)
-		unauthenticatedProgressClient = network.NewTraceClient(unauthenticatedProgressClient, os.Stderr)
-		authedClient = network.NewTraceClient(authedClient, os.Stderr)
-		authedCookieClient = network.NewTraceClient(authedCookieClient, os.Stderr)
-		authedProgressClient = network.NewTraceClient(authedProgressClient, os.Stderr)
+	liveWriter.Out = stderrWriter
+	unauthenticatedProgressBar := progress.NewBar()
+	unauthenticatedProgressBar.SetOutput(stderrWriter)
+	authedProgressBar := progress.NewBar()
+	authedProgressBar.SetOutput(stderrWriter)
+	unauthenticatedProgressClient = network.NewProgressClient(unauthenticatedClient, unauthenticatedProgressBar, liveWriter)
+	authedProgressClient = network.NewProgressClient(authedClient, authedProgressBar, liveWriter)
+
+	if global.Trace || global.TraceFile != "" {
+		var traceWriter io.Writer = stderrWriter
+		if global.TraceFile != "" {
+			traceFile, err := os.OpenFile(global.TraceFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				fatal.Fatalf("could not open the trace file: %s", err)
+			}
+			defer traceFile.Close()
+
+			traceWriter = traceFile
+		}
+
+		unauthenticatedClient = network.NewTraceClient(unauthenticatedClient, traceWriter, global.TraceBodies)
+		unauthenticatedProgressClient = network.NewTraceClient(unauthenticatedProgressClient, traceWriter, global.TraceBodies)
+		authedClient = network.NewTraceClient(authedClient, traceWriter, global.TraceBodies)
+		authedProgressClient = network.NewTraceClient(authedProgressClient, traceWriter, global.TraceBodies)
 	}
 
 	api := api.New(api.ApiInput{
@@ -129,11 +207,8 @@ func main() {
 		UnauthedProgressClient: unauthenticatedProgressClient,
 		Logger:                 stderr,
 	})
-	ui := ui.New(ui.UiInput{
-		Client: authedCookieClient,
-	})
-	logWriter := commands.NewLogWriter(os.Stdout)
-	tableWriter := tablewriter.NewWriter(os.Stdout)
+	logWriter := commands.NewLogWriter(stdoutWriter)
+	tableWriter := tablewriter.NewWriter(stdoutWriter)
 	pivnetLogWriter := logshim.NewLogShim(stderr, stderr, global.Trace)
 
 	form := formcontent.NewForm()
@@ -143,23 +218,37 @@ func main() {
 	pivnetFactory := commands.DefaultPivnetFactory
 	stower := commands.DefaultStow{}
 
-	presenter := presenters.NewPresenter(presenters.NewTablePresenter(tableWriter), presenters.NewJSONPresenter(os.Stdout))
+	presenter := presenters.NewPresenter(presenters.NewTablePresenter(tableWriter), presenters.NewJSONPresenter(stdoutWriter))
 	envRendererFactory := renderers.NewFactory(renderers.NewEnvGetter())
 
 	commandSet := jhanda.CommandSet{}
 	commandSet["activate-certificate-authority"] = commands.NewActivateCertificateAuthority(api, stdout)
 	commandSet["apply-changes"] = commands.NewApplyChanges(api, api, logWriter, stdout, applySleepDuration)
+	commandSet["apply-environment"] = commands.NewApplyEnvironment(os.Environ,
+		commands.NewConfigureDirector(os.Environ, api, stdout),
+		commands.NewConfigureProduct(os.Environ, api, metadataExtractor, global.Target, stdout),
+		commands.NewCompareConfig(os.Environ, api, stdout),
+		commands.NewAssignStemcell(api, stdout),
+		commands.NewApplyChanges(api, api, logWriter, stdout, applySleepDuration),
+		stdout)
+	commandSet["assign-multi-stemcell"] = commands.NewAssignMultiStemcell(api, stdout)
 	commandSet["assign-stemcell"] = commands.NewAssignStemcell(api, stdout)
 	commandSet["available-products"] = commands.NewAvailableProducts(api, presenter, stdout)
+	commandSet["blobstore"] = commands.NewBlobstore(os.Environ, stower, stderrWriter, stdout)
 	commandSet["bosh-env"] = commands.NewBoshEnvironment(api, stdout, global.Target, envRendererFactory)
 	commandSet["certificate-authorities"] = commands.NewCertificateAuthorities(api, presenter)
 	commandSet["certificate-authority"] = commands.NewCertificateAuthority(api, presenter, stdout)
-	commandSet["config-template"] = commands.NewConfigTemplate(metadataExtractor, stdout)
+	commandSet["certificate-rotations"] = commands.NewCertificateRotations(api, presenter)
+	commandSet["compare-config"] = commands.NewCompareConfig(os.Environ, api, stdout)
+	commandSet["config-template"] = commands.NewConfigTemplate(metadataExtractor, pivnetLogWriter, stderrWriter, pivnetFactory, stdout)
 	commandSet["configure-authentication"] = commands.NewConfigureAuthentication(api, stdout)
 	commandSet["configure-director"] = commands.NewConfigureDirector(os.Environ, api, stdout)
 	commandSet["configure-ldap-authentication"] = commands.NewConfigureLDAPAuthentication(api, stdout)
-	commandSet["configure-product"] = commands.NewConfigureProduct(os.Environ, api, global.Target, stdout)
+	commandSet["configure-opsman"] = commands.NewConfigureOpsman(os.Environ, api, stdout)
+	commandSet["configure-product"] = commands.NewConfigureProduct(os.Environ, api, metadataExtractor, global.Target, stdout)
+	commandSet["configure-resource-config"] = commands.NewConfigureResourceConfig(os.Environ, api, stdout)
 	commandSet["configure-saml-authentication"] = commands.NewConfigureSAMLAuthentication(api, stdout)
+	commandSet["configure-syslog"] = commands.NewConfigureSyslog(os.Environ, api, stdout)
 	commandSet["create-certificate-authority"] = commands.NewCreateCertificateAuthority(api, presenter)
 	commandSet["create-vm-extension"] = commands.NewCreateVMExtension(os.Environ, api, stdout)
 	commandSet["credential-references"] = commands.NewCredentialReferences(api, presenter, stdout)
@@ -170,41 +259,186 @@ func main() {
 	commandSet["delete-ssl-certificate"] = commands.NewDeleteSSLCertificate(api, stdout)
 	commandSet["delete-product"] = commands.NewDeleteProduct(api)
 	commandSet["delete-unused-products"] = commands.NewDeleteUnusedProducts(api, stdout)
+	commandSet["delete-vm-extension"] = commands.NewDeleteVMExtension(os.Environ, api, stdout)
 	commandSet["deployed-manifest"] = commands.NewDeployedManifest(api, stdout)
 	commandSet["deployed-products"] = commands.NewDeployedProducts(presenter, api)
-	commandSet["download-product"] = commands.NewDownloadProduct(os.Environ, pivnetLogWriter, os.Stdout, pivnetFactory, stower)
+	commandSet["diff"] = commands.NewDiff(api, stdout)
+	commandSet["director-verifiers"] = commands.NewDirectorVerifiers(api, presenter)
+	commandSet["disable-director-verifiers"] = commands.NewDisableDirectorVerifiers(api, stdout)
+	commandSet["disable-product-verifiers"] = commands.NewDisableProductVerifiers(api, presenter, stdout)
+	commandSet["download-product"] = commands.NewDownloadProduct(os.Environ, pivnetLogWriter, stdoutWriter, os.Stdout, pivnetFactory, stower, form, api)
 	commandSet["errands"] = commands.NewErrands(presenter, api)
+	commandSet["expiring-certificates"] = commands.NewExpiringCertificates(api, presenter, stdout)
+	commandSet["expiring-licenses"] = commands.NewExpiringLicenses(api, presenter, stdout)
 	commandSet["export-installation"] = commands.NewExportInstallation(api, stderr)
 	commandSet["generate-certificate"] = commands.NewGenerateCertificate(api, stdout)
 	commandSet["generate-certificate-authority"] = commands.NewGenerateCertificateAuthority(api, presenter)
-	commandSet["help"] = commands.NewHelp(os.Stdout, globalFlagsUsage, commandSet)
+	commandSet["help"] = commands.NewHelp(stdoutWriter, globalFlagsUsage, commandSet)
 	commandSet["import-installation"] = commands.NewImportInstallation(form, api, global.DecryptionPassphrase, stdout)
 	commandSet["installation-log"] = commands.NewInstallationLog(api, stdout)
 	commandSet["installations"] = commands.NewInstallations(api, presenter)
 	commandSet["interpolate"] = commands.NewInterpolate(os.Environ, stdout)
 	commandSet["pending-changes"] = commands.NewPendingChanges(presenter, api)
+	commandSet["pre-deploy-check"] = commands.NewPreDeployCheck(api, stdout)
+	commandSet["product-metadata"] = commands.NewProductMetadata(metadataExtractor, stdout)
+	commandSet["products"] = commands.NewProducts(presenter, api)
 	commandSet["regenerate-certificates"] = commands.NewRegenerateCertificates(api, stdout)
-	commandSet["revert-staged-changes"] = commands.NewRevertStagedChanges(ui, stdout)
+	commandSet["resource-config"] = commands.NewResourceConfig(api, presenter)
+	commandSet["revert-staged-changes"] = commands.NewRevertStagedChanges(api, stdout)
+	commandSet["rotate-certificate-authority"] = commands.NewRotateCertificateAuthority(api, presenter, stderr)
+	commandSet["rotate-director-certificates"] = commands.NewRotateDirectorCertificates(api, presenter, stderr)
 	commandSet["stage-product"] = commands.NewStageProduct(api, stdout)
+	commandSet["ssh"] = commands.NewSSH(api, runner.New(os.Stdin, os.Stdout, os.Stderr), global.Target)
 	commandSet["ssl-certificate"] = commands.NewSSLCertificate(api, presenter)
 	commandSet["staged-config"] = commands.NewStagedConfig(api, stdout)
 	commandSet["staged-director-config"] = commands.NewStagedDirectorConfig(api, stdout)
 	commandSet["staged-manifest"] = commands.NewStagedManifest(api, stdout)
 	commandSet["staged-products"] = commands.NewStagedProducts(presenter, api)
+	commandSet["stemcell-assignments"] = commands.NewStemcellAssignments(presenter, api)
 	commandSet["tile-metadata"] = commands.NewTileMetadata(stdout)
 	commandSet["unstage-product"] = commands.NewUnstageProduct(api, stdout)
 	commandSet["update-ssl-certificate"] = commands.NewUpdateSSLCertificate(api, stdout)
+	commandSet["upgrade-opsman"] = commands.NewUpgradeOpsman(form, api, global.DecryptionPassphrase, stdout)
 	commandSet["upload-product"] = commands.NewUploadProduct(form, metadataExtractor, api, stdout)
 	commandSet["upload-stemcell"] = commands.NewUploadStemcell(form, api, stdout)
-	commandSet["version"] = commands.NewVersion(version, os.Stdout)
+	commandSet["validate-config"] = commands.NewValidateConfig(os.Environ, api, stdout)
+	commandSet["version"] = commands.NewVersion(version, stdoutWriter)
+	commandSet["vm-extensions"] = commands.NewVMExtensions(api, presenter, stdout)
+	commandSet["vm-lifecycle"] = commands.NewVMLifecycle(os.Environ, func(config vmlifecycle.Config) (vmlifecycle.VMManager, string, error) {
+		return vmlifecycle.New(config, runner.New(os.Stdin, os.Stderr, os.Stderr))
+	}, stdout)
+
+	if _, ok := commandSet[command]; !ok {
+		if path, ok := commands.FindPlugin(command, global.PluginsDir); ok {
+			plugin := commands.NewPlugin(runner.New(os.Stdin, os.Stdout, os.Stderr), path, func() ([]string, error) {
+				return pluginEnvironment(global, oauthClient)
+			})
+
+			err = plugin.Execute(args)
+			if err != nil {
+				// the plugin has already reported why it failed
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					fatal.exit(exitErr.ExitCode())
+				}
+				fatal.Fatal(err)
+			}
+			return
+		}
+	}
 
+	span := telemetry.StartRoot(command, telemetry.Attributes{"om.command": command})
 	err = commandSet.Execute(command, args)
+	span.End(err)
+
+	if flushErr := telemetry.Flush(); flushErr != nil {
+		stderr.Printf("warning: %s", flushErr)
+	}
+
 	if err != nil {
-		stderr.Fatal(err)
+		// jhanda flattens the error of the command into a string
+		if strings.HasSuffix(err.Error(), commands.ErrInstallationTimedOut.Error()) {
+			fatal.Println(err)
+			fatal.exit(applyChangesTimeoutExitCode)
+		}
+		fatal.Fatal(err)
+	}
+}
+
+// exitLogger reports the errors om exits with. The json writers hold the
+// last line of the output until they are flushed, which has to happen before
+// om exits, as deferred calls are not run by os.Exit.
+type exitLogger struct {
+	*log.Logger
+	writers []*jsonlog.Writer
+}
+
+func (l exitLogger) Fatal(v ...interface{}) {
+	l.flush()
+	_ = l.Output(2, fmt.Sprint(v...))
+	os.Exit(1)
+}
+
+func (l exitLogger) Fatalf(format string, v ...interface{}) {
+	l.flush()
+	_ = l.Output(2, fmt.Sprintf(format, v...))
+	os.Exit(1)
+}
+
+func (l exitLogger) exit(code int) {
+	l.flush()
+	os.Exit(code)
+}
+
+func (l exitLogger) flush() {
+	for _, writer := range l.writers {
+		_ = writer.Flush()
+	}
+}
+
+// pluginEnvironment is the environment the plugins are run with: the target
+// of om, the path of om for the plugins to run its commands, and a token of
+// the UAA of Ops Manager when om has credentials to get one. The credentials
+// themselves are only given with --plugins-credentials; otherwise the ones of
+// the environment of om are cleared, as plugins are third-party executables.
+func pluginEnvironment(global options, client interface{ AccessToken() (string, error) }) ([]string, error) {
+	env := []string{
+		fmt.Sprintf("OM_SKIP_SSL_VALIDATION=%t", global.SkipSSLValidation),
+	}
+
+	if global.Target != "" {
+		env = append(env, "OM_TARGET="+global.Target)
+	}
+
+	credentials := map[string]string{
+		"OM_USERNAME":              global.Username,
+		"OM_PASSWORD":              global.Password,
+		"OM_CLIENT_ID":             global.ClientID,
+		"OM_CLIENT_SECRET":         global.ClientSecret,
+		"OM_DECRYPTION_PASSPHRASE": global.DecryptionPassphrase,
+		// the env file has the credentials too
+		"OM_ENV": global.Env,
+	}
+	for name, value := range credentials {
+		if !global.PluginsCredentials {
+			env = append(env, name+"=")
+		} else if value != "" {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		env = append(env, "OM_BIN="+executable)
+	}
+
+	if global.Target != "" && (global.Username != "" || global.ClientID != "") {
+		token, err := client.AccessToken()
+		if err != nil {
+			return nil, fmt.Errorf("could not authenticate with Ops Manager: %s", err)
+		}
+		env = append(env, "OM_ACCESS_TOKEN="+token)
 	}
+
+	sort.Strings(env)
+
+	return env, nil
 }
 
-func setEnvFileProperties(global *options) error {
+// envFileKeys are the keys of the options that can be set in an env file.
+var envFileKeys = func() map[string]bool {
+	keys := map[string]bool{}
+
+	optionsType := reflect.TypeOf(options{})
+	for i := 0; i < optionsType.NumField(); i++ {
+		if key := optionsType.Field(i).Tag.Get("yaml"); key != "" {
+			keys[key] = true
+		}
+	}
+
+	return keys
+}()
+
+func setEnvFileProperties(global *options, stderr *log.Logger) error {
 	if global.Env == "" {
 		return nil
 	}
@@ -225,6 +459,23 @@ func setEnvFileProperties(global *options) error {
 		return fmt.Errorf("could not parse env file: %s", err)
 	}
 
+	var keys map[string]interface{}
+	err = yaml.Unmarshal(contents, &keys)
+	if err != nil {
+		return fmt.Errorf("could not parse env file: %s", err) // un-tested
+	}
+
+	var unrecognizedKeys []string
+	for key := range keys {
+		if !envFileKeys[key] {
+			unrecognizedKeys = append(unrecognizedKeys, key)
+		}
+	}
+	if len(unrecognizedKeys) > 0 {
+		sort.Strings(unrecognizedKeys)
+		stderr.Printf("warning: env file contains unrecognized keys, which are ignored: \"%s\"", strings.Join(unrecognizedKeys, "\", \""))
+	}
+
 	if global.ClientID == "" {
 		global.ClientID = opts.ClientID
 	}
@@ -240,6 +491,12 @@ func setEnvFileProperties(global *options) error {
 	if global.RequestTimeout == 1800 && opts.RequestTimeout != 0 {
 		global.RequestTimeout = opts.RequestTimeout
 	}
+	if global.Retries == 3 && opts.Retries != 0 {
+		global.Retries = opts.Retries
+	}
+	if global.RetryDelay == 1 && opts.RetryDelay != 0 {
+		global.RetryDelay = opts.RetryDelay
+	}
 	if global.SkipSSLValidation == false {
 		global.SkipSSLValidation = opts.SkipSSLValidation
 	}
@@ -249,12 +506,33 @@ func setEnvFileProperties(global *options) error {
 	if global.Trace == false {
 		global.Trace = opts.Trace
 	}
+	if global.LogFormat == "text" && opts.LogFormat != "" {
+		global.LogFormat = opts.LogFormat
+	}
+	if global.TraceBodies == false {
+		global.TraceBodies = opts.TraceBodies
+	}
+	if global.TraceFile == "" {
+		global.TraceFile = opts.TraceFile
+	}
 	if global.Username == "" {
 		global.Username = opts.Username
 	}
 	if global.DecryptionPassphrase == "" {
 		global.DecryptionPassphrase = opts.DecryptionPassphrase
 	}
+	if global.NoCache == false {
+		global.NoCache = opts.NoCache
+	}
+	if global.PluginsDir == "" {
+		global.PluginsDir = opts.PluginsDir
+	}
+	if global.PluginsCredentials == false {
+		global.PluginsCredentials = opts.PluginsCredentials
+	}
+	if global.OTLPEndpoint == "" {
+		global.OTLPEndpoint = opts.OTLPEndpoint
+	}
 
 	return nil
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestOm(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "main")
+}
+
+var _ = Describe("setEnvFileProperties", func() {
+	It("sets every option of the env file that is not given otherwise", func() {
+		var global options
+		envFile := map[string]interface{}{}
+
+		globalValue := reflect.ValueOf(&global).Elem()
+		optionsType := globalValue.Type()
+		for i := 0; i < optionsType.NumField(); i++ {
+			field := optionsType.Field(i)
+			key := field.Tag.Get("yaml")
+			if key == "" {
+				continue
+			}
+
+			// the option has its default, as when it is not given, and the
+			// env file another value
+			def := field.Tag.Get("default")
+			switch field.Type.Kind() {
+			case reflect.String:
+				globalValue.Field(i).SetString(def)
+				envFile[key] = "some-" + key
+			case reflect.Int:
+				n, _ := strconv.Atoi(def)
+				globalValue.Field(i).SetInt(int64(n))
+				envFile[key] = n + 42
+			case reflect.Bool:
+				envFile[key] = true
+			default:
+				Fail(fmt.Sprintf("the option %s of type %s is not covered", key, field.Type))
+			}
+		}
+
+		contents, err := yaml.Marshal(envFile)
+		Expect(err).NotTo(HaveOccurred())
+
+		file, err := ioutil.TempFile("", "env.yml")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(file.Name())
+
+		_, err = file.Write(contents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Close()).To(Succeed())
+
+		global.Env = file.Name()
+		Expect(setEnvFileProperties(&global, log.New(GinkgoWriter, "", 0))).To(Succeed())
+
+		for i := 0; i < optionsType.NumField(); i++ {
+			key := optionsType.Field(i).Tag.Get("yaml")
+			if key == "" {
+				continue
+			}
+
+			Expect(globalValue.Field(i).Interface()).To(BeEquivalentTo(envFile[key]), "the %s of the env file is not set", key)
+		}
+	})
+})
@@ -15,8 +15,32 @@ type Product struct {
 	Version string `json:"version"`
 }
 
+type ProductVersions struct {
+	Name      string   `json:"name"`
+	Available []string `json:"available"`
+	Staged    string   `json:"staged"`
+	Deployed  string   `json:"deployed"`
+}
+
+type StemcellAssignment struct {
+	ProductName                string   `json:"product"`
+	RequiredStemcellOS         string   `json:"required_stemcell_os,omitempty"`
+	RequiredStemcellVersion    string   `json:"required_stemcell_version"`
+	StagedStemcellVersion      string   `json:"staged_stemcell_version"`
+	AvailableStemcellVersions  []string `json:"available_stemcell_versions"`
+	CompatibleStemcellUploaded bool     `json:"compatible_stemcell_uploaded"`
+}
+
 type Errand struct {
 	Name              string `json:"name"`
 	PostDeployEnabled string `json:"post_deploy_enabled,omitempty"`
 	PreDeleteEnabled  string `json:"pre_delete_enabled,omitempty"`
 }
+
+type ExpiringLicense struct {
+	ProductName       string    `json:"product_name"`
+	GUID              string    `json:"guid"`
+	ProductState      []string  `json:"product_state"`
+	PropertyReference string    `json:"property_reference"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
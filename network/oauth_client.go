@@ -3,6 +3,7 @@ package network
 import (
 	"context"
 	"crypto/tls"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net"
@@ -10,6 +11,8 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"os"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,9 +31,22 @@ type OAuthClient struct {
 	password      string
 	target        string
 	timeout       time.Duration
+	tokens        *tokenState
+	cache         *TokenCache
 }
 
-func NewOAuthClient(target, username, password string, clientID, clientSecret string, insecureSkipVerify bool, includeCookies bool, requestTimeout time.Duration, connectTimeout time.Duration) (OAuthClient, error) {
+// tokenState is shared by the copies of an OAuthClient, so that a token is
+// only retrieved once and then reused, or refreshed, until the command exits.
+type tokenState struct {
+	mutex     sync.Mutex
+	source    oauth2.TokenSource
+	fromCache bool
+}
+
+// NewOAuthClient authenticates with the client credentials, or else with the
+// username and password. When tokenCache is not nil, the token is read from
+// it, and stored in it, to be reused by the next invocations of om.
+func NewOAuthClient(target, username, password string, clientID, clientSecret string, insecureSkipVerify bool, includeCookies bool, requestTimeout time.Duration, connectTimeout time.Duration, tokenCache *TokenCache) (OAuthClient, error) {
 	conf := &oauth2.Config{
 		ClientID:     "opsman",
 		ClientSecret: "",
@@ -76,12 +92,65 @@ func NewOAuthClient(target, username, password string, clientID, clientSecret st
 		password:      password,
 		target:        target,
 		timeout:       requestTimeout,
+		tokens:        &tokenState{},
+		cache:         tokenCache,
 	}, nil
 }
 
 func (oc OAuthClient) Do(request *http.Request) (*http.Response, error) {
-	var client *http.Client
+	targetURL, err := oc.targetURL()
+	if err != nil {
+		return nil, err
+	}
+
+	request.URL.Scheme = targetURL.Scheme
+	request.URL.Host = targetURL.Host
+
+	resp, err := oc.do(request)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !oc.discardCachedToken() {
+		return resp, err
+	}
+
+	// the cached token has been rejected, e.g. it has been revoked,
+	// so the request is sent again with a new token
+	if request.Body != nil {
+		if request.GetBody == nil {
+			return resp, nil // un-tested
+		}
+
+		request.Body, err = request.GetBody()
+		if err != nil {
+			return nil, err // un-tested
+		}
+	}
+	resp.Body.Close()
 
+	return oc.do(request)
+}
+
+// AccessToken returns the token that the requests are authenticated with,
+// for the processes started by om to make requests of their own, e.g. plugins.
+func (oc OAuthClient) AccessToken() (string, error) {
+	if _, err := oc.targetURL(); err != nil {
+		return "", err
+	}
+
+	source, err := oc.tokenSource()
+	if err != nil {
+		return "", err
+	}
+
+	token, err := source.Token()
+	if err != nil {
+		return "", err
+	}
+
+	return token.AccessToken, nil
+}
+
+// targetURL is the URL of the Ops Manager, the UAA of which the tokens are
+// retrieved from.
+func (oc OAuthClient) targetURL() (*url.URL, error) {
 	if oc.target == "" {
 		return nil, fmt.Errorf("target flag is required. Run `om help` for more info.")
 	}
@@ -102,39 +171,184 @@ func (oc OAuthClient) Do(request *http.Request) (*http.Response, error) {
 		return nil, fmt.Errorf("could not parse target url: %s", err)
 	}
 
-	targetURL.Path = "/uaa/oauth/token"
-	oc.oauthConfigCC.TokenURL = targetURL.String()
-	oc.oauthConfig.Endpoint.TokenURL = targetURL.String()
+	tokenURL := *targetURL
+	tokenURL.Path = "/uaa/oauth/token"
+	oc.oauthConfigCC.TokenURL = tokenURL.String()
+	oc.oauthConfig.Endpoint.TokenURL = tokenURL.String()
+
+	return targetURL, nil
+}
+
+func (oc OAuthClient) do(request *http.Request) (*http.Response, error) {
+	source, err := oc.tokenSource()
+	if err != nil {
+		return nil, err
+	}
+
+	client := oauth2.NewClient(oc.context, source)
+
+	client.Timeout = oc.timeout
+
+	if oc.jar != nil {
+		client.Jar = oc.jar
+	}
+
+	return client.Do(request)
+}
+
+// tokenSource retrieves a token with the client credentials, or else with the
+// username and password, the first time it is called. The source it returns
+// reuses the token until it expires, and then retrieves a new one with the
+// client credentials or the refresh token, so that commands that poll Ops
+// Manager for longer than a token is valid, like apply-changes, keep working.
+func (oc OAuthClient) tokenSource() (oauth2.TokenSource, error) {
+	oc.tokens.mutex.Lock()
+	defer oc.tokens.mutex.Unlock()
+
+	if oc.tokens.source != nil {
+		return oc.tokens.source, nil
+	}
+
+	source, token, err := oc.cachedTokenSource()
+	if err == nil {
+		oc.tokens.source = oc.cachingTokenSource(source, token)
+		oc.tokens.fromCache = true
+
+		return oc.tokens.source, nil
+	}
 
 	if oc.oauthConfigCC.ClientID != "" {
-		client = oc.oauthConfigCC.Client(oc.context)
+		source = oc.oauthConfigCC.TokenSource(oc.context)
+
+		token, err = retrieveTokenWithRetry(source.Token)
+		if err != nil {
+			return nil, err
+		}
 	} else {
-		token, err := retrieveTokenWithRetry(oc.oauthConfig, oc.context, oc.username, oc.password)
+		token, err = retrieveTokenWithRetry(func() (*oauth2.Token, error) {
+			return oc.oauthConfig.PasswordCredentialsToken(oc.context, oc.username, oc.password)
+		})
 		if err != nil {
 			return nil, err
 		}
 
-		client = oc.oauthConfig.Client(oc.context, token)
+		source = oc.oauthConfig.TokenSource(oc.context, token)
 	}
 
-	client.Timeout = oc.timeout
+	oc.tokens.source = oc.cachingTokenSource(source, nil)
+	oc.tokens.fromCache = false
 
-	if oc.jar != nil {
-		client.Jar = oc.jar
+	return oc.tokens.source, nil
+}
+
+// cachedTokenSource returns a source of the token stored in the cache by a
+// previous invocation of om. The token is refreshed if it has expired.
+func (oc OAuthClient) cachedTokenSource() (oauth2.TokenSource, *oauth2.Token, error) {
+	if oc.cache == nil {
+		return nil, nil, errors.New("the token cache is disabled")
 	}
 
-	request.URL.Scheme = targetURL.Scheme
-	request.URL.Host = targetURL.Host
+	identity, secret := oc.credentials()
+	token, found := oc.cache.Load(oc.target, identity, secret)
+	if !found {
+		return nil, nil, errors.New("the token is not cached")
+	}
 
-	// we only want to retry non-modifying actions
-	if request.Method == "GET" {
-		return httpResponseWithRetry(client, request)
+	var source oauth2.TokenSource
+	if oc.oauthConfigCC.ClientID != "" {
+		source = oauth2.ReuseTokenSource(token, oc.oauthConfigCC.TokenSource(oc.context))
+	} else {
+		source = oc.oauthConfig.TokenSource(oc.context, token)
 	}
 
-	return client.Do(request)
+	token, err := source.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return source, token, nil
+}
+
+// cachingTokenSource stores every new token of the source in the cache.
+// The token that is already in the cache is not stored again.
+func (oc OAuthClient) cachingTokenSource(source oauth2.TokenSource, cached *oauth2.Token) oauth2.TokenSource {
+	if oc.cache == nil {
+		return source
+	}
+
+	caching := &cachingTokenSource{source: source}
+	if cached != nil {
+		caching.lastAccessToken = cached.AccessToken
+	}
+
+	identity, secret := oc.credentials()
+	caching.save = func(token *oauth2.Token) {
+		err := oc.cache.Save(oc.target, identity, secret, token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not cache the token: %s\n", err)
+		}
+	}
+
+	return caching
+}
+
+// discardCachedToken removes the token that was read from the cache, so that
+// a new one is retrieved. It returns false if the token was not from the cache.
+func (oc OAuthClient) discardCachedToken() bool {
+	oc.tokens.mutex.Lock()
+	defer oc.tokens.mutex.Unlock()
+
+	if !oc.tokens.fromCache {
+		return false
+	}
+
+	oc.tokens.source = nil
+	oc.tokens.fromCache = false
+
+	identity, _ := oc.credentials()
+	err := oc.cache.Delete(oc.target, identity)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not remove the cached token: %s\n", err) // un-tested
+	}
+
+	return true
+}
+
+// credentials returns who the token is for, and the secret that its entry of
+// the cache is encrypted with.
+func (oc OAuthClient) credentials() (string, string) {
+	if oc.oauthConfigCC.ClientID != "" {
+		return "client:" + oc.oauthConfigCC.ClientID, oc.oauthConfigCC.ClientSecret
+	}
+
+	return "user:" + oc.username, oc.password
+}
+
+type cachingTokenSource struct {
+	mutex           sync.Mutex
+	source          oauth2.TokenSource
+	save            func(*oauth2.Token)
+	lastAccessToken string
 }
 
-func retrieveTokenWithRetry(config *oauth2.Config, ctx context.Context, username, password string) (*oauth2.Token, error) {
+func (c *cachingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := c.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if token.AccessToken != c.lastAccessToken {
+		c.lastAccessToken = token.AccessToken
+		c.save(token)
+	}
+
+	return token, nil
+}
+
+func retrieveTokenWithRetry(retrieveToken func() (*oauth2.Token, error)) (*oauth2.Token, error) {
 	var token *oauth2.Token
 	var err error
 
@@ -142,7 +356,7 @@ func retrieveTokenWithRetry(config *oauth2.Config, ctx context.Context, username
 		if i != 0 {
 			fmt.Fprintf(os.Stderr, "\nRetrying, attempt %d out of %d...\n", i+1, TOKEN_ATTEMPT_COUNT)
 		}
-		token, err = config.PasswordCredentialsToken(ctx, username, password)
+		token, err = retrieveToken()
 		if !CanRetry(err) {
 			break
 		}
@@ -160,22 +374,6 @@ func retrieveTokenWithRetry(config *oauth2.Config, ctx context.Context, username
 	return token, nil
 }
 
-func httpResponseWithRetry(client *http.Client, request *http.Request) (*http.Response, error) {
-retry:
-	resp, err := client.Do(request)
-	if client.Timeout == 0 {
-		if CanRetry(err) {
-			goto retry
-		}
-	}
-
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
 func CanRetry(err error) bool {
 	if err != nil {
 		err = errors.Cause(err)
@@ -187,11 +385,25 @@ func CanRetry(err error) bool {
 			err = ue.Err
 		}
 
-		if err == io.EOF {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
 			return true
 		}
 
-		return false
+		if oe, ok := err.(*net.OpError); ok {
+			err = oe.Err
+		}
+
+		if se, ok := err.(*os.SyscallError); ok {
+			err = se.Err
+		}
+
+		if err == syscall.ECONNRESET || err == syscall.EPIPE {
+			return true
+		}
+
+		// TLS connections wrap the errors of the underlying connection, which
+		// is closed when the server drops it while the request is being written
+		return stderrors.Is(err, syscall.ECONNRESET) || stderrors.Is(err, syscall.EPIPE) || stderrors.Is(err, net.ErrClosed)
 	}
 
 	return false
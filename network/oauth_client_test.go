@@ -3,13 +3,20 @@ package network_test
 import (
 	"bufio"
 	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"os"
 	"strings"
+	"syscall"
 
 	"github.com/pivotal-cf/om/network"
+	"github.com/pkg/errors"
 
 	"time"
 
@@ -61,7 +68,7 @@ var _ = Describe("OAuthClient", func() {
 
 	Describe("Do", func() {
 		It("makes a request with authentication", func() {
-			client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+			client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(callCount).To(Equal(0))
@@ -96,7 +103,7 @@ var _ = Describe("OAuthClient", func() {
 		})
 
 		It("makes a request with client credentials", func() {
-			client, err := network.NewOAuthClient(server.URL, "", "", "client_id", "client_secret", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+			client, err := network.NewOAuthClient(server.URL, "", "", "client_id", "client_secret", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(callCount).To(Equal(0))
@@ -128,6 +135,120 @@ var _ = Describe("OAuthClient", func() {
 			}))
 		})
 
+		It("reuses the token for the requests that follow", func() {
+			client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 3; i++ {
+				req, err := http.NewRequest("GET", "/some/path", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = client.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(callCount).To(Equal(1))
+			Expect(authHeader).To(Equal("Bearer some-opsman-token"))
+		})
+
+		It("reuses the token of the client credentials for the requests that follow", func() {
+			client, err := network.NewOAuthClient(server.URL, "", "", "client_id", "client_secret", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 3; i++ {
+				req, err := http.NewRequest("POST", "/some/path", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = client.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(callCount).To(Equal(1))
+			Expect(authHeader).To(Equal("Bearer some-opsman-token"))
+		})
+
+		Context("when the token expires", func() {
+			BeforeEach(func() {
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					switch req.URL.Path {
+					case "/uaa/oauth/token":
+						callCount++
+						w.Header().Set("Content-Type", "application/json")
+						_, err := w.Write([]byte(fmt.Sprintf(`{
+							"access_token": "some-opsman-token-%d",
+							"token_type": "bearer",
+							"expires_in": 1
+						}`, callCount)))
+						Expect(err).ToNot(HaveOccurred())
+					default:
+						authHeader = req.Header.Get("Authorization")
+						w.WriteHeader(http.StatusNoContent)
+					}
+				})
+			})
+
+			It("retrieves a new token with the client credentials", func() {
+				client, err := network.NewOAuthClient(server.URL, "", "", "client_id", "client_secret", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				for i := 0; i < 2; i++ {
+					req, err := http.NewRequest("GET", "/some/path", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = client.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				Expect(callCount).To(BeNumerically(">=", 2))
+				Expect(authHeader).To(Equal(fmt.Sprintf("Bearer some-opsman-token-%d", callCount)))
+			})
+		})
+
+		Context("when a token cache is provided", func() {
+			var cacheDir string
+
+			BeforeEach(func() {
+				var err error
+				cacheDir, err = ioutil.TempDir("", "")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(cacheDir)
+			})
+
+			It("reuses the token of a previous client", func() {
+				for i := 0; i < 2; i++ {
+					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, network.NewTokenCache(cacheDir))
+					Expect(err).NotTo(HaveOccurred())
+
+					req, err := http.NewRequest("GET", "/some/path", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = client.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				Expect(callCount).To(Equal(1))
+				Expect(authHeader).To(Equal("Bearer some-opsman-token"))
+			})
+
+			It("does not reuse the token of other client credentials", func() {
+				for _, clientID := range []string{"client_id", "other_client_id"} {
+					client, err := network.NewOAuthClient(server.URL, "", "", clientID, "client_secret", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, network.NewTokenCache(cacheDir))
+					Expect(err).NotTo(HaveOccurred())
+
+					req, err := http.NewRequest("GET", "/some/path", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = client.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				Expect(callCount).To(Equal(2))
+			})
+		})
+
 		Context("when passing a url with no scheme", func() {
 			It("defaults to HTTPS", func() {
 				noScheme, err := url.Parse(server.URL)
@@ -136,7 +257,7 @@ var _ = Describe("OAuthClient", func() {
 				noScheme.Scheme = ""
 				finalURL := noScheme.String()
 
-				client, err := network.NewOAuthClient(finalURL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+				client, err := network.NewOAuthClient(finalURL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				req, err := http.NewRequest("GET", "/some/path", strings.NewReader("request-body"))
@@ -152,7 +273,7 @@ var _ = Describe("OAuthClient", func() {
 		Context("when insecureSkipVerify is configured", func() {
 			Context("when it is set to false", func() {
 				It("throws an error for invalid certificates", func() {
-					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", false, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", false, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 					Expect(err).NotTo(HaveOccurred())
 
 					req, err := http.NewRequest("GET", "/some/path", strings.NewReader("request-body"))
@@ -165,7 +286,7 @@ var _ = Describe("OAuthClient", func() {
 
 			Context("when it is set to true", func() {
 				It("does not verify certificates", func() {
-					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 					Expect(err).NotTo(HaveOccurred())
 
 					req, err := http.NewRequest("GET", "/some/path", strings.NewReader("request-body"))
@@ -180,7 +301,7 @@ var _ = Describe("OAuthClient", func() {
 		Context("when includeCookies is configured", func() {
 			Context("when it is set to true", func() {
 				It("has a cookie jar", func() {
-					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, true, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, true, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 					Expect(err).NotTo(HaveOccurred())
 
 					req, err := http.NewRequest("GET", "/some/path", strings.NewReader("request-body"))
@@ -202,7 +323,7 @@ var _ = Describe("OAuthClient", func() {
 
 			Context("when it is false", func() {
 				It("does not collect any of the cookies", func() {
-					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+					client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 					Expect(err).NotTo(HaveOccurred())
 
 					req, err := http.NewRequest("GET", "/some/path", strings.NewReader("request-body"))
@@ -233,7 +354,7 @@ var _ = Describe("OAuthClient", func() {
 				})
 
 				It("returns an error", func() {
-					client, err := network.NewOAuthClient(badServer.URL, "username", "password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+					client, err := network.NewOAuthClient(badServer.URL, "username", "password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 					Expect(err).NotTo(HaveOccurred())
 
 					req, err := http.NewRequest("GET", "/some/path", strings.NewReader("request-body"))
@@ -244,9 +365,30 @@ var _ = Describe("OAuthClient", func() {
 				})
 			})
 
+			Context("when the token of the client credentials cannot be retrieved", func() {
+				var badServer *httptest.Server
+
+				BeforeEach(func() {
+					badServer = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+						w.WriteHeader(http.StatusUnauthorized)
+					}))
+				})
+
+				It("returns an error", func() {
+					client, err := network.NewOAuthClient(badServer.URL, "", "", "client_id", "client_secret", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					req, err := http.NewRequest("GET", "/some/path", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = client.Do(req)
+					Expect(err).To(MatchError(ContainSubstring("token could not be retrieved from target url: oauth2: cannot fetch token: 401")))
+				})
+			})
+
 			Context("when the target url is empty", func() {
 				It("returns an error", func() {
-					client, err := network.NewOAuthClient("", "username", "password", "", "", false, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second)
+					client, err := network.NewOAuthClient("", "username", "password", "", "", false, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
 					Expect(err).NotTo(HaveOccurred())
 
 					req, err := http.NewRequest("GET", "/some/path", strings.NewReader("request-body"))
@@ -258,4 +400,49 @@ var _ = Describe("OAuthClient", func() {
 			})
 		})
 	})
+
+	Describe("AccessToken", func() {
+		It("returns the token that the requests are authenticated with", func() {
+			client, err := network.NewOAuthClient(server.URL, "opsman-username", "opsman-password", "", "", true, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			token, err := client.AccessToken()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("some-opsman-token"))
+
+			req, err := http.NewRequest("GET", "/some/path", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(authHeader).To(Equal("Bearer some-opsman-token"))
+			Expect(callCount).To(Equal(1))
+		})
+
+		It("returns an error when the target url is empty", func() {
+			client, err := network.NewOAuthClient("", "username", "password", "", "", false, false, time.Duration(30)*time.Second, time.Duration(5)*time.Second, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.AccessToken()
+			Expect(err).To(MatchError("target flag is required. Run `om help` for more info."))
+		})
+	})
+
+	Describe("CanRetry", func() {
+		It("retries when the connection was dropped mid-request", func() {
+			Expect(network.CanRetry(io.EOF)).To(BeTrue())
+			Expect(network.CanRetry(errors.Wrap(io.ErrUnexpectedEOF, "some upload error"))).To(BeTrue())
+			Expect(network.CanRetry(&url.Error{Op: "Post", URL: "/api/v0/stemcells", Err: &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)}})).To(BeTrue())
+			Expect(network.CanRetry(&url.Error{Op: "Post", URL: "/api/v0/available_products", Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}})).To(BeTrue())
+			Expect(network.CanRetry(&url.Error{Op: "Post", URL: "/api/v0/stemcells", Err: fmt.Errorf("tls: %w", &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)})})).To(BeTrue())
+			Expect(network.CanRetry(&url.Error{Op: "Post", URL: "/api/v0/available_products", Err: &net.OpError{Op: "write", Err: net.ErrClosed}})).To(BeTrue())
+		})
+
+		It("does not retry other errors", func() {
+			Expect(network.CanRetry(nil)).To(BeFalse())
+			Expect(network.CanRetry(errors.New("some error"))).To(BeFalse())
+			Expect(network.CanRetry(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)})).To(BeFalse())
+		})
+	})
 })
@@ -0,0 +1,90 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pivotal-cf/om/telemetry"
+)
+
+// retryableStatusCodes are the statuses of an Ops Manager that is restarting,
+// or of a load balancer that cannot reach it for a while.
+var retryableStatusCodes = map[int]bool{
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// RetryClient sends the requests that do not modify Ops Manager again when the
+// connection is dropped, or when Ops Manager is unavailable. The delay between
+// the attempts doubles after every attempt.
+type RetryClient struct {
+	client  httpClient
+	retries int
+	delay   time.Duration
+	writer  io.Writer
+}
+
+func NewRetryClient(client httpClient, retries int, delay time.Duration, writer io.Writer) *RetryClient {
+	return &RetryClient{
+		client:  client,
+		retries: retries,
+		delay:   delay,
+		writer:  writer,
+	}
+}
+
+func (c *RetryClient) Do(request *http.Request) (*http.Response, error) {
+	// we only want to retry non-modifying actions
+	// whose body, if any, can be sent again
+	if (request.Method != "GET" && request.Method != "HEAD") || (request.Body != nil && request.GetBody == nil) {
+		return c.client.Do(request)
+	}
+
+	delay := c.delay
+	for attempt := 1; ; attempt++ {
+		response, err := c.client.Do(request)
+
+		var reason string
+		switch {
+		case err != nil && CanRetry(err) && !isTimeout(err):
+			reason = err.Error()
+		case err == nil && retryableStatusCodes[response.StatusCode]:
+			reason = response.Status
+		default:
+			return response, err
+		}
+
+		if attempt > c.retries {
+			return response, err
+		}
+
+		if response != nil {
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		if request.Body != nil {
+			request.Body, err = request.GetBody()
+			if err != nil {
+				return nil, err // un-tested
+			}
+		}
+
+		telemetry.Count("om.http.retries", "", 1, telemetry.Attributes{"method": request.Method})
+		fmt.Fprintf(c.writer, "%s %s failed: %s\nRetrying in %s, attempt %d out of %d...\n", request.Method, request.URL.Path, reason, delay, attempt, c.retries)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isTimeout is true for the requests that exceeded the timeouts, which
+// are set by the user and so are not retried.
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
@@ -0,0 +1,170 @@
+package network_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/om/network"
+	"github.com/pivotal-cf/om/network/fakes"
+)
+
+var _ = Describe("Retry Client", func() {
+	var (
+		fakeClient  *fakes.HttpClient
+		retryClient *network.RetryClient
+		out         *gbytes.Buffer
+	)
+
+	newResponse := func(statusCode int) *http.Response {
+		return &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+	}
+
+	BeforeEach(func() {
+		fakeClient = &fakes.HttpClient{}
+		fakeClient.DoReturns(newResponse(http.StatusOK), nil)
+
+		out = gbytes.NewBuffer()
+
+		retryClient = network.NewRetryClient(fakeClient, 2, time.Millisecond, out)
+	})
+
+	It("returns the response of the underlying http client", func() {
+		request, err := http.NewRequest("GET", "/api/v0/staged/products", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		response, err := retryClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(fakeClient.DoCallCount()).To(Equal(1))
+	})
+
+	It("retries when Ops Manager is unavailable", func() {
+		fakeClient.DoReturnsOnCall(0, newResponse(http.StatusBadGateway), nil)
+		fakeClient.DoReturnsOnCall(1, newResponse(http.StatusServiceUnavailable), nil)
+
+		request, err := http.NewRequest("GET", "/api/v0/staged/products", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		response, err := retryClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(fakeClient.DoCallCount()).To(Equal(3))
+
+		Expect(out).To(gbytes.Say("GET /api/v0/staged/products failed: Bad Gateway"))
+		Expect(out).To(gbytes.Say("Retrying in 1ms, attempt 1 out of 2..."))
+		Expect(out).To(gbytes.Say("GET /api/v0/staged/products failed: Service Unavailable"))
+		Expect(out).To(gbytes.Say("Retrying in 2ms, attempt 2 out of 2..."))
+	})
+
+	It("retries when the connection is reset", func() {
+		fakeClient.DoReturnsOnCall(0, nil, io.ErrUnexpectedEOF)
+
+		request, err := http.NewRequest("GET", "/api/v0/staged/products", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = retryClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.DoCallCount()).To(Equal(2))
+	})
+
+	It("sends the body of the request again", func() {
+		var bodies []string
+		fakeClient.DoStub = func(request *http.Request) (*http.Response, error) {
+			body, err := ioutil.ReadAll(request.Body)
+			Expect(err).NotTo(HaveOccurred())
+			bodies = append(bodies, string(body))
+
+			if len(bodies) == 1 {
+				return newResponse(http.StatusGatewayTimeout), nil
+			}
+			return newResponse(http.StatusOK), nil
+		}
+
+		request, err := http.NewRequest("GET", "/api/v0/staged/products", strings.NewReader("some-body"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = retryClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bodies).To(Equal([]string{"some-body", "some-body"}))
+	})
+
+	It("returns the last response once the retries are exhausted", func() {
+		fakeClient.DoReturns(newResponse(http.StatusServiceUnavailable), nil)
+
+		request, err := http.NewRequest("GET", "/api/v0/staged/products", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		response, err := retryClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(fakeClient.DoCallCount()).To(Equal(3))
+	})
+
+	It("returns the last error once the retries are exhausted", func() {
+		fakeClient.DoReturns(nil, io.EOF)
+
+		request, err := http.NewRequest("GET", "/api/v0/staged/products", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = retryClient.Do(request)
+		Expect(err).To(Equal(io.EOF))
+		Expect(fakeClient.DoCallCount()).To(Equal(3))
+	})
+
+	It("does not retry the requests that timed out", func() {
+		fakeClient.DoReturns(nil, &url.Error{Op: "Get", URL: "/api/v0/staged/products", Err: timeoutError{}})
+
+		request, err := http.NewRequest("GET", "/api/v0/staged/products", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = retryClient.Do(request)
+		Expect(err).To(HaveOccurred())
+		Expect(fakeClient.DoCallCount()).To(Equal(1))
+	})
+
+	It("does not retry the requests that modify Ops Manager", func() {
+		fakeClient.DoReturns(newResponse(http.StatusServiceUnavailable), nil)
+
+		request, err := http.NewRequest("POST", "/api/v0/installations", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		response, err := retryClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(fakeClient.DoCallCount()).To(Equal(1))
+	})
+
+	It("does not retry other errors and statuses", func() {
+		fakeClient.DoReturnsOnCall(0, nil, errors.New("some error"))
+		fakeClient.DoReturnsOnCall(1, newResponse(http.StatusInternalServerError), nil)
+
+		request, err := http.NewRequest("GET", "/api/v0/staged/products", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = retryClient.Do(request)
+		Expect(err).To(MatchError("some error"))
+
+		response, err := retryClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+		Expect(fakeClient.DoCallCount()).To(Equal(2))
+	})
+})
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,143 @@
+package network
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenCache stores the UAA tokens of om between its invocations, so that a
+// sequence of commands against the same Ops Manager only authenticates once.
+//
+// There is a file for every target and user, or client. The token is
+// encrypted with a key derived from the password, or client secret, so it can
+// only be read back with the same credentials.
+type TokenCache struct {
+	directory string
+}
+
+// NewTokenCache stores the tokens in the directory, which is created when the
+// first token is stored.
+func NewTokenCache(directory string) *TokenCache {
+	return &TokenCache{directory: directory}
+}
+
+// DefaultTokenCacheDirectory is the om directory of the cache directory of the user.
+func DefaultTokenCacheDirectory() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, "om", "tokens"), nil
+}
+
+// Load returns the token that was stored for the credentials. Tokens that
+// cannot be read or decrypted are not found.
+func (c *TokenCache) Load(target, identity, secret string) (*oauth2.Token, bool) {
+	contents, err := ioutil.ReadFile(c.path(target, identity))
+	if err != nil {
+		return nil, false
+	}
+
+	gcm, err := newTokenCipher(target, identity, secret)
+	if err != nil {
+		return nil, false
+	}
+
+	if len(contents) < gcm.NonceSize() {
+		return nil, false
+	}
+
+	plaintext, err := gcm.Open(nil, contents[:gcm.NonceSize()], contents[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, false
+	}
+
+	var token oauth2.Token
+	err = json.Unmarshal(plaintext, &token)
+	if err != nil || token.AccessToken == "" {
+		return nil, false
+	}
+
+	return &token, true
+}
+
+// Save stores the token for the credentials, replacing the previous one.
+func (c *TokenCache) Save(target, identity, secret string, token *oauth2.Token) error {
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return err // un-tested
+	}
+
+	gcm, err := newTokenCipher(target, identity, secret)
+	if err != nil {
+		return err // un-tested
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err // un-tested
+	}
+
+	err = os.MkdirAll(c.directory, 0700)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(c.directory, "token")
+	if err != nil {
+		return err // un-tested
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(gcm.Seal(nonce, nonce, plaintext, nil))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err // un-tested
+	}
+
+	return os.Rename(file.Name(), c.path(target, identity))
+}
+
+// Delete removes the token stored for the credentials, if any.
+func (c *TokenCache) Delete(target, identity string) error {
+	err := os.Remove(c.path(target, identity))
+	if err != nil && !os.IsNotExist(err) {
+		return err // un-tested
+	}
+
+	return nil
+}
+
+func (c *TokenCache) path(target, identity string) string {
+	sum := sha256.Sum256([]byte(target + "\n" + identity))
+	return filepath.Join(c.directory, hex.EncodeToString(sum[:]))
+}
+
+func newTokenCipher(target, identity, secret string) (cipher.AEAD, error) {
+	if secret == "" {
+		return nil, errors.New("cannot encrypt a token without a secret")
+	}
+
+	key := sha256.Sum256([]byte("om token cache\n" + target + "\n" + identity + "\n" + secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err // un-tested
+	}
+
+	return cipher.NewGCM(block)
+}
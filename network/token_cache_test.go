@@ -0,0 +1,91 @@
+package network_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pivotal-cf/om/network"
+	"golang.org/x/oauth2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TokenCache", func() {
+	var (
+		directory string
+		cache     *network.TokenCache
+		token     *oauth2.Token
+	)
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		cache = network.NewTokenCache(filepath.Join(directory, "tokens"))
+		token = &oauth2.Token{
+			AccessToken:  "some-access-token",
+			TokenType:    "bearer",
+			RefreshToken: "some-refresh-token",
+			Expiry:       time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(directory)
+	})
+
+	It("loads the token that was saved for the same credentials", func() {
+		Expect(cache.Save("https://opsman.example.com", "user:admin", "some-password", token)).To(Succeed())
+
+		loaded, found := cache.Load("https://opsman.example.com", "user:admin", "some-password")
+		Expect(found).To(BeTrue())
+		Expect(loaded.AccessToken).To(Equal("some-access-token"))
+		Expect(loaded.RefreshToken).To(Equal("some-refresh-token"))
+		Expect(loaded.Expiry.Equal(token.Expiry)).To(BeTrue())
+	})
+
+	It("encrypts the token", func() {
+		Expect(cache.Save("https://opsman.example.com", "user:admin", "some-password", token)).To(Succeed())
+
+		files, err := ioutil.ReadDir(filepath.Join(directory, "tokens"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		Expect(files[0].Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		contents, err := ioutil.ReadFile(filepath.Join(directory, "tokens", files[0].Name()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).NotTo(ContainSubstring("some-access-token"))
+	})
+
+	It("does not find the token with other credentials or of another target", func() {
+		Expect(cache.Save("https://opsman.example.com", "user:admin", "some-password", token)).To(Succeed())
+
+		_, found := cache.Load("https://opsman.example.com", "user:admin", "other-password")
+		Expect(found).To(BeFalse())
+
+		_, found = cache.Load("https://opsman.example.com", "user:other", "some-password")
+		Expect(found).To(BeFalse())
+
+		_, found = cache.Load("https://other.example.com", "user:admin", "some-password")
+		Expect(found).To(BeFalse())
+	})
+
+	It("does not find the token once it is deleted", func() {
+		Expect(cache.Save("https://opsman.example.com", "user:admin", "some-password", token)).To(Succeed())
+		Expect(cache.Delete("https://opsman.example.com", "user:admin")).To(Succeed())
+
+		_, found := cache.Load("https://opsman.example.com", "user:admin", "some-password")
+		Expect(found).To(BeFalse())
+
+		Expect(cache.Delete("https://opsman.example.com", "user:admin")).To(Succeed())
+	})
+
+	It("does not cache tokens without a secret", func() {
+		err := cache.Save("https://opsman.example.com", "user:admin", "", token)
+		Expect(err).To(MatchError("cannot encrypt a token without a secret"))
+	})
+})
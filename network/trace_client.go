@@ -5,10 +5,14 @@ import (
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"time"
 )
 
 const maxBodySize = 1024 * 1024
 
+// redactedHeaders carry credentials, so their values are never traced.
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
 //go:generate counterfeiter -o ./fakes/httpclient.go --fake-name HttpClient . httpClient
 
 type httpClient interface {
@@ -16,44 +20,74 @@ type httpClient interface {
 }
 
 type TraceClient struct {
-	client httpClient
-	writer io.Writer
+	client        httpClient
+	writer        io.Writer
+	includeBodies bool
 }
 
-func NewTraceClient(client httpClient, writer io.Writer) *TraceClient {
+// NewTraceClient writes the requests and responses to the writer, with the
+// headers that carry credentials redacted. The bodies, which can contain
+// secrets, are only written when includeBodies is set.
+func NewTraceClient(client httpClient, writer io.Writer, includeBodies bool) *TraceClient {
 	return &TraceClient{
-		client: client,
-		writer: writer,
+		client:        client,
+		writer:        writer,
+		includeBodies: includeBodies,
 	}
 }
 
 func (c *TraceClient) Do(request *http.Request) (*http.Response, error) {
-	dumpRequestBody := true
-	if request.ContentLength >= maxBodySize {
-		dumpRequestBody = false
-	}
+	dumpRequestBody := c.includeBodies && request.ContentLength < maxBodySize
 
-	requestOutput, err := httputil.DumpRequest(request, dumpRequestBody)
+	redactedRequest := *request
+	redactedRequest.Header = redactHeaders(request.Header)
+
+	requestOutput, err := httputil.DumpRequest(&redactedRequest, dumpRequestBody)
 	if err != nil {
 		return nil, err
 	}
+	// the body has been read, and replaced by a copy, to be dumped
+	request.Body = redactedRequest.Body
 
 	fmt.Fprintf(c.writer, "%s\n", string(requestOutput))
 
+	start := time.Now()
 	response, err := c.client.Do(request)
+	elapsed := time.Since(start).Round(time.Millisecond)
 	if err != nil {
+		fmt.Fprintf(c.writer, "%s %s failed after %s: %s\n\n", request.Method, request.URL, elapsed, err)
 		return nil, err
 	}
 
-	dumpResponseBody := true
-	if response.ContentLength >= maxBodySize {
-		dumpResponseBody = false
-	}
-	responseOutput, err := httputil.DumpResponse(response, dumpResponseBody)
+	fmt.Fprintf(c.writer, "%s %s: %s in %s\n", request.Method, request.URL, response.Status, elapsed)
+
+	dumpResponseBody := c.includeBodies && response.ContentLength < maxBodySize
+
+	redactedResponse := *response
+	redactedResponse.Header = redactHeaders(response.Header)
+
+	responseOutput, err := httputil.DumpResponse(&redactedResponse, dumpResponseBody)
 	if err != nil {
 		return nil, err
 	}
+	response.Body = redactedResponse.Body
+
 	fmt.Fprintf(c.writer, "%s\n", string(responseOutput))
 
 	return response, nil
 }
+
+func redactHeaders(headers http.Header) http.Header {
+	redacted := http.Header{}
+	for name, values := range headers {
+		redacted[name] = values
+	}
+
+	for _, name := range redactedHeaders {
+		if _, ok := redacted[name]; ok {
+			redacted.Set(name, "[REDACTED]")
+		}
+	}
+
+	return redacted
+}
@@ -38,7 +38,7 @@ var _ = Describe("Trace Client", func() {
 
 		out = gbytes.NewBuffer()
 
-		traceClient = network.NewTraceClient(fakeClient, out)
+		traceClient = network.NewTraceClient(fakeClient, out, true)
 	})
 
 	It("calls the underlying http client", func() {
@@ -62,6 +62,61 @@ var _ = Describe("Trace Client", func() {
 		Expect(out).To(gbytes.Say(string(expectedContents)))
 	})
 
+	It("writes the method, url, status and duration of the request", func() {
+		response.Status = "200 OK"
+
+		_, err := traceClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out).To(gbytes.Say(`GET http://example.com: 200 OK in \d+m?s`))
+	})
+
+	It("redacts the headers that carry credentials", func() {
+		request.Header.Set("Authorization", "Bearer some-token")
+		request.Header.Set("Cookie", "session=some-session")
+		request.Header.Set("Accept", "application/json")
+		response.Header = http.Header{"Set-Cookie": []string{"session=some-other-session"}}
+
+		_, err := traceClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(out.Contents())).NotTo(ContainSubstring("some-token"))
+		Expect(string(out.Contents())).NotTo(ContainSubstring("some-session"))
+		Expect(string(out.Contents())).NotTo(ContainSubstring("some-other-session"))
+		Expect(string(out.Contents())).To(ContainSubstring("Authorization: [REDACTED]"))
+		Expect(string(out.Contents())).To(ContainSubstring("Set-Cookie: [REDACTED]"))
+		Expect(string(out.Contents())).To(ContainSubstring("Accept: application/json"))
+
+		Expect(fakeClient.DoArgsForCall(0).Header.Get("Authorization")).To(Equal("Bearer some-token"))
+	})
+
+	Context("when the bodies are not included", func() {
+		BeforeEach(func() {
+			traceClient = network.NewTraceClient(fakeClient, out, false)
+		})
+
+		It("only dumps the headers, and leaves the bodies to be read", func() {
+			request, err := http.NewRequest("PUT", "http://example.com", strings.NewReader(`{"password": "some-password"}`))
+			Expect(err).NotTo(HaveOccurred())
+			response.Body = ioutil.NopCloser(strings.NewReader(`{"secret": "some-secret"}`))
+
+			resp, err := traceClient.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(out.Contents())).To(ContainSubstring("PUT / HTTP/1.1"))
+			Expect(string(out.Contents())).NotTo(ContainSubstring("some-password"))
+			Expect(string(out.Contents())).NotTo(ContainSubstring("some-secret"))
+
+			requestBody, err := ioutil.ReadAll(fakeClient.DoArgsForCall(0).Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(requestBody)).To(Equal(`{"password": "some-password"}`))
+
+			responseBody, err := ioutil.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(responseBody)).To(Equal(`{"secret": "some-secret"}`))
+		})
+	})
+
 	Context("when the underlying http client fails", func() {
 		BeforeEach(func() {
 			fakeClient.DoReturns(nil, errors.New("boom!"))
@@ -70,6 +125,8 @@ var _ = Describe("Trace Client", func() {
 		It("returns the error", func() {
 			_, err := traceClient.Do(request)
 			Expect(err).To(HaveOccurred())
+
+			Expect(out).To(gbytes.Say(`GET http://example.com failed after \d+m?s: boom!`))
 		})
 	})
 
@@ -25,6 +25,11 @@ type FormattedPresenter struct {
 	presentCertificateAuthorityArgsForCall []struct {
 		arg1 api.CA
 	}
+	PresentCertificateRotationsStub        func([]api.CertificateRotation)
+	presentCertificateRotationsMutex       sync.RWMutex
+	presentCertificateRotationsArgsForCall []struct {
+		arg1 []api.CertificateRotation
+	}
 	PresentCredentialReferencesStub        func([]string)
 	presentCredentialReferencesMutex       sync.RWMutex
 	presentCredentialReferencesArgsForCall []struct {
@@ -45,6 +50,16 @@ type FormattedPresenter struct {
 	presentErrandsArgsForCall []struct {
 		arg1 []models.Errand
 	}
+	PresentExpiringCertificatesStub        func([]api.ExpiringCertificate)
+	presentExpiringCertificatesMutex       sync.RWMutex
+	presentExpiringCertificatesArgsForCall []struct {
+		arg1 []api.ExpiringCertificate
+	}
+	PresentExpiringLicensesStub        func([]models.ExpiringLicense)
+	presentExpiringLicensesMutex       sync.RWMutex
+	presentExpiringLicensesArgsForCall []struct {
+		arg1 []models.ExpiringLicense
+	}
 	PresentInstallationsStub        func([]models.Installation)
 	presentInstallationsMutex       sync.RWMutex
 	presentInstallationsArgsForCall []struct {
@@ -55,6 +70,16 @@ type FormattedPresenter struct {
 	presentPendingChangesArgsForCall []struct {
 		arg1 []api.ProductChange
 	}
+	PresentProductsStub        func([]models.ProductVersions)
+	presentProductsMutex       sync.RWMutex
+	presentProductsArgsForCall []struct {
+		arg1 []models.ProductVersions
+	}
+	PresentResourceConfigStub        func(map[string]api.JobProperties)
+	presentResourceConfigMutex       sync.RWMutex
+	presentResourceConfigArgsForCall []struct {
+		arg1 map[string]api.JobProperties
+	}
 	PresentSSLCertificateStub        func(api.SSLCertificate)
 	presentSSLCertificateMutex       sync.RWMutex
 	presentSSLCertificateArgsForCall []struct {
@@ -65,6 +90,21 @@ type FormattedPresenter struct {
 	presentStagedProductsArgsForCall []struct {
 		arg1 []api.DiagnosticProduct
 	}
+	PresentStemcellAssignmentsStub        func([]models.StemcellAssignment)
+	presentStemcellAssignmentsMutex       sync.RWMutex
+	presentStemcellAssignmentsArgsForCall []struct {
+		arg1 []models.StemcellAssignment
+	}
+	PresentVMExtensionsStub        func([]api.VMExtension)
+	presentVMExtensionsMutex       sync.RWMutex
+	presentVMExtensionsArgsForCall []struct {
+		arg1 []api.VMExtension
+	}
+	PresentVerifiersStub        func([]api.Verifier)
+	presentVerifiersMutex       sync.RWMutex
+	presentVerifiersArgsForCall []struct {
+		arg1 []api.Verifier
+	}
 	SetFormatStub        func(string)
 	setFormatMutex       sync.RWMutex
 	setFormatArgsForCall []struct {
@@ -177,6 +217,42 @@ func (fake *FormattedPresenter) PresentCertificateAuthorityArgsForCall(i int) ap
 	return argsForCall.arg1
 }
 
+func (fake *FormattedPresenter) PresentCertificateRotations(arg1 []api.CertificateRotation) {
+	var arg1Copy []api.CertificateRotation
+	if arg1 != nil {
+		arg1Copy = make([]api.CertificateRotation, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentCertificateRotationsMutex.Lock()
+	fake.presentCertificateRotationsArgsForCall = append(fake.presentCertificateRotationsArgsForCall, struct {
+		arg1 []api.CertificateRotation
+	}{arg1Copy})
+	fake.recordInvocation("PresentCertificateRotations", []interface{}{arg1Copy})
+	fake.presentCertificateRotationsMutex.Unlock()
+	if fake.PresentCertificateRotationsStub != nil {
+		fake.PresentCertificateRotationsStub(arg1)
+	}
+}
+
+func (fake *FormattedPresenter) PresentCertificateRotationsCallCount() int {
+	fake.presentCertificateRotationsMutex.RLock()
+	defer fake.presentCertificateRotationsMutex.RUnlock()
+	return len(fake.presentCertificateRotationsArgsForCall)
+}
+
+func (fake *FormattedPresenter) PresentCertificateRotationsCalls(stub func([]api.CertificateRotation)) {
+	fake.presentCertificateRotationsMutex.Lock()
+	defer fake.presentCertificateRotationsMutex.Unlock()
+	fake.PresentCertificateRotationsStub = stub
+}
+
+func (fake *FormattedPresenter) PresentCertificateRotationsArgsForCall(i int) []api.CertificateRotation {
+	fake.presentCertificateRotationsMutex.RLock()
+	defer fake.presentCertificateRotationsMutex.RUnlock()
+	argsForCall := fake.presentCertificateRotationsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FormattedPresenter) PresentCredentialReferences(arg1 []string) {
 	var arg1Copy []string
 	if arg1 != nil {
@@ -316,6 +392,78 @@ func (fake *FormattedPresenter) PresentErrandsArgsForCall(i int) []models.Errand
 	return argsForCall.arg1
 }
 
+func (fake *FormattedPresenter) PresentExpiringCertificates(arg1 []api.ExpiringCertificate) {
+	var arg1Copy []api.ExpiringCertificate
+	if arg1 != nil {
+		arg1Copy = make([]api.ExpiringCertificate, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentExpiringCertificatesMutex.Lock()
+	fake.presentExpiringCertificatesArgsForCall = append(fake.presentExpiringCertificatesArgsForCall, struct {
+		arg1 []api.ExpiringCertificate
+	}{arg1Copy})
+	fake.recordInvocation("PresentExpiringCertificates", []interface{}{arg1Copy})
+	fake.presentExpiringCertificatesMutex.Unlock()
+	if fake.PresentExpiringCertificatesStub != nil {
+		fake.PresentExpiringCertificatesStub(arg1)
+	}
+}
+
+func (fake *FormattedPresenter) PresentExpiringCertificatesCallCount() int {
+	fake.presentExpiringCertificatesMutex.RLock()
+	defer fake.presentExpiringCertificatesMutex.RUnlock()
+	return len(fake.presentExpiringCertificatesArgsForCall)
+}
+
+func (fake *FormattedPresenter) PresentExpiringCertificatesCalls(stub func([]api.ExpiringCertificate)) {
+	fake.presentExpiringCertificatesMutex.Lock()
+	defer fake.presentExpiringCertificatesMutex.Unlock()
+	fake.PresentExpiringCertificatesStub = stub
+}
+
+func (fake *FormattedPresenter) PresentExpiringCertificatesArgsForCall(i int) []api.ExpiringCertificate {
+	fake.presentExpiringCertificatesMutex.RLock()
+	defer fake.presentExpiringCertificatesMutex.RUnlock()
+	argsForCall := fake.presentExpiringCertificatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FormattedPresenter) PresentExpiringLicenses(arg1 []models.ExpiringLicense) {
+	var arg1Copy []models.ExpiringLicense
+	if arg1 != nil {
+		arg1Copy = make([]models.ExpiringLicense, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentExpiringLicensesMutex.Lock()
+	fake.presentExpiringLicensesArgsForCall = append(fake.presentExpiringLicensesArgsForCall, struct {
+		arg1 []models.ExpiringLicense
+	}{arg1Copy})
+	fake.recordInvocation("PresentExpiringLicenses", []interface{}{arg1Copy})
+	fake.presentExpiringLicensesMutex.Unlock()
+	if fake.PresentExpiringLicensesStub != nil {
+		fake.PresentExpiringLicensesStub(arg1)
+	}
+}
+
+func (fake *FormattedPresenter) PresentExpiringLicensesCallCount() int {
+	fake.presentExpiringLicensesMutex.RLock()
+	defer fake.presentExpiringLicensesMutex.RUnlock()
+	return len(fake.presentExpiringLicensesArgsForCall)
+}
+
+func (fake *FormattedPresenter) PresentExpiringLicensesCalls(stub func([]models.ExpiringLicense)) {
+	fake.presentExpiringLicensesMutex.Lock()
+	defer fake.presentExpiringLicensesMutex.Unlock()
+	fake.PresentExpiringLicensesStub = stub
+}
+
+func (fake *FormattedPresenter) PresentExpiringLicensesArgsForCall(i int) []models.ExpiringLicense {
+	fake.presentExpiringLicensesMutex.RLock()
+	defer fake.presentExpiringLicensesMutex.RUnlock()
+	argsForCall := fake.presentExpiringLicensesArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FormattedPresenter) PresentInstallations(arg1 []models.Installation) {
 	var arg1Copy []models.Installation
 	if arg1 != nil {
@@ -388,6 +536,73 @@ func (fake *FormattedPresenter) PresentPendingChangesArgsForCall(i int) []api.Pr
 	return argsForCall.arg1
 }
 
+func (fake *FormattedPresenter) PresentProducts(arg1 []models.ProductVersions) {
+	var arg1Copy []models.ProductVersions
+	if arg1 != nil {
+		arg1Copy = make([]models.ProductVersions, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentProductsMutex.Lock()
+	fake.presentProductsArgsForCall = append(fake.presentProductsArgsForCall, struct {
+		arg1 []models.ProductVersions
+	}{arg1Copy})
+	fake.recordInvocation("PresentProducts", []interface{}{arg1Copy})
+	fake.presentProductsMutex.Unlock()
+	if fake.PresentProductsStub != nil {
+		fake.PresentProductsStub(arg1)
+	}
+}
+
+func (fake *FormattedPresenter) PresentProductsCallCount() int {
+	fake.presentProductsMutex.RLock()
+	defer fake.presentProductsMutex.RUnlock()
+	return len(fake.presentProductsArgsForCall)
+}
+
+func (fake *FormattedPresenter) PresentProductsCalls(stub func([]models.ProductVersions)) {
+	fake.presentProductsMutex.Lock()
+	defer fake.presentProductsMutex.Unlock()
+	fake.PresentProductsStub = stub
+}
+
+func (fake *FormattedPresenter) PresentProductsArgsForCall(i int) []models.ProductVersions {
+	fake.presentProductsMutex.RLock()
+	defer fake.presentProductsMutex.RUnlock()
+	argsForCall := fake.presentProductsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FormattedPresenter) PresentResourceConfig(arg1 map[string]api.JobProperties) {
+	fake.presentResourceConfigMutex.Lock()
+	fake.presentResourceConfigArgsForCall = append(fake.presentResourceConfigArgsForCall, struct {
+		arg1 map[string]api.JobProperties
+	}{arg1})
+	fake.recordInvocation("PresentResourceConfig", []interface{}{arg1})
+	fake.presentResourceConfigMutex.Unlock()
+	if fake.PresentResourceConfigStub != nil {
+		fake.PresentResourceConfigStub(arg1)
+	}
+}
+
+func (fake *FormattedPresenter) PresentResourceConfigCallCount() int {
+	fake.presentResourceConfigMutex.RLock()
+	defer fake.presentResourceConfigMutex.RUnlock()
+	return len(fake.presentResourceConfigArgsForCall)
+}
+
+func (fake *FormattedPresenter) PresentResourceConfigCalls(stub func(map[string]api.JobProperties)) {
+	fake.presentResourceConfigMutex.Lock()
+	defer fake.presentResourceConfigMutex.Unlock()
+	fake.PresentResourceConfigStub = stub
+}
+
+func (fake *FormattedPresenter) PresentResourceConfigArgsForCall(i int) map[string]api.JobProperties {
+	fake.presentResourceConfigMutex.RLock()
+	defer fake.presentResourceConfigMutex.RUnlock()
+	argsForCall := fake.presentResourceConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FormattedPresenter) PresentSSLCertificate(arg1 api.SSLCertificate) {
 	fake.presentSSLCertificateMutex.Lock()
 	fake.presentSSLCertificateArgsForCall = append(fake.presentSSLCertificateArgsForCall, struct {
@@ -455,6 +670,114 @@ func (fake *FormattedPresenter) PresentStagedProductsArgsForCall(i int) []api.Di
 	return argsForCall.arg1
 }
 
+func (fake *FormattedPresenter) PresentStemcellAssignments(arg1 []models.StemcellAssignment) {
+	var arg1Copy []models.StemcellAssignment
+	if arg1 != nil {
+		arg1Copy = make([]models.StemcellAssignment, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentStemcellAssignmentsMutex.Lock()
+	fake.presentStemcellAssignmentsArgsForCall = append(fake.presentStemcellAssignmentsArgsForCall, struct {
+		arg1 []models.StemcellAssignment
+	}{arg1Copy})
+	fake.recordInvocation("PresentStemcellAssignments", []interface{}{arg1Copy})
+	fake.presentStemcellAssignmentsMutex.Unlock()
+	if fake.PresentStemcellAssignmentsStub != nil {
+		fake.PresentStemcellAssignmentsStub(arg1)
+	}
+}
+
+func (fake *FormattedPresenter) PresentStemcellAssignmentsCallCount() int {
+	fake.presentStemcellAssignmentsMutex.RLock()
+	defer fake.presentStemcellAssignmentsMutex.RUnlock()
+	return len(fake.presentStemcellAssignmentsArgsForCall)
+}
+
+func (fake *FormattedPresenter) PresentStemcellAssignmentsCalls(stub func([]models.StemcellAssignment)) {
+	fake.presentStemcellAssignmentsMutex.Lock()
+	defer fake.presentStemcellAssignmentsMutex.Unlock()
+	fake.PresentStemcellAssignmentsStub = stub
+}
+
+func (fake *FormattedPresenter) PresentStemcellAssignmentsArgsForCall(i int) []models.StemcellAssignment {
+	fake.presentStemcellAssignmentsMutex.RLock()
+	defer fake.presentStemcellAssignmentsMutex.RUnlock()
+	argsForCall := fake.presentStemcellAssignmentsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FormattedPresenter) PresentVMExtensions(arg1 []api.VMExtension) {
+	var arg1Copy []api.VMExtension
+	if arg1 != nil {
+		arg1Copy = make([]api.VMExtension, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentVMExtensionsMutex.Lock()
+	fake.presentVMExtensionsArgsForCall = append(fake.presentVMExtensionsArgsForCall, struct {
+		arg1 []api.VMExtension
+	}{arg1Copy})
+	fake.recordInvocation("PresentVMExtensions", []interface{}{arg1Copy})
+	fake.presentVMExtensionsMutex.Unlock()
+	if fake.PresentVMExtensionsStub != nil {
+		fake.PresentVMExtensionsStub(arg1)
+	}
+}
+
+func (fake *FormattedPresenter) PresentVMExtensionsCallCount() int {
+	fake.presentVMExtensionsMutex.RLock()
+	defer fake.presentVMExtensionsMutex.RUnlock()
+	return len(fake.presentVMExtensionsArgsForCall)
+}
+
+func (fake *FormattedPresenter) PresentVMExtensionsCalls(stub func([]api.VMExtension)) {
+	fake.presentVMExtensionsMutex.Lock()
+	defer fake.presentVMExtensionsMutex.Unlock()
+	fake.PresentVMExtensionsStub = stub
+}
+
+func (fake *FormattedPresenter) PresentVMExtensionsArgsForCall(i int) []api.VMExtension {
+	fake.presentVMExtensionsMutex.RLock()
+	defer fake.presentVMExtensionsMutex.RUnlock()
+	argsForCall := fake.presentVMExtensionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FormattedPresenter) PresentVerifiers(arg1 []api.Verifier) {
+	var arg1Copy []api.Verifier
+	if arg1 != nil {
+		arg1Copy = make([]api.Verifier, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentVerifiersMutex.Lock()
+	fake.presentVerifiersArgsForCall = append(fake.presentVerifiersArgsForCall, struct {
+		arg1 []api.Verifier
+	}{arg1Copy})
+	fake.recordInvocation("PresentVerifiers", []interface{}{arg1Copy})
+	fake.presentVerifiersMutex.Unlock()
+	if fake.PresentVerifiersStub != nil {
+		fake.PresentVerifiersStub(arg1)
+	}
+}
+
+func (fake *FormattedPresenter) PresentVerifiersCallCount() int {
+	fake.presentVerifiersMutex.RLock()
+	defer fake.presentVerifiersMutex.RUnlock()
+	return len(fake.presentVerifiersArgsForCall)
+}
+
+func (fake *FormattedPresenter) PresentVerifiersCalls(stub func([]api.Verifier)) {
+	fake.presentVerifiersMutex.Lock()
+	defer fake.presentVerifiersMutex.Unlock()
+	fake.PresentVerifiersStub = stub
+}
+
+func (fake *FormattedPresenter) PresentVerifiersArgsForCall(i int) []api.Verifier {
+	fake.presentVerifiersMutex.RLock()
+	defer fake.presentVerifiersMutex.RUnlock()
+	argsForCall := fake.presentVerifiersArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FormattedPresenter) SetFormat(arg1 string) {
 	fake.setFormatMutex.Lock()
 	fake.setFormatArgsForCall = append(fake.setFormatArgsForCall, struct {
@@ -495,6 +818,8 @@ func (fake *FormattedPresenter) Invocations() map[string][][]interface{} {
 	defer fake.presentCertificateAuthoritiesMutex.RUnlock()
 	fake.presentCertificateAuthorityMutex.RLock()
 	defer fake.presentCertificateAuthorityMutex.RUnlock()
+	fake.presentCertificateRotationsMutex.RLock()
+	defer fake.presentCertificateRotationsMutex.RUnlock()
 	fake.presentCredentialReferencesMutex.RLock()
 	defer fake.presentCredentialReferencesMutex.RUnlock()
 	fake.presentCredentialsMutex.RLock()
@@ -503,14 +828,28 @@ func (fake *FormattedPresenter) Invocations() map[string][][]interface{} {
 	defer fake.presentDeployedProductsMutex.RUnlock()
 	fake.presentErrandsMutex.RLock()
 	defer fake.presentErrandsMutex.RUnlock()
+	fake.presentExpiringCertificatesMutex.RLock()
+	defer fake.presentExpiringCertificatesMutex.RUnlock()
+	fake.presentExpiringLicensesMutex.RLock()
+	defer fake.presentExpiringLicensesMutex.RUnlock()
 	fake.presentInstallationsMutex.RLock()
 	defer fake.presentInstallationsMutex.RUnlock()
 	fake.presentPendingChangesMutex.RLock()
 	defer fake.presentPendingChangesMutex.RUnlock()
+	fake.presentProductsMutex.RLock()
+	defer fake.presentProductsMutex.RUnlock()
+	fake.presentResourceConfigMutex.RLock()
+	defer fake.presentResourceConfigMutex.RUnlock()
 	fake.presentSSLCertificateMutex.RLock()
 	defer fake.presentSSLCertificateMutex.RUnlock()
 	fake.presentStagedProductsMutex.RLock()
 	defer fake.presentStagedProductsMutex.RUnlock()
+	fake.presentStemcellAssignmentsMutex.RLock()
+	defer fake.presentStemcellAssignmentsMutex.RUnlock()
+	fake.presentVMExtensionsMutex.RLock()
+	defer fake.presentVMExtensionsMutex.RUnlock()
+	fake.presentVerifiersMutex.RLock()
+	defer fake.presentVerifiersMutex.RUnlock()
 	fake.setFormatMutex.RLock()
 	defer fake.setFormatMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -25,6 +25,11 @@ type Presenter struct {
 	presentCertificateAuthorityArgsForCall []struct {
 		arg1 api.CA
 	}
+	PresentCertificateRotationsStub        func([]api.CertificateRotation)
+	presentCertificateRotationsMutex       sync.RWMutex
+	presentCertificateRotationsArgsForCall []struct {
+		arg1 []api.CertificateRotation
+	}
 	PresentCredentialReferencesStub        func([]string)
 	presentCredentialReferencesMutex       sync.RWMutex
 	presentCredentialReferencesArgsForCall []struct {
@@ -45,6 +50,16 @@ type Presenter struct {
 	presentErrandsArgsForCall []struct {
 		arg1 []models.Errand
 	}
+	PresentExpiringCertificatesStub        func([]api.ExpiringCertificate)
+	presentExpiringCertificatesMutex       sync.RWMutex
+	presentExpiringCertificatesArgsForCall []struct {
+		arg1 []api.ExpiringCertificate
+	}
+	PresentExpiringLicensesStub        func([]models.ExpiringLicense)
+	presentExpiringLicensesMutex       sync.RWMutex
+	presentExpiringLicensesArgsForCall []struct {
+		arg1 []models.ExpiringLicense
+	}
 	PresentInstallationsStub        func([]models.Installation)
 	presentInstallationsMutex       sync.RWMutex
 	presentInstallationsArgsForCall []struct {
@@ -55,6 +70,16 @@ type Presenter struct {
 	presentPendingChangesArgsForCall []struct {
 		arg1 []api.ProductChange
 	}
+	PresentProductsStub        func([]models.ProductVersions)
+	presentProductsMutex       sync.RWMutex
+	presentProductsArgsForCall []struct {
+		arg1 []models.ProductVersions
+	}
+	PresentResourceConfigStub        func(map[string]api.JobProperties)
+	presentResourceConfigMutex       sync.RWMutex
+	presentResourceConfigArgsForCall []struct {
+		arg1 map[string]api.JobProperties
+	}
 	PresentSSLCertificateStub        func(api.SSLCertificate)
 	presentSSLCertificateMutex       sync.RWMutex
 	presentSSLCertificateArgsForCall []struct {
@@ -65,6 +90,21 @@ type Presenter struct {
 	presentStagedProductsArgsForCall []struct {
 		arg1 []api.DiagnosticProduct
 	}
+	PresentStemcellAssignmentsStub        func([]models.StemcellAssignment)
+	presentStemcellAssignmentsMutex       sync.RWMutex
+	presentStemcellAssignmentsArgsForCall []struct {
+		arg1 []models.StemcellAssignment
+	}
+	PresentVMExtensionsStub        func([]api.VMExtension)
+	presentVMExtensionsMutex       sync.RWMutex
+	presentVMExtensionsArgsForCall []struct {
+		arg1 []api.VMExtension
+	}
+	PresentVerifiersStub        func([]api.Verifier)
+	presentVerifiersMutex       sync.RWMutex
+	presentVerifiersArgsForCall []struct {
+		arg1 []api.Verifier
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -172,6 +212,42 @@ func (fake *Presenter) PresentCertificateAuthorityArgsForCall(i int) api.CA {
 	return argsForCall.arg1
 }
 
+func (fake *Presenter) PresentCertificateRotations(arg1 []api.CertificateRotation) {
+	var arg1Copy []api.CertificateRotation
+	if arg1 != nil {
+		arg1Copy = make([]api.CertificateRotation, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentCertificateRotationsMutex.Lock()
+	fake.presentCertificateRotationsArgsForCall = append(fake.presentCertificateRotationsArgsForCall, struct {
+		arg1 []api.CertificateRotation
+	}{arg1Copy})
+	fake.recordInvocation("PresentCertificateRotations", []interface{}{arg1Copy})
+	fake.presentCertificateRotationsMutex.Unlock()
+	if fake.PresentCertificateRotationsStub != nil {
+		fake.PresentCertificateRotationsStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentCertificateRotationsCallCount() int {
+	fake.presentCertificateRotationsMutex.RLock()
+	defer fake.presentCertificateRotationsMutex.RUnlock()
+	return len(fake.presentCertificateRotationsArgsForCall)
+}
+
+func (fake *Presenter) PresentCertificateRotationsCalls(stub func([]api.CertificateRotation)) {
+	fake.presentCertificateRotationsMutex.Lock()
+	defer fake.presentCertificateRotationsMutex.Unlock()
+	fake.PresentCertificateRotationsStub = stub
+}
+
+func (fake *Presenter) PresentCertificateRotationsArgsForCall(i int) []api.CertificateRotation {
+	fake.presentCertificateRotationsMutex.RLock()
+	defer fake.presentCertificateRotationsMutex.RUnlock()
+	argsForCall := fake.presentCertificateRotationsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *Presenter) PresentCredentialReferences(arg1 []string) {
 	var arg1Copy []string
 	if arg1 != nil {
@@ -311,6 +387,78 @@ func (fake *Presenter) PresentErrandsArgsForCall(i int) []models.Errand {
 	return argsForCall.arg1
 }
 
+func (fake *Presenter) PresentExpiringCertificates(arg1 []api.ExpiringCertificate) {
+	var arg1Copy []api.ExpiringCertificate
+	if arg1 != nil {
+		arg1Copy = make([]api.ExpiringCertificate, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentExpiringCertificatesMutex.Lock()
+	fake.presentExpiringCertificatesArgsForCall = append(fake.presentExpiringCertificatesArgsForCall, struct {
+		arg1 []api.ExpiringCertificate
+	}{arg1Copy})
+	fake.recordInvocation("PresentExpiringCertificates", []interface{}{arg1Copy})
+	fake.presentExpiringCertificatesMutex.Unlock()
+	if fake.PresentExpiringCertificatesStub != nil {
+		fake.PresentExpiringCertificatesStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentExpiringCertificatesCallCount() int {
+	fake.presentExpiringCertificatesMutex.RLock()
+	defer fake.presentExpiringCertificatesMutex.RUnlock()
+	return len(fake.presentExpiringCertificatesArgsForCall)
+}
+
+func (fake *Presenter) PresentExpiringCertificatesCalls(stub func([]api.ExpiringCertificate)) {
+	fake.presentExpiringCertificatesMutex.Lock()
+	defer fake.presentExpiringCertificatesMutex.Unlock()
+	fake.PresentExpiringCertificatesStub = stub
+}
+
+func (fake *Presenter) PresentExpiringCertificatesArgsForCall(i int) []api.ExpiringCertificate {
+	fake.presentExpiringCertificatesMutex.RLock()
+	defer fake.presentExpiringCertificatesMutex.RUnlock()
+	argsForCall := fake.presentExpiringCertificatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Presenter) PresentExpiringLicenses(arg1 []models.ExpiringLicense) {
+	var arg1Copy []models.ExpiringLicense
+	if arg1 != nil {
+		arg1Copy = make([]models.ExpiringLicense, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentExpiringLicensesMutex.Lock()
+	fake.presentExpiringLicensesArgsForCall = append(fake.presentExpiringLicensesArgsForCall, struct {
+		arg1 []models.ExpiringLicense
+	}{arg1Copy})
+	fake.recordInvocation("PresentExpiringLicenses", []interface{}{arg1Copy})
+	fake.presentExpiringLicensesMutex.Unlock()
+	if fake.PresentExpiringLicensesStub != nil {
+		fake.PresentExpiringLicensesStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentExpiringLicensesCallCount() int {
+	fake.presentExpiringLicensesMutex.RLock()
+	defer fake.presentExpiringLicensesMutex.RUnlock()
+	return len(fake.presentExpiringLicensesArgsForCall)
+}
+
+func (fake *Presenter) PresentExpiringLicensesCalls(stub func([]models.ExpiringLicense)) {
+	fake.presentExpiringLicensesMutex.Lock()
+	defer fake.presentExpiringLicensesMutex.Unlock()
+	fake.PresentExpiringLicensesStub = stub
+}
+
+func (fake *Presenter) PresentExpiringLicensesArgsForCall(i int) []models.ExpiringLicense {
+	fake.presentExpiringLicensesMutex.RLock()
+	defer fake.presentExpiringLicensesMutex.RUnlock()
+	argsForCall := fake.presentExpiringLicensesArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *Presenter) PresentInstallations(arg1 []models.Installation) {
 	var arg1Copy []models.Installation
 	if arg1 != nil {
@@ -383,6 +531,73 @@ func (fake *Presenter) PresentPendingChangesArgsForCall(i int) []api.ProductChan
 	return argsForCall.arg1
 }
 
+func (fake *Presenter) PresentProducts(arg1 []models.ProductVersions) {
+	var arg1Copy []models.ProductVersions
+	if arg1 != nil {
+		arg1Copy = make([]models.ProductVersions, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentProductsMutex.Lock()
+	fake.presentProductsArgsForCall = append(fake.presentProductsArgsForCall, struct {
+		arg1 []models.ProductVersions
+	}{arg1Copy})
+	fake.recordInvocation("PresentProducts", []interface{}{arg1Copy})
+	fake.presentProductsMutex.Unlock()
+	if fake.PresentProductsStub != nil {
+		fake.PresentProductsStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentProductsCallCount() int {
+	fake.presentProductsMutex.RLock()
+	defer fake.presentProductsMutex.RUnlock()
+	return len(fake.presentProductsArgsForCall)
+}
+
+func (fake *Presenter) PresentProductsCalls(stub func([]models.ProductVersions)) {
+	fake.presentProductsMutex.Lock()
+	defer fake.presentProductsMutex.Unlock()
+	fake.PresentProductsStub = stub
+}
+
+func (fake *Presenter) PresentProductsArgsForCall(i int) []models.ProductVersions {
+	fake.presentProductsMutex.RLock()
+	defer fake.presentProductsMutex.RUnlock()
+	argsForCall := fake.presentProductsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Presenter) PresentResourceConfig(arg1 map[string]api.JobProperties) {
+	fake.presentResourceConfigMutex.Lock()
+	fake.presentResourceConfigArgsForCall = append(fake.presentResourceConfigArgsForCall, struct {
+		arg1 map[string]api.JobProperties
+	}{arg1})
+	fake.recordInvocation("PresentResourceConfig", []interface{}{arg1})
+	fake.presentResourceConfigMutex.Unlock()
+	if fake.PresentResourceConfigStub != nil {
+		fake.PresentResourceConfigStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentResourceConfigCallCount() int {
+	fake.presentResourceConfigMutex.RLock()
+	defer fake.presentResourceConfigMutex.RUnlock()
+	return len(fake.presentResourceConfigArgsForCall)
+}
+
+func (fake *Presenter) PresentResourceConfigCalls(stub func(map[string]api.JobProperties)) {
+	fake.presentResourceConfigMutex.Lock()
+	defer fake.presentResourceConfigMutex.Unlock()
+	fake.PresentResourceConfigStub = stub
+}
+
+func (fake *Presenter) PresentResourceConfigArgsForCall(i int) map[string]api.JobProperties {
+	fake.presentResourceConfigMutex.RLock()
+	defer fake.presentResourceConfigMutex.RUnlock()
+	argsForCall := fake.presentResourceConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *Presenter) PresentSSLCertificate(arg1 api.SSLCertificate) {
 	fake.presentSSLCertificateMutex.Lock()
 	fake.presentSSLCertificateArgsForCall = append(fake.presentSSLCertificateArgsForCall, struct {
@@ -450,6 +665,114 @@ func (fake *Presenter) PresentStagedProductsArgsForCall(i int) []api.DiagnosticP
 	return argsForCall.arg1
 }
 
+func (fake *Presenter) PresentStemcellAssignments(arg1 []models.StemcellAssignment) {
+	var arg1Copy []models.StemcellAssignment
+	if arg1 != nil {
+		arg1Copy = make([]models.StemcellAssignment, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentStemcellAssignmentsMutex.Lock()
+	fake.presentStemcellAssignmentsArgsForCall = append(fake.presentStemcellAssignmentsArgsForCall, struct {
+		arg1 []models.StemcellAssignment
+	}{arg1Copy})
+	fake.recordInvocation("PresentStemcellAssignments", []interface{}{arg1Copy})
+	fake.presentStemcellAssignmentsMutex.Unlock()
+	if fake.PresentStemcellAssignmentsStub != nil {
+		fake.PresentStemcellAssignmentsStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentStemcellAssignmentsCallCount() int {
+	fake.presentStemcellAssignmentsMutex.RLock()
+	defer fake.presentStemcellAssignmentsMutex.RUnlock()
+	return len(fake.presentStemcellAssignmentsArgsForCall)
+}
+
+func (fake *Presenter) PresentStemcellAssignmentsCalls(stub func([]models.StemcellAssignment)) {
+	fake.presentStemcellAssignmentsMutex.Lock()
+	defer fake.presentStemcellAssignmentsMutex.Unlock()
+	fake.PresentStemcellAssignmentsStub = stub
+}
+
+func (fake *Presenter) PresentStemcellAssignmentsArgsForCall(i int) []models.StemcellAssignment {
+	fake.presentStemcellAssignmentsMutex.RLock()
+	defer fake.presentStemcellAssignmentsMutex.RUnlock()
+	argsForCall := fake.presentStemcellAssignmentsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Presenter) PresentVMExtensions(arg1 []api.VMExtension) {
+	var arg1Copy []api.VMExtension
+	if arg1 != nil {
+		arg1Copy = make([]api.VMExtension, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentVMExtensionsMutex.Lock()
+	fake.presentVMExtensionsArgsForCall = append(fake.presentVMExtensionsArgsForCall, struct {
+		arg1 []api.VMExtension
+	}{arg1Copy})
+	fake.recordInvocation("PresentVMExtensions", []interface{}{arg1Copy})
+	fake.presentVMExtensionsMutex.Unlock()
+	if fake.PresentVMExtensionsStub != nil {
+		fake.PresentVMExtensionsStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentVMExtensionsCallCount() int {
+	fake.presentVMExtensionsMutex.RLock()
+	defer fake.presentVMExtensionsMutex.RUnlock()
+	return len(fake.presentVMExtensionsArgsForCall)
+}
+
+func (fake *Presenter) PresentVMExtensionsCalls(stub func([]api.VMExtension)) {
+	fake.presentVMExtensionsMutex.Lock()
+	defer fake.presentVMExtensionsMutex.Unlock()
+	fake.PresentVMExtensionsStub = stub
+}
+
+func (fake *Presenter) PresentVMExtensionsArgsForCall(i int) []api.VMExtension {
+	fake.presentVMExtensionsMutex.RLock()
+	defer fake.presentVMExtensionsMutex.RUnlock()
+	argsForCall := fake.presentVMExtensionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Presenter) PresentVerifiers(arg1 []api.Verifier) {
+	var arg1Copy []api.Verifier
+	if arg1 != nil {
+		arg1Copy = make([]api.Verifier, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.presentVerifiersMutex.Lock()
+	fake.presentVerifiersArgsForCall = append(fake.presentVerifiersArgsForCall, struct {
+		arg1 []api.Verifier
+	}{arg1Copy})
+	fake.recordInvocation("PresentVerifiers", []interface{}{arg1Copy})
+	fake.presentVerifiersMutex.Unlock()
+	if fake.PresentVerifiersStub != nil {
+		fake.PresentVerifiersStub(arg1)
+	}
+}
+
+func (fake *Presenter) PresentVerifiersCallCount() int {
+	fake.presentVerifiersMutex.RLock()
+	defer fake.presentVerifiersMutex.RUnlock()
+	return len(fake.presentVerifiersArgsForCall)
+}
+
+func (fake *Presenter) PresentVerifiersCalls(stub func([]api.Verifier)) {
+	fake.presentVerifiersMutex.Lock()
+	defer fake.presentVerifiersMutex.Unlock()
+	fake.PresentVerifiersStub = stub
+}
+
+func (fake *Presenter) PresentVerifiersArgsForCall(i int) []api.Verifier {
+	fake.presentVerifiersMutex.RLock()
+	defer fake.presentVerifiersMutex.RUnlock()
+	argsForCall := fake.presentVerifiersArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *Presenter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -459,6 +782,8 @@ func (fake *Presenter) Invocations() map[string][][]interface{} {
 	defer fake.presentCertificateAuthoritiesMutex.RUnlock()
 	fake.presentCertificateAuthorityMutex.RLock()
 	defer fake.presentCertificateAuthorityMutex.RUnlock()
+	fake.presentCertificateRotationsMutex.RLock()
+	defer fake.presentCertificateRotationsMutex.RUnlock()
 	fake.presentCredentialReferencesMutex.RLock()
 	defer fake.presentCredentialReferencesMutex.RUnlock()
 	fake.presentCredentialsMutex.RLock()
@@ -467,14 +792,28 @@ func (fake *Presenter) Invocations() map[string][][]interface{} {
 	defer fake.presentDeployedProductsMutex.RUnlock()
 	fake.presentErrandsMutex.RLock()
 	defer fake.presentErrandsMutex.RUnlock()
+	fake.presentExpiringCertificatesMutex.RLock()
+	defer fake.presentExpiringCertificatesMutex.RUnlock()
+	fake.presentExpiringLicensesMutex.RLock()
+	defer fake.presentExpiringLicensesMutex.RUnlock()
 	fake.presentInstallationsMutex.RLock()
 	defer fake.presentInstallationsMutex.RUnlock()
 	fake.presentPendingChangesMutex.RLock()
 	defer fake.presentPendingChangesMutex.RUnlock()
+	fake.presentProductsMutex.RLock()
+	defer fake.presentProductsMutex.RUnlock()
+	fake.presentResourceConfigMutex.RLock()
+	defer fake.presentResourceConfigMutex.RUnlock()
 	fake.presentSSLCertificateMutex.RLock()
 	defer fake.presentSSLCertificateMutex.RUnlock()
 	fake.presentStagedProductsMutex.RLock()
 	defer fake.presentStagedProductsMutex.RUnlock()
+	fake.presentStemcellAssignmentsMutex.RLock()
+	defer fake.presentStemcellAssignmentsMutex.RUnlock()
+	fake.presentVMExtensionsMutex.RLock()
+	defer fake.presentVMExtensionsMutex.RUnlock()
+	fake.presentVerifiersMutex.RLock()
+	defer fake.presentVerifiersMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
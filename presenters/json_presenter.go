@@ -26,6 +26,10 @@ func (j JSONPresenter) PresentCertificateAuthorities(certificateAuthorities []ap
 	j.encodeJSON(certificateAuthorities)
 }
 
+func (j JSONPresenter) PresentCertificateRotations(rotations []api.CertificateRotation) {
+	j.encodeJSON(rotations)
+}
+
 func (j JSONPresenter) PresentCredentialReferences(credentialReferences []string) {
 	j.encodeJSON(credentialReferences)
 }
@@ -42,6 +46,14 @@ func (j JSONPresenter) PresentErrands(errands []models.Errand) {
 	j.encodeJSON(errands)
 }
 
+func (j JSONPresenter) PresentExpiringCertificates(certificates []api.ExpiringCertificate) {
+	j.encodeJSON(certificates)
+}
+
+func (j JSONPresenter) PresentExpiringLicenses(licenses []models.ExpiringLicense) {
+	j.encodeJSON(licenses)
+}
+
 func (j JSONPresenter) PresentCertificateAuthority(certificateAuthority api.CA) {
 	j.encodeJSON(certificateAuthority)
 }
@@ -58,10 +70,30 @@ func (j JSONPresenter) PresentPendingChanges(pendingChanges []api.ProductChange)
 	j.encodeJSON(pendingChanges)
 }
 
+func (j JSONPresenter) PresentProducts(products []models.ProductVersions) {
+	j.encodeJSON(products)
+}
+
 func (j JSONPresenter) PresentStagedProducts(stagedProducts []api.DiagnosticProduct) {
 	j.encodeJSON(stagedProducts)
 }
 
+func (j JSONPresenter) PresentStemcellAssignments(assignments []models.StemcellAssignment) {
+	j.encodeJSON(assignments)
+}
+
+func (j JSONPresenter) PresentResourceConfig(resourceConfig map[string]api.JobProperties) {
+	j.encodeJSON(resourceConfig)
+}
+
+func (j JSONPresenter) PresentVerifiers(verifiers []api.Verifier) {
+	j.encodeJSON(verifiers)
+}
+
+func (j JSONPresenter) PresentVMExtensions(vmExtensions []api.VMExtension) {
+	j.encodeJSON(vmExtensions)
+}
+
 func (j JSONPresenter) encodeJSON(v interface{}) {
 	b, _ := json.MarshalIndent(&v, "", "  ")
 
@@ -11,14 +11,22 @@ type Presenter interface {
 	PresentAvailableProducts([]models.Product)
 	PresentCertificateAuthorities([]api.CA)
 	PresentCertificateAuthority(api.CA)
+	PresentCertificateRotations([]api.CertificateRotation)
 	PresentSSLCertificate(api.SSLCertificate)
 	PresentCredentialReferences([]string)
 	PresentCredentials(map[string]string)
 	PresentDeployedProducts([]api.DiagnosticProduct)
 	PresentErrands([]models.Errand)
+	PresentExpiringCertificates([]api.ExpiringCertificate)
+	PresentExpiringLicenses([]models.ExpiringLicense)
 	PresentInstallations([]models.Installation)
 	PresentPendingChanges([]api.ProductChange)
+	PresentProducts([]models.ProductVersions)
+	PresentResourceConfig(map[string]api.JobProperties)
 	PresentStagedProducts([]api.DiagnosticProduct)
+	PresentStemcellAssignments([]models.StemcellAssignment)
+	PresentVerifiers([]api.Verifier)
+	PresentVMExtensions([]api.VMExtension)
 }
 
 //go:generate counterfeiter -o fakes/formatted_presenter.go --fake-name FormattedPresenter . FormattedPresenter
@@ -73,6 +81,15 @@ func (p *MultiPresenter) PresentCertificateAuthority(ca api.CA) {
 	}
 }
 
+func (p *MultiPresenter) PresentCertificateRotations(rotations []api.CertificateRotation) {
+	switch p.format {
+	case "json":
+		p.jsonPresenter.PresentCertificateRotations(rotations)
+	default:
+		p.tablePresenter.PresentCertificateRotations(rotations)
+	}
+}
+
 func (p *MultiPresenter) PresentSSLCertificate(cert api.SSLCertificate) {
 	switch p.format {
 	case "json":
@@ -118,6 +135,24 @@ func (p *MultiPresenter) PresentErrands(errands []models.Errand) {
 	}
 }
 
+func (p *MultiPresenter) PresentExpiringCertificates(certificates []api.ExpiringCertificate) {
+	switch p.format {
+	case "json":
+		p.jsonPresenter.PresentExpiringCertificates(certificates)
+	default:
+		p.tablePresenter.PresentExpiringCertificates(certificates)
+	}
+}
+
+func (p *MultiPresenter) PresentExpiringLicenses(licenses []models.ExpiringLicense) {
+	switch p.format {
+	case "json":
+		p.jsonPresenter.PresentExpiringLicenses(licenses)
+	default:
+		p.tablePresenter.PresentExpiringLicenses(licenses)
+	}
+}
+
 func (p *MultiPresenter) PresentInstallations(i []models.Installation) {
 	switch p.format {
 	case "json":
@@ -135,6 +170,15 @@ func (p *MultiPresenter) PresentPendingChanges(c []api.ProductChange) {
 	}
 }
 
+func (p *MultiPresenter) PresentProducts(products []models.ProductVersions) {
+	switch p.format {
+	case "json":
+		p.jsonPresenter.PresentProducts(products)
+	default:
+		p.tablePresenter.PresentProducts(products)
+	}
+}
+
 func (p *MultiPresenter) PresentStagedProducts(products []api.DiagnosticProduct) {
 	switch p.format {
 	case "json":
@@ -143,3 +187,39 @@ func (p *MultiPresenter) PresentStagedProducts(products []api.DiagnosticProduct)
 		p.tablePresenter.PresentStagedProducts(products)
 	}
 }
+
+func (p *MultiPresenter) PresentStemcellAssignments(assignments []models.StemcellAssignment) {
+	switch p.format {
+	case "json":
+		p.jsonPresenter.PresentStemcellAssignments(assignments)
+	default:
+		p.tablePresenter.PresentStemcellAssignments(assignments)
+	}
+}
+
+func (p *MultiPresenter) PresentVerifiers(verifiers []api.Verifier) {
+	switch p.format {
+	case "json":
+		p.jsonPresenter.PresentVerifiers(verifiers)
+	default:
+		p.tablePresenter.PresentVerifiers(verifiers)
+	}
+}
+
+func (p *MultiPresenter) PresentVMExtensions(vmExtensions []api.VMExtension) {
+	switch p.format {
+	case "json":
+		p.jsonPresenter.PresentVMExtensions(vmExtensions)
+	default:
+		p.tablePresenter.PresentVMExtensions(vmExtensions)
+	}
+}
+
+func (p *MultiPresenter) PresentResourceConfig(resourceConfig map[string]api.JobProperties) {
+	switch p.format {
+	case "json":
+		p.jsonPresenter.PresentResourceConfig(resourceConfig)
+	default:
+		p.tablePresenter.PresentResourceConfig(resourceConfig)
+	}
+}
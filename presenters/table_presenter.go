@@ -1,8 +1,11 @@
 package presenters
 
 import (
+	"encoding/json"
+	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
@@ -53,6 +56,17 @@ func (t TablePresenter) PresentCertificateAuthorities(certificateAuthorities []a
 	t.tableWriter.Render()
 }
 
+func (t TablePresenter) PresentCertificateRotations(rotations []api.CertificateRotation) {
+	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
+	t.tableWriter.SetHeader([]string{"type", "phase", "apply changes required", "updated at"})
+
+	for _, rotation := range rotations {
+		t.tableWriter.Append([]string{rotation.Type, rotation.Phase, strconv.FormatBool(rotation.ApplyChangesRequired), rotation.UpdatedAt})
+	}
+
+	t.tableWriter.Render()
+}
+
 func (t TablePresenter) PresentCredentialReferences(credentialReferences []string) {
 	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
 	t.tableWriter.SetHeader([]string{"Credentials"})
@@ -169,6 +183,148 @@ func (t TablePresenter) PresentStagedProducts(stagedProducts []api.DiagnosticPro
 	t.tableWriter.Render()
 }
 
+func (t TablePresenter) PresentProducts(products []models.ProductVersions) {
+	t.tableWriter.SetHeader([]string{"NAME", "AVAILABLE", "STAGED", "DEPLOYED"})
+
+	for _, product := range products {
+		t.tableWriter.Append([]string{
+			product.Name,
+			strings.Join(product.Available, ", "),
+			product.Staged,
+			product.Deployed,
+		})
+	}
+
+	t.tableWriter.Render()
+}
+
+func (t TablePresenter) PresentExpiringCertificates(certificates []api.ExpiringCertificate) {
+	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
+	t.tableWriter.SetAutoWrapText(false)
+	t.tableWriter.SetHeader([]string{"PRODUCT", "REFERENCE", "LOCATION", "CA", "CONFIGURABLE", "VALID UNTIL"})
+
+	for _, certificate := range certificates {
+		reference := certificate.PropertyReference
+		if reference == "" {
+			reference = certificate.VariablePath
+		}
+
+		t.tableWriter.Append([]string{
+			certificate.ProductGUID,
+			reference,
+			certificate.Location,
+			strconv.FormatBool(certificate.IsCA),
+			strconv.FormatBool(certificate.Configurable),
+			certificate.ValidUntil,
+		})
+	}
+
+	t.tableWriter.Render()
+}
+
+func (t TablePresenter) PresentExpiringLicenses(licenses []models.ExpiringLicense) {
+	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
+	t.tableWriter.SetHeader([]string{"PRODUCT", "GUID", "STATE", "LICENSE", "EXPIRES AT"})
+
+	for _, license := range licenses {
+		t.tableWriter.Append([]string{
+			license.ProductName,
+			license.GUID,
+			strings.Join(license.ProductState, ", "),
+			license.PropertyReference,
+			license.ExpiresAt.Format("2006-01-02"),
+		})
+	}
+
+	t.tableWriter.Render()
+}
+
+func (t TablePresenter) PresentStemcellAssignments(assignments []models.StemcellAssignment) {
+	t.tableWriter.SetHeader([]string{"PRODUCT", "REQUIRED STEMCELL", "STAGED STEMCELL", "COMPATIBLE STEMCELL UPLOADED", "AVAILABLE STEMCELLS"})
+
+	for _, assignment := range assignments {
+		requiredStemcell := assignment.RequiredStemcellVersion
+		if assignment.RequiredStemcellOS != "" {
+			requiredStemcell = fmt.Sprintf("%s %s", assignment.RequiredStemcellOS, assignment.RequiredStemcellVersion)
+		}
+
+		t.tableWriter.Append([]string{
+			assignment.ProductName,
+			requiredStemcell,
+			assignment.StagedStemcellVersion,
+			strconv.FormatBool(assignment.CompatibleStemcellUploaded),
+			strings.Join(assignment.AvailableStemcellVersions, ", "),
+		})
+	}
+
+	t.tableWriter.Render()
+}
+
+func (t TablePresenter) PresentVerifiers(verifiers []api.Verifier) {
+	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
+	t.tableWriter.SetHeader([]string{"TYPE", "ENABLED"})
+
+	for _, verifier := range verifiers {
+		t.tableWriter.Append([]string{verifier.Type, strconv.FormatBool(verifier.Enabled)})
+	}
+
+	t.tableWriter.Render()
+}
+
+func (t TablePresenter) PresentVMExtensions(vmExtensions []api.VMExtension) {
+	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
+	t.tableWriter.SetAutoWrapText(false)
+	t.tableWriter.SetHeader([]string{"NAME", "CLOUD PROPERTIES"})
+
+	for _, vmExtension := range vmExtensions {
+		cloudProperties := []byte("{}")
+		if len(vmExtension.CloudProperties) > 0 {
+			cloudProperties, _ = json.Marshal(vmExtension.CloudProperties)
+		}
+		t.tableWriter.Append([]string{vmExtension.Name, string(cloudProperties)})
+	}
+
+	t.tableWriter.Render()
+}
+
+func (t TablePresenter) PresentResourceConfig(resourceConfig map[string]api.JobProperties) {
+	t.tableWriter.SetAlignment(tablewriter.ALIGN_LEFT)
+	t.tableWriter.SetAutoWrapText(false)
+	t.tableWriter.SetHeader([]string{"JOB", "INSTANCES", "INSTANCE TYPE", "PERSISTENT DISK MB", "INTERNET CONNECTED", "ADDITIONAL VM EXTENSIONS"})
+
+	var jobNames []string
+	for jobName := range resourceConfig {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	for _, jobName := range jobNames {
+		jobProperties := resourceConfig[jobName]
+
+		var instances, persistentDisk, internetConnected string
+		if jobProperties.Instances != nil {
+			instances = fmt.Sprint(jobProperties.Instances)
+		}
+		if jobProperties.PersistentDisk != nil {
+			persistentDisk = jobProperties.PersistentDisk.Size
+		}
+		if jobProperties.InternetConnected != nil {
+			internetConnected = strconv.FormatBool(*jobProperties.InternetConnected)
+		}
+
+		t.tableWriter.Append([]string{
+			jobName,
+			instances,
+			jobProperties.InstanceType.ID,
+			persistentDisk,
+			internetConnected,
+			strings.Join(jobProperties.AdditionalVMExtensions, ", "),
+		})
+	}
+
+	t.tableWriter.Render()
+}
+
 func sortCredentialMap(cm map[string]string) ([]string, []string) {
 	var header []string
 	var credential []string
@@ -224,6 +224,157 @@ var _ = Describe("TablePresenter", func() {
 		})
 	})
 
+	Describe("PresentProducts", func() {
+		It("creates a table", func() {
+			tablePresenter.PresentProducts([]models.ProductVersions{
+				{Name: "cf", Available: []string{"2.2.1", "2.3.0"}, Staged: "2.3.0", Deployed: "2.2.1"},
+				{Name: "p-redis", Available: []string{"1.14.0"}},
+			})
+
+			Expect(fakeTableWriter.SetHeaderCallCount()).To(Equal(1))
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"NAME", "AVAILABLE", "STAGED", "DEPLOYED"}))
+
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"cf", "2.2.1, 2.3.0", "2.3.0", "2.2.1"}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"p-redis", "1.14.0", "", ""}))
+
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("PresentExpiringCertificates", func() {
+		It("creates a table, with the variable path of the certificates that are not properties", func() {
+			tablePresenter.PresentExpiringCertificates([]api.ExpiringCertificate{
+				{
+					IsCA:              true,
+					PropertyReference: ".properties.root_ca",
+					ProductGUID:       "p-bosh-guid",
+					Location:          "ops_manager",
+					ValidUntil:        "2021-01-09T19:27:16Z",
+				},
+				{
+					Configurable: true,
+					ProductGUID:  "cf-guid",
+					Location:     "credhub",
+					VariablePath: "/opsmgr/cf-guid/uaa/service_provider_credentials",
+					ValidUntil:   "2020-01-09T19:27:16Z",
+				},
+			})
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"PRODUCT", "REFERENCE", "LOCATION", "CA", "CONFIGURABLE", "VALID UNTIL"}))
+
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"p-bosh-guid", ".properties.root_ca", "ops_manager", "true", "false", "2021-01-09T19:27:16Z"}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"cf-guid", "/opsmgr/cf-guid/uaa/service_provider_credentials", "credhub", "false", "true", "2020-01-09T19:27:16Z"}))
+
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("PresentExpiringLicenses", func() {
+		It("creates a table", func() {
+			tablePresenter.PresentExpiringLicenses([]models.ExpiringLicense{
+				{
+					ProductName:       "cf",
+					GUID:              "cf-guid",
+					ProductState:      []string{"deployed", "staged"},
+					PropertyReference: ".properties.license_key",
+					ExpiresAt:         time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC),
+				},
+			})
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"PRODUCT", "GUID", "STATE", "LICENSE", "EXPIRES AT"}))
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(1))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"cf", "cf-guid", "deployed, staged", ".properties.license_key", "2020-01-31"}))
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("PresentVerifiers", func() {
+		It("creates a table", func() {
+			tablePresenter.PresentVerifiers([]api.Verifier{
+				{Type: "NetworksPingableVerifier", Enabled: true},
+				{Type: "IaasConfigurationVerifier", Enabled: false},
+			})
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"TYPE", "ENABLED"}))
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"NetworksPingableVerifier", "true"}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"IaasConfigurationVerifier", "false"}))
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("PresentResourceConfig", func() {
+		It("creates a table sorted by job", func() {
+			internetConnected := false
+			tablePresenter.PresentResourceConfig(map[string]api.JobProperties{
+				"router": {
+					Instances:              float64(3),
+					InstanceType:           api.InstanceType{ID: "automatic"},
+					InternetConnected:      &internetConnected,
+					AdditionalVMExtensions: []string{"web-lb", "public-ip"},
+				},
+				"database": {
+					Instances:      "automatic",
+					InstanceType:   api.InstanceType{ID: "xlarge"},
+					PersistentDisk: &api.Disk{Size: "102400"},
+				},
+			})
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"JOB", "INSTANCES", "INSTANCE TYPE", "PERSISTENT DISK MB", "INTERNET CONNECTED", "ADDITIONAL VM EXTENSIONS"}))
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"database", "automatic", "xlarge", "102400", "", ""}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"router", "3", "automatic", "", "false", "web-lb, public-ip"}))
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("PresentVMExtensions", func() {
+		It("creates a table with the cloud properties as json", func() {
+			tablePresenter.PresentVMExtensions([]api.VMExtension{
+				{Name: "web-lb", CloudProperties: map[string]interface{}{"lb_target_groups": []string{"web"}}},
+				{Name: "no-properties"},
+			})
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"NAME", "CLOUD PROPERTIES"}))
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"web-lb", `{"lb_target_groups":["web"]}`}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"no-properties", "{}"}))
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("PresentStemcellAssignments", func() {
+		It("creates a table", func() {
+			tablePresenter.PresentStemcellAssignments([]models.StemcellAssignment{
+				{
+					ProductName:                "cf",
+					RequiredStemcellOS:         "ubuntu-xenial",
+					RequiredStemcellVersion:    "97.18",
+					StagedStemcellVersion:      "97.28",
+					AvailableStemcellVersions:  []string{"97.28", "97.57"},
+					CompatibleStemcellUploaded: true,
+				},
+				{
+					ProductName:                "p-redis",
+					RequiredStemcellVersion:    "170.15",
+					AvailableStemcellVersions:  []string{},
+					CompatibleStemcellUploaded: false,
+				},
+			})
+
+			Expect(fakeTableWriter.SetHeaderCallCount()).To(Equal(1))
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"PRODUCT", "REQUIRED STEMCELL", "STAGED STEMCELL", "COMPATIBLE STEMCELL UPLOADED", "AVAILABLE STEMCELLS"}))
+
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"cf", "ubuntu-xenial 97.18", "97.28", "true", "97.28, 97.57"}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"p-redis", "170.15", "", "false", ""}))
+
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
 	Describe("PresentPendingChanges", func() {
 		var pendingChanges []api.ProductChange
 		BeforeEach(func() {
@@ -321,6 +472,21 @@ var _ = Describe("TablePresenter", func() {
 		})
 	})
 
+	Describe("PresentCertificateRotations", func() {
+		It("creates a table", func() {
+			tablePresenter.PresentCertificateRotations([]api.CertificateRotation{
+				{Type: "nats", Phase: "created", ApplyChangesRequired: true, UpdatedAt: "2019-01-09T10:00:00Z"},
+				{Type: "bosh-dns", Phase: "none"},
+			})
+
+			Expect(fakeTableWriter.SetHeaderArgsForCall(0)).To(Equal([]string{"type", "phase", "apply changes required", "updated at"}))
+			Expect(fakeTableWriter.AppendCallCount()).To(Equal(2))
+			Expect(fakeTableWriter.AppendArgsForCall(0)).To(Equal([]string{"nats", "created", "true", "2019-01-09T10:00:00Z"}))
+			Expect(fakeTableWriter.AppendArgsForCall(1)).To(Equal([]string{"bosh-dns", "none", "false", ""}))
+			Expect(fakeTableWriter.RenderCallCount()).To(Equal(1))
+		})
+	})
+
 	Describe("PresentCertificateAuthorities", func() {
 		var certificateAuthorities []api.CA
 		BeforeEach(func() {
@@ -0,0 +1,53 @@
+package progress
+
+import (
+	"io"
+	"sync"
+)
+
+// LabelledWriter prefixes every line written to it with a label, so that the
+// progress of concurrent downloads sharing an output can be told apart. The
+// writers sharing an output must share the lock, so their lines do not mix.
+type LabelledWriter struct {
+	out         io.Writer
+	lock        sync.Locker
+	label       string
+	atLineStart bool
+}
+
+func NewLabelledWriter(out io.Writer, lock sync.Locker, label string) *LabelledWriter {
+	return &LabelledWriter{
+		out:         out,
+		lock:        lock,
+		label:       label,
+		atLineStart: true,
+	}
+}
+
+// Write writes the label at the start of every line, including the lines that
+// a progress bar redraws after a carriage return.
+func (w *LabelledWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var labelled []byte
+	for _, b := range p {
+		if w.atLineStart && b != '\r' && b != '\n' {
+			labelled = append(labelled, w.label...)
+			w.atLineStart = false
+		}
+
+		labelled = append(labelled, b)
+
+		if b == '\r' || b == '\n' {
+			w.atLineStart = true
+		}
+	}
+
+	_, err := w.out.Write(labelled)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,84 @@
+package progress_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/pivotal-cf/om/progress"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+var _ = Describe("LabelledWriter", func() {
+	var (
+		out  *bytes.Buffer
+		lock *sync.Mutex
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		lock = &sync.Mutex{}
+	})
+
+	It("prefixes every line with the label", func() {
+		writer := progress.NewLabelledWriter(out, lock, "[product] ")
+
+		n, err := writer.Write([]byte("first line\nsecond "))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(18))
+
+		_, err = writer.Write([]byte("line\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).To(Equal("[product] first line\n[product] second line\n"))
+	})
+
+	It("prefixes the lines that are redrawn after a carriage return", func() {
+		writer := progress.NewLabelledWriter(out, lock, "[stemcell] ")
+
+		_, err := writer.Write([]byte("\r10%"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = writer.Write([]byte("\r20%"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.String()).To(Equal("\r[stemcell] 10%\r[stemcell] 20%"))
+	})
+
+	It("keeps the lines of the writers sharing an output apart", func() {
+		product := progress.NewLabelledWriter(out, lock, "[product] ")
+		stemcell := progress.NewLabelledWriter(out, lock, "[stemcell] ")
+
+		var wg sync.WaitGroup
+		for _, writer := range []*progress.LabelledWriter{product, stemcell} {
+			wg.Add(1)
+			go func(writer *progress.LabelledWriter) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					_, _ = writer.Write([]byte("progress\n"))
+				}
+			}(writer)
+		}
+		wg.Wait()
+
+		lines := bytes.Split(bytes.TrimSuffix(out.Bytes(), []byte("\n")), []byte("\n"))
+		Expect(lines).To(HaveLen(200))
+		for _, line := range lines {
+			Expect(string(line)).To(Or(Equal("[product] progress"), Equal("[stemcell] progress")))
+		}
+	})
+
+	It("returns the error of the output", func() {
+		writer := progress.NewLabelledWriter(failingWriter{}, lock, "[product] ")
+
+		_, err := writer.Write([]byte("progress"))
+		Expect(err).To(MatchError("disk full"))
+	})
+})
@@ -36,7 +36,9 @@ func (b Bar) SetTotal64(size int64) {
 }
 
 func (b *Bar) Reset() {
+	output := b.bar.Output
 	b.bar = NewBar().bar
+	b.bar.Output = output
 }
 
 func (b Bar) SetOutput(writer io.Writer) {
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// Runner runs the executables that om hands the terminal over to, such as ssh,
+// and the command line interfaces om drives, such as the ones of the IaaSes
+type Runner interface {
+	Run(name string, args []string, env []string) error
+	Output(name string, args []string, env []string) (string, error)
+}
+
+type runner struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// New creates a runner whose executables read from stdin and write to stdout and stderr
+func New(stdin io.Reader, stdout, stderr io.Writer) Runner {
+	return &runner{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// Run runs the executable, with the environment of om and the given variables, and waits for it to exit
+func (r *runner) Run(name string, args []string, env []string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = r.stdin
+	cmd.Stdout = r.stdout
+	cmd.Stderr = r.stderr
+
+	return cmd.Run()
+}
+
+// Output runs the executable like Run, without stdin, and returns what it
+// writes to stdout instead of writing it to the stdout of the runner
+func (r *runner) Output(name string, args []string, env []string) (string, error) {
+	var stdout bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = r.stderr
+
+	err := cmd.Run()
+
+	return stdout.String(), err
+}
@@ -0,0 +1,60 @@
+package runner_test
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/runner"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	})
+
+	It("runs the executable with its arguments, stdin and the extra environment", func() {
+		r := runner.New(strings.NewReader("some-input"), stdout, stderr)
+
+		err := r.Run("sh", []string{"-c", `cat; echo " $SOME_VARIABLE"; echo some-error >&2`}, []string{"SOME_VARIABLE=some-value"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(stdout.String()).To(Equal("some-input some-value\n"))
+		Expect(stderr.String()).To(Equal("some-error\n"))
+	})
+
+	It("returns the exit error of the executable", func() {
+		r := runner.New(strings.NewReader(""), stdout, stderr)
+
+		err := r.Run("sh", []string{"-c", "exit 3"}, nil)
+		Expect(err).To(BeAssignableToTypeOf(&exec.ExitError{}))
+		Expect(err).To(MatchError("exit status 3"))
+	})
+
+	It("returns the output of the executable", func() {
+		r := runner.New(strings.NewReader("some-input"), stdout, stderr)
+
+		output, err := r.Output("sh", []string{"-c", `echo "$SOME_VARIABLE"; echo some-error >&2`}, []string{"SOME_VARIABLE=some-value"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output).To(Equal("some-value\n"))
+		Expect(stdout.String()).To(BeEmpty())
+		Expect(stderr.String()).To(Equal("some-error\n"))
+	})
+
+	It("returns the output and the exit error of the executable", func() {
+		r := runner.New(strings.NewReader(""), stdout, stderr)
+
+		output, err := r.Output("sh", []string{"-c", "echo some-output; exit 3"}, nil)
+		Expect(err).To(MatchError("exit status 3"))
+		Expect(output).To(Equal("some-output\n"))
+	})
+})
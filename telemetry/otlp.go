@@ -0,0 +1,222 @@
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// the OTLP/JSON payloads, see
+// https://github.com/open-telemetry/opentelemetry-proto/tree/main/opentelemetry/proto
+
+const (
+	spanKindInternal                 = 1
+	statusCodeOK                     = 1
+	statusCodeError                  = 2
+	aggregationTemporalityCumulative = 2
+)
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsInt             *string        `json:"asInt,omitempty"`
+	AsDouble          *float64       `json:"asDouble,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+type otlpMetric struct {
+	Name string  `json:"name"`
+	Unit string  `json:"unit,omitempty"`
+	Sum  otlpSum `json:"sum"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpMetrics struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+func (r *recorder) resource() otlpResource {
+	attributes := Attributes{"service.name": r.config.ServiceName}
+	if r.config.ServiceVersion != "" {
+		attributes["service.version"] = r.config.ServiceVersion
+	}
+	return otlpResource{Attributes: otlpAttributes(attributes)}
+}
+
+func (r *recorder) scope() otlpScope {
+	return otlpScope{Name: scopeName, Version: r.config.ServiceVersion}
+}
+
+func (r *recorder) traces(spans []*Span) otlpTraces {
+	var otlpSpans []otlpSpan
+	for _, span := range spans {
+		span.mutex.Lock()
+		status := otlpStatus{Code: statusCodeOK}
+		if span.err != nil {
+			status = otlpStatus{Code: statusCodeError, Message: span.err.Error()}
+		}
+
+		otlpSpans = append(otlpSpans, otlpSpan{
+			TraceID:           r.traceID,
+			SpanID:            span.id,
+			ParentSpanID:      span.parentID,
+			Name:              span.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(span.start),
+			EndTimeUnixNano:   unixNano(span.end),
+			Attributes:        otlpAttributes(span.attributes),
+			Status:            status,
+		})
+		span.mutex.Unlock()
+	}
+
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   r.resource(),
+		ScopeSpans: []otlpScopeSpans{{Scope: r.scope(), Spans: otlpSpans}},
+	}}}
+}
+
+func (r *recorder) metrics(counters []*counter) otlpMetrics {
+	now := unixNano(time.Now())
+
+	var metrics []otlpMetric
+	byName := map[string]int{}
+	for _, c := range counters {
+		i, ok := byName[c.name]
+		if !ok {
+			metrics = append(metrics, otlpMetric{
+				Name: c.name,
+				Unit: c.unit,
+				Sum:  otlpSum{AggregationTemporality: aggregationTemporalityCumulative, IsMonotonic: true},
+			})
+
+			i = len(metrics) - 1
+			byName[c.name] = i
+		}
+
+		point := otlpDataPoint{
+			Attributes:        otlpAttributes(c.attributes),
+			StartTimeUnixNano: unixNano(r.started),
+			TimeUnixNano:      now,
+		}
+		if c.isDouble {
+			double := c.double
+			point.AsDouble = &double
+		} else {
+			value := strconv.FormatInt(c.value, 10)
+			point.AsInt = &value
+		}
+
+		metrics[i].Sum.DataPoints = append(metrics[i].Sum.DataPoints, point)
+	}
+
+	return otlpMetrics{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     r.resource(),
+		ScopeMetrics: []otlpScopeMetrics{{Scope: r.scope(), Metrics: metrics}},
+	}}}
+}
+
+func otlpAttributes(attributes Attributes) []otlpKeyValue {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := []otlpKeyValue{}
+	for _, key := range keys {
+		var value otlpValue
+		switch v := attributes[key].(type) {
+		case bool:
+			value.BoolValue = &v
+		case int:
+			i := strconv.Itoa(v)
+			value.IntValue = &i
+		case int64:
+			i := strconv.FormatInt(v, 10)
+			value.IntValue = &i
+		case float64:
+			value.DoubleValue = &v
+		case string:
+			value.StringValue = &v
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+		values = append(values, otlpKeyValue{Key: key, Value: value})
+	}
+
+	return values
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,315 @@
+// Package telemetry records the spans and the counters of the long-running
+// operations of om, such as downloads, uploads and installations, and exports
+// them to an OpenTelemetry collector with OTLP over HTTP, in its JSON encoding.
+//
+// Nothing is recorded until Configure is called with an endpoint.
+package telemetry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const scopeName = "github.com/pivotal-cf/om"
+
+// Attributes describe a span or a data point of a counter. The values are
+// strings, bools, ints or float64s.
+type Attributes map[string]interface{}
+
+type Config struct {
+	// Endpoint is the base URL of the collector, e.g. http://localhost:4318,
+	// that /v1/traces and /v1/metrics are posted to.
+	Endpoint       string
+	Headers        map[string]string
+	ServiceName    string
+	ServiceVersion string
+}
+
+type recorder struct {
+	config  Config
+	client  *http.Client
+	traceID string
+	started time.Time
+	root    *Span
+
+	mutex    sync.Mutex
+	spans    []*Span
+	counters map[string]*counter
+}
+
+type counter struct {
+	name       string
+	unit       string
+	attributes Attributes
+	value      int64
+	double     float64
+	isDouble   bool
+}
+
+var (
+	current *recorder
+	lock    sync.Mutex
+)
+
+// Configure enables the recording of the spans and the counters, to be
+// exported to the endpoint by Flush.
+func Configure(config Config) {
+	if config.ServiceName == "" {
+		config.ServiceName = "om"
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	current = &recorder{
+		config:   config,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		traceID:  randomID(16),
+		started:  time.Now(),
+		counters: map[string]*counter{},
+	}
+}
+
+// Reset disables the recording, dropping what has not been flushed.
+func Reset() {
+	lock.Lock()
+	defer lock.Unlock()
+
+	current = nil
+}
+
+func active() *recorder {
+	lock.Lock()
+	defer lock.Unlock()
+
+	return current
+}
+
+// Span is an operation, timed from its start to its end.
+type Span struct {
+	recorder   *recorder
+	id         string
+	parentID   string
+	name       string
+	attributes Attributes
+	start      time.Time
+	end        time.Time
+	err        error
+	mutex      sync.Mutex
+}
+
+// StartRoot starts the span that the spans started after it are part of,
+// i.e. the om command that is run.
+func StartRoot(name string, attributes Attributes) *Span {
+	span := Start(name, attributes)
+	if span.recorder != nil {
+		span.recorder.mutex.Lock()
+		span.recorder.root = span
+		span.recorder.mutex.Unlock()
+	}
+	return span
+}
+
+// Start starts a span, that does nothing when the recording is not enabled.
+func Start(name string, attributes Attributes) *Span {
+	r := active()
+	if r == nil {
+		return &Span{}
+	}
+
+	span := &Span{
+		recorder:   r,
+		id:         randomID(8),
+		name:       name,
+		attributes: Attributes{},
+		start:      time.Now(),
+	}
+	for key, value := range attributes {
+		span.attributes[key] = value
+	}
+
+	r.mutex.Lock()
+	if r.root != nil {
+		span.parentID = r.root.id
+	}
+	r.mutex.Unlock()
+
+	return span
+}
+
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s.recorder == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.attributes[key] = value
+}
+
+// End ends the span, which has failed with the error when it is not nil, and
+// records its duration in the om.operation.duration counter.
+func (s *Span) End(err error) {
+	if s.recorder == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	s.end = time.Now()
+	s.err = err
+	s.mutex.Unlock()
+
+	status := "succeeded"
+	if err != nil {
+		status = "failed"
+	}
+
+	r := s.recorder
+	r.mutex.Lock()
+	r.spans = append(r.spans, s)
+	r.mutex.Unlock()
+
+	r.add("om.operation.duration", "s", Attributes{"operation": s.name, "status": status}, 0, s.end.Sub(s.start).Seconds(), true)
+}
+
+// Count adds the value to the counter, e.g. the bytes that are transferred.
+func Count(name, unit string, value int64, attributes Attributes) {
+	r := active()
+	if r == nil {
+		return
+	}
+
+	r.add(name, unit, attributes, value, 0, false)
+}
+
+func (r *recorder) add(name, unit string, attributes Attributes, value int64, double float64, isDouble bool) {
+	key := name + "\x00" + attributesKey(attributes)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c, ok := r.counters[key]
+	if !ok {
+		c = &counter{name: name, unit: unit, attributes: attributes, isDouble: isDouble}
+		r.counters[key] = c
+	}
+	c.value += value
+	c.double += double
+}
+
+// Flush exports the spans that have ended and the counters to the endpoint.
+func Flush() error {
+	r := active()
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	spans := r.spans
+	r.spans = nil
+	counters := make([]*counter, 0, len(r.counters))
+	for _, c := range r.counters {
+		counters = append(counters, c)
+	}
+	r.mutex.Unlock()
+
+	sort.Slice(counters, func(i, j int) bool {
+		if counters[i].name != counters[j].name {
+			return counters[i].name < counters[j].name
+		}
+		return attributesKey(counters[i].attributes) < attributesKey(counters[j].attributes)
+	})
+
+	var errs []string
+	if len(spans) > 0 {
+		if err := r.post("/v1/traces", r.traces(spans)); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(counters) > 0 {
+		if err := r.post("/v1/metrics", r.metrics(counters)); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("could not export the telemetry: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (r *recorder) post(path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err // un-tested
+	}
+
+	url := strings.TrimSuffix(r.config.Endpoint, "/") + path
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range r.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: unexpected response %d", url, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ParseHeaders parses the headers of OTEL_EXPORTER_OTLP_HEADERS, which are a
+// comma separated list of key=value pairs.
+func ParseHeaders(value string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("could not parse the header %q, expected key=value", pair)
+		}
+
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return headers, nil
+}
+
+func randomID(size int) string {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func attributesKey(attributes Attributes) string {
+	var pairs []string
+	for key, value := range attributes {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
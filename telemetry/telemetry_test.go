@@ -0,0 +1,178 @@
+package telemetry_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/om/telemetry"
+)
+
+var _ = Describe("telemetry", func() {
+	var (
+		server   *httptest.Server
+		requests map[string]map[string]interface{}
+		headers  http.Header
+		status   int
+	)
+
+	BeforeEach(func() {
+		requests = map[string]map[string]interface{}{}
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(req.Method).To(Equal("POST"))
+			Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+			headers = req.Header
+
+			var payload map[string]interface{}
+			Expect(json.NewDecoder(req.Body).Decode(&payload)).To(Succeed())
+			requests[req.URL.Path] = payload
+
+			w.WriteHeader(status)
+		}))
+
+		telemetry.Configure(telemetry.Config{
+			Endpoint:       server.URL + "/",
+			Headers:        map[string]string{"Api-Key": "some-key"},
+			ServiceVersion: "1.2.3",
+		})
+	})
+
+	AfterEach(func() {
+		telemetry.Reset()
+		server.Close()
+	})
+
+	// at digs into the decoded payload, e.g. at(payload, "resourceSpans", 0, "resource")
+	at := func(value interface{}, path ...interface{}) interface{} {
+		for _, key := range path {
+			switch k := key.(type) {
+			case string:
+				Expect(value).To(HaveKey(k))
+				value = value.(map[string]interface{})[k]
+			case int:
+				Expect(len(value.([]interface{}))).To(BeNumerically(">", k))
+				value = value.([]interface{})[k]
+			}
+		}
+		return value
+	}
+
+	It("exports the spans to /v1/traces, as part of the root span", func() {
+		root := telemetry.StartRoot("download-product", telemetry.Attributes{"om.command": "download-product"})
+		span := telemetry.Start("download", telemetry.Attributes{"file": "some-product.pivotal"})
+		span.SetAttribute("retried", true)
+		span.End(errors.New("some download error"))
+		root.End(nil)
+
+		Expect(telemetry.Flush()).To(Succeed())
+
+		Expect(headers.Get("Api-Key")).To(Equal("some-key"))
+
+		resourceSpans := at(requests["/v1/traces"], "resourceSpans", 0)
+		Expect(at(resourceSpans, "resource", "attributes")).To(Equal([]interface{}{
+			map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "om"}},
+			map[string]interface{}{"key": "service.version", "value": map[string]interface{}{"stringValue": "1.2.3"}},
+		}))
+		Expect(at(resourceSpans, "scopeSpans", 0, "scope")).To(Equal(map[string]interface{}{"name": "github.com/pivotal-cf/om", "version": "1.2.3"}))
+
+		spans := at(resourceSpans, "scopeSpans", 0, "spans").([]interface{})
+		Expect(spans).To(HaveLen(2))
+
+		download, command := spans[0].(map[string]interface{}), spans[1].(map[string]interface{})
+		Expect(download["name"]).To(Equal("download"))
+		Expect(download["traceId"]).To(MatchRegexp(`^[0-9a-f]{32}$`))
+		Expect(download["spanId"]).To(MatchRegexp(`^[0-9a-f]{16}$`))
+		Expect(download["parentSpanId"]).To(Equal(command["spanId"]))
+		Expect(download["traceId"]).To(Equal(command["traceId"]))
+		Expect(download["startTimeUnixNano"]).To(MatchRegexp(`^\d+$`))
+		Expect(download["status"]).To(Equal(map[string]interface{}{"code": float64(2), "message": "some download error"}))
+		Expect(download["attributes"]).To(Equal([]interface{}{
+			map[string]interface{}{"key": "file", "value": map[string]interface{}{"stringValue": "some-product.pivotal"}},
+			map[string]interface{}{"key": "retried", "value": map[string]interface{}{"boolValue": true}},
+		}))
+
+		Expect(command["name"]).To(Equal("download-product"))
+		Expect(command).NotTo(HaveKey("parentSpanId"))
+		Expect(command["status"]).To(Equal(map[string]interface{}{"code": float64(1)}))
+	})
+
+	It("exports the counters to /v1/metrics, summed by attributes", func() {
+		telemetry.Count("om.download.bytes", "By", 100, telemetry.Attributes{"file": "a.pivotal"})
+		telemetry.Count("om.download.bytes", "By", 50, telemetry.Attributes{"file": "a.pivotal"})
+		telemetry.Count("om.download.bytes", "By", 7, telemetry.Attributes{"file": "b.tgz"})
+		telemetry.Start("apply-changes", nil).End(nil)
+
+		Expect(telemetry.Flush()).To(Succeed())
+
+		metrics := at(requests["/v1/metrics"], "resourceMetrics", 0, "scopeMetrics", 0, "metrics").([]interface{})
+		Expect(metrics).To(HaveLen(2))
+
+		bytes := metrics[0].(map[string]interface{})
+		Expect(bytes["name"]).To(Equal("om.download.bytes"))
+		Expect(bytes["unit"]).To(Equal("By"))
+		Expect(at(bytes, "sum", "isMonotonic")).To(BeTrue())
+		Expect(at(bytes, "sum", "aggregationTemporality")).To(Equal(float64(2)))
+
+		points := at(bytes, "sum", "dataPoints").([]interface{})
+		Expect(points).To(HaveLen(2))
+		Expect(at(points, 0, "asInt")).To(Equal("150"))
+		Expect(at(points, 0, "attributes", 0, "value", "stringValue")).To(Equal("a.pivotal"))
+		Expect(at(points, 1, "asInt")).To(Equal("7"))
+
+		duration := metrics[1].(map[string]interface{})
+		Expect(duration["name"]).To(Equal("om.operation.duration"))
+		Expect(at(duration, "sum", "dataPoints", 0, "asDouble")).To(BeNumerically(">=", 0))
+		Expect(at(duration, "sum", "dataPoints", 0, "attributes")).To(Equal([]interface{}{
+			map[string]interface{}{"key": "operation", "value": map[string]interface{}{"stringValue": "apply-changes"}},
+			map[string]interface{}{"key": "status", "value": map[string]interface{}{"stringValue": "succeeded"}},
+		}))
+	})
+
+	It("does not export the spans twice", func() {
+		telemetry.Start("apply-changes", nil).End(nil)
+		Expect(telemetry.Flush()).To(Succeed())
+
+		requests = map[string]map[string]interface{}{}
+		Expect(telemetry.Flush()).To(Succeed())
+		Expect(requests).NotTo(HaveKey("/v1/traces"))
+	})
+
+	It("returns an error when the collector does not accept the telemetry", func() {
+		status = http.StatusBadRequest
+		telemetry.Start("apply-changes", nil).End(nil)
+
+		err := telemetry.Flush()
+		Expect(err).To(MatchError(ContainSubstring("could not export the telemetry: POST " + server.URL + "/v1/traces: unexpected response 400")))
+	})
+
+	It("records nothing when it is not configured", func() {
+		telemetry.Reset()
+
+		span := telemetry.Start("apply-changes", nil)
+		span.SetAttribute("products", "cf")
+		span.End(errors.New("some error"))
+		telemetry.Count("om.http.retries", "", 1, nil)
+
+		Expect(telemetry.Flush()).To(Succeed())
+		Expect(requests).To(BeEmpty())
+	})
+
+	Describe("ParseHeaders", func() {
+		It("parses the key=value pairs", func() {
+			headers, err := telemetry.ParseHeaders("api-key=some-key, x-tenant = some-tenant,")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(headers).To(Equal(map[string]string{"api-key": "some-key", "x-tenant": "some-tenant"}))
+		})
+
+		It("returns an error for a header without a value", func() {
+			_, err := telemetry.ParseHeaders("api-key")
+			Expect(err).To(MatchError(`could not parse the header "api-key", expected key=value`))
+		})
+	})
+})
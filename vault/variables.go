@@ -0,0 +1,199 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/envvars"
+	"github.com/pivotal-cf/om/network"
+)
+
+// referencePattern matches the `((path#key))` references to a key of a Vault secret.
+var referencePattern = regexp.MustCompile(`\(\((!?[-/\.\w\pL]+)#([-\.\w\pL]+)\)\)`)
+
+type httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Variables resolves the variables whose name is a path, such as `secret/cf`,
+// from the secrets stored in Vault. Every other variable is left to the other
+// sources of variables.
+type Variables struct {
+	client   httpClient
+	token    string
+	roleID   string
+	secretID string
+	secrets  map[string]map[interface{}]interface{}
+}
+
+var _ boshtpl.Variables = &Variables{}
+
+// NewVariables authenticates with the token, or logs in with the AppRole
+// role ID and secret ID when there is no token.
+func NewVariables(client httpClient, token, roleID, secretID string) *Variables {
+	return &Variables{
+		client:   client,
+		token:    token,
+		roleID:   roleID,
+		secretID: secretID,
+		secrets:  map[string]map[interface{}]interface{}{},
+	}
+}
+
+// NewVariablesFromEnv configures Vault from the VAULT_ADDR, VAULT_TOKEN,
+// VAULT_ROLE_ID, VAULT_SECRET_ID and VAULT_SKIP_VERIFY environment variables.
+func NewVariablesFromEnv(environ []string) (*Variables, error) {
+	env := envvars.Map(environ)
+
+	address := env["VAULT_ADDR"]
+	if address == "" {
+		return nil, errors.New("VAULT_ADDR must be set to read variables from vault")
+	}
+
+	token := env["VAULT_TOKEN"]
+	roleID := env["VAULT_ROLE_ID"]
+	secretID := env["VAULT_SECRET_ID"]
+	if token == "" && (roleID == "" || secretID == "") {
+		return nil, errors.New("VAULT_TOKEN, or VAULT_ROLE_ID and VAULT_SECRET_ID, must be set along with VAULT_ADDR to read variables from vault")
+	}
+
+	var skipVerify bool
+	if value, ok := env["VAULT_SKIP_VERIFY"]; ok && value != "" {
+		var err error
+		skipVerify, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse VAULT_SKIP_VERIFY: %s", err)
+		}
+	}
+
+	client := network.NewUnauthenticatedClient(address, skipVerify, 30*time.Second, 5*time.Second)
+
+	return NewVariables(client, token, roleID, secretID), nil
+}
+
+// NormalizeReferences rewrites the `((path#key))` references of the template
+// to `((path.key))`, which is how keys of variables are looked up.
+func NormalizeReferences(template []byte) []byte {
+	return referencePattern.ReplaceAll(template, []byte("(($1.$2))"))
+}
+
+func (v *Variables) Get(definition boshtpl.VariableDefinition) (interface{}, bool, error) {
+	path := definition.Name
+	if !strings.Contains(path, "/") {
+		return nil, false, nil
+	}
+
+	if secret, ok := v.secrets[path]; ok {
+		return secret, true, nil
+	}
+
+	if v.token == "" {
+		err := v.login()
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	request, err := http.NewRequest("GET", "/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return nil, false, err // un-tested
+	}
+	request.Header.Set("X-Vault-Token", v.token)
+
+	response, err := v.client.Do(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read %s from vault: %s", path, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("could not read %s from vault: unexpected response status %d", path, response.StatusCode)
+	}
+
+	var output struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&output)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not unmarshal the %s secret from vault: %s", path, err)
+	}
+
+	data := output.Data
+
+	// the KV version 2 secrets engine nests the data of the secret,
+	// next to its metadata
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	secret := map[interface{}]interface{}{}
+	for key, value := range data {
+		secret[key] = value
+	}
+	v.secrets[path] = secret
+
+	return secret, true, nil
+}
+
+// List is empty as the secrets of Vault are only read when they are referenced.
+func (v *Variables) List() ([]boshtpl.VariableDefinition, error) {
+	return nil, nil
+}
+
+func (v *Variables) login() error {
+	payload, err := json.Marshal(map[string]string{
+		"role_id":   v.roleID,
+		"secret_id": v.secretID,
+	})
+	if err != nil {
+		return err // un-tested
+	}
+
+	request, err := http.NewRequest("POST", "/v1/auth/approle/login", bytes.NewReader(payload))
+	if err != nil {
+		return err // un-tested
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := v.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("could not log in to vault with approle: %s", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not log in to vault with approle: unexpected response status %d", response.StatusCode)
+	}
+
+	var output struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&output)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal the approle login response from vault: %s", err)
+	}
+
+	if output.Auth.ClientToken == "" {
+		return fmt.Errorf("could not log in to vault with approle: no client token was returned")
+	}
+
+	v.token = output.Auth.ClientToken
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package vault_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/director/template"
+	"github.com/pivotal-cf/om/vault"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Variables", func() {
+	var (
+		server   *httptest.Server
+		requests []*http.Request
+		handler  func(w http.ResponseWriter, req *http.Request)
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests = append(requests, req)
+			handler(w, req)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("NewVariablesFromEnv", func() {
+		It("returns an error when VAULT_ADDR is not set", func() {
+			_, err := vault.NewVariablesFromEnv([]string{"VAULT_TOKEN=some-token"})
+			Expect(err).To(MatchError("VAULT_ADDR must be set to read variables from vault"))
+		})
+
+		It("returns an error when neither a token nor an approle is set", func() {
+			_, err := vault.NewVariablesFromEnv([]string{"VAULT_ADDR=" + server.URL, "VAULT_ROLE_ID=some-role-id"})
+			Expect(err).To(MatchError("VAULT_TOKEN, or VAULT_ROLE_ID and VAULT_SECRET_ID, must be set along with VAULT_ADDR to read variables from vault"))
+		})
+
+		It("returns an error when VAULT_SKIP_VERIFY is not a boolean", func() {
+			_, err := vault.NewVariablesFromEnv([]string{"VAULT_ADDR=" + server.URL, "VAULT_TOKEN=some-token", "VAULT_SKIP_VERIFY=maybe"})
+			Expect(err).To(MatchError(ContainSubstring("could not parse VAULT_SKIP_VERIFY")))
+		})
+	})
+
+	Describe("Get", func() {
+		Context("with a token", func() {
+			var vars *vault.Variables
+
+			BeforeEach(func() {
+				var err error
+				vars, err = vault.NewVariablesFromEnv([]string{"VAULT_ADDR=" + server.URL, "VAULT_TOKEN=some-token"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("reads the secret at the path of the variable", func() {
+				handler = func(w http.ResponseWriter, req *http.Request) {
+					w.Write([]byte(`{"data": {"password": "some-password", "username": "admin"}}`))
+				}
+
+				secret, found, err := vars.Get(boshtpl.VariableDefinition{Name: "secret/cf"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(secret).To(Equal(map[interface{}]interface{}{
+					"password": "some-password",
+					"username": "admin",
+				}))
+
+				Expect(requests).To(HaveLen(1))
+				Expect(requests[0].Method).To(Equal("GET"))
+				Expect(requests[0].URL.Path).To(Equal("/v1/secret/cf"))
+				Expect(requests[0].Header.Get("X-Vault-Token")).To(Equal("some-token"))
+			})
+
+			It("unwraps the secrets of the KV version 2 secrets engine", func() {
+				handler = func(w http.ResponseWriter, req *http.Request) {
+					w.Write([]byte(`{"data": {"data": {"password": "some-password"}, "metadata": {"version": 2}}}`))
+				}
+
+				secret, found, err := vars.Get(boshtpl.VariableDefinition{Name: "secret/data/cf"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(secret).To(Equal(map[interface{}]interface{}{"password": "some-password"}))
+
+				Expect(requests[0].URL.Path).To(Equal("/v1/secret/data/cf"))
+			})
+
+			It("reads every secret only once", func() {
+				handler = func(w http.ResponseWriter, req *http.Request) {
+					w.Write([]byte(`{"data": {"password": "some-password"}}`))
+				}
+
+				_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "secret/cf"})
+				Expect(err).NotTo(HaveOccurred())
+				_, _, err = vars.Get(boshtpl.VariableDefinition{Name: "secret/cf"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(requests).To(HaveLen(1))
+			})
+
+			It("does not look up variables whose name is not a path", func() {
+				_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "password"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+
+				Expect(requests).To(BeEmpty())
+			})
+
+			It("does not find secrets that do not exist", func() {
+				handler = func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				}
+
+				_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "secret/missing"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+
+			It("returns an error when the secret cannot be read", func() {
+				handler = func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+				}
+
+				_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "secret/cf"})
+				Expect(err).To(MatchError("could not read secret/cf from vault: unexpected response status 403"))
+			})
+
+			It("returns an error when the secret is not JSON", func() {
+				handler = func(w http.ResponseWriter, req *http.Request) {
+					w.Write([]byte(`%%%`))
+				}
+
+				_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "secret/cf"})
+				Expect(err).To(MatchError(ContainSubstring("could not unmarshal the secret/cf secret from vault")))
+			})
+		})
+
+		Context("with an approle", func() {
+			var vars *vault.Variables
+
+			BeforeEach(func() {
+				var err error
+				vars, err = vault.NewVariablesFromEnv([]string{
+					"VAULT_ADDR=" + server.URL,
+					"VAULT_ROLE_ID=some-role-id",
+					"VAULT_SECRET_ID=some-secret-id",
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("logs in before reading the secret", func() {
+				var login map[string]string
+				handler = func(w http.ResponseWriter, req *http.Request) {
+					if req.URL.Path == "/v1/auth/approle/login" {
+						Expect(json.NewDecoder(req.Body).Decode(&login)).To(Succeed())
+						w.Write([]byte(`{"auth": {"client_token": "some-client-token"}}`))
+						return
+					}
+
+					w.Write([]byte(`{"data": {"password": "some-password"}}`))
+				}
+
+				_, found, err := vars.Get(boshtpl.VariableDefinition{Name: "secret/cf"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				Expect(requests).To(HaveLen(2))
+				Expect(requests[0].Method).To(Equal("POST"))
+				Expect(login).To(Equal(map[string]string{
+					"role_id":   "some-role-id",
+					"secret_id": "some-secret-id",
+				}))
+				Expect(requests[1].Header.Get("X-Vault-Token")).To(Equal("some-client-token"))
+			})
+
+			It("returns an error when the login fails", func() {
+				handler = func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+
+				_, _, err := vars.Get(boshtpl.VariableDefinition{Name: "secret/cf"})
+				Expect(err).To(MatchError("could not log in to vault with approle: unexpected response status 400"))
+			})
+		})
+	})
+
+	Describe("NormalizeReferences", func() {
+		It("turns the key of a path into a key of the variable", func() {
+			Expect(string(vault.NormalizeReferences([]byte(`password: ((secret/cf#admin_password)) name: ((name))`)))).To(
+				Equal(`password: ((secret/cf.admin_password)) name: ((name))`))
+		})
+	})
+})
@@ -0,0 +1,132 @@
+package vmlifecycle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AWSConfig configures the Ops Manager VM on AWS, created with the aws CLI
+// from the AMI of the region in the image file.
+type AWSConfig struct {
+	AccessKeyID            string   `yaml:"access-key-id"`
+	SecretAccessKey        string   `yaml:"secret-access-key"`
+	Region                 string   `yaml:"region"`
+	VMName                 string   `yaml:"vm-name"`
+	VPCSubnetID            string   `yaml:"vpc-subnet-id"`
+	SecurityGroupIDs       []string `yaml:"security-group-ids"`
+	KeyPairName            string   `yaml:"key-pair-name"`
+	IAMInstanceProfileName string   `yaml:"iam-instance-profile-name"`
+	InstanceType           string   `yaml:"instance-type"`
+	BootDiskSize           int      `yaml:"boot-disk-size"`
+	PrivateIP              string   `yaml:"private-ip"`
+	PublicIPAllocationID   string   `yaml:"public-ip-allocation-id"`
+}
+
+type awsVMManager struct {
+	config AWSConfig
+	runner commandRunner
+}
+
+func (a *awsVMManager) validate() error {
+	if a.config.VMName == "" {
+		a.config.VMName = "ops-manager-vm"
+	}
+	if a.config.InstanceType == "" {
+		a.config.InstanceType = "m5.large"
+	}
+	if a.config.BootDiskSize == 0 {
+		a.config.BootDiskSize = 100
+	}
+
+	err := requireFields(map[string]string{
+		"access-key-id":     a.config.AccessKeyID,
+		"secret-access-key": a.config.SecretAccessKey,
+		"region":            a.config.Region,
+		"vpc-subnet-id":     a.config.VPCSubnetID,
+		"key-pair-name":     a.config.KeyPairName,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(a.config.SecurityGroupIDs) == 0 {
+		return fmt.Errorf("missing required fields: security-group-ids")
+	}
+
+	return nil
+}
+
+func (a *awsVMManager) CreateVM(imageFile string) (string, error) {
+	images, err := loadImages(imageFile)
+	if err != nil {
+		return "", err
+	}
+
+	ami, err := imageOf(images, a.config.Region)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{
+		"ec2", "run-instances",
+		"--image-id", ami,
+		"--instance-type", a.config.InstanceType,
+		"--subnet-id", a.config.VPCSubnetID,
+		"--key-name", a.config.KeyPairName,
+		"--block-device-mappings", fmt.Sprintf(`[{"DeviceName":"/dev/xvda","Ebs":{"VolumeSize":%d,"VolumeType":"gp2","DeleteOnTermination":true}}]`, a.config.BootDiskSize),
+		"--tag-specifications", fmt.Sprintf(`[{"ResourceType":"instance","Tags":[{"Key":"Name","Value":%s}]}]`, strconv.Quote(a.config.VMName)),
+		"--query", "Instances[0].InstanceId",
+		"--output", "text",
+	}
+	args = append(args, "--security-group-ids")
+	args = append(args, a.config.SecurityGroupIDs...)
+	if a.config.PrivateIP != "" {
+		args = append(args, "--private-ip-address", a.config.PrivateIP)
+	}
+	if a.config.IAMInstanceProfileName != "" {
+		args = append(args, "--iam-instance-profile", "Name="+a.config.IAMInstanceProfileName)
+	}
+
+	id, err := a.aws(args...)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = a.aws("ec2", "wait", "instance-running", "--instance-ids", id)
+	if err != nil {
+		return id, err
+	}
+
+	if a.config.PublicIPAllocationID != "" {
+		_, err = a.aws("ec2", "associate-address", "--allocation-id", a.config.PublicIPAllocationID, "--instance-id", id)
+		if err != nil {
+			return id, err
+		}
+	}
+
+	return id, nil
+}
+
+func (a *awsVMManager) DeleteVM(id string) error {
+	_, err := a.aws("ec2", "terminate-instances", "--instance-ids", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.aws("ec2", "wait", "instance-terminated", "--instance-ids", id)
+	return err
+}
+
+func (a *awsVMManager) aws(args ...string) (string, error) {
+	output, err := a.runner.Output("aws", args, []string{
+		"AWS_ACCESS_KEY_ID=" + a.config.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY=" + a.config.SecretAccessKey,
+		"AWS_DEFAULT_REGION=" + a.config.Region,
+	})
+	if err != nil {
+		return "", cliError("aws", args, err)
+	}
+
+	return strings.TrimSpace(output), nil
+}
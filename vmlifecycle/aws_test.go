@@ -0,0 +1,114 @@
+package vmlifecycle_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/om/vmlifecycle"
+	"github.com/pivotal-cf/om/vmlifecycle/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AWS", func() {
+	var (
+		runner    *fakes.CommandRunner
+		manager   vmlifecycle.VMManager
+		dir       string
+		imageFile string
+	)
+
+	BeforeEach(func() {
+		runner = &fakes.CommandRunner{}
+		runner.OutputReturnsOnCall(0, "i-123\n", nil)
+
+		var err error
+		manager, _, err = vmlifecycle.New(parseConfig(`
+opsman-configuration:
+  aws:
+    access-key-id: some-key-id
+    secret-access-key: some-secret
+    region: us-west-2
+    vpc-subnet-id: subnet-123
+    security-group-ids: [sg-1, sg-2]
+    key-pair-name: some-key-pair
+    iam-instance-profile-name: some-profile
+    private-ip: 10.0.0.10
+    public-ip-allocation-id: eipalloc-123
+`), runner)
+		Expect(err).NotTo(HaveOccurred())
+
+		dir, err = ioutil.TempDir("", "aws")
+		Expect(err).NotTo(HaveOccurred())
+		imageFile = writeFile(dir, "image.yml", "us-east-1: ami-east\nus-west-2: ami-west\n")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("creates the VM from the AMI of the region", func() {
+		id, err := manager.CreateVM(imageFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("i-123"))
+
+		Expect(commandLines(runner)).To(Equal([][]string{
+			{"aws", "ec2", "run-instances",
+				"--image-id", "ami-west",
+				"--instance-type", "m5.large",
+				"--subnet-id", "subnet-123",
+				"--key-name", "some-key-pair",
+				"--block-device-mappings", `[{"DeviceName":"/dev/xvda","Ebs":{"VolumeSize":100,"VolumeType":"gp2","DeleteOnTermination":true}}]`,
+				"--tag-specifications", `[{"ResourceType":"instance","Tags":[{"Key":"Name","Value":"ops-manager-vm"}]}]`,
+				"--query", "Instances[0].InstanceId",
+				"--output", "text",
+				"--security-group-ids", "sg-1", "sg-2",
+				"--private-ip-address", "10.0.0.10",
+				"--iam-instance-profile", "Name=some-profile",
+			},
+			{"aws", "ec2", "wait", "instance-running", "--instance-ids", "i-123"},
+			{"aws", "ec2", "associate-address", "--allocation-id", "eipalloc-123", "--instance-id", "i-123"},
+		}))
+
+		_, _, env := runner.OutputArgsForCall(0)
+		Expect(env).To(Equal([]string{
+			"AWS_ACCESS_KEY_ID=some-key-id",
+			"AWS_SECRET_ACCESS_KEY=some-secret",
+			"AWS_DEFAULT_REGION=us-west-2",
+		}))
+	})
+
+	It("returns the ID of the VM that did not start", func() {
+		runner.OutputReturnsOnCall(1, "", errors.New("exit status 255"))
+
+		id, err := manager.CreateVM(imageFile)
+		Expect(err).To(MatchError("aws ec2 wait failed: exit status 255"))
+		Expect(id).To(Equal("i-123"))
+	})
+
+	It("returns an error when the image file has no AMI for the region", func() {
+		imageFile = writeFile(dir, "image.yml", "eu-west-1: ami-eu\nus-east-1: ami-east\n")
+
+		_, err := manager.CreateVM(imageFile)
+		Expect(err).To(MatchError("the image file has no image for us-west-2, it has images for: eu-west-1, us-east-1"))
+		Expect(runner.OutputCallCount()).To(Equal(0))
+	})
+
+	It("deletes the VM", func() {
+		Expect(manager.DeleteVM("i-123")).To(Succeed())
+
+		Expect(commandLines(runner)).To(Equal([][]string{
+			{"aws", "ec2", "terminate-instances", "--instance-ids", "i-123"},
+			{"aws", "ec2", "wait", "instance-terminated", "--instance-ids", "i-123"},
+		}))
+	})
+
+	It("returns an error when the VM cannot be deleted", func() {
+		runner.OutputReturnsOnCall(0, "", errors.New("exit status 255"))
+
+		err := manager.DeleteVM("i-123")
+		Expect(err).To(MatchError("aws ec2 terminate-instances failed: exit status 255"))
+	})
+})
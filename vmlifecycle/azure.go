@@ -0,0 +1,196 @@
+package vmlifecycle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// AzureConfig configures the Ops Manager VM on Azure, created with the az CLI
+// from the VHD of the location in the image file.
+type AzureConfig struct {
+	SubscriptionID string `yaml:"subscription-id"`
+	TenantID       string `yaml:"tenant-id"`
+	ClientID       string `yaml:"client-id"`
+	ClientSecret   string `yaml:"client-secret"`
+	ResourceGroup  string `yaml:"resource-group"`
+	Location       string `yaml:"location"`
+	SubnetID       string `yaml:"subnet-id"`
+	VMName         string `yaml:"vm-name"`
+	VMSize         string `yaml:"vm-size"`
+	BootDiskSize   int    `yaml:"boot-disk-size"`
+	PrivateIP      string `yaml:"private-ip"`
+	PublicIP       string `yaml:"public-ip"`
+	SSHPublicKey   string `yaml:"ssh-public-key"`
+}
+
+type azureVMManager struct {
+	config AzureConfig
+	runner commandRunner
+	env    []string
+}
+
+func (a *azureVMManager) validate() error {
+	if a.config.VMName == "" {
+		a.config.VMName = "ops-manager-vm"
+	}
+	if a.config.VMSize == "" {
+		a.config.VMSize = "Standard_DS2_v2"
+	}
+	if a.config.BootDiskSize == 0 {
+		a.config.BootDiskSize = 128
+	}
+
+	return requireFields(map[string]string{
+		"subscription-id": a.config.SubscriptionID,
+		"tenant-id":       a.config.TenantID,
+		"client-id":       a.config.ClientID,
+		"client-secret":   a.config.ClientSecret,
+		"resource-group":  a.config.ResourceGroup,
+		"location":        a.config.Location,
+		"subnet-id":       a.config.SubnetID,
+		"ssh-public-key":  a.config.SSHPublicKey,
+	})
+}
+
+// CreateVM creates a managed image from the VHD of the location, then the VM
+// from the image. The image file of Azure has a VHD per location, such as
+// `west_us: https://...vhd`.
+func (a *azureVMManager) CreateVM(imageFile string) (string, error) {
+	images, err := loadImages(imageFile)
+	if err != nil {
+		return "", err
+	}
+
+	vhd, err := imageOf(images, azureLocationKey(images, a.config.Location))
+	if err != nil {
+		return "", err
+	}
+
+	cleanup, err := a.authenticate()
+	defer cleanup()
+	if err != nil {
+		return "", err
+	}
+
+	image := a.config.VMName + "-image"
+	_, err = a.az("image", "create",
+		"--resource-group", a.config.ResourceGroup,
+		"--name", image,
+		"--source", vhd,
+		"--location", a.config.Location,
+		"--os-type", "Linux",
+	)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{
+		"vm", "create",
+		"--resource-group", a.config.ResourceGroup,
+		"--name", a.config.VMName,
+		"--location", a.config.Location,
+		"--image", image,
+		"--size", a.config.VMSize,
+		"--os-disk-size-gb", fmt.Sprintf("%d", a.config.BootDiskSize),
+		"--subnet", a.config.SubnetID,
+		"--admin-username", "ubuntu",
+		"--ssh-key-values", a.config.SSHPublicKey,
+		"--public-ip-address", a.config.PublicIP,
+		"--nsg", "",
+	}
+	if a.config.PrivateIP != "" {
+		args = append(args, "--private-ip-address", a.config.PrivateIP)
+	}
+
+	_, err = a.az(args...)
+	if err != nil {
+		return "", err
+	}
+
+	return a.config.VMName, nil
+}
+
+// DeleteVM deletes the VM, its disk and the image it was created from.
+func (a *azureVMManager) DeleteVM(id string) error {
+	cleanup, err := a.authenticate()
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	disk, err := a.az("vm", "show",
+		"--resource-group", a.config.ResourceGroup,
+		"--name", id,
+		"--query", "storageProfile.osDisk.name",
+		"--output", "tsv",
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.az("vm", "delete", "--resource-group", a.config.ResourceGroup, "--name", id, "--yes")
+	if err != nil {
+		return err
+	}
+
+	if disk != "" {
+		_, err = a.az("disk", "delete", "--resource-group", a.config.ResourceGroup, "--name", disk, "--yes")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = a.az("image", "delete", "--resource-group", a.config.ResourceGroup, "--name", id+"-image")
+	return err
+}
+
+// authenticate logs the service principal in, in an az configuration of its
+// own, so that the configuration of the user is left alone.
+func (a *azureVMManager) authenticate() (func(), error) {
+	dir, err := ioutil.TempDir("", "om-az")
+	if err != nil {
+		return func() {}, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	a.env = []string{"AZURE_CONFIG_DIR=" + dir}
+
+	_, err = a.az("login", "--service-principal",
+		"--username", a.config.ClientID,
+		"--password", a.config.ClientSecret,
+		"--tenant", a.config.TenantID,
+	)
+	if err != nil {
+		return cleanup, err
+	}
+
+	_, err = a.az("account", "set", "--subscription", a.config.SubscriptionID)
+	return cleanup, err
+}
+
+func (a *azureVMManager) az(args ...string) (string, error) {
+	output, err := a.runner.Output("az", args, a.env)
+	if err != nil {
+		return "", cliError("az", args, err)
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// azureLocationKey is the key of the location in the images, whose keys
+// are spelled differently from the locations, e.g. west_us for westus.
+func azureLocationKey(images map[string]string, location string) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("_", "", " ", "", "-", "").Replace(s))
+	}
+
+	for key := range images {
+		if normalize(key) == normalize(location) {
+			return key
+		}
+	}
+
+	return location
+}
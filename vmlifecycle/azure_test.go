@@ -0,0 +1,101 @@
+package vmlifecycle_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/om/vmlifecycle"
+	"github.com/pivotal-cf/om/vmlifecycle/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Azure", func() {
+	var (
+		runner    *fakes.CommandRunner
+		manager   vmlifecycle.VMManager
+		dir       string
+		imageFile string
+	)
+
+	BeforeEach(func() {
+		runner = &fakes.CommandRunner{}
+
+		var err error
+		manager, _, err = vmlifecycle.New(parseConfig(`
+opsman-configuration:
+  azure:
+    subscription-id: some-subscription
+    tenant-id: some-tenant
+    client-id: some-client
+    client-secret: some-secret
+    resource-group: some-resource-group
+    location: westus
+    subnet-id: some-subnet-id
+    private-ip: 10.0.0.10
+    public-ip: some-public-ip
+    ssh-public-key: ssh-rsa some-key
+`), runner)
+		Expect(err).NotTo(HaveOccurred())
+
+		dir, err = ioutil.TempDir("", "azure")
+		Expect(err).NotTo(HaveOccurred())
+		imageFile = writeFile(dir, "image.yml", "east_us: https://east/image.vhd\nwest_us: https://west/image.vhd\n")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("logs in, creates the image from the VHD of the location, then the VM", func() {
+		id, err := manager.CreateVM(imageFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ops-manager-vm"))
+
+		Expect(commandLines(runner)).To(Equal([][]string{
+			{"az", "login", "--service-principal", "--username", "some-client", "--password", "some-secret", "--tenant", "some-tenant"},
+			{"az", "account", "set", "--subscription", "some-subscription"},
+			{"az", "image", "create", "--resource-group", "some-resource-group", "--name", "ops-manager-vm-image", "--source", "https://west/image.vhd", "--location", "westus", "--os-type", "Linux"},
+			{"az", "vm", "create",
+				"--resource-group", "some-resource-group",
+				"--name", "ops-manager-vm",
+				"--location", "westus",
+				"--image", "ops-manager-vm-image",
+				"--size", "Standard_DS2_v2",
+				"--os-disk-size-gb", "128",
+				"--subnet", "some-subnet-id",
+				"--admin-username", "ubuntu",
+				"--ssh-key-values", "ssh-rsa some-key",
+				"--public-ip-address", "some-public-ip",
+				"--nsg", "",
+				"--private-ip-address", "10.0.0.10",
+			},
+		}))
+
+		_, _, env := runner.OutputArgsForCall(0)
+		Expect(env).To(HaveLen(1))
+		Expect(env[0]).To(HavePrefix("AZURE_CONFIG_DIR="))
+	})
+
+	It("deletes the VM, its disk and its image", func() {
+		runner.OutputReturnsOnCall(2, "some-disk\n", nil)
+
+		Expect(manager.DeleteVM("ops-manager-vm")).To(Succeed())
+
+		Expect(commandLines(runner)[2:]).To(Equal([][]string{
+			{"az", "vm", "show", "--resource-group", "some-resource-group", "--name", "ops-manager-vm", "--query", "storageProfile.osDisk.name", "--output", "tsv"},
+			{"az", "vm", "delete", "--resource-group", "some-resource-group", "--name", "ops-manager-vm", "--yes"},
+			{"az", "disk", "delete", "--resource-group", "some-resource-group", "--name", "some-disk", "--yes"},
+			{"az", "image", "delete", "--resource-group", "some-resource-group", "--name", "ops-manager-vm-image"},
+		}))
+	})
+
+	It("does not report the secret of the service principal when the login fails", func() {
+		runner.OutputReturnsOnCall(0, "", errors.New("exit status 1"))
+
+		_, err := manager.CreateVM(imageFile)
+		Expect(err).To(MatchError("az login --service-principal failed: exit status 1"))
+	})
+})
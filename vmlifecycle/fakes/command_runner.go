@@ -0,0 +1,125 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	sync "sync"
+)
+
+type CommandRunner struct {
+	OutputStub        func(string, []string, []string) (string, error)
+	outputMutex       sync.RWMutex
+	outputArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 []string
+	}
+	outputReturns struct {
+		result1 string
+		result2 error
+	}
+	outputReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *CommandRunner) Output(arg1 string, arg2 []string, arg3 []string) (string, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.outputMutex.Lock()
+	ret, specificReturn := fake.outputReturnsOnCall[len(fake.outputArgsForCall)]
+	fake.outputArgsForCall = append(fake.outputArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 []string
+	}{arg1, arg2Copy, arg3Copy})
+	fake.recordInvocation("Output", []interface{}{arg1, arg2Copy, arg3Copy})
+	fake.outputMutex.Unlock()
+	if fake.OutputStub != nil {
+		return fake.OutputStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.outputReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *CommandRunner) OutputCallCount() int {
+	fake.outputMutex.RLock()
+	defer fake.outputMutex.RUnlock()
+	return len(fake.outputArgsForCall)
+}
+
+func (fake *CommandRunner) OutputCalls(stub func(string, []string, []string) (string, error)) {
+	fake.outputMutex.Lock()
+	defer fake.outputMutex.Unlock()
+	fake.OutputStub = stub
+}
+
+func (fake *CommandRunner) OutputArgsForCall(i int) (string, []string, []string) {
+	fake.outputMutex.RLock()
+	defer fake.outputMutex.RUnlock()
+	argsForCall := fake.outputArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *CommandRunner) OutputReturns(result1 string, result2 error) {
+	fake.outputMutex.Lock()
+	defer fake.outputMutex.Unlock()
+	fake.OutputStub = nil
+	fake.outputReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CommandRunner) OutputReturnsOnCall(i int, result1 string, result2 error) {
+	fake.outputMutex.Lock()
+	defer fake.outputMutex.Unlock()
+	fake.OutputStub = nil
+	if fake.outputReturnsOnCall == nil {
+		fake.outputReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.outputReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *CommandRunner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.outputMutex.RLock()
+	defer fake.outputMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *CommandRunner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,177 @@
+package vmlifecycle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GCPConfig configures the Ops Manager VM on GCP, created with the gcloud CLI
+// from the image of the region in the image file.
+type GCPConfig struct {
+	ServiceAccountKey string   `yaml:"gcp-service-account"`
+	Project           string   `yaml:"project"`
+	Region            string   `yaml:"region"`
+	Zone              string   `yaml:"zone"`
+	VMName            string   `yaml:"vm-name"`
+	VPCSubnet         string   `yaml:"vpc-subnet"`
+	Tags              []string `yaml:"tags"`
+	CustomCPU         int      `yaml:"custom-cpu"`
+	CustomMemory      int      `yaml:"custom-memory"`
+	BootDiskSize      int      `yaml:"boot-disk-size"`
+	PrivateIP         string   `yaml:"private-ip"`
+	PublicIP          string   `yaml:"public-ip"`
+	SSHPublicKey      string   `yaml:"ssh-public-key"`
+}
+
+type gcpVMManager struct {
+	config GCPConfig
+	runner commandRunner
+	env    []string
+}
+
+func (g *gcpVMManager) validate() error {
+	if g.config.VMName == "" {
+		g.config.VMName = "ops-manager-vm"
+	}
+	if g.config.CustomCPU == 0 {
+		g.config.CustomCPU = 2
+	}
+	if g.config.CustomMemory == 0 {
+		g.config.CustomMemory = 8
+	}
+	if g.config.BootDiskSize == 0 {
+		g.config.BootDiskSize = 100
+	}
+
+	return requireFields(map[string]string{
+		"gcp-service-account": g.config.ServiceAccountKey,
+		"project":             g.config.Project,
+		"region":              g.config.Region,
+		"zone":                g.config.Zone,
+		"vpc-subnet":          g.config.VPCSubnet,
+	})
+}
+
+// CreateVM creates the image of the VM from the tarball of the region, as
+// the image file of GCP has a tarball per continent: us, eu and asia.
+func (g *gcpVMManager) CreateVM(imageFile string) (string, error) {
+	images, err := loadImages(imageFile)
+	if err != nil {
+		return "", err
+	}
+
+	tarball, err := imageOf(images, gcpContinent(g.config.Region))
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(tarball, "gs://") && !strings.HasPrefix(tarball, "https://") {
+		tarball = "gs://" + tarball
+	}
+
+	cleanup, err := g.authenticate()
+	defer cleanup()
+	if err != nil {
+		return "", err
+	}
+
+	image := g.config.VMName + "-image"
+	_, err = g.gcloud("compute", "images", "create", image, "--source-uri", tarball)
+	if err != nil {
+		return "", err
+	}
+
+	networkInterface := "subnet=" + g.config.VPCSubnet
+	if g.config.PrivateIP != "" {
+		networkInterface += ",private-network-ip=" + g.config.PrivateIP
+	}
+	if g.config.PublicIP != "" {
+		networkInterface += ",address=" + g.config.PublicIP
+	} else {
+		networkInterface += ",no-address"
+	}
+
+	args := []string{
+		"compute", "instances", "create", g.config.VMName,
+		"--zone", g.config.Zone,
+		"--image", image,
+		"--custom-cpu", fmt.Sprintf("%d", g.config.CustomCPU),
+		"--custom-memory", fmt.Sprintf("%dGB", g.config.CustomMemory),
+		"--boot-disk-size", fmt.Sprintf("%dGB", g.config.BootDiskSize),
+		"--network-interface", networkInterface,
+	}
+	if len(g.config.Tags) > 0 {
+		args = append(args, "--tags", strings.Join(g.config.Tags, ","))
+	}
+	if g.config.SSHPublicKey != "" {
+		args = append(args, "--metadata", "ssh-keys=ubuntu:"+g.config.SSHPublicKey)
+	}
+
+	_, err = g.gcloud(args...)
+	if err != nil {
+		return "", err
+	}
+
+	return g.config.VMName, nil
+}
+
+// DeleteVM deletes the VM, and the image it was created from.
+func (g *gcpVMManager) DeleteVM(id string) error {
+	cleanup, err := g.authenticate()
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	_, err = g.gcloud("compute", "instances", "delete", id, "--zone", g.config.Zone, "--quiet")
+	if err != nil {
+		return err
+	}
+
+	_, err = g.gcloud("compute", "images", "delete", id+"-image", "--quiet")
+	return err
+}
+
+// authenticate activates the service account in a gcloud configuration of
+// its own, so that the configuration of the user is left alone.
+func (g *gcpVMManager) authenticate() (func(), error) {
+	dir, err := ioutil.TempDir("", "om-gcloud")
+	if err != nil {
+		return func() {}, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	keyFile := filepath.Join(dir, "service-account.json")
+	err = ioutil.WriteFile(keyFile, []byte(g.config.ServiceAccountKey), 0600)
+	if err != nil {
+		return cleanup, err
+	}
+
+	g.env = []string{"CLOUDSDK_CONFIG=" + dir, "CLOUDSDK_CORE_PROJECT=" + g.config.Project}
+
+	_, err = g.gcloud("auth", "activate-service-account", "--key-file", keyFile)
+	return cleanup, err
+}
+
+func (g *gcpVMManager) gcloud(args ...string) (string, error) {
+	output, err := g.runner.Output("gcloud", append(args, "--format", "json"), g.env)
+	if err != nil {
+		return "", cliError("gcloud", args, err)
+	}
+
+	return output, nil
+}
+
+// gcpContinent is the key of the image of the region in the image file of GCP.
+func gcpContinent(region string) string {
+	switch {
+	case strings.HasPrefix(region, "europe-"):
+		return "eu"
+	case strings.HasPrefix(region, "asia-"), strings.HasPrefix(region, "australia-"):
+		return "asia"
+	default:
+		return "us"
+	}
+}
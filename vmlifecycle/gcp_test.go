@@ -0,0 +1,112 @@
+package vmlifecycle_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotal-cf/om/vmlifecycle"
+	"github.com/pivotal-cf/om/vmlifecycle/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GCP", func() {
+	var (
+		runner    *fakes.CommandRunner
+		manager   vmlifecycle.VMManager
+		dir       string
+		imageFile string
+		keyFile   string
+	)
+
+	BeforeEach(func() {
+		runner = &fakes.CommandRunner{}
+		runner.OutputStub = func(name string, args []string, env []string) (string, error) {
+			if args[0] == "auth" {
+				contents, err := ioutil.ReadFile(args[3])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal(`{"type": "service_account"}`))
+				keyFile = args[3]
+			}
+			return "", nil
+		}
+
+		var err error
+		manager, _, err = vmlifecycle.New(parseConfig(`
+opsman-configuration:
+  gcp:
+    gcp-service-account: '{"type": "service_account"}'
+    project: some-project
+    region: europe-west1
+    zone: europe-west1-b
+    vpc-subnet: some-subnet
+    tags: [ops-manager, allow-https]
+    private-ip: 10.0.0.10
+    public-ip: 1.2.3.4
+    ssh-public-key: ssh-rsa some-key
+`), runner)
+		Expect(err).NotTo(HaveOccurred())
+
+		dir, err = ioutil.TempDir("", "gcp")
+		Expect(err).NotTo(HaveOccurred())
+		imageFile = writeFile(dir, "image.yml", "us: ops-manager-us/image.tar.gz\neu: ops-manager-eu/image.tar.gz\nasia: ops-manager-asia/image.tar.gz\n")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("creates the image of the continent of the region, then the VM", func() {
+		id, err := manager.CreateVM(imageFile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ops-manager-vm"))
+
+		Expect(commandLines(runner)).To(Equal([][]string{
+			{"gcloud", "auth", "activate-service-account", "--key-file", keyFile, "--format", "json"},
+			{"gcloud", "compute", "images", "create", "ops-manager-vm-image", "--source-uri", "gs://ops-manager-eu/image.tar.gz", "--format", "json"},
+			{"gcloud", "compute", "instances", "create", "ops-manager-vm",
+				"--zone", "europe-west1-b",
+				"--image", "ops-manager-vm-image",
+				"--custom-cpu", "2",
+				"--custom-memory", "8GB",
+				"--boot-disk-size", "100GB",
+				"--network-interface", "subnet=some-subnet,private-network-ip=10.0.0.10,address=1.2.3.4",
+				"--tags", "ops-manager,allow-https",
+				"--metadata", "ssh-keys=ubuntu:ssh-rsa some-key",
+				"--format", "json",
+			},
+		}))
+
+		By("using a gcloud configuration of its own, removed afterwards")
+		_, _, env := runner.OutputArgsForCall(1)
+		Expect(env).To(HaveLen(2))
+		Expect(env[0]).To(HavePrefix("CLOUDSDK_CONFIG="))
+		Expect(filepath.Dir(keyFile)).To(Equal(strings.TrimPrefix(env[0], "CLOUDSDK_CONFIG=")))
+		Expect(env[1]).To(Equal("CLOUDSDK_CORE_PROJECT=some-project"))
+		Expect(filepath.Dir(keyFile)).NotTo(BeADirectory())
+	})
+
+	It("deletes the VM and its image", func() {
+		Expect(manager.DeleteVM("ops-manager-vm")).To(Succeed())
+
+		lines := commandLines(runner)
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[1:]).To(Equal([][]string{
+			{"gcloud", "compute", "instances", "delete", "ops-manager-vm", "--zone", "europe-west1-b", "--quiet", "--format", "json"},
+			{"gcloud", "compute", "images", "delete", "ops-manager-vm-image", "--quiet", "--format", "json"},
+		}))
+	})
+
+	It("returns an error when the service account cannot be activated", func() {
+		runner.OutputStub = nil
+		runner.OutputReturns("", errors.New("exit status 1"))
+
+		_, err := manager.CreateVM(imageFile)
+		Expect(err).To(MatchError("gcloud auth activate-service-account failed: exit status 1"))
+		Expect(runner.OutputCallCount()).To(Equal(1))
+	})
+})
@@ -0,0 +1,13 @@
+package vmlifecycle_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestVMLifecycle(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "vmlifecycle")
+}
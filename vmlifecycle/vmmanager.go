@@ -0,0 +1,174 @@
+// Package vmlifecycle creates and deletes the Ops Manager VM itself, with the
+// command line interface of its IaaS: aws, gcloud, az or govc.
+package vmlifecycle
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the configuration of the Ops Manager VM, on exactly one IaaS.
+type Config struct {
+	OpsmanConfig struct {
+		AWS     *AWSConfig     `yaml:"aws"`
+		GCP     *GCPConfig     `yaml:"gcp"`
+		Azure   *AzureConfig   `yaml:"azure"`
+		VSphere *VSphereConfig `yaml:"vsphere"`
+	} `yaml:"opsman-configuration"`
+}
+
+// State records the Ops Manager VM that was created, so that it can be
+// deleted or replaced later on.
+type State struct {
+	IAAS string `yaml:"iaas"`
+	ID   string `yaml:"vm_id"`
+}
+
+// VMManager creates and deletes the Ops Manager VM on an IaaS.
+type VMManager interface {
+	// CreateVM creates and starts the VM from the image described by the
+	// image file, and returns the ID it is deleted with.
+	CreateVM(imageFile string) (string, error)
+	DeleteVM(id string) error
+}
+
+//go:generate counterfeiter -o ./fakes/command_runner.go --fake-name CommandRunner . commandRunner
+type commandRunner interface {
+	Output(name string, args []string, env []string) (string, error)
+}
+
+// New returns the VM manager of the IaaS of the config, and the name of the IaaS.
+func New(config Config, runner commandRunner) (VMManager, string, error) {
+	var (
+		managers []vmManager
+		iaases   []string
+	)
+
+	if c := config.OpsmanConfig.AWS; c != nil {
+		managers = append(managers, &awsVMManager{config: *c, runner: runner})
+		iaases = append(iaases, "aws")
+	}
+	if c := config.OpsmanConfig.GCP; c != nil {
+		managers = append(managers, &gcpVMManager{config: *c, runner: runner})
+		iaases = append(iaases, "gcp")
+	}
+	if c := config.OpsmanConfig.Azure; c != nil {
+		managers = append(managers, &azureVMManager{config: *c, runner: runner})
+		iaases = append(iaases, "azure")
+	}
+	if c := config.OpsmanConfig.VSphere; c != nil {
+		managers = append(managers, &vsphereVMManager{config: *c, runner: runner})
+		iaases = append(iaases, "vsphere")
+	}
+
+	if len(managers) != 1 {
+		return nil, "", fmt.Errorf("the opsman-configuration must configure exactly one of aws, gcp, azure or vsphere, got %d", len(managers))
+	}
+
+	err := managers[0].validate()
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s configuration: %s", iaases[0], err)
+	}
+
+	return managers[0], iaases[0], nil
+}
+
+type vmManager interface {
+	VMManager
+	validate() error
+}
+
+// LoadState reads the state file, which is empty when it does not exist yet.
+func LoadState(path string) (State, error) {
+	var state State
+
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	err = yaml.UnmarshalStrict(contents, &state)
+	if err != nil {
+		return state, fmt.Errorf("could not parse the state file %s: %s", path, err)
+	}
+
+	return state, nil
+}
+
+// WriteState writes the state file.
+func WriteState(path string, state State) error {
+	contents, err := yaml.Marshal(state)
+	if err != nil {
+		return err // un-tested
+	}
+
+	return ioutil.WriteFile(path, contents, 0600)
+}
+
+// requireFields returns an error listing the fields, by their yaml keys, that are empty.
+func requireFields(fields map[string]string) error {
+	var missing []string
+	for name, value := range fields {
+		if value == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// loadImages reads the YAML image file published with Ops Manager for the IaaSes
+// that have an image per region, such as `us-east-1: ami-0123`.
+func loadImages(imageFile string) (map[string]string, error) {
+	contents, err := ioutil.ReadFile(imageFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the image file: %s", err)
+	}
+
+	var images map[string]string
+	err = yaml.Unmarshal(contents, &images)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the image file %s: %s", imageFile, err)
+	}
+
+	return images, nil
+}
+
+// imageOf returns the image of the region, and the regions of the image file
+// in the error when there is none.
+func imageOf(images map[string]string, region string) (string, error) {
+	if image, ok := images[region]; ok && image != "" {
+		return image, nil
+	}
+
+	var regions []string
+	for r := range images {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+
+	return "", fmt.Errorf("the image file has no image for %s, it has images for: %s", region, strings.Join(regions, ", "))
+}
+
+// cliError reports the failure of a command line interface, whose own error
+// has been written to stderr.
+func cliError(name string, args []string, err error) error {
+	if len(args) > 2 {
+		args = args[:2]
+	}
+
+	return fmt.Errorf("%s %s failed: %s", name, strings.Join(args, " "), err)
+}
@@ -0,0 +1,125 @@
+package vmlifecycle_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/om/vmlifecycle"
+	"github.com/pivotal-cf/om/vmlifecycle/fakes"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// commandLines returns the executable and the arguments of every command run
+func commandLines(runner *fakes.CommandRunner) [][]string {
+	var lines [][]string
+	for i := 0; i < runner.OutputCallCount(); i++ {
+		name, args, _ := runner.OutputArgsForCall(i)
+		lines = append(lines, append([]string{name}, args...))
+	}
+	return lines
+}
+
+func parseConfig(contents string) vmlifecycle.Config {
+	var config vmlifecycle.Config
+	Expect(yaml.UnmarshalStrict([]byte(contents), &config)).To(Succeed())
+	return config
+}
+
+func writeFile(dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+	return path
+}
+
+var _ = Describe("VMManager", func() {
+	var runner *fakes.CommandRunner
+
+	BeforeEach(func() {
+		runner = &fakes.CommandRunner{}
+	})
+
+	Describe("New", func() {
+		It("returns the VM manager of the IaaS of the config", func() {
+			_, iaas, err := vmlifecycle.New(parseConfig(`
+opsman-configuration:
+  gcp:
+    gcp-service-account: some-key
+    project: some-project
+    region: us-west1
+    zone: us-west1-a
+    vpc-subnet: some-subnet
+`), runner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(iaas).To(Equal("gcp"))
+		})
+
+		It("requires exactly one IaaS", func() {
+			_, _, err := vmlifecycle.New(parseConfig(`opsman-configuration: {}`), runner)
+			Expect(err).To(MatchError("the opsman-configuration must configure exactly one of aws, gcp, azure or vsphere, got 0"))
+
+			_, _, err = vmlifecycle.New(parseConfig(`
+opsman-configuration:
+  aws: {}
+  gcp: {}
+`), runner)
+			Expect(err).To(MatchError("the opsman-configuration must configure exactly one of aws, gcp, azure or vsphere, got 2"))
+		})
+
+		It("returns the missing required fields of the IaaS", func() {
+			_, _, err := vmlifecycle.New(parseConfig(`
+opsman-configuration:
+  azure:
+    subscription-id: some-subscription
+    tenant-id: some-tenant
+    client-id: some-client
+    resource-group: some-resource-group
+    subnet-id: some-subnet
+    ssh-public-key: some-key
+`), runner)
+			Expect(err).To(MatchError("invalid azure configuration: missing required fields: client-secret, location"))
+		})
+	})
+
+	Describe("state", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "vmlifecycle")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("writes and loads the state", func() {
+			stateFile := filepath.Join(dir, "state.yml")
+
+			state, err := vmlifecycle.LoadState(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(vmlifecycle.State{}))
+
+			Expect(vmlifecycle.WriteState(stateFile, vmlifecycle.State{IAAS: "aws", ID: "i-123"})).To(Succeed())
+
+			contents, err := ioutil.ReadFile(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("iaas: aws\nvm_id: i-123\n"))
+
+			state, err = vmlifecycle.LoadState(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(Equal(vmlifecycle.State{IAAS: "aws", ID: "i-123"}))
+		})
+
+		It("returns an error when the state file cannot be parsed", func() {
+			stateFile := writeFile(dir, "state.yml", "some-key: some-value\n")
+
+			_, err := vmlifecycle.LoadState(stateFile)
+			Expect(err).To(MatchError(ContainSubstring("could not parse the state file " + stateFile)))
+		})
+	})
+})
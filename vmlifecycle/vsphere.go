@@ -0,0 +1,188 @@
+package vmlifecycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// VSphereConfig configures the Ops Manager VM on vSphere, imported with the
+// govc CLI from the OVA given as the image file.
+type VSphereConfig struct {
+	VCenter struct {
+		URL          string `yaml:"url"`
+		Username     string `yaml:"username"`
+		Password     string `yaml:"password"`
+		Insecure     bool   `yaml:"insecure"`
+		Datacenter   string `yaml:"datacenter"`
+		Datastore    string `yaml:"datastore"`
+		ResourcePool string `yaml:"resource-pool"`
+		Folder       string `yaml:"folder"`
+	} `yaml:"vcenter"`
+	VMName       string `yaml:"vm-name"`
+	Network      string `yaml:"network"`
+	PrivateIP    string `yaml:"private-ip"`
+	Netmask      string `yaml:"netmask"`
+	Gateway      string `yaml:"gateway"`
+	DNS          string `yaml:"dns"`
+	NTP          string `yaml:"ntp"`
+	SSHPublicKey string `yaml:"ssh-public-key"`
+	Hostname     string `yaml:"hostname"`
+	CPU          int    `yaml:"cpu"`
+	Memory       int    `yaml:"memory"`
+	DiskType     string `yaml:"disk-type"`
+}
+
+type vsphereVMManager struct {
+	config VSphereConfig
+	runner commandRunner
+}
+
+type ovaOptions struct {
+	DiskProvisioning   string
+	IPAllocationPolicy string
+	IPProtocol         string
+	PropertyMapping    []ovaProperty
+	NetworkMapping     []ovaNetwork
+	PowerOn            bool
+	InjectOvfEnv       bool
+	WaitForIP          bool
+	Name               string
+}
+
+type ovaProperty struct {
+	Key   string
+	Value string
+}
+
+type ovaNetwork struct {
+	Name    string
+	Network string
+}
+
+func (v *vsphereVMManager) validate() error {
+	if v.config.VMName == "" {
+		v.config.VMName = "ops-manager-vm"
+	}
+	if v.config.CPU == 0 {
+		v.config.CPU = 2
+	}
+	if v.config.Memory == 0 {
+		v.config.Memory = 8
+	}
+	if v.config.DiskType == "" {
+		v.config.DiskType = "thin"
+	}
+
+	return requireFields(map[string]string{
+		"vcenter.url":        v.config.VCenter.URL,
+		"vcenter.username":   v.config.VCenter.Username,
+		"vcenter.password":   v.config.VCenter.Password,
+		"vcenter.datacenter": v.config.VCenter.Datacenter,
+		"vcenter.datastore":  v.config.VCenter.Datastore,
+		"network":            v.config.Network,
+		"private-ip":         v.config.PrivateIP,
+		"netmask":            v.config.Netmask,
+		"gateway":            v.config.Gateway,
+		"dns":                v.config.DNS,
+		"ntp":                v.config.NTP,
+		"ssh-public-key":     v.config.SSHPublicKey,
+	})
+}
+
+// CreateVM imports the OVA with the network settings of the config, then
+// resizes and powers on the VM. Its ID is its inventory path.
+func (v *vsphereVMManager) CreateVM(imageFile string) (string, error) {
+	if _, err := os.Stat(imageFile); err != nil {
+		return "", fmt.Errorf("could not read the image file: %s", err)
+	}
+
+	options, err := ioutil.TempFile("", "om-ova-options")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(options.Name())
+
+	err = json.NewEncoder(options).Encode(ovaOptions{
+		DiskProvisioning:   v.config.DiskType,
+		IPAllocationPolicy: "fixedPolicy",
+		IPProtocol:         "IPv4",
+		PropertyMapping: []ovaProperty{
+			{Key: "ip0", Value: v.config.PrivateIP},
+			{Key: "netmask0", Value: v.config.Netmask},
+			{Key: "gateway", Value: v.config.Gateway},
+			{Key: "DNS", Value: v.config.DNS},
+			{Key: "ntp_servers", Value: v.config.NTP},
+			{Key: "public_ssh_key", Value: v.config.SSHPublicKey},
+			{Key: "custom_hostname", Value: v.config.Hostname},
+		},
+		NetworkMapping: []ovaNetwork{{Name: "Network 1", Network: v.config.Network}},
+		Name:           v.config.VMName,
+	})
+	if closeErr := options.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"import.ova", "-options", options.Name()}
+	if v.config.VCenter.Folder != "" {
+		args = append(args, "-folder", v.config.VCenter.Folder)
+	}
+	_, err = v.govc(append(args, imageFile)...)
+	if err != nil {
+		return "", err
+	}
+
+	folder := v.config.VCenter.Folder
+	if folder == "" {
+		folder = path.Join("/", v.config.VCenter.Datacenter, "vm")
+	}
+	id := path.Join(folder, v.config.VMName)
+
+	_, err = v.govc("vm.change", "-vm.ipath", id, "-c", fmt.Sprintf("%d", v.config.CPU), "-m", fmt.Sprintf("%d", v.config.Memory*1024))
+	if err != nil {
+		return id, err
+	}
+
+	_, err = v.govc("vm.power", "-on", "-vm.ipath", id)
+	return id, err
+}
+
+func (v *vsphereVMManager) DeleteVM(id string) error {
+	state, err := v.govc("object.collect", "-s", id, "runtime.powerState")
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(state) == "poweredOn" {
+		_, err = v.govc("vm.power", "-off", "-force", "-vm.ipath", id)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = v.govc("vm.destroy", "-vm.ipath", id)
+	return err
+}
+
+func (v *vsphereVMManager) govc(args ...string) (string, error) {
+	output, err := v.runner.Output("govc", args, []string{
+		"GOVC_URL=" + v.config.VCenter.URL,
+		"GOVC_USERNAME=" + v.config.VCenter.Username,
+		"GOVC_PASSWORD=" + v.config.VCenter.Password,
+		fmt.Sprintf("GOVC_INSECURE=%t", v.config.VCenter.Insecure),
+		"GOVC_DATACENTER=" + v.config.VCenter.Datacenter,
+		"GOVC_DATASTORE=" + v.config.VCenter.Datastore,
+		"GOVC_RESOURCE_POOL=" + v.config.VCenter.ResourcePool,
+	})
+	if err != nil {
+		return "", cliError("govc", args[:1], err)
+	}
+
+	return output, nil
+}
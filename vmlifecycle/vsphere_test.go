@@ -0,0 +1,123 @@
+package vmlifecycle_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/om/vmlifecycle"
+	"github.com/pivotal-cf/om/vmlifecycle/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("vSphere", func() {
+	var (
+		runner  *fakes.CommandRunner
+		manager vmlifecycle.VMManager
+		dir     string
+		ova     string
+		options map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		runner = &fakes.CommandRunner{}
+		runner.OutputStub = func(name string, args []string, env []string) (string, error) {
+			if args[0] == "import.ova" {
+				contents, err := ioutil.ReadFile(args[2])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &options)).To(Succeed())
+			}
+			return "", nil
+		}
+
+		var err error
+		manager, _, err = vmlifecycle.New(parseConfig(`
+opsman-configuration:
+  vsphere:
+    vcenter:
+      url: vcenter.example.com
+      username: some-user
+      password: some-password
+      insecure: true
+      datacenter: dc
+      datastore: ds
+      folder: /dc/vm/ops-manager
+    network: some-network
+    private-ip: 10.0.0.10
+    netmask: 255.255.255.0
+    gateway: 10.0.0.1
+    dns: 8.8.8.8
+    ntp: ntp.example.com
+    ssh-public-key: ssh-rsa some-key
+    hostname: opsman.example.com
+`), runner)
+		Expect(err).NotTo(HaveOccurred())
+
+		dir, err = ioutil.TempDir("", "vsphere")
+		Expect(err).NotTo(HaveOccurred())
+		ova = writeFile(dir, "ops-manager.ova", "some-ova")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("imports the OVA with the network settings, then resizes and powers on the VM", func() {
+		id, err := manager.CreateVM(ova)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("/dc/vm/ops-manager/ops-manager-vm"))
+
+		lines := commandLines(runner)
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[0][:2]).To(Equal([]string{"govc", "import.ova"}))
+		Expect(lines[0][4:]).To(Equal([]string{"-folder", "/dc/vm/ops-manager", ova}))
+		Expect(lines[1:]).To(Equal([][]string{
+			{"govc", "vm.change", "-vm.ipath", "/dc/vm/ops-manager/ops-manager-vm", "-c", "2", "-m", "8192"},
+			{"govc", "vm.power", "-on", "-vm.ipath", "/dc/vm/ops-manager/ops-manager-vm"},
+		}))
+		Expect(lines[0][3]).NotTo(BeAnExistingFile())
+
+		Expect(options["Name"]).To(Equal("ops-manager-vm"))
+		Expect(options["DiskProvisioning"]).To(Equal("thin"))
+		Expect(options["NetworkMapping"]).To(Equal([]interface{}{map[string]interface{}{"Name": "Network 1", "Network": "some-network"}}))
+		Expect(options["PropertyMapping"]).To(ContainElement(map[string]interface{}{"Key": "ip0", "Value": "10.0.0.10"}))
+		Expect(options["PropertyMapping"]).To(ContainElement(map[string]interface{}{"Key": "public_ssh_key", "Value": "ssh-rsa some-key"}))
+
+		_, _, env := runner.OutputArgsForCall(0)
+		Expect(env).To(ContainElement("GOVC_URL=vcenter.example.com"))
+		Expect(env).To(ContainElement("GOVC_PASSWORD=some-password"))
+		Expect(env).To(ContainElement("GOVC_INSECURE=true"))
+	})
+
+	It("returns an error when the OVA does not exist", func() {
+		_, err := manager.CreateVM("/does/not/exist.ova")
+		Expect(err).To(MatchError(ContainSubstring("could not read the image file: ")))
+		Expect(runner.OutputCallCount()).To(Equal(0))
+	})
+
+	It("powers off and destroys the VM", func() {
+		runner.OutputStub = nil
+		runner.OutputReturnsOnCall(0, "poweredOn\n", nil)
+
+		Expect(manager.DeleteVM("/dc/vm/ops-manager/ops-manager-vm")).To(Succeed())
+
+		Expect(commandLines(runner)).To(Equal([][]string{
+			{"govc", "object.collect", "-s", "/dc/vm/ops-manager/ops-manager-vm", "runtime.powerState"},
+			{"govc", "vm.power", "-off", "-force", "-vm.ipath", "/dc/vm/ops-manager/ops-manager-vm"},
+			{"govc", "vm.destroy", "-vm.ipath", "/dc/vm/ops-manager/ops-manager-vm"},
+		}))
+	})
+
+	It("destroys the VM that is powered off", func() {
+		runner.OutputStub = nil
+		runner.OutputReturnsOnCall(0, "poweredOff\n", nil)
+		runner.OutputReturnsOnCall(1, "", errors.New("exit status 1"))
+
+		err := manager.DeleteVM("/dc/vm/ops-manager/ops-manager-vm")
+		Expect(err).To(MatchError("govc vm.destroy failed: exit status 1"))
+		Expect(runner.OutputCallCount()).To(Equal(2))
+	})
+})